@@ -30,6 +30,7 @@ import (
 	"vitess.io/vitess/go/vt/logutil"
 	"vitess.io/vitess/go/vt/servenv"
 	"vitess.io/vitess/go/vt/vtadmin"
+	"vitess.io/vitess/go/vt/vtadmin/audit"
 	"vitess.io/vitess/go/vt/vtadmin/cache"
 	"vitess.io/vitess/go/vt/vtadmin/cluster"
 	"vitess.io/vitess/go/vt/vtadmin/grpcserver"
@@ -53,6 +54,9 @@ var (
 
 	cacheRefreshKey string
 
+	auditLogPath       string
+	auditLogBufferSize int
+
 	traceCloser io.Closer = &noopCloser{}
 
 	rootCmd = &cobra.Command{
@@ -147,10 +151,18 @@ func run(cmd *cobra.Command, args []string) {
 	if err != nil {
 		fatal(err)
 	}
+
+	auditLog, err := audit.NewLog(auditLogPath, auditLogBufferSize)
+	if err != nil {
+		fatal(err)
+	}
+	defer auditLog.Close()
+
 	s := vtadmin.NewAPI(env, clusters, vtadmin.Options{
 		GRPCOpts:              opts,
 		HTTPOpts:              httpOpts,
 		RBAC:                  rbacConfig,
+		AuditLog:              auditLog,
 		EnableDynamicClusters: enableDynamicClusters,
 	})
 	bootSpan.Finish()
@@ -209,6 +221,10 @@ func main() {
 		"Note: any whitespace characters are replaced with hyphens."
 	rootCmd.Flags().StringVar(&cacheRefreshKey, "cache-refresh-key", "vt-cache-refresh", cacheRefreshHelp)
 
+	// Audit log flags
+	rootCmd.Flags().StringVar(&auditLogPath, "audit-log-file", "", "path to a file to append an NDJSON audit log of mutating API calls to. if empty, the audit log is only kept in memory")
+	rootCmd.Flags().IntVar(&auditLogBufferSize, "audit-log-buffer-size", 1000, "number of recent audit log entries to keep in memory and serve at /api/audit")
+
 	// glog flags, no better way to do this
 	rootCmd.Flags().AddGoFlag(flag.Lookup("v"))
 	rootCmd.Flags().AddGoFlag(flag.Lookup("logtostderr"))
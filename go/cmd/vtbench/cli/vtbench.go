@@ -20,6 +20,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
 	"strings"
 	"time"
 
@@ -30,8 +31,11 @@ import (
 	"vitess.io/vitess/go/vt/grpccommon"
 	"vitess.io/vitess/go/vt/logutil"
 	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vtbench"
 
+	querypb "vitess.io/vitess/go/vt/proto/query"
+
 	// Import and register the gRPC vtgateconn client
 	_ "vitess.io/vitess/go/vt/vtgate/grpcvtgateconn"
 	// Import and register the gRPC tabletconn client
@@ -79,6 +83,18 @@ import (
         --threads 10 \
         --count 10
 
+  Replay of a vtgate query log, captured with --log_queries_to_file and
+  --querylog-format=json:
+  vtbench \
+        --protocol mysql \
+        --host staging-vtgate-host.my.domain \
+        --port 15306 \
+        --user db_username \
+        --db-credentials-file ./vtbench_db_creds.json \
+        --replay-file ./vtgate_querylog.json \
+        --replay-speed 2 \
+        --replay-bind-var tenant_id=42
+
 */
 
 var (
@@ -89,6 +105,10 @@ var (
 	threads                         = 2
 	count                           = 1000
 
+	replayFile     string
+	replaySpeed    = 1.0
+	replayBindVars []string
+
 	Main = &cobra.Command{
 		Use:   "vtbench",
 		Short: "vtbench is a simple load testing client to compare workloads in Vitess across the various client/server protocols.",
@@ -126,7 +146,18 @@ vtbench \
 	--db loadtest/00-80@replica  \
 	--sql "select * from loadtest_table where id=123456789" \
 	--threads 10 \
-	--count 10`,
+	--count 10
+
+Replay of a vtgate query log, captured with --log_queries_to_file and --querylog-format=json:
+vtbench \
+	--protocol mysql \
+	--host staging-vtgate-host.my.domain \
+	--port 15306 \
+	--user db_username \
+	--db-credentials-file ./vtbench_db_creds.json \
+	--replay-file ./vtgate_querylog.json \
+	--replay-speed 2 \
+	--replay-bind-var tenant_id=42`,
 		Args:    cobra.NoArgs,
 		Version: servenv.AppVersion.String(),
 		PreRunE: servenv.CobraPreRunE,
@@ -149,7 +180,12 @@ func init() {
 	Main.Flags().IntVar(&threads, "threads", threads, "Number of parallel threads to run")
 	Main.Flags().IntVar(&count, "count", count, "Number of queries per thread")
 
-	Main.MarkFlagRequired("sql")
+	Main.Flags().StringVar(&replayFile, "replay-file", replayFile, "Replay the queries of a vtgate query log written in JSON format, instead of running --sql")
+	Main.Flags().Float64Var(&replaySpeed, "replay-speed", replaySpeed, "Replay speed relative to the captured traffic, e.g. 2 replays twice as fast. 0 replays as fast as possible")
+	Main.Flags().StringArrayVar(&replayBindVars, "replay-bind-var", replayBindVars, "Bind variable override for the replayed queries, in the form name=value. May be repeated")
+
+	Main.MarkFlagsOneRequired("sql", "replay-file")
+	Main.MarkFlagsMutuallyExclusive("sql", "replay-file")
 
 	grpccommon.RegisterFlags(Main.Flags())
 	acl.RegisterFlags(Main.Flags())
@@ -210,11 +246,15 @@ func run(cmd *cobra.Command, args []string) error {
 		Password:   password,
 	}
 
-	b := vtbench.NewBench(threads, count, connParams, sql)
-
 	ctx, cancel := context.WithTimeout(context.Background(), deadline)
 	defer cancel()
 
+	if replayFile != "" {
+		return runReplay(ctx, connParams)
+	}
+
+	b := vtbench.NewBench(threads, count, connParams, sql)
+
 	fmt.Printf("Initializing test with %s protocol / %d threads / %d iterations\n",
 		b.ConnParams.Protocol.String(), b.Threads, b.Count)
 	err := b.Run(ctx)
@@ -244,3 +284,46 @@ func run(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+func runReplay(ctx context.Context, connParams vtbench.ConnParams) error {
+	if replaySpeed < 0 {
+		return fmt.Errorf("invalid replay speed %v", replaySpeed)
+	}
+	bindVars := map[string]*querypb.BindVariable{}
+	for _, override := range replayBindVars {
+		name, bv, err := vtbench.ParseBindVarOverride(override)
+		if err != nil {
+			return err
+		}
+		bindVars[name] = bv
+	}
+
+	f, err := os.Open(replayFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	entries, err := vtbench.ReadQueryLog(f)
+	if err != nil {
+		return fmt.Errorf("error reading query log %s: %w", replayFile, err)
+	}
+
+	parser, err := sqlparser.New(sqlparser.Options{
+		MySQLServerVersion: servenv.MySQLServerVersion(),
+		TruncateUILen:      servenv.TruncateUILen,
+		TruncateErrLen:     servenv.TruncateErrLen,
+	})
+	if err != nil {
+		return err
+	}
+
+	r := vtbench.NewReplay(connParams, entries, replaySpeed, bindVars, parser)
+	fmt.Printf("Initializing replay with %s protocol / speed %v\n", r.ConnParams.Protocol.String(), r.Speed)
+	if err := r.Run(ctx); err != nil {
+		return fmt.Errorf("error in replay: %w", err)
+	}
+
+	fmt.Printf("Total Replay Time: %v\n", r.TotalTime)
+	r.PrintReport(os.Stdout)
+	return nil
+}
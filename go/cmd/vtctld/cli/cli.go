@@ -20,6 +20,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"vitess.io/vitess/go/acl"
+	"vitess.io/vitess/go/vt/dbconfigs"
 	"vitess.io/vitess/go/vt/servenv"
 	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/vtctld"
@@ -93,6 +94,9 @@ func init() {
 	servenv.RegisterGRPCServerFlags()
 	servenv.RegisterGRPCServerAuthFlags()
 	servenv.RegisterServiceMapFlag()
+	// RestoreTable connects to the scratch mysqlds that it restores the
+	// backups into with these users.
+	dbconfigs.RegisterFlags(dbconfigs.Dba, dbconfigs.AllPrivs, dbconfigs.Filtered)
 
 	servenv.MoveFlagsToCobraCommand(Main)
 
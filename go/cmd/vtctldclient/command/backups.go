@@ -76,6 +76,22 @@ If no replica-type tablet can be found, the backup can be taken on the primary i
 		Args:                  cobra.ExactArgs(1),
 		RunE:                  commandRestoreFromBackup,
 	}
+	// RestoreTable makes a RestoreTable gRPC call to a vtctld.
+	RestoreTable = &cobra.Command{
+		Use:   "RestoreTable --tables <table>[,<table>...] [--restore-to-pos <pos>|--restore-to-timestamp <timestamp>] [--target-table-suffix <suffix>] [--filter <where-expression>] [--batch-size <rows>] [--resume] <keyspace>",
+		Short: "Restores the given tables from backups into new tables in the keyspace.",
+		Long: `Restores the given tables from backups into new tables in the keyspace.
+
+The backups of each shard are restored, up to the given position or timestamp, into a scratch mysqld that the vtctld
+starts for the duration of the command, so the vtctld host needs the mysqld binaries and room in $VTDATAROOT for the data.
+The rows of each table, optionally limited by the --filter expression, are then streamed from the scratch mysqld in primary
+key order into a new table named <table><suffix> on the shard primary, in batches which are subject to the primary's throttler.
+An interrupted restore can be continued with --resume, which copies the rows after the last one of the existing new tables.`,
+		Example:               `vtctldclient --server localhost:15999 RestoreTable --tables corder --restore-to-timestamp 2024-03-01T10:00:00Z --filter "customer_id = 42" customer`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandRestoreTable,
+	}
 )
 
 var backupOptions = struct {
@@ -108,7 +124,7 @@ func commandBackup(cmd *cobra.Command, args []string) error {
 		resp, err := stream.Recv()
 		switch err {
 		case nil:
-			fmt.Printf("%s/%s (%s): %v\n", resp.Keyspace, resp.Shard, topoproto.TabletAliasString(resp.TabletAlias), resp.Event)
+			fmt.Printf("%s/%s: %v\n", resp.Keyspace, resp.Shard, resp.Event)
 		case io.EOF:
 			return nil
 		default:
@@ -148,7 +164,7 @@ func commandBackupShard(cmd *cobra.Command, args []string) error {
 		resp, err := stream.Recv()
 		switch err {
 		case nil:
-			fmt.Printf("%s/%s (%s): %v\n", resp.Keyspace, resp.Shard, topoproto.TabletAliasString(resp.TabletAlias), resp.Event)
+			fmt.Printf("%s/%s: %v\n", resp.Keyspace, resp.Shard, resp.Event)
 		case io.EOF:
 			return nil
 		default:
@@ -269,7 +285,61 @@ func commandRestoreFromBackup(cmd *cobra.Command, args []string) error {
 		resp, err := stream.Recv()
 		switch err {
 		case nil:
-			fmt.Printf("%s/%s (%s): %v\n", resp.Keyspace, resp.Shard, topoproto.TabletAliasString(resp.TabletAlias), resp.Event)
+			fmt.Printf("%s/%s: %v\n", resp.Keyspace, resp.Shard, resp.Event)
+		case io.EOF:
+			return nil
+		default:
+			return err
+		}
+	}
+}
+
+var restoreTableOptions = struct {
+	Tables             []string
+	TargetTableSuffix  string
+	Filter             string
+	RestoreToPos       string
+	RestoreToTimestamp string
+	BatchSize          int64
+	Resume             bool
+}{}
+
+func commandRestoreTable(cmd *cobra.Command, args []string) error {
+	if restoreTableOptions.RestoreToPos != "" && restoreTableOptions.RestoreToTimestamp != "" {
+		return fmt.Errorf("--restore-to-pos and --restore-to-timestamp are mutually exclusive")
+	}
+
+	req := &vtctldatapb.RestoreTableRequest{
+		Keyspace:          cmd.Flags().Arg(0),
+		Tables:            restoreTableOptions.Tables,
+		TargetTableSuffix: restoreTableOptions.TargetTableSuffix,
+		Filter:            restoreTableOptions.Filter,
+		RestoreToPos:      restoreTableOptions.RestoreToPos,
+		BatchSize:         restoreTableOptions.BatchSize,
+		Resume:            restoreTableOptions.Resume,
+	}
+
+	if restoreTableOptions.RestoreToTimestamp != "" {
+		t, err := mysqlctl.ParseRFC3339(restoreTableOptions.RestoreToTimestamp)
+		if err != nil {
+			return err
+		}
+
+		req.RestoreToTimestamp = protoutil.TimeToProto(t)
+	}
+
+	cli.FinishedParsing(cmd)
+
+	stream, err := client.RestoreTable(commandCtx, req)
+	if err != nil {
+		return err
+	}
+
+	for {
+		resp, err := stream.Recv()
+		switch err {
+		case nil:
+			fmt.Printf("%s/%s: %v\n", resp.Keyspace, resp.Shard, resp.Event)
 		case io.EOF:
 			return nil
 		default:
@@ -303,4 +373,14 @@ func init() {
 	RestoreFromBackup.Flags().StringVar(&restoreFromBackupOptions.RestoreToTimestamp, "restore-to-timestamp", "", "Run a point in time recovery that restores up to, and excluding, given timestamp in RFC3339 format (`2006-01-02T15:04:05Z07:00`). This will attempt to use one full backup followed by zero or more incremental backups")
 	RestoreFromBackup.Flags().BoolVar(&restoreFromBackupOptions.DryRun, "dry-run", false, "Only validate restore steps, do not actually restore data")
 	Root.AddCommand(RestoreFromBackup)
+
+	RestoreTable.Flags().StringSliceVar(&restoreTableOptions.Tables, "tables", nil, "The tables to restore.")
+	RestoreTable.MarkFlagRequired("tables")
+	RestoreTable.Flags().StringVar(&restoreTableOptions.TargetTableSuffix, "target-table-suffix", "", "The suffix appended to the name of each table to get the name of the table that its rows are restored into (default \"_restored\").")
+	RestoreTable.Flags().StringVar(&restoreTableOptions.Filter, "filter", "", "A WHERE clause expression that limits the restored rows to the ones which match it.")
+	RestoreTable.Flags().StringVar(&restoreTableOptions.RestoreToPos, "restore-to-pos", "", "Restore the backups up to the given position. Only supported for keyspaces with a single shard.")
+	RestoreTable.Flags().StringVar(&restoreTableOptions.RestoreToTimestamp, "restore-to-timestamp", "", "Restore the backups up to, and excluding, the given timestamp in RFC3339 format (`2006-01-02T15:04:05Z07:00`).")
+	RestoreTable.Flags().Int64Var(&restoreTableOptions.BatchSize, "batch-size", 1000, "The maximum number of rows to copy in a single statement.")
+	RestoreTable.Flags().BoolVar(&restoreTableOptions.Resume, "resume", false, "Continue a previous restore of the tables, after the last row that was copied into the new tables.")
+	Root.AddCommand(RestoreTable)
 }
@@ -0,0 +1,197 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"vitess.io/vitess/go/cmd/vtctldclient/cli"
+	"vitess.io/vitess/go/vt/schema"
+
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+var (
+	DMLJob = &cobra.Command{
+		Use:                   "DMLJob <cmd> <keyspace> [args]",
+		Short:                 "Operates on batch DML jobs, submitted through vtgate with the BATCH_DML query directive.",
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.MinimumNArgs(2),
+	}
+	DMLJobCancel = &cobra.Command{
+		Use:                   "cancel <keyspace> <uuid>",
+		Short:                 "Cancel a batch DML job. Rows already changed by the job are not reverted.",
+		Example:               "DMLJob cancel test_keyspace 82fa54ac_e83e_11ea_96b7_f875a4d24e90",
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(2),
+		RunE:                  commandDMLJobCancel,
+	}
+	DMLJobPause = &cobra.Command{
+		Use:                   "pause <keyspace> <uuid>",
+		Short:                 "Pause a queued or running batch DML job.",
+		Example:               "DMLJob pause test_keyspace 82fa54ac_e83e_11ea_96b7_f875a4d24e90",
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(2),
+		RunE:                  commandDMLJobPause,
+	}
+	DMLJobResume = &cobra.Command{
+		Use:                   "resume <keyspace> <uuid>",
+		Short:                 "Resume a paused or failed batch DML job from where it left off.",
+		Example:               "DMLJob resume test_keyspace 82fa54ac_e83e_11ea_96b7_f875a4d24e90",
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(2),
+		RunE:                  commandDMLJobResume,
+	}
+	DMLJobShow = &cobra.Command{
+		Use:   "show <keyspace> [<uuid|context|status|all>]",
+		Short: "Display information about batch DML jobs.",
+		Example: `DMLJob show test_keyspace 82fa54ac_e83e_11ea_96b7_f875a4d24e90
+DMLJob show test_keyspace all
+DMLJob show test_keyspace running
+DMLJob show test_keyspace vtgate:e3ba5ad2-e14c-11ee-9d4a-0a43f95f28a3`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.RangeArgs(1, 2),
+		RunE:                  commandDMLJobShow,
+	}
+)
+
+// analyzeDMLJobCommandArguments is a helper function for DMLJob commands that accept a keyspace and a job UUID.
+func analyzeDMLJobCommandArguments(cmd *cobra.Command) (keyspace, uuid string, err error) {
+	keyspace = cmd.Flags().Arg(0)
+	uuid = cmd.Flags().Arg(1)
+	if !schema.IsOnlineDDLUUID(uuid) {
+		return "", "", fmt.Errorf("%s is not a valid UUID", uuid)
+	}
+	return keyspace, uuid, nil
+}
+
+func commandDMLJobCancel(cmd *cobra.Command, args []string) error {
+	keyspace, uuid, err := analyzeDMLJobCommandArguments(cmd)
+	if err != nil {
+		return err
+	}
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.CancelDMLJob(commandCtx, &vtctldatapb.CancelDMLJobRequest{
+		Keyspace: keyspace,
+		Uuid:     uuid,
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+	return nil
+}
+
+func commandDMLJobPause(cmd *cobra.Command, args []string) error {
+	keyspace, uuid, err := analyzeDMLJobCommandArguments(cmd)
+	if err != nil {
+		return err
+	}
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.PauseDMLJob(commandCtx, &vtctldatapb.PauseDMLJobRequest{
+		Keyspace: keyspace,
+		Uuid:     uuid,
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+	return nil
+}
+
+func commandDMLJobResume(cmd *cobra.Command, args []string) error {
+	keyspace, uuid, err := analyzeDMLJobCommandArguments(cmd)
+	if err != nil {
+		return err
+	}
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.ResumeDMLJob(commandCtx, &vtctldatapb.ResumeDMLJobRequest{
+		Keyspace: keyspace,
+		Uuid:     uuid,
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+	return nil
+}
+
+func commandDMLJobShow(cmd *cobra.Command, args []string) error {
+	cli.FinishedParsing(cmd)
+
+	req := &vtctldatapb.GetDMLJobsRequest{
+		Keyspace: cmd.Flags().Arg(0),
+	}
+
+	switch arg := cmd.Flags().Arg(1); arg {
+	case "", "all":
+	default:
+		if status, ok := vtctldatapb.DMLJob_Status_value[strings.ToUpper(arg)]; ok {
+			// Argument is a status name.
+			req.Status = vtctldatapb.DMLJob_Status(status)
+		} else if schema.IsOnlineDDLUUID(arg) {
+			req.Uuid = arg
+		} else {
+			req.JobContext = arg
+		}
+	}
+
+	resp, err := client.GetDMLJobs(commandCtx, req)
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+	return nil
+}
+
+func init() {
+	DMLJob.AddCommand(DMLJobCancel)
+	DMLJob.AddCommand(DMLJobPause)
+	DMLJob.AddCommand(DMLJobResume)
+	DMLJob.AddCommand(DMLJobShow)
+	Root.AddCommand(DMLJob)
+}
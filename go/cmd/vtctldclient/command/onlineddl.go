@@ -69,6 +69,12 @@ var (
 		DisableFlagsInUseLine: true,
 		Args:                  cobra.ExactArgs(2),
 		RunE:                  commandOnlineDDLComplete,
+		Long: `Complete one or all migrations executed with --postpone-completion.
+
+A single migration executed with --coordinated-cutover is cut over on all shards at once, once it is ready to complete on all of them:
+each shard prepares its cut-over, holding its locks, and only once all shards are prepared do they commit. If any shard fails to prepare,
+the cut-over is aborted on all shards and the migration keeps running. The outcome of the cut-over is reported by shard, and the command
+fails unless all shards committed.`,
 	}
 	OnlineDDLLaunch = &cobra.Command{
 		Use:                   "launch <keyspace> <uuid|all>",
@@ -237,6 +243,11 @@ func commandOnlineDDLComplete(cmd *cobra.Command, args []string) error {
 	}
 
 	fmt.Printf("%s\n", data)
+	for shard, result := range resp.CutOverResultsByShard {
+		if result != "committed" {
+			return fmt.Errorf("coordinated cut-over of migration %s did not commit on shard %s: %s", uuid, shard, result)
+		}
+	}
 	return nil
 }
 
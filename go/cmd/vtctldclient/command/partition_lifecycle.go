@@ -0,0 +1,156 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package command
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"vitess.io/vitess/go/cmd/vtctldclient/cli"
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/vt/schema"
+
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+var (
+	PartitionLifecycle = &cobra.Command{
+		Use:                   "PartitionLifecycle <cmd> <keyspace> [args]",
+		Short:                 "Manages the automated partition lifecycle of RANGE partitioned tables.",
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.MinimumNArgs(2),
+	}
+	PartitionLifecycleDelete = &cobra.Command{
+		Use:                   "delete <keyspace> <table>",
+		Short:                 "Stop managing the partitions of a table. Existing partitions are left in place.",
+		Example:               "PartitionLifecycle delete test_keyspace events",
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(2),
+		RunE:                  commandPartitionLifecycleDelete,
+	}
+	PartitionLifecycleSet = &cobra.Command{
+		Use:   "set [--interval <interval>] [--future-partitions <count>] [--retention <duration>] <keyspace> <table>",
+		Short: "Create or update the partition lifecycle of a table.",
+		Long: `Create or update the partition lifecycle of a table.
+
+The table must be partitioned by RANGE COLUMNS over a single date or datetime column, or by RANGE over an
+expression of a single column, such as TO_DAYS(created_at). The primary tablet of each shard periodically
+submits Online DDL migrations with the --fast-range-rotation flag, to create partitions ahead of time and
+to drop partitions past retention. Partition intervals are aligned in UTC.`,
+		Example:               "PartitionLifecycle set --interval day --future-partitions 7 --retention 720h test_keyspace events",
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(2),
+		RunE:                  commandPartitionLifecycleSet,
+	}
+	PartitionLifecycleShow = &cobra.Command{
+		Use:   "show <keyspace> [<table>]",
+		Short: "Display the partition lifecycle configuration and status of tables, by shard.",
+		Example: `PartitionLifecycle show test_keyspace
+PartitionLifecycle show test_keyspace events`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.RangeArgs(1, 2),
+		RunE:                  commandPartitionLifecycleShow,
+	}
+)
+
+var partitionLifecycleSetOptions = struct {
+	Interval         string
+	FuturePartitions uint32
+	Retention        time.Duration
+}{}
+
+func commandPartitionLifecycleDelete(cmd *cobra.Command, args []string) error {
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.DeletePartitionLifecycle(commandCtx, &vtctldatapb.DeletePartitionLifecycleRequest{
+		Keyspace: cmd.Flags().Arg(0),
+		Table:    cmd.Flags().Arg(1),
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+	return nil
+}
+
+func commandPartitionLifecycleSet(cmd *cobra.Command, args []string) error {
+	if _, err := schema.ParsePartitionInterval(partitionLifecycleSetOptions.Interval); err != nil {
+		return err
+	}
+	if partitionLifecycleSetOptions.Retention < 0 {
+		return fmt.Errorf("invalid negative retention: %v", partitionLifecycleSetOptions.Retention)
+	}
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.SetPartitionLifecycle(commandCtx, &vtctldatapb.SetPartitionLifecycleRequest{
+		Keyspace:         cmd.Flags().Arg(0),
+		Table:            cmd.Flags().Arg(1),
+		Interval:         partitionLifecycleSetOptions.Interval,
+		FuturePartitions: partitionLifecycleSetOptions.FuturePartitions,
+		Retention:        protoutil.DurationToProto(partitionLifecycleSetOptions.Retention),
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+	return nil
+}
+
+func commandPartitionLifecycleShow(cmd *cobra.Command, args []string) error {
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.GetPartitionLifecycles(commandCtx, &vtctldatapb.GetPartitionLifecyclesRequest{
+		Keyspace: cmd.Flags().Arg(0),
+		Table:    cmd.Flags().Arg(1),
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+	return nil
+}
+
+func init() {
+	PartitionLifecycleSet.Flags().StringVar(&partitionLifecycleSetOptions.Interval, "interval", string(schema.PartitionIntervalDay), "The time span covered by each partition: hour, day, week, month or year.")
+	PartitionLifecycleSet.Flags().Uint32Var(&partitionLifecycleSetOptions.FuturePartitions, "future-partitions", 3, "The number of partitions to create ahead of the current interval.")
+	PartitionLifecycleSet.Flags().DurationVar(&partitionLifecycleSetOptions.Retention, "retention", 0, "How long to keep partitions once their interval has passed. Expired partitions are dropped via the table lifecycle. Zero keeps partitions forever.")
+
+	PartitionLifecycle.AddCommand(PartitionLifecycleDelete)
+	PartitionLifecycle.AddCommand(PartitionLifecycleSet)
+	PartitionLifecycle.AddCommand(PartitionLifecycleShow)
+	Root.AddCommand(PartitionLifecycle)
+}
@@ -0,0 +1,185 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package command
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"vitess.io/vitess/go/cmd/vtctldclient/cli"
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+var (
+	// ApplyQueryRules makes an ApplyQueryRules gRPC call to a vtctld.
+	ApplyQueryRules = &cobra.Command{
+		Use:   "ApplyQueryRules {--rules RULES | --rules-file RULES_FILE} [--keyspace KEYSPACE [--shards s1,s2,...]] [--tablet-types t1,t2,...] [--ttl DURATION] [--dry-run]",
+		Short: "Applies the provided query rules to the tablets, replacing the ones with the same names.",
+		Long: `Applies the provided query rules to the tablets, replacing the ones with the same names.
+
+The rules are a JSON list, in the format of the rules of the vttablet --filecustomrules flag, such as:
+
+[
+  {"Name": "block_bad_report", "Description": "Blocks the bad report", "Query": "select .* from orders where .*", "Plans": ["Select"], "TableNames": ["orders"], "Action": "FAIL"}
+]
+
+The rules are stored in the global topo, from which the tablets started with --topocustomrule_query_rules pick
+them up within seconds; the other tablets ignore them. They apply to the tablets of the --keyspace, --shards and
+--tablet-types, or to all of them, until the --ttl expires.
+
+With --dry-run, the tablets do not perform the action of the rules: they only list the rules that a query
+matched in their /querylogz page. The tablets count the queries that matched each rule in their QueryRuleHits and
+QueryRuleDryRunHits stats.`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.NoArgs,
+		RunE:                  commandApplyQueryRules,
+	}
+	// DeleteQueryRule makes a DeleteQueryRule gRPC call to a vtctld.
+	DeleteQueryRule = &cobra.Command{
+		Use:                   "DeleteQueryRule <name>",
+		Short:                 "Deletes the query rule applied with ApplyQueryRules with the given name.",
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandDeleteQueryRule,
+	}
+	// GetQueryRules makes a GetQueryRules gRPC call to a vtctld.
+	GetQueryRules = &cobra.Command{
+		Use:                   "GetQueryRules",
+		Short:                 "Displays the query rules applied with ApplyQueryRules as a JSON document.",
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.NoArgs,
+		RunE:                  commandGetQueryRules,
+	}
+)
+
+var applyQueryRulesOptions = struct {
+	Rules         string
+	RulesFilePath string
+	Keyspace      string
+	Shards        []string
+	TabletTypes   []topodatapb.TabletType
+	TTL           time.Duration
+	DryRun        bool
+}{}
+
+func commandApplyQueryRules(cmd *cobra.Command, args []string) error {
+	if applyQueryRulesOptions.Rules != "" && applyQueryRulesOptions.RulesFilePath != "" {
+		return fmt.Errorf("cannot pass both --rules (=%s) and --rules-file (=%s)", applyQueryRulesOptions.Rules, applyQueryRulesOptions.RulesFilePath)
+	}
+
+	if applyQueryRulesOptions.Rules == "" && applyQueryRulesOptions.RulesFilePath == "" {
+		return errors.New("must pass exactly one of --rules or --rules-file")
+	}
+
+	if len(applyQueryRulesOptions.Shards) > 0 && applyQueryRulesOptions.Keyspace == "" {
+		return errors.New("--shards requires --keyspace")
+	}
+
+	cli.FinishedParsing(cmd)
+
+	rules := applyQueryRulesOptions.Rules
+	if applyQueryRulesOptions.RulesFilePath != "" {
+		data, err := os.ReadFile(applyQueryRulesOptions.RulesFilePath)
+		if err != nil {
+			return err
+		}
+
+		rules = string(data)
+	}
+
+	req := &vtctldatapb.ApplyQueryRulesRequest{
+		Rules:       rules,
+		Keyspace:    applyQueryRulesOptions.Keyspace,
+		Shards:      applyQueryRulesOptions.Shards,
+		TabletTypes: applyQueryRulesOptions.TabletTypes,
+		DryRun:      applyQueryRulesOptions.DryRun,
+	}
+	if applyQueryRulesOptions.TTL != 0 {
+		req.Ttl = protoutil.DurationToProto(applyQueryRulesOptions.TTL)
+	}
+
+	resp, err := client.ApplyQueryRules(commandCtx, req)
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp.QueryRules)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("New QueryRules object:\n%s\n", data)
+
+	return nil
+}
+
+func commandDeleteQueryRule(cmd *cobra.Command, args []string) error {
+	cli.FinishedParsing(cmd)
+
+	name := cmd.Flags().Arg(0)
+	_, err := client.DeleteQueryRule(commandCtx, &vtctldatapb.DeleteQueryRuleRequest{
+		Name: name,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Successfully deleted query rule %s.\n", name)
+
+	return nil
+}
+
+func commandGetQueryRules(cmd *cobra.Command, args []string) error {
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.GetQueryRules(commandCtx, &vtctldatapb.GetQueryRulesRequest{})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp.QueryRules)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+
+	return nil
+}
+
+func init() {
+	ApplyQueryRules.Flags().StringVarP(&applyQueryRulesOptions.Rules, "rules", "r", "", "Query rules, specified as a string")
+	ApplyQueryRules.Flags().StringVarP(&applyQueryRulesOptions.RulesFilePath, "rules-file", "f", "", "Path to a file containing query rules specified as JSON")
+	ApplyQueryRules.Flags().StringVarP(&applyQueryRulesOptions.Keyspace, "keyspace", "k", "", "Limit the query rules to the tablets of this keyspace.")
+	ApplyQueryRules.Flags().StringSliceVar(&applyQueryRulesOptions.Shards, "shards", nil, "Limit the query rules to the tablets of these shards of the keyspace.")
+	ApplyQueryRules.Flags().Var((*topoproto.TabletTypeListFlag)(&applyQueryRulesOptions.TabletTypes), "tablet-types", "Limit the query rules to the tablets of these tablet types.")
+	ApplyQueryRules.Flags().DurationVar(&applyQueryRulesOptions.TTL, "ttl", 0, "How long the tablets apply the query rules for. The query rules do not expire if unset.")
+	ApplyQueryRules.Flags().BoolVarP(&applyQueryRulesOptions.DryRun, "dry-run", "d", false, "Only report the queries that match the query rules in the tablets' /querylogz, instead of performing their actions.")
+	Root.AddCommand(ApplyQueryRules)
+
+	Root.AddCommand(DeleteQueryRule)
+
+	Root.AddCommand(GetQueryRules)
+}
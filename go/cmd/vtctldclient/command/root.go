@@ -41,6 +41,7 @@ import (
 
 	// These imports ensure init()s within them get called and they register their commands/subcommands.
 	"vitess.io/vitess/go/cmd/vtctldclient/cli"
+	_ "vitess.io/vitess/go/cmd/vtctldclient/command/vreplication/changevindex"
 	vreplcommon "vitess.io/vitess/go/cmd/vtctldclient/command/vreplication/common"
 	_ "vitess.io/vitess/go/cmd/vtctldclient/command/vreplication/lookupvindex"
 	_ "vitess.io/vitess/go/cmd/vtctldclient/command/vreplication/materialize"
@@ -54,6 +55,7 @@ import (
 	// These imports register the topo factories to use when --server=internal.
 	_ "vitess.io/vitess/go/vt/topo/consultopo"
 	_ "vitess.io/vitess/go/vt/topo/etcd2topo"
+	_ "vitess.io/vitess/go/vt/topo/mysqltopo"
 	_ "vitess.io/vitess/go/vt/topo/zk2topo"
 )
 
@@ -66,6 +66,23 @@ For --sql, semi-colons and repeated values may be mixed, for example:
 		Args:                  cobra.ExactArgs(1),
 		RunE:                  commandGetSchema,
 	}
+	// GetSchemaHistory makes a GetSchemaHistory gRPC call to a vtctld.
+	GetSchemaHistory = &cobra.Command{
+		Use:   "GetSchemaHistory [--table <table>] [--limit <limit>] <keyspace>",
+		Short: "Displays the recorded schema changes of tables in the keyspace, by shard, most recent first.",
+		Long: `Displays the recorded schema changes of tables in the keyspace, by shard, most recent first.
+
+Each entry is a DDL applied to a table, as recorded by the schema tracker of the shard primary, along with its
+GTID position, the UUID of the Online DDL migration that issued it, if any, and the table's CREATE statement
+before and after the change. The CREATE statement before the first recorded change of a table that already existed
+is unknown, as flagged by create_statement_before_unknown. Schema changes are recorded only when --track_schema_versions
+is enabled.`,
+		Example: `GetSchemaHistory commerce
+GetSchemaHistory --table customer --limit 10 commerce`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandGetSchemaHistory,
+	}
 	// ReloadSchema makes a ReloadSchema gRPC call to a vtctld.
 	ReloadSchema = &cobra.Command{
 		Use:                   "ReloadSchema <tablet_alias>",
@@ -163,6 +180,32 @@ var getSchemaOptions = struct {
 	TableSchemaOnly bool
 }{}
 
+var getSchemaHistoryOptions = struct {
+	Table string
+	Limit uint64
+}{}
+
+func commandGetSchemaHistory(cmd *cobra.Command, args []string) error {
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.GetSchemaHistory(commandCtx, &vtctldatapb.GetSchemaHistoryRequest{
+		Keyspace: cmd.Flags().Arg(0),
+		Table:    getSchemaHistoryOptions.Table,
+		Limit:    getSchemaHistoryOptions.Limit,
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+	return nil
+}
+
 func commandGetSchema(cmd *cobra.Command, args []string) error {
 	if getSchemaOptions.TableNamesOnly && getSchemaOptions.TableSizesOnly {
 		return errors.New("can only pass one of --table-names-only and --table-sizes-only")
@@ -305,6 +348,10 @@ func init() {
 
 	Root.AddCommand(GetSchema)
 
+	GetSchemaHistory.Flags().StringVar(&getSchemaHistoryOptions.Table, "table", "", "Display only the schema changes of this table.")
+	GetSchemaHistory.Flags().Uint64Var(&getSchemaHistoryOptions.Limit, "limit", 0, "Maximum number of schema changes to display per shard. Zero displays all of them.")
+	Root.AddCommand(GetSchemaHistory)
+
 	Root.AddCommand(ReloadSchema)
 
 	ReloadSchemaKeyspace.Flags().Int32Var(&reloadSchemaKeyspaceOptions.Concurrency, "concurrency", 10, "Number of tablets to reload in parallel. Set to zero for unbounded concurrency.")
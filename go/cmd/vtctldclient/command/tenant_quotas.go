@@ -0,0 +1,174 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package command
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"vitess.io/vitess/go/cmd/vtctldclient/cli"
+	"vitess.io/vitess/go/json2"
+
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+var (
+	// ApplyTenantQuotas makes an ApplyTenantQuotas gRPC call to a vtctld.
+	ApplyTenantQuotas = &cobra.Command{
+		Use:   "ApplyTenantQuotas {--quotas QUOTAS | --quotas-file QUOTAS_FILE} [--cells=c1,c2,...] [--skip-rebuild] [--dry-run]",
+		Short: "Applies the provided per-tenant resource quotas, which vtgate enforces.",
+		Long: `Applies the provided per-tenant resource quotas, which vtgate enforces.
+
+The quotas are a JSON document such as:
+
+{
+  "tenant_key": "username",
+  "dry_run": false,
+  "quotas": [
+    {"tenant": "*", "qps": 100},
+    {"tenant": "acme", "qps": 500, "burst": 1000, "max_concurrent_queries": 50, "max_shards": 4, "rows_per_minute": 1000000}
+  ]
+}
+
+The tenant of a query is identified by its vtgate username ("username"), by the principal, component or
+subcomponent of its effective caller id ("principal", "component" or "subcomponent"), or by its
+/*vt+ TENANT=... */ comment directive ("tag"). The quota of tenant "*" applies to each tenant without a quota of its
+own, and a limit of 0 means no limit. Queries exceeding a quota fail with MySQL error 1226
+(ER_USER_LIMIT_REACHED), unless "dry_run" is set, in which case vtgate only counts them.`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.NoArgs,
+		RunE:                  commandApplyTenantQuotas,
+	}
+	// GetTenantQuotas makes a GetTenantQuotas gRPC call to a vtctld.
+	GetTenantQuotas = &cobra.Command{
+		Use:                   "GetTenantQuotas",
+		Short:                 "Displays the per-tenant resource quotas as a JSON document.",
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.NoArgs,
+		RunE:                  commandGetTenantQuotas,
+	}
+)
+
+var applyTenantQuotasOptions = struct {
+	Quotas         string
+	QuotasFilePath string
+	Cells          []string
+	SkipRebuild    bool
+	DryRun         bool
+}{}
+
+func commandApplyTenantQuotas(cmd *cobra.Command, args []string) error {
+	if applyTenantQuotasOptions.Quotas != "" && applyTenantQuotasOptions.QuotasFilePath != "" {
+		return fmt.Errorf("cannot pass both --quotas (=%s) and --quotas-file (=%s)", applyTenantQuotasOptions.Quotas, applyTenantQuotasOptions.QuotasFilePath)
+	}
+
+	if applyTenantQuotasOptions.Quotas == "" && applyTenantQuotasOptions.QuotasFilePath == "" {
+		return errors.New("must pass exactly one of --quotas or --quotas-file")
+	}
+
+	cli.FinishedParsing(cmd)
+
+	var quotasBytes []byte
+	if applyTenantQuotasOptions.QuotasFilePath != "" {
+		data, err := os.ReadFile(applyTenantQuotasOptions.QuotasFilePath)
+		if err != nil {
+			return err
+		}
+
+		quotasBytes = data
+	} else {
+		quotasBytes = []byte(applyTenantQuotasOptions.Quotas)
+	}
+
+	tq := &vschemapb.TenantQuotas{}
+	if err := json2.Unmarshal(quotasBytes, &tq); err != nil {
+		return err
+	}
+	// Round-trip so when we display the result it's readable.
+	data, err := cli.MarshalJSON(tq)
+	if err != nil {
+		return err
+	}
+
+	if applyTenantQuotasOptions.DryRun {
+		fmt.Printf("[DRY RUN] Would have saved new TenantQuotas object:\n%s\n", data)
+
+		if applyTenantQuotasOptions.SkipRebuild {
+			fmt.Println("[DRY RUN] Would not have rebuilt VSchema graph, would have required operator to run RebuildVSchemaGraph for changes to take effect.")
+		} else {
+			fmt.Print("[DRY RUN] Would have rebuilt the VSchema graph")
+			if len(applyTenantQuotasOptions.Cells) == 0 {
+				fmt.Print(" in all cells\n")
+			} else {
+				fmt.Printf(" in the following cells: %s.\n", strings.Join(applyTenantQuotasOptions.Cells, ", "))
+			}
+		}
+
+		return nil
+	}
+
+	_, err = client.ApplyTenantQuotas(commandCtx, &vtctldatapb.ApplyTenantQuotasRequest{
+		TenantQuotas: tq,
+		SkipRebuild:  applyTenantQuotasOptions.SkipRebuild,
+		RebuildCells: applyTenantQuotasOptions.Cells,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("New TenantQuotas object:\n%s\nIf this is not what you expected, check the input data (as JSON parsing will skip unexpected fields).\n", data)
+
+	if applyTenantQuotasOptions.SkipRebuild {
+		fmt.Println("Skipping rebuild of VSchema graph as requested, you will need to run RebuildVSchemaGraph for the changes to take effect.")
+	}
+
+	return nil
+}
+
+func commandGetTenantQuotas(cmd *cobra.Command, args []string) error {
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.GetTenantQuotas(commandCtx, &vtctldatapb.GetTenantQuotasRequest{})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp.TenantQuotas)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+
+	return nil
+}
+
+func init() {
+	ApplyTenantQuotas.Flags().StringVarP(&applyTenantQuotasOptions.Quotas, "quotas", "q", "", "Tenant quotas, specified as a string")
+	ApplyTenantQuotas.Flags().StringVarP(&applyTenantQuotasOptions.QuotasFilePath, "quotas-file", "f", "", "Path to a file containing tenant quotas specified as JSON")
+	ApplyTenantQuotas.Flags().StringSliceVarP(&applyTenantQuotasOptions.Cells, "cells", "c", nil, "Limit the VSchema graph rebuilding to the specified cells. Ignored if --skip-rebuild is specified.")
+	ApplyTenantQuotas.Flags().BoolVar(&applyTenantQuotasOptions.SkipRebuild, "skip-rebuild", false, "Skip rebuilding the SrvVSchema objects.")
+	ApplyTenantQuotas.Flags().BoolVarP(&applyTenantQuotasOptions.DryRun, "dry-run", "d", false, "Note the actions that would be taken, but do not actually apply the tenant quotas to the topo.")
+	Root.AddCommand(ApplyTenantQuotas)
+
+	Root.AddCommand(GetTenantQuotas)
+}
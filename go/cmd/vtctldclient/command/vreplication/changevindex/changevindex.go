@@ -0,0 +1,88 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package changevindex
+
+import (
+	"github.com/spf13/cobra"
+
+	"vitess.io/vitess/go/vt/topo/topoproto"
+
+	"vitess.io/vitess/go/cmd/vtctldclient/command/vreplication/common"
+)
+
+var (
+	// base is the base command for all actions related to ChangeVindex.
+	base = &cobra.Command{
+		Use:                   "ChangeVindex --workflow <workflow> --target-keyspace <keyspace> [command] [command-flags]",
+		Short:                 "Perform commands related to changing the primary vindex of a table within its keyspace.",
+		DisableFlagsInUseLine: true,
+		Aliases:               []string{"changevindex"},
+		Args:                  cobra.ExactArgs(1),
+	}
+)
+
+func registerCommands(root *cobra.Command) {
+	common.AddCommonFlags(base)
+	root.AddCommand(base)
+
+	create.Flags().StringSliceVarP(&common.CreateOptions.Cells, "cells", "c", nil, "Cells and/or CellAliases to copy table data from.")
+	create.Flags().BoolVarP(&common.CreateOptions.AllCells, "all-cells", "a", false, "Copy table data from any existing cell.")
+	create.Flags().Var((*topoproto.TabletTypeListFlag)(&common.CreateOptions.TabletTypes), "tablet-types", "Source tablet types to replicate table data from (e.g. PRIMARY,REPLICA,RDONLY).")
+	create.Flags().BoolVar(&common.CreateOptions.TabletTypesInPreferenceOrder, "tablet-types-in-preference-order", true, "When performing source tablet selection, look for candidates in the type order as they are listed in the tablet-types flag.")
+	create.Flags().BoolVar(&common.CreateOptions.DeferSecondaryKeys, "defer-secondary-keys", false, "Defer secondary index creation for the shadow table until after it has been copied.")
+	create.Flags().StringVar(&createOptions.Table, "table", "", "The table whose primary vindex is being changed.")
+	create.MarkFlagRequired("table")
+	create.Flags().StringVar(&createOptions.ShadowTable, "shadow-table", "", "The name of the table that the rows are copied into (default _<table>_vcv).")
+	create.Flags().StringVar(&createOptions.Vindex, "vindex", "", "The name of the new primary vindex for the table.")
+	create.MarkFlagRequired("vindex")
+	create.Flags().StringSliceVar(&createOptions.VindexColumns, "vindex-columns", nil, "The table column(s) that the new primary vindex uses.")
+	create.MarkFlagRequired("vindex-columns")
+	create.Flags().StringVar(&createOptions.VindexType, "vindex-type", "", "The type of the vindex, required when the vindex does not already exist in the keyspace (e.g. xxhash).")
+	create.Flags().StringToStringVar(&createOptions.VindexParams, "vindex-params", nil, "Params for the vindex when it does not already exist in the keyspace.")
+	base.AddCommand(create)
+
+	opts := &common.SubCommandsOpts{
+		SubCommand: "ChangeVindex",
+		Workflow:   "corder_rekey",
+	}
+	base.AddCommand(common.GetShowCommand(opts))
+	base.AddCommand(common.GetStatusCommand(opts))
+
+	base.AddCommand(common.GetStartCommand(opts))
+	base.AddCommand(common.GetStopCommand(opts))
+
+	switchTrafficCommand := common.GetSwitchTrafficCommand(opts)
+	common.AddCommonSwitchTrafficFlags(switchTrafficCommand, false)
+	base.AddCommand(switchTrafficCommand)
+
+	reverseTrafficCommand := common.GetReverseTrafficCommand(opts)
+	common.AddCommonSwitchTrafficFlags(reverseTrafficCommand, false)
+	base.AddCommand(reverseTrafficCommand)
+
+	complete := common.GetCompleteCommand(opts)
+	complete.Flags().BoolVar(&common.CompleteOptions.RenameTables, "rename-tables", false, "Keep the original table, but rename it to '_<tablename>_old' instead of dropping it.")
+	complete.Flags().BoolVar(&common.CompleteOptions.DryRun, "dry-run", false, "Print the actions that would be taken and report any known errors that would have occurred.")
+	base.AddCommand(complete)
+
+	cancel := common.GetCancelCommand(opts)
+	cancel.Flags().BoolVar(&common.CancelOptions.KeepData, "keep-data", false, "Keep the partially copied shadow table.")
+	base.AddCommand(cancel)
+}
+
+func init() {
+	common.RegisterCommandHandler("ChangeVindex", registerCommands)
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package changevindex
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"vitess.io/vitess/go/cmd/vtctldclient/cli"
+	"vitess.io/vitess/go/cmd/vtctldclient/command/vreplication/common"
+
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+var (
+	createOptions = struct {
+		Table         string
+		ShadowTable   string
+		Vindex        string
+		VindexColumns []string
+		VindexType    string
+		VindexParams  map[string]string
+	}{}
+
+	// create makes a ChangeVindexCreate gRPC call to a vtctld.
+	create = &cobra.Command{
+		Use:                   "create",
+		Short:                 "Create and start a ChangeVindex VReplication workflow.",
+		Example:               `vtctldclient --server localhost:15999 ChangeVindex --workflow corder_rekey --target-keyspace customer create --table corder --vindex corder_xxhash --vindex-columns order_id --vindex-type xxhash`,
+		SilenceUsage:          true,
+		DisableFlagsInUseLine: true,
+		Aliases:               []string{"Create"},
+		Args:                  cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if len(createOptions.VindexParams) > 0 && createOptions.VindexType == "" {
+				return fmt.Errorf("vindex-params can only be used along with vindex-type")
+			}
+			if err := common.ParseCells(cmd); err != nil {
+				return err
+			}
+			if err := common.ParseTabletTypes(cmd); err != nil {
+				return err
+			}
+			return nil
+		},
+		RunE: commandCreate,
+	}
+)
+
+func commandCreate(cmd *cobra.Command, args []string) error {
+	format, err := common.GetOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+	tsp := common.GetTabletSelectionPreference(cmd)
+	cli.FinishedParsing(cmd)
+
+	req := &vtctldatapb.ChangeVindexCreateRequest{
+		Keyspace:    common.BaseOptions.TargetKeyspace,
+		Workflow:    common.BaseOptions.Workflow,
+		Table:       createOptions.Table,
+		ShadowTable: createOptions.ShadowTable,
+		ColumnVindex: &vschemapb.ColumnVindex{
+			Name:    createOptions.Vindex,
+			Columns: createOptions.VindexColumns,
+		},
+		Cells:                     common.CreateOptions.Cells,
+		TabletTypes:               common.CreateOptions.TabletTypes,
+		TabletSelectionPreference: tsp,
+		DeferSecondaryKeys:        common.CreateOptions.DeferSecondaryKeys,
+	}
+	if createOptions.VindexType != "" {
+		req.Vindex = &vschemapb.Vindex{
+			Type:   createOptions.VindexType,
+			Params: createOptions.VindexParams,
+		}
+	}
+
+	resp, err := common.GetClient().ChangeVindexCreate(common.GetCommandCtx(), req)
+	if err != nil {
+		return err
+	}
+
+	if format == "json" {
+		data, err := cli.MarshalJSONPretty(resp)
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+	fmt.Printf("ChangeVindex workflow %s.%s created: table %s is being copied into %s\n",
+		common.BaseOptions.TargetKeyspace, common.BaseOptions.Workflow, createOptions.Table, resp.ShadowTable)
+	return nil
+}
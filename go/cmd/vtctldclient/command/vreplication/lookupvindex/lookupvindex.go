@@ -68,13 +68,27 @@ var (
 		TabletTypesInPreferenceOrder bool
 		IgnoreNulls                  bool
 		ContinueAfterCopyWithOwner   bool
+		UniqueConstraint             bool
 	}{}
 
 	externalizeOptions = struct {
 		Keyspace string
 	}{}
 
+	validateOptions = struct {
+		Keyspace      string
+		MaxSampleRows int64
+	}{}
+
+	repairOptions = struct {
+		Keyspace         string
+		MaxRowsPerSecond int64
+	}{}
+
 	parseAndValidateCreate = func(cmd *cobra.Command, args []string) error {
+		if createOptions.UniqueConstraint {
+			return parseAndValidateCreateUniqueConstraint(cmd)
+		}
 		if createOptions.TableName == "" { // Use vindex name
 			createOptions.TableName = baseOptions.Name
 		}
@@ -116,7 +130,49 @@ var (
 			},
 		}
 
-		// VReplication specific flags.
+		parseVReplicationFlags(cmd)
+		return nil
+	}
+
+	// parseAndValidateCreateUniqueConstraint builds the specs of a unique
+	// constraint, whose hidden Lookup Vindex is chosen by the vtctld.
+	parseAndValidateCreateUniqueConstraint = func(cmd *cobra.Command) error {
+		if createOptions.Type != "" {
+			return fmt.Errorf("the vindex type cannot be set for a unique constraint")
+		}
+		if createOptions.TableName == "" { // Use the name of the hidden vindex
+			createOptions.TableName = fmt.Sprintf("%s_%s_uniq", createOptions.TableOwner, baseOptions.Name)
+		}
+		baseOptions.Vschema = &vschemapb.Keyspace{
+			Tables: map[string]*vschemapb.Table{
+				createOptions.TableOwner: {
+					UniqueConstraints: []*vschemapb.UniqueConstraint{
+						{
+							Name:        baseOptions.Name,
+							Columns:     createOptions.TableOwnerColumns,
+							LookupTable: baseOptions.TableKeyspace + "." + createOptions.TableName,
+						},
+					},
+				},
+			},
+		}
+		// The vtctld chooses the primary vindex of the lookup table based
+		// on the type of the first column when none is provided.
+		if createOptions.TableVindexType != "" {
+			baseOptions.Vschema.Tables[createOptions.TableName] = &vschemapb.Table{
+				ColumnVindexes: []*vschemapb.ColumnVindex{
+					{
+						Name:    createOptions.TableVindexType,
+						Columns: createOptions.TableOwnerColumns,
+					},
+				},
+			}
+		}
+		parseVReplicationFlags(cmd)
+		return nil
+	}
+
+	parseVReplicationFlags = func(cmd *cobra.Command) {
 		ttFlag := cmd.Flags().Lookup("tablet-types")
 		if ttFlag != nil && ttFlag.Changed {
 			createOptions.TabletTypes = tabletTypesDefault
@@ -127,7 +183,6 @@ var (
 				createOptions.Cells[i] = strings.TrimSpace(cell)
 			}
 		}
-		return nil
 	}
 
 	// cancel makes a WorkflowDelete call to a vtctld.
@@ -167,6 +222,30 @@ var (
 		RunE:                  commandExternalize,
 	}
 
+	// validate makes a LookupVindexValidate call to a vtctld.
+	validate = &cobra.Command{
+		Use:                   "validate",
+		Short:                 "Compare the owner table of the Lookup Vindex with its lookup table and report the missing and dangling lookup entries.",
+		Example:               `vtctldclient --server localhost:15999 LookupVindex --name corder_lookup_vdx --table-keyspace customer validate --keyspace customer`,
+		SilenceUsage:          true,
+		DisableFlagsInUseLine: true,
+		Aliases:               []string{"Validate"},
+		Args:                  cobra.NoArgs,
+		RunE:                  commandValidate,
+	}
+
+	// repair makes a LookupVindexRepair call to a vtctld.
+	repair = &cobra.Command{
+		Use:                   "repair",
+		Short:                 "Insert the missing and delete the dangling entries of the Lookup Vindex's lookup table, at a throttled rate.",
+		Example:               `vtctldclient --server localhost:15999 LookupVindex --name corder_lookup_vdx --table-keyspace customer repair --keyspace customer --max-rows-per-second 50`,
+		SilenceUsage:          true,
+		DisableFlagsInUseLine: true,
+		Aliases:               []string{"Repair"},
+		Args:                  cobra.NoArgs,
+		RunE:                  commandRepair,
+	}
+
 	// show makes a GetWorkflows call to a vtctld.
 	show = &cobra.Command{
 		Use:                   "show",
@@ -217,8 +296,12 @@ func commandCreate(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	output := fmt.Sprintf("LookupVindex %s created in the %s keyspace and the %s VReplication wokflow scheduled on the %s shards, use show to view progress",
-		baseOptions.Name, createOptions.Keyspace, baseOptions.Name, baseOptions.TableKeyspace)
+	kind := "LookupVindex"
+	if createOptions.UniqueConstraint {
+		kind = "Unique constraint"
+	}
+	output := fmt.Sprintf("%s %s created in the %s keyspace and the %s VReplication wokflow scheduled on the %s shards, use show to view progress",
+		kind, baseOptions.Name, createOptions.Keyspace, baseOptions.Name, baseOptions.TableKeyspace)
 	fmt.Println(output)
 
 	return nil
@@ -251,6 +334,55 @@ func commandExternalize(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func commandValidate(cmd *cobra.Command, args []string) error {
+	if validateOptions.Keyspace == "" {
+		validateOptions.Keyspace = baseOptions.TableKeyspace
+	}
+	cli.FinishedParsing(cmd)
+
+	resp, err := common.GetClient().LookupVindexValidate(common.GetCommandCtx(), &vtctldatapb.LookupVindexValidateRequest{
+		Keyspace:      validateOptions.Keyspace,
+		Name:          baseOptions.Name,
+		TableKeyspace: baseOptions.TableKeyspace,
+		MaxSampleRows: validateOptions.MaxSampleRows,
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSONPretty(resp)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+
+	return nil
+}
+
+func commandRepair(cmd *cobra.Command, args []string) error {
+	if repairOptions.Keyspace == "" {
+		repairOptions.Keyspace = baseOptions.TableKeyspace
+	}
+	cli.FinishedParsing(cmd)
+
+	resp, err := common.GetClient().LookupVindexRepair(common.GetCommandCtx(), &vtctldatapb.LookupVindexRepairRequest{
+		Keyspace:         repairOptions.Keyspace,
+		Name:             baseOptions.Name,
+		TableKeyspace:    baseOptions.TableKeyspace,
+		MaxRowsPerSecond: repairOptions.MaxRowsPerSecond,
+	})
+	if err != nil {
+		return err
+	}
+
+	output := fmt.Sprintf("LookupVindex %s has been repaired: %d entries inserted, %d entries deleted and %d entries skipped because the owner table changed",
+		baseOptions.Name, resp.Inserted, resp.Deleted, resp.Skipped)
+	fmt.Println(output)
+
+	return nil
+}
+
 func commandShow(cmd *cobra.Command, args []string) error {
 	cli.FinishedParsing(cmd)
 
@@ -284,8 +416,7 @@ func registerCommands(root *cobra.Command) {
 	// and setup a VReplication workflow to backfill its lookup table.
 	create.Flags().StringVar(&createOptions.Keyspace, "keyspace", "", "The keyspace to create the Lookup Vindex in. This is also where the table-owner must exist.")
 	create.MarkFlagRequired("keyspace")
-	create.Flags().StringVar(&createOptions.Type, "type", "", "The type of Lookup Vindex to create.")
-	create.MarkFlagRequired("type")
+	create.Flags().StringVar(&createOptions.Type, "type", "", "The type of Lookup Vindex to create. Required unless --unique-constraint is set.")
 	create.Flags().StringVar(&createOptions.TableOwner, "table-owner", "", "The table holding the data which we should use to backfill the Lookup Vindex. This must exist in the same keyspace as the Lookup Vindex.")
 	create.MarkFlagRequired("table-owner")
 	create.Flags().StringSliceVar(&createOptions.TableOwnerColumns, "table-owner-columns", nil, "The columns to read from the owner table. These will be used to build the hash which gets stored as the keyspace_id value in the lookup table.")
@@ -294,6 +425,7 @@ func registerCommands(root *cobra.Command) {
 	create.Flags().StringVar(&createOptions.TableVindexType, "table-vindex-type", "", "The primary vindex name/type to use for the lookup table, if the table-keyspace is sharded. This must match the name of a vindex defined in the table-keyspace. If no value is provided then the default type will be used based on the table-owner-columns types.")
 	create.Flags().BoolVar(&createOptions.IgnoreNulls, "ignore-nulls", false, "Do not add corresponding records in the lookup table if any of the owner table's 'from' fields are NULL.")
 	create.Flags().BoolVar(&createOptions.ContinueAfterCopyWithOwner, "continue-after-copy-with-owner", true, "Vindex will continue materialization after the backfill completes when an owner is provided.")
+	create.Flags().BoolVar(&createOptions.UniqueConstraint, "unique-constraint", false, "Create a unique constraint named after --name on the table-owner-columns of the table-owner, instead of a Lookup Vindex. VTGate rejects duplicate values across shards once the constraint is externalized.")
 	// VReplication specific flags.
 	create.Flags().StringSliceVar(&createOptions.Cells, "cells", nil, "Cells to look in for source tablets to replicate from.")
 	create.Flags().Var((*topoprotopb.TabletTypeListFlag)(&createOptions.TabletTypes), "tablet-types", "Source tablet types to replicate from.")
@@ -310,6 +442,15 @@ func registerCommands(root *cobra.Command) {
 	externalize.Flags().StringVar(&externalizeOptions.Keyspace, "keyspace", "", "The keyspace containing the Lookup Vindex. If no value is specified then the table-keyspace will be used.")
 	base.AddCommand(externalize)
 
+	// These compare the owner table with the lookup table and
+	// optionally fix the differences.
+	validate.Flags().StringVar(&validateOptions.Keyspace, "keyspace", "", "The keyspace containing the Lookup Vindex. If no value is specified then the table-keyspace will be used.")
+	validate.Flags().Int64Var(&validateOptions.MaxSampleRows, "max-sample-rows", 10, "The maximum number of missing and dangling entries to report as samples.")
+	base.AddCommand(validate)
+	repair.Flags().StringVar(&repairOptions.Keyspace, "keyspace", "", "The keyspace containing the Lookup Vindex. If no value is specified then the table-keyspace will be used.")
+	repair.Flags().Int64Var(&repairOptions.MaxRowsPerSecond, "max-rows-per-second", 100, "The maximum number of lookup table rows to insert or delete per second.")
+	base.AddCommand(repair)
+
 	// The cancel command deletes the VReplication workflow used
 	// to backfill the lookup vindex. It ends up making a
 	// WorkflowDelete VtctldServer call.
@@ -159,3 +159,12 @@ func (l *List[T]) PushBack(v T) *Element[T] {
 func (l *List[T]) PushBackValue(v *Element[T]) {
 	l.insert(v, l.root.prev)
 }
+
+// InsertAfterValue inserts the element v immediately after mark.
+// If mark is not an element of l, the list is not modified.
+func (l *List[T]) InsertAfterValue(v, mark *Element[T]) {
+	if mark.list != l {
+		return
+	}
+	l.insert(v, mark)
+}
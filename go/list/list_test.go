@@ -133,3 +133,21 @@ func TestPushFrontValue(t *testing.T) {
 	assert.Equal(t, a, l.Front())
 	assert.Equal(t, a, e.prev)
 }
+
+func TestInsertAfterValue(t *testing.T) {
+	l := New[int]()
+	e := l.PushBack(1)
+	f := l.PushBack(3)
+	a := &Element[int]{Value: 2}
+	l.InsertAfterValue(a, e)
+	assert.Equal(t, a, e.next)
+	assert.Equal(t, f, a.next)
+	assert.Equal(t, 3, l.Len())
+
+	m := New[int]()
+	g := m.PushBack(4)
+	b := &Element[int]{Value: 5}
+	l.InsertAfterValue(b, g)
+	assert.Equal(t, 3, l.Len())
+	assert.Equal(t, 1, m.Len())
+}
@@ -201,6 +201,7 @@ var stateToMysqlCode = map[vterrors.State]mysqlCode{
 	vterrors.UnsupportedPS:                {num: ERUnsupportedPS, state: SSUnknownSQLState},
 	vterrors.UnknownSystemVariable:        {num: ERUnknownSystemVariable, state: SSUnknownSQLState},
 	vterrors.UnknownTable:                 {num: ERUnknownTable, state: SSUnknownTable},
+	vterrors.UserLimitReached:             {num: ERUserLimitReached, state: SSClientError},
 	vterrors.WrongGroupField:              {num: ERWrongGroupField, state: SSClientError},
 	vterrors.WrongNumberOfColumnsInSelect: {num: ERWrongNumberOfColumnsInSelect, state: SSWrongNumberOfColumns},
 	vterrors.WrongTypeForVar:              {num: ERWrongTypeForVar, state: SSClientError},
@@ -294,6 +295,8 @@ func demuxResourceExhaustedErrors(msg string) ErrorCode {
 		return ERNetPacketTooLarge
 	case strings.Contains(msg, "Transaction throttled"):
 		return EROutOfResources
+	case strings.Contains(msg, "tenant quota exceeded"):
+		return ERUserLimitReached
 	default:
 		return ERTooManyUserConnections
 	}
@@ -45,6 +45,7 @@ func TestDemuxResourceExhaustedErrors(t *testing.T) {
 		// and therefore shouldn't need to be teased out of another error.
 		{"in-memory row count exceeded allowed limit of 13", ERTooManyUserConnections},
 		{"rpc error: code = ResourceExhausted desc = Transaction throttled", EROutOfResources},
+		{"rpc error: code = ResourceExhausted desc = tenant quota exceeded for 'tenant1': QPS limit of 10", ERUserLimitReached},
 	}
 
 	for _, c := range cases {
@@ -154,6 +155,11 @@ func TestNewSQLErrorFromError(t *testing.T) {
 			num: ERNoDb,
 			ss:  SSNoDB,
 		},
+		{
+			err: vterrors.NewErrorf(vtrpc.Code_RESOURCE_EXHAUSTED, vterrors.UserLimitReached, "tenant quota exceeded"),
+			num: ERUserLimitReached,
+			ss:  SSClientError,
+		},
 		{
 			err: fmt.Errorf("just some random text here"),
 			num: ERUnknownError,
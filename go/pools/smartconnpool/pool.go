@@ -20,6 +20,7 @@ import (
 	"context"
 	"math/rand/v2"
 	"slices"
+	"strconv"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -142,6 +143,10 @@ type ConnPool[C Connection] struct {
 	}
 
 	Metrics Metrics
+
+	// priorityWaits tracks the time spent waiting for a connection by the
+	// clients with a priority, by priority; it's nil if the pool has no stats
+	priorityWaits *servenv.TimingsWrapper
 }
 
 // NewPool creates a new connection pool with the given Config.
@@ -305,9 +310,14 @@ func (pool *ConnPool[D]) RefreshInterval() time.Duration {
 	return time.Duration(pool.config.refreshInterval.Load())
 }
 
-func (pool *ConnPool[C]) recordWait(start time.Time) {
+func (pool *ConnPool[C]) recordWait(ctx context.Context, start time.Time) {
 	pool.Metrics.waitCount.Add(1)
 	pool.Metrics.waitTime.Add(time.Since(start).Nanoseconds())
+	if pool.priorityWaits != nil {
+		if priority, ok := priorityFromContext(ctx); ok {
+			pool.priorityWaits.Record(strconv.Itoa(priority), start)
+		}
+	}
 	if pool.config.logWait != nil {
 		pool.config.logWait(start)
 	}
@@ -473,7 +483,7 @@ func (pool *ConnPool[C]) get(ctx context.Context) (*Pooled[C], error) {
 		if err != nil {
 			return nil, ErrTimeout
 		}
-		pool.recordWait(start)
+		pool.recordWait(ctx, start)
 	}
 	// no connections available and no connections to wait for (pool is closed)
 	if conn == nil {
@@ -530,7 +540,7 @@ func (pool *ConnPool[C]) getWithSetting(ctx context.Context, setting *Setting) (
 		if err != nil {
 			return nil, ErrTimeout
 		}
-		pool.recordWait(start)
+		pool.recordWait(ctx, start)
 	}
 	// no connections available and no connections to wait for (pool is closed)
 	if conn == nil {
@@ -702,4 +712,5 @@ func (pool *ConnPool[C]) RegisterStats(stats *servenv.Exporter, name string) {
 	stats.NewCounterFunc(name+"ResetSetting", "Number of times pool reset the setting", func() int64 {
 		return pool.Metrics.ResetSettingCount()
 	})
+	pool.priorityWaits = stats.NewTimings(name+"PriorityWaitTime", "Tablet server conn pool wait time by priority", "Priority")
 }
@@ -1028,3 +1028,88 @@ func TestApplySettingsFailure(t *testing.T) {
 		p.put(r)
 	}
 }
+
+func TestPriorityWaiters(t *testing.T) {
+	var state TestState
+
+	ctx := context.Background()
+	p := NewPool(&Config[*TestConn]{
+		Capacity:    1,
+		IdleTimeout: time.Second,
+		LogWait:     state.LogWait,
+	}).Open(newConnector(&state), nil)
+	defer p.Close()
+
+	r, err := p.Get(ctx, nil)
+	require.NoError(t, err)
+
+	// queue the waiters from the highest to the lowest priority value
+	priorities := []int{100, 50, 0}
+	served := make(chan int, len(priorities))
+	for i, priority := range priorities {
+		go func() {
+			conn, err := p.Get(NewContextWithPriority(ctx, priority), nil)
+			if !assert.NoError(t, err) {
+				return
+			}
+			served <- priority
+			conn.Recycle()
+		}()
+		for p.wait.waiting() != i+1 {
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	r.Recycle()
+	for _, want := range []int{0, 50, 100} {
+		assert.Equal(t, want, <-served)
+	}
+	assert.EqualValues(t, 3, p.Metrics.WaitCount())
+}
+
+func TestPriorityWaitersAging(t *testing.T) {
+	var state TestState
+
+	ctx := context.Background()
+	p := NewPool(&Config[*TestConn]{
+		Capacity:    1,
+		IdleTimeout: time.Second,
+		LogWait:     state.LogWait,
+	}).Open(newConnector(&state), nil)
+	defer p.Close()
+
+	r, err := p.Get(ctx, nil)
+	require.NoError(t, err)
+
+	// the waiter with the high priority value is skipped over by the first
+	// maxAge+1 waiters with a lower value, and then it's too old to be
+	// skipped over again
+	priorities := []int{100}
+	for range maxAge + 2 {
+		priorities = append(priorities, 0)
+	}
+	served := make(chan int, len(priorities))
+	for i, priority := range priorities {
+		go func() {
+			conn, err := p.Get(NewContextWithPriority(ctx, priority), nil)
+			if !assert.NoError(t, err) {
+				return
+			}
+			served <- priority
+			conn.Recycle()
+		}()
+		for p.wait.waiting() != i+1 {
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	r.Recycle()
+	var want []int
+	for range maxAge + 1 {
+		want = append(want, 0)
+	}
+	want = append(want, 100, 0)
+	for _, w := range want {
+		assert.Equal(t, w, <-served)
+	}
+}
@@ -0,0 +1,38 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package smartconnpool
+
+import "context"
+
+// priorityKey is the context key for the priority of a Get request
+type priorityKey struct{}
+
+// NewContextWithPriority returns a copy of ctx carrying the given priority.
+// When a client has to wait for a connection to be returned to the pool,
+// the waiters with a lower priority value are handed over connections first;
+// waiters that have been skipped over too many times are served regardless
+// of their priority, so they cannot starve. Requests without a priority in
+// their context have a priority of 0.
+func NewContextWithPriority(ctx context.Context, priority int) context.Context {
+	return context.WithValue(ctx, priorityKey{}, priority)
+}
+
+// priorityFromContext returns the priority stored in the context, if any
+func priorityFromContext(ctx context.Context) (int, bool) {
+	priority, ok := ctx.Value(priorityKey{}).(int)
+	return priority, ok
+}
@@ -37,8 +37,21 @@ type waiter[C Connection] struct {
 	sema semaphore
 	// age is the amount of cycles this client has been on the waitlist
 	age uint32
+	// priority is the priority of the waiting client; waiters with a lower
+	// value are handed over connections first
+	priority int
 }
 
+// maxAge is the number of times that a waiter can be skipped over, by the
+// waiters that are handed over a connection before it or that are queued
+// ahead of it, before it is served first.
+// the maxAge of 8 has been set empirically: smaller values cause clients
+// with a specific setting to slightly starve, and aging all the clients
+// in the list every time leads to unfairness when the system is at capacity.
+// it also keeps clients with a high priority value from starving when
+// there's a steady stream of clients with a lower value.
+const maxAge = 8
+
 type waitlist[C Connection] struct {
 	nodes sync.Pool
 	mu    sync.Mutex
@@ -52,11 +65,24 @@ type waitlist[C Connection] struct {
 // forced an expiration of all waiters in the waitlist.
 func (wl *waitlist[C]) waitForConn(ctx context.Context, setting *Setting) (*Pooled[C], error) {
 	elem := wl.nodes.Get().(*list.Element[waiter[C]])
-	elem.Value = waiter[C]{setting: setting, conn: nil, ctx: ctx}
+	priority, _ := priorityFromContext(ctx)
+	elem.Value = waiter[C]{setting: setting, conn: nil, ctx: ctx, priority: priority}
 
 	wl.mu.Lock()
-	// add ourselves as a waiter at the end of the waitlist
-	wl.list.PushBackValue(elem)
+	// add ourselves as a waiter at the end of the waitlist, or ahead of the
+	// waiters with a higher priority value, so the waitlist is sorted by
+	// priority. the waiters that we skip over age, and we don't skip over the
+	// ones that are too old.
+	mark := wl.list.Back()
+	for mark != nil && mark.Value.priority > priority && mark.Value.age <= maxAge {
+		mark.Value.age++
+		mark = mark.Prev()
+	}
+	if mark == nil {
+		wl.list.PushFrontValue(elem)
+	} else {
+		wl.list.InsertAfterValue(elem, mark)
+	}
 	wl.mu.Unlock()
 
 	// block on our waiter's semaphore until somebody can hand over a connection to us
@@ -112,7 +138,6 @@ func (wl *waitlist[D]) tryReturnConn(conn *Pooled[D]) bool {
 }
 
 func (wl *waitlist[D]) tryReturnConnSlow(conn *Pooled[D]) bool {
-	const maxAge = 8
 	var (
 		target      *list.Element[waiter[D]]
 		connSetting = conn.Conn.Setting()
@@ -120,20 +145,21 @@ func (wl *waitlist[D]) tryReturnConnSlow(conn *Pooled[D]) bool {
 
 	wl.mu.Lock()
 	target = wl.list.Front()
-	// iterate through the waitlist looking for either waiters that have been
-	// here too long, or a waiter that is looking exactly for the same Setting
-	// as the one we have in our connection.
-	for e := target; e != nil; e = e.Next() {
+	// iterate through the waiters with the same priority as the front of the
+	// waitlist, which has the lowest priority value, looking for either waiters
+	// that have been here too long, or a waiter that is looking exactly for the
+	// same Setting as the one we have in our connection.
+	for e := target; e != nil && e.Value.priority == target.Value.priority; e = e.Next() {
 		if e.Value.age > maxAge || e.Value.setting == connSetting {
+			// this only ages the waiters that are being skipped over: we'll
+			// start aging the waiters in the back once they get to the front
+			// of the pool.
+			for skipped := target; skipped != e; skipped = skipped.Next() {
+				skipped.Value.age++
+			}
 			target = e
 			break
 		}
-		// this only ages the waiters that are being skipped over: we'll start
-		// aging the waiters in the back once they get to the front of the pool.
-		// the maxAge of 8 has been set empirically: smaller values cause clients
-		// with a specific setting to slightly starve, and aging all the clients
-		// in the list every time leads to unfairness when the system is at capacity
-		e.Value.age++
 	}
 	if target != nil {
 		wl.list.Remove(target)
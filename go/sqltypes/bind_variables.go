@@ -36,6 +36,19 @@ var (
 	// BvReplaceSchemaName is bind variable to be sent down to vttablet to replace schema name.
 	BvReplaceSchemaName = "__replacevtschemaname"
 
+	// BvSequenceAllocator is bind variable to be sent down to vttablet with the
+	// allocator type of a sharded sequence.
+	BvSequenceAllocator = "__vtseqallocator"
+
+	// BvSequenceEpoch is bind variable to be sent down to vttablet with the
+	// epoch of a snowflake sequence.
+	BvSequenceEpoch = "__vtseqepoch"
+
+	// BvSequencePartial is bind variable to be sent down to vttablet when a
+	// sharded sequence may hand out fewer consecutive values than requested.
+	// Their count is then returned along with the first one.
+	BvSequencePartial = "__vtseqpartial"
+
 	// NullBindVariable is a bindvar with NULL value.
 	NullBindVariable = &querypb.BindVariable{Type: querypb.Type_NULL_TYPE}
 )
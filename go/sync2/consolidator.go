@@ -34,6 +34,7 @@ type Consolidator interface {
 
 // PendingResult is a wrapper for result of a query.
 type PendingResult interface {
+	AddWaiterCounter(int64) int64
 	Broadcast()
 	Err() error
 	SetErr(error)
@@ -67,6 +68,8 @@ type pendingResult struct {
 	query        string
 	result       *sqltypes.Result
 	err          error
+	// waiters is the number of duplicate queries waiting on the result.
+	waiters atomic.Int64
 }
 
 // Create adds a query to currently executing queries and acquires a
@@ -85,6 +88,13 @@ func (co *consolidator) Create(query string) (PendingResult, bool) {
 	return r, true
 }
 
+// AddWaiterCounter adds c to the number of duplicate queries waiting on the
+// result, and returns the updated number. Callers use it to cap the number of
+// waiters of a query.
+func (rs *pendingResult) AddWaiterCounter(c int64) int64 {
+	return rs.waiters.Add(c)
+}
+
 // Broadcast removes the entry from current queries and releases the
 // lock on its Result. Broadcast should be invoked when original
 // query completes execution.
@@ -45,6 +45,13 @@ func TestConsolidator(t *testing.T) {
 	if added {
 		t.Fatalf("did not expect consolidator to register a new entry")
 	}
+	if waiters := dup.AddWaiterCounter(1); waiters != 1 {
+		t.Fatalf("expected 1 waiter, got %d", waiters)
+	}
+	if waiters := orig.AddWaiterCounter(0); waiters != 1 {
+		t.Fatalf("expected waiters to be counted per query, got %d", waiters)
+	}
+	dup.AddWaiterCounter(-1)
 
 	result := &sqltypes.Result{}
 	go func() {
@@ -51,8 +51,10 @@ type FakePendingResult struct {
 	BroadcastCalls int
 	// WaitCalls can be used to inspect Wait calls.
 	WaitCalls int
-	err       error
-	result    *sqltypes.Result
+	// Waiters can be used to inspect and pre-configure the waiter counter.
+	Waiters int64
+	err     error
+	result  *sqltypes.Result
 }
 
 var (
@@ -83,6 +85,12 @@ func (fc *FakeConsolidator) Items() []ConsolidatorCacheItem {
 	return nil
 }
 
+// AddWaiterCounter adds c to Waiters, and returns the updated value.
+func (fr *FakePendingResult) AddWaiterCounter(c int64) int64 {
+	fr.Waiters += c
+	return fr.Waiters
+}
+
 // Broadcast records the Broadcast call for later verification.
 func (fr *FakePendingResult) Broadcast() {
 	fr.BroadcastCalls++
@@ -37,8 +37,8 @@ var numSidecarDBTables int
 var ddls1, ddls2 []string
 
 func init() {
-	sidecarDBTables = []string{"copy_state", "dt_participant", "dt_state", "heartbeat", "post_copy_action", "redo_state",
-		"redo_statement", "reparent_journal", "resharding_journal", "schema_migrations", "schema_version", "tables",
+	sidecarDBTables = []string{"copy_state", "dml_jobs", "dt_participant", "dt_state", "heartbeat", "partition_lifecycle", "post_copy_action", "redo_state",
+		"redo_statement", "reparent_journal", "resharding_journal", "schema_history", "schema_migrations", "schema_version", "tables",
 		"vdiff", "vdiff_log", "vdiff_table", "views", "vreplication", "vreplication_log"}
 	numSidecarDBTables = len(sidecarDBTables)
 	ddls1 = []string{
@@ -0,0 +1,167 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	cutOverWindowTimesRegexp = regexp.MustCompile(`^(\d{1,2}):(\d{2})-(\d{1,2}):(\d{2})$`)
+	weekdaysByName           = map[string]time.Weekday{
+		"sun": time.Sunday,
+		"mon": time.Monday,
+		"tue": time.Tuesday,
+		"wed": time.Wednesday,
+		"thu": time.Thursday,
+		"fri": time.Friday,
+		"sat": time.Saturday,
+	}
+)
+
+// CutOverWindow is a recurring time window in which a migration is allowed to cut over, as
+// given by --cut-over-window. Its format is `[days] HH:MM-HH:MM [timezone]`, e.g.
+// `Mon-Fri 02:00-04:00 UTC`:
+//   - days is a comma separated list of days or day ranges, e.g. `Mon-Fri` or `Sat,Sun`. If
+//     omitted, the window opens every day.
+//   - the end time may be earlier than the start time, in which case the window ends on the
+//     next day, e.g. `Fri 22:00-02:00`. The end time may be 24:00.
+//   - timezone is an IANA time zone name. If omitted, UTC is used.
+type CutOverWindow struct {
+	spec     string
+	days     [7]bool
+	start    time.Duration // since midnight
+	end      time.Duration // since midnight
+	location *time.Location
+}
+
+// ParseCutOverWindow parses the value of a --cut-over-window flag
+func ParseCutOverWindow(spec string) (*CutOverWindow, error) {
+	w := &CutOverWindow{
+		spec:     strings.TrimSpace(spec),
+		location: time.UTC,
+	}
+	fields := strings.Fields(w.spec)
+	timesIndex := -1
+	for i, field := range fields {
+		if cutOverWindowTimesRegexp.MatchString(field) {
+			timesIndex = i
+			break
+		}
+	}
+	if timesIndex < 0 || timesIndex > 1 || len(fields) > timesIndex+2 {
+		return nil, fmt.Errorf("invalid cut-over window '%s', expected format is '[days] HH:MM-HH:MM [timezone]'", spec)
+	}
+	submatch := cutOverWindowTimesRegexp.FindStringSubmatch(fields[timesIndex])
+	var err error
+	if w.start, err = parseCutOverWindowTime(submatch[1], submatch[2]); err != nil {
+		return nil, fmt.Errorf("invalid cut-over window '%s': %w", spec, err)
+	}
+	if w.end, err = parseCutOverWindowTime(submatch[3], submatch[4]); err != nil {
+		return nil, fmt.Errorf("invalid cut-over window '%s': %w", spec, err)
+	}
+	if w.start == w.end {
+		return nil, fmt.Errorf("invalid cut-over window '%s': start and end times are equal", spec)
+	}
+	if w.start == 24*time.Hour {
+		return nil, fmt.Errorf("invalid cut-over window '%s': start time must be before 24:00", spec)
+	}
+	if timesIndex == 0 {
+		for i := range w.days {
+			w.days[i] = true
+		}
+	} else if err := w.parseDays(fields[0]); err != nil {
+		return nil, fmt.Errorf("invalid cut-over window '%s': %w", spec, err)
+	}
+	if len(fields) > timesIndex+1 {
+		if w.location, err = time.LoadLocation(fields[timesIndex+1]); err != nil {
+			return nil, fmt.Errorf("invalid cut-over window '%s': %w", spec, err)
+		}
+	}
+	return w, nil
+}
+
+func parseCutOverWindowTime(hours, minutes string) (time.Duration, error) {
+	h, _ := strconv.Atoi(hours)
+	m, _ := strconv.Atoi(minutes)
+	if m > 59 || h > 24 || (h == 24 && m != 0) {
+		return 0, fmt.Errorf("invalid time %s:%s", hours, minutes)
+	}
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute, nil
+}
+
+// parseDays parses a comma separated list of days or day ranges, such as `Mon-Wed,Fri`
+func (w *CutOverWindow) parseDays(days string) error {
+	parseDay := func(day string) (time.Weekday, error) {
+		weekday, ok := weekdaysByName[strings.ToLower(day)]
+		if !ok {
+			return 0, fmt.Errorf("invalid day '%s'", day)
+		}
+		return weekday, nil
+	}
+	for _, token := range strings.Split(days, ",") {
+		from, to, isRange := strings.Cut(token, "-")
+		first, err := parseDay(from)
+		if err != nil {
+			return err
+		}
+		last := first
+		if isRange {
+			if last, err = parseDay(to); err != nil {
+				return err
+			}
+		}
+		// Ranges may wrap around the end of the week, e.g. Fri-Mon
+		for day := first; ; day = (day + 1) % 7 {
+			w.days[day] = true
+			if day == last {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// Contains returns true when the given time is within the window
+func (w *CutOverWindow) Contains(t time.Time) bool {
+	t = t.In(w.location)
+	sinceMidnight := time.Duration(t.Hour())*time.Hour +
+		time.Duration(t.Minute())*time.Minute +
+		time.Duration(t.Second())*time.Second +
+		time.Duration(t.Nanosecond())
+	day := t.Weekday()
+	if w.start < w.end {
+		return w.days[day] && sinceMidnight >= w.start && sinceMidnight < w.end
+	}
+	// The window crosses midnight: it either opened today, or it opened yesterday and is still open.
+	if sinceMidnight >= w.start {
+		return w.days[day]
+	}
+	if sinceMidnight < w.end {
+		return w.days[(day+6)%7]
+	}
+	return false
+}
+
+// String returns the window as it was specified
+func (w *CutOverWindow) String() string {
+	return w.spec
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCutOverWindow(t *testing.T) {
+	tt := []struct {
+		spec        string
+		expectError string
+	}{
+		{spec: "02:00-04:00"},
+		{spec: "Mon-Fri 02:00-04:00"},
+		{spec: "Mon-Fri 02:00-04:00 UTC"},
+		{spec: "sat,sun 22:00-24:00 UTC"},
+		{spec: "Fri-Mon,Wed 23:00-01:30 UTC"},
+		{spec: "", expectError: "expected format"},
+		{spec: "Mon-Fri", expectError: "expected format"},
+		{spec: "Mon Tue 02:00-04:00", expectError: "expected format"},
+		{spec: "02:00-04:00 UTC extra", expectError: "expected format"},
+		{spec: "Mon-Fri 02:00-25:00", expectError: "invalid time 25:00"},
+		{spec: "Mon-Fri 02:60-04:00", expectError: "invalid time 02:60"},
+		{spec: "Mon-Fri 24:00-04:00", expectError: "start time must be before 24:00"},
+		{spec: "Mon-Fri 02:00-02:00", expectError: "start and end times are equal"},
+		{spec: "Mon-Fry 02:00-04:00", expectError: "invalid day 'Fry'"},
+		{spec: "Mon-Fri 02:00-04:00 Nowhere/Special", expectError: "unknown time zone"},
+	}
+	for _, tc := range tt {
+		t.Run(tc.spec, func(t *testing.T) {
+			w, err := ParseCutOverWindow(tc.spec)
+			if tc.expectError != "" {
+				assert.ErrorContains(t, err, tc.expectError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.spec, w.String())
+		})
+	}
+}
+
+func TestCutOverWindowContains(t *testing.T) {
+	// 2024-01-01 is a Monday
+	at := func(day int, hour int, minute int) time.Time {
+		return time.Date(2024, 1, day, hour, minute, 0, 0, time.UTC)
+	}
+	tt := []struct {
+		spec   string
+		t      time.Time
+		expect bool
+	}{
+		{spec: "Mon-Fri 02:00-04:00 UTC", t: at(1, 1, 59), expect: false},
+		{spec: "Mon-Fri 02:00-04:00 UTC", t: at(1, 2, 0), expect: true},
+		{spec: "Mon-Fri 02:00-04:00 UTC", t: at(5, 3, 59), expect: true},
+		{spec: "Mon-Fri 02:00-04:00 UTC", t: at(5, 4, 0), expect: false},
+		{spec: "Mon-Fri 02:00-04:00 UTC", t: at(6, 3, 0), expect: false},
+		{spec: "Mon-Fri 02:00-04:00", t: at(2, 3, 0).In(time.FixedZone("UTC+1", 3600)), expect: true},
+		{spec: "02:00-04:00", t: at(7, 3, 0), expect: true},
+		{spec: "Sat,Sun 22:00-24:00", t: at(7, 23, 59), expect: true},
+		{spec: "Sat,Sun 22:00-24:00", t: at(8, 0, 0), expect: false},
+		// Crossing midnight: the window opens on Friday and closes on Saturday
+		{spec: "Fri 22:00-02:00", t: at(5, 22, 0), expect: true},
+		{spec: "Fri 22:00-02:00", t: at(6, 1, 59), expect: true},
+		{spec: "Fri 22:00-02:00", t: at(6, 2, 0), expect: false},
+		{spec: "Fri 22:00-02:00", t: at(6, 22, 0), expect: false},
+		{spec: "Fri 22:00-02:00", t: at(5, 1, 0), expect: false},
+		// Day ranges wrap around the end of the week
+		{spec: "Sat-Mon 10:00-11:00", t: at(7, 10, 30), expect: true},
+		{spec: "Sat-Mon 10:00-11:00", t: at(8, 10, 30), expect: true},
+		{spec: "Sat-Mon 10:00-11:00", t: at(2, 10, 30), expect: false},
+	}
+	for _, tc := range tt {
+		t.Run(tc.spec+" "+tc.t.String(), func(t *testing.T) {
+			w, err := ParseCutOverWindow(tc.spec)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expect, w.Contains(tc.t))
+		})
+	}
+}
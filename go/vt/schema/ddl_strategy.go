@@ -31,6 +31,7 @@ var (
 	cutOverThresholdFlagRegexp  = regexp.MustCompile(fmt.Sprintf(`^[-]{1,2}%s=(.*?)$`, cutOverThresholdFlag))
 	forceCutOverAfterFlagRegexp = regexp.MustCompile(fmt.Sprintf(`^[-]{1,2}%s=(.*?)$`, forceCutOverAfterFlag))
 	retainArtifactsFlagRegexp   = regexp.MustCompile(fmt.Sprintf(`^[-]{1,2}%s=(.*?)$`, retainArtifactsFlag))
+	cutOverWindowFlagRegexp     = regexp.MustCompile(fmt.Sprintf(`^[-]{1,2}%s=(.*?)$`, cutOverWindowFlag))
 )
 
 const (
@@ -47,6 +48,8 @@ const (
 	fastRangeRotationFlag  = "fast-range-rotation"
 	cutOverThresholdFlag   = "cut-over-threshold"
 	forceCutOverAfterFlag  = "force-cut-over-after"
+	cutOverWindowFlag      = "cut-over-window"
+	coordinatedCutOverFlag = "coordinated-cutover"
 	retainArtifactsFlag    = "retain-artifacts"
 	vreplicationTestSuite  = "vreplication-test-suite"
 	allowForeignKeysFlag   = "unsafe-allow-foreign-keys"
@@ -129,6 +132,20 @@ func ParseDDLStrategy(strategyVariable string) (*DDLStrategySetting, error) {
 			return nil, fmt.Errorf("--force-cut-over-after is only valid in 'vitess' strategy. Found %v value in '%v' strategy", cutoverAfter, setting.Strategy)
 		}
 	}
+	cutOverWindow, err := setting.CutOverWindow()
+	if err != nil {
+		return nil, err
+	}
+	switch setting.Strategy {
+	case DDLStrategyVitess, DDLStrategyOnline:
+	default:
+		if cutOverWindow != nil {
+			return nil, fmt.Errorf("--cut-over-window is only valid in 'vitess' strategy. Found '%v' value in '%v' strategy", cutOverWindow, setting.Strategy)
+		}
+		if setting.IsCoordinatedCutOverFlag() {
+			return nil, fmt.Errorf("--coordinated-cutover is only valid in 'vitess' strategy. Found in '%v' strategy", setting.Strategy)
+		}
+	}
 
 	switch setting.Strategy {
 	case DDLStrategyVitess, DDLStrategyOnline, DDLStrategyMySQL, DDLStrategyDirect:
@@ -212,6 +229,11 @@ func (setting *DDLStrategySetting) IsFastRangeRotationFlag() bool {
 	return setting.hasFlag(fastRangeRotationFlag)
 }
 
+// IsCoordinatedCutOverFlag checks if strategy options include --coordinated-cutover
+func (setting *DDLStrategySetting) IsCoordinatedCutOverFlag() bool {
+	return setting.hasFlag(coordinatedCutOverFlag)
+}
+
 // isCutOverThresholdFlag returns true when given option denotes a `--cut-over-threshold=[...]` flag
 func isCutOverThresholdFlag(opt string) (string, bool) {
 	submatch := cutOverThresholdFlagRegexp.FindStringSubmatch(opt)
@@ -239,6 +261,15 @@ func isRetainArtifactsFlag(opt string) (string, bool) {
 	return submatch[1], true
 }
 
+// isCutOverWindowFlag returns true when given option denotes a `--cut-over-window=[...]` flag
+func isCutOverWindowFlag(opt string) (string, bool) {
+	submatch := cutOverWindowFlagRegexp.FindStringSubmatch(opt)
+	if len(submatch) == 0 {
+		return "", false
+	}
+	return submatch[1], true
+}
+
 // CutOverThreshold returns a the duration threshold indicated by --cut-over-threshold
 func (setting *DDLStrategySetting) CutOverThreshold() (d time.Duration, err error) {
 	// We do some ugly manual parsing of --cut-over-threshold value
@@ -275,6 +306,25 @@ func (setting *DDLStrategySetting) ForceCutOverAfter() (d time.Duration, err err
 	return d, err
 }
 
+// CutOverWindow returns the recurring time window indicated by --cut-over-window, or nil
+// if the flag is not set
+func (setting *DDLStrategySetting) CutOverWindow() (w *CutOverWindow, err error) {
+	// We do some ugly manual parsing of --cut-over-window value
+	opts, _ := shlex.Split(setting.Options)
+	for _, opt := range opts {
+		if val, isCutOverWindow := isCutOverWindowFlag(opt); isCutOverWindow {
+			// value is possibly quoted
+			if s, err := strconv.Unquote(val); err == nil {
+				val = s
+			}
+			if val != "" {
+				w, err = ParseCutOverWindow(val)
+			}
+		}
+	}
+	return w, err
+}
+
 // RetainArtifactsDuration returns a the duration indicated by --retain-artifacts
 func (setting *DDLStrategySetting) RetainArtifactsDuration() (d time.Duration, err error) {
 	// We do some ugly manual parsing of --retain-artifacts
@@ -322,6 +372,9 @@ func (setting *DDLStrategySetting) RuntimeOptions() []string {
 		if _, ok := isRetainArtifactsFlag(opt); ok {
 			continue
 		}
+		if _, ok := isCutOverWindowFlag(opt); ok {
+			continue
+		}
 		switch {
 		case isFlag(opt, declarativeFlag):
 		case isFlag(opt, skipTopoFlag):
@@ -334,6 +387,7 @@ func (setting *DDLStrategySetting) RuntimeOptions() []string {
 		case isFlag(opt, allowConcurrentFlag):
 		case isFlag(opt, preferInstantDDL):
 		case isFlag(opt, fastRangeRotationFlag):
+		case isFlag(opt, coordinatedCutOverFlag):
 		case isFlag(opt, vreplicationTestSuite):
 		case isFlag(opt, allowForeignKeysFlag):
 		case isFlag(opt, analyzeTableFlag):
@@ -199,6 +199,8 @@ func TestParseDDLStrategy(t *testing.T) {
 		analyzeTable         bool
 		cutOverThreshold     time.Duration
 		forceCutOverAfter    time.Duration
+		cutOverWindow        string
+		coordinatedCutOver   bool
 		expireArtifacts      time.Duration
 		runtimeOptions       string
 		expectError          string
@@ -340,6 +342,38 @@ func TestParseDDLStrategy(t *testing.T) {
 			runtimeOptions:   "",
 			expectError:      "--force-cut-over-after is only valid in 'vitess' strategy",
 		},
+		{
+			strategyVariable: "vitess --cut-over-window='Mon-Fri 02:00-04:00 UTC'",
+			strategy:         DDLStrategyVitess,
+			options:          "--cut-over-window='Mon-Fri 02:00-04:00 UTC'",
+			runtimeOptions:   "",
+			cutOverWindow:    "Mon-Fri 02:00-04:00 UTC",
+		},
+		{
+			strategyVariable: "vitess --cut-over-window=Mon-Fri",
+			strategy:         DDLStrategyVitess,
+			runtimeOptions:   "",
+			expectError:      "invalid cut-over window",
+		},
+		{
+			strategyVariable: "gh-ost --cut-over-window=02:00-04:00",
+			strategy:         DDLStrategyVitess,
+			runtimeOptions:   "",
+			expectError:      "--cut-over-window is only valid in 'vitess' strategy",
+		},
+		{
+			strategyVariable:   "vitess --coordinated-cutover",
+			strategy:           DDLStrategyVitess,
+			options:            "--coordinated-cutover",
+			runtimeOptions:     "",
+			coordinatedCutOver: true,
+		},
+		{
+			strategyVariable: "gh-ost --coordinated-cutover",
+			strategy:         DDLStrategyGhost,
+			runtimeOptions:   "",
+			expectError:      "--coordinated-cutover is only valid in 'vitess' strategy",
+		},
 		{
 			strategyVariable: "vitess --retain-artifacts=4m",
 			strategy:         DDLStrategyVitess,
@@ -387,12 +421,20 @@ func TestParseDDLStrategy(t *testing.T) {
 			assert.Equal(t, ts.fastRangeRotation, setting.IsFastRangeRotationFlag())
 			assert.Equal(t, ts.allowForeignKeys, setting.IsAllowForeignKeysFlag())
 			assert.Equal(t, ts.analyzeTable, setting.IsAnalyzeTableFlag())
+			assert.Equal(t, ts.coordinatedCutOver, setting.IsCoordinatedCutOverFlag())
 			cutOverThreshold, err := setting.CutOverThreshold()
 			assert.NoError(t, err)
 			assert.Equal(t, ts.cutOverThreshold, cutOverThreshold)
 			forceCutOverAfter, err := setting.ForceCutOverAfter()
 			assert.NoError(t, err)
 			assert.Equal(t, ts.forceCutOverAfter, forceCutOverAfter)
+			cutOverWindow, err := setting.CutOverWindow()
+			assert.NoError(t, err)
+			if ts.cutOverWindow == "" {
+				assert.Nil(t, cutOverWindow)
+			} else {
+				assert.Equal(t, ts.cutOverWindow, cutOverWindow.String())
+			}
 
 			runtimeOptions := strings.Join(setting.RuntimeOptions(), " ")
 			assert.Equal(t, ts.runtimeOptions, runtimeOptions)
@@ -0,0 +1,197 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+const (
+	DMLJobsTableName = "dml_jobs"
+	// DefaultOnlineDMLBatchSize is the number of rows per batch, when BATCH_DML is given without a value
+	DefaultOnlineDMLBatchSize = 1000
+)
+
+// OnlineDMLStatus is an indicator to a batch DML job status
+type OnlineDMLStatus string
+
+const (
+	OnlineDMLStatusQueued    OnlineDMLStatus = "queued"
+	OnlineDMLStatusRunning   OnlineDMLStatus = "running"
+	OnlineDMLStatusPaused    OnlineDMLStatus = "paused"
+	OnlineDMLStatusComplete  OnlineDMLStatus = "complete"
+	OnlineDMLStatusFailed    OnlineDMLStatus = "failed"
+	OnlineDMLStatusCancelled OnlineDMLStatus = "cancelled"
+)
+
+// OnlineDML encapsulates the relevant information in a batch DML job request: an UPDATE or DELETE
+// statement that is run in small, throttled transactions, each covering a range of the table's
+// primary key.
+type OnlineDML struct {
+	Keyspace   string `json:"keyspace,omitempty"`
+	Table      string `json:"table,omitempty"`
+	SQL        string `json:"sql,omitempty"`
+	UUID       string `json:"uuid,omitempty"`
+	BatchSize  int64  `json:"batch_size,omitempty"`
+	JobContext string `json:"context,omitempty"`
+}
+
+// ValidateOnlineDMLStatement checks that the given statement can run as a batch DML job, and returns
+// the name of the table it operates on. A batch DML job is a single table UPDATE or DELETE, without
+// ORDER BY or LIMIT clauses, since the statement is applied to one primary key range at a time.
+func ValidateOnlineDMLStatement(stmt sqlparser.Statement) (table sqlparser.TableName, err error) {
+	var tableExprs []sqlparser.TableExpr
+	switch stmt := stmt.(type) {
+	case *sqlparser.Update:
+		if stmt.With != nil {
+			return table, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "WITH is not supported in batch DML")
+		}
+		if len(stmt.OrderBy) > 0 || stmt.Limit != nil {
+			return table, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "ORDER BY and LIMIT are not supported in batch DML")
+		}
+		tableExprs = stmt.TableExprs
+	case *sqlparser.Delete:
+		if stmt.With != nil {
+			return table, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "WITH is not supported in batch DML")
+		}
+		if len(stmt.OrderBy) > 0 || stmt.Limit != nil {
+			return table, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "ORDER BY and LIMIT are not supported in batch DML")
+		}
+		if len(stmt.Targets) > 0 || stmt.Partitions != nil {
+			return table, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "multi-table and partition DELETE are not supported in batch DML")
+		}
+		tableExprs = stmt.TableExprs
+	default:
+		return table, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "unsupported statement for batch DML: %v", sqlparser.String(stmt))
+	}
+	if len(tableExprs) != 1 {
+		return table, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "batch DML only supports a single table: %v", sqlparser.String(stmt))
+	}
+	aliasedTableExpr, ok := tableExprs[0].(*sqlparser.AliasedTableExpr)
+	if !ok {
+		return table, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "batch DML only supports a single table: %v", sqlparser.String(stmt))
+	}
+	table, err = aliasedTableExpr.TableName()
+	if err != nil {
+		return table, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "batch DML only supports a single table: %v", sqlparser.String(stmt))
+	}
+	return table, nil
+}
+
+// NewOnlineDML creates a batch DML job request with a self generated UUID. The statement's comments
+// are replaced with a BATCH_DML directive along with the job's metadata, e.g.
+// `DELETE /*vt+ BATCH_DML=1000 uuid=... context=... table=... */ FROM ...`
+func NewOnlineDML(keyspace string, stmt sqlparser.Statement, batchSize int64, jobContext string) (onlineDML *OnlineDML, err error) {
+	table, err := ValidateOnlineDMLStatement(stmt)
+	if err != nil {
+		return nil, err
+	}
+	if batchSize <= 0 {
+		batchSize = DefaultOnlineDMLBatchSize
+	}
+	if err := ValidateMigrationContext(jobContext); err != nil {
+		return nil, err
+	}
+	uuid, err := CreateOnlineDDLUUID()
+	if err != nil {
+		return nil, err
+	}
+
+	encodeDirective := func(directive string) string {
+		return strconv.Quote(hex.EncodeToString([]byte(directive)))
+	}
+	comments := sqlparser.Comments{
+		fmt.Sprintf(`/*vt+ %s=%d uuid=%s context=%s table=%s */`,
+			sqlparser.DirectiveBatchDML,
+			batchSize,
+			encodeDirective(uuid),
+			encodeDirective(jobContext),
+			encodeDirective(table.Name.String()),
+		)}
+	// The statement may be cached by the query planner, so we work on a copy of it.
+	stmt = sqlparser.CloneStatement(stmt)
+	stmt.(sqlparser.Commented).SetComments(comments)
+
+	return &OnlineDML{
+		Keyspace:   keyspace,
+		Table:      table.Name.String(),
+		SQL:        sqlparser.String(stmt),
+		UUID:       uuid,
+		BatchSize:  batchSize,
+		JobContext: jobContext,
+	}, nil
+}
+
+// OnlineDMLFromCommentedStatement creates a batch DML job request based on a commented query. The query is expected
+// to be commented as e.g. `DELETE /*vt+ BATCH_DML=... uuid=... context=... table=... */ FROM ...`
+func OnlineDMLFromCommentedStatement(stmt sqlparser.Statement) (onlineDML *OnlineDML, err error) {
+	if _, err := ValidateOnlineDMLStatement(stmt); err != nil {
+		return nil, err
+	}
+	batchSize, isBatchDML, err := sqlparser.GetBatchDMLFromStatement(stmt)
+	if err != nil {
+		return nil, err
+	}
+	if !isBatchDML {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "no %s directive found in statement: %v", sqlparser.DirectiveBatchDML, sqlparser.String(stmt))
+	}
+	if batchSize == 0 {
+		batchSize = DefaultOnlineDMLBatchSize
+	}
+	// See OnlineDDLFromCommentedStatement as for why we clone the comments.
+	comments := sqlparser.CloneRefOfParsedComments(stmt.(sqlparser.Commented).GetParsedComments())
+	comments.ResetDirectives()
+	directives := comments.Directives()
+	decodeDirective := func(name string) (string, error) {
+		value, ok := directives.GetString(name, "")
+		if !ok {
+			return "", vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "no value found for comment directive %s", name)
+		}
+		b, err := hex.DecodeString(value)
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	}
+
+	buf := sqlparser.NewTrackedBuffer(formatWithoutComments)
+	stmt.Format(buf)
+
+	onlineDML = &OnlineDML{
+		SQL:       buf.String(),
+		BatchSize: batchSize,
+	}
+	if onlineDML.UUID, err = decodeDirective("uuid"); err != nil {
+		return nil, err
+	}
+	if !IsOnlineDDLUUID(onlineDML.UUID) {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid UUID read from statement %s", sqlparser.String(stmt))
+	}
+	if onlineDML.Table, err = decodeDirective("table"); err != nil {
+		return nil, err
+	}
+	if onlineDML.JobContext, err = decodeDirective("context"); err != nil {
+		return nil, err
+	}
+	return onlineDML, nil
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/sqlparser"
+)
+
+func TestValidateOnlineDMLStatement(t *testing.T) {
+	tcases := []struct {
+		query string
+		table string
+		err   string
+	}{
+		{
+			query: "delete from t where id > 5",
+			table: "t",
+		},
+		{
+			query: "update /*vt+ BATCH_DML */ ks.t set val = 1 where val is null",
+			table: "t",
+		},
+		{
+			query: "delete from t where id > 5 limit 10",
+			err:   "ORDER BY and LIMIT are not supported in batch DML",
+		},
+		{
+			query: "update t set val = 1 order by id",
+			err:   "ORDER BY and LIMIT are not supported in batch DML",
+		},
+		{
+			query: "update t1 join t2 on t1.id = t2.id set t1.val = t2.val",
+			err:   "batch DML only supports a single table",
+		},
+		{
+			query: "delete t1 from t1, t2 where t1.id = t2.id",
+			err:   "multi-table and partition DELETE are not supported in batch DML",
+		},
+		{
+			query: "insert into t values (1)",
+			err:   "unsupported statement for batch DML",
+		},
+	}
+	parser := sqlparser.NewTestParser()
+	for _, tcase := range tcases {
+		t.Run(tcase.query, func(t *testing.T) {
+			stmt, err := parser.Parse(tcase.query)
+			require.NoError(t, err)
+			table, err := ValidateOnlineDMLStatement(stmt)
+			if tcase.err != "" {
+				assert.ErrorContains(t, err, tcase.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tcase.table, table.Name.String())
+		})
+	}
+}
+
+func TestOnlineDMLFromCommentedStatement(t *testing.T) {
+	tcases := []struct {
+		query     string
+		batchSize int64
+		sql       string
+	}{
+		{
+			query: "delete from t where id > 5",
+			sql:   "delete from t where id > 5",
+		},
+		{
+			query:     "update /*vt+ BATCH_DML=200 */ t set val = 'x' where val is null",
+			batchSize: 200,
+			sql:       "update t set val = 'x' where val is null",
+		},
+	}
+	jobContext := "vtgate:354b-11eb-82cd-f875a4d24e90"
+	parser := sqlparser.NewTestParser()
+	for _, tcase := range tcases {
+		t.Run(tcase.query, func(t *testing.T) {
+			stmt, err := parser.Parse(tcase.query)
+			require.NoError(t, err)
+			batchSize, _, err := sqlparser.GetBatchDMLFromStatement(stmt)
+			require.NoError(t, err)
+			o1, err := NewOnlineDML("ks", stmt, batchSize, jobContext)
+			require.NoError(t, err)
+			// The original statement is left untouched
+			assert.Equal(t, tcase.query, sqlparser.String(stmt))
+
+			stmt, err = parser.Parse(o1.SQL)
+			require.NoError(t, err)
+			o2, err := OnlineDMLFromCommentedStatement(stmt)
+			require.NoError(t, err)
+			assert.True(t, IsOnlineDDLUUID(o2.UUID))
+			assert.Equal(t, o1.UUID, o2.UUID)
+			assert.Equal(t, jobContext, o2.JobContext)
+			assert.Equal(t, "t", o2.Table)
+			assert.Equal(t, tcase.sql, o2.SQL)
+			if tcase.batchSize == 0 {
+				assert.EqualValues(t, DefaultOnlineDMLBatchSize, o2.BatchSize)
+			} else {
+				assert.Equal(t, tcase.batchSize, o2.BatchSize)
+			}
+		})
+	}
+
+	stmt, err := parser.Parse("delete /*vt+ BATCH_DML */ from t")
+	require.NoError(t, err)
+	_, err = OnlineDMLFromCommentedStatement(stmt)
+	assert.ErrorContains(t, err, "no value found for comment directive uuid")
+}
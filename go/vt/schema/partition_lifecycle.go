@@ -0,0 +1,98 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"strings"
+	"time"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+// PartitionInterval is the time span covered by each partition of a table whose partitions are
+// managed by the partition lifecycle manager.
+type PartitionInterval string
+
+const (
+	PartitionIntervalHour  PartitionInterval = "hour"
+	PartitionIntervalDay   PartitionInterval = "day"
+	PartitionIntervalWeek  PartitionInterval = "week"
+	PartitionIntervalMonth PartitionInterval = "month"
+	PartitionIntervalYear  PartitionInterval = "year"
+)
+
+// ParsePartitionInterval parses a partition interval name, case insensitively.
+func ParsePartitionInterval(s string) (PartitionInterval, error) {
+	switch interval := PartitionInterval(strings.ToLower(strings.TrimSpace(s))); interval {
+	case PartitionIntervalHour, PartitionIntervalDay, PartitionIntervalWeek, PartitionIntervalMonth, PartitionIntervalYear:
+		return interval, nil
+	}
+	return "", vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid partition interval: %q. Supported intervals are hour, day, week, month and year", s)
+}
+
+// Truncate returns the beginning of the interval that contains the given time, in UTC. Weeks begin
+// on Monday.
+func (i PartitionInterval) Truncate(t time.Time) time.Time {
+	t = t.UTC()
+	switch i {
+	case PartitionIntervalHour:
+		return t.Truncate(time.Hour)
+	case PartitionIntervalDay:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	case PartitionIntervalWeek:
+		daysSinceMonday := (int(t.Weekday()) + 6) % 7
+		return time.Date(t.Year(), t.Month(), t.Day()-daysSinceMonday, 0, 0, 0, 0, time.UTC)
+	case PartitionIntervalMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	case PartitionIntervalYear:
+		return time.Date(t.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	}
+	return t
+}
+
+// Add returns the time n intervals after the given time.
+func (i PartitionInterval) Add(t time.Time, n int) time.Time {
+	switch i {
+	case PartitionIntervalHour:
+		return t.Add(time.Duration(n) * time.Hour)
+	case PartitionIntervalDay:
+		return t.AddDate(0, 0, n)
+	case PartitionIntervalWeek:
+		return t.AddDate(0, 0, 7*n)
+	case PartitionIntervalMonth:
+		return t.AddDate(0, n, 0)
+	case PartitionIntervalYear:
+		return t.AddDate(n, 0, 0)
+	}
+	return t
+}
+
+// PartitionName returns the name of the partition for the interval that begins at the given time,
+// e.g. p20240501 for a daily partition.
+func (i PartitionInterval) PartitionName(start time.Time) string {
+	start = start.UTC()
+	switch i {
+	case PartitionIntervalHour:
+		return start.Format("p2006010215")
+	case PartitionIntervalMonth:
+		return start.Format("p200601")
+	case PartitionIntervalYear:
+		return start.Format("p2006")
+	}
+	return start.Format("p20060102")
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePartitionInterval(t *testing.T) {
+	interval, err := ParsePartitionInterval(" Day")
+	require.NoError(t, err)
+	assert.Equal(t, PartitionIntervalDay, interval)
+
+	_, err = ParsePartitionInterval("fortnight")
+	assert.ErrorContains(t, err, "invalid partition interval")
+}
+
+func TestPartitionInterval(t *testing.T) {
+	// A Thursday
+	now := time.Date(2024, time.February, 29, 13, 45, 10, 0, time.UTC)
+	tcases := []struct {
+		interval PartitionInterval
+		start    string
+		next     string
+		name     string
+	}{
+		{
+			interval: PartitionIntervalHour,
+			start:    "2024-02-29 13:00:00",
+			next:     "2024-02-29 14:00:00",
+			name:     "p2024022913",
+		},
+		{
+			interval: PartitionIntervalDay,
+			start:    "2024-02-29 00:00:00",
+			next:     "2024-03-01 00:00:00",
+			name:     "p20240229",
+		},
+		{
+			interval: PartitionIntervalWeek,
+			start:    "2024-02-26 00:00:00",
+			next:     "2024-03-04 00:00:00",
+			name:     "p20240226",
+		},
+		{
+			interval: PartitionIntervalMonth,
+			start:    "2024-02-01 00:00:00",
+			next:     "2024-03-01 00:00:00",
+			name:     "p202402",
+		},
+		{
+			interval: PartitionIntervalYear,
+			start:    "2024-01-01 00:00:00",
+			next:     "2025-01-01 00:00:00",
+			name:     "p2024",
+		},
+	}
+	for _, tcase := range tcases {
+		t.Run(string(tcase.interval), func(t *testing.T) {
+			start := tcase.interval.Truncate(now)
+			assert.Equal(t, tcase.start, start.Format(time.DateTime))
+			assert.Equal(t, tcase.next, tcase.interval.Add(start, 1).Format(time.DateTime))
+			assert.Equal(t, tcase.name, tcase.interval.PartitionName(start))
+		})
+	}
+}
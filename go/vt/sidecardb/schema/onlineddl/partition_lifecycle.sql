@@ -0,0 +1,34 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+CREATE TABLE IF NOT EXISTS partition_lifecycle
+(
+    `id`                   bigint unsigned  NOT NULL AUTO_INCREMENT,
+    `mysql_table`          varchar(128)     NOT NULL,
+    `partition_interval`   varchar(16)      NOT NULL,
+    `future_partitions`    int unsigned     NOT NULL DEFAULT '0',
+    `retention_seconds`    bigint unsigned  NOT NULL DEFAULT '0',
+    `lifecycle_status`     varchar(32)      NOT NULL DEFAULT '',
+    `message`              text             NOT NULL,
+    `partitions`           int unsigned     NOT NULL DEFAULT '0',
+    `first_partition`      varchar(64)      NOT NULL DEFAULT '',
+    `last_partition`       varchar(64)      NOT NULL DEFAULT '',
+    `submitted_migrations` text             NOT NULL,
+    `added_timestamp`      timestamp        NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `last_check_timestamp` timestamp        NULL     DEFAULT NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `mysql_table_idx` (`mysql_table`)
+) ENGINE = InnoDB
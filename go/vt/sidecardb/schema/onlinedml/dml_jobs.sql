@@ -0,0 +1,45 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+CREATE TABLE IF NOT EXISTS dml_jobs
+(
+    `id`                  bigint unsigned  NOT NULL AUTO_INCREMENT,
+    `job_uuid`            varchar(64)      NOT NULL,
+    `keyspace`            varchar(256)     NOT NULL,
+    `shard`               varchar(255)     NOT NULL,
+    `mysql_schema`        varchar(128)     NOT NULL,
+    `mysql_table`         varchar(128)     NOT NULL,
+    `job_statement`       text             NOT NULL,
+    `job_context`         varchar(1024)    NOT NULL DEFAULT '',
+    `job_status`          varchar(128)     NOT NULL,
+    `batch_size`          bigint unsigned  NOT NULL,
+    `tablet`              varchar(128)     NOT NULL DEFAULT '',
+    `pk_columns`          text             NOT NULL,
+    `last_pk`             text             NOT NULL,
+    `rows_scanned`        bigint unsigned  NOT NULL DEFAULT '0',
+    `rows_affected`       bigint unsigned  NOT NULL DEFAULT '0',
+    `table_rows`          bigint           NOT NULL DEFAULT '0',
+    `progress`            float            NOT NULL DEFAULT '0',
+    `message`             text             NOT NULL,
+    `added_timestamp`     timestamp        NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `started_timestamp`   timestamp        NULL     DEFAULT NULL,
+    `liveness_timestamp`  timestamp        NULL     DEFAULT NULL,
+    `completed_timestamp` timestamp(6)     NULL     DEFAULT NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uuid_idx` (`job_uuid`),
+    KEY `status_idx` (`job_status`, `liveness_timestamp`),
+    KEY `job_context_idx` (`job_context`(64))
+) ENGINE = InnoDB
@@ -0,0 +1,29 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+CREATE TABLE IF NOT EXISTS schema_history
+(
+    `id`                      bigint unsigned  NOT NULL AUTO_INCREMENT,
+    `pos`                     varbinary(10000) NOT NULL,
+    `time_updated`            bigint           NOT NULL,
+    `table_name`              varchar(128)     NOT NULL,
+    `ddl`                     blob             NOT NULL,
+    `migration_uuid`          varchar(64)      NOT NULL DEFAULT '',
+    `create_statement_before` longtext,
+    `create_statement_after`  longtext         NOT NULL,
+    PRIMARY KEY (`id`),
+    KEY `table_name_idx` (`table_name`, `id`)
+) ENGINE = InnoDB
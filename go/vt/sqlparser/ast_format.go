@@ -307,6 +307,12 @@ func (node *AlterMigration) Format(buf *TrackedBuffer) {
 		alterType = "force_cutover"
 	case ForceCutOverAllMigrationType:
 		alterType = "force_cutover all"
+	case PrepareCutOverMigrationType:
+		alterType = "prepare_cutover"
+	case CommitCutOverMigrationType:
+		alterType = "commit_cutover"
+	case AbortCutOverMigrationType:
+		alterType = "abort_cutover"
 	}
 	buf.astPrintf(node, " %#s", alterType)
 	if node.Expire != "" {
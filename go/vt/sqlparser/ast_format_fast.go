@@ -428,6 +428,12 @@ func (node *AlterMigration) FormatFast(buf *TrackedBuffer) {
 		alterType = "force_cutover"
 	case ForceCutOverAllMigrationType:
 		alterType = "force_cutover all"
+	case PrepareCutOverMigrationType:
+		alterType = "prepare_cutover"
+	case CommitCutOverMigrationType:
+		alterType = "commit_cutover"
+	case AbortCutOverMigrationType:
+		alterType = "abort_cutover"
 	}
 	buf.WriteByte(' ')
 	buf.WriteString(alterType)
@@ -1978,10 +1978,16 @@ func (ty ShowCommandType) ToString() string {
 		return VariableSessionStr
 	case VGtidExecGlobal:
 		return VGtidExecGlobalStr
+	case VitessDMLJobs:
+		return VitessDMLJobsStr
 	case VitessMigrations:
 		return VitessMigrationsStr
+	case VitessQueryDigests:
+		return VitessQueryDigestsStr
 	case VitessReplicationStatus:
 		return VitessReplicationStatusStr
+	case VitessSchemaHistory:
+		return VitessSchemaHistoryStr
 	case VitessShards:
 		return VitessShardsStr
 	case VitessTablets:
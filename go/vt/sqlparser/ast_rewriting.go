@@ -383,6 +383,7 @@ func (er *astRewriter) sysVarRewrite(cursor *Cursor, node *Variable) {
 	case sysvars.Autocommit.Name,
 		sysvars.Charset.Name,
 		sysvars.ClientFoundRows.Name,
+		sysvars.ConsistentSnapshotGTIDs.Name,
 		sysvars.DDLStrategy.Name,
 		sysvars.MigrationContext.Name,
 		sysvars.Names.Name,
@@ -58,6 +58,12 @@ const (
 	// DirectivePriority specifies the priority of a workload. It should be an integer between 0 and MaxPriorityValue,
 	// where 0 is the highest priority, and MaxPriorityValue is the lowest one.
 	DirectivePriority = "PRIORITY"
+	// DirectiveTenant tags the query with the tenant that vtgate enforces the resource quotas of, when the tenant
+	// quotas are keyed by tag.
+	DirectiveTenant = "TENANT"
+	// DirectiveBatchDML runs an UPDATE or DELETE as a throttled, resumable batch DML job rather than as a single
+	// transaction. It optionally takes the number of rows per batch as a value, e.g. BATCH_DML=500.
+	DirectiveBatchDML = "BATCH_DML"
 
 	// MaxPriorityValue specifies the maximum value allowed for the priority query directive. Valid priority values are
 	// between zero and MaxPriorityValue.
@@ -69,6 +75,8 @@ const (
 
 var ErrInvalidPriority = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "Invalid priority value specified in query")
 
+var ErrInvalidBatchDMLSize = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "Invalid BATCH_DML batch size specified in query")
+
 func isNonSpace(r rune) bool {
 	return !unicode.IsSpace(r)
 }
@@ -606,6 +614,32 @@ func GetPriorityFromStatement(statement Statement) (string, error) {
 	return priority, nil
 }
 
+// GetBatchDMLFromStatement returns whether the provided UPDATE or DELETE Statement has the DirectiveBatchDML
+// directive, along with the requested batch size. The batch size is zero when the directive has no value.
+func GetBatchDMLFromStatement(statement Statement) (batchSize int64, isBatchDML bool, err error) {
+	var comments *ParsedComments
+	switch stmt := statement.(type) {
+	case *Update:
+		comments = stmt.Comments
+	case *Delete:
+		comments = stmt.Comments
+	default:
+		return 0, false, nil
+	}
+	val, ok := comments.Directives().GetString(DirectiveBatchDML, "")
+	if !ok {
+		return 0, false, nil
+	}
+	if val == "true" {
+		return 0, true, nil
+	}
+	batchSize, err = strconv.ParseInt(val, 10, 64)
+	if err != nil || batchSize <= 0 {
+		return 0, false, ErrInvalidBatchDMLSize
+	}
+	return batchSize, true, nil
+}
+
 // Consolidator returns the consolidator option.
 func Consolidator(stmt Statement) querypb.ExecuteOptions_Consolidator {
 	var comments *ParsedComments
@@ -644,3 +678,15 @@ func GetWorkloadNameFromStatement(statement Statement) string {
 
 	return workloadName
 }
+
+// GetTenantFromStatement gets the tenant tag of the provided Statement from its TENANT query directive, or an
+// empty string if it has none.
+func GetTenantFromStatement(statement Statement) string {
+	commentedStatement, ok := statement.(Commented)
+	if !ok {
+		return ""
+	}
+
+	tenant, _ := commentedStatement.GetParsedComments().Directives().GetString(DirectiveTenant, "")
+	return tenant
+}
@@ -480,6 +480,29 @@ func TestConsolidator(t *testing.T) {
 	}
 }
 
+func TestGetTenantFromStatement(t *testing.T) {
+	testCases := []struct {
+		query    string
+		expected string
+	}{
+		{"select * from users", ""},
+		{"select /*vt+ WORKLOAD_NAME=app */ * from users", ""},
+		{"select /*vt+ TENANT=acme */ * from users", "acme"},
+		{"insert /*vt+ TENANT=acme */ into users(id) values (1)", "acme"},
+		{"update /*vt+ TENANT=\"acme\" */ users set name=1", "acme"},
+		{"begin", ""},
+	}
+
+	parser := NewTestParser()
+	for _, test := range testCases {
+		t.Run(test.query, func(t *testing.T) {
+			stmt, err := parser.Parse(test.query)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, GetTenantFromStatement(stmt))
+		})
+	}
+}
+
 func TestGetPriorityFromStatement(t *testing.T) {
 	testCases := []struct {
 		query            string
@@ -545,6 +568,55 @@ func TestGetPriorityFromStatement(t *testing.T) {
 	}
 }
 
+func TestGetBatchDMLFromStatement(t *testing.T) {
+	testCases := []struct {
+		query             string
+		expectedBatchSize int64
+		expectedBatchDML  bool
+		expectedError     error
+	}{
+		{
+			query: "delete from a_table where id > 5",
+		},
+		{
+			query: "select /*vt+ BATCH_DML */ * from a_table",
+		},
+		{
+			query:            "delete /*vt+ BATCH_DML */ from a_table where id > 5",
+			expectedBatchDML: true,
+		},
+		{
+			query:             "update /*vt+ BATCH_DML=500 */ a_table set val = 1",
+			expectedBatchSize: 500,
+			expectedBatchDML:  true,
+		},
+		{
+			query:         "update /*vt+ BATCH_DML=0 */ a_table set val = 1",
+			expectedError: ErrInvalidBatchDMLSize,
+		},
+		{
+			query:         "delete /*vt+ BATCH_DML=some_text */ from a_table",
+			expectedError: ErrInvalidBatchDMLSize,
+		},
+	}
+
+	parser := NewTestParser()
+	for _, testCase := range testCases {
+		t.Run(testCase.query, func(t *testing.T) {
+			stmt, err := parser.Parse(testCase.query)
+			require.NoError(t, err)
+			batchSize, isBatchDML, err := GetBatchDMLFromStatement(stmt)
+			if testCase.expectedError != nil {
+				assert.ErrorIs(t, err, testCase.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expectedBatchDML, isBatchDML)
+			assert.Equal(t, testCase.expectedBatchSize, batchSize)
+		})
+	}
+}
+
 // TestGetMySQLSetVarValue tests the functionality of GetMySQLSetVarValue
 func TestGetMySQLSetVarValue(t *testing.T) {
 	tests := []struct {
@@ -310,8 +310,11 @@ const (
 	VariableSessionStr         = " variables"
 	VGtidExecGlobalStr         = " global vgtid_executed"
 	KeyspaceStr                = " keyspaces"
+	VitessDMLJobsStr           = " vitess_dml_jobs"
 	VitessMigrationsStr        = " vitess_migrations"
+	VitessQueryDigestsStr      = " vitess_query_digests"
 	VitessReplicationStatusStr = " vitess_replication_status"
+	VitessSchemaHistoryStr     = " vitess_schema_history"
 	VitessShardsStr            = " vitess_shards"
 	VitessTabletsStr           = " vitess_tablets"
 	VitessTargetStr            = " vitess_target"
@@ -888,8 +891,11 @@ const (
 	VariableGlobal
 	VariableSession
 	VGtidExecGlobal
+	VitessDMLJobs
 	VitessMigrations
+	VitessQueryDigests
 	VitessReplicationStatus
+	VitessSchemaHistory
 	VitessShards
 	VitessTablets
 	VitessTarget
@@ -933,6 +939,9 @@ const (
 	UnthrottleAllMigrationType
 	ForceCutOverMigrationType
 	ForceCutOverAllMigrationType
+	PrepareCutOverMigrationType
+	CommitCutOverMigrationType
+	AbortCutOverMigrationType
 )
 
 // ColumnStorage constants
@@ -114,6 +114,7 @@ var keywords = []keyword{
 	{"_utf8", UNDERSCORE_UTF8},
 	{"_utf8mb4", UNDERSCORE_UTF8MB4},
 	{"_utf8mb3", UNDERSCORE_UTF8MB3},
+	{"abort_cutover", ABORT_CUTOVER},
 	{"accessible", UNUSED},
 	{"action", ACTION},
 	{"add", ADD},
@@ -175,6 +176,7 @@ var keywords = []keyword{
 	{"comment", COMMENT_KEYWORD},
 	{"committed", COMMITTED},
 	{"commit", COMMIT},
+	{"commit_cutover", COMMIT_CUTOVER},
 	{"compact", COMPACT},
 	{"complete", COMPLETE},
 	{"compressed", COMPRESSED},
@@ -504,6 +506,7 @@ var keywords = []keyword{
 	{"preceding", PRECEDING},
 	{"precision", UNUSED},
 	{"prepare", PREPARE},
+	{"prepare_cutover", PREPARE_CUTOVER},
 	{"primary", PRIMARY},
 	{"privileges", PRIVILEGES},
 	{"purge", PURGE},
@@ -752,11 +755,14 @@ var keywords = []keyword{
 	{"vindexes", VINDEXES},
 	{"view", VIEW},
 	{"vitess", VITESS},
+	{"vitess_dml_jobs", VITESS_DML_JOBS},
 	{"vitess_keyspaces", VITESS_KEYSPACES},
 	{"vitess_metadata", VITESS_METADATA},
 	{"vitess_migration", VITESS_MIGRATION},
 	{"vitess_migrations", VITESS_MIGRATIONS},
+	{"vitess_query_digests", VITESS_QUERY_DIGESTS},
 	{"vitess_replication_status", VITESS_REPLICATION_STATUS},
+	{"vitess_schema_history", VITESS_SCHEMA_HISTORY},
 	{"vitess_shards", VITESS_SHARDS},
 	{"vitess_tablets", VITESS_TABLETS},
 	{"vitess_target", VITESS_TARGET},
@@ -2362,6 +2362,10 @@ var (
 		output: "show keyspaces like '%'",
 	}, {
 		input: "show vitess_metadata variables",
+	}, {
+		input: "show vitess_query_digests",
+	}, {
+		input: "show vitess_query_digests like '%select%'",
 	}, {
 		input: "show vitess_replication_status",
 	}, {
@@ -2389,6 +2393,18 @@ var (
 	}, {
 		input:  "show vschema vindexes on t",
 		output: "show vschema vindexes from t",
+	}, {
+		input: "show vitess_dml_jobs",
+	}, {
+		input: "show vitess_dml_jobs from ks where job_status = 'running'",
+	}, {
+		input: "show vitess_dml_jobs like '9748c3b7_7fdb_11eb_ac2c_f875a4d24e90'",
+	}, {
+		input: "show vitess_schema_history",
+	}, {
+		input: "show vitess_schema_history from ks like 'customer'",
+	}, {
+		input: "show vitess_schema_history where migration_uuid = '9748c3b7_7fdb_11eb_ac2c_f875a4d24e90'",
 	}, {
 		input: "show vitess_migrations",
 	}, {
@@ -2430,6 +2446,14 @@ var (
 	}, {
 		input:  "alter vitess_migration '9748c3b7_7fdb_11eb_ac2c_f875a4d24e90' FORCE_CUTOVER",
 		output: "alter vitess_migration '9748c3b7_7fdb_11eb_ac2c_f875a4d24e90' force_cutover",
+	}, {
+		input: "alter vitess_migration '9748c3b7_7fdb_11eb_ac2c_f875a4d24e90' prepare_cutover",
+	}, {
+		input: "alter vitess_migration '9748c3b7_7fdb_11eb_ac2c_f875a4d24e90' prepare_cutover expire '45s'",
+	}, {
+		input: "alter vitess_migration '9748c3b7_7fdb_11eb_ac2c_f875a4d24e90' commit_cutover",
+	}, {
+		input: "alter vitess_migration '9748c3b7_7fdb_11eb_ac2c_f875a4d24e90' abort_cutover",
 	}, {
 		input: "alter vitess_migration cancel all",
 	}, {
@@ -2471,6 +2495,10 @@ var (
 		// Making sure "force_cutover" is not a keyword
 		input:  "select force_cutover from t",
 		output: "select `force_cutover` from t",
+	}, {
+		// Making sure the coordinated cut-over keywords are not reserved
+		input:  "select prepare_cutover, commit_cutover, abort_cutover from t",
+		output: "select `prepare_cutover`, `commit_cutover`, `abort_cutover` from t",
 	}, {
 		input:  "use db",
 		output: "use db",
@@ -315,7 +315,7 @@ func markBindVariable(yylex yyLexer, bvar string) {
 %token <str> SEQUENCE MERGE TEMPORARY TEMPTABLE INVOKER SECURITY FIRST AFTER LAST
 
 // Migration tokens
-%token <str> VITESS_MIGRATION CANCEL RETRY LAUNCH COMPLETE CLEANUP THROTTLE UNTHROTTLE FORCE_CUTOVER EXPIRE RATIO
+%token <str> VITESS_MIGRATION CANCEL RETRY LAUNCH COMPLETE CLEANUP THROTTLE UNTHROTTLE FORCE_CUTOVER PREPARE_CUTOVER COMMIT_CUTOVER ABORT_CUTOVER EXPIRE RATIO
 // Throttler tokens
 %token <str> VITESS_THROTTLER
 
@@ -342,7 +342,7 @@ func markBindVariable(yylex yyLexer, bvar string) {
 // SHOW tokens
 %token <str> CODE COLLATION COLUMNS DATABASES ENGINES EVENT EXTENDED FIELDS FULL FUNCTION GTID_EXECUTED
 %token <str> KEYSPACES OPEN PLUGINS PRIVILEGES PROCESSLIST SCHEMAS TABLES TRIGGERS USER
-%token <str> VGTID_EXECUTED VITESS_KEYSPACES VITESS_METADATA VITESS_MIGRATIONS VITESS_REPLICATION_STATUS VITESS_SHARDS VITESS_TABLETS VITESS_TARGET VSCHEMA VITESS_THROTTLED_APPS
+%token <str> VGTID_EXECUTED VITESS_DML_JOBS VITESS_KEYSPACES VITESS_METADATA VITESS_MIGRATIONS VITESS_QUERY_DIGESTS VITESS_REPLICATION_STATUS VITESS_SCHEMA_HISTORY VITESS_SHARDS VITESS_TABLETS VITESS_TARGET VSCHEMA VITESS_THROTTLED_APPS
 
 // SET tokens
 %token <str> NAMES GLOBAL SESSION ISOLATION LEVEL READ WRITE ONLY REPEATABLE COMMITTED UNCOMMITTED SERIALIZABLE
@@ -3392,6 +3392,28 @@ alter_statement:
       Type: ForceCutOverAllMigrationType,
     }
   }
+| ALTER comment_opt VITESS_MIGRATION STRING PREPARE_CUTOVER expire_opt
+  {
+    $$ = &AlterMigration{
+      Type: PrepareCutOverMigrationType,
+      UUID: string($4),
+      Expire: $6,
+    }
+  }
+| ALTER comment_opt VITESS_MIGRATION STRING COMMIT_CUTOVER
+  {
+    $$ = &AlterMigration{
+      Type: CommitCutOverMigrationType,
+      UUID: string($4),
+    }
+  }
+| ALTER comment_opt VITESS_MIGRATION STRING ABORT_CUTOVER
+  {
+    $$ = &AlterMigration{
+      Type: AbortCutOverMigrationType,
+      UUID: string($4),
+    }
+  }
 
 partitions_options_opt:
   {
@@ -4127,6 +4149,14 @@ show_statement:
   {
     $$ = &Show{&ShowBasic{Command: VitessVariables, Filter: $4}}
   }
+| SHOW VITESS_DML_JOBS from_database_opt like_or_where_opt
+  {
+    $$ = &Show{&ShowBasic{Command: VitessDMLJobs, Filter: $4, DbName: $3}}
+  }
+| SHOW VITESS_SCHEMA_HISTORY from_database_opt like_or_where_opt
+  {
+    $$ = &Show{&ShowBasic{Command: VitessSchemaHistory, Filter: $4, DbName: $3}}
+  }
 | SHOW VITESS_MIGRATIONS from_database_opt like_or_where_opt
   {
     $$ = &Show{&ShowBasic{Command: VitessMigrations, Filter: $4, DbName: $3}}
@@ -4139,6 +4169,10 @@ show_statement:
   {
     $$ = &ShowThrottledApps{}
   }
+| SHOW VITESS_QUERY_DIGESTS like_opt
+  {
+    $$ = &Show{&ShowBasic{Command: VitessQueryDigests, Filter: $3}}
+  }
 | SHOW VITESS_REPLICATION_STATUS like_opt
   {
     $$ = &Show{&ShowBasic{Command: VitessReplicationStatus, Filter: $3}}
@@ -8130,6 +8164,7 @@ reserved_keyword:
 */
 non_reserved_keyword:
   AGAINST
+| ABORT_CUTOVER
 | ACTION
 | ACTIVE
 | ADDDATE %prec FUNCTION_CALL_NON_KEYWORD
@@ -8173,6 +8208,7 @@ non_reserved_keyword:
 | COMMENT_KEYWORD
 | COMMIT
 | COMMITTED
+| COMMIT_CUTOVER
 | COMPACT
 | COMPLETE
 | COMPONENT
@@ -8371,6 +8407,7 @@ non_reserved_keyword:
 | PLAN
 | PRECEDING
 | PREPARE
+| PREPARE_CUTOVER
 | PRIVILEGE_CHECKS_USER
 | PRIVILEGES
 | PROCESS
@@ -8556,11 +8593,14 @@ non_reserved_keyword:
 | VINDEXES
 | VISIBLE
 | VITESS
+| VITESS_DML_JOBS
 | VITESS_KEYSPACES
 | VITESS_METADATA
 | VITESS_MIGRATION
 | VITESS_MIGRATIONS
+| VITESS_QUERY_DIGESTS
 | VITESS_REPLICATION_STATUS
+| VITESS_SCHEMA_HISTORY
 | VITESS_SHARDS
 | VITESS_TABLETS
 | VITESS_TARGET
@@ -71,6 +71,8 @@ var (
 	TransactionMode             = SystemVariable{Name: "transaction_mode", IdentifierAsString: true}
 	TransactionReadOnly         = SystemVariable{Name: "transaction_read_only", IsBoolean: true, Default: off}
 	TxReadOnly                  = SystemVariable{Name: "tx_read_only", IsBoolean: true, Default: off}
+	TransactionIsolation        = SystemVariable{Name: "transaction_isolation", Case: SCUpper}
+	TxIsolation                 = SystemVariable{Name: "tx_isolation", Case: SCUpper}
 	Workload                    = SystemVariable{Name: "workload", IdentifierAsString: true}
 	QueryTimeout                = SystemVariable{Name: "query_timeout"}
 
@@ -87,6 +89,13 @@ var (
 	ReadAfterWriteTimeOut = SystemVariable{Name: "read_after_write_timeout"}
 	SessionTrackGTIDs     = SystemVariable{Name: "session_track_gtids", IdentifierAsString: true}
 
+	// ConsistentSnapshot is the vitess specific transaction isolation value which makes
+	// the following transactions read from a cross-shard consistent snapshot.
+	ConsistentSnapshot = "CONSISTENT_SNAPSHOT"
+	// ConsistentSnapshotGTIDs holds the GTID set of each shard at which the current
+	// cross-shard consistent snapshot transaction was started.
+	ConsistentSnapshotGTIDs = SystemVariable{Name: "consistent_snapshot_gtids"}
+
 	VitessAware = []SystemVariable{
 		Autocommit,
 		ClientFoundRows,
@@ -112,6 +121,7 @@ var (
 		Socket,
 		Version,
 		VersionComment,
+		ConsistentSnapshotGTIDs,
 	}
 
 	IgnoreThese = []SystemVariable{
@@ -223,9 +233,9 @@ var (
 		{Name: "sql_warnings", IsBoolean: true},
 		{Name: "time_zone"},
 		{Name: "tmp_table_size", SupportSetVar: true},
-		{Name: "transaction_isolation", Case: SCUpper},
+		TransactionIsolation,
 		{Name: "transaction_prealloc_size"},
-		{Name: "tx_isolation", Case: SCUpper},
+		TxIsolation,
 		{Name: "unique_checks", IsBoolean: true, SupportSetVar: true},
 		{Name: "updatable_views_with_limit", IsBoolean: true, SupportSetVar: true},
 	}
@@ -53,6 +53,10 @@ func DecodeContent(filename string, data []byte, json bool) (string, error) {
 		p = new(topodatapb.SrvKeyspace)
 	case RoutingRulesFile:
 		p = new(vschemapb.RoutingRules)
+	case TenantQuotasFile:
+		p = new(vschemapb.TenantQuotas)
+	case QueryRulesFile:
+		p = new(topodatapb.QueryRules)
 	default:
 		switch dir {
 		case "/" + GetExternalVitessClusterDir():
@@ -0,0 +1,178 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqltopo
+
+import (
+	"context"
+	"path"
+	"time"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/topo"
+)
+
+// NewLeaderParticipation is part of the topo.Server interface
+func (s *Server) NewLeaderParticipation(name, id string) (topo.LeaderParticipation, error) {
+	return &mysqlLeaderParticipation{
+		s:    s,
+		name: name,
+		id:   id,
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}, nil
+}
+
+// mysqlLeaderParticipation implements topo.LeaderParticipation.
+//
+// We use a directory (in global election path, with the name) with
+// ephemeral files in it, that contains the id.  The oldest revision
+// wins the election.
+type mysqlLeaderParticipation struct {
+	// s is our parent MySQL topo Server
+	s *Server
+
+	// name is the name of this LeaderParticipation
+	name string
+
+	// id is the process's current id.
+	id string
+
+	// stop is a channel closed when Stop is called.
+	stop chan struct{}
+
+	// done is a channel closed when we're done processing the Stop
+	done chan struct{}
+}
+
+// WaitForLeadership is part of the topo.LeaderParticipation interface.
+func (mp *mysqlLeaderParticipation) WaitForLeadership() (context.Context, error) {
+	// If Stop was already called, mp.done is closed, so we are interrupted.
+	select {
+	case <-mp.done:
+		return nil, topo.NewError(topo.Interrupted, "Leadership")
+	default:
+	}
+
+	electionPath := path.Join(electionsPath, mp.name)
+	var ld topo.LockDescriptor
+
+	// We use a cancelable context here. If stop is closed,
+	// we just cancel that context.
+	lockCtx, lockCancel := context.WithCancel(context.Background())
+	go func() {
+		select {
+		case <-mp.s.running:
+			return
+		case <-mp.stop:
+		}
+		if ld != nil {
+			if err := ld.Unlock(context.Background()); err != nil {
+				log.Errorf("failed to unlock electionPath %v: %v", electionPath, err)
+			}
+		}
+		lockCancel()
+		close(mp.done)
+	}()
+
+	// Try to get the primaryship, by getting a lock.
+	var err error
+	ld, err = mp.s.lock(lockCtx, electionPath, mp.id)
+	if err != nil {
+		// It can be that we were interrupted.
+		return nil, err
+	}
+
+	// We got the lock. Return the lockContext. If Stop() is called,
+	// it will cancel the lockCtx, and cancel the returned context.
+	return lockCtx, nil
+}
+
+// Stop is part of the topo.LeaderParticipation interface
+func (mp *mysqlLeaderParticipation) Stop() {
+	close(mp.stop)
+	<-mp.done
+}
+
+// GetCurrentLeaderID is part of the topo.LeaderParticipation interface
+func (mp *mysqlLeaderParticipation) GetCurrentLeaderID(ctx context.Context) (string, error) {
+	electionPath := path.Join(mp.s.root, electionsPath, mp.name)
+	leader, _, err := mp.s.currentLeader(ctx, electionPath)
+	return leader, err
+}
+
+// WaitForNewLeader is part of the topo.LeaderParticipation interface
+func (mp *mysqlLeaderParticipation) WaitForNewLeader(ctx context.Context) (<-chan string, error) {
+	electionPath := path.Join(mp.s.root, electionsPath, mp.name)
+
+	// Get the current leader.
+	leader, version, err := mp.s.currentLeader(ctx, electionPath)
+	if err != nil {
+		return nil, err
+	}
+	notifications := make(chan string, 8)
+	if version != 0 {
+		notifications <- leader
+	}
+
+	// Poll for leader changes, and notify the new ones.
+	go func() {
+		defer close(notifications)
+		ticker := time.NewTicker(mysqlPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-mp.s.running:
+				return
+			case <-mp.done:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			newLeader, newVersion, err := mp.s.currentLeader(ctx, electionPath)
+			if err != nil || newVersion == 0 || newVersion == version {
+				continue
+			}
+			version = newVersion
+			notifications <- newLeader
+		}
+	}()
+
+	return notifications, nil
+}
+
+// currentLeader returns the id and version of the oldest file in the
+// election directory. The version is 0 if there is no leader.
+func (s *Server) currentLeader(ctx context.Context, electionPath string) (string, int64, error) {
+	dirPath := path.Join(electionPath, locksPath) + "/"
+	qr, err := s.exec(ctx, "SELECT contents, version FROM topo_data WHERE path >= %a AND path < %a ORDER BY version LIMIT 1",
+		sqltypes.StringBindVariable(dirPath), sqltypes.StringBindVariable(prefixEnd(dirPath)))
+	if err != nil {
+		return "", 0, convertError(err, electionPath)
+	}
+	if len(qr.Rows) == 0 {
+		// No file in the directory, means nobody is the primary.
+		return "", 0, nil
+	}
+	contents, version, err := parseFileRow(qr.Rows[0])
+	if err != nil {
+		return "", 0, err
+	}
+	return string(contents), version, nil
+}
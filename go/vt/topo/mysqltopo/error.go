@@ -0,0 +1,71 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqltopo
+
+import (
+	"context"
+	"errors"
+
+	"vitess.io/vitess/go/mysql/sqlerror"
+	"vitess.io/vitess/go/vt/topo"
+)
+
+// Errors specific to this package.
+var (
+	// ErrBadResponse is returned from this package if the response from the
+	// MySQL server does not contain the data that the queries promise.
+	ErrBadResponse = errors.New("mysql topo query returned success, but response is missing required data")
+)
+
+// convertError converts a context or MySQL error into a topo error.
+// All errors are either application-level errors, or context errors.
+func convertError(err error, nodePath string) error {
+	if err == nil {
+		return nil
+	}
+
+	// Convert specific sentinel values.
+	switch {
+	case errors.Is(err, context.Canceled):
+		return topo.NewError(topo.Interrupted, nodePath)
+	case errors.Is(err, context.DeadlineExceeded):
+		return topo.NewError(topo.Timeout, nodePath)
+	}
+
+	var sqlErr *sqlerror.SQLError
+	if errors.As(err, &sqlErr) {
+		switch sqlErr.Number() {
+		case sqlerror.ERDupEntry:
+			return topo.NewError(topo.NodeExists, nodePath)
+		case sqlerror.ERLockWaitTimeout:
+			return topo.NewError(topo.Timeout, nodePath)
+		case sqlerror.ERTooManyUserConnections, sqlerror.ERConCount:
+			return topo.NewError(topo.ResourceExhausted, nodePath)
+		}
+	}
+	return err
+}
+
+// isConnErr returns true if the error means the connection it was
+// returned on cannot be used anymore.
+func isConnErr(err error) bool {
+	var sqlErr *sqlerror.SQLError
+	if errors.As(err, &sqlErr) {
+		return sqlerror.IsConnErr(sqlErr)
+	}
+	return false
+}
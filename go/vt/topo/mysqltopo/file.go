@@ -0,0 +1,243 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqltopo
+
+import (
+	"context"
+	"path"
+
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/topo"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+// write runs f in a transaction on a pooled connection. It first allocates
+// a new revision for the transaction and passes it to f. The revision row
+// stays locked until the transaction commits, so the writes, and their
+// change log entries, are committed in revision order.
+func (s *Server) write(ctx context.Context, f func(conn *mysql.Conn, revision int64) error) (int64, error) {
+	conn, err := s.getConn(ctx)
+	if err != nil {
+		return 0, err
+	}
+	var revision int64
+	err = func() error {
+		if _, err := conn.ExecuteFetch("begin", 0, false); err != nil {
+			return err
+		}
+		qr, err := conn.ExecuteFetch("UPDATE topo_revision SET revision = LAST_INSERT_ID(revision + 1) WHERE id = 1", 0, false)
+		if err == nil {
+			revision = int64(qr.InsertID)
+			err = f(conn, revision)
+		}
+		if err == nil {
+			_, err = conn.ExecuteFetch("commit", 0, false)
+		}
+		if err != nil && !isConnErr(err) {
+			// Best effort, the connection is closed otherwise.
+			_, _ = conn.ExecuteFetch("rollback", 0, false)
+		}
+		return err
+	}()
+	s.putConn(conn, err)
+	return revision, err
+}
+
+// recordChange adds an entry to the change log for the given revision.
+func recordChange(conn *mysql.Conn, revision int64, nodePath string, contents []byte, deleted bool) error {
+	if deleted {
+		_, err := execBound(conn, "INSERT INTO topo_changelog (revision, path, deleted) VALUES (%a, %a, 1)",
+			sqltypes.Int64BindVariable(revision), sqltypes.StringBindVariable(nodePath))
+		return err
+	}
+	_, err := execBound(conn, "INSERT INTO topo_changelog (revision, path, contents) VALUES (%a, %a, %a)",
+		sqltypes.Int64BindVariable(revision), sqltypes.StringBindVariable(nodePath), sqltypes.BytesBindVariable(contents))
+	return err
+}
+
+// create creates a new file, bound to the lease if leaseID isn't 0.
+func (s *Server) create(ctx context.Context, nodePath string, contents []byte, leaseID int64) (int64, error) {
+	return s.write(ctx, func(conn *mysql.Conn, revision int64) error {
+		if _, err := execBound(conn, "INSERT INTO topo_data (path, contents, version, lease_id) VALUES (%a, %a, %a, %a)",
+			sqltypes.StringBindVariable(nodePath), sqltypes.BytesBindVariable(contents),
+			sqltypes.Int64BindVariable(revision), sqltypes.Int64BindVariable(leaseID)); err != nil {
+			return err
+		}
+		return recordChange(conn, revision, nodePath, contents, false)
+	})
+}
+
+// Create is part of the topo.Conn interface.
+func (s *Server) Create(ctx context.Context, filePath string, contents []byte) (topo.Version, error) {
+	nodePath := path.Join(s.root, filePath)
+
+	revision, err := s.create(ctx, nodePath, contents, 0)
+	if err != nil {
+		return nil, convertError(err, nodePath)
+	}
+	return MySQLVersion(revision), nil
+}
+
+// Update is part of the topo.Conn interface.
+func (s *Server) Update(ctx context.Context, filePath string, contents []byte, version topo.Version) (topo.Version, error) {
+	nodePath := path.Join(s.root, filePath)
+
+	revision, err := s.write(ctx, func(conn *mysql.Conn, revision int64) error {
+		if version != nil {
+			// Only update the file if its current version is
+			// what we expect.
+			qr, err := execBound(conn, "UPDATE topo_data SET contents = %a, version = %a WHERE path = %a AND version = %a",
+				sqltypes.BytesBindVariable(contents), sqltypes.Int64BindVariable(revision),
+				sqltypes.StringBindVariable(nodePath), sqltypes.Int64BindVariable(int64(version.(MySQLVersion))))
+			if err != nil {
+				return err
+			}
+			if qr.RowsAffected == 0 {
+				return topo.NewError(topo.BadVersion, nodePath)
+			}
+		} else {
+			// No version specified, create the file or overwrite it.
+			if _, err := execBound(conn, "INSERT INTO topo_data (path, contents, version) VALUES (%a, %a, %a) ON DUPLICATE KEY UPDATE contents = VALUES(contents), version = VALUES(version)",
+				sqltypes.StringBindVariable(nodePath), sqltypes.BytesBindVariable(contents), sqltypes.Int64BindVariable(revision)); err != nil {
+				return err
+			}
+		}
+		return recordChange(conn, revision, nodePath, contents, false)
+	})
+	if err != nil {
+		return nil, convertError(err, nodePath)
+	}
+	return MySQLVersion(revision), nil
+}
+
+// Get is part of the topo.Conn interface.
+func (s *Server) Get(ctx context.Context, filePath string) ([]byte, topo.Version, error) {
+	nodePath := path.Join(s.root, filePath)
+
+	qr, err := s.exec(ctx, "SELECT contents, version FROM topo_data WHERE path = %a", sqltypes.StringBindVariable(nodePath))
+	if err != nil {
+		return nil, nil, convertError(err, nodePath)
+	}
+	if len(qr.Rows) == 0 {
+		return nil, nil, topo.NewError(topo.NoNode, nodePath)
+	}
+	contents, version, err := parseFileRow(qr.Rows[0])
+	if err != nil {
+		return nil, nil, err
+	}
+	return contents, MySQLVersion(version), nil
+}
+
+// List is part of the topo.Conn interface.
+func (s *Server) List(ctx context.Context, filePathPrefix string) ([]topo.KVInfo, error) {
+	nodePathPrefix := path.Join(s.root, filePathPrefix)
+
+	qr, err := s.exec(ctx, "SELECT path, contents, version FROM topo_data WHERE path >= %a AND path < %a ORDER BY path",
+		sqltypes.StringBindVariable(nodePathPrefix), sqltypes.StringBindVariable(prefixEnd(nodePathPrefix)))
+	if err != nil {
+		return nil, convertError(err, nodePathPrefix)
+	}
+	if len(qr.Rows) == 0 {
+		return nil, topo.NewError(topo.NoNode, nodePathPrefix)
+	}
+	pairs := make([]topo.KVInfo, 0, len(qr.Rows))
+	for _, row := range qr.Rows {
+		key, err := row[0].ToBytes()
+		if err != nil {
+			return nil, err
+		}
+		contents, version, err := parseFileRow(row[1:])
+		if err != nil {
+			return nil, err
+		}
+		pairs = append(pairs, topo.KVInfo{
+			Key:     key,
+			Value:   contents,
+			Version: MySQLVersion(version),
+		})
+	}
+	return pairs, nil
+}
+
+// Delete is part of the topo.Conn interface.
+func (s *Server) Delete(ctx context.Context, filePath string, version topo.Version) error {
+	nodePath := path.Join(s.root, filePath)
+
+	_, err := s.write(ctx, func(conn *mysql.Conn, revision int64) error {
+		query := "DELETE FROM topo_data WHERE path = %a"
+		binds := []*querypb.BindVariable{sqltypes.StringBindVariable(nodePath)}
+		if version != nil {
+			query += " AND version = %a"
+			binds = append(binds, sqltypes.Int64BindVariable(int64(version.(MySQLVersion))))
+		}
+		qr, err := execBound(conn, query, binds...)
+		if err != nil {
+			return err
+		}
+		if qr.RowsAffected == 0 {
+			if version == nil {
+				return topo.NewError(topo.NoNode, nodePath)
+			}
+			// Find out if the file is missing, or has
+			// another version.
+			qr, err := execBound(conn, "SELECT 1 FROM topo_data WHERE path = %a", sqltypes.StringBindVariable(nodePath))
+			if err != nil {
+				return err
+			}
+			if len(qr.Rows) == 0 {
+				return topo.NewError(topo.NoNode, nodePath)
+			}
+			return topo.NewError(topo.BadVersion, nodePath)
+		}
+		return recordChange(conn, revision, nodePath, nil, true)
+	})
+	return convertError(err, nodePath)
+}
+
+// parseFileRow returns the contents and version from a row of
+// (contents, version) values.
+func parseFileRow(row []sqltypes.Value) ([]byte, int64, error) {
+	if len(row) < 2 {
+		return nil, 0, ErrBadResponse
+	}
+	contents, err := row[0].ToBytes()
+	if err != nil {
+		return nil, 0, err
+	}
+	version, err := row[1].ToInt64()
+	if err != nil {
+		return nil, 0, err
+	}
+	return contents, version, nil
+}
+
+// prefixEnd returns the smallest key that is greater than all the keys
+// starting with prefix, to use as the exclusive end of a range scan.
+// The keys are paths, so the last byte can always be incremented.
+func prefixEnd(prefix string) string {
+	end := []byte(prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return string(end[:i+1])
+		}
+	}
+	// Only 0xff bytes, there is no upper bound.
+	return "\xff"
+}
@@ -0,0 +1,295 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqltopo
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/topo"
+)
+
+// newLease creates a new lease that expires after mysqlLeaseTTL,
+// unless it is kept alive.
+func (s *Server) newLease(ctx context.Context) (int64, error) {
+	qr, err := s.exec(ctx, "INSERT INTO topo_leases (expires_at) VALUES (NOW(6) + INTERVAL %a MICROSECOND)",
+		sqltypes.Int64BindVariable(mysqlLeaseTTL.Microseconds()))
+	if err != nil {
+		return 0, err
+	}
+	if qr.InsertID == 0 {
+		return 0, ErrBadResponse
+	}
+	return int64(qr.InsertID), nil
+}
+
+// keepLeaseAlive pushes back the expiration of a lease that has not
+// expired yet. It returns a NoNode error if the lease is gone.
+func (s *Server) keepLeaseAlive(ctx context.Context, leaseID int64) error {
+	qr, err := s.exec(ctx, "UPDATE topo_leases SET expires_at = NOW(6) + INTERVAL %a MICROSECOND WHERE id = %a AND expires_at > NOW(6)",
+		sqltypes.Int64BindVariable(mysqlLeaseTTL.Microseconds()), sqltypes.Int64BindVariable(leaseID))
+	if err != nil {
+		return err
+	}
+	if qr.RowsAffected == 0 {
+		return topo.NewError(topo.NoNode, fmt.Sprintf("lease %v", leaseID))
+	}
+	return nil
+}
+
+// revokeLease deletes a lease and all the files bound to it, recording
+// the deletions in the change log. It returns a NoNode error if the
+// lease is already gone.
+func (s *Server) revokeLease(ctx context.Context, leaseID int64) error {
+	_, err := s.write(ctx, func(conn *mysql.Conn, revision int64) error {
+		qr, err := execBound(conn, "DELETE FROM topo_leases WHERE id = %a", sqltypes.Int64BindVariable(leaseID))
+		if err != nil {
+			return err
+		}
+		if qr.RowsAffected == 0 {
+			return topo.NewError(topo.NoNode, fmt.Sprintf("lease %v", leaseID))
+		}
+		qr, err = execBound(conn, "SELECT path FROM topo_data WHERE lease_id = %a FOR UPDATE", sqltypes.Int64BindVariable(leaseID))
+		if err != nil {
+			return err
+		}
+		for _, row := range qr.Rows {
+			nodePath := row[0].ToString()
+			if _, err := execBound(conn, "DELETE FROM topo_data WHERE path = %a", sqltypes.StringBindVariable(nodePath)); err != nil {
+				return err
+			}
+			if err := recordChange(conn, revision, nodePath, nil, true); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return err
+}
+
+// expireLeases revokes all the leases that were not kept alive in time.
+func (s *Server) expireLeases(ctx context.Context) error {
+	qr, err := s.exec(ctx, "SELECT id FROM topo_leases WHERE expires_at <= NOW(6)")
+	if err != nil {
+		return err
+	}
+	for _, row := range qr.Rows {
+		leaseID, err := row[0].ToInt64()
+		if err != nil {
+			return err
+		}
+		// Another client may have revoked it concurrently.
+		if err := s.revokeLease(ctx, leaseID); err != nil && !topo.IsErrType(err, topo.NoNode) {
+			return err
+		}
+	}
+	return nil
+}
+
+// waitOnLastRev waits on all revisions of the files in the provided
+// directory that have revisions smaller than the provided revision.
+// It returns true only if there is no more other older files.
+func (s *Server) waitOnLastRev(ctx context.Context, nodePath string, revision int64) (bool, error) {
+	// Get the key that is blocking us, if any.
+	dirPath := nodePath + "/"
+	qr, err := s.exec(ctx, "SELECT path FROM topo_data WHERE path >= %a AND path < %a AND version < %a ORDER BY version DESC LIMIT 1",
+		sqltypes.StringBindVariable(dirPath), sqltypes.StringBindVariable(prefixEnd(dirPath)), sqltypes.Int64BindVariable(revision))
+	if err != nil {
+		return false, convertError(err, nodePath)
+	}
+	if len(qr.Rows) == 0 {
+		// No older key, we're done waiting.
+		return true, nil
+	}
+
+	// Poll until the blocking key is deleted.
+	key := qr.Rows[0][0].ToString()
+	ticker := time.NewTicker(mysqlPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return false, convertError(ctx.Err(), nodePath)
+		case <-s.running:
+			return false, topo.NewError(topo.Interrupted, nodePath)
+		case <-ticker.C:
+		}
+		qr, err := s.exec(ctx, "SELECT 1 FROM topo_data WHERE path = %a", sqltypes.StringBindVariable(key))
+		if err != nil {
+			return false, convertError(err, nodePath)
+		}
+		if len(qr.Rows) == 0 {
+			// There might still be older keys,
+			// but not this one.
+			return false, nil
+		}
+	}
+}
+
+// mysqlLockDescriptor implements topo.LockDescriptor.
+type mysqlLockDescriptor struct {
+	s       *Server
+	leaseID int64
+
+	// stop is closed to stop the keep alive of the lease.
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// newLockDescriptor returns a lock descriptor for the lease, and starts
+// keeping the lease alive until the lock is released.
+func (s *Server) newLockDescriptor(leaseID int64) *mysqlLockDescriptor {
+	ld := &mysqlLockDescriptor{
+		s:       s,
+		leaseID: leaseID,
+		stop:    make(chan struct{}),
+	}
+	s.wg.Add(1)
+	go ld.keepAlive()
+	return ld
+}
+
+// keepAlive refreshes the lease a few times per TTL, until the lock is
+// released or the server closed.
+func (ld *mysqlLockDescriptor) keepAlive() {
+	defer ld.s.wg.Done()
+	ticker := time.NewTicker(mysqlLeaseTTL / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ld.stop:
+			return
+		case <-ld.s.running:
+			return
+		case <-ticker.C:
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), topo.RemoteOperationTimeout)
+		err := ld.s.keepLeaseAlive(ctx, ld.leaseID)
+		cancel()
+		if err != nil {
+			log.Warningf("mysqltopo: failed to keep lease %v alive: %v", ld.leaseID, err)
+			if topo.IsErrType(err, topo.NoNode) {
+				return
+			}
+		}
+	}
+}
+
+// TryLock is part of the topo.Conn interface.
+func (s *Server) TryLock(ctx context.Context, dirPath, contents string) (topo.LockDescriptor, error) {
+	// We list all the entries under dirPath
+	entries, err := s.ListDir(ctx, dirPath, true)
+	if err != nil {
+		return nil, convertError(err, dirPath)
+	}
+
+	// If there is a folder '/locks' with some entries in it then we can assume that someone else already has a lock.
+	// Throw error in this case
+	for _, e := range entries {
+		if e.Name == locksPath && e.Type == topo.TypeDirectory && e.Ephemeral {
+			return nil, topo.NewError(topo.NodeExists, fmt.Sprintf("lock already exists at path %s", dirPath))
+		}
+	}
+
+	// everything is good let's acquire the lock.
+	return s.lock(ctx, dirPath, contents)
+}
+
+// Lock is part of the topo.Conn interface.
+func (s *Server) Lock(ctx context.Context, dirPath, contents string) (topo.LockDescriptor, error) {
+	// We list the directory first to make sure it exists.
+	if _, err := s.ListDir(ctx, dirPath, false /*full*/); err != nil {
+		return nil, convertError(err, dirPath)
+	}
+
+	return s.lock(ctx, dirPath, contents)
+}
+
+// lock is used by both Lock() and primary election.
+func (s *Server) lock(ctx context.Context, nodePath, contents string) (topo.LockDescriptor, error) {
+	nodePath = path.Join(s.root, nodePath, locksPath)
+
+	// Get a lease, and keep it alive.
+	leaseID, err := s.newLease(ctx)
+	if err != nil {
+		return nil, convertError(err, nodePath)
+	}
+	ld := s.newLockDescriptor(leaseID)
+
+	// Create an ephemeral node in the locks directory. Use the lease ID
+	// as the file name, so it's guaranteed unique.
+	key := fmt.Sprintf("%v/%v", nodePath, leaseID)
+	revision, err := s.create(ctx, key, []byte(contents), leaseID)
+	if err != nil {
+		ld.release()
+		return nil, convertError(err, key)
+	}
+
+	// Wait until all older nodes in the locks directory are gone.
+	for {
+		done, err := s.waitOnLastRev(ctx, nodePath, revision)
+		if err != nil {
+			// We had an error waiting on the last node.
+			// Revoke our lease, this will delete the file.
+			ld.release()
+			return nil, err
+		}
+		if done {
+			// No more older nodes, we're it!
+			return ld, nil
+		}
+	}
+}
+
+// release revokes the lease in the background, logging any error.
+// It is used when the lock could not be acquired.
+func (ld *mysqlLockDescriptor) release() {
+	ld.stopOnce.Do(func() { close(ld.stop) })
+	ctx, cancel := context.WithTimeout(context.Background(), topo.RemoteOperationTimeout)
+	defer cancel()
+	if err := ld.s.revokeLease(ctx, ld.leaseID); err != nil {
+		log.Warningf("mysqltopo: revokeLease(%d) failed, files may be left behind until it expires: %v", ld.leaseID, err)
+	}
+}
+
+// Check is part of the topo.LockDescriptor interface.
+// We make sure the lease is still there and has not expired.
+func (ld *mysqlLockDescriptor) Check(ctx context.Context) error {
+	qr, err := ld.s.exec(ctx, "SELECT 1 FROM topo_leases WHERE id = %a AND expires_at > NOW(6)", sqltypes.Int64BindVariable(ld.leaseID))
+	if err != nil {
+		return convertError(err, "lease")
+	}
+	if len(qr.Rows) == 0 {
+		return topo.NewError(topo.NoNode, "lease")
+	}
+	return nil
+}
+
+// Unlock is part of the topo.LockDescriptor interface.
+func (ld *mysqlLockDescriptor) Unlock(ctx context.Context) error {
+	ld.stopOnce.Do(func() { close(ld.stop) })
+	if err := ld.s.revokeLease(ctx, ld.leaseID); err != nil {
+		return convertError(err, "lease")
+	}
+	return nil
+}
@@ -0,0 +1,312 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package mysqltopo implements topo.Server with a MySQL database as the backend.
+
+All the cells share a single database, the keys are stored with the cell root
+as their prefix, like the etcd2 implementation does. Every write is assigned a
+new global revision, which is used as the version of the file and is recorded
+in a change log that watches poll. Locks and elections use ephemeral files
+bound to a lease row that its owner keeps alive.
+*/
+package mysqltopo
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/topo"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+const (
+	locksPath     = "locks"
+	electionsPath = "elections"
+)
+
+var (
+	mysqlUser               string
+	mysqlPassword           string
+	mysqlDatabase           = "vt_topo"
+	mysqlPoolSize           = 8
+	mysqlLeaseTTL           = 30 * time.Second
+	mysqlPollInterval       = 500 * time.Millisecond
+	mysqlChangeLogRetention = 10 * time.Minute
+)
+
+func init() {
+	servenv.RegisterFlagsForTopoBinaries(registerServerFlags)
+}
+
+func registerServerFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&mysqlUser, "topo_mysql_user", mysqlUser, "user to use when connecting to the MySQL topo server")
+	fs.StringVar(&mysqlPassword, "topo_mysql_password", mysqlPassword, "password to use when connecting to the MySQL topo server")
+	fs.StringVar(&mysqlDatabase, "topo_mysql_database", mysqlDatabase, "database holding the topo tables on the MySQL topo server, created if missing")
+	fs.IntVar(&mysqlPoolSize, "topo_mysql_pool_size", mysqlPoolSize, "maximum number of connections to open to the MySQL topo server")
+	fs.DurationVar(&mysqlLeaseTTL, "topo_mysql_lease_ttl", mysqlLeaseTTL, "Lease TTL for locks and leader election. The client keeps the lease alive while it holds it.")
+	fs.DurationVar(&mysqlPollInterval, "topo_mysql_poll_interval", mysqlPollInterval, "how often watches, locks and elections poll the MySQL topo server for changes")
+	fs.DurationVar(&mysqlChangeLogRetention, "topo_mysql_changelog_retention", mysqlChangeLogRetention, "how long to keep entries of the MySQL topo change log that watches poll")
+}
+
+// schema is the set of statements run when connecting to the server, to
+// create the topo tables if they don't exist yet.
+var schema = []string{
+	`CREATE TABLE IF NOT EXISTS topo_revision (
+  id tinyint unsigned NOT NULL,
+  revision bigint unsigned NOT NULL,
+  PRIMARY KEY (id)
+) ENGINE=InnoDB`,
+	`INSERT IGNORE INTO topo_revision (id, revision) VALUES (1, 0)`,
+	`CREATE TABLE IF NOT EXISTS topo_data (
+  path varbinary(768) NOT NULL,
+  contents longblob NOT NULL,
+  version bigint unsigned NOT NULL,
+  lease_id bigint unsigned NOT NULL DEFAULT 0,
+  PRIMARY KEY (path),
+  KEY lease_id_idx (lease_id)
+) ENGINE=InnoDB`,
+	`CREATE TABLE IF NOT EXISTS topo_changelog (
+  revision bigint unsigned NOT NULL,
+  path varbinary(768) NOT NULL,
+  contents longblob,
+  deleted tinyint unsigned NOT NULL DEFAULT 0,
+  created_at timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+  PRIMARY KEY (revision, path),
+  KEY path_revision_idx (path, revision),
+  KEY created_at_idx (created_at)
+) ENGINE=InnoDB`,
+	`CREATE TABLE IF NOT EXISTS topo_leases (
+  id bigint unsigned NOT NULL AUTO_INCREMENT,
+  expires_at timestamp(6) NOT NULL,
+  PRIMARY KEY (id),
+  KEY expires_at_idx (expires_at)
+) ENGINE=InnoDB`,
+}
+
+// Factory is the MySQL topo.Factory implementation.
+type Factory struct{}
+
+// HasGlobalReadOnlyCell is part of the topo.Factory interface.
+func (f Factory) HasGlobalReadOnlyCell(serverAddr, root string) bool {
+	return false
+}
+
+// Create is part of the topo.Factory interface.
+func (f Factory) Create(cell, serverAddr, root string) (topo.Conn, error) {
+	return NewServer(serverAddr, root)
+}
+
+// Server is the implementation of topo.Server for MySQL.
+type Server struct {
+	// params are the connection parameters to the MySQL server.
+	params *mysql.ConnParams
+
+	// root is the root path for this client.
+	root string
+
+	// conns is the connection pool. It holds mysqlPoolSize slots,
+	// which are either an open connection or nil.
+	conns chan *mysql.Conn
+
+	// running is closed when Close() is called.
+	running chan struct{}
+
+	// closeOnce protects running from being closed twice.
+	closeOnce sync.Once
+
+	// wg tracks the background goroutines.
+	wg sync.WaitGroup
+}
+
+// NewServer returns a new mysqltopo.Server. serverAddr is either the
+// host:port of the MySQL server, or the path to its unix socket.
+func NewServer(serverAddr, root string) (*Server, error) {
+	params := &mysql.ConnParams{
+		Uname: mysqlUser,
+		Pass:  mysqlPassword,
+	}
+	if strings.HasPrefix(serverAddr, "/") {
+		params.UnixSocket = serverAddr
+	} else {
+		host, portStr, err := net.SplitHostPort(serverAddr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid MySQL topo server address %q: %v", serverAddr, err)
+		}
+		port, err := strconv.Atoi(portStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid MySQL topo server port %q: %v", portStr, err)
+		}
+		params.Host = host
+		params.Port = port
+	}
+	s := &Server{
+		params:  params,
+		root:    root,
+		conns:   make(chan *mysql.Conn, mysqlPoolSize),
+		running: make(chan struct{}),
+	}
+	for i := 0; i < mysqlPoolSize; i++ {
+		s.conns <- nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), topo.RemoteOperationTimeout)
+	defer cancel()
+	if err := s.initSchema(ctx); err != nil {
+		s.Close()
+		return nil, err
+	}
+
+	s.wg.Add(1)
+	go s.purgeLoop()
+	return s, nil
+}
+
+// initSchema creates the topo database and tables if needed, and then
+// makes all the pooled connections use that database.
+func (s *Server) initSchema(ctx context.Context) error {
+	conn, err := mysql.Connect(ctx, s.params)
+	if err != nil {
+		return convertError(err, s.root)
+	}
+	defer conn.Close()
+	if _, err := conn.ExecuteFetch(fmt.Sprintf("CREATE DATABASE IF NOT EXISTS %s", sqlparser.String(sqlparser.NewIdentifierCS(mysqlDatabase))), 0, false); err != nil {
+		return convertError(err, s.root)
+	}
+	if _, err := conn.ExecuteFetch(fmt.Sprintf("USE %s", sqlparser.String(sqlparser.NewIdentifierCS(mysqlDatabase))), 0, false); err != nil {
+		return convertError(err, s.root)
+	}
+	for _, query := range schema {
+		if _, err := conn.ExecuteFetch(query, 0, false); err != nil {
+			return convertError(err, s.root)
+		}
+	}
+	s.params.DbName = mysqlDatabase
+	return nil
+}
+
+// getConn returns a connection from the pool, opening a new one if needed.
+// The connection must be returned with putConn.
+func (s *Server) getConn(ctx context.Context) (*mysql.Conn, error) {
+	var conn *mysql.Conn
+	select {
+	case conn = <-s.conns:
+	case <-s.running:
+		return nil, topo.NewError(topo.Interrupted, "server closed")
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if conn != nil && !conn.IsClosed() {
+		return conn, nil
+	}
+	conn, err := mysql.Connect(ctx, s.params)
+	if err != nil {
+		s.conns <- nil
+		return nil, err
+	}
+	return conn, nil
+}
+
+// putConn returns a connection to the pool. The connection is closed
+// instead if the last query ran on it returned a connection error.
+func (s *Server) putConn(conn *mysql.Conn, err error) {
+	if err != nil && isConnErr(err) {
+		conn.Close()
+		conn = nil
+	}
+	s.conns <- conn
+}
+
+// exec runs a single query on a pooled connection, after binding the
+// provided variables to its %a placeholders.
+func (s *Server) exec(ctx context.Context, query string, binds ...*querypb.BindVariable) (*sqltypes.Result, error) {
+	conn, err := s.getConn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	qr, err := execBound(conn, query, binds...)
+	s.putConn(conn, err)
+	return qr, err
+}
+
+// execBound binds the variables to the %a placeholders of the query and
+// runs it on the provided connection.
+func execBound(conn *mysql.Conn, query string, binds ...*querypb.BindVariable) (*sqltypes.Result, error) {
+	if len(binds) > 0 {
+		bound, err := sqlparser.ParseAndBind(query, binds...)
+		if err != nil {
+			return nil, err
+		}
+		query = bound
+	}
+	return conn.ExecuteFetch(query, math.MaxInt32, false)
+}
+
+// purgeLoop periodically removes the expired leases, with the ephemeral files
+// bound to them, and the change log entries older than the retention period.
+func (s *Server) purgeLoop() {
+	defer s.wg.Done()
+	ticker := time.NewTicker(mysqlPollInterval * 10)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.running:
+			return
+		case <-ticker.C:
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), topo.RemoteOperationTimeout)
+		if err := s.expireLeases(ctx); err != nil {
+			log.Warningf("mysqltopo: failed to expire leases: %v", err)
+		}
+		if _, err := s.exec(ctx, "DELETE FROM topo_changelog WHERE created_at < NOW(6) - INTERVAL %a MICROSECOND",
+			sqltypes.Int64BindVariable(mysqlChangeLogRetention.Microseconds())); err != nil {
+			log.Warningf("mysqltopo: failed to purge the change log: %v", err)
+		}
+		cancel()
+	}
+}
+
+// Close implements topo.Server.Close.
+// It closes all the connections, any attempt to re-use this server will fail.
+func (s *Server) Close() {
+	s.closeOnce.Do(func() {
+		close(s.running)
+	})
+	s.wg.Wait()
+	for i := 0; i < cap(s.conns); i++ {
+		if conn := <-s.conns; conn != nil {
+			conn.Close()
+		}
+	}
+}
+
+func init() {
+	topo.RegisterFactory("mysql", Factory{})
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqltopo
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"testing"
+	"time"
+
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/test"
+	"vitess.io/vitess/go/vt/vttest"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vttestpb "vitess.io/vitess/go/vt/proto/vttest"
+)
+
+// startMySQL starts a mysqld, and returns the address of its unix socket.
+func startMySQL(t *testing.T) string {
+	cluster := &vttest.LocalCluster{
+		Config: vttest.Config{
+			Topology: &vttestpb.VTTestTopology{
+				Keyspaces: []*vttestpb.Keyspace{
+					{
+						Name: "vttest",
+						Shards: []*vttestpb.Shard{
+							{
+								Name:           "0",
+								DbNameOverride: "vttest",
+							},
+						},
+					},
+				},
+			},
+			OnlyMySQL: true,
+		},
+	}
+	if err := cluster.Setup(); err != nil {
+		t.Fatalf("could not launch mysql: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := cluster.TearDown(); err != nil {
+			t.Errorf("cluster.TearDown() failed: %v", err)
+		}
+	})
+
+	params := cluster.MySQLConnParams()
+	mysqlUser = params.Uname
+	mysqlPassword = params.Pass
+	return params.UnixSocket
+}
+
+func TestMySQLTopo(t *testing.T) {
+	// Start a single mysqld in the background.
+	serverAddr := startMySQL(t)
+
+	// Poll faster than the default, to keep the test duration down.
+	mysqlPollInterval = 50 * time.Millisecond
+
+	testIndex := 0
+	newServer := func() *topo.Server {
+		// Each test will use its own sub-directories.
+		testRoot := fmt.Sprintf("/test-%v", testIndex)
+		testIndex++
+
+		// Create the server on the new root.
+		ts, err := topo.OpenServer("mysql", serverAddr, path.Join(testRoot, topo.GlobalCell))
+		if err != nil {
+			t.Fatalf("OpenServer() failed: %v", err)
+		}
+
+		// Create the CellInfo.
+		if err := ts.CreateCellInfo(context.Background(), test.LocalCellName, &topodatapb.CellInfo{
+			ServerAddress: serverAddr,
+			Root:          path.Join(testRoot, test.LocalCellName),
+		}); err != nil {
+			t.Fatalf("CreateCellInfo() failed: %v", err)
+		}
+
+		return ts
+	}
+
+	// Run the TopoServerTestSuite tests.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	test.TopoServerTestSuite(t, ctx, func() *topo.Server {
+		return newServer()
+	}, []string{})
+
+	// Run mysql-specific tests.
+	testLeaseExpiration(t, serverAddr)
+}
+
+// testLeaseExpiration makes sure the ephemeral files of a lease that
+// is not kept alive are deleted, and watches are notified.
+func testLeaseExpiration(t *testing.T, serverAddr string) {
+	ctx := context.Background()
+	s, err := NewServer(serverAddr, "/test-lease")
+	if err != nil {
+		t.Fatalf("NewServer() failed: %v", err)
+	}
+	defer s.Close()
+
+	// Create a lease, with a short TTL, and never keep it alive.
+	oldTTL := mysqlLeaseTTL
+	mysqlLeaseTTL = 100 * time.Millisecond
+	defer func() { mysqlLeaseTTL = oldTTL }()
+	leaseID, err := s.newLease(ctx)
+	if err != nil {
+		t.Fatalf("newLease() failed: %v", err)
+	}
+	if _, err := s.create(ctx, path.Join(s.root, "ephemeral"), []byte("contents"), leaseID); err != nil {
+		t.Fatalf("create() failed: %v", err)
+	}
+	_, changes, err := s.Watch(ctx, "ephemeral")
+	if err != nil {
+		t.Fatalf("Watch() failed: %v", err)
+	}
+
+	time.Sleep(2 * mysqlLeaseTTL)
+	if err := s.expireLeases(ctx); err != nil {
+		t.Fatalf("expireLeases() failed: %v", err)
+	}
+	if _, _, err := s.Get(ctx, "ephemeral"); !topo.IsErrType(err, topo.NoNode) {
+		t.Fatalf("Get() after the lease expired returned %v, expected NoNode", err)
+	}
+	if err := s.keepLeaseAlive(ctx, leaseID); !topo.IsErrType(err, topo.NoNode) {
+		t.Fatalf("keepLeaseAlive() after the lease expired returned %v, expected NoNode", err)
+	}
+
+	wd, ok := <-changes
+	if !ok || !topo.IsErrType(wd.Err, topo.NoNode) {
+		t.Fatalf("unexpected watch notification after the lease expired: %v", wd)
+	}
+}
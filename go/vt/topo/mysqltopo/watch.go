@@ -0,0 +1,231 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqltopo
+
+import (
+	"context"
+	"path"
+	"strings"
+	"time"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/topo"
+)
+
+// change is an entry of the change log.
+type change struct {
+	revision int64
+	path     string
+	contents []byte
+	deleted  bool
+}
+
+// changesSince returns the changes to nodePath, or to all the paths
+// starting with it if recursive is set, committed after revision.
+// They are returned in revision order.
+func (s *Server) changesSince(ctx context.Context, nodePath string, recursive bool, revision int64) ([]change, error) {
+	var qr *sqltypes.Result
+	var err error
+	if recursive {
+		qr, err = s.exec(ctx, "SELECT revision, path, contents, deleted FROM topo_changelog WHERE revision > %a AND path >= %a AND path < %a ORDER BY revision, path",
+			sqltypes.Int64BindVariable(revision), sqltypes.StringBindVariable(nodePath), sqltypes.StringBindVariable(prefixEnd(nodePath)))
+	} else {
+		qr, err = s.exec(ctx, "SELECT revision, path, contents, deleted FROM topo_changelog WHERE path = %a AND revision > %a ORDER BY revision",
+			sqltypes.StringBindVariable(nodePath), sqltypes.Int64BindVariable(revision))
+	}
+	if err != nil {
+		return nil, err
+	}
+	changes := make([]change, 0, len(qr.Rows))
+	for _, row := range qr.Rows {
+		if len(row) < 4 {
+			return nil, ErrBadResponse
+		}
+		c := change{path: row[1].ToString()}
+		if c.revision, err = row[0].ToInt64(); err != nil {
+			return nil, err
+		}
+		if c.contents, err = row[2].ToBytes(); err != nil {
+			return nil, err
+		}
+		deleted, err := row[3].ToInt64()
+		if err != nil {
+			return nil, err
+		}
+		c.deleted = deleted != 0
+		changes = append(changes, c)
+	}
+	return changes, nil
+}
+
+// Watch is part of the topo.Conn interface.
+func (s *Server) Watch(ctx context.Context, filePath string) (*topo.WatchData, <-chan *topo.WatchData, error) {
+	nodePath := path.Join(s.root, filePath)
+
+	// Get the initial version of the file. Its version is the
+	// revision of its last change, so we poll for the later ones.
+	initialCtx, initialCancel := context.WithTimeout(ctx, topo.RemoteOperationTimeout)
+	defer initialCancel()
+	contents, version, err := s.Get(initialCtx, filePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	wd := &topo.WatchData{
+		Contents: contents,
+		Version:  version,
+	}
+
+	// Create the notifications channel, send updates to it.
+	notifications := make(chan *topo.WatchData, 10)
+	go func() {
+		defer close(notifications)
+
+		currVersion := int64(version.(MySQLVersion))
+		ticker := time.NewTicker(mysqlPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.running:
+				return
+			case <-ctx.Done():
+				// This includes context cancellation errors.
+				notifications <- &topo.WatchData{
+					Err: convertError(ctx.Err(), nodePath),
+				}
+				return
+			case <-ticker.C:
+			}
+
+			changes, err := s.changesSince(ctx, nodePath, false, currVersion)
+			if err != nil {
+				// We will try again on the next tick.
+				log.Warningf("watch %v failed to poll for changes, currVersion: %v: %v", nodePath, currVersion, err)
+				continue
+			}
+			for _, c := range changes {
+				currVersion = c.revision
+				if c.deleted {
+					// Node is gone, send a final notice.
+					notifications <- &topo.WatchData{
+						Err: topo.NewError(topo.NoNode, nodePath),
+					}
+					return
+				}
+				notifications <- &topo.WatchData{
+					Contents: c.contents,
+					Version:  MySQLVersion(c.revision),
+				}
+			}
+		}
+	}()
+
+	return wd, notifications, nil
+}
+
+// WatchRecursive is part of the topo.Conn interface.
+func (s *Server) WatchRecursive(ctx context.Context, dirpath string) ([]*topo.WatchDataRecursive, <-chan *topo.WatchDataRecursive, error) {
+	nodePath := path.Join(s.root, dirpath)
+	if !strings.HasSuffix(nodePath, "/") {
+		nodePath = nodePath + "/"
+	}
+
+	// Get the current revision first, so we don't miss any change
+	// made while listing the files. Some may be sent twice.
+	qr, err := s.exec(ctx, "SELECT revision FROM topo_revision WHERE id = 1")
+	if err != nil {
+		return nil, nil, convertError(err, nodePath)
+	}
+	if len(qr.Rows) != 1 {
+		return nil, nil, ErrBadResponse
+	}
+	revision, err := qr.Rows[0][0].ToInt64()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Get the initial version of the files.
+	qr, err = s.exec(ctx, "SELECT path, contents, version FROM topo_data WHERE path >= %a AND path < %a ORDER BY path",
+		sqltypes.StringBindVariable(nodePath), sqltypes.StringBindVariable(prefixEnd(nodePath)))
+	if err != nil {
+		return nil, nil, convertError(err, nodePath)
+	}
+
+	var initialwd []*topo.WatchDataRecursive
+	for _, row := range qr.Rows {
+		contents, version, err := parseFileRow(row[1:])
+		if err != nil {
+			return nil, nil, err
+		}
+		var wd topo.WatchDataRecursive
+		wd.Path = row[0].ToString()
+		wd.Contents = contents
+		wd.Version = MySQLVersion(version)
+		initialwd = append(initialwd, &wd)
+	}
+
+	// Create the notifications channel, send updates to it.
+	notifications := make(chan *topo.WatchDataRecursive, 10)
+	go func() {
+		defer close(notifications)
+
+		currVersion := revision
+		ticker := time.NewTicker(mysqlPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.running:
+				return
+			case <-ctx.Done():
+				// This includes context cancellation errors.
+				notifications <- &topo.WatchDataRecursive{
+					WatchData: topo.WatchData{Err: convertError(ctx.Err(), nodePath)},
+				}
+				return
+			case <-ticker.C:
+			}
+
+			changes, err := s.changesSince(ctx, nodePath, true, currVersion)
+			if err != nil {
+				// We will try again on the next tick.
+				log.Warningf("watch %v failed to poll for changes, currVersion: %v: %v", nodePath, currVersion, err)
+				continue
+			}
+			for _, c := range changes {
+				currVersion = c.revision
+				if c.deleted {
+					notifications <- &topo.WatchDataRecursive{
+						Path: c.path,
+						WatchData: topo.WatchData{
+							Err: topo.NewError(topo.NoNode, nodePath),
+						},
+					}
+					continue
+				}
+				notifications <- &topo.WatchDataRecursive{
+					Path: c.path,
+					WatchData: topo.WatchData{
+						Contents: c.contents,
+						Version:  MySQLVersion(c.revision),
+					},
+				}
+			}
+		}
+	}()
+
+	return initialwd, notifications, nil
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topo
+
+import (
+	"context"
+
+	"vitess.io/vitess/go/vt/vterrors"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// This file contains the utility methods to manage the QueryRules object.
+
+// WatchQueryRulesData is returned / streamed by WatchQueryRules.
+// The WatchQueryRules API guarantees exactly one of Value or Err will be set.
+type WatchQueryRulesData struct {
+	Value *topodatapb.QueryRules
+	Err   error
+}
+
+// GetQueryRules returns the query rules of the tablets from the global topo.
+func (ts *Server) GetQueryRules(ctx context.Context) (*topodatapb.QueryRules, error) {
+	queryRules, _, err := ts.getQueryRules(ctx)
+	return queryRules, err
+}
+
+func (ts *Server) getQueryRules(ctx context.Context) (*topodatapb.QueryRules, Version, error) {
+	queryRules := &topodatapb.QueryRules{}
+	data, version, err := ts.globalCell.Get(ctx, QueryRulesFile)
+	if err != nil {
+		if IsErrType(err, NoNode) {
+			return queryRules, nil, nil
+		}
+		return nil, nil, err
+	}
+	if err := queryRules.UnmarshalVT(data); err != nil {
+		return nil, nil, vterrors.Wrapf(err, "invalid query rules: %q", data)
+	}
+	return queryRules, version, nil
+}
+
+// UpdateQueryRules reads the query rules of the tablets, calls the update
+// function on them, and writes them back. If they were changed in between, it
+// starts over. If the update function returns NoUpdateNeeded, nothing is
+// written and the current query rules are returned.
+func (ts *Server) UpdateQueryRules(ctx context.Context, update func(*topodatapb.QueryRules) error) (*topodatapb.QueryRules, error) {
+	for {
+		queryRules, version, err := ts.getQueryRules(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if err := update(queryRules); err != nil {
+			if IsErrType(err, NoUpdateNeeded) {
+				return queryRules, nil
+			}
+			return nil, err
+		}
+
+		data, err := queryRules.MarshalVT()
+		if err != nil {
+			return nil, err
+		}
+		// The file is kept even without rules, so that the tablets can keep
+		// watching it.
+		if version == nil {
+			_, err = ts.globalCell.Create(ctx, QueryRulesFile, data)
+		} else {
+			_, err = ts.globalCell.Update(ctx, QueryRulesFile, data, version)
+		}
+		if !IsErrType(err, BadVersion) && !IsErrType(err, NodeExists) {
+			return queryRules, err
+		}
+	}
+}
+
+// WatchQueryRules will set a watch on the QueryRules object.
+// It has the same contract as Conn.Watch, but it also unpacks the
+// contents into a QueryRules object.
+func (ts *Server) WatchQueryRules(ctx context.Context) (*WatchQueryRulesData, <-chan *WatchQueryRulesData, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	current, wdChannel, err := ts.globalCell.Watch(ctx, QueryRulesFile)
+	if err != nil {
+		cancel()
+		return nil, nil, err
+	}
+	value := &topodatapb.QueryRules{}
+	if err := value.UnmarshalVT(current.Contents); err != nil {
+		// Cancel the watch, drain channel.
+		cancel()
+		for range wdChannel {
+		}
+		return nil, nil, vterrors.Wrapf(err, "error unpacking initial QueryRules object")
+	}
+
+	changes := make(chan *WatchQueryRulesData, 10)
+
+	// The background routine reads any event from the watch channel,
+	// translates it, and sends it to the caller.
+	// If cancel() is called, the underlying Watch() code will
+	// send an ErrInterrupted and then close the channel. We'll
+	// just propagate that back to our caller.
+	go func() {
+		defer cancel()
+		defer close(changes)
+
+		for wd := range wdChannel {
+			if wd.Err != nil {
+				// Last error value, we're done.
+				// wdChannel will be closed right after
+				// this, no need to do anything.
+				changes <- &WatchQueryRulesData{Err: wd.Err}
+				return
+			}
+
+			value := &topodatapb.QueryRules{}
+			if err := value.UnmarshalVT(wd.Contents); err != nil {
+				cancel()
+				for range wdChannel {
+				}
+				changes <- &WatchQueryRulesData{Err: vterrors.Wrapf(err, "error unpacking QueryRules object")}
+				return
+			}
+			changes <- &WatchQueryRulesData{Value: value}
+		}
+	}()
+
+	return &WatchQueryRulesData{Value: value}, changes, nil
+}
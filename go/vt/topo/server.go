@@ -79,6 +79,8 @@ const (
 	RoutingRulesFile      = "RoutingRules"
 	ExternalClustersFile  = "ExternalClusters"
 	ShardRoutingRulesFile = "ShardRoutingRules"
+	TenantQuotasFile      = "TenantQuotas"
+	QueryRulesFile        = "QueryRules"
 )
 
 // Path for all object types.
@@ -204,6 +204,12 @@ func (ts *Server) RebuildSrvVSchema(ctx context.Context, cells []string) error {
 	}
 	srvVSchema.ShardRoutingRules = srr
 
+	tq, err := ts.GetTenantQuotas(ctx)
+	if err != nil {
+		return fmt.Errorf("GetTenantQuotas failed: %v", err)
+	}
+	srvVSchema.TenantQuotas = tq
+
 	// now save the SrvVSchema in all cells in parallel
 	for _, cell := range cells {
 		wg.Add(1)
@@ -0,0 +1,74 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topotests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/test/utils"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func TestQueryRules(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "cell1")
+	defer ts.Close()
+
+	// Without query rules, the tablets cannot watch them yet.
+	_, _, err := ts.WatchQueryRules(ctx)
+	assert.True(t, topo.IsErrType(err, topo.NoNode), "unexpected error: %v", err)
+
+	queryRules, err := ts.GetQueryRules(ctx)
+	require.NoError(t, err)
+	utils.MustMatch(t, &topodatapb.QueryRules{}, queryRules)
+
+	rule1 := &topodatapb.QueryRule{Name: "r1", Rule: `{"Name":"r1"}`, Keyspace: "ks1"}
+	queryRules, err = ts.UpdateQueryRules(ctx, func(queryRules *topodatapb.QueryRules) error {
+		queryRules.Rules = append(queryRules.Rules, rule1)
+		return nil
+	})
+	require.NoError(t, err)
+	utils.MustMatch(t, &topodatapb.QueryRules{Rules: []*topodatapb.QueryRule{rule1}}, queryRules)
+
+	current, changes, err := ts.WatchQueryRules(ctx)
+	require.NoError(t, err)
+	utils.MustMatch(t, queryRules, current.Value)
+
+	// The file is kept once the last rule is deleted.
+	_, err = ts.UpdateQueryRules(ctx, func(queryRules *topodatapb.QueryRules) error {
+		queryRules.Rules = nil
+		return nil
+	})
+	require.NoError(t, err)
+	change := <-changes
+	require.NoError(t, change.Err)
+	utils.MustMatch(t, &topodatapb.QueryRules{}, change.Value)
+
+	queryRules, err = ts.UpdateQueryRules(ctx, func(queryRules *topodatapb.QueryRules) error {
+		return topo.NewError(topo.NoUpdateNeeded, topo.QueryRulesFile)
+	})
+	require.NoError(t, err)
+	utils.MustMatch(t, &topodatapb.QueryRules{}, queryRules)
+}
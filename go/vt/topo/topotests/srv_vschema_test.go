@@ -31,6 +31,7 @@ func TestRebuildVSchema(t *testing.T) {
 	emptySrvVSchema := &vschemapb.SrvVSchema{
 		RoutingRules:      &vschemapb.RoutingRules{},
 		ShardRoutingRules: &vschemapb.ShardRoutingRules{},
+		TenantQuotas:      &vschemapb.TenantQuotas{},
 	}
 
 	// Set up topology.
@@ -54,6 +55,7 @@ func TestRebuildVSchema(t *testing.T) {
 	emptyKs1SrvVSchema := &vschemapb.SrvVSchema{
 		RoutingRules:      &vschemapb.RoutingRules{},
 		ShardRoutingRules: &vschemapb.ShardRoutingRules{},
+		TenantQuotas:      &vschemapb.TenantQuotas{},
 		Keyspaces: map[string]*vschemapb.Keyspace{
 			"ks1": {},
 		},
@@ -83,6 +85,7 @@ func TestRebuildVSchema(t *testing.T) {
 	wanted1 := &vschemapb.SrvVSchema{
 		RoutingRules:      &vschemapb.RoutingRules{},
 		ShardRoutingRules: &vschemapb.ShardRoutingRules{},
+		TenantQuotas:      &vschemapb.TenantQuotas{},
 		Keyspaces: map[string]*vschemapb.Keyspace{
 			"ks1": keyspace1,
 		},
@@ -124,6 +127,7 @@ func TestRebuildVSchema(t *testing.T) {
 	wanted2 := &vschemapb.SrvVSchema{
 		RoutingRules:      &vschemapb.RoutingRules{},
 		ShardRoutingRules: &vschemapb.ShardRoutingRules{},
+		TenantQuotas:      &vschemapb.TenantQuotas{},
 		Keyspaces: map[string]*vschemapb.Keyspace{
 			"ks1": keyspace1,
 			"ks2": keyspace2,
@@ -162,6 +166,7 @@ func TestRebuildVSchema(t *testing.T) {
 	wanted3 := &vschemapb.SrvVSchema{
 		RoutingRules:      rr,
 		ShardRoutingRules: &vschemapb.ShardRoutingRules{},
+		TenantQuotas:      &vschemapb.TenantQuotas{},
 		Keyspaces: map[string]*vschemapb.Keyspace{
 			"ks1": keyspace1,
 			"ks2": keyspace2,
@@ -158,3 +158,38 @@ func (ts *Server) GetShardRoutingRules(ctx context.Context) (*vschemapb.ShardRou
 	}
 	return srr, nil
 }
+
+// SaveTenantQuotas saves the per-tenant resource quotas into the topo.
+func (ts *Server) SaveTenantQuotas(ctx context.Context, tenantQuotas *vschemapb.TenantQuotas) error {
+	data, err := tenantQuotas.MarshalVT()
+	if err != nil {
+		return err
+	}
+
+	if len(data) == 0 {
+		if err := ts.globalCell.Delete(ctx, TenantQuotasFile, nil); err != nil && !IsErrType(err, NoNode) {
+			return err
+		}
+		return nil
+	}
+
+	_, err = ts.globalCell.Update(ctx, TenantQuotasFile, data, nil)
+	return err
+}
+
+// GetTenantQuotas fetches the per-tenant resource quotas from the topo.
+func (ts *Server) GetTenantQuotas(ctx context.Context) (*vschemapb.TenantQuotas, error) {
+	tq := &vschemapb.TenantQuotas{}
+	data, _, err := ts.globalCell.Get(ctx, TenantQuotasFile)
+	if err != nil {
+		if IsErrType(err, NoNode) {
+			return tq, nil
+		}
+		return nil, err
+	}
+	err = tq.UnmarshalVT(data)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "invalid tenant quotas: %q", data)
+	}
+	return tq, nil
+}
@@ -78,6 +78,24 @@ contains more details on the VSchema:
 https://github.com/vitessio/vitess/blob/main/doc/V3VindexDesign.md
 
 
+Multiple vtgates
+
+Instead of a single Address, a Configuration can list several vtgates in
+Addresses, or provide a vtgateconn.Resolver to discover them:
+
+	db, err := vitessdriver.OpenWithConfiguration(vitessdriver.Configuration{
+		Addresses: []string{"vtgate1:15991", "vtgate2:15991"},
+		Target:    "@primary",
+	})
+
+The vtgates are health checked, and each new connection is pinned to a healthy
+vtgate, in round-robin order. If that vtgate becomes unavailable, the connection
+moves to another one, keeping its session, including any open transaction.
+Read-only queries that fail because their vtgate was unavailable are retried on
+another vtgate. Other queries return the error, since they may have been
+executed.
+
+
 Isolation levels
 
 The Vitess isolation model is different from the one exposed by a traditional database.
@@ -86,12 +104,14 @@ You can perform primary, replica or rdonly reads. Primary reads give you read-af
 consistency. Replica and rdonly reads give you eventual consistency. Replica reads
 are for satisfying OLTP workloads while rdonly is for OLAP.
 
-All transactions must be sent to the primary where writes are allowed.
-Replica and rdonly reads can only be performed outside of a transaction. So, there is
-no concept of a read-only transaction in Vitess.
+Transactions that write must be sent to the primary where writes are allowed.
+Read-only transactions can be requested with sql.TxOptions.ReadOnly. They are
+started with START TRANSACTION READ ONLY, and if Configuration.ReadOnlyTarget is
+set (e.g. to "@replica"), they are sent to that target instead of Target.
 
-Consequently, no IsolationLevel must be specified while calling BeginContext. Doing so
-will result in an error.
+The MySQL isolation levels (read uncommitted, read committed, repeatable read and
+serializable) can be requested with sql.TxOptions.Isolation, and apply to the
+transaction on every shard it touches. Other isolation levels result in an error.
 
 
 Named arguments
@@ -24,6 +24,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"sync"
+	"time"
 
 	"google.golang.org/grpc"
 
@@ -36,13 +39,24 @@ import (
 
 var (
 	errNoIntermixing        = errors.New("named and positional arguments intermixing disallowed")
-	errIsolationUnsupported = errors.New("isolation levels are not supported")
+	errIsolationUnsupported = errors.New("isolation level is not supported")
 )
 
+// txIsolations maps the database/sql isolation levels supported by Vitess to
+// the isolation levels of the vttablet transactions.
+var txIsolations = map[sql.IsolationLevel]querypb.ExecuteOptions_TransactionIsolation{
+	sql.LevelDefault:         querypb.ExecuteOptions_DEFAULT,
+	sql.LevelReadUncommitted: querypb.ExecuteOptions_READ_UNCOMMITTED,
+	sql.LevelReadCommitted:   querypb.ExecuteOptions_READ_COMMITTED,
+	sql.LevelRepeatableRead:  querypb.ExecuteOptions_REPEATABLE_READ,
+	sql.LevelSerializable:    querypb.ExecuteOptions_SERIALIZABLE,
+}
+
 // Type-check interfaces.
 var (
 	_ interface {
 		driver.Connector
+		io.Closer
 	} = &connector{}
 
 	_ interface {
@@ -111,6 +125,17 @@ func OpenWithConfiguration(c Configuration) (*sql.DB, error) {
 		vtgateconn.RegisterDialer(c.Protocol, grpcvtgateconn.Dial(c.GRPCDialOptions...))
 	}
 
+	if c.Resolver != nil {
+		// The resolver cannot be passed through the JSON string, so build the
+		// connector directly.
+		connector, err := drv{}.newConnector(c)
+		if err != nil {
+			return nil, err
+		}
+
+		return sql.OpenDB(connector), nil
+	}
+
 	return sql.Open(c.DriverName, json)
 }
 
@@ -154,13 +179,19 @@ func (d drv) OpenConnector(name string) (driver.Connector, error) {
 
 // A connector holds immutable state for the creation of additional conns via
 // the Connect method.
+//
+// When the configuration lists multiple vtgates, the connector also owns the
+// balanced *vtgateconn.VTGateConn shared by all of its conns.
 type connector struct {
 	drv     drv
 	cfg     Configuration
 	convert *converter
+
+	m        sync.Mutex
+	balanced *vtgateconn.VTGateConn
 }
 
-func (d drv) newConnector(cfg Configuration) (driver.Connector, error) {
+func (d drv) newConnector(cfg Configuration) (*connector, error) {
 	convert, err := newConverter(&cfg)
 	if err != nil {
 		return nil, err
@@ -180,13 +211,69 @@ func (c *connector) Connect(ctx context.Context) (driver.Conn, error) {
 		convert: c.convert,
 	}
 
-	if err := conn.dial(ctx); err != nil {
+	if !c.cfg.isBalanced() {
+		if err := conn.dial(ctx); err != nil {
+			return nil, err
+		}
+
+		return conn, nil
+	}
+
+	vtgateConn, err := c.balancedConn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	conn.conn = vtgateConn
+	conn.shared = true
+	if err := conn.newSession(); err != nil {
 		return nil, err
 	}
 
 	return conn, nil
 }
 
+// balancedConn returns the connector's balanced VTGateConn, dialing it on
+// first use.
+func (c *connector) balancedConn(ctx context.Context) (*vtgateconn.VTGateConn, error) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	if c.balanced != nil {
+		return c.balanced, nil
+	}
+
+	resolver := c.cfg.Resolver
+	if resolver == nil {
+		resolver = vtgateconn.StaticResolver(c.cfg.addresses())
+	}
+
+	vtgateConn, err := vtgateconn.DialBalanced(ctx, c.cfg.Protocol, resolver, vtgateconn.BalancerOptions{
+		HealthCheckInterval: c.cfg.HealthCheckInterval,
+		MaxRetries:          c.cfg.MaxRetries,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	c.balanced = vtgateConn
+	return vtgateConn, nil
+}
+
+// Close implements the io.Closer interface. It is called by (*sql.DB).Close,
+// and closes the balanced VTGateConn, if any.
+func (c *connector) Close() error {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	if c.balanced != nil {
+		c.balanced.Close()
+		c.balanced = nil
+	}
+
+	return nil
+}
+
 // Driver implements the database/sql/driver.Connector interface.
 func (c *connector) Driver() driver.Driver { return c.drv }
 
@@ -205,6 +292,39 @@ type Configuration struct {
 	// Format: hostname:port
 	Address string
 
+	// Addresses is a list of vtgate instances to balance sessions across. If
+	// Address is also set, it is added to this list. When more than one
+	// vtgate is configured, each new connection is pinned to a healthy vtgate,
+	// and moves to another one (keeping its session, including any open
+	// transaction) if that vtgate becomes unavailable. Read-only queries that
+	// fail because their vtgate is unavailable are retried on another one.
+	//
+	// Format: [hostname:port, ...]
+	Addresses []string `json:",omitempty"`
+
+	// Resolver, if set, is used instead of Address and Addresses to discover
+	// the vtgates to balance sessions across. The list is refreshed every
+	// HealthCheckInterval.
+	//
+	// A Resolver cannot be expressed in the JSON string accepted by
+	// sql.Open, so it is only supported by OpenWithConfiguration, which
+	// ignores DriverName in that case.
+	//
+	// Default: none
+	Resolver vtgateconn.Resolver `json:"-"`
+
+	// HealthCheckInterval is how often vtgates are health checked, when
+	// balancing across multiple vtgates.
+	//
+	// Default: 5s
+	HealthCheckInterval time.Duration `json:",omitempty"`
+
+	// MaxRetries is the maximum number of other vtgates on which a read-only
+	// query is retried, when balancing across multiple vtgates.
+	//
+	// Default: 2
+	MaxRetries int `json:",omitempty"`
+
 	// Target specifies the default target.
 	Target string
 
@@ -235,6 +355,14 @@ type Configuration struct {
 	// SessionToken is a protobuf encoded vtgatepb.Session represented as base64, which
 	// can be used to distribute a transaction over the wire.
 	SessionToken string
+
+	// ReadOnlyTarget, if set, is the target used by read-only transactions
+	// (sql.TxOptions.ReadOnly) instead of Target. This allows read-only
+	// transactions to be served by replicas, e.g. with "@replica" or
+	// "ks@replica".
+	//
+	// Default: none
+	ReadOnlyTarget string `json:",omitempty"`
 }
 
 // toJSON converts Configuration to the JSON string which is required by the
@@ -260,11 +388,31 @@ func (c *Configuration) setDefaults() {
 	}
 }
 
+// addresses returns Address and Addresses as a single list.
+func (c *Configuration) addresses() []string {
+	if c.Address == "" {
+		return c.Addresses
+	}
+
+	return append([]string{c.Address}, c.Addresses...)
+}
+
+// isBalanced returns whether connections should be balanced across multiple
+// vtgates.
+func (c *Configuration) isBalanced() bool {
+	return c.Resolver != nil || len(c.Addresses) > 0
+}
+
 type conn struct {
 	cfg     Configuration
 	convert *converter
 	conn    *vtgateconn.VTGateConn
 	session *vtgateconn.VTGateSession
+	// shared is true if conn is owned by the connector rather than this conn.
+	shared bool
+	// txRestore, if set, undoes the session changes made by BeginTx for the
+	// options of the current transaction.
+	txRestore func()
 }
 
 func (c *conn) dial(ctx context.Context) error {
@@ -273,6 +421,10 @@ func (c *conn) dial(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	return c.newSession()
+}
+
+func (c *conn) newSession() error {
 	if c.cfg.SessionToken != "" {
 		sessionFromToken, err := sessionTokenToSession(c.cfg.SessionToken)
 		if err != nil {
@@ -303,7 +455,9 @@ func (c *conn) PrepareContext(ctx context.Context, query string) (driver.Stmt, e
 }
 
 func (c *conn) Close() error {
-	c.conn.Close()
+	if !c.shared {
+		c.conn.Close()
+	}
 	return nil
 }
 
@@ -447,13 +601,71 @@ func (c *conn) Begin() (driver.Tx, error) {
 	return c, nil
 }
 
+// BeginTx starts a transaction with the given options. The isolation level
+// and the target of a read-only transaction (see Configuration.ReadOnlyTarget)
+// are set on the session for the duration of the transaction only.
 func (c *conn) BeginTx(_ context.Context, opts driver.TxOptions) (driver.Tx, error) {
 	// We don't use the context. The function signature accepts the context
 	// to signal to the driver that it's allowed to call Rollback on Cancel.
-	if opts.Isolation != driver.IsolationLevel(0) || opts.ReadOnly {
-		return nil, errIsolationUnsupported
+	isolation, ok := txIsolations[sql.IsolationLevel(opts.Isolation)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %v", errIsolationUnsupported, sql.IsolationLevel(opts.Isolation))
+	}
+
+	if isolation == querypb.ExecuteOptions_DEFAULT && !opts.ReadOnly {
+		return c.Begin()
+	}
+
+	if c.cfg.SessionToken != "" {
+		return nil, errors.New("transaction options are not supported for a distributed tx")
+	}
+
+	c.setTxOptions(isolation, opts.ReadOnly)
+
+	query := "begin"
+	if opts.ReadOnly {
+		query = "start transaction read only"
+	}
+
+	if _, err := c.Exec(query, nil); err != nil {
+		c.restoreTxOptions()
+		return nil, err
+	}
+	return c, nil
+}
+
+// setTxOptions changes the session for a transaction with the given options,
+// and sets c.txRestore to undo those changes.
+func (c *conn) setTxOptions(isolation querypb.ExecuteOptions_TransactionIsolation, readOnly bool) {
+	session := c.session.SessionPb()
+	if session.Options == nil {
+		session.Options = &querypb.ExecuteOptions{}
+	}
+
+	origIsolation := session.Options.TransactionIsolation
+	origTarget := session.TargetString
+
+	session.Options.TransactionIsolation = isolation
+	if readOnly && c.cfg.ReadOnlyTarget != "" {
+		session.TargetString = c.cfg.ReadOnlyTarget
+	}
+
+	c.txRestore = func() {
+		// The session is replaced by every call to vtgate, so it has to be
+		// fetched again.
+		session := c.session.SessionPb()
+		if session.Options != nil {
+			session.Options.TransactionIsolation = origIsolation
+		}
+		session.TargetString = origTarget
+	}
+}
+
+func (c *conn) restoreTxOptions() {
+	if c.txRestore != nil {
+		c.txRestore()
+		c.txRestore = nil
 	}
-	return c.Begin()
 }
 
 func (c *conn) Commit() error {
@@ -464,6 +676,7 @@ func (c *conn) Commit() error {
 		return errors.New("calling Commit from a distributed tx is not allowed")
 	}
 
+	defer c.restoreTxOptions()
 	_, err := c.Exec("commit", nil)
 	return err
 }
@@ -476,6 +689,7 @@ func (c *conn) Rollback() error {
 		return errors.New("calling Rollback from a distributed tx is not allowed")
 	}
 
+	defer c.restoreTxOptions()
 	_, err := c.Exec("rollback", nil)
 	return err
 }
@@ -171,11 +171,39 @@ func TestBeginIsolation(t *testing.T) {
 	db, err := Open(testAddress, "@primary")
 	require.NoError(t, err)
 	defer db.Close()
-	_, err = db.BeginTx(context.Background(), &sql.TxOptions{Isolation: sql.LevelRepeatableRead})
-	want := errIsolationUnsupported.Error()
-	if err == nil || err.Error() != want {
-		t.Errorf("Begin: %v, want %s", err, want)
-	}
+	_, err = db.BeginTx(context.Background(), &sql.TxOptions{Isolation: sql.LevelLinearizable})
+	require.ErrorIs(t, err, errIsolationUnsupported)
+	require.ErrorContains(t, err, "Linearizable")
+}
+
+func TestBeginTxOptions(t *testing.T) {
+	ctx := context.Background()
+	db, err := OpenWithConfiguration(Configuration{
+		Address:        testAddress,
+		Target:         "@primary",
+		ReadOnlyTarget: "@replica",
+	})
+	require.NoError(t, err)
+	defer db.Close()
+
+	c, err := db.Conn(ctx)
+	require.NoError(t, err)
+	defer c.Close()
+
+	// The fake server checks that the transaction is started on the read-only
+	// target with the requested isolation level.
+	tx, err := c.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	require.NoError(t, err)
+	require.NoError(t, tx.Rollback())
+
+	// The options only apply to the transaction.
+	err = c.Raw(func(driverConn any) error {
+		session := driverConn.(*conn).session.SessionPb()
+		assert.Equal(t, "@primary", session.TargetString)
+		assert.Equal(t, querypb.ExecuteOptions_DEFAULT, session.GetOptions().GetTransactionIsolation())
+		return nil
+	})
+	require.NoError(t, err)
 }
 
 func TestExec(t *testing.T) {
@@ -233,6 +261,46 @@ func TestConfigurationToJSON(t *testing.T) {
 	}
 }
 
+func TestBalanced(t *testing.T) {
+	// A vtgate that is not listening.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	deadAddress := listener.Addr().String()
+	listener.Close()
+
+	for _, streaming := range []bool{false, true} {
+		db, err := OpenWithConfiguration(Configuration{
+			Address:   testAddress,
+			Addresses: []string{deadAddress},
+			Target:    "@rdonly",
+			Streaming: streaming,
+		})
+		require.NoError(t, err)
+
+		// Every connection succeeds, since read-only queries sent to the dead
+		// vtgate are retried on the live one.
+		for i := 0; i < 4; i++ {
+			conn, err := db.Conn(context.Background())
+			require.NoError(t, err)
+
+			rows, err := conn.QueryContext(context.Background(), "select * from t", int64(0))
+			require.NoError(t, err, "streaming=%v, conn %d", streaming, i)
+
+			count := 0
+			for rows.Next() {
+				count++
+			}
+			require.NoError(t, rows.Err())
+			assert.Equal(t, 2, count)
+
+			rows.Close()
+			require.NoError(t, conn.Close())
+		}
+
+		require.NoError(t, db.Close())
+	}
+}
+
 func TestExecStreamingNotAllowed(t *testing.T) {
 	db, err := OpenForStreaming(testAddress, "@rdonly")
 	if err != nil {
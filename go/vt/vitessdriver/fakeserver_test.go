@@ -200,6 +200,20 @@ var execMap = map[string]struct {
 		result:  &result1,
 		session: nil,
 	},
+	"select * from t": {
+		execQuery: &queryExecute{
+			SQL: "select * from t",
+			BindVariables: map[string]*querypb.BindVariable{
+				"v1": sqltypes.Int64BindVariable(0),
+			},
+			Session: &vtgatepb.Session{
+				TargetString: "@rdonly",
+				Autocommit:   true,
+			},
+		},
+		result:  &result1,
+		session: nil,
+	},
 	"requestDates": {
 		execQuery: &queryExecute{
 			SQL: "requestDates",
@@ -260,6 +274,20 @@ var execMap = map[string]struct {
 		result:  &sqltypes.Result{},
 		session: session1,
 	},
+	"start transaction read only": {
+		execQuery: &queryExecute{
+			SQL: "start transaction read only",
+			Session: &vtgatepb.Session{
+				TargetString: "@replica",
+				Autocommit:   true,
+				Options: &querypb.ExecuteOptions{
+					TransactionIsolation: querypb.ExecuteOptions_REPEATABLE_READ,
+				},
+			},
+		},
+		result:  &sqltypes.Result{},
+		session: session2,
+	},
 	"commit": {
 		execQuery: &queryExecute{
 			SQL:     "commit",
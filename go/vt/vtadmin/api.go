@@ -42,6 +42,7 @@ import (
 	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/topo/memorytopo"
 	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vtadmin/audit"
 	"vitess.io/vitess/go/vt/vtadmin/cluster"
 	"vitess.io/vitess/go/vt/vtadmin/cluster/dynamic"
 	"vitess.io/vitess/go/vt/vtadmin/errors"
@@ -56,6 +57,7 @@ import (
 	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vtexplain"
 
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	vtadminpb "vitess.io/vitess/go/vt/proto/vtadmin"
 	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
@@ -91,6 +93,9 @@ type Options struct {
 	GRPCOpts grpcserver.Options
 	HTTPOpts vtadminhttp.Options
 	RBAC     *rbac.Config
+	// AuditLog, if non-nil, records every mutating API call, and is served at
+	// /api/audit.
+	AuditLog *audit.Log
 	// EnableDynamicClusters makes it so that clients can pass clusters dynamically
 	// in a session-like way, either via HTTP cookies or gRPC metadata.
 	EnableDynamicClusters bool
@@ -122,6 +127,13 @@ func NewAPI(env *vtenv.Environment, clusters []*cluster.Cluster, opts Options) *
 		}
 	}
 
+	if opts.AuditLog != nil {
+		// This must come after the authentication interceptor, so that
+		// entries include the actor.
+		opts.GRPCOpts.UnaryInterceptors = append(opts.GRPCOpts.UnaryInterceptors, audit.UnaryServerInterceptor(opts.AuditLog))
+		opts.HTTPOpts.AuditLog = opts.AuditLog
+	}
+
 	if authz == nil {
 		authz, _ = rbac.NewAuthorizer(&rbac.Config{
 			Rules: []*struct {
@@ -358,6 +370,7 @@ func (api *API) Handler() http.Handler {
 
 	httpAPI := vtadminhttp.NewAPI(api, api.options.HTTPOpts)
 
+	router.HandleFunc("/audit", httpAPI.Adapt(vtadminhttp.GetAuditLog)).Name("API.GetAuditLog")
 	router.HandleFunc("/backups", httpAPI.Adapt(vtadminhttp.GetBackups)).Name("API.GetBackups")
 	router.HandleFunc("/cells", httpAPI.Adapt(vtadminhttp.GetCellInfos)).Name("API.GetCellInfos")
 	router.HandleFunc("/cells_aliases", httpAPI.Adapt(vtadminhttp.GetCellsAliases)).Name("API.GetCellsAliases")
@@ -415,7 +428,18 @@ func (api *API) Handler() http.Handler {
 	router.HandleFunc("/vschemas", httpAPI.Adapt(vtadminhttp.GetVSchemas)).Name("API.GetVSchemas")
 	router.HandleFunc("/vtctlds", httpAPI.Adapt(vtadminhttp.GetVtctlds)).Name("API.GetVtctlds")
 	router.HandleFunc("/vtexplain", httpAPI.Adapt(vtadminhttp.VTExplain)).Name("API.VTExplain")
+	router.HandleFunc("/vdiff/{cluster_id}", httpAPI.Adapt(vtadminhttp.VDiffCreate)).Name("API.VDiffCreate").Methods("POST")
+	router.HandleFunc("/vdiff/{cluster_id}/{keyspace}/{name}", httpAPI.Adapt(vtadminhttp.VDiffShow)).Name("API.VDiffShow").Methods("GET")
+	router.HandleFunc("/workflow/{cluster_id}/materialize", httpAPI.Adapt(vtadminhttp.MaterializeCreate)).Name("API.MaterializeCreate").Methods("POST")
+	router.HandleFunc("/workflow/{cluster_id}/movetables", httpAPI.Adapt(vtadminhttp.MoveTablesCreate)).Name("API.MoveTablesCreate").Methods("POST")
+	router.HandleFunc("/workflow/{cluster_id}/reshard", httpAPI.Adapt(vtadminhttp.ReshardCreate)).Name("API.ReshardCreate").Methods("POST")
+	router.HandleFunc("/workflow/{cluster_id}/{keyspace}/{name}", httpAPI.Adapt(vtadminhttp.WorkflowDelete)).Name("API.WorkflowDelete").Methods("DELETE", "OPTIONS")
 	router.HandleFunc("/workflow/{cluster_id}/{keyspace}/{name}", httpAPI.Adapt(vtadminhttp.GetWorkflow)).Name("API.GetWorkflow")
+	router.HandleFunc("/workflow/{cluster_id}/{keyspace}/{name}/complete", httpAPI.Adapt(vtadminhttp.MoveTablesComplete)).Name("API.MoveTablesComplete").Methods("POST")
+	router.HandleFunc("/workflow/{cluster_id}/{keyspace}/{name}/reversetraffic", httpAPI.Adapt(vtadminhttp.WorkflowReverseTraffic)).Name("API.WorkflowReverseTraffic").Methods("POST")
+	router.HandleFunc("/workflow/{cluster_id}/{keyspace}/{name}/start", httpAPI.Adapt(vtadminhttp.StartWorkflow)).Name("API.StartWorkflow").Methods("PUT", "OPTIONS")
+	router.HandleFunc("/workflow/{cluster_id}/{keyspace}/{name}/stop", httpAPI.Adapt(vtadminhttp.StopWorkflow)).Name("API.StopWorkflow").Methods("PUT", "OPTIONS")
+	router.HandleFunc("/workflow/{cluster_id}/{keyspace}/{name}/switchtraffic", httpAPI.Adapt(vtadminhttp.WorkflowSwitchTraffic)).Name("API.WorkflowSwitchTraffic").Methods("POST")
 	router.HandleFunc("/workflows", httpAPI.Adapt(vtadminhttp.GetWorkflows)).Name("API.GetWorkflows")
 
 	experimentalRouter := router.PathPrefix("/experimental").Subrouter()
@@ -715,6 +739,43 @@ func (api *API) FindSchema(ctx context.Context, req *vtadminpb.FindSchemaRequest
 	}
 }
 
+// GetAuditLog is part of the vtadminhttp.AuditLogServer interface. Entries
+// are filtered to those in clusters for which the actor may get the AuditLog
+// resource. Entries that do not name a cluster are only visible to actors
+// permitted to see the AuditLog resource in all clusters.
+func (api *API) GetAuditLog(ctx context.Context, limit int, clusterIDs []string) ([]*audit.Entry, error) {
+	span, ctx := trace.NewSpan(ctx, "API.GetAuditLog")
+	defer span.Finish()
+
+	span.Annotate("limit", limit)
+	span.Annotate("cluster_ids", strings.Join(clusterIDs, ","))
+
+	if api.options.AuditLog == nil {
+		return nil, nil
+	}
+
+	requested := sets.New[string](clusterIDs...)
+	allowed := func(entry *audit.Entry) bool {
+		if len(entry.ClusterIDs) == 0 {
+			return requested.Len() == 0 && api.authz.IsAuthorized(ctx, "", rbac.AuditLogResource, rbac.GetAction)
+		}
+
+		if requested.Len() > 0 && !requested.HasAny(entry.ClusterIDs...) {
+			return false
+		}
+
+		for _, id := range entry.ClusterIDs {
+			if !api.authz.IsAuthorized(ctx, id, rbac.AuditLogResource, rbac.GetAction) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	return api.options.AuditLog.Entries(limit, allowed), nil
+}
+
 // GetBackups is part of the vtadminpb.VTAdminServer interface.
 func (api *API) GetBackups(ctx context.Context, req *vtadminpb.GetBackupsRequest) (*vtadminpb.GetBackupsResponse, error) {
 	span, ctx := trace.NewSpan(ctx, "API.GetBackups")
@@ -1732,6 +1793,63 @@ func (api *API) LaunchSchemaMigration(ctx context.Context, req *vtadminpb.Launch
 	return c.LaunchSchemaMigration(ctx, req.Request)
 }
 
+// MaterializeCreate is part of the vtadminpb.VTAdminServer interface.
+func (api *API) MaterializeCreate(ctx context.Context, req *vtadminpb.MaterializeCreateRequest) (*vtctldatapb.MaterializeCreateResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "API.MaterializeCreate")
+	defer span.Finish()
+
+	span.Annotate("cluster_id", req.ClusterId)
+
+	if !api.authz.IsAuthorized(ctx, req.ClusterId, rbac.WorkflowResource, rbac.CreateAction) {
+		return nil, fmt.Errorf("%w: cannot create materialize workflow in %s", errors.ErrUnauthorized, req.ClusterId)
+	}
+
+	c, err := api.getClusterForRequest(req.ClusterId)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.MaterializeCreate(ctx, req.Request)
+}
+
+// MoveTablesComplete is part of the vtadminpb.VTAdminServer interface.
+func (api *API) MoveTablesComplete(ctx context.Context, req *vtadminpb.MoveTablesCompleteRequest) (*vtctldatapb.MoveTablesCompleteResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "API.MoveTablesComplete")
+	defer span.Finish()
+
+	span.Annotate("cluster_id", req.ClusterId)
+
+	if !api.authz.IsAuthorized(ctx, req.ClusterId, rbac.WorkflowResource, rbac.CompleteWorkflowAction) {
+		return nil, fmt.Errorf("%w: cannot complete movetables workflow in %s", errors.ErrUnauthorized, req.ClusterId)
+	}
+
+	c, err := api.getClusterForRequest(req.ClusterId)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.MoveTablesComplete(ctx, req.Request)
+}
+
+// MoveTablesCreate is part of the vtadminpb.VTAdminServer interface.
+func (api *API) MoveTablesCreate(ctx context.Context, req *vtadminpb.MoveTablesCreateRequest) (*vtctldatapb.WorkflowStatusResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "API.MoveTablesCreate")
+	defer span.Finish()
+
+	span.Annotate("cluster_id", req.ClusterId)
+
+	if !api.authz.IsAuthorized(ctx, req.ClusterId, rbac.WorkflowResource, rbac.CreateAction) {
+		return nil, fmt.Errorf("%w: cannot create movetables workflow in %s", errors.ErrUnauthorized, req.ClusterId)
+	}
+
+	c, err := api.getClusterForRequest(req.ClusterId)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.MoveTablesCreate(ctx, req.Request)
+}
+
 // PingTablet is part of the vtadminpb.VTAdminServer interface.
 func (api *API) PingTablet(ctx context.Context, req *vtadminpb.PingTabletRequest) (*vtadminpb.PingTabletResponse, error) {
 	span, ctx := trace.NewSpan(ctx, "API.PingTablet")
@@ -1939,6 +2057,25 @@ func (api *API) ReloadSchemaShard(ctx context.Context, req *vtadminpb.ReloadSche
 	}, nil
 }
 
+// ReshardCreate is part of the vtadminpb.VTAdminServer interface.
+func (api *API) ReshardCreate(ctx context.Context, req *vtadminpb.ReshardCreateRequest) (*vtctldatapb.WorkflowStatusResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "API.ReshardCreate")
+	defer span.Finish()
+
+	span.Annotate("cluster_id", req.ClusterId)
+
+	if !api.authz.IsAuthorized(ctx, req.ClusterId, rbac.WorkflowResource, rbac.CreateAction) {
+		return nil, fmt.Errorf("%w: cannot create reshard workflow in %s", errors.ErrUnauthorized, req.ClusterId)
+	}
+
+	c, err := api.getClusterForRequest(req.ClusterId)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.ReshardCreate(ctx, req.Request)
+}
+
 // RetrySchemaMigration is part of the vtadminpb.VTAdminServer interface.
 func (api *API) RetrySchemaMigration(ctx context.Context, req *vtadminpb.RetrySchemaMigrationRequest) (*vtctldatapb.RetrySchemaMigrationResponse, error) {
 	span, ctx := trace.NewSpan(ctx, "API.RetrySchemaMigration")
@@ -2047,6 +2184,27 @@ func (api *API) StartReplication(ctx context.Context, req *vtadminpb.StartReplic
 	}, nil
 }
 
+// StartWorkflow is part of the vtadminpb.VTAdminServer interface.
+func (api *API) StartWorkflow(ctx context.Context, req *vtadminpb.StartWorkflowRequest) (*vtctldatapb.WorkflowUpdateResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "API.StartWorkflow")
+	defer span.Finish()
+
+	span.Annotate("cluster_id", req.ClusterId)
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("workflow", req.Workflow)
+
+	if !api.authz.IsAuthorized(ctx, req.ClusterId, rbac.WorkflowResource, rbac.ManageWorkflowAction) {
+		return nil, fmt.Errorf("%w: cannot start workflow in %s", errors.ErrUnauthorized, req.ClusterId)
+	}
+
+	c, err := api.getClusterForRequest(req.ClusterId)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.SetWorkflowState(ctx, req.Keyspace, req.Workflow, binlogdatapb.VReplicationWorkflowState_Running)
+}
+
 // StopReplication is part of the vtadminpb.VTAdminServer interface.
 func (api *API) StopReplication(ctx context.Context, req *vtadminpb.StopReplicationRequest) (*vtadminpb.StopReplicationResponse, error) {
 	span, ctx := trace.NewSpan(ctx, "API.StopReplication")
@@ -2068,6 +2226,27 @@ func (api *API) StopReplication(ctx context.Context, req *vtadminpb.StopReplicat
 	}, nil
 }
 
+// StopWorkflow is part of the vtadminpb.VTAdminServer interface.
+func (api *API) StopWorkflow(ctx context.Context, req *vtadminpb.StopWorkflowRequest) (*vtctldatapb.WorkflowUpdateResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "API.StopWorkflow")
+	defer span.Finish()
+
+	span.Annotate("cluster_id", req.ClusterId)
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("workflow", req.Workflow)
+
+	if !api.authz.IsAuthorized(ctx, req.ClusterId, rbac.WorkflowResource, rbac.ManageWorkflowAction) {
+		return nil, fmt.Errorf("%w: cannot stop workflow in %s", errors.ErrUnauthorized, req.ClusterId)
+	}
+
+	c, err := api.getClusterForRequest(req.ClusterId)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.SetWorkflowState(ctx, req.Keyspace, req.Workflow, binlogdatapb.VReplicationWorkflowState_Stopped)
+}
+
 // TabletExternallyPromoted is part of the vtadminpb.VTAdminServer interface.
 func (api *API) TabletExternallyPromoted(ctx context.Context, req *vtadminpb.TabletExternallyPromotedRequest) (*vtadminpb.TabletExternallyPromotedResponse, error) {
 	span, ctx := trace.NewSpan(ctx, "API.TabletExternallyPromoted")
@@ -2235,6 +2414,44 @@ func (api *API) ValidateVersionShard(ctx context.Context, req *vtadminpb.Validat
 	return res, nil
 }
 
+// VDiffCreate is part of the vtadminpb.VTAdminServer interface.
+func (api *API) VDiffCreate(ctx context.Context, req *vtadminpb.VDiffCreateRequest) (*vtctldatapb.VDiffCreateResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "API.VDiffCreate")
+	defer span.Finish()
+
+	span.Annotate("cluster_id", req.ClusterId)
+
+	if !api.authz.IsAuthorized(ctx, req.ClusterId, rbac.VDiffResource, rbac.CreateAction) {
+		return nil, fmt.Errorf("%w: cannot create vdiff in %s", errors.ErrUnauthorized, req.ClusterId)
+	}
+
+	c, err := api.getClusterForRequest(req.ClusterId)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.VDiffCreate(ctx, req.Request)
+}
+
+// VDiffShow is part of the vtadminpb.VTAdminServer interface.
+func (api *API) VDiffShow(ctx context.Context, req *vtadminpb.VDiffShowRequest) (*vtctldatapb.VDiffShowResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "API.VDiffShow")
+	defer span.Finish()
+
+	span.Annotate("cluster_id", req.ClusterId)
+
+	if !api.authz.IsAuthorized(ctx, req.ClusterId, rbac.VDiffResource, rbac.GetAction) {
+		return nil, fmt.Errorf("%w: cannot get vdiff in %s", errors.ErrUnauthorized, req.ClusterId)
+	}
+
+	c, err := api.getClusterForRequest(req.ClusterId)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.VDiffShow(ctx, req.Request)
+}
+
 // VTExplain is part of the vtadminpb.VTAdminServer interface.
 func (api *API) VTExplain(ctx context.Context, req *vtadminpb.VTExplainRequest) (*vtadminpb.VTExplainResponse, error) {
 	// TODO (andrew): https://github.com/vitessio/vitess/issues/12161.
@@ -2407,6 +2624,44 @@ func (api *API) VTExplain(ctx context.Context, req *vtadminpb.VTExplainRequest)
 	}, nil
 }
 
+// WorkflowDelete is part of the vtadminpb.VTAdminServer interface.
+func (api *API) WorkflowDelete(ctx context.Context, req *vtadminpb.WorkflowDeleteRequest) (*vtctldatapb.WorkflowDeleteResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "API.WorkflowDelete")
+	defer span.Finish()
+
+	span.Annotate("cluster_id", req.ClusterId)
+
+	if !api.authz.IsAuthorized(ctx, req.ClusterId, rbac.WorkflowResource, rbac.DeleteAction) {
+		return nil, fmt.Errorf("%w: cannot delete workflow in %s", errors.ErrUnauthorized, req.ClusterId)
+	}
+
+	c, err := api.getClusterForRequest(req.ClusterId)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.WorkflowDelete(ctx, req.Request)
+}
+
+// WorkflowSwitchTraffic is part of the vtadminpb.VTAdminServer interface.
+func (api *API) WorkflowSwitchTraffic(ctx context.Context, req *vtadminpb.WorkflowSwitchTrafficRequest) (*vtctldatapb.WorkflowSwitchTrafficResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "API.WorkflowSwitchTraffic")
+	defer span.Finish()
+
+	span.Annotate("cluster_id", req.ClusterId)
+
+	if !api.authz.IsAuthorized(ctx, req.ClusterId, rbac.WorkflowResource, rbac.SwitchWorkflowTrafficAction) {
+		return nil, fmt.Errorf("%w: cannot switch workflow traffic in %s", errors.ErrUnauthorized, req.ClusterId)
+	}
+
+	c, err := api.getClusterForRequest(req.ClusterId)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.WorkflowSwitchTraffic(ctx, req.Request)
+}
+
 func (api *API) getClusterForRequest(id string) (*cluster.Cluster, error) {
 	api.clusterMu.Lock()
 	defer api.clusterMu.Unlock()
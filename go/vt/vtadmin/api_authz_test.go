@@ -2399,6 +2399,205 @@ func TestLaunchSchemaMigration(t *testing.T) {
 	})
 }
 
+func TestMaterializeCreate(t *testing.T) {
+	t.Parallel()
+
+	opts := vtadmin.Options{
+		RBAC: &rbac.Config{
+			Rules: []*struct {
+				Resource string
+				Actions  []string
+				Subjects []string
+				Clusters []string
+			}{
+				{
+					Resource: "Workflow",
+					Actions:  []string{"create"},
+					Subjects: []string{"user:allowed"},
+					Clusters: []string{"*"},
+				},
+			},
+		},
+	}
+	err := opts.RBAC.Reify()
+	require.NoError(t, err, "failed to reify authorization rules: %+v", opts.RBAC.Rules)
+
+	api := vtadmin.NewAPI(vtenv.NewTestEnv(), testClusters(t), opts)
+	t.Cleanup(func() {
+		if err := api.Close(); err != nil {
+			t.Logf("api did not close cleanly: %s", err.Error())
+		}
+	})
+
+	t.Run("unauthorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "other"}
+		ctx := context.Background()
+		ctx = rbac.NewContext(ctx, actor)
+
+		resp, err := api.MaterializeCreate(ctx, &vtadminpb.MaterializeCreateRequest{
+			ClusterId: "test",
+			Request: &vtctldatapb.MaterializeCreateRequest{
+				Settings: &vtctldatapb.MaterializeSettings{
+					TargetKeyspace: "test",
+				},
+			},
+		})
+		assert.Error(t, err, "actor %+v should not be permitted to MaterializeCreate", actor)
+		assert.Nil(t, resp, "actor %+v should not be permitted to MaterializeCreate", actor)
+	})
+
+	t.Run("authorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "allowed"}
+		ctx := context.Background()
+		ctx = rbac.NewContext(ctx, actor)
+
+		resp, err := api.MaterializeCreate(ctx, &vtadminpb.MaterializeCreateRequest{
+			ClusterId: "test",
+			Request: &vtctldatapb.MaterializeCreateRequest{
+				Settings: &vtctldatapb.MaterializeSettings{
+					TargetKeyspace: "test",
+				},
+			},
+		})
+		require.NoError(t, err)
+		assert.NotNil(t, resp, "actor %+v should be permitted to MaterializeCreate", actor)
+	})
+}
+
+func TestMoveTablesComplete(t *testing.T) {
+	t.Parallel()
+
+	opts := vtadmin.Options{
+		RBAC: &rbac.Config{
+			Rules: []*struct {
+				Resource string
+				Actions  []string
+				Subjects []string
+				Clusters []string
+			}{
+				{
+					Resource: "Workflow",
+					Actions:  []string{"complete_workflow"},
+					Subjects: []string{"user:allowed"},
+					Clusters: []string{"*"},
+				},
+			},
+		},
+	}
+	err := opts.RBAC.Reify()
+	require.NoError(t, err, "failed to reify authorization rules: %+v", opts.RBAC.Rules)
+
+	api := vtadmin.NewAPI(vtenv.NewTestEnv(), testClusters(t), opts)
+	t.Cleanup(func() {
+		if err := api.Close(); err != nil {
+			t.Logf("api did not close cleanly: %s", err.Error())
+		}
+	})
+
+	t.Run("unauthorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "other"}
+		ctx := context.Background()
+		ctx = rbac.NewContext(ctx, actor)
+
+		resp, err := api.MoveTablesComplete(ctx, &vtadminpb.MoveTablesCompleteRequest{
+			ClusterId: "test",
+			Request: &vtctldatapb.MoveTablesCompleteRequest{
+				TargetKeyspace: "test",
+			},
+		})
+		assert.Error(t, err, "actor %+v should not be permitted to MoveTablesComplete", actor)
+		assert.Nil(t, resp, "actor %+v should not be permitted to MoveTablesComplete", actor)
+	})
+
+	t.Run("authorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "allowed"}
+		ctx := context.Background()
+		ctx = rbac.NewContext(ctx, actor)
+
+		resp, err := api.MoveTablesComplete(ctx, &vtadminpb.MoveTablesCompleteRequest{
+			ClusterId: "test",
+			Request: &vtctldatapb.MoveTablesCompleteRequest{
+				TargetKeyspace: "test",
+			},
+		})
+		require.NoError(t, err)
+		assert.NotNil(t, resp, "actor %+v should be permitted to MoveTablesComplete", actor)
+	})
+}
+
+func TestMoveTablesCreate(t *testing.T) {
+	t.Parallel()
+
+	opts := vtadmin.Options{
+		RBAC: &rbac.Config{
+			Rules: []*struct {
+				Resource string
+				Actions  []string
+				Subjects []string
+				Clusters []string
+			}{
+				{
+					Resource: "Workflow",
+					Actions:  []string{"create"},
+					Subjects: []string{"user:allowed"},
+					Clusters: []string{"*"},
+				},
+			},
+		},
+	}
+	err := opts.RBAC.Reify()
+	require.NoError(t, err, "failed to reify authorization rules: %+v", opts.RBAC.Rules)
+
+	api := vtadmin.NewAPI(vtenv.NewTestEnv(), testClusters(t), opts)
+	t.Cleanup(func() {
+		if err := api.Close(); err != nil {
+			t.Logf("api did not close cleanly: %s", err.Error())
+		}
+	})
+
+	t.Run("unauthorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "other"}
+		ctx := context.Background()
+		ctx = rbac.NewContext(ctx, actor)
+
+		resp, err := api.MoveTablesCreate(ctx, &vtadminpb.MoveTablesCreateRequest{
+			ClusterId: "test",
+			Request: &vtctldatapb.MoveTablesCreateRequest{
+				TargetKeyspace: "test",
+			},
+		})
+		assert.Error(t, err, "actor %+v should not be permitted to MoveTablesCreate", actor)
+		assert.Nil(t, resp, "actor %+v should not be permitted to MoveTablesCreate", actor)
+	})
+
+	t.Run("authorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "allowed"}
+		ctx := context.Background()
+		ctx = rbac.NewContext(ctx, actor)
+
+		resp, err := api.MoveTablesCreate(ctx, &vtadminpb.MoveTablesCreateRequest{
+			ClusterId: "test",
+			Request: &vtctldatapb.MoveTablesCreateRequest{
+				TargetKeyspace: "test",
+			},
+		})
+		require.NoError(t, err)
+		assert.NotNil(t, resp, "actor %+v should be permitted to MoveTablesCreate", actor)
+	})
+}
+
 func TestPingTablet(t *testing.T) {
 	t.Parallel()
 
@@ -2747,6 +2946,71 @@ func TestReloadSchemas(t *testing.T) {
 	})
 }
 
+func TestReshardCreate(t *testing.T) {
+	t.Parallel()
+
+	opts := vtadmin.Options{
+		RBAC: &rbac.Config{
+			Rules: []*struct {
+				Resource string
+				Actions  []string
+				Subjects []string
+				Clusters []string
+			}{
+				{
+					Resource: "Workflow",
+					Actions:  []string{"create"},
+					Subjects: []string{"user:allowed"},
+					Clusters: []string{"*"},
+				},
+			},
+		},
+	}
+	err := opts.RBAC.Reify()
+	require.NoError(t, err, "failed to reify authorization rules: %+v", opts.RBAC.Rules)
+
+	api := vtadmin.NewAPI(vtenv.NewTestEnv(), testClusters(t), opts)
+	t.Cleanup(func() {
+		if err := api.Close(); err != nil {
+			t.Logf("api did not close cleanly: %s", err.Error())
+		}
+	})
+
+	t.Run("unauthorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "other"}
+		ctx := context.Background()
+		ctx = rbac.NewContext(ctx, actor)
+
+		resp, err := api.ReshardCreate(ctx, &vtadminpb.ReshardCreateRequest{
+			ClusterId: "test",
+			Request: &vtctldatapb.ReshardCreateRequest{
+				Keyspace: "test",
+			},
+		})
+		assert.Error(t, err, "actor %+v should not be permitted to ReshardCreate", actor)
+		assert.Nil(t, resp, "actor %+v should not be permitted to ReshardCreate", actor)
+	})
+
+	t.Run("authorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "allowed"}
+		ctx := context.Background()
+		ctx = rbac.NewContext(ctx, actor)
+
+		resp, err := api.ReshardCreate(ctx, &vtadminpb.ReshardCreateRequest{
+			ClusterId: "test",
+			Request: &vtctldatapb.ReshardCreateRequest{
+				Keyspace: "test",
+			},
+		})
+		require.NoError(t, err)
+		assert.NotNil(t, resp, "actor %+v should be permitted to ReshardCreate", actor)
+	})
+}
+
 func TestRetrySchemaMigration(t *testing.T) {
 	t.Parallel()
 
@@ -2855,8 +3119,267 @@ func TestRunHealthCheck(t *testing.T) {
 				Uid:  100,
 			},
 		})
-		assert.Error(t, err, "actor %+v should not be permitted to RunHealthCheck", actor)
-		assert.Nil(t, resp, "actor %+v should not be permitted to RunHealthCheck", actor)
+		assert.Error(t, err, "actor %+v should not be permitted to RunHealthCheck", actor)
+		assert.Nil(t, resp, "actor %+v should not be permitted to RunHealthCheck", actor)
+	})
+
+	t.Run("authorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "allowed"}
+		ctx := context.Background()
+		ctx = rbac.NewContext(ctx, actor)
+
+		resp, err := api.RunHealthCheck(ctx, &vtadminpb.RunHealthCheckRequest{
+			Alias: &topodatapb.TabletAlias{
+				Cell: "zone1",
+				Uid:  100,
+			},
+		})
+		require.NoError(t, err)
+		assert.NotNil(t, resp, "actor %+v should be permitted to RunHealthCheck", actor)
+	})
+}
+
+func TestSetReadOnly(t *testing.T) {
+	t.Parallel()
+
+	opts := vtadmin.Options{
+		RBAC: &rbac.Config{
+			Rules: []*struct {
+				Resource string
+				Actions  []string
+				Subjects []string
+				Clusters []string
+			}{
+				{
+					Resource: "Tablet",
+					Actions:  []string{"manage_tablet_writability"},
+					Subjects: []string{"user:allowed"},
+					Clusters: []string{"*"},
+				},
+			},
+		},
+	}
+	err := opts.RBAC.Reify()
+	require.NoError(t, err, "failed to reify authorization rules: %+v", opts.RBAC.Rules)
+
+	api := vtadmin.NewAPI(vtenv.NewTestEnv(), testClusters(t), opts)
+	t.Cleanup(func() {
+		if err := api.Close(); err != nil {
+			t.Logf("api did not close cleanly: %s", err.Error())
+		}
+	})
+
+	t.Run("unauthorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "other"}
+		ctx := context.Background()
+		ctx = rbac.NewContext(ctx, actor)
+
+		resp, err := api.SetReadOnly(ctx, &vtadminpb.SetReadOnlyRequest{
+			Alias: &topodatapb.TabletAlias{
+				Cell: "zone1",
+				Uid:  100,
+			},
+		})
+		assert.Error(t, err, "actor %+v should not be permitted to SetReadOnly", actor)
+		assert.Nil(t, resp, "actor %+v should not be permitted to SetReadOnly", actor)
+	})
+
+	t.Run("authorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "allowed"}
+		ctx := context.Background()
+		ctx = rbac.NewContext(ctx, actor)
+
+		resp, err := api.SetReadOnly(ctx, &vtadminpb.SetReadOnlyRequest{
+			Alias: &topodatapb.TabletAlias{
+				Cell: "zone1",
+				Uid:  100,
+			},
+		})
+		require.NoError(t, err)
+		assert.NotNil(t, resp, "actor %+v should be permitted to SetReadOnly", actor)
+	})
+}
+
+func TestSetReadWrite(t *testing.T) {
+	t.Parallel()
+
+	opts := vtadmin.Options{
+		RBAC: &rbac.Config{
+			Rules: []*struct {
+				Resource string
+				Actions  []string
+				Subjects []string
+				Clusters []string
+			}{
+				{
+					Resource: "Tablet",
+					Actions:  []string{"manage_tablet_writability"},
+					Subjects: []string{"user:allowed"},
+					Clusters: []string{"*"},
+				},
+			},
+		},
+	}
+	err := opts.RBAC.Reify()
+	require.NoError(t, err, "failed to reify authorization rules: %+v", opts.RBAC.Rules)
+
+	api := vtadmin.NewAPI(vtenv.NewTestEnv(), testClusters(t), opts)
+	t.Cleanup(func() {
+		if err := api.Close(); err != nil {
+			t.Logf("api did not close cleanly: %s", err.Error())
+		}
+	})
+
+	t.Run("unauthorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "other"}
+		ctx := context.Background()
+		ctx = rbac.NewContext(ctx, actor)
+
+		resp, err := api.SetReadWrite(ctx, &vtadminpb.SetReadWriteRequest{
+			Alias: &topodatapb.TabletAlias{
+				Cell: "zone1",
+				Uid:  100,
+			},
+		})
+		assert.Error(t, err, "actor %+v should not be permitted to SetReadWrite", actor)
+		assert.Nil(t, resp, "actor %+v should not be permitted to SetReadWrite", actor)
+	})
+
+	t.Run("authorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "allowed"}
+		ctx := context.Background()
+		ctx = rbac.NewContext(ctx, actor)
+
+		resp, err := api.SetReadWrite(ctx, &vtadminpb.SetReadWriteRequest{
+			Alias: &topodatapb.TabletAlias{
+				Cell: "zone1",
+				Uid:  100,
+			},
+		})
+		require.NoError(t, err)
+		assert.NotNil(t, resp, "actor %+v should be permitted to SetReadWrite", actor)
+	})
+}
+
+func TestStartReplication(t *testing.T) {
+	t.Parallel()
+
+	opts := vtadmin.Options{
+		RBAC: &rbac.Config{
+			Rules: []*struct {
+				Resource string
+				Actions  []string
+				Subjects []string
+				Clusters []string
+			}{
+				{
+					Resource: "Tablet",
+					Actions:  []string{"manage_tablet_replication"},
+					Subjects: []string{"user:allowed"},
+					Clusters: []string{"*"},
+				},
+			},
+		},
+	}
+	err := opts.RBAC.Reify()
+	require.NoError(t, err, "failed to reify authorization rules: %+v", opts.RBAC.Rules)
+
+	api := vtadmin.NewAPI(vtenv.NewTestEnv(), testClusters(t), opts)
+	t.Cleanup(func() {
+		if err := api.Close(); err != nil {
+			t.Logf("api did not close cleanly: %s", err.Error())
+		}
+	})
+
+	t.Run("unauthorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "other"}
+		ctx := context.Background()
+		ctx = rbac.NewContext(ctx, actor)
+
+		resp, err := api.StartReplication(ctx, &vtadminpb.StartReplicationRequest{
+			Alias: &topodatapb.TabletAlias{
+				Cell: "zone1",
+				Uid:  100,
+			},
+		})
+		assert.Error(t, err, "actor %+v should not be permitted to StartReplication", actor)
+		assert.Nil(t, resp, "actor %+v should not be permitted to StartReplication", actor)
+	})
+
+	t.Run("authorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "allowed"}
+		ctx := context.Background()
+		ctx = rbac.NewContext(ctx, actor)
+
+		resp, err := api.StartReplication(ctx, &vtadminpb.StartReplicationRequest{
+			Alias: &topodatapb.TabletAlias{
+				Cell: "zone1",
+				Uid:  100,
+			},
+		})
+		require.NoError(t, err)
+		assert.NotNil(t, resp, "actor %+v should be permitted to StartReplication", actor)
+	})
+}
+
+func TestStartWorkflow(t *testing.T) {
+	t.Parallel()
+
+	opts := vtadmin.Options{
+		RBAC: &rbac.Config{
+			Rules: []*struct {
+				Resource string
+				Actions  []string
+				Subjects []string
+				Clusters []string
+			}{
+				{
+					Resource: "Workflow",
+					Actions:  []string{"manage_workflow"},
+					Subjects: []string{"user:allowed"},
+					Clusters: []string{"*"},
+				},
+			},
+		},
+	}
+	err := opts.RBAC.Reify()
+	require.NoError(t, err, "failed to reify authorization rules: %+v", opts.RBAC.Rules)
+
+	api := vtadmin.NewAPI(vtenv.NewTestEnv(), testClusters(t), opts)
+	t.Cleanup(func() {
+		if err := api.Close(); err != nil {
+			t.Logf("api did not close cleanly: %s", err.Error())
+		}
+	})
+
+	t.Run("unauthorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "other"}
+		ctx := context.Background()
+		ctx = rbac.NewContext(ctx, actor)
+
+		resp, err := api.StartWorkflow(ctx, &vtadminpb.StartWorkflowRequest{
+			ClusterId: "test",
+			Keyspace:  "test",
+			Workflow:  "testworkflow",
+		})
+		assert.Error(t, err, "actor %+v should not be permitted to StartWorkflow", actor)
+		assert.Nil(t, resp, "actor %+v should not be permitted to StartWorkflow", actor)
 	})
 
 	t.Run("authorized actor", func(t *testing.T) {
@@ -2866,18 +3389,17 @@ func TestRunHealthCheck(t *testing.T) {
 		ctx := context.Background()
 		ctx = rbac.NewContext(ctx, actor)
 
-		resp, err := api.RunHealthCheck(ctx, &vtadminpb.RunHealthCheckRequest{
-			Alias: &topodatapb.TabletAlias{
-				Cell: "zone1",
-				Uid:  100,
-			},
+		resp, err := api.StartWorkflow(ctx, &vtadminpb.StartWorkflowRequest{
+			ClusterId: "test",
+			Keyspace:  "test",
+			Workflow:  "testworkflow",
 		})
 		require.NoError(t, err)
-		assert.NotNil(t, resp, "actor %+v should be permitted to RunHealthCheck", actor)
+		assert.NotNil(t, resp, "actor %+v should be permitted to StartWorkflow", actor)
 	})
 }
 
-func TestSetReadOnly(t *testing.T) {
+func TestStopReplication(t *testing.T) {
 	t.Parallel()
 
 	opts := vtadmin.Options{
@@ -2890,7 +3412,7 @@ func TestSetReadOnly(t *testing.T) {
 			}{
 				{
 					Resource: "Tablet",
-					Actions:  []string{"manage_tablet_writability"},
+					Actions:  []string{"manage_tablet_replication"},
 					Subjects: []string{"user:allowed"},
 					Clusters: []string{"*"},
 				},
@@ -2914,14 +3436,14 @@ func TestSetReadOnly(t *testing.T) {
 		ctx := context.Background()
 		ctx = rbac.NewContext(ctx, actor)
 
-		resp, err := api.SetReadOnly(ctx, &vtadminpb.SetReadOnlyRequest{
+		resp, err := api.StopReplication(ctx, &vtadminpb.StopReplicationRequest{
 			Alias: &topodatapb.TabletAlias{
 				Cell: "zone1",
 				Uid:  100,
 			},
 		})
-		assert.Error(t, err, "actor %+v should not be permitted to SetReadOnly", actor)
-		assert.Nil(t, resp, "actor %+v should not be permitted to SetReadOnly", actor)
+		assert.Error(t, err, "actor %+v should not be permitted to StopReplication", actor)
+		assert.Nil(t, resp, "actor %+v should not be permitted to StopReplication", actor)
 	})
 
 	t.Run("authorized actor", func(t *testing.T) {
@@ -2931,18 +3453,18 @@ func TestSetReadOnly(t *testing.T) {
 		ctx := context.Background()
 		ctx = rbac.NewContext(ctx, actor)
 
-		resp, err := api.SetReadOnly(ctx, &vtadminpb.SetReadOnlyRequest{
+		resp, err := api.StopReplication(ctx, &vtadminpb.StopReplicationRequest{
 			Alias: &topodatapb.TabletAlias{
 				Cell: "zone1",
 				Uid:  100,
 			},
 		})
 		require.NoError(t, err)
-		assert.NotNil(t, resp, "actor %+v should be permitted to SetReadOnly", actor)
+		assert.NotNil(t, resp, "actor %+v should be permitted to StopReplication", actor)
 	})
 }
 
-func TestSetReadWrite(t *testing.T) {
+func TestStopWorkflow(t *testing.T) {
 	t.Parallel()
 
 	opts := vtadmin.Options{
@@ -2954,8 +3476,8 @@ func TestSetReadWrite(t *testing.T) {
 				Clusters []string
 			}{
 				{
-					Resource: "Tablet",
-					Actions:  []string{"manage_tablet_writability"},
+					Resource: "Workflow",
+					Actions:  []string{"manage_workflow"},
 					Subjects: []string{"user:allowed"},
 					Clusters: []string{"*"},
 				},
@@ -2979,14 +3501,13 @@ func TestSetReadWrite(t *testing.T) {
 		ctx := context.Background()
 		ctx = rbac.NewContext(ctx, actor)
 
-		resp, err := api.SetReadWrite(ctx, &vtadminpb.SetReadWriteRequest{
-			Alias: &topodatapb.TabletAlias{
-				Cell: "zone1",
-				Uid:  100,
-			},
+		resp, err := api.StopWorkflow(ctx, &vtadminpb.StopWorkflowRequest{
+			ClusterId: "test",
+			Keyspace:  "test",
+			Workflow:  "testworkflow",
 		})
-		assert.Error(t, err, "actor %+v should not be permitted to SetReadWrite", actor)
-		assert.Nil(t, resp, "actor %+v should not be permitted to SetReadWrite", actor)
+		assert.Error(t, err, "actor %+v should not be permitted to StopWorkflow", actor)
+		assert.Nil(t, resp, "actor %+v should not be permitted to StopWorkflow", actor)
 	})
 
 	t.Run("authorized actor", func(t *testing.T) {
@@ -2996,18 +3517,17 @@ func TestSetReadWrite(t *testing.T) {
 		ctx := context.Background()
 		ctx = rbac.NewContext(ctx, actor)
 
-		resp, err := api.SetReadWrite(ctx, &vtadminpb.SetReadWriteRequest{
-			Alias: &topodatapb.TabletAlias{
-				Cell: "zone1",
-				Uid:  100,
-			},
+		resp, err := api.StopWorkflow(ctx, &vtadminpb.StopWorkflowRequest{
+			ClusterId: "test",
+			Keyspace:  "test",
+			Workflow:  "testworkflow",
 		})
 		require.NoError(t, err)
-		assert.NotNil(t, resp, "actor %+v should be permitted to SetReadWrite", actor)
+		assert.NotNil(t, resp, "actor %+v should be permitted to StopWorkflow", actor)
 	})
 }
 
-func TestStartReplication(t *testing.T) {
+func TestTabletExternallyPromoted(t *testing.T) {
 	t.Parallel()
 
 	opts := vtadmin.Options{
@@ -3019,8 +3539,8 @@ func TestStartReplication(t *testing.T) {
 				Clusters []string
 			}{
 				{
-					Resource: "Tablet",
-					Actions:  []string{"manage_tablet_replication"},
+					Resource: "Shard",
+					Actions:  []string{"tablet_externally_promoted"},
 					Subjects: []string{"user:allowed"},
 					Clusters: []string{"*"},
 				},
@@ -3044,14 +3564,14 @@ func TestStartReplication(t *testing.T) {
 		ctx := context.Background()
 		ctx = rbac.NewContext(ctx, actor)
 
-		resp, err := api.StartReplication(ctx, &vtadminpb.StartReplicationRequest{
+		resp, err := api.TabletExternallyPromoted(ctx, &vtadminpb.TabletExternallyPromotedRequest{
 			Alias: &topodatapb.TabletAlias{
 				Cell: "zone1",
 				Uid:  100,
 			},
 		})
-		assert.Error(t, err, "actor %+v should not be permitted to StartReplication", actor)
-		assert.Nil(t, resp, "actor %+v should not be permitted to StartReplication", actor)
+		assert.Error(t, err, "actor %+v should not be permitted to TabletExternallyPromoted", actor)
+		assert.Nil(t, resp, "actor %+v should not be permitted to TabletExternallyPromoted", actor)
 	})
 
 	t.Run("authorized actor", func(t *testing.T) {
@@ -3061,18 +3581,18 @@ func TestStartReplication(t *testing.T) {
 		ctx := context.Background()
 		ctx = rbac.NewContext(ctx, actor)
 
-		resp, err := api.StartReplication(ctx, &vtadminpb.StartReplicationRequest{
+		resp, err := api.TabletExternallyPromoted(ctx, &vtadminpb.TabletExternallyPromotedRequest{
 			Alias: &topodatapb.TabletAlias{
 				Cell: "zone1",
 				Uid:  100,
 			},
 		})
 		require.NoError(t, err)
-		assert.NotNil(t, resp, "actor %+v should be permitted to StartReplication", actor)
+		assert.NotNil(t, resp, "actor %+v should be permitted to TabletExternallyPromoted", actor)
 	})
 }
 
-func TestStopReplication(t *testing.T) {
+func TestVDiffCreate(t *testing.T) {
 	t.Parallel()
 
 	opts := vtadmin.Options{
@@ -3084,8 +3604,8 @@ func TestStopReplication(t *testing.T) {
 				Clusters []string
 			}{
 				{
-					Resource: "Tablet",
-					Actions:  []string{"manage_tablet_replication"},
+					Resource: "VDiff",
+					Actions:  []string{"create"},
 					Subjects: []string{"user:allowed"},
 					Clusters: []string{"*"},
 				},
@@ -3109,14 +3629,14 @@ func TestStopReplication(t *testing.T) {
 		ctx := context.Background()
 		ctx = rbac.NewContext(ctx, actor)
 
-		resp, err := api.StopReplication(ctx, &vtadminpb.StopReplicationRequest{
-			Alias: &topodatapb.TabletAlias{
-				Cell: "zone1",
-				Uid:  100,
+		resp, err := api.VDiffCreate(ctx, &vtadminpb.VDiffCreateRequest{
+			ClusterId: "test",
+			Request: &vtctldatapb.VDiffCreateRequest{
+				TargetKeyspace: "test",
 			},
 		})
-		assert.Error(t, err, "actor %+v should not be permitted to StopReplication", actor)
-		assert.Nil(t, resp, "actor %+v should not be permitted to StopReplication", actor)
+		assert.Error(t, err, "actor %+v should not be permitted to VDiffCreate", actor)
+		assert.Nil(t, resp, "actor %+v should not be permitted to VDiffCreate", actor)
 	})
 
 	t.Run("authorized actor", func(t *testing.T) {
@@ -3126,18 +3646,18 @@ func TestStopReplication(t *testing.T) {
 		ctx := context.Background()
 		ctx = rbac.NewContext(ctx, actor)
 
-		resp, err := api.StopReplication(ctx, &vtadminpb.StopReplicationRequest{
-			Alias: &topodatapb.TabletAlias{
-				Cell: "zone1",
-				Uid:  100,
+		resp, err := api.VDiffCreate(ctx, &vtadminpb.VDiffCreateRequest{
+			ClusterId: "test",
+			Request: &vtctldatapb.VDiffCreateRequest{
+				TargetKeyspace: "test",
 			},
 		})
 		require.NoError(t, err)
-		assert.NotNil(t, resp, "actor %+v should be permitted to StopReplication", actor)
+		assert.NotNil(t, resp, "actor %+v should be permitted to VDiffCreate", actor)
 	})
 }
 
-func TestTabletExternallyPromoted(t *testing.T) {
+func TestVDiffShow(t *testing.T) {
 	t.Parallel()
 
 	opts := vtadmin.Options{
@@ -3149,8 +3669,8 @@ func TestTabletExternallyPromoted(t *testing.T) {
 				Clusters []string
 			}{
 				{
-					Resource: "Shard",
-					Actions:  []string{"tablet_externally_promoted"},
+					Resource: "VDiff",
+					Actions:  []string{"get"},
 					Subjects: []string{"user:allowed"},
 					Clusters: []string{"*"},
 				},
@@ -3174,14 +3694,14 @@ func TestTabletExternallyPromoted(t *testing.T) {
 		ctx := context.Background()
 		ctx = rbac.NewContext(ctx, actor)
 
-		resp, err := api.TabletExternallyPromoted(ctx, &vtadminpb.TabletExternallyPromotedRequest{
-			Alias: &topodatapb.TabletAlias{
-				Cell: "zone1",
-				Uid:  100,
+		resp, err := api.VDiffShow(ctx, &vtadminpb.VDiffShowRequest{
+			ClusterId: "test",
+			Request: &vtctldatapb.VDiffShowRequest{
+				TargetKeyspace: "test",
 			},
 		})
-		assert.Error(t, err, "actor %+v should not be permitted to TabletExternallyPromoted", actor)
-		assert.Nil(t, resp, "actor %+v should not be permitted to TabletExternallyPromoted", actor)
+		assert.Error(t, err, "actor %+v should not be permitted to VDiffShow", actor)
+		assert.Nil(t, resp, "actor %+v should not be permitted to VDiffShow", actor)
 	})
 
 	t.Run("authorized actor", func(t *testing.T) {
@@ -3191,14 +3711,14 @@ func TestTabletExternallyPromoted(t *testing.T) {
 		ctx := context.Background()
 		ctx = rbac.NewContext(ctx, actor)
 
-		resp, err := api.TabletExternallyPromoted(ctx, &vtadminpb.TabletExternallyPromotedRequest{
-			Alias: &topodatapb.TabletAlias{
-				Cell: "zone1",
-				Uid:  100,
+		resp, err := api.VDiffShow(ctx, &vtadminpb.VDiffShowRequest{
+			ClusterId: "test",
+			Request: &vtctldatapb.VDiffShowRequest{
+				TargetKeyspace: "test",
 			},
 		})
 		require.NoError(t, err)
-		assert.NotNil(t, resp, "actor %+v should be permitted to TabletExternallyPromoted", actor)
+		assert.NotNil(t, resp, "actor %+v should be permitted to VDiffShow", actor)
 	})
 }
 
@@ -3453,6 +3973,136 @@ func TestValidateVersionKeyspace(t *testing.T) {
 	})
 }
 
+func TestWorkflowDelete(t *testing.T) {
+	t.Parallel()
+
+	opts := vtadmin.Options{
+		RBAC: &rbac.Config{
+			Rules: []*struct {
+				Resource string
+				Actions  []string
+				Subjects []string
+				Clusters []string
+			}{
+				{
+					Resource: "Workflow",
+					Actions:  []string{"delete"},
+					Subjects: []string{"user:allowed"},
+					Clusters: []string{"*"},
+				},
+			},
+		},
+	}
+	err := opts.RBAC.Reify()
+	require.NoError(t, err, "failed to reify authorization rules: %+v", opts.RBAC.Rules)
+
+	api := vtadmin.NewAPI(vtenv.NewTestEnv(), testClusters(t), opts)
+	t.Cleanup(func() {
+		if err := api.Close(); err != nil {
+			t.Logf("api did not close cleanly: %s", err.Error())
+		}
+	})
+
+	t.Run("unauthorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "other"}
+		ctx := context.Background()
+		ctx = rbac.NewContext(ctx, actor)
+
+		resp, err := api.WorkflowDelete(ctx, &vtadminpb.WorkflowDeleteRequest{
+			ClusterId: "test",
+			Request: &vtctldatapb.WorkflowDeleteRequest{
+				Keyspace: "test",
+			},
+		})
+		assert.Error(t, err, "actor %+v should not be permitted to WorkflowDelete", actor)
+		assert.Nil(t, resp, "actor %+v should not be permitted to WorkflowDelete", actor)
+	})
+
+	t.Run("authorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "allowed"}
+		ctx := context.Background()
+		ctx = rbac.NewContext(ctx, actor)
+
+		resp, err := api.WorkflowDelete(ctx, &vtadminpb.WorkflowDeleteRequest{
+			ClusterId: "test",
+			Request: &vtctldatapb.WorkflowDeleteRequest{
+				Keyspace: "test",
+			},
+		})
+		require.NoError(t, err)
+		assert.NotNil(t, resp, "actor %+v should be permitted to WorkflowDelete", actor)
+	})
+}
+
+func TestWorkflowSwitchTraffic(t *testing.T) {
+	t.Parallel()
+
+	opts := vtadmin.Options{
+		RBAC: &rbac.Config{
+			Rules: []*struct {
+				Resource string
+				Actions  []string
+				Subjects []string
+				Clusters []string
+			}{
+				{
+					Resource: "Workflow",
+					Actions:  []string{"switch_workflow_traffic"},
+					Subjects: []string{"user:allowed"},
+					Clusters: []string{"*"},
+				},
+			},
+		},
+	}
+	err := opts.RBAC.Reify()
+	require.NoError(t, err, "failed to reify authorization rules: %+v", opts.RBAC.Rules)
+
+	api := vtadmin.NewAPI(vtenv.NewTestEnv(), testClusters(t), opts)
+	t.Cleanup(func() {
+		if err := api.Close(); err != nil {
+			t.Logf("api did not close cleanly: %s", err.Error())
+		}
+	})
+
+	t.Run("unauthorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "other"}
+		ctx := context.Background()
+		ctx = rbac.NewContext(ctx, actor)
+
+		resp, err := api.WorkflowSwitchTraffic(ctx, &vtadminpb.WorkflowSwitchTrafficRequest{
+			ClusterId: "test",
+			Request: &vtctldatapb.WorkflowSwitchTrafficRequest{
+				Keyspace: "test",
+			},
+		})
+		assert.Error(t, err, "actor %+v should not be permitted to WorkflowSwitchTraffic", actor)
+		assert.Nil(t, resp, "actor %+v should not be permitted to WorkflowSwitchTraffic", actor)
+	})
+
+	t.Run("authorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "allowed"}
+		ctx := context.Background()
+		ctx = rbac.NewContext(ctx, actor)
+
+		resp, err := api.WorkflowSwitchTraffic(ctx, &vtadminpb.WorkflowSwitchTrafficRequest{
+			ClusterId: "test",
+			Request: &vtctldatapb.WorkflowSwitchTrafficRequest{
+				Keyspace: "test",
+			},
+		})
+		require.NoError(t, err)
+		assert.NotNil(t, resp, "actor %+v should be permitted to WorkflowSwitchTraffic", actor)
+	})
+}
+
 func testClusters(t testing.TB) []*cluster.Cluster {
 	configs := []testutil.TestClusterConfig{
 		{
@@ -3670,6 +4320,30 @@ func testClusters(t testing.TB) []*cluster.Cluster {
 						Response: &vtctldatapb.LaunchSchemaMigrationResponse{},
 					},
 				},
+				MaterializeCreateResults: map[string]struct {
+					Response *vtctldatapb.MaterializeCreateResponse
+					Error    error
+				}{
+					"test": {
+						Response: &vtctldatapb.MaterializeCreateResponse{},
+					},
+				},
+				MoveTablesCompleteResults: map[string]struct {
+					Response *vtctldatapb.MoveTablesCompleteResponse
+					Error    error
+				}{
+					"test": {
+						Response: &vtctldatapb.MoveTablesCompleteResponse{},
+					},
+				},
+				MoveTablesCreateResults: map[string]struct {
+					Response *vtctldatapb.WorkflowStatusResponse
+					Error    error
+				}{
+					"test": {
+						Response: &vtctldatapb.WorkflowStatusResponse{},
+					},
+				},
 				PingTabletResults: map[string]error{
 					"zone1-0000000100": nil,
 				},
@@ -3701,6 +4375,14 @@ func testClusters(t testing.TB) []*cluster.Cluster {
 						Response: &vtctldatapb.ReparentTabletResponse{},
 					},
 				},
+				ReshardCreateResults: map[string]struct {
+					Response *vtctldatapb.WorkflowStatusResponse
+					Error    error
+				}{
+					"test": {
+						Response: &vtctldatapb.WorkflowStatusResponse{},
+					},
+				},
 				RetrySchemaMigrationResults: map[string]struct {
 					Response *vtctldatapb.RetrySchemaMigrationResponse
 					Error    error
@@ -3737,6 +4419,22 @@ func testClusters(t testing.TB) []*cluster.Cluster {
 						Response: &vtctldatapb.TabletExternallyReparentedResponse{},
 					},
 				},
+				VDiffCreateResults: map[string]struct {
+					Response *vtctldatapb.VDiffCreateResponse
+					Error    error
+				}{
+					"test": {
+						Response: &vtctldatapb.VDiffCreateResponse{},
+					},
+				},
+				VDiffShowResults: map[string]struct {
+					Response *vtctldatapb.VDiffShowResponse
+					Error    error
+				}{
+					"test": {
+						Response: &vtctldatapb.VDiffShowResponse{},
+					},
+				},
 				ValidateKeyspaceResults: map[string]struct {
 					Response *vtctldatapb.ValidateKeyspaceResponse
 					Error    error
@@ -3761,6 +4459,30 @@ func testClusters(t testing.TB) []*cluster.Cluster {
 						Response: &vtctldatapb.ValidateVersionKeyspaceResponse{},
 					},
 				},
+				WorkflowDeleteResults: map[string]struct {
+					Response *vtctldatapb.WorkflowDeleteResponse
+					Error    error
+				}{
+					"test": {
+						Response: &vtctldatapb.WorkflowDeleteResponse{},
+					},
+				},
+				WorkflowSwitchTrafficResults: map[string]struct {
+					Response *vtctldatapb.WorkflowSwitchTrafficResponse
+					Error    error
+				}{
+					"test": {
+						Response: &vtctldatapb.WorkflowSwitchTrafficResponse{},
+					},
+				},
+				WorkflowUpdateResults: map[string]struct {
+					Response *vtctldatapb.WorkflowUpdateResponse
+					Error    error
+				}{
+					"test": {
+						Response: &vtctldatapb.WorkflowUpdateResponse{},
+					},
+				},
 			},
 			Tablets: []*vtadminpb.Tablet{
 				{
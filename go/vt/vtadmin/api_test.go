@@ -27,6 +27,7 @@ import (
 	"net/url"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -39,9 +40,11 @@ import (
 	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/topo/memorytopo"
 	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vtadmin/audit"
 	"vitess.io/vitess/go/vt/vtadmin/cluster"
 	"vitess.io/vitess/go/vt/vtadmin/cluster/discovery/fakediscovery"
 	vtadminerrors "vitess.io/vitess/go/vt/vtadmin/errors"
+	"vitess.io/vitess/go/vt/vtadmin/rbac"
 	vtadmintestutil "vitess.io/vitess/go/vt/vtadmin/testutil"
 	"vitess.io/vitess/go/vt/vtadmin/vtctldclient/fakevtctldclient"
 	"vitess.io/vitess/go/vt/vtctl/grpcvtctldserver"
@@ -817,6 +820,138 @@ func TestFindSchema(t *testing.T) {
 	})
 }
 
+func TestGetAuditLog(t *testing.T) {
+	t.Parallel()
+
+	workflowUpdateResults := map[string]struct {
+		Response *vtctldatapb.WorkflowUpdateResponse
+		Error    error
+	}{
+		"ks": {
+			Response: &vtctldatapb.WorkflowUpdateResponse{},
+		},
+	}
+
+	clusters := []*cluster.Cluster{
+		vtadmintestutil.BuildCluster(t, vtadmintestutil.TestClusterConfig{
+			Cluster: &vtadminpb.Cluster{
+				Id:   "c1",
+				Name: "cluster1",
+			},
+			VtctldClient: &fakevtctldclient.VtctldClient{
+				WorkflowUpdateResults: workflowUpdateResults,
+			},
+		}),
+		vtadmintestutil.BuildCluster(t, vtadmintestutil.TestClusterConfig{
+			Cluster: &vtadminpb.Cluster{
+				Id:   "c2",
+				Name: "cluster2",
+			},
+			VtctldClient: &fakevtctldclient.VtctldClient{
+				WorkflowUpdateResults: workflowUpdateResults,
+			},
+		}),
+	}
+
+	rbacCfg := &rbac.Config{
+		Rules: []*struct {
+			Resource string
+			Actions  []string
+			Subjects []string
+			Clusters []string
+		}{
+			{
+				Resource: "*",
+				Actions:  []string{"*"},
+				Subjects: []string{"user:admin"},
+				Clusters: []string{"*"},
+			},
+			{
+				Resource: string(rbac.AuditLogResource),
+				Actions:  []string{string(rbac.GetAction)},
+				Subjects: []string{"user:c1viewer"},
+				Clusters: []string{"c1"},
+			},
+		},
+	}
+	require.NoError(t, rbacCfg.Reify())
+
+	auditLog, err := audit.NewLog("", 10)
+	require.NoError(t, err)
+
+	api := NewAPI(vtenv.NewTestEnv(), clusters, Options{
+		RBAC:     rbacCfg,
+		AuditLog: auditLog,
+	})
+	defer api.Close()
+
+	admin := &rbac.Actor{Name: "admin"}
+	for _, req := range []struct {
+		method string
+		path   string
+	}{
+		{method: http.MethodPut, path: "/api/workflow/c1/ks/wf1/start"},
+		{method: http.MethodGet, path: "/api/clusters"},
+		{method: http.MethodPut, path: "/api/workflow/c2/ks/wf2/stop"},
+	} {
+		r := httptest.NewRequest(req.method, req.path, nil)
+		r = r.WithContext(rbac.NewContext(r.Context(), admin))
+
+		w := httptest.NewRecorder()
+		api.ServeHTTP(w, r)
+		require.Equal(t, http.StatusOK, w.Code, "%s %s failed: %s", req.method, req.path, w.Body.String())
+	}
+
+	ctx := rbac.NewContext(context.Background(), admin)
+	entries, err := api.GetAuditLog(ctx, 0, nil)
+	require.NoError(t, err)
+	require.Len(t, entries, 2, "only mutating calls should be audited")
+
+	for _, entry := range entries {
+		entry.Time = time.Time{}
+	}
+
+	assert.Equal(t, []*audit.Entry{
+		{
+			Actor:      admin,
+			Method:     "StopWorkflow",
+			Transport:  audit.TransportHTTP,
+			ClusterIDs: []string{"c2"},
+			Keyspace:   "ks",
+			Workflow:   "wf2",
+			Success:    true,
+		},
+		{
+			Actor:      admin,
+			Method:     "StartWorkflow",
+			Transport:  audit.TransportHTTP,
+			ClusterIDs: []string{"c1"},
+			Keyspace:   "ks",
+			Workflow:   "wf1",
+			Success:    true,
+		},
+	}, entries)
+
+	entries, err = api.GetAuditLog(ctx, 0, []string{"c1"})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "StartWorkflow", entries[0].Method)
+
+	entries, err = api.GetAuditLog(ctx, 1, nil)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "StopWorkflow", entries[0].Method)
+
+	entries, err = api.GetAuditLog(rbac.NewContext(context.Background(), &rbac.Actor{Name: "c1viewer"}), 0, nil)
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "c1viewer should only see entries in c1")
+	assert.Equal(t, "StartWorkflow", entries[0].Method)
+
+	entries, err = api.GetAuditLog(rbac.NewContext(context.Background(), &rbac.Actor{Name: "other"}), 0, nil)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
 func TestGetClusters(t *testing.T) {
 	t.Parallel()
 
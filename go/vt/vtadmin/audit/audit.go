@@ -0,0 +1,191 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package audit provides a structured log of the mutating calls made against
+// the vtadmin API.
+//
+// Each entry records who made the call, which API method was invoked, which
+// cluster, keyspace, shard, tablet, or workflow it targeted, and whether it
+// succeeded. Entries are written as newline-delimited JSON to an (optional)
+// file, and the most recent entries are retained in memory so they can be
+// served by the API.
+package audit
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/vtadmin/rbac"
+)
+
+// Transport values for Entry.Transport.
+const (
+	TransportGRPC = "grpc"
+	TransportHTTP = "http"
+)
+
+// Entry is a single audit log record.
+type Entry struct {
+	Time       time.Time   `json:"time"`
+	Actor      *rbac.Actor `json:"actor,omitempty"`
+	Method     string      `json:"method"`
+	Transport  string      `json:"transport"`
+	ClusterIDs []string    `json:"cluster_ids,omitempty"`
+	Keyspace   string      `json:"keyspace,omitempty"`
+	Shard      string      `json:"shard,omitempty"`
+	Tablet     string      `json:"tablet,omitempty"`
+	Workflow   string      `json:"workflow,omitempty"`
+	Success    bool        `json:"success"`
+	Error      string      `json:"error,omitempty"`
+}
+
+// Log is an audit log. It is safe for concurrent use.
+type Log struct {
+	m       sync.Mutex
+	w       io.WriteCloser
+	entries []*Entry // ring buffer of the most recent entries
+	next    int      // index in entries of the next write
+	full    bool     // whether entries has wrapped around
+}
+
+// NewLog returns a Log that retains the most recent size entries in memory. If
+// path is non-empty, every entry is also appended to the file at that path as
+// a line of JSON.
+func NewLog(path string, size int) (*Log, error) {
+	if size < 1 {
+		size = 1
+	}
+
+	l := &Log{
+		entries: make([]*Entry, size),
+	}
+
+	if path != "" {
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o640)
+		if err != nil {
+			return nil, err
+		}
+
+		l.w = f
+	}
+
+	return l, nil
+}
+
+// Record adds an entry to the log. Failures to write the entry to the log file
+// are logged, but otherwise ignored, so that a full disk does not fail API
+// calls.
+func (l *Log) Record(entry *Entry) {
+	if entry.Time.IsZero() {
+		entry.Time = time.Now().UTC()
+	}
+
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	l.entries[l.next] = entry
+	l.next = (l.next + 1) % len(l.entries)
+	if l.next == 0 {
+		l.full = true
+	}
+
+	if l.w == nil {
+		return
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		log.Errorf("[audit]: failed to marshal entry for %s: %s", entry.Method, err)
+		return
+	}
+
+	if _, err := l.w.Write(append(data, '\n')); err != nil {
+		log.Errorf("[audit]: failed to write entry for %s: %s", entry.Method, err)
+	}
+}
+
+// Entries returns the entries retained in memory, most recent first, for which
+// the filter returns true. A nil filter includes every entry. If limit is
+// positive, at most limit entries are returned.
+func (l *Log) Entries(limit int, filter func(entry *Entry) bool) []*Entry {
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	n := l.next
+	if l.full {
+		n = len(l.entries)
+	}
+
+	entries := make([]*Entry, 0, n)
+	for i := 1; i <= n; i++ {
+		entry := l.entries[(l.next-i+len(l.entries))%len(l.entries)]
+		if filter != nil && !filter(entry) {
+			continue
+		}
+
+		entries = append(entries, entry)
+		if limit > 0 && len(entries) == limit {
+			break
+		}
+	}
+
+	return entries
+}
+
+// Close closes the log file, if any.
+func (l *Log) Close() error {
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	if l.w == nil {
+		return nil
+	}
+
+	err := l.w.Close()
+	l.w = nil
+	return err
+}
+
+// readOnlyMethodPrefixes are the prefixes of the vtadmin API methods that do
+// not change any state. Everything else is considered mutating, so that new
+// methods are audited unless explicitly excluded here.
+var readOnlyMethodPrefixes = []string{
+	"Find",
+	"Get",
+	"Ping",
+	"RunHealthCheck",
+	"TabletDebugVarsPassthrough",
+	"VDiffShow",
+	"VTExplain",
+	"Validate",
+}
+
+// IsMutating returns whether the vtadmin API method of the given name (e.g.
+// "StartWorkflow") changes state, and should therefore be audited.
+func IsMutating(method string) bool {
+	for _, prefix := range readOnlyMethodPrefixes {
+		if strings.HasPrefix(method, prefix) {
+			return false
+		}
+	}
+
+	return true
+}
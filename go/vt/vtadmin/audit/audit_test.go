@@ -0,0 +1,233 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"vitess.io/vitess/go/vt/vtadmin/rbac"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtadminpb "vitess.io/vitess/go/vt/proto/vtadmin"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+func TestLog(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "audit.log")
+	l, err := NewLog(path, 3)
+	require.NoError(t, err)
+
+	for i := 0; i < 5; i++ {
+		l.Record(&Entry{
+			Method:     fmt.Sprintf("Method%d", i),
+			ClusterIDs: []string{fmt.Sprintf("c%d", i%2)},
+			Success:    true,
+		})
+	}
+
+	methods := func(entries []*Entry) []string {
+		names := make([]string, len(entries))
+		for i, entry := range entries {
+			names[i] = entry.Method
+		}
+
+		return names
+	}
+
+	assert.Equal(t, []string{"Method4", "Method3", "Method2"}, methods(l.Entries(0, nil)), "only the most recent entries should be kept in memory, most recent first")
+	assert.Equal(t, []string{"Method4", "Method3"}, methods(l.Entries(2, nil)))
+	assert.Equal(t, []string{"Method4", "Method2"}, methods(l.Entries(0, func(entry *Entry) bool {
+		return entry.ClusterIDs[0] == "c0"
+	})))
+
+	require.NoError(t, l.Close())
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var logged []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry Entry
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+		assert.False(t, entry.Time.IsZero(), "entries should be timestamped")
+		logged = append(logged, entry.Method)
+	}
+	require.NoError(t, scanner.Err())
+
+	assert.Equal(t, []string{"Method0", "Method1", "Method2", "Method3", "Method4"}, logged, "every entry should be written to the file")
+}
+
+func TestLogNotFull(t *testing.T) {
+	t.Parallel()
+
+	l, err := NewLog("", 10)
+	require.NoError(t, err)
+	defer l.Close()
+
+	assert.Empty(t, l.Entries(0, nil))
+
+	l.Record(&Entry{Method: "Method0"})
+	entries := l.Entries(0, nil)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "Method0", entries[0].Method)
+}
+
+func TestIsMutating(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]bool{
+		"GetTablets":            false,
+		"FindSchema":            false,
+		"PingTablet":            false,
+		"ValidateKeyspace":      false,
+		"VTExplain":             false,
+		"VDiffShow":             false,
+		"CreateKeyspace":        true,
+		"DeleteTablet":          true,
+		"StartWorkflow":         true,
+		"WorkflowSwitchTraffic": true,
+		"VDiffCreate":           true,
+		"ReloadSchemas":         true,
+	}
+
+	for method, expected := range tests {
+		assert.Equal(t, expected, IsMutating(method), method)
+	}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	t.Parallel()
+
+	actor := &rbac.Actor{Name: "user1", Roles: []string{"dba"}}
+	ctx := rbac.NewContext(context.Background(), actor)
+
+	tests := []struct {
+		name       string
+		method     string
+		req        any
+		handlerErr error
+		expected   *Entry
+	}{
+		{
+			name:   "read-only method",
+			method: "GetTablet",
+			req: &vtadminpb.GetTabletRequest{
+				Alias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 100},
+			},
+			expected: nil,
+		},
+		{
+			name:   "top-level fields",
+			method: "StartWorkflow",
+			req: &vtadminpb.StartWorkflowRequest{
+				ClusterId: "c1",
+				Keyspace:  "ks",
+				Workflow:  "wf",
+			},
+			expected: &Entry{
+				Actor:      actor,
+				Method:     "StartWorkflow",
+				Transport:  TransportGRPC,
+				ClusterIDs: []string{"c1"},
+				Keyspace:   "ks",
+				Workflow:   "wf",
+				Success:    true,
+			},
+		},
+		{
+			name:   "tablet alias",
+			method: "DeleteTablet",
+			req: &vtadminpb.DeleteTabletRequest{
+				Alias:      &topodatapb.TabletAlias{Cell: "zone1", Uid: 100},
+				ClusterIds: []string{"c1", "c2"},
+			},
+			handlerErr: errors.New("tablet is primary"),
+			expected: &Entry{
+				Actor:      actor,
+				Method:     "DeleteTablet",
+				Transport:  TransportGRPC,
+				ClusterIDs: []string{"c1", "c2"},
+				Tablet:     "zone1-0000000100",
+				Success:    false,
+				Error:      "tablet is primary",
+			},
+		},
+		{
+			name:   "nested request",
+			method: "MoveTablesComplete",
+			req: &vtadminpb.MoveTablesCompleteRequest{
+				ClusterId: "c1",
+				Request: &vtctldatapb.MoveTablesCompleteRequest{
+					TargetKeyspace: "customer",
+					Workflow:       "commerce2customer",
+				},
+			},
+			expected: &Entry{
+				Actor:      actor,
+				Method:     "MoveTablesComplete",
+				Transport:  TransportGRPC,
+				ClusterIDs: []string{"c1"},
+				Keyspace:   "customer",
+				Workflow:   "commerce2customer",
+				Success:    true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			l, err := NewLog("", 10)
+			require.NoError(t, err)
+
+			interceptor := UnaryServerInterceptor(l)
+			info := &grpc.UnaryServerInfo{FullMethod: "/vtadmin.VTAdmin/" + tt.method}
+			_, err = interceptor(ctx, tt.req, info, func(ctx context.Context, req any) (any, error) {
+				return "ok", tt.handlerErr
+			})
+			assert.Equal(t, tt.handlerErr, err)
+
+			entries := l.Entries(0, nil)
+			if tt.expected == nil {
+				assert.Empty(t, entries)
+				return
+			}
+
+			require.Len(t, entries, 1)
+			entries[0].Time = tt.expected.Time
+			assert.Equal(t, tt.expected, entries[0])
+		})
+	}
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"context"
+	"path"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vtadmin/rbac"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// UnaryServerInterceptor returns a grpc.UnaryServerInterceptor that records an
+// entry in the log for every mutating rpc. It must be installed after the
+// authentication interceptor for entries to include the actor.
+func UnaryServerInterceptor(l *Log) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		method := path.Base(info.FullMethod)
+		if !IsMutating(method) {
+			return handler(ctx, req)
+		}
+
+		resp, err := handler(ctx, req)
+
+		entry := &Entry{
+			Method:    method,
+			Transport: TransportGRPC,
+			Success:   err == nil,
+		}
+		entry.Actor, _ = rbac.FromContext(ctx)
+
+		if err != nil {
+			entry.Error = err.Error()
+		}
+
+		if msg, ok := req.(proto.Message); ok {
+			entry.setTargetFromMessage(msg.ProtoReflect(), true)
+		}
+
+		l.Record(entry)
+		return resp, err
+	}
+}
+
+// setTargetFromMessage fills in the target fields of the entry from the
+// well-known fields of a vtadmin request. If recurse is true, the fields of a
+// nested "request" message (as used by the vtadmin requests that wrap a
+// vtctldata request) are also inspected.
+func (entry *Entry) setTargetFromMessage(msg protoreflect.Message, recurse bool) {
+	fields := msg.Descriptor().Fields()
+
+	getString := func(name protoreflect.Name) string {
+		fd := fields.ByName(name)
+		if fd == nil || fd.Kind() != protoreflect.StringKind || fd.IsList() {
+			return ""
+		}
+
+		return msg.Get(fd).String()
+	}
+
+	setIfEmpty := func(dst *string, val string) {
+		if *dst == "" {
+			*dst = val
+		}
+	}
+
+	if id := getString("cluster_id"); id != "" {
+		entry.ClusterIDs = append(entry.ClusterIDs, id)
+	}
+
+	if fd := fields.ByName("cluster_ids"); fd != nil && fd.IsList() && fd.Kind() == protoreflect.StringKind {
+		list := msg.Get(fd).List()
+		for i := 0; i < list.Len(); i++ {
+			entry.ClusterIDs = append(entry.ClusterIDs, list.Get(i).String())
+		}
+	}
+
+	setIfEmpty(&entry.Keyspace, getString("keyspace"))
+	setIfEmpty(&entry.Keyspace, getString("target_keyspace"))
+	setIfEmpty(&entry.Shard, getString("shard"))
+	setIfEmpty(&entry.Workflow, getString("workflow"))
+
+	for _, name := range []protoreflect.Name{"alias", "tablet_alias"} {
+		fd := fields.ByName(name)
+		if fd == nil || fd.Kind() != protoreflect.MessageKind || fd.IsList() || !msg.Has(fd) {
+			continue
+		}
+
+		if alias, ok := msg.Get(fd).Message().Interface().(*topodatapb.TabletAlias); ok {
+			setIfEmpty(&entry.Tablet, topoproto.TabletAliasString(alias))
+		}
+	}
+
+	if !recurse {
+		return
+	}
+
+	if fd := fields.ByName("request"); fd != nil && fd.Kind() == protoreflect.MessageKind && !fd.IsList() && msg.Has(fd) {
+		entry.setTargetFromMessage(msg.Get(fd).Message(), false)
+	}
+}
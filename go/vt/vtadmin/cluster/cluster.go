@@ -49,6 +49,8 @@ import (
 	"vitess.io/vitess/go/vt/vtadmin/vtsql"
 	"vitess.io/vitess/go/vt/vtctl/schematools"
 
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	vtadminpb "vitess.io/vitess/go/vt/proto/vtadmin"
 	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
@@ -2080,6 +2082,44 @@ func (c *Cluster) LaunchSchemaMigration(ctx context.Context, req *vtctldatapb.La
 	return c.Vtctld.LaunchSchemaMigration(ctx, req)
 }
 
+// MaterializeCreate creates a Materialize workflow in this cluster.
+func (c *Cluster) MaterializeCreate(ctx context.Context, req *vtctldatapb.MaterializeCreateRequest) (*vtctldatapb.MaterializeCreateResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "Cluster.MaterializeCreate")
+	defer span.Finish()
+
+	AnnotateSpan(c, span)
+	span.Annotate("workflow", req.Settings.GetWorkflow())
+	span.Annotate("target_keyspace", req.Settings.GetTargetKeyspace())
+
+	return c.Vtctld.MaterializeCreate(ctx, req)
+}
+
+// MoveTablesComplete completes a MoveTables workflow in this cluster.
+func (c *Cluster) MoveTablesComplete(ctx context.Context, req *vtctldatapb.MoveTablesCompleteRequest) (*vtctldatapb.MoveTablesCompleteResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "Cluster.MoveTablesComplete")
+	defer span.Finish()
+
+	AnnotateSpan(c, span)
+	span.Annotate("workflow", req.Workflow)
+	span.Annotate("target_keyspace", req.TargetKeyspace)
+	span.Annotate("dry_run", req.DryRun)
+
+	return c.Vtctld.MoveTablesComplete(ctx, req)
+}
+
+// MoveTablesCreate creates a MoveTables workflow in this cluster.
+func (c *Cluster) MoveTablesCreate(ctx context.Context, req *vtctldatapb.MoveTablesCreateRequest) (*vtctldatapb.WorkflowStatusResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "Cluster.MoveTablesCreate")
+	defer span.Finish()
+
+	AnnotateSpan(c, span)
+	span.Annotate("workflow", req.Workflow)
+	span.Annotate("source_keyspace", req.SourceKeyspace)
+	span.Annotate("target_keyspace", req.TargetKeyspace)
+
+	return c.Vtctld.MoveTablesCreate(ctx, req)
+}
+
 // PlannedFailoverShard fails over the shard either to a new primary or away
 // from an old primary. Both the current and candidate primaries must be
 // reachable and running.
@@ -2421,6 +2461,18 @@ func (c *Cluster) reloadTabletSchemas(ctx context.Context, req *vtadminpb.Reload
 	return results, nil
 }
 
+// ReshardCreate creates a Reshard workflow in this cluster.
+func (c *Cluster) ReshardCreate(ctx context.Context, req *vtctldatapb.ReshardCreateRequest) (*vtctldatapb.WorkflowStatusResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "Cluster.ReshardCreate")
+	defer span.Finish()
+
+	AnnotateSpan(c, span)
+	span.Annotate("workflow", req.Workflow)
+	span.Annotate("keyspace", req.Keyspace)
+
+	return c.Vtctld.ReshardCreate(ctx, req)
+}
+
 // RetrySchemaMigration retries a schema migration in the given keyspace in
 // this cluster.
 func (c *Cluster) RetrySchemaMigration(ctx context.Context, req *vtctldatapb.RetrySchemaMigrationRequest) (*vtctldatapb.RetrySchemaMigrationResponse, error) {
@@ -2448,6 +2500,30 @@ func (c *Cluster) SetWritable(ctx context.Context, req *vtctldatapb.SetWritableR
 	return err
 }
 
+// SetWorkflowState starts or stops a workflow in this cluster, depending on
+// the given state, leaving the rest of its configuration untouched.
+func (c *Cluster) SetWorkflowState(ctx context.Context, keyspace string, workflow string, state binlogdatapb.VReplicationWorkflowState) (*vtctldatapb.WorkflowUpdateResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "Cluster.SetWorkflowState")
+	defer span.Finish()
+
+	AnnotateSpan(c, span)
+	span.Annotate("keyspace", keyspace)
+	span.Annotate("workflow", workflow)
+	span.Annotate("state", state.String())
+
+	// The only thing we're updating is the state.
+	return c.Vtctld.WorkflowUpdate(ctx, &vtctldatapb.WorkflowUpdateRequest{
+		Keyspace: keyspace,
+		TabletRequest: &tabletmanagerdatapb.UpdateVReplicationWorkflowRequest{
+			Workflow:    workflow,
+			Cells:       textutil.SimulatedNullStringSlice,
+			TabletTypes: []topodatapb.TabletType{topodatapb.TabletType(textutil.SimulatedNullInt)},
+			OnDdl:       binlogdatapb.OnDDLAction(textutil.SimulatedNullInt),
+			State:       state,
+		},
+	})
+}
+
 // TabletExternallyPromoted updates the topo record for a shard to reflect a
 // tablet that was promoted to primary external to Vitess (e.g. orchestrator).
 func (c *Cluster) TabletExternallyPromoted(ctx context.Context, tablet *vtadminpb.Tablet) (*vtadminpb.TabletExternallyPromotedResponse, error) {
@@ -2502,6 +2578,59 @@ func (c *Cluster) ToggleTabletReplication(ctx context.Context, tablet *vtadminpb
 	return err
 }
 
+// VDiffCreate creates a VDiff of a workflow in this cluster.
+func (c *Cluster) VDiffCreate(ctx context.Context, req *vtctldatapb.VDiffCreateRequest) (*vtctldatapb.VDiffCreateResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "Cluster.VDiffCreate")
+	defer span.Finish()
+
+	AnnotateSpan(c, span)
+	span.Annotate("workflow", req.Workflow)
+	span.Annotate("target_keyspace", req.TargetKeyspace)
+
+	return c.Vtctld.VDiffCreate(ctx, req)
+}
+
+// VDiffShow returns the VDiffs of a workflow in this cluster.
+func (c *Cluster) VDiffShow(ctx context.Context, req *vtctldatapb.VDiffShowRequest) (*vtctldatapb.VDiffShowResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "Cluster.VDiffShow")
+	defer span.Finish()
+
+	AnnotateSpan(c, span)
+	span.Annotate("workflow", req.Workflow)
+	span.Annotate("target_keyspace", req.TargetKeyspace)
+	span.Annotate("arg", req.Arg)
+
+	return c.Vtctld.VDiffShow(ctx, req)
+}
+
+// WorkflowDelete deletes a workflow in this cluster.
+func (c *Cluster) WorkflowDelete(ctx context.Context, req *vtctldatapb.WorkflowDeleteRequest) (*vtctldatapb.WorkflowDeleteResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "Cluster.WorkflowDelete")
+	defer span.Finish()
+
+	AnnotateSpan(c, span)
+	span.Annotate("workflow", req.Workflow)
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("keep_data", req.KeepData)
+	span.Annotate("keep_routing_rules", req.KeepRoutingRules)
+
+	return c.Vtctld.WorkflowDelete(ctx, req)
+}
+
+// WorkflowSwitchTraffic switches the traffic of a workflow in this cluster.
+func (c *Cluster) WorkflowSwitchTraffic(ctx context.Context, req *vtctldatapb.WorkflowSwitchTrafficRequest) (*vtctldatapb.WorkflowSwitchTrafficResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "Cluster.WorkflowSwitchTraffic")
+	defer span.Finish()
+
+	AnnotateSpan(c, span)
+	span.Annotate("workflow", req.Workflow)
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("direction", req.Direction)
+	span.Annotate("dry_run", req.DryRun)
+
+	return c.Vtctld.WorkflowSwitchTraffic(ctx, req)
+}
+
 // Debug returns a map of debug information for a cluster.
 func (c *Cluster) Debug() map[string]any {
 	m := map[string]any{
@@ -19,10 +19,14 @@ package http
 import (
 	"context"
 	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
 
 	"vitess.io/vitess/go/sets"
 	"vitess.io/vitess/go/trace"
 	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/vtadmin/audit"
 	"vitess.io/vitess/go/vt/vtadmin/cache"
 	"vitess.io/vitess/go/vt/vtadmin/rbac"
 
@@ -43,7 +47,10 @@ type Options struct {
 	DisableCompression bool
 	// DisableDebug specifies whether to omit the /debug/pprof/* and /debug/env
 	// routes.
-	DisableDebug        bool
+	DisableDebug bool
+	// AuditLog, if non-nil, records an entry for every call to a mutating API
+	// endpoint.
+	AuditLog            *audit.Log
 	ExperimentalOptions struct {
 		TabletURLTmpl string
 	}
@@ -89,8 +96,63 @@ func (api *API) Adapt(handler VTAdminHandler) http.HandlerFunc {
 		// Transform any ?cluster query params to ?cluster_id.
 		deprecateQueryParam(r, "cluster_id", "cluster")
 
-		handler(ctx, Request{r}, api).Write(w)
+		resp := handler(ctx, Request{r}, api)
+		if api.opts.AuditLog != nil {
+			api.audit(r, actor, resp)
+		}
+
+		resp.Write(w)
+	}
+}
+
+// audit records an audit log entry for the request, if its route is a
+// mutating one.
+func (api *API) audit(r *http.Request, actor *rbac.Actor, resp *JSONResponse) {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return
+	}
+
+	method := strings.TrimPrefix(route.GetName(), "API.")
+	if method == "" || !audit.IsMutating(method) {
+		return
+	}
+
+	entry := &audit.Entry{
+		Actor:     actor,
+		Method:    method,
+		Transport: audit.TransportHTTP,
+		Success:   resp.Ok,
+	}
+
+	if resp.Error != nil {
+		entry.Error = resp.Error.Message
+	}
+
+	vars := mux.Vars(r)
+	if id, ok := vars["cluster_id"]; ok {
+		entry.ClusterIDs = []string{id}
+	} else {
+		entry.ClusterIDs = r.URL.Query()["cluster_id"]
 	}
+
+	entry.Keyspace = vars["keyspace"]
+	entry.Shard = vars["shard"]
+	entry.Tablet = vars["tablet"]
+
+	// The {name} path variable refers to a keyspace or a workflow, depending
+	// on the route.
+	if name, ok := vars["name"]; ok {
+		tmpl, _ := route.GetPathTemplate()
+		switch {
+		case strings.Contains(tmpl, "/keyspace/"):
+			entry.Keyspace = name
+		case strings.Contains(tmpl, "/workflow/"), strings.Contains(tmpl, "/vdiff/"):
+			entry.Workflow = name
+		}
+	}
+
+	api.opts.AuditLog.Record(entry)
 }
 
 // Options returns a copy of the Options this API was configured with.
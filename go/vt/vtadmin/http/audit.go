@@ -0,0 +1,61 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package http
+
+import (
+	"context"
+	"fmt"
+
+	"vitess.io/vitess/go/vt/vtadmin/audit"
+	"vitess.io/vitess/go/vt/vtadmin/errors"
+)
+
+// AuditLogServer is implemented by VTAdminServers that keep an audit log of
+// mutating API calls.
+type AuditLogServer interface {
+	// GetAuditLog returns the most recent audit log entries, most recent
+	// first, that the actor in the context is permitted to see. If limit is
+	// positive, at most limit entries are returned. If clusterIDs is
+	// non-empty, only entries for those clusters are returned.
+	GetAuditLog(ctx context.Context, limit int, clusterIDs []string) ([]*audit.Entry, error)
+}
+
+// GetAuditLog implements the http wrapper for /audit[?cluster_id=&limit=].
+func GetAuditLog(ctx context.Context, r Request, api *API) *JSONResponse {
+	server, ok := api.server.(AuditLogServer)
+	if !ok {
+		return NewJSONResponse(nil, &errors.Internal{
+			Err: fmt.Errorf("%T does not keep an audit log", api.server),
+		})
+	}
+
+	limit, err := r.ParseQueryParamAsUint32("limit", 0)
+	if err != nil {
+		return NewJSONResponse(nil, err)
+	}
+
+	entries, err := server.GetAuditLog(ctx, int(limit), r.URL.Query()["cluster_id"])
+	if err != nil {
+		return NewJSONResponse(nil, err)
+	}
+
+	return NewJSONResponse(struct {
+		Entries []*audit.Entry `json:"entries"`
+	}{
+		Entries: entries,
+	}, nil)
+}
@@ -18,8 +18,14 @@ package http
 
 import (
 	"context"
+	"encoding/json"
+	"io"
+
+	"vitess.io/vitess/go/vt/vtadmin/errors"
+	"vitess.io/vitess/go/vt/vtctl/workflow"
 
 	vtadminpb "vitess.io/vitess/go/vt/proto/vtadmin"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
 )
 
 // GetWorkflow implements the http wrapper for the VTAdminServer.GetWorkflow
@@ -69,3 +75,247 @@ func GetWorkflows(ctx context.Context, r Request, api *API) *JSONResponse {
 
 	return NewJSONResponse(workflows, err)
 }
+
+// MaterializeCreate implements the http wrapper for
+// POST /workflow/{cluster_id}/materialize.
+func MaterializeCreate(ctx context.Context, r Request, api *API) *JSONResponse {
+	decoder := json.NewDecoder(r.Body)
+	defer r.Body.Close()
+
+	var req vtctldatapb.MaterializeCreateRequest
+	if err := decoder.Decode(&req); err != nil {
+		return NewJSONResponse(nil, &errors.BadRequest{
+			Err: err,
+		})
+	}
+
+	resp, err := api.server.MaterializeCreate(ctx, &vtadminpb.MaterializeCreateRequest{
+		ClusterId: r.Vars()["cluster_id"],
+		Request:   &req,
+	})
+
+	return NewJSONResponse(resp, err)
+}
+
+// MoveTablesComplete implements the http wrapper for
+// POST /workflow/{cluster_id}/{keyspace}/{name}/complete.
+//
+// The keyspace is the target keyspace of the MoveTables workflow. The body may
+// be empty, or contain any of the other MoveTablesCompleteRequest options
+// (keep_data, keep_routing_rules, rename_tables, dry_run).
+func MoveTablesComplete(ctx context.Context, r Request, api *API) *JSONResponse {
+	var req vtctldatapb.MoveTablesCompleteRequest
+	if err := decodeOptionalBody(r, &req); err != nil {
+		return NewJSONResponse(nil, err)
+	}
+
+	vars := r.Vars()
+	req.TargetKeyspace = vars["keyspace"]
+	req.Workflow = vars["name"]
+
+	resp, err := api.server.MoveTablesComplete(ctx, &vtadminpb.MoveTablesCompleteRequest{
+		ClusterId: vars["cluster_id"],
+		Request:   &req,
+	})
+
+	return NewJSONResponse(resp, err)
+}
+
+// MoveTablesCreate implements the http wrapper for
+// POST /workflow/{cluster_id}/movetables.
+func MoveTablesCreate(ctx context.Context, r Request, api *API) *JSONResponse {
+	decoder := json.NewDecoder(r.Body)
+	defer r.Body.Close()
+
+	var req vtctldatapb.MoveTablesCreateRequest
+	if err := decoder.Decode(&req); err != nil {
+		return NewJSONResponse(nil, &errors.BadRequest{
+			Err: err,
+		})
+	}
+
+	resp, err := api.server.MoveTablesCreate(ctx, &vtadminpb.MoveTablesCreateRequest{
+		ClusterId: r.Vars()["cluster_id"],
+		Request:   &req,
+	})
+
+	return NewJSONResponse(resp, err)
+}
+
+// ReshardCreate implements the http wrapper for
+// POST /workflow/{cluster_id}/reshard.
+func ReshardCreate(ctx context.Context, r Request, api *API) *JSONResponse {
+	decoder := json.NewDecoder(r.Body)
+	defer r.Body.Close()
+
+	var req vtctldatapb.ReshardCreateRequest
+	if err := decoder.Decode(&req); err != nil {
+		return NewJSONResponse(nil, &errors.BadRequest{
+			Err: err,
+		})
+	}
+
+	resp, err := api.server.ReshardCreate(ctx, &vtadminpb.ReshardCreateRequest{
+		ClusterId: r.Vars()["cluster_id"],
+		Request:   &req,
+	})
+
+	return NewJSONResponse(resp, err)
+}
+
+// StartWorkflow implements the http wrapper for
+// PUT /workflow/{cluster_id}/{keyspace}/{name}/start.
+func StartWorkflow(ctx context.Context, r Request, api *API) *JSONResponse {
+	vars := r.Vars()
+
+	resp, err := api.server.StartWorkflow(ctx, &vtadminpb.StartWorkflowRequest{
+		ClusterId: vars["cluster_id"],
+		Keyspace:  vars["keyspace"],
+		Workflow:  vars["name"],
+	})
+
+	return NewJSONResponse(resp, err)
+}
+
+// StopWorkflow implements the http wrapper for
+// PUT /workflow/{cluster_id}/{keyspace}/{name}/stop.
+func StopWorkflow(ctx context.Context, r Request, api *API) *JSONResponse {
+	vars := r.Vars()
+
+	resp, err := api.server.StopWorkflow(ctx, &vtadminpb.StopWorkflowRequest{
+		ClusterId: vars["cluster_id"],
+		Keyspace:  vars["keyspace"],
+		Workflow:  vars["name"],
+	})
+
+	return NewJSONResponse(resp, err)
+}
+
+// VDiffCreate implements the http wrapper for
+// POST /vdiff/{cluster_id}.
+func VDiffCreate(ctx context.Context, r Request, api *API) *JSONResponse {
+	decoder := json.NewDecoder(r.Body)
+	defer r.Body.Close()
+
+	var req vtctldatapb.VDiffCreateRequest
+	if err := decoder.Decode(&req); err != nil {
+		return NewJSONResponse(nil, &errors.BadRequest{
+			Err: err,
+		})
+	}
+
+	resp, err := api.server.VDiffCreate(ctx, &vtadminpb.VDiffCreateRequest{
+		ClusterId: r.Vars()["cluster_id"],
+		Request:   &req,
+	})
+
+	return NewJSONResponse(resp, err)
+}
+
+// VDiffShow implements the http wrapper for
+// GET /vdiff/{cluster_id}/{keyspace}/{name}[?arg=].
+//
+// The arg query param is one of "last", "all", or a vdiff UUID, and defaults
+// to "last".
+func VDiffShow(ctx context.Context, r Request, api *API) *JSONResponse {
+	vars := r.Vars()
+
+	arg := r.URL.Query().Get("arg")
+	if arg == "" {
+		arg = "last"
+	}
+
+	resp, err := api.server.VDiffShow(ctx, &vtadminpb.VDiffShowRequest{
+		ClusterId: vars["cluster_id"],
+		Request: &vtctldatapb.VDiffShowRequest{
+			TargetKeyspace: vars["keyspace"],
+			Workflow:       vars["name"],
+			Arg:            arg,
+		},
+	})
+
+	return NewJSONResponse(resp, err)
+}
+
+// WorkflowDelete implements the http wrapper for
+// DELETE /workflow/{cluster_id}/{keyspace}/{name}[?keep_data=&keep_routing_rules=].
+func WorkflowDelete(ctx context.Context, r Request, api *API) *JSONResponse {
+	vars := r.Vars()
+
+	keepData, err := r.ParseQueryParamAsBool("keep_data", false)
+	if err != nil {
+		return NewJSONResponse(nil, err)
+	}
+
+	keepRoutingRules, err := r.ParseQueryParamAsBool("keep_routing_rules", false)
+	if err != nil {
+		return NewJSONResponse(nil, err)
+	}
+
+	resp, err := api.server.WorkflowDelete(ctx, &vtadminpb.WorkflowDeleteRequest{
+		ClusterId: vars["cluster_id"],
+		Request: &vtctldatapb.WorkflowDeleteRequest{
+			Keyspace:         vars["keyspace"],
+			Workflow:         vars["name"],
+			KeepData:         keepData,
+			KeepRoutingRules: keepRoutingRules,
+		},
+	})
+
+	return NewJSONResponse(resp, err)
+}
+
+// WorkflowSwitchTraffic implements the http wrapper for
+// POST /workflow/{cluster_id}/{keyspace}/{name}/switchtraffic.
+//
+// The body may be empty, or contain any of the other
+// WorkflowSwitchTrafficRequest options. The direction is always forward; use
+// WorkflowReverseTraffic to switch traffic back to the source keyspace.
+func WorkflowSwitchTraffic(ctx context.Context, r Request, api *API) *JSONResponse {
+	return switchTraffic(ctx, r, api, workflow.DirectionForward)
+}
+
+// WorkflowReverseTraffic implements the http wrapper for
+// POST /workflow/{cluster_id}/{keyspace}/{name}/reversetraffic.
+//
+// It calls VTAdminServer.WorkflowSwitchTraffic with a backward direction.
+func WorkflowReverseTraffic(ctx context.Context, r Request, api *API) *JSONResponse {
+	return switchTraffic(ctx, r, api, workflow.DirectionBackward)
+}
+
+func switchTraffic(ctx context.Context, r Request, api *API, direction workflow.TrafficSwitchDirection) *JSONResponse {
+	var req vtctldatapb.WorkflowSwitchTrafficRequest
+	if err := decodeOptionalBody(r, &req); err != nil {
+		return NewJSONResponse(nil, err)
+	}
+
+	vars := r.Vars()
+	req.Keyspace = vars["keyspace"]
+	req.Workflow = vars["name"]
+	req.Direction = int32(direction)
+
+	resp, err := api.server.WorkflowSwitchTraffic(ctx, &vtadminpb.WorkflowSwitchTrafficRequest{
+		ClusterId: vars["cluster_id"],
+		Request:   &req,
+	})
+
+	return NewJSONResponse(resp, err)
+}
+
+// decodeOptionalBody decodes the JSON request body into v, treating an empty
+// body as a request with all options unset.
+func decodeOptionalBody(r Request, v any) error {
+	if r.Body == nil {
+		return nil
+	}
+
+	defer r.Body.Close()
+
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil && err != io.EOF {
+		return &errors.BadRequest{
+			Err: err,
+		}
+	}
+
+	return nil
+}
@@ -32,7 +32,10 @@ import (
 // cfg.Reify. A config must be reified before first use.
 type Config struct {
 	Authenticator string
-	Rules         []*struct {
+	// JWT configures the built-in JWT authenticator. It is only used when
+	// Authenticator is set to "jwt".
+	JWT   *JWTConfig
+	Rules []*struct {
 		Resource string
 		Actions  []string
 		Subjects []string
@@ -134,6 +137,13 @@ func (c *Config) Reify() error {
 			return err
 		}
 
+		c.authenticator = authn
+	case c.Authenticator == JWTAuthenticatorName:
+		authn, err := NewJWTAuthenticator(c.JWT)
+		if err != nil {
+			return err
+		}
+
 		c.authenticator = authn
 	case c.Authenticator != "":
 		factory, ok := authenticators[c.Authenticator]
@@ -181,6 +191,9 @@ func DefaultConfig() *Config {
 		string(ManageTabletReplicationAction),
 		string(ManageTabletWritabilityAction),
 		string(RefreshTabletReplicationSourceAction),
+		string(CompleteWorkflowAction),
+		string(ManageWorkflowAction),
+		string(SwitchWorkflowTrafficAction),
 	}
 	subjects := []string{"*"}
 	clusters := []string{"*"}
@@ -0,0 +1,672 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rbac
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+
+	"vitess.io/vitess/go/vt/log"
+)
+
+// JWTAuthenticatorName is the name to use in the Authenticator field of an RBAC
+// config to select the built-in JWT authenticator. Its settings are read from
+// the JWT section of the config.
+const JWTAuthenticatorName = "jwt"
+
+const (
+	defaultJWTClockSkew              = 30 * time.Second
+	defaultJWKSRefreshInterval       = time.Hour
+	defaultJWTNameClaim              = "sub"
+	minJWKSRefreshInterval           = 30 * time.Second
+	jwksFetchTimeout                 = 10 * time.Second
+	authorizationHeader              = "authorization"
+	bearerTokenPrefix                = "bearer "
+	maxJWKSResponseSize        int64 = 1 << 20
+)
+
+var (
+	// ErrMissingBearerToken is returned by the JWT authenticator when a request
+	// does not carry an "Authorization: Bearer <token>" header.
+	ErrMissingBearerToken = errors.New("missing bearer token")
+	// ErrInvalidToken is returned by the JWT authenticator when a token is
+	// malformed, has an invalid signature, or fails claim validation.
+	ErrInvalidToken = errors.New("invalid token")
+)
+
+// JWTConfig configures the built-in JWT authenticator.
+//
+// Tokens are validated against the keys in a JSON Web Key Set, which is read
+// either from a local file or from a URL (typically an OIDC provider's
+// jwks_uri). Exactly one of JWKSFile and JWKSURL must be set. Issuer and
+// Audiences are required, so tokens issued by the same provider for other
+// applications are not accepted.
+//
+// Validated tokens are mapped to an Actor, whose name is taken from NameClaim
+// and whose roles are taken from RolesClaim. Claim names may use dots to refer
+// to nested claims (e.g. "realm_access.roles"). If RoleMappings is non-empty,
+// only claim values present in the mapping produce roles, and each produces
+// the roles it maps to.
+//
+// For example, to authenticate users of an OIDC provider and grant members of
+// its "db-oncall" group the "dba" role:
+//
+//	authenticator: jwt
+//	jwt:
+//	  issuer: https://accounts.example.com
+//	  audiences: ["vtadmin"]
+//	  jwks_url: https://accounts.example.com/.well-known/jwks.json
+//	  name_claim: email
+//	  roles_claim: groups
+//	  role_mappings:
+//	    db-oncall: ["dba"]
+type JWTConfig struct {
+	Issuer              string              `mapstructure:"issuer"`
+	Audiences           []string            `mapstructure:"audiences"`
+	JWKSFile            string              `mapstructure:"jwks_file"`
+	JWKSURL             string              `mapstructure:"jwks_url"`
+	JWKSRefreshInterval time.Duration       `mapstructure:"jwks_refresh_interval"`
+	NameClaim           string              `mapstructure:"name_claim"`
+	RolesClaim          string              `mapstructure:"roles_claim"`
+	RoleMappings        map[string][]string `mapstructure:"role_mappings"`
+	ClockSkew           time.Duration       `mapstructure:"clock_skew"`
+}
+
+// JWTAuthenticator is an Authenticator that validates bearer JWTs, such as the
+// ID or access tokens issued by an OIDC provider.
+type JWTAuthenticator struct {
+	cfg  JWTConfig
+	keys *jwks
+
+	now func() time.Time
+}
+
+var _ Authenticator = (*JWTAuthenticator)(nil)
+
+// NewJWTAuthenticator returns a JWTAuthenticator for the given config. It
+// returns an error if the config is invalid or if the initial load of the key
+// set fails.
+func NewJWTAuthenticator(cfg *JWTConfig) (*JWTAuthenticator, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("%s authenticator requires a jwt config section", JWTAuthenticatorName)
+	}
+
+	c := *cfg
+	switch {
+	case c.JWKSFile == "" && c.JWKSURL == "":
+		return nil, errors.New("jwt config must specify one of jwks_file or jwks_url")
+	case c.JWKSFile != "" && c.JWKSURL != "":
+		return nil, errors.New("jwt config cannot specify both jwks_file and jwks_url")
+	}
+
+	if c.Issuer == "" {
+		return nil, errors.New("jwt config must specify an issuer")
+	}
+
+	if len(c.Audiences) == 0 {
+		return nil, errors.New("jwt config must specify at least one audience")
+	}
+
+	if c.NameClaim == "" {
+		c.NameClaim = defaultJWTNameClaim
+	}
+
+	if c.ClockSkew == 0 {
+		c.ClockSkew = defaultJWTClockSkew
+	}
+
+	if c.JWKSRefreshInterval == 0 {
+		c.JWKSRefreshInterval = defaultJWKSRefreshInterval
+	}
+
+	keys := &jwks{
+		refreshInterval: c.JWKSRefreshInterval,
+		now:             time.Now,
+	}
+	if c.JWKSFile != "" {
+		keys.fetch = func() ([]byte, error) { return os.ReadFile(c.JWKSFile) }
+	} else {
+		keys.fetch = func() ([]byte, error) { return fetchJWKS(c.JWKSURL) }
+	}
+
+	if err := keys.refresh(); err != nil {
+		return nil, fmt.Errorf("failed to load jwks: %w", err)
+	}
+
+	return &JWTAuthenticator{
+		cfg:  c,
+		keys: keys,
+		now:  time.Now,
+	}, nil
+}
+
+// Authenticate is part of the Authenticator interface. It reads the token from
+// the "authorization" key of the incoming grpc metadata.
+func (authn *JWTAuthenticator) Authenticate(ctx context.Context) (*Actor, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	var header string
+	if vals := md.Get(authorizationHeader); len(vals) > 0 {
+		header = vals[0]
+	}
+
+	return authn.authenticate(header)
+}
+
+// AuthenticateHTTP is part of the Authenticator interface. It reads the token
+// from the Authorization header of the request.
+func (authn *JWTAuthenticator) AuthenticateHTTP(r *http.Request) (*Actor, error) {
+	return authn.authenticate(r.Header.Get(authorizationHeader))
+}
+
+func (authn *JWTAuthenticator) authenticate(header string) (*Actor, error) {
+	if len(header) <= len(bearerTokenPrefix) || !strings.EqualFold(header[:len(bearerTokenPrefix)], bearerTokenPrefix) {
+		return nil, ErrMissingBearerToken
+	}
+
+	claims, err := authn.verify(strings.TrimSpace(header[len(bearerTokenPrefix):]))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidToken, err)
+	}
+
+	return authn.actorFromClaims(claims)
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// verify checks the signature and the registered claims of a compact-serialized
+// JWT, returning its claims if the token is valid.
+func (authn *JWTAuthenticator) verify(token string) (map[string]any, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("token must have three parts")
+	}
+
+	var header jwtHeader
+	if err := decodeJWTSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("failed to decode header: %w", err)
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode signature: %w", err)
+	}
+
+	keys, err := authn.keys.lookup(header.Kid)
+	if err != nil {
+		return nil, err
+	}
+
+	signed := []byte(parts[0] + "." + parts[1])
+
+	var verified bool
+	for _, key := range keys {
+		if err := verifyJWTSignature(header.Alg, key, signed, sig); err == nil {
+			verified = true
+			break
+		} else if errors.Is(err, errUnsupportedAlg) {
+			return nil, err
+		}
+	}
+
+	if !verified {
+		return nil, errors.New("signature verification failed")
+	}
+
+	var claims map[string]any
+	if err := decodeJWTSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("failed to decode claims: %w", err)
+	}
+
+	if err := authn.validateClaims(claims); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+func (authn *JWTAuthenticator) validateClaims(claims map[string]any) error {
+	now := authn.now()
+
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return errors.New("missing exp claim")
+	}
+
+	if now.After(time.Unix(int64(exp), 0).Add(authn.cfg.ClockSkew)) {
+		return errors.New("token is expired")
+	}
+
+	if nbf, ok := claims["nbf"].(float64); ok && now.Add(authn.cfg.ClockSkew).Before(time.Unix(int64(nbf), 0)) {
+		return errors.New("token is not valid yet")
+	}
+
+	if iss, _ := claims["iss"].(string); iss != authn.cfg.Issuer {
+		return fmt.Errorf("unexpected issuer %q", iss)
+	}
+
+	for _, aud := range claimStrings(claims["aud"]) {
+		for _, want := range authn.cfg.Audiences {
+			if aud == want {
+				return nil
+			}
+		}
+	}
+
+	return errors.New("token is not intended for this audience")
+}
+
+func (authn *JWTAuthenticator) actorFromClaims(claims map[string]any) (*Actor, error) {
+	name, ok := lookupClaim(claims, authn.cfg.NameClaim).(string)
+	if !ok || name == "" {
+		return nil, fmt.Errorf("%w: missing %s claim", ErrInvalidToken, authn.cfg.NameClaim)
+	}
+
+	actor := &Actor{Name: name}
+	if authn.cfg.RolesClaim == "" {
+		return actor, nil
+	}
+
+	for _, val := range claimStrings(lookupClaim(claims, authn.cfg.RolesClaim)) {
+		if len(authn.cfg.RoleMappings) == 0 {
+			actor.Roles = append(actor.Roles, val)
+			continue
+		}
+
+		actor.Roles = append(actor.Roles, authn.cfg.RoleMappings[val]...)
+	}
+
+	return actor, nil
+}
+
+// lookupClaim returns the value of the (possibly nested, dot-separated) claim,
+// or nil if it does not exist.
+func lookupClaim(claims map[string]any, name string) any {
+	var val any = claims
+	for _, part := range strings.Split(name, ".") {
+		m, ok := val.(map[string]any)
+		if !ok {
+			return nil
+		}
+
+		val = m[part]
+	}
+
+	return val
+}
+
+// claimStrings returns a claim that is either a single string or an array of
+// strings as a slice. Non-string values are ignored.
+func claimStrings(val any) []string {
+	switch val := val.(type) {
+	case string:
+		return []string{val}
+	case []any:
+		strs := make([]string, 0, len(val))
+		for _, v := range val {
+			if s, ok := v.(string); ok {
+				strs = append(strs, s)
+			}
+		}
+
+		return strs
+	default:
+		return nil
+	}
+}
+
+func decodeJWTSegment(seg string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+var errUnsupportedAlg = errors.New("unsupported signing algorithm")
+
+// ecdsaAlgCurves maps the "ES*" JWS algorithms to the only curve each of them
+// may be used with (RFC 7518, section 3.4).
+var ecdsaAlgCurves = map[string]string{
+	"ES256": "P-256",
+	"ES384": "P-384",
+	"ES512": "P-521",
+}
+
+// verifyJWTSignature verifies sig over signed using the given public key and
+// JWS algorithm. Symmetric ("HS*") and "none" algorithms are never accepted.
+func verifyJWTSignature(alg string, key crypto.PublicKey, signed []byte, sig []byte) error {
+	var hash crypto.Hash
+	switch alg {
+	case "RS256", "PS256", "ES256":
+		hash = crypto.SHA256
+	case "RS384", "PS384", "ES384":
+		hash = crypto.SHA384
+	case "RS512", "PS512", "ES512":
+		hash = crypto.SHA512
+	default:
+		return fmt.Errorf("%w %q", errUnsupportedAlg, alg)
+	}
+
+	h := hash.New()
+	h.Write(signed)
+	digest := h.Sum(nil)
+
+	switch alg[:2] {
+	case "RS":
+		pub, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return errors.New("key type does not match algorithm")
+		}
+
+		return rsa.VerifyPKCS1v15(pub, hash, digest, sig)
+	case "PS":
+		pub, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return errors.New("key type does not match algorithm")
+		}
+
+		return rsa.VerifyPSS(pub, hash, digest, sig, nil)
+	default: // "ES"
+		pub, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return errors.New("key type does not match algorithm")
+		}
+
+		if pub.Curve.Params().Name != ecdsaAlgCurves[alg] {
+			return errors.New("key curve does not match algorithm")
+		}
+
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		if len(sig) != 2*size {
+			return errors.New("invalid ecdsa signature length")
+		}
+
+		r := new(big.Int).SetBytes(sig[:size])
+		s := new(big.Int).SetBytes(sig[size:])
+		if !ecdsa.Verify(pub, digest, r, s) {
+			return errors.New("ecdsa signature verification failed")
+		}
+
+		return nil
+	}
+}
+
+// jwks is a JSON Web Key Set that is lazily refreshed from its source, both
+// periodically and when a token references an unknown key ID.
+type jwks struct {
+	fetch           func() ([]byte, error)
+	refreshInterval time.Duration
+	now             func() time.Time
+
+	m          sync.Mutex
+	keys       map[string]crypto.PublicKey
+	unnamed    []crypto.PublicKey
+	fetchedAt  time.Time
+	refreshing bool
+}
+
+// lookup returns the candidate keys for a token with the given key ID. If kid
+// is empty, all keys in the set are candidates.
+func (ks *jwks) lookup(kid string) ([]crypto.PublicKey, error) {
+	if ks.shouldRefresh(kid) {
+		if err := ks.refresh(); err != nil {
+			// Keep serving the keys we have; the source may be briefly
+			// unavailable.
+			log.Warningf("[rbac]: failed to refresh jwks: %s", err)
+		}
+	}
+
+	ks.m.Lock()
+	defer ks.m.Unlock()
+
+	if kid != "" {
+		key, ok := ks.keys[kid]
+		if !ok {
+			return nil, fmt.Errorf("unknown key id %q", kid)
+		}
+
+		return []crypto.PublicKey{key}, nil
+	}
+
+	keys := make([]crypto.PublicKey, 0, len(ks.keys)+len(ks.unnamed))
+	for _, key := range ks.keys {
+		keys = append(keys, key)
+	}
+
+	return append(keys, ks.unnamed...), nil
+}
+
+// shouldRefresh returns whether the caller should refresh the key set before
+// looking up the given key ID, in which case the caller owns the refresh until
+// it calls refresh. The key set is refreshed when it is older than the refresh
+// interval, or when kid is unknown, but then at most once per
+// minJWKSRefreshInterval, so tokens with made up key IDs cannot be used to
+// hammer the source. Only one refresh runs at a time; the other callers keep
+// using the current keys in the meantime.
+func (ks *jwks) shouldRefresh(kid string) bool {
+	ks.m.Lock()
+	defer ks.m.Unlock()
+
+	if ks.refreshing {
+		return false
+	}
+
+	age := ks.now().Sub(ks.fetchedAt)
+	_, known := ks.keys[kid]
+
+	if age > ks.refreshInterval || (kid != "" && !known && age > minJWKSRefreshInterval) {
+		ks.refreshing = true
+		return true
+	}
+
+	return false
+}
+
+// refresh fetches and parses the key set from its source, without holding the
+// lock, and then replaces the current keys with it.
+func (ks *jwks) refresh() error {
+	ks.m.Lock()
+	// Record the attempt even if it fails, so a broken source is not hammered
+	// on every request.
+	ks.fetchedAt = ks.now()
+	ks.m.Unlock()
+
+	data, err := ks.fetch()
+
+	var (
+		keys    map[string]crypto.PublicKey
+		unnamed []crypto.PublicKey
+	)
+	if err == nil {
+		keys, unnamed, err = parseJWKS(data)
+	}
+
+	ks.m.Lock()
+	defer ks.m.Unlock()
+
+	ks.refreshing = false
+	if err != nil {
+		return err
+	}
+
+	ks.keys = keys
+	ks.unnamed = unnamed
+	return nil
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// parseJWKS parses the RSA and EC signing keys in a JSON Web Key Set, returning
+// the keys with a key ID separately from those without. Keys of other types are
+// ignored.
+func parseJWKS(data []byte) (map[string]crypto.PublicKey, []crypto.PublicKey, error) {
+	var set struct {
+		Keys []*jsonWebKey `json:"keys"`
+	}
+
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse jwks: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	var unnamed []crypto.PublicKey
+
+	for i, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		var (
+			key crypto.PublicKey
+			err error
+		)
+		switch jwk.Kty {
+		case "RSA":
+			key, err = jwk.rsaPublicKey()
+		case "EC":
+			key, err = jwk.ecdsaPublicKey()
+		default:
+			continue
+		}
+
+		if err != nil {
+			return nil, nil, fmt.Errorf("jwks key %d (kid=%q): %w", i, jwk.Kid, err)
+		}
+
+		if jwk.Kid == "" {
+			unnamed = append(unnamed, key)
+			continue
+		}
+
+		keys[jwk.Kid] = key
+	}
+
+	if len(keys) == 0 && len(unnamed) == 0 {
+		return nil, nil, errors.New("jwks contains no usable signing keys")
+	}
+
+	return keys, unnamed, nil
+}
+
+func (jwk *jsonWebKey) rsaPublicKey() (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+	if err != nil {
+		return nil, fmt.Errorf("invalid modulus: %w", err)
+	}
+
+	e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+	if err != nil {
+		return nil, fmt.Errorf("invalid exponent: %w", err)
+	}
+
+	exp := new(big.Int).SetBytes(e)
+	if !exp.IsInt64() || exp.Int64() > 1<<31-1 || exp.Int64() < 2 {
+		return nil, errors.New("invalid exponent")
+	}
+
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: int(exp.Int64()),
+	}, nil
+}
+
+func (jwk *jsonWebKey) ecdsaPublicKey() (*ecdsa.PublicKey, error) {
+	var curve elliptic.Curve
+	switch jwk.Crv {
+	case "P-256":
+		curve = elliptic.P256()
+	case "P-384":
+		curve = elliptic.P384()
+	case "P-521":
+		curve = elliptic.P521()
+	default:
+		return nil, fmt.Errorf("unsupported curve %q", jwk.Crv)
+	}
+
+	x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+	if err != nil {
+		return nil, fmt.Errorf("invalid x coordinate: %w", err)
+	}
+
+	y, err := base64.RawURLEncoding.DecodeString(jwk.Y)
+	if err != nil {
+		return nil, fmt.Errorf("invalid y coordinate: %w", err)
+	}
+
+	pub := &ecdsa.PublicKey{
+		Curve: curve,
+		X:     new(big.Int).SetBytes(x),
+		Y:     new(big.Int).SetBytes(y),
+	}
+	if !curve.IsOnCurve(pub.X, pub.Y) {
+		return nil, errors.New("point is not on curve")
+	}
+
+	return pub, nil
+}
+
+func fetchJWKS(url string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), jwksFetchTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching %s: unexpected status %s", url, resp.Status)
+	}
+
+	return io.ReadAll(io.LimitReader(resp.Body, maxJWKSResponseSize))
+}
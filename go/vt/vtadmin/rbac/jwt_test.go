@@ -0,0 +1,601 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rbac
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+)
+
+type jwtTestKeys struct {
+	rsa   *rsa.PrivateKey
+	ecdsa *ecdsa.PrivateKey
+}
+
+func newJWTTestKeys(t *testing.T) *jwtTestKeys {
+	t.Helper()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	return &jwtTestKeys{rsa: rsaKey, ecdsa: ecKey}
+}
+
+func (keys *jwtTestKeys) jwks(t *testing.T) []byte {
+	t.Helper()
+
+	enc := base64.RawURLEncoding
+	data, err := json.Marshal(map[string]any{
+		"keys": []map[string]string{
+			{
+				"kty": "RSA",
+				"kid": "rsa1",
+				"use": "sig",
+				"n":   enc.EncodeToString(keys.rsa.N.Bytes()),
+				"e":   enc.EncodeToString(big.NewInt(int64(keys.rsa.E)).Bytes()),
+			},
+			{
+				"kty": "EC",
+				"kid": "ec1",
+				"crv": "P-256",
+				"x":   enc.EncodeToString(keys.ecdsa.X.FillBytes(make([]byte, 32))),
+				"y":   enc.EncodeToString(keys.ecdsa.Y.FillBytes(make([]byte, 32))),
+			},
+			{
+				"kty": "oct",
+				"kid": "ignored",
+				"k":   "c2VjcmV0",
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	return data
+}
+
+func (keys *jwtTestKeys) sign(t *testing.T, alg string, kid string, claims map[string]any) string {
+	t.Helper()
+
+	enc := base64.RawURLEncoding
+
+	header, err := json.Marshal(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"})
+	require.NoError(t, err)
+
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+
+	signed := enc.EncodeToString(header) + "." + enc.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signed))
+
+	var sig []byte
+	switch alg {
+	case "RS256":
+		sig, err = rsa.SignPKCS1v15(rand.Reader, keys.rsa, crypto.SHA256, digest[:])
+		require.NoError(t, err)
+	case "ES256":
+		r, s, err := ecdsa.Sign(rand.Reader, keys.ecdsa, digest[:])
+		require.NoError(t, err)
+
+		sig = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	default:
+		sig = []byte("not-a-signature")
+	}
+
+	return signed + "." + enc.EncodeToString(sig)
+}
+
+func writeJWKSFile(t *testing.T, data []byte) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+
+	return path
+}
+
+func TestJWTAuthenticator(t *testing.T) {
+	t.Parallel()
+
+	keys := newJWTTestKeys(t)
+	now := time.Now()
+
+	authn, err := NewJWTAuthenticator(&JWTConfig{
+		Issuer:     "https://issuer.example.com",
+		Audiences:  []string{"vtadmin"},
+		JWKSFile:   writeJWKSFile(t, keys.jwks(t)),
+		NameClaim:  "email",
+		RolesClaim: "realm_access.roles",
+	})
+	require.NoError(t, err)
+
+	validClaims := func() map[string]any {
+		return map[string]any{
+			"iss":   "https://issuer.example.com",
+			"aud":   []string{"other", "vtadmin"},
+			"exp":   now.Add(time.Hour).Unix(),
+			"iat":   now.Unix(),
+			"email": "dba@example.com",
+			"realm_access": map[string]any{
+				"roles": []string{"dba", "oncall"},
+			},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		alg       string
+		kid       string
+		claims    func(claims map[string]any)
+		header    func(token string) string
+		expected  *Actor
+		shouldErr bool
+	}{
+		{
+			name:     "rsa",
+			alg:      "RS256",
+			kid:      "rsa1",
+			expected: &Actor{Name: "dba@example.com", Roles: []string{"dba", "oncall"}},
+		},
+		{
+			name:     "ecdsa",
+			alg:      "ES256",
+			kid:      "ec1",
+			expected: &Actor{Name: "dba@example.com", Roles: []string{"dba", "oncall"}},
+		},
+		{
+			name:     "no kid",
+			alg:      "RS256",
+			expected: &Actor{Name: "dba@example.com", Roles: []string{"dba", "oncall"}},
+		},
+		{
+			name: "single audience",
+			alg:  "RS256",
+			kid:  "rsa1",
+			claims: func(claims map[string]any) {
+				claims["aud"] = "vtadmin"
+			},
+			expected: &Actor{Name: "dba@example.com", Roles: []string{"dba", "oncall"}},
+		},
+		{
+			name: "lowercase bearer",
+			alg:  "RS256",
+			kid:  "rsa1",
+			header: func(token string) string {
+				return "bearer " + token
+			},
+			expected: &Actor{Name: "dba@example.com", Roles: []string{"dba", "oncall"}},
+		},
+		{
+			name: "expired",
+			alg:  "RS256",
+			kid:  "rsa1",
+			claims: func(claims map[string]any) {
+				claims["exp"] = now.Add(-time.Hour).Unix()
+			},
+			shouldErr: true,
+		},
+		{
+			name: "missing exp",
+			alg:  "RS256",
+			kid:  "rsa1",
+			claims: func(claims map[string]any) {
+				delete(claims, "exp")
+			},
+			shouldErr: true,
+		},
+		{
+			name: "not yet valid",
+			alg:  "RS256",
+			kid:  "rsa1",
+			claims: func(claims map[string]any) {
+				claims["nbf"] = now.Add(time.Hour).Unix()
+			},
+			shouldErr: true,
+		},
+		{
+			name: "wrong issuer",
+			alg:  "RS256",
+			kid:  "rsa1",
+			claims: func(claims map[string]any) {
+				claims["iss"] = "https://evil.example.com"
+			},
+			shouldErr: true,
+		},
+		{
+			name: "wrong audience",
+			alg:  "RS256",
+			kid:  "rsa1",
+			claims: func(claims map[string]any) {
+				claims["aud"] = "someone-else"
+			},
+			shouldErr: true,
+		},
+		{
+			name: "missing name claim",
+			alg:  "RS256",
+			kid:  "rsa1",
+			claims: func(claims map[string]any) {
+				delete(claims, "email")
+			},
+			shouldErr: true,
+		},
+		{
+			name:      "unknown kid",
+			alg:       "RS256",
+			kid:       "rsa2",
+			shouldErr: true,
+		},
+		{
+			name:      "key type does not match alg",
+			alg:       "RS256",
+			kid:       "ec1",
+			shouldErr: true,
+		},
+		{
+			name:      "symmetric alg",
+			alg:       "HS256",
+			kid:       "ignored",
+			shouldErr: true,
+		},
+		{
+			name:      "none alg",
+			alg:       "none",
+			shouldErr: true,
+		},
+		{
+			name: "tampered payload",
+			alg:  "RS256",
+			kid:  "rsa1",
+			header: func(token string) string {
+				parts := strings.Split(token, ".")
+				parts[1] = base64.RawURLEncoding.EncodeToString([]byte(`{"email":"admin@example.com"}`))
+				return "Bearer " + strings.Join(parts, ".")
+			},
+			shouldErr: true,
+		},
+		{
+			name: "missing token",
+			header: func(token string) string {
+				return ""
+			},
+			shouldErr: true,
+		},
+		{
+			name: "basic auth",
+			header: func(token string) string {
+				return "Basic dXNlcjpwYXNz"
+			},
+			shouldErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			claims := validClaims()
+			if tt.claims != nil {
+				tt.claims(claims)
+			}
+
+			token := keys.sign(t, tt.alg, tt.kid, claims)
+			header := "Bearer " + token
+			if tt.header != nil {
+				header = tt.header(token)
+			}
+
+			r := httptest.NewRequest(http.MethodGet, "/api/clusters", nil)
+			r.Header.Set("Authorization", header)
+
+			actor, err := authn.AuthenticateHTTP(r)
+			if tt.shouldErr {
+				assert.Error(t, err)
+				assert.Nil(t, actor)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected, actor)
+			}
+
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", header))
+			grpcActor, grpcErr := authn.Authenticate(ctx)
+			assert.Equal(t, actor, grpcActor, "grpc and http authentication should agree")
+			assert.Equal(t, err != nil, grpcErr != nil, "grpc and http authentication should agree")
+		})
+	}
+}
+
+func TestJWTAuthenticatorRoleMappings(t *testing.T) {
+	t.Parallel()
+
+	keys := newJWTTestKeys(t)
+	authn, err := NewJWTAuthenticator(&JWTConfig{
+		Issuer:     "https://issuer.example.com",
+		Audiences:  []string{"vtadmin"},
+		JWKSFile:   writeJWKSFile(t, keys.jwks(t)),
+		RolesClaim: "groups",
+		RoleMappings: map[string][]string{
+			"db-team": {"dba", "dev"},
+		},
+	})
+	require.NoError(t, err)
+
+	token := keys.sign(t, "ES256", "ec1", map[string]any{
+		"iss":    "https://issuer.example.com",
+		"aud":    "vtadmin",
+		"sub":    "user1",
+		"exp":    time.Now().Add(time.Minute).Unix(),
+		"groups": []string{"db-team", "unmapped"},
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "/api/clusters", nil)
+	r.Header.Set("Authorization", "Bearer "+token)
+
+	actor, err := authn.AuthenticateHTTP(r)
+	require.NoError(t, err)
+	assert.Equal(t, &Actor{Name: "user1", Roles: []string{"dba", "dev"}}, actor)
+}
+
+func TestJWTAuthenticatorJWKSURL(t *testing.T) {
+	t.Parallel()
+
+	oldKeys := newJWTTestKeys(t)
+	newKeys := newJWTTestKeys(t)
+
+	var jwks atomic.Pointer[[]byte]
+	data := oldKeys.jwks(t)
+	jwks.Store(&data)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(*jwks.Load())
+	}))
+	defer server.Close()
+
+	authn, err := NewJWTAuthenticator(&JWTConfig{
+		Issuer:    "https://issuer.example.com",
+		Audiences: []string{"vtadmin"},
+		JWKSURL:   server.URL,
+	})
+	require.NoError(t, err)
+
+	claims := map[string]any{
+		"iss": "https://issuer.example.com",
+		"aud": "vtadmin",
+		"sub": "user1",
+		"exp": time.Now().Add(time.Minute).Unix(),
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/api/clusters", nil)
+	r.Header.Set("Authorization", "Bearer "+oldKeys.sign(t, "RS256", "rsa1", claims))
+	_, err = authn.AuthenticateHTTP(r)
+	require.NoError(t, err)
+
+	// Rotate the keys. Tokens signed by the new key are rejected until the
+	// key set is refreshed, which happens periodically.
+	data = newKeys.jwks(t)
+	jwks.Store(&data)
+	r.Header.Set("Authorization", "Bearer "+newKeys.sign(t, "RS256", "rsa1", claims))
+	_, err = authn.AuthenticateHTTP(r)
+	assert.Error(t, err)
+
+	authn.keys.now = func() time.Time { return time.Now().Add(2 * defaultJWKSRefreshInterval) }
+	_, err = authn.AuthenticateHTTP(r)
+	assert.NoError(t, err)
+}
+
+func TestNewJWTAuthenticatorErrors(t *testing.T) {
+	t.Parallel()
+
+	keys := newJWTTestKeys(t)
+	path := writeJWKSFile(t, keys.jwks(t))
+
+	tests := []struct {
+		name string
+		cfg  *JWTConfig
+	}{
+		{
+			name: "nil config",
+		},
+		{
+			name: "no jwks",
+			cfg:  &JWTConfig{},
+		},
+		{
+			name: "both jwks sources",
+			cfg:  &JWTConfig{JWKSFile: path, JWKSURL: "https://example.com/jwks"},
+		},
+		{
+			name: "no issuer",
+			cfg:  &JWTConfig{Audiences: []string{"vtadmin"}, JWKSFile: path},
+		},
+		{
+			name: "no audiences",
+			cfg:  &JWTConfig{Issuer: "https://issuer.example.com", JWKSFile: path},
+		},
+		{
+			name: "missing jwks file",
+			cfg: &JWTConfig{
+				Issuer:    "https://issuer.example.com",
+				Audiences: []string{"vtadmin"},
+				JWKSFile:  filepath.Join(t.TempDir(), "nope.json"),
+			},
+		},
+		{
+			name: "no usable keys",
+			cfg: &JWTConfig{
+				Issuer:    "https://issuer.example.com",
+				Audiences: []string{"vtadmin"},
+				JWKSFile:  writeJWKSFile(t, []byte(`{"keys":[{"kty":"oct","k":"c2VjcmV0"}]}`)),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewJWTAuthenticator(tt.cfg)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestVerifyJWTSignatureECDSACurve(t *testing.T) {
+	t.Parallel()
+
+	key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	signed := []byte("header.payload")
+	digest := sha256.Sum256(signed)
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	require.NoError(t, err)
+
+	sig := append(r.FillBytes(make([]byte, 48)), s.FillBytes(make([]byte, 48))...)
+
+	// ES256 must only be used with P-256 keys, even though the signature over
+	// the SHA-256 digest is otherwise valid.
+	assert.Error(t, verifyJWTSignature("ES256", &key.PublicKey, signed, sig))
+}
+
+func TestJWKSRefresh(t *testing.T) {
+	t.Parallel()
+
+	keys := newJWTTestKeys(t)
+	data := keys.jwks(t)
+
+	var fetches atomic.Int32
+	release := make(chan struct{})
+	now := time.Now()
+
+	ks := &jwks{
+		refreshInterval: defaultJWKSRefreshInterval,
+		now:             func() time.Time { return now },
+	}
+	ks.fetch = func() ([]byte, error) {
+		fetches.Add(1)
+		return data, nil
+	}
+	require.NoError(t, ks.refresh())
+	require.EqualValues(t, 1, fetches.Load())
+
+	// Unknown key IDs do not trigger a refetch until minJWKSRefreshInterval
+	// has passed since the last one.
+	_, err := ks.lookup("unknown")
+	assert.Error(t, err)
+	assert.EqualValues(t, 1, fetches.Load())
+
+	now = now.Add(2 * minJWKSRefreshInterval)
+	_, err = ks.lookup("unknown")
+	assert.Error(t, err)
+	assert.EqualValues(t, 2, fetches.Load())
+
+	_, err = ks.lookup("unknown")
+	assert.Error(t, err)
+	assert.EqualValues(t, 2, fetches.Load())
+
+	// Only one refetch runs at a time, and it does not block the lookups of
+	// the known keys.
+	ks.fetch = func() ([]byte, error) {
+		fetches.Add(1)
+		<-release
+		return data, nil
+	}
+	now = now.Add(2 * defaultJWKSRefreshInterval)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, err := ks.lookup("rsa1")
+		assert.NoError(t, err)
+	}()
+
+	require.Eventually(t, func() bool { return fetches.Load() == 3 }, 5*time.Second, time.Millisecond)
+
+	_, err = ks.lookup("ec1")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3, fetches.Load())
+
+	close(release)
+	<-done
+}
+
+func TestLoadConfigJWT(t *testing.T) {
+	t.Parallel()
+
+	keys := newJWTTestKeys(t)
+	dir := t.TempDir()
+	jwksPath := filepath.Join(dir, "jwks.json")
+	require.NoError(t, os.WriteFile(jwksPath, keys.jwks(t), 0o600))
+
+	cfgPath := filepath.Join(dir, "rbac.yaml")
+	require.NoError(t, os.WriteFile(cfgPath, []byte(`authenticator: jwt
+jwt:
+  issuer: https://issuer.example.com
+  audiences: ["vtadmin"]
+  jwks_file: `+jwksPath+`
+  jwks_refresh_interval: 5m
+  roles_claim: groups
+  clock_skew: 1m
+rules:
+  - resource: "*"
+    actions: ["*"]
+    subjects: ["role:dba"]
+    clusters: ["*"]
+`), 0o600))
+
+	cfg, err := LoadConfig(cfgPath)
+	require.NoError(t, err)
+
+	authn, ok := cfg.GetAuthenticator().(*JWTAuthenticator)
+	require.True(t, ok, "expected a *JWTAuthenticator, got %T", cfg.GetAuthenticator())
+	assert.Equal(t, JWTConfig{
+		Issuer:              "https://issuer.example.com",
+		Audiences:           []string{"vtadmin"},
+		JWKSFile:            jwksPath,
+		JWKSRefreshInterval: 5 * time.Minute,
+		NameClaim:           "sub",
+		RolesClaim:          "groups",
+		ClockSkew:           time.Minute,
+	}, authn.cfg)
+}
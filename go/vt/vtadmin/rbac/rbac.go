@@ -93,6 +93,12 @@ const (
 	ManageTabletReplicationAction        Action = "manage_tablet_replication" // Start/Stop Replication
 	ManageTabletWritabilityAction        Action = "manage_tablet_writability" // SetRead{Only,Write}
 	RefreshTabletReplicationSourceAction Action = "refresh_tablet_replication_source"
+
+	/* workflow-specific actions */
+
+	CompleteWorkflowAction      Action = "complete_workflow"
+	ManageWorkflowAction        Action = "manage_workflow" // Start/Stop
+	SwitchWorkflowTrafficAction Action = "switch_workflow_traffic"
 )
 
 // Resource is an enum representing all resources managed by vtadmin.
@@ -131,9 +137,12 @@ const (
 
 	BackupResource                   Resource = "Backup"
 	ShardReplicationPositionResource Resource = "ShardReplicationPosition"
+	VDiffResource                    Resource = "VDiff"
 	WorkflowResource                 Resource = "Workflow"
 
 	VTExplainResource Resource = "VTExplain"
 
 	TabletFullStatusResource Resource = "TabletFullStatus"
+
+	AuditLogResource Resource = "AuditLog"
 )
@@ -117,6 +117,18 @@ type VtctldClient struct {
 		Response *vtctldatapb.LaunchSchemaMigrationResponse
 		Error    error
 	}
+	MaterializeCreateResults map[string]struct {
+		Response *vtctldatapb.MaterializeCreateResponse
+		Error    error
+	}
+	MoveTablesCompleteResults map[string]struct {
+		Response *vtctldatapb.MoveTablesCompleteResponse
+		Error    error
+	}
+	MoveTablesCreateResults map[string]struct {
+		Response *vtctldatapb.WorkflowStatusResponse
+		Error    error
+	}
 	PingTabletResults           map[string]error
 	PlannedReparentShardResults map[string]struct {
 		Response *vtctldatapb.PlannedReparentShardResponse
@@ -139,6 +151,10 @@ type VtctldClient struct {
 		Response *vtctldatapb.ReparentTabletResponse
 		Error    error
 	}
+	ReshardCreateResults map[string]struct {
+		Response *vtctldatapb.WorkflowStatusResponse
+		Error    error
+	}
 	RetrySchemaMigrationResults map[string]struct {
 		Response *vtctldatapb.RetrySchemaMigrationResponse
 		Error    error
@@ -155,6 +171,14 @@ type VtctldClient struct {
 		Response *vtctldatapb.TabletExternallyReparentedResponse
 		Error    error
 	}
+	VDiffCreateResults map[string]struct {
+		Response *vtctldatapb.VDiffCreateResponse
+		Error    error
+	}
+	VDiffShowResults map[string]struct {
+		Response *vtctldatapb.VDiffShowResponse
+		Error    error
+	}
 	ValidateKeyspaceResults map[string]struct {
 		Response *vtctldatapb.ValidateKeyspaceResponse
 		Error    error
@@ -167,6 +191,14 @@ type VtctldClient struct {
 		Response *vtctldatapb.ValidateVersionKeyspaceResponse
 		Error    error
 	}
+	WorkflowDeleteResults map[string]struct {
+		Response *vtctldatapb.WorkflowDeleteResponse
+		Error    error
+	}
+	WorkflowSwitchTrafficResults map[string]struct {
+		Response *vtctldatapb.WorkflowSwitchTrafficResponse
+		Error    error
+	}
 	WorkflowUpdateResults map[string]struct {
 		Response *vtctldatapb.WorkflowUpdateResponse
 		Error    error
@@ -545,6 +577,45 @@ func (fake *VtctldClient) LaunchSchemaMigration(ctx context.Context, req *vtctld
 	return nil, fmt.Errorf("%w: no result set for %s", assert.AnError, key)
 }
 
+// MaterializeCreate is part of the vtctldclient.VtctldClient interface.
+func (fake *VtctldClient) MaterializeCreate(ctx context.Context, req *vtctldatapb.MaterializeCreateRequest, opts ...grpc.CallOption) (*vtctldatapb.MaterializeCreateResponse, error) {
+	if fake.MaterializeCreateResults == nil {
+		return nil, fmt.Errorf("%w: MaterializeCreateResults not set on fake vtctldclient", assert.AnError)
+	}
+
+	if result, ok := fake.MaterializeCreateResults[req.Settings.GetTargetKeyspace()]; ok {
+		return result.Response, result.Error
+	}
+
+	return nil, fmt.Errorf("%w: no result set for keyspace %s", assert.AnError, req.Settings.GetTargetKeyspace())
+}
+
+// MoveTablesComplete is part of the vtctldclient.VtctldClient interface.
+func (fake *VtctldClient) MoveTablesComplete(ctx context.Context, req *vtctldatapb.MoveTablesCompleteRequest, opts ...grpc.CallOption) (*vtctldatapb.MoveTablesCompleteResponse, error) {
+	if fake.MoveTablesCompleteResults == nil {
+		return nil, fmt.Errorf("%w: MoveTablesCompleteResults not set on fake vtctldclient", assert.AnError)
+	}
+
+	if result, ok := fake.MoveTablesCompleteResults[req.TargetKeyspace]; ok {
+		return result.Response, result.Error
+	}
+
+	return nil, fmt.Errorf("%w: no result set for keyspace %s", assert.AnError, req.TargetKeyspace)
+}
+
+// MoveTablesCreate is part of the vtctldclient.VtctldClient interface.
+func (fake *VtctldClient) MoveTablesCreate(ctx context.Context, req *vtctldatapb.MoveTablesCreateRequest, opts ...grpc.CallOption) (*vtctldatapb.WorkflowStatusResponse, error) {
+	if fake.MoveTablesCreateResults == nil {
+		return nil, fmt.Errorf("%w: MoveTablesCreateResults not set on fake vtctldclient", assert.AnError)
+	}
+
+	if result, ok := fake.MoveTablesCreateResults[req.TargetKeyspace]; ok {
+		return result.Response, result.Error
+	}
+
+	return nil, fmt.Errorf("%w: no result set for keyspace %s", assert.AnError, req.TargetKeyspace)
+}
+
 // PingTablet is part of the vtctldclient.VtctldClient interface.
 func (fake *VtctldClient) PingTablet(ctx context.Context, req *vtctldatapb.PingTabletRequest, opts ...grpc.CallOption) (*vtctldatapb.PingTabletResponse, error) {
 	if fake.PingTabletResults == nil {
@@ -650,6 +721,19 @@ func (fake *VtctldClient) ReparentTablet(ctx context.Context, req *vtctldatapb.R
 	return nil, fmt.Errorf("%w: no result set for %s", assert.AnError, key)
 }
 
+// ReshardCreate is part of the vtctldclient.VtctldClient interface.
+func (fake *VtctldClient) ReshardCreate(ctx context.Context, req *vtctldatapb.ReshardCreateRequest, opts ...grpc.CallOption) (*vtctldatapb.WorkflowStatusResponse, error) {
+	if fake.ReshardCreateResults == nil {
+		return nil, fmt.Errorf("%w: ReshardCreateResults not set on fake vtctldclient", assert.AnError)
+	}
+
+	if result, ok := fake.ReshardCreateResults[req.Keyspace]; ok {
+		return result.Response, result.Error
+	}
+
+	return nil, fmt.Errorf("%w: no result set for keyspace %s", assert.AnError, req.Keyspace)
+}
+
 // RetrySchemaMigration is part of the vtctldclient.VtctldClient interface.
 func (fake *VtctldClient) RetrySchemaMigration(ctx context.Context, req *vtctldatapb.RetrySchemaMigrationRequest, opts ...grpc.CallOption) (*vtctldatapb.RetrySchemaMigrationResponse, error) {
 	if fake.RetrySchemaMigrationResults == nil {
@@ -765,6 +849,32 @@ func (fake *VtctldClient) TabletExternallyReparented(ctx context.Context, req *v
 	return nil, fmt.Errorf("%w: no result set for %s", assert.AnError, key)
 }
 
+// VDiffCreate is part of the vtctldclient.VtctldClient interface.
+func (fake *VtctldClient) VDiffCreate(ctx context.Context, req *vtctldatapb.VDiffCreateRequest, opts ...grpc.CallOption) (*vtctldatapb.VDiffCreateResponse, error) {
+	if fake.VDiffCreateResults == nil {
+		return nil, fmt.Errorf("%w: VDiffCreateResults not set on fake vtctldclient", assert.AnError)
+	}
+
+	if result, ok := fake.VDiffCreateResults[req.TargetKeyspace]; ok {
+		return result.Response, result.Error
+	}
+
+	return nil, fmt.Errorf("%w: no result set for keyspace %s", assert.AnError, req.TargetKeyspace)
+}
+
+// VDiffShow is part of the vtctldclient.VtctldClient interface.
+func (fake *VtctldClient) VDiffShow(ctx context.Context, req *vtctldatapb.VDiffShowRequest, opts ...grpc.CallOption) (*vtctldatapb.VDiffShowResponse, error) {
+	if fake.VDiffShowResults == nil {
+		return nil, fmt.Errorf("%w: VDiffShowResults not set on fake vtctldclient", assert.AnError)
+	}
+
+	if result, ok := fake.VDiffShowResults[req.TargetKeyspace]; ok {
+		return result.Response, result.Error
+	}
+
+	return nil, fmt.Errorf("%w: no result set for keyspace %s", assert.AnError, req.TargetKeyspace)
+}
+
 // ValidateKeyspace is part of the vtctldclient.VtctldClient interface.
 func (fake *VtctldClient) ValidateKeyspace(ctx context.Context, req *vtctldatapb.ValidateKeyspaceRequest, opts ...grpc.CallOption) (*vtctldatapb.ValidateKeyspaceResponse, error) {
 	if fake.ValidateKeyspaceResults == nil {
@@ -807,6 +917,32 @@ func (fake *VtctldClient) ValidateVersionKeyspace(ctx context.Context, req *vtct
 	return nil, fmt.Errorf("%w: no result set for %s", assert.AnError, key)
 }
 
+// WorkflowDelete is part of the vtctldclient.VtctldClient interface.
+func (fake *VtctldClient) WorkflowDelete(ctx context.Context, req *vtctldatapb.WorkflowDeleteRequest, opts ...grpc.CallOption) (*vtctldatapb.WorkflowDeleteResponse, error) {
+	if fake.WorkflowDeleteResults == nil {
+		return nil, fmt.Errorf("%w: WorkflowDeleteResults not set on fake vtctldclient", assert.AnError)
+	}
+
+	if result, ok := fake.WorkflowDeleteResults[req.Keyspace]; ok {
+		return result.Response, result.Error
+	}
+
+	return nil, fmt.Errorf("%w: no result set for keyspace %s", assert.AnError, req.Keyspace)
+}
+
+// WorkflowSwitchTraffic is part of the vtctldclient.VtctldClient interface.
+func (fake *VtctldClient) WorkflowSwitchTraffic(ctx context.Context, req *vtctldatapb.WorkflowSwitchTrafficRequest, opts ...grpc.CallOption) (*vtctldatapb.WorkflowSwitchTrafficResponse, error) {
+	if fake.WorkflowSwitchTrafficResults == nil {
+		return nil, fmt.Errorf("%w: WorkflowSwitchTrafficResults not set on fake vtctldclient", assert.AnError)
+	}
+
+	if result, ok := fake.WorkflowSwitchTrafficResults[req.Keyspace]; ok {
+		return result.Response, result.Error
+	}
+
+	return nil, fmt.Errorf("%w: no result set for keyspace %s", assert.AnError, req.Keyspace)
+}
+
 // WorkflowUpdate is part of the vtctldclient.VtctldClient interface.
 func (fake *VtctldClient) WorkflowUpdate(ctx context.Context, req *vtctldatapb.WorkflowUpdateRequest, opts ...grpc.CallOption) (*vtctldatapb.WorkflowUpdateResponse, error) {
 	if fake.WorkflowUpdateResults == nil {
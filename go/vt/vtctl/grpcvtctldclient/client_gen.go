@@ -47,6 +47,15 @@ func (client *gRPCVtctldClient) AddCellsAlias(ctx context.Context, in *vtctldata
 	return client.c.AddCellsAlias(ctx, in, opts...)
 }
 
+// ApplyQueryRules is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) ApplyQueryRules(ctx context.Context, in *vtctldatapb.ApplyQueryRulesRequest, opts ...grpc.CallOption) (*vtctldatapb.ApplyQueryRulesResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.ApplyQueryRules(ctx, in, opts...)
+}
+
 // ApplyRoutingRules is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) ApplyRoutingRules(ctx context.Context, in *vtctldatapb.ApplyRoutingRulesRequest, opts ...grpc.CallOption) (*vtctldatapb.ApplyRoutingRulesResponse, error) {
 	if client.c == nil {
@@ -74,6 +83,15 @@ func (client *gRPCVtctldClient) ApplyShardRoutingRules(ctx context.Context, in *
 	return client.c.ApplyShardRoutingRules(ctx, in, opts...)
 }
 
+// ApplyTenantQuotas is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) ApplyTenantQuotas(ctx context.Context, in *vtctldatapb.ApplyTenantQuotasRequest, opts ...grpc.CallOption) (*vtctldatapb.ApplyTenantQuotasResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.ApplyTenantQuotas(ctx, in, opts...)
+}
+
 // ApplyVSchema is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) ApplyVSchema(ctx context.Context, in *vtctldatapb.ApplyVSchemaRequest, opts ...grpc.CallOption) (*vtctldatapb.ApplyVSchemaResponse, error) {
 	if client.c == nil {
@@ -101,6 +119,15 @@ func (client *gRPCVtctldClient) BackupShard(ctx context.Context, in *vtctldatapb
 	return client.c.BackupShard(ctx, in, opts...)
 }
 
+// CancelDMLJob is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) CancelDMLJob(ctx context.Context, in *vtctldatapb.CancelDMLJobRequest, opts ...grpc.CallOption) (*vtctldatapb.CancelDMLJobResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.CancelDMLJob(ctx, in, opts...)
+}
+
 // CancelSchemaMigration is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) CancelSchemaMigration(ctx context.Context, in *vtctldatapb.CancelSchemaMigrationRequest, opts ...grpc.CallOption) (*vtctldatapb.CancelSchemaMigrationResponse, error) {
 	if client.c == nil {
@@ -119,6 +146,15 @@ func (client *gRPCVtctldClient) ChangeTabletType(ctx context.Context, in *vtctld
 	return client.c.ChangeTabletType(ctx, in, opts...)
 }
 
+// ChangeVindexCreate is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) ChangeVindexCreate(ctx context.Context, in *vtctldatapb.ChangeVindexCreateRequest, opts ...grpc.CallOption) (*vtctldatapb.ChangeVindexCreateResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.ChangeVindexCreate(ctx, in, opts...)
+}
+
 // CleanupSchemaMigration is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) CleanupSchemaMigration(ctx context.Context, in *vtctldatapb.CleanupSchemaMigrationRequest, opts ...grpc.CallOption) (*vtctldatapb.CleanupSchemaMigrationResponse, error) {
 	if client.c == nil {
@@ -182,6 +218,24 @@ func (client *gRPCVtctldClient) DeleteKeyspace(ctx context.Context, in *vtctldat
 	return client.c.DeleteKeyspace(ctx, in, opts...)
 }
 
+// DeletePartitionLifecycle is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) DeletePartitionLifecycle(ctx context.Context, in *vtctldatapb.DeletePartitionLifecycleRequest, opts ...grpc.CallOption) (*vtctldatapb.DeletePartitionLifecycleResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.DeletePartitionLifecycle(ctx, in, opts...)
+}
+
+// DeleteQueryRule is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) DeleteQueryRule(ctx context.Context, in *vtctldatapb.DeleteQueryRuleRequest, opts ...grpc.CallOption) (*vtctldatapb.DeleteQueryRuleResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.DeleteQueryRule(ctx, in, opts...)
+}
+
 // DeleteShards is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) DeleteShards(ctx context.Context, in *vtctldatapb.DeleteShardsRequest, opts ...grpc.CallOption) (*vtctldatapb.DeleteShardsResponse, error) {
 	if client.c == nil {
@@ -308,6 +362,15 @@ func (client *gRPCVtctldClient) GetCellsAliases(ctx context.Context, in *vtctlda
 	return client.c.GetCellsAliases(ctx, in, opts...)
 }
 
+// GetDMLJobs is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) GetDMLJobs(ctx context.Context, in *vtctldatapb.GetDMLJobsRequest, opts ...grpc.CallOption) (*vtctldatapb.GetDMLJobsResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.GetDMLJobs(ctx, in, opts...)
+}
+
 // GetFullStatus is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) GetFullStatus(ctx context.Context, in *vtctldatapb.GetFullStatusRequest, opts ...grpc.CallOption) (*vtctldatapb.GetFullStatusResponse, error) {
 	if client.c == nil {
@@ -335,6 +398,15 @@ func (client *gRPCVtctldClient) GetKeyspaces(ctx context.Context, in *vtctldatap
 	return client.c.GetKeyspaces(ctx, in, opts...)
 }
 
+// GetPartitionLifecycles is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) GetPartitionLifecycles(ctx context.Context, in *vtctldatapb.GetPartitionLifecyclesRequest, opts ...grpc.CallOption) (*vtctldatapb.GetPartitionLifecyclesResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.GetPartitionLifecycles(ctx, in, opts...)
+}
+
 // GetPermissions is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) GetPermissions(ctx context.Context, in *vtctldatapb.GetPermissionsRequest, opts ...grpc.CallOption) (*vtctldatapb.GetPermissionsResponse, error) {
 	if client.c == nil {
@@ -344,6 +416,15 @@ func (client *gRPCVtctldClient) GetPermissions(ctx context.Context, in *vtctldat
 	return client.c.GetPermissions(ctx, in, opts...)
 }
 
+// GetQueryRules is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) GetQueryRules(ctx context.Context, in *vtctldatapb.GetQueryRulesRequest, opts ...grpc.CallOption) (*vtctldatapb.GetQueryRulesResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.GetQueryRules(ctx, in, opts...)
+}
+
 // GetRoutingRules is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) GetRoutingRules(ctx context.Context, in *vtctldatapb.GetRoutingRulesRequest, opts ...grpc.CallOption) (*vtctldatapb.GetRoutingRulesResponse, error) {
 	if client.c == nil {
@@ -362,6 +443,15 @@ func (client *gRPCVtctldClient) GetSchema(ctx context.Context, in *vtctldatapb.G
 	return client.c.GetSchema(ctx, in, opts...)
 }
 
+// GetSchemaHistory is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) GetSchemaHistory(ctx context.Context, in *vtctldatapb.GetSchemaHistoryRequest, opts ...grpc.CallOption) (*vtctldatapb.GetSchemaHistoryResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.GetSchemaHistory(ctx, in, opts...)
+}
+
 // GetSchemaMigrations is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) GetSchemaMigrations(ctx context.Context, in *vtctldatapb.GetSchemaMigrationsRequest, opts ...grpc.CallOption) (*vtctldatapb.GetSchemaMigrationsResponse, error) {
 	if client.c == nil {
@@ -452,6 +542,15 @@ func (client *gRPCVtctldClient) GetTablets(ctx context.Context, in *vtctldatapb.
 	return client.c.GetTablets(ctx, in, opts...)
 }
 
+// GetTenantQuotas is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) GetTenantQuotas(ctx context.Context, in *vtctldatapb.GetTenantQuotasRequest, opts ...grpc.CallOption) (*vtctldatapb.GetTenantQuotasResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.GetTenantQuotas(ctx, in, opts...)
+}
+
 // GetTopologyPath is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) GetTopologyPath(ctx context.Context, in *vtctldatapb.GetTopologyPathRequest, opts ...grpc.CallOption) (*vtctldatapb.GetTopologyPathResponse, error) {
 	if client.c == nil {
@@ -524,6 +623,24 @@ func (client *gRPCVtctldClient) LookupVindexExternalize(ctx context.Context, in
 	return client.c.LookupVindexExternalize(ctx, in, opts...)
 }
 
+// LookupVindexRepair is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) LookupVindexRepair(ctx context.Context, in *vtctldatapb.LookupVindexRepairRequest, opts ...grpc.CallOption) (*vtctldatapb.LookupVindexRepairResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.LookupVindexRepair(ctx, in, opts...)
+}
+
+// LookupVindexValidate is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) LookupVindexValidate(ctx context.Context, in *vtctldatapb.LookupVindexValidateRequest, opts ...grpc.CallOption) (*vtctldatapb.LookupVindexValidateResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.LookupVindexValidate(ctx, in, opts...)
+}
+
 // MaterializeCreate is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) MaterializeCreate(ctx context.Context, in *vtctldatapb.MaterializeCreateRequest, opts ...grpc.CallOption) (*vtctldatapb.MaterializeCreateResponse, error) {
 	if client.c == nil {
@@ -596,6 +713,15 @@ func (client *gRPCVtctldClient) MoveTablesCreate(ctx context.Context, in *vtctld
 	return client.c.MoveTablesCreate(ctx, in, opts...)
 }
 
+// PauseDMLJob is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) PauseDMLJob(ctx context.Context, in *vtctldatapb.PauseDMLJobRequest, opts ...grpc.CallOption) (*vtctldatapb.PauseDMLJobResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.PauseDMLJob(ctx, in, opts...)
+}
+
 // PingTablet is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) PingTablet(ctx context.Context, in *vtctldatapb.PingTabletRequest, opts ...grpc.CallOption) (*vtctldatapb.PingTabletResponse, error) {
 	if client.c == nil {
@@ -731,6 +857,24 @@ func (client *gRPCVtctldClient) RestoreFromBackup(ctx context.Context, in *vtctl
 	return client.c.RestoreFromBackup(ctx, in, opts...)
 }
 
+// RestoreTable is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) RestoreTable(ctx context.Context, in *vtctldatapb.RestoreTableRequest, opts ...grpc.CallOption) (vtctlservicepb.Vtctld_RestoreTableClient, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.RestoreTable(ctx, in, opts...)
+}
+
+// ResumeDMLJob is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) ResumeDMLJob(ctx context.Context, in *vtctldatapb.ResumeDMLJobRequest, opts ...grpc.CallOption) (*vtctldatapb.ResumeDMLJobResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.ResumeDMLJob(ctx, in, opts...)
+}
+
 // RetrySchemaMigration is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) RetrySchemaMigration(ctx context.Context, in *vtctldatapb.RetrySchemaMigrationRequest, opts ...grpc.CallOption) (*vtctldatapb.RetrySchemaMigrationResponse, error) {
 	if client.c == nil {
@@ -758,6 +902,15 @@ func (client *gRPCVtctldClient) SetKeyspaceDurabilityPolicy(ctx context.Context,
 	return client.c.SetKeyspaceDurabilityPolicy(ctx, in, opts...)
 }
 
+// SetPartitionLifecycle is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) SetPartitionLifecycle(ctx context.Context, in *vtctldatapb.SetPartitionLifecycleRequest, opts ...grpc.CallOption) (*vtctldatapb.SetPartitionLifecycleResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.SetPartitionLifecycle(ctx, in, opts...)
+}
+
 // SetShardIsPrimaryServing is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) SetShardIsPrimaryServing(ctx context.Context, in *vtctldatapb.SetShardIsPrimaryServingRequest, opts ...grpc.CallOption) (*vtctldatapb.SetShardIsPrimaryServingResponse, error) {
 	if client.c == nil {
@@ -39,6 +39,40 @@ const (
 	*
 	from _vt.schema_migrations where %s %s %s`
 	AllMigrationsIndicator = "all"
+
+	selectCoordinatedCutOverSql = `select
+	migration_status, ready_to_complete, strategy, options
+	from _vt.schema_migrations where migration_uuid=%a`
+
+	selectDMLJobsSql = `select
+	*
+	from _vt.dml_jobs where %s order by id`
+	pauseDMLJobSql = `update _vt.dml_jobs
+	set job_status='paused'
+	where job_uuid=%a and job_status in ('queued', 'running')`
+	resumeDMLJobSql = `update _vt.dml_jobs
+	set job_status='queued', message='', completed_timestamp=NULL
+	where job_uuid=%a and job_status in ('paused', 'failed')`
+	cancelDMLJobSql = `update _vt.dml_jobs
+	set job_status='cancelled', completed_timestamp=NOW(6)
+	where job_uuid=%a and job_status in ('queued', 'running', 'paused')`
+
+	selectPartitionLifecyclesSql = `select
+	*
+	from _vt.partition_lifecycle where %s order by id`
+	setPartitionLifecycleSql = `insert into _vt.partition_lifecycle
+	(mysql_table, partition_interval, future_partitions, retention_seconds, message, submitted_migrations)
+	values (%a, %a, %a, %a, '', '')
+	on duplicate key update
+	partition_interval=values(partition_interval),
+	future_partitions=values(future_partitions),
+	retention_seconds=values(retention_seconds)`
+	deletePartitionLifecycleSql = `delete from _vt.partition_lifecycle
+	where mysql_table=%a`
+
+	selectSchemaHistorySql = `select
+	*
+	from _vt.schema_history where %s order by id desc %s`
 )
 
 func alterSchemaMigrationQuery(command, uuid string) (string, error) {
@@ -178,9 +212,117 @@ func rowToSchemaMigration(row sqltypes.RowNamedValues) (sm *vtctldatapb.SchemaMi
 		return nil, err
 	}
 
+	sm.CutoverWindow = row.AsString("cutover_window", "")
+
 	return sm, nil
 }
 
+// rowToDMLJob converts a single row of the dml_jobs table into a DMLJob protobuf.
+func rowToDMLJob(row sqltypes.RowNamedValues) (job *vtctldatapb.DMLJob, err error) {
+	job = new(vtctldatapb.DMLJob)
+	job.Uuid = row.AsString("job_uuid", "")
+	job.Keyspace = row.AsString("keyspace", "")
+	job.Shard = row.AsString("shard", "")
+	job.Schema = row.AsString("mysql_schema", "")
+	job.Table = row.AsString("mysql_table", "")
+	job.Statement = row.AsString("job_statement", "")
+	job.JobContext = row.AsString("job_context", "")
+
+	status, ok := vtctldatapb.DMLJob_Status_value[strings.ToUpper(row.AsString("job_status", ""))]
+	if !ok {
+		return nil, fmt.Errorf("unknown batch DML job status %q", row.AsString("job_status", ""))
+	}
+	job.Status = vtctldatapb.DMLJob_Status(status)
+
+	job.BatchSize = row.AsInt64("batch_size", 0)
+	if alias := row.AsString("tablet", ""); alias != "" {
+		job.Tablet, err = topoproto.ParseTabletAlias(alias)
+		if err != nil {
+			return nil, err
+		}
+	}
+	job.LastPk = row.AsString("last_pk", "")
+	job.RowsScanned = row.AsUint64("rows_scanned", 0)
+	job.RowsAffected = row.AsUint64("rows_affected", 0)
+	job.TableRows = row.AsInt64("table_rows", 0)
+	job.Progress = float32(row.AsFloat64("progress", 0))
+	job.Message = row.AsString("message", "")
+
+	job.AddedAt, err = valueToVTTime(row.AsString("added_timestamp", ""))
+	if err != nil {
+		return nil, err
+	}
+
+	job.StartedAt, err = valueToVTTime(row.AsString("started_timestamp", ""))
+	if err != nil {
+		return nil, err
+	}
+
+	job.LivenessTimestamp, err = valueToVTTime(row.AsString("liveness_timestamp", ""))
+	if err != nil {
+		return nil, err
+	}
+
+	job.CompletedAt, err = valueToVTTime(row.AsString("completed_timestamp", ""))
+	if err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// rowToPartitionLifecycle converts a single row of the partition_lifecycle table
+// into a PartitionLifecycle protobuf. The table has no keyspace and shard
+// columns, which are left for the caller to set.
+func rowToPartitionLifecycle(row sqltypes.RowNamedValues) (lifecycle *vtctldatapb.PartitionLifecycle, err error) {
+	lifecycle = new(vtctldatapb.PartitionLifecycle)
+	lifecycle.Table = row.AsString("mysql_table", "")
+	lifecycle.Interval = row.AsString("partition_interval", "")
+	lifecycle.FuturePartitions = uint32(row.AsUint64("future_partitions", 0))
+	lifecycle.Retention = protoutil.DurationToProto(time.Duration(row.AsInt64("retention_seconds", 0)) * time.Second)
+	lifecycle.Status = row.AsString("lifecycle_status", "")
+	lifecycle.Message = row.AsString("message", "")
+	lifecycle.Partitions = uint32(row.AsUint64("partitions", 0))
+	lifecycle.FirstPartition = row.AsString("first_partition", "")
+	lifecycle.LastPartition = row.AsString("last_partition", "")
+	if submitted := row.AsString("submitted_migrations", ""); submitted != "" {
+		lifecycle.SubmittedMigrations = strings.Split(submitted, ",")
+	}
+
+	lifecycle.AddedAt, err = valueToVTTime(row.AsString("added_timestamp", ""))
+	if err != nil {
+		return nil, err
+	}
+
+	lifecycle.LastCheckedAt, err = valueToVTTime(row.AsString("last_check_timestamp", ""))
+	if err != nil {
+		return nil, err
+	}
+
+	return lifecycle, nil
+}
+
+// rowToSchemaHistoryEntry converts a single row of the schema_history table into
+// a SchemaHistoryEntry protobuf. The table has no keyspace and shard columns,
+// which are left for the caller to set.
+func rowToSchemaHistoryEntry(row sqltypes.RowNamedValues) *vtctldatapb.SchemaHistoryEntry {
+	entry := new(vtctldatapb.SchemaHistoryEntry)
+	entry.Id = row.AsInt64("id", 0)
+	entry.Position = row.AsString("pos", "")
+	if timestamp := row.AsInt64("time_updated", 0); timestamp != 0 {
+		entry.Time = protoutil.TimeToProto(time.Unix(timestamp, 0))
+	}
+	entry.Table = row.AsString("table_name", "")
+	entry.Ddl = row.AsString("ddl", "")
+	entry.MigrationUuid = row.AsString("migration_uuid", "")
+	entry.CreateStatementBefore = row.AsString("create_statement_before", "")
+	if before, ok := row["create_statement_before"]; ok && before.IsNull() {
+		entry.CreateStatementBeforeUnknown = true
+	}
+	entry.CreateStatementAfter = row.AsString("create_statement_after", "")
+	return entry
+}
+
 // valueToVTTime converts a SQL timestamp string into a vttime Time type, first
 // parsing the raw string value into a Go Time type in the local timezone. This
 // is a correct conversion only if the vtctld is set to the same timezone as the
@@ -98,6 +98,68 @@ func TestRowToSchemaMigration(t *testing.T) {
 	}
 }
 
+func TestRowToDMLJob(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		row       sqltypes.RowNamedValues
+		expected  *vtctldatapb.DMLJob
+		shouldErr bool
+	}{
+		{
+			row: sqltypes.RowNamedValues(map[string]sqltypes.Value{
+				"job_uuid":          sqltypes.NewVarChar("abc"),
+				"keyspace":          sqltypes.NewVarChar("testks"),
+				"shard":             sqltypes.NewVarChar("-80"),
+				"mysql_schema":      sqltypes.NewVarChar("vt_testks"),
+				"mysql_table":       sqltypes.NewVarChar("t1"),
+				"job_statement":     sqltypes.NewVarChar("delete from t1 where val is null"),
+				"job_status":        sqltypes.NewVarChar("running"),
+				"batch_size":        sqltypes.NewInt64(500),
+				"last_pk":           sqltypes.NewVarChar("42"),
+				"rows_scanned":      sqltypes.NewUint64(1000),
+				"rows_affected":     sqltypes.NewUint64(12),
+				"started_timestamp": sqltypes.NewTimestamp(mysqlTimestamp(now)),
+			}),
+			expected: &vtctldatapb.DMLJob{
+				Uuid:         "abc",
+				Keyspace:     "testks",
+				Shard:        "-80",
+				Schema:       "vt_testks",
+				Table:        "t1",
+				Statement:    "delete from t1 where val is null",
+				Status:       vtctldatapb.DMLJob_RUNNING,
+				BatchSize:    500,
+				LastPk:       "42",
+				RowsScanned:  1000,
+				RowsAffected: 12,
+				StartedAt:    protoutil.TimeToProto(now.Truncate(time.Second)),
+			},
+		},
+		{
+			name: "unknown status",
+			row: sqltypes.RowNamedValues(map[string]sqltypes.Value{
+				"job_status": sqltypes.NewVarChar("exploded"),
+			}),
+			shouldErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			out, err := rowToDMLJob(test.row)
+			if test.shouldErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			utils.MustMatch(t, test.expected, out)
+		})
+	}
+}
+
 func mysqlTimestamp(t time.Time) string {
 	return t.Local().Format(sqltypes.TimestampFormat)
 }
@@ -234,3 +296,74 @@ func TestAlterSchemaMigrationQuery(t *testing.T) {
 		})
 	}
 }
+
+func TestRowToSchemaHistoryEntry(t *testing.T) {
+	t.Parallel()
+
+	row := sqltypes.RowNamedValues(map[string]sqltypes.Value{
+		"id":                      sqltypes.NewInt64(7),
+		"pos":                     sqltypes.NewVarChar("MySQL56/7b04699f-f5e9-11e9-bf88-9cb6d089e1c3:1-10"),
+		"time_updated":            sqltypes.NewInt64(now.Unix()),
+		"table_name":              sqltypes.NewVarChar("t1"),
+		"ddl":                     sqltypes.NewVarChar("alter table t1 add column j int"),
+		"migration_uuid":          sqltypes.NewVarChar("1876a9c2_2c46_11ef_b6e8_0a43f95f28a3"),
+		"create_statement_before": sqltypes.NewVarChar("CREATE TABLE `t1` (\n  `i` int\n)"),
+		"create_statement_after":  sqltypes.NewVarChar("CREATE TABLE `t1` (\n  `i` int,\n  `j` int\n)"),
+	})
+	expected := &vtctldatapb.SchemaHistoryEntry{
+		Id:                    7,
+		Position:              "MySQL56/7b04699f-f5e9-11e9-bf88-9cb6d089e1c3:1-10",
+		Time:                  protoutil.TimeToProto(now.Truncate(time.Second)),
+		Table:                 "t1",
+		Ddl:                   "alter table t1 add column j int",
+		MigrationUuid:         "1876a9c2_2c46_11ef_b6e8_0a43f95f28a3",
+		CreateStatementBefore: "CREATE TABLE `t1` (\n  `i` int\n)",
+		CreateStatementAfter:  "CREATE TABLE `t1` (\n  `i` int,\n  `j` int\n)",
+	}
+
+	out := rowToSchemaHistoryEntry(row)
+	utils.MustMatch(t, expected, out)
+
+	row["create_statement_before"] = sqltypes.NULL
+	expected.CreateStatementBefore = ""
+	expected.CreateStatementBeforeUnknown = true
+
+	out = rowToSchemaHistoryEntry(row)
+	utils.MustMatch(t, expected, out)
+}
+
+func TestRowToPartitionLifecycle(t *testing.T) {
+	t.Parallel()
+
+	row := sqltypes.RowNamedValues(map[string]sqltypes.Value{
+		"mysql_table":          sqltypes.NewVarChar("events"),
+		"partition_interval":   sqltypes.NewVarChar("day"),
+		"future_partitions":    sqltypes.NewUint64(3),
+		"retention_seconds":    sqltypes.NewUint64(7 * 24 * 3600),
+		"lifecycle_status":     sqltypes.NewVarChar("ok"),
+		"partitions":           sqltypes.NewUint64(10),
+		"first_partition":      sqltypes.NewVarChar("p20240101"),
+		"last_partition":       sqltypes.NewVarChar("p20240110"),
+		"submitted_migrations": sqltypes.NewVarChar("1876a9c2_2c46_11ef_b6e8_0a43f95f28a3,2876a9c2_2c46_11ef_b6e8_0a43f95f28a3"),
+		"last_check_timestamp": sqltypes.NewTimestamp(mysqlTimestamp(now)),
+	})
+	expected := &vtctldatapb.PartitionLifecycle{
+		Table:            "events",
+		Interval:         "day",
+		FuturePartitions: 3,
+		Retention:        protoutil.DurationToProto(7 * 24 * time.Hour),
+		Status:           "ok",
+		Partitions:       10,
+		FirstPartition:   "p20240101",
+		LastPartition:    "p20240110",
+		SubmittedMigrations: []string{
+			"1876a9c2_2c46_11ef_b6e8_0a43f95f28a3",
+			"2876a9c2_2c46_11ef_b6e8_0a43f95f28a3",
+		},
+		LastCheckedAt: protoutil.TimeToProto(now.Truncate(time.Second)),
+	}
+
+	out, err := rowToPartitionLifecycle(row)
+	require.NoError(t, err)
+	utils.MustMatch(t, expected, out)
+}
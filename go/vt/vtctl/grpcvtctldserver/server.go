@@ -59,10 +59,12 @@ import (
 	"vitess.io/vitess/go/vt/topotools/events"
 	"vitess.io/vitess/go/vt/vtctl/reparentutil"
 	"vitess.io/vitess/go/vt/vtctl/schematools"
+	"vitess.io/vitess/go/vt/vtctl/tablerestore"
 	"vitess.io/vitess/go/vt/vtctl/workflow"
 	"vitess.io/vitess/go/vt/vtenv"
 	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vtgate/vindexes"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/rules"
 	"vitess.io/vitess/go/vt/vttablet/tmclient"
 
 	logutilpb "vitess.io/vitess/go/vt/proto/logutil"
@@ -75,6 +77,7 @@ import (
 	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
 	vtctlservicepb "vitess.io/vitess/go/vt/proto/vtctlservice"
 	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/proto/vttime"
 )
 
 const (
@@ -82,6 +85,11 @@ const (
 
 	// DefaultWaitReplicasTimeout is the default value for waitReplicasTimeout, which is used when calling method ApplySchema.
 	DefaultWaitReplicasTimeout = 10 * time.Second
+
+	// coordinatedCutOverPrepareTimeout bounds the prepare phase of a coordinated cut-over. Tablets
+	// hold a prepared cut-over for this long plus topo.RemoteOperationTimeout, so that the commit
+	// reaches them before they abort on their own.
+	coordinatedCutOverPrepareTimeout = 30 * time.Second
 )
 
 // VtctldServer implements the Vtctld RPC service protocol.
@@ -165,6 +173,104 @@ func (s *VtctldServer) AddCellsAlias(ctx context.Context, req *vtctldatapb.AddCe
 	return &vtctldatapb.AddCellsAliasResponse{}, nil
 }
 
+// ApplyQueryRules is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) ApplyQueryRules(ctx context.Context, req *vtctldatapb.ApplyQueryRulesRequest) (resp *vtctldatapb.ApplyQueryRulesResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.ApplyQueryRules")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("shards", strings.Join(req.Shards, ","))
+	span.Annotate("tablet_types", topoproto.MakeStringTypeCSV(req.TabletTypes))
+	span.Annotate("dry_run", req.DryRun)
+
+	qrs := rules.New()
+	if err := qrs.UnmarshalJSON([]byte(req.Rules)); err != nil {
+		return nil, vterrors.Wrapf(err, "invalid query rules")
+	}
+	if len(qrs.CopyUnderlying()) == 0 {
+		return nil, vterrors.New(vtrpcpb.Code_INVALID_ARGUMENT, "no query rules to apply")
+	}
+
+	if req.Keyspace != "" {
+		if _, err := s.ts.GetKeyspace(ctx, req.Keyspace); err != nil {
+			return nil, err
+		}
+	}
+	for _, shard := range req.Shards {
+		if req.Keyspace == "" {
+			return nil, vterrors.New(vtrpcpb.Code_INVALID_ARGUMENT, "query rules can only be limited to shards of a keyspace")
+		}
+		if _, err := s.ts.GetShard(ctx, req.Keyspace, shard); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	var expireTime *vttime.Time
+	ttl, ok, err := protoutil.DurationFromProto(req.Ttl)
+	switch {
+	case err != nil:
+		return nil, vterrors.Wrapf(err, "unable to parse Ttl into a valid duration")
+	case ok && ttl <= 0:
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid ttl %v, it must be positive", ttl)
+	case ok:
+		expireTime = protoutil.TimeToProto(now.Add(ttl))
+	}
+
+	names := make(map[string]bool)
+	var applied []*topodatapb.QueryRule
+	for _, qr := range qrs.CopyUnderlying() {
+		if qr.Name == "" {
+			return nil, vterrors.New(vtrpcpb.Code_INVALID_ARGUMENT, "query rules must have a Name")
+		}
+		if names[qr.Name] {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "duplicate query rule %s", qr.Name)
+		}
+		names[qr.Name] = true
+
+		qr.DryRun = qr.DryRun || req.DryRun
+		rule, err := json.Marshal(qr)
+		if err != nil {
+			return nil, err
+		}
+		applied = append(applied, &topodatapb.QueryRule{
+			Name:        qr.Name,
+			Rule:        string(rule),
+			Keyspace:    req.Keyspace,
+			Shards:      req.Shards,
+			TabletTypes: req.TabletTypes,
+			ExpireTime:  expireTime,
+		})
+	}
+
+	queryRules, err := s.ts.UpdateQueryRules(ctx, func(queryRules *topodatapb.QueryRules) error {
+		// The expired rules are purged along the way.
+		kept := queryRules.Rules[:0]
+		for _, qr := range queryRules.Rules {
+			if names[qr.Name] || isQueryRuleExpired(qr, now) {
+				continue
+			}
+			kept = append(kept, qr)
+		}
+		queryRules.Rules = append(kept, applied...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.ApplyQueryRulesResponse{
+		QueryRules: queryRules,
+	}, nil
+}
+
+// isQueryRuleExpired returns true if the tablets no longer apply the query rule.
+func isQueryRuleExpired(qr *topodatapb.QueryRule, now time.Time) bool {
+	return qr.ExpireTime != nil && !now.Before(protoutil.TimeFromProto(qr.ExpireTime))
+}
+
 // ApplyRoutingRules is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) ApplyRoutingRules(ctx context.Context, req *vtctldatapb.ApplyRoutingRulesRequest) (resp *vtctldatapb.ApplyRoutingRulesResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.ApplyRoutingRules")
@@ -220,6 +326,36 @@ func (s *VtctldServer) ApplyShardRoutingRules(ctx context.Context, req *vtctldat
 	return resp, nil
 }
 
+// ApplyTenantQuotas is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) ApplyTenantQuotas(ctx context.Context, req *vtctldatapb.ApplyTenantQuotasRequest) (*vtctldatapb.ApplyTenantQuotasResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.ApplyTenantQuotas")
+	defer span.Finish()
+
+	span.Annotate("skip_rebuild", req.SkipRebuild)
+	span.Annotate("rebuild_cells", strings.Join(req.RebuildCells, ","))
+
+	if err := vindexes.ValidateTenantQuotas(req.TenantQuotas); err != nil {
+		return nil, err
+	}
+
+	if err := s.ts.SaveTenantQuotas(ctx, req.TenantQuotas); err != nil {
+		return nil, err
+	}
+
+	resp := &vtctldatapb.ApplyTenantQuotasResponse{}
+
+	if req.SkipRebuild {
+		log.Warningf("Skipping rebuild of SrvVSchema as requested, you will need to run RebuildVSchemaGraph for changes to take effect")
+		return resp, nil
+	}
+
+	if err := s.ts.RebuildSrvVSchema(ctx, req.RebuildCells); err != nil {
+		return nil, vterrors.Wrapf(err, "RebuildSrvVSchema(%v) failed: %v", req.RebuildCells, err)
+	}
+
+	return resp, nil
+}
+
 // ApplySchema is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) ApplySchema(ctx context.Context, req *vtctldatapb.ApplySchemaRequest) (resp *vtctldatapb.ApplySchemaResponse, err error) {
 	log.Infof("VtctldServer.ApplySchema: keyspace=%s, migrationContext=%v, ddlStrategy=%v, batchSize=%v", req.Keyspace, req.MigrationContext, req.DdlStrategy, req.BatchSize)
@@ -551,6 +687,27 @@ func (s *VtctldServer) backupTablet(ctx context.Context, tablet *topodatapb.Tabl
 	}
 }
 
+// CancelDMLJob is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) CancelDMLJob(ctx context.Context, req *vtctldatapb.CancelDMLJobRequest) (resp *vtctldatapb.CancelDMLJobResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.CancelDMLJob")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("uuid", req.Uuid)
+
+	rowsAffectedByShard, err := s.updateDMLJob(ctx, req.Keyspace, req.Uuid, cancelDMLJobSql)
+	if err != nil {
+		return nil, err
+	}
+
+	resp = &vtctldatapb.CancelDMLJobResponse{
+		RowsAffectedByShard: rowsAffectedByShard,
+	}
+	return resp, nil
+}
+
 // CancelSchemaMigration is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) CancelSchemaMigration(ctx context.Context, req *vtctldatapb.CancelSchemaMigrationRequest) (resp *vtctldatapb.CancelSchemaMigrationResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.CancelSchemaMigration")
@@ -680,6 +837,23 @@ func (s *VtctldServer) ChangeTabletType(ctx context.Context, req *vtctldatapb.Ch
 	}, nil
 }
 
+// ChangeVindexCreate is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) ChangeVindexCreate(ctx context.Context, req *vtctldatapb.ChangeVindexCreateRequest) (resp *vtctldatapb.ChangeVindexCreateResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.ChangeVindexCreate")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("workflow", req.Workflow)
+	span.Annotate("table", req.Table)
+	span.Annotate("cells", req.Cells)
+	span.Annotate("tablet_types", req.TabletTypes)
+
+	resp, err = s.ws.ChangeVindexCreate(ctx, req)
+	return resp, err
+}
+
 // CleanupSchemaMigration is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) CleanupSchemaMigration(ctx context.Context, req *vtctldatapb.CleanupSchemaMigrationRequest) (resp *vtctldatapb.CleanupSchemaMigrationResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.CleanupSchemaMigration")
@@ -752,6 +926,17 @@ func (s *VtctldServer) CompleteSchemaMigration(ctx context.Context, req *vtctlda
 	span.Annotate("keyspace", req.Keyspace)
 	span.Annotate("uuid", req.Uuid)
 
+	if schema.IsOnlineDDLUUID(req.Uuid) {
+		shards, err := s.coordinatedCutOverShards(ctx, req.Keyspace, req.Uuid)
+		if err != nil {
+			return nil, err
+		}
+		if len(shards) > 0 {
+			span.Annotate("coordinated_cutover", true)
+			return s.coordinatedCutOver(ctx, req.Keyspace, req.Uuid, shards)
+		}
+	}
+
 	query, err := alterSchemaMigrationQuery("complete", req.Uuid)
 	if err != nil {
 		return nil, err
@@ -1065,6 +1250,71 @@ func (s *VtctldServer) DeleteKeyspace(ctx context.Context, req *vtctldatapb.Dele
 	return &vtctldatapb.DeleteKeyspaceResponse{}, nil
 }
 
+// DeletePartitionLifecycle is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) DeletePartitionLifecycle(ctx context.Context, req *vtctldatapb.DeletePartitionLifecycleRequest) (resp *vtctldatapb.DeletePartitionLifecycleResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.DeletePartitionLifecycle")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("table", req.Table)
+
+	if req.Table == "" {
+		return nil, vterrors.New(vtrpcpb.Code_INVALID_ARGUMENT, "table name is required")
+	}
+	query, err := sqlparser.ParseAndBind(deletePartitionLifecycleSql, sqltypes.StringBindVariable(req.Table))
+	if err != nil {
+		return nil, err
+	}
+
+	rowsAffectedByShard, err := s.updateOnKeyspacePrimaries(ctx, req.Keyspace, query)
+	if err != nil {
+		return nil, err
+	}
+
+	resp = &vtctldatapb.DeletePartitionLifecycleResponse{
+		RowsAffectedByShard: rowsAffectedByShard,
+	}
+	return resp, nil
+}
+
+// DeleteQueryRule is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) DeleteQueryRule(ctx context.Context, req *vtctldatapb.DeleteQueryRuleRequest) (resp *vtctldatapb.DeleteQueryRuleResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.DeleteQueryRule")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("name", req.Name)
+
+	now := time.Now()
+	_, err = s.ts.UpdateQueryRules(ctx, func(queryRules *topodatapb.QueryRules) error {
+		found := false
+		// The expired rules are purged along the way.
+		kept := queryRules.Rules[:0]
+		for _, qr := range queryRules.Rules {
+			if qr.Name == req.Name {
+				found = true
+				continue
+			}
+			if !isQueryRuleExpired(qr, now) {
+				kept = append(kept, qr)
+			}
+		}
+		if !found {
+			return vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "query rule %s not found", req.Name)
+		}
+		queryRules.Rules = kept
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.DeleteQueryRuleResponse{}, nil
+}
+
 // DeleteShards is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) DeleteShards(ctx context.Context, req *vtctldatapb.DeleteShardsRequest) (resp *vtctldatapb.DeleteShardsResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.DeleteShards")
@@ -1475,6 +1725,56 @@ func (s *VtctldServer) GetCellsAliases(ctx context.Context, req *vtctldatapb.Get
 	return &vtctldatapb.GetCellsAliasesResponse{Aliases: aliases}, nil
 }
 
+// GetDMLJobs is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) GetDMLJobs(ctx context.Context, req *vtctldatapb.GetDMLJobsRequest) (resp *vtctldatapb.GetDMLJobsResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.GetDMLJobs")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+
+	var condition string
+	switch {
+	case req.Uuid != "":
+		span.Annotate("uuid", req.Uuid)
+		if !schema.IsOnlineDDLUUID(req.Uuid) {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "%s is not a valid UUID", req.Uuid)
+		}
+		condition, err = sqlparser.ParseAndBind("job_uuid=%a", sqltypes.StringBindVariable(req.Uuid))
+	case req.JobContext != "":
+		span.Annotate("job_context", req.JobContext)
+		condition, err = sqlparser.ParseAndBind("job_context=%a", sqltypes.StringBindVariable(req.JobContext))
+	case req.Status != vtctldatapb.DMLJob_UNKNOWN:
+		status := strings.ToLower(req.Status.String())
+		span.Annotate("job_status", status)
+		condition, err = sqlparser.ParseAndBind("job_status=%a", sqltypes.StringBindVariable(status))
+	default:
+		condition = "job_uuid like '%'"
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Error generating batch DML jobs query: %+v", err)
+	}
+
+	results, err := s.executeOnKeyspacePrimaries(ctx, req.Keyspace, fmt.Sprintf(selectDMLJobsSql, condition))
+	if err != nil {
+		return nil, err
+	}
+
+	// combine results. This loses sorting if there's more then 1 tablet
+	combinedResults := queryResultForTabletResults(results)
+
+	resp = new(vtctldatapb.GetDMLJobsResponse)
+	for _, row := range combinedResults.Named().Rows {
+		job, err := rowToDMLJob(row)
+		if err != nil {
+			return nil, err
+		}
+		resp.Jobs = append(resp.Jobs, job)
+	}
+	return resp, nil
+}
+
 // GetFullStatus is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) GetFullStatus(ctx context.Context, req *vtctldatapb.GetFullStatusRequest) (resp *vtctldatapb.GetFullStatusResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.GetFullStatus")
@@ -1548,6 +1848,50 @@ func (s *VtctldServer) GetKeyspaces(ctx context.Context, req *vtctldatapb.GetKey
 	return &vtctldatapb.GetKeyspacesResponse{Keyspaces: keyspaces}, nil
 }
 
+// GetPartitionLifecycles is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) GetPartitionLifecycles(ctx context.Context, req *vtctldatapb.GetPartitionLifecyclesRequest) (resp *vtctldatapb.GetPartitionLifecyclesResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.GetPartitionLifecycles")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+
+	condition := "1 = 1"
+	if req.Table != "" {
+		span.Annotate("table", req.Table)
+		condition, err = sqlparser.ParseAndBind("mysql_table=%a", sqltypes.StringBindVariable(req.Table))
+		if err != nil {
+			return nil, fmt.Errorf("Error generating partition lifecycles query: %+v", err)
+		}
+	}
+
+	results, err := s.executeOnKeyspacePrimaries(ctx, req.Keyspace, fmt.Sprintf(selectPartitionLifecyclesSql, condition))
+	if err != nil {
+		return nil, err
+	}
+
+	shards := make([]string, 0, len(results))
+	for shard := range results {
+		shards = append(shards, shard)
+	}
+	sort.Strings(shards)
+
+	resp = new(vtctldatapb.GetPartitionLifecyclesResponse)
+	for _, shard := range shards {
+		for _, row := range results[shard].Named().Rows {
+			lifecycle, err := rowToPartitionLifecycle(row)
+			if err != nil {
+				return nil, err
+			}
+			lifecycle.Keyspace = req.Keyspace
+			lifecycle.Shard = shard
+			resp.Lifecycles = append(resp.Lifecycles, lifecycle)
+		}
+	}
+	return resp, nil
+}
+
 // GetPermissions is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) GetPermissions(ctx context.Context, req *vtctldatapb.GetPermissionsRequest) (resp *vtctldatapb.GetPermissionsResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.GetPermissions")
@@ -1572,6 +1916,23 @@ func (s *VtctldServer) GetPermissions(ctx context.Context, req *vtctldatapb.GetP
 	}, nil
 }
 
+// GetQueryRules is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) GetQueryRules(ctx context.Context, req *vtctldatapb.GetQueryRulesRequest) (resp *vtctldatapb.GetQueryRulesResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.GetQueryRules")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	queryRules, err := s.ts.GetQueryRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.GetQueryRulesResponse{
+		QueryRules: queryRules,
+	}, nil
+}
+
 // GetRoutingRules is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) GetRoutingRules(ctx context.Context, req *vtctldatapb.GetRoutingRulesRequest) (resp *vtctldatapb.GetRoutingRulesResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.GetRoutingRules")
@@ -1655,6 +2016,52 @@ func (s *VtctldServer) GetSchema(ctx context.Context, req *vtctldatapb.GetSchema
 	}, nil
 }
 
+// GetSchemaHistory is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) GetSchemaHistory(ctx context.Context, req *vtctldatapb.GetSchemaHistoryRequest) (resp *vtctldatapb.GetSchemaHistoryResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.GetSchemaHistory")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+
+	condition := "1 = 1"
+	if req.Table != "" {
+		span.Annotate("table", req.Table)
+		condition, err = sqlparser.ParseAndBind("table_name=%a", sqltypes.StringBindVariable(req.Table))
+		if err != nil {
+			return nil, fmt.Errorf("Error generating schema history query: %+v", err)
+		}
+	}
+	var limit string
+	if req.Limit > 0 {
+		span.Annotate("limit", req.Limit)
+		limit = fmt.Sprintf("limit %d", req.Limit)
+	}
+
+	results, err := s.executeOnKeyspacePrimaries(ctx, req.Keyspace, fmt.Sprintf(selectSchemaHistorySql, condition, limit))
+	if err != nil {
+		return nil, err
+	}
+
+	shards := make([]string, 0, len(results))
+	for shard := range results {
+		shards = append(shards, shard)
+	}
+	sort.Strings(shards)
+
+	resp = new(vtctldatapb.GetSchemaHistoryResponse)
+	for _, shard := range shards {
+		for _, row := range results[shard].Named().Rows {
+			entry := rowToSchemaHistoryEntry(row)
+			entry.Keyspace = req.Keyspace
+			entry.Shard = shard
+			resp.Entries = append(resp.Entries, entry)
+		}
+	}
+	return resp, nil
+}
+
 func (s *VtctldServer) GetSchemaMigrations(ctx context.Context, req *vtctldatapb.GetSchemaMigrationsRequest) (resp *vtctldatapb.GetSchemaMigrationsResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.GetShard")
 	defer span.Finish()
@@ -2254,6 +2661,21 @@ func (s *VtctldServer) GetTablets(ctx context.Context, req *vtctldatapb.GetTable
 	}, nil
 }
 
+// GetTenantQuotas is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) GetTenantQuotas(ctx context.Context, req *vtctldatapb.GetTenantQuotasRequest) (*vtctldatapb.GetTenantQuotasResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.GetTenantQuotas")
+	defer span.Finish()
+
+	tq, err := s.ts.GetTenantQuotas(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.GetTenantQuotasResponse{
+		TenantQuotas: tq,
+	}, nil
+}
+
 // GetTopologyPath is part of the vtctlservicepb.VtctldServer interface.
 // It returns the cell located at the provided path in the topology server.
 func (s *VtctldServer) GetTopologyPath(ctx context.Context, req *vtctldatapb.GetTopologyPathRequest) (*vtctldatapb.GetTopologyPathResponse, error) {
@@ -2683,6 +3105,37 @@ func (s *VtctldServer) LookupVindexExternalize(ctx context.Context, req *vtctlda
 	return resp, err
 }
 
+// LookupVindexRepair is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) LookupVindexRepair(ctx context.Context, req *vtctldatapb.LookupVindexRepairRequest) (resp *vtctldatapb.LookupVindexRepairResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.LookupVindexRepair")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("name", req.Name)
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("table_keyspace", req.TableKeyspace)
+	span.Annotate("max_rows_per_second", req.MaxRowsPerSecond)
+
+	resp, err = s.ws.LookupVindexRepair(ctx, req)
+	return resp, err
+}
+
+// LookupVindexValidate is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) LookupVindexValidate(ctx context.Context, req *vtctldatapb.LookupVindexValidateRequest) (resp *vtctldatapb.LookupVindexValidateResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.LookupVindexValidate")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("name", req.Name)
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("table_keyspace", req.TableKeyspace)
+
+	resp, err = s.ws.LookupVindexValidate(ctx, req)
+	return resp, err
+}
+
 // MaterializeCreate is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) MaterializeCreate(ctx context.Context, req *vtctldatapb.MaterializeCreateRequest) (resp *vtctldatapb.MaterializeCreateResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.MaterializeCreate")
@@ -2805,6 +3258,27 @@ func (s *VtctldServer) MoveTablesComplete(ctx context.Context, req *vtctldatapb.
 	return resp, err
 }
 
+// PauseDMLJob is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) PauseDMLJob(ctx context.Context, req *vtctldatapb.PauseDMLJobRequest) (resp *vtctldatapb.PauseDMLJobResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.PauseDMLJob")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("uuid", req.Uuid)
+
+	rowsAffectedByShard, err := s.updateDMLJob(ctx, req.Keyspace, req.Uuid, pauseDMLJobSql)
+	if err != nil {
+		return nil, err
+	}
+
+	resp = &vtctldatapb.PauseDMLJobResponse{
+		RowsAffectedByShard: rowsAffectedByShard,
+	}
+	return resp, nil
+}
+
 // PingTablet is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) PingTablet(ctx context.Context, req *vtctldatapb.PingTabletRequest) (resp *vtctldatapb.PingTabletResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.PingTablet")
@@ -3049,6 +3523,239 @@ func (s *VtctldServer) ReloadSchemaShard(ctx context.Context, req *vtctldatapb.R
 	}, nil
 }
 
+// executeOnKeyspacePrimaries runs the given query as DBA on the primary tablet
+// of each shard in the keyspace, and returns the results by shard.
+func (s *VtctldServer) executeOnKeyspacePrimaries(ctx context.Context, keyspace string, query string) (map[string]*sqltypes.Result, error) {
+	tabletsResp, err := s.GetTablets(ctx, &vtctldatapb.GetTabletsRequest{
+		Keyspace:   keyspace,
+		TabletType: topodatapb.TabletType_PRIMARY,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		m       sync.Mutex
+		wg      sync.WaitGroup
+		rec     concurrency.AllErrorRecorder
+		results = map[string]*sqltypes.Result{}
+	)
+	for _, tablet := range tabletsResp.Tablets {
+		wg.Add(1)
+		go func(tablet *topodatapb.Tablet) {
+			defer wg.Done()
+
+			fetchResp, err := s.ExecuteFetchAsDBA(ctx, &vtctldatapb.ExecuteFetchAsDBARequest{
+				TabletAlias: tablet.Alias,
+				Query:       query,
+				MaxRows:     10_000,
+			})
+			if err != nil {
+				rec.RecordError(err)
+				return
+			}
+
+			m.Lock()
+			defer m.Unlock()
+
+			results[tablet.Shard] = sqltypes.Proto3ToResult(fetchResp.Result)
+		}(tablet)
+	}
+
+	wg.Wait()
+	if rec.HasErrors() {
+		return nil, rec.Error()
+	}
+	return results, nil
+}
+
+// coordinatedCutOverShards reads the given migration on the primary tablet of each shard in the
+// keyspace. If the migration uses --coordinated-cutover, it returns the shards the migration runs
+// on, and fails unless the migration is ready to complete on all of them. It returns no shards for
+// any other migration.
+func (s *VtctldServer) coordinatedCutOverShards(ctx context.Context, keyspace string, uuid string) ([]string, error) {
+	query, err := sqlparser.ParseAndBind(selectCoordinatedCutOverSql, sqltypes.StringBindVariable(uuid))
+	if err != nil {
+		return nil, err
+	}
+	results, err := s.executeOnKeyspacePrimaries(ctx, keyspace, query)
+	if err != nil {
+		return nil, err
+	}
+
+	var shards []string
+	for shard, qr := range results {
+		row := qr.Named().Row()
+		if row == nil {
+			continue
+		}
+		setting := schema.NewDDLStrategySetting(schema.DDLStrategy(row["strategy"].ToString()), row["options"].ToString())
+		if !setting.IsCoordinatedCutOverFlag() {
+			continue
+		}
+		status := schema.OnlineDDLStatus(row["migration_status"].ToString())
+		if status != schema.OnlineDDLStatusRunning || !row.AsBool("ready_to_complete", false) {
+			return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "migration %s is not ready to complete on shard %s (status: %s)", uuid, shard, status)
+		}
+		shards = append(shards, shard)
+	}
+	sort.Strings(shards)
+	return shards, nil
+}
+
+// coordinatedCutOver cuts over a --coordinated-cutover migration on the given shards in two phases.
+// First, all shards prepare their cut-over, which holds their locks with the RENAME in place. Only
+// once all shards are prepared do they all commit. If any shard fails to prepare, the cut-over is
+// aborted on all shards, and the migration keeps running everywhere. The outcome of the cut-over is
+// returned by shard, along with the number of affected rows on the shards that committed.
+func (s *VtctldServer) coordinatedCutOver(ctx context.Context, keyspace string, uuid string, shards []string) (*vtctldatapb.CompleteSchemaMigrationResponse, error) {
+	tabletsResp, err := s.GetTablets(ctx, &vtctldatapb.GetTabletsRequest{
+		Keyspace:   keyspace,
+		TabletType: topodatapb.TabletType_PRIMARY,
+	})
+	if err != nil {
+		return nil, err
+	}
+	primaries := make(map[string]*topodatapb.Tablet, len(tabletsResp.Tablets))
+	for _, tablet := range tabletsResp.Tablets {
+		primaries[tablet.Shard] = tablet
+	}
+	tablets := make([]*topodatapb.Tablet, 0, len(shards))
+	for _, shard := range shards {
+		tablet, ok := primaries[shard]
+		if !ok {
+			return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "no primary tablet for shard %s/%s", keyspace, shard)
+		}
+		tablets = append(tablets, tablet)
+	}
+
+	prepareQuery, err := alterSchemaMigrationQuery("prepare_cutover", uuid)
+	if err != nil {
+		return nil, err
+	}
+	// The tablets wait for the decision past the end of the prepare phase, for as long as the
+	// decision may take to reach them.
+	prepareQuery = fmt.Sprintf("%s expire '%s'", prepareQuery, coordinatedCutOverPrepareTimeout+topo.RemoteOperationTimeout)
+	abortQuery, err := alterSchemaMigrationQuery("abort_cutover", uuid)
+	if err != nil {
+		return nil, err
+	}
+	commitQuery, err := alterSchemaMigrationQuery("commit_cutover", uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("Preparing coordinated cut-over of migration %s on shards %v", uuid, shards)
+	prepareCtx, prepareCancel := context.WithTimeout(ctx, coordinatedCutOverPrepareTimeout)
+	defer prepareCancel()
+	_, prepareErrs := s.executeQueryOnTablets(prepareCtx, tablets, prepareQuery)
+
+	// Once the cut-over is decided, the decision must reach all shards even if the request was cancelled.
+	decisionCtx, decisionCancel := context.WithTimeout(context.WithoutCancel(ctx), topo.RemoteOperationTimeout)
+	defer decisionCancel()
+
+	resp := &vtctldatapb.CompleteSchemaMigrationResponse{
+		RowsAffectedByShard:   make(map[string]uint64, len(shards)),
+		CutOverResultsByShard: make(map[string]string, len(shards)),
+	}
+	if len(prepareErrs) > 0 {
+		log.Errorf("Aborting coordinated cut-over of migration %s: %d shards failed to prepare", uuid, len(prepareErrs))
+		// Tablets abort a prepared cut-over on their own if it is never decided, this only
+		// releases the locks sooner.
+		_, abortErrs := s.executeQueryOnTablets(decisionCtx, tablets, abortQuery)
+		for _, shard := range shards {
+			switch {
+			case prepareErrs[shard] != nil:
+				log.Errorf("Failed preparing coordinated cut-over of migration %s on shard %s: %v", uuid, shard, prepareErrs[shard])
+				resp.CutOverResultsByShard[shard] = fmt.Sprintf("prepare failed: %v", prepareErrs[shard])
+			case abortErrs[shard] != nil:
+				log.Errorf("Failed aborting coordinated cut-over of migration %s on shard %s: %v", uuid, shard, abortErrs[shard])
+				resp.CutOverResultsByShard[shard] = fmt.Sprintf("abort failed: %v", abortErrs[shard])
+			default:
+				resp.CutOverResultsByShard[shard] = "aborted"
+			}
+		}
+		return resp, nil
+	}
+
+	log.Infof("Committing coordinated cut-over of migration %s on shards %v", uuid, shards)
+	results, commitErrs := s.executeQueryOnTablets(decisionCtx, tablets, commitQuery)
+	for _, shard := range shards {
+		if err := commitErrs[shard]; err != nil {
+			log.Errorf("Failed committing coordinated cut-over of migration %s on shard %s: %v", uuid, shard, err)
+			resp.CutOverResultsByShard[shard] = fmt.Sprintf("commit failed: %v", err)
+			continue
+		}
+		resp.CutOverResultsByShard[shard] = "committed"
+		resp.RowsAffectedByShard[shard] = results[shard].RowsAffected
+	}
+	return resp, nil
+}
+
+// executeQueryOnTablets runs the given query on each of the given tablets in parallel, through
+// the query service, and returns the results and the errors by shard.
+func (s *VtctldServer) executeQueryOnTablets(ctx context.Context, tablets []*topodatapb.Tablet, query string) (map[string]*querypb.QueryResult, map[string]error) {
+	var (
+		m       sync.Mutex
+		wg      sync.WaitGroup
+		results = map[string]*querypb.QueryResult{}
+		errs    = map[string]error{}
+	)
+	for _, tablet := range tablets {
+		wg.Add(1)
+		go func(tablet *topodatapb.Tablet) {
+			defer wg.Done()
+
+			qr, err := s.tmc.ExecuteQuery(ctx, tablet, &tabletmanagerdatapb.ExecuteQueryRequest{
+				Query:   []byte(query),
+				MaxRows: 10,
+			})
+
+			m.Lock()
+			defer m.Unlock()
+
+			if err != nil {
+				errs[tablet.Shard] = err
+				return
+			}
+			results[tablet.Shard] = qr
+		}(tablet)
+	}
+
+	wg.Wait()
+	return results, errs
+}
+
+// updateDMLJob changes the status of a batch DML job on all shards of the
+// keyspace, and returns the number of affected rows by shard.
+func (s *VtctldServer) updateDMLJob(ctx context.Context, keyspace string, uuid string, sql string) (map[string]uint64, error) {
+	if !schema.IsOnlineDDLUUID(uuid) {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "%s is not a valid UUID", uuid)
+	}
+	query, err := sqlparser.ParseAndBind(sql, sqltypes.StringBindVariable(uuid))
+	if err != nil {
+		return nil, err
+	}
+
+	return s.updateOnKeyspacePrimaries(ctx, keyspace, query)
+}
+
+// updateOnKeyspacePrimaries runs the given DML query as DBA on the primary
+// tablet of each shard in the keyspace, and returns the number of affected
+// rows by shard.
+func (s *VtctldServer) updateOnKeyspacePrimaries(ctx context.Context, keyspace string, query string) (map[string]uint64, error) {
+	results, err := s.executeOnKeyspacePrimaries(ctx, keyspace, query)
+	if err != nil {
+		return nil, err
+	}
+
+	rowsAffectedByShard := make(map[string]uint64, len(results))
+	for shard, result := range results {
+		rowsAffectedByShard[shard] = result.RowsAffected
+	}
+	return rowsAffectedByShard, nil
+}
+
 func (s *VtctldServer) reloadSchemaShard(ctx context.Context, req *vtctldatapb.ReloadSchemaShardRequest, sema *semaphore.Weighted, logger logutil.Logger) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.ReloadSchemaShard")
 	defer span.Finish()
@@ -3364,6 +4071,60 @@ func (s *VtctldServer) RestoreFromBackup(req *vtctldatapb.RestoreFromBackupReque
 	}
 }
 
+// RestoreTable is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) RestoreTable(req *vtctldatapb.RestoreTableRequest, stream vtctlservicepb.Vtctld_RestoreTableServer) (err error) {
+	span, ctx := trace.NewSpan(stream.Context(), "VtctldServer.RestoreTable")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("tables", strings.Join(req.Tables, ","))
+	span.Annotate("resume", req.Resume)
+	span.Annotate("restore_to_pos", req.RestoreToPos)
+	restoreToTimestamp := protoutil.TimeFromProto(req.RestoreToTimestamp).UTC()
+	if !restoreToTimestamp.IsZero() {
+		span.Annotate("restore_to_timestamp", restoreToTimestamp.Format(mysqlctl.BackupTimestampFormat))
+	}
+
+	logger := logutil.NewConsoleLogger()
+	// The shards are restored concurrently, so sending on the stream must be
+	// serialized.
+	m := sync.Mutex{}
+	send := func(resp *vtctldatapb.RestoreTableResponse) {
+		m.Lock()
+		defer m.Unlock()
+
+		logutil.LogEvent(logger, resp.Event)
+		if err := stream.Send(resp); err != nil {
+			logger.Errorf("failed to send stream response %+v: %v", resp, err)
+		}
+	}
+
+	return tablerestore.NewTableRestorer(s.ts, s.tmc, s.ws.Environment()).RestoreTables(ctx, req, send)
+}
+
+// ResumeDMLJob is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) ResumeDMLJob(ctx context.Context, req *vtctldatapb.ResumeDMLJobRequest) (resp *vtctldatapb.ResumeDMLJobResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.ResumeDMLJob")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("uuid", req.Uuid)
+
+	rowsAffectedByShard, err := s.updateDMLJob(ctx, req.Keyspace, req.Uuid, resumeDMLJobSql)
+	if err != nil {
+		return nil, err
+	}
+
+	resp = &vtctldatapb.ResumeDMLJobResponse{
+		RowsAffectedByShard: rowsAffectedByShard,
+	}
+	return resp, nil
+}
+
 // RetrySchemaMigration is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) RetrySchemaMigration(ctx context.Context, req *vtctldatapb.RetrySchemaMigrationRequest) (resp *vtctldatapb.RetrySchemaMigrationResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.RetrySchemaMigration")
@@ -3458,6 +4219,55 @@ func (s *VtctldServer) SetKeyspaceDurabilityPolicy(ctx context.Context, req *vtc
 	}, nil
 }
 
+// SetPartitionLifecycle is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) SetPartitionLifecycle(ctx context.Context, req *vtctldatapb.SetPartitionLifecycleRequest) (resp *vtctldatapb.SetPartitionLifecycleResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.SetPartitionLifecycle")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("table", req.Table)
+	span.Annotate("interval", req.Interval)
+	span.Annotate("future_partitions", req.FuturePartitions)
+
+	if req.Table == "" {
+		return nil, vterrors.New(vtrpcpb.Code_INVALID_ARGUMENT, "table name is required")
+	}
+	interval, err := schema.ParsePartitionInterval(req.Interval)
+	if err != nil {
+		return nil, err
+	}
+	retention, _, err := protoutil.DurationFromProto(req.Retention)
+	if err != nil {
+		return nil, err
+	}
+	if retention < 0 {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid negative retention: %v", retention)
+	}
+	span.Annotate("retention", retention.String())
+
+	query, err := sqlparser.ParseAndBind(setPartitionLifecycleSql,
+		sqltypes.StringBindVariable(req.Table),
+		sqltypes.StringBindVariable(string(interval)),
+		sqltypes.Uint64BindVariable(uint64(req.FuturePartitions)),
+		sqltypes.Int64BindVariable(int64(retention.Seconds())),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	rowsAffectedByShard, err := s.updateOnKeyspacePrimaries(ctx, req.Keyspace, query)
+	if err != nil {
+		return nil, err
+	}
+
+	resp = &vtctldatapb.SetPartitionLifecycleResponse{
+		RowsAffectedByShard: rowsAffectedByShard,
+	}
+	return resp, nil
+}
+
 // SetShardIsPrimaryServing is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) SetShardIsPrimaryServing(ctx context.Context, req *vtctldatapb.SetShardIsPrimaryServingRequest) (resp *vtctldatapb.SetShardIsPrimaryServingResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.SetShardIsPrimaryServing")
@@ -47,6 +47,7 @@ import (
 	"vitess.io/vitess/go/vt/vtctl/localvtctldclient"
 	"vitess.io/vitess/go/vt/vtctl/schematools"
 	"vitess.io/vitess/go/vt/vtenv"
+	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vttablet/tmclient"
 	"vitess.io/vitess/go/vt/vttablet/tmclienttest"
 
@@ -59,6 +60,7 @@ import (
 	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
 	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
 	vtctlservicepb "vitess.io/vitess/go/vt/proto/vtctlservice"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 )
 
 func init() {
@@ -231,6 +233,130 @@ func TestAddCellsAlias(t *testing.T) {
 	}
 }
 
+func TestApplyQueryRules(t *testing.T) {
+	t.Parallel()
+
+	existing := []*topodatapb.QueryRule{
+		{Name: "r1", Rule: `{"Description":"","Name":"r1","Action":"FAIL"}`},
+		{Name: "r2", Rule: `{"Description":"","Name":"r2","Action":"FAIL"}`},
+		{Name: "expired", Rule: `{"Description":"","Name":"expired","Action":"FAIL"}`, ExpireTime: protoutil.TimeToProto(time.Now().Add(-time.Minute))},
+	}
+	tests := []struct {
+		name      string
+		req       *vtctldatapb.ApplyQueryRulesRequest
+		expected  []*topodatapb.QueryRule
+		expires   bool
+		shouldErr string
+	}{
+		{
+			name: "success",
+			req: &vtctldatapb.ApplyQueryRulesRequest{
+				Rules:       `[{"Name": "r2", "Query": "select.*", "Action": "FAIL_RETRY"}, {"Name": "r3", "DryRun": true}]`,
+				Keyspace:    "ks",
+				Shards:      []string{"-80"},
+				TabletTypes: []topodatapb.TabletType{topodatapb.TabletType_REPLICA},
+			},
+			expected: []*topodatapb.QueryRule{
+				existing[0],
+				{Name: "r2", Rule: `{"Description":"","Name":"r2","Query":"select.*","Action":"FAIL_RETRY"}`, Keyspace: "ks", Shards: []string{"-80"}, TabletTypes: []topodatapb.TabletType{topodatapb.TabletType_REPLICA}},
+				{Name: "r3", Rule: `{"Description":"","Name":"r3","Action":"FAIL","DryRun":true}`, Keyspace: "ks", Shards: []string{"-80"}, TabletTypes: []topodatapb.TabletType{topodatapb.TabletType_REPLICA}},
+			},
+		},
+		{
+			name: "dry run with ttl",
+			req: &vtctldatapb.ApplyQueryRulesRequest{
+				Rules:  `[{"Name": "r4"}]`,
+				Ttl:    protoutil.DurationToProto(time.Hour),
+				DryRun: true,
+			},
+			expected: []*topodatapb.QueryRule{
+				existing[0],
+				existing[1],
+				{Name: "r4", Rule: `{"Description":"","Name":"r4","Action":"FAIL","DryRun":true}`},
+			},
+			expires: true,
+		},
+		{
+			name:      "invalid rule",
+			req:       &vtctldatapb.ApplyQueryRulesRequest{Rules: `[{"Name": "r4", "Action": "IGNORE"}]`},
+			shouldErr: "invalid Action IGNORE",
+		},
+		{
+			name:      "no rules",
+			req:       &vtctldatapb.ApplyQueryRulesRequest{Rules: `[]`},
+			shouldErr: "no query rules to apply",
+		},
+		{
+			name:      "missing name",
+			req:       &vtctldatapb.ApplyQueryRulesRequest{Rules: `[{"Query": "select.*"}]`},
+			shouldErr: "query rules must have a Name",
+		},
+		{
+			name:      "duplicate name",
+			req:       &vtctldatapb.ApplyQueryRulesRequest{Rules: `[{"Name": "r4"}, {"Name": "r4"}]`},
+			shouldErr: "duplicate query rule r4",
+		},
+		{
+			name:      "unknown keyspace",
+			req:       &vtctldatapb.ApplyQueryRulesRequest{Rules: `[{"Name": "r4"}]`, Keyspace: "unknown"},
+			shouldErr: "node doesn't exist",
+		},
+		{
+			name:      "shards without keyspace",
+			req:       &vtctldatapb.ApplyQueryRulesRequest{Rules: `[{"Name": "r4"}]`, Shards: []string{"-80"}},
+			shouldErr: "query rules can only be limited to shards of a keyspace",
+		},
+		{
+			name:      "unknown shard",
+			req:       &vtctldatapb.ApplyQueryRulesRequest{Rules: `[{"Name": "r4"}]`, Keyspace: "ks", Shards: []string{"80-"}},
+			shouldErr: "node doesn't exist",
+		},
+		{
+			name:      "negative ttl",
+			req:       &vtctldatapb.ApplyQueryRulesRequest{Rules: `[{"Name": "r4"}]`, Ttl: protoutil.DurationToProto(-time.Minute)},
+			shouldErr: "invalid ttl -1m0s, it must be positive",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			ts := memorytopo.NewServer(ctx, "zone1")
+			testutil.AddShards(ctx, t, ts, &vtctldatapb.Shard{Keyspace: "ks", Name: "-80"})
+			_, err := ts.UpdateQueryRules(ctx, func(queryRules *topodatapb.QueryRules) error {
+				queryRules.Rules = existing
+				return nil
+			})
+			require.NoError(t, err)
+
+			vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+				return NewVtctldServer(vtenv.NewTestEnv(), ts)
+			})
+			resp, err := vtctld.ApplyQueryRules(ctx, tt.req)
+			if tt.shouldErr != "" {
+				assert.ErrorContains(t, err, tt.shouldErr)
+				return
+			}
+			require.NoError(t, err)
+
+			queryRules, err := ts.GetQueryRules(ctx)
+			require.NoError(t, err)
+			utils.MustMatch(t, queryRules, resp.QueryRules)
+
+			applied := queryRules.Rules[len(queryRules.Rules)-1]
+			if tt.expires {
+				require.NotNil(t, applied.ExpireTime)
+				assert.WithinDuration(t, time.Now().Add(time.Hour), protoutil.TimeFromProto(applied.ExpireTime), time.Minute)
+				applied.ExpireTime = nil
+			}
+			utils.MustMatch(t, tt.expected, queryRules.Rules)
+		})
+	}
+}
+
 func TestApplyRoutingRules(t *testing.T) {
 	t.Parallel()
 
@@ -344,6 +470,105 @@ func TestApplyRoutingRules(t *testing.T) {
 	}
 }
 
+func TestApplyTenantQuotas(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	quotas := &vschemapb.TenantQuotas{
+		TenantKey: "tag",
+		Quotas: []*vschemapb.TenantQuota{
+			{Tenant: "*", Qps: 100},
+			{Tenant: "acme", Qps: 10, MaxShards: 2},
+		},
+	}
+	tests := []struct {
+		name           string
+		cells          []string
+		req            *vtctldatapb.ApplyTenantQuotasRequest
+		expectedQuotas *vschemapb.TenantQuotas
+		topoDown       bool
+		shouldErr      bool
+	}{
+		{
+			name:  "success",
+			cells: []string{"zone1"},
+			req: &vtctldatapb.ApplyTenantQuotasRequest{
+				TenantQuotas: quotas,
+			},
+			expectedQuotas: quotas,
+		},
+		{
+			name:  "invalid quotas",
+			cells: []string{"zone1"},
+			req: &vtctldatapb.ApplyTenantQuotasRequest{
+				TenantQuotas: &vschemapb.TenantQuotas{
+					Quotas: []*vschemapb.TenantQuota{{Tenant: "acme", MaxShards: -1}},
+				},
+			},
+			shouldErr: true,
+		},
+		{
+			name:  "rebuild failed (bad cell)",
+			cells: []string{"zone1"},
+			req: &vtctldatapb.ApplyTenantQuotasRequest{
+				TenantQuotas: quotas,
+				RebuildCells: []string{"zone1", "zone2"},
+			},
+			shouldErr: true,
+		},
+		{
+			// this test case is exactly like the previous, but we don't fail
+			// because we don't rebuild the vschema graph.
+			name:  "rebuild skipped",
+			cells: []string{"zone1"},
+			req: &vtctldatapb.ApplyTenantQuotasRequest{
+				TenantQuotas: quotas,
+				SkipRebuild:  true,
+				RebuildCells: []string{"zone1", "zone2"},
+			},
+			expectedQuotas: quotas,
+		},
+		{
+			name:      "topo down",
+			cells:     []string{"zone1"},
+			req:       &vtctldatapb.ApplyTenantQuotasRequest{},
+			topoDown:  true,
+			shouldErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts, factory := memorytopo.NewServerAndFactory(ctx, tt.cells...)
+			if tt.topoDown {
+				factory.SetError(errors.New("topo down for testing"))
+			}
+
+			vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+				return NewVtctldServer(vtenv.NewTestEnv(), ts)
+			})
+			_, err := vtctld.ApplyTenantQuotas(ctx, tt.req)
+			if tt.shouldErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err, "ApplyTenantQuotas(%+v) failed", tt.req)
+
+			tq, err := ts.GetTenantQuotas(ctx)
+			require.NoError(t, err, "failed to get tenant quotas from topo to compare")
+			utils.MustMatch(t, tt.expectedQuotas, tq)
+
+			if !tt.req.SkipRebuild {
+				srvVSchema, err := ts.GetSrvVSchema(ctx, "zone1")
+				require.NoError(t, err)
+				utils.MustMatch(t, tt.expectedQuotas, srvVSchema.TenantQuotas)
+			}
+		})
+	}
+}
+
 func TestApplyVSchema(t *testing.T) {
 	t.Parallel()
 
@@ -670,6 +895,7 @@ func TestApplyVSchema(t *testing.T) {
 					ShardRoutingRules: &vschemapb.ShardRoutingRules{
 						Rules: []*vschemapb.ShardRoutingRule{},
 					},
+					TenantQuotas: &vschemapb.TenantQuotas{},
 				}
 				utils.MustMatch(t, changedSrvVSchema, finalSrvVSchema)
 			}
@@ -2163,6 +2389,57 @@ func TestForceCutOverSchemaMigration(t *testing.T) {
 func TestCompleteSchemaMigration(t *testing.T) {
 	t.Parallel()
 
+	uuid := "6842c23d_0ad8_11ef_8b5c_0a43f95f28a3"
+	primaries := []*topodatapb.Tablet{
+		{
+			Keyspace: "ks",
+			Shard:    "-80",
+			Alias: &topodatapb.TabletAlias{
+				Cell: "zone1",
+				Uid:  100,
+			},
+			Type: topodatapb.TabletType_PRIMARY,
+		},
+		{
+			Keyspace: "ks",
+			Shard:    "80-",
+			Alias: &topodatapb.TabletAlias{
+				Cell: "zone1",
+				Uid:  200,
+			},
+			Type: topodatapb.TabletType_PRIMARY,
+		},
+	}
+	migrationRow := func(row string) struct {
+		Response *querypb.QueryResult
+		Error    error
+	} {
+		return struct {
+			Response *querypb.QueryResult
+			Error    error
+		}{
+			Response: sqltypes.ResultToProto3(sqltypes.MakeTestResult(
+				sqltypes.MakeTestFields("migration_status|ready_to_complete|strategy|options", "varchar|int64|varchar|varchar"),
+				row,
+			)),
+		}
+	}
+	queryResult := func(rowsAffected uint64, err error) struct {
+		Response *querypb.QueryResult
+		Error    error
+	} {
+		return struct {
+			Response *querypb.QueryResult
+			Error    error
+		}{
+			Response: &querypb.QueryResult{RowsAffected: rowsAffected},
+			Error:    err,
+		}
+	}
+	prepareQuery := fmt.Sprintf("alter vitess_migration '%s' prepare_cutover expire '45s'", uuid)
+	commitQuery := fmt.Sprintf("alter vitess_migration '%s' commit_cutover", uuid)
+	abortQuery := fmt.Sprintf("alter vitess_migration '%s' abort_cutover", uuid)
+
 	tests := []struct {
 		name      string
 		tablets   []*topodatapb.Tablet
@@ -2334,6 +2611,237 @@ func TestCompleteSchemaMigration(t *testing.T) {
 			shouldErr: true,
 		},
 		// execute query failure
+		{
+			name:    "not a coordinated cut-over",
+			tablets: primaries,
+			tmc: &testutil.TabletManagerClient{
+				ExecuteFetchAsDbaResults: map[string]struct {
+					Response *querypb.QueryResult
+					Error    error
+				}{
+					"zone1-0000000100": migrationRow("running|1|vitess|--postpone-completion"),
+					"zone1-0000000200": migrationRow("running|0|vitess|--postpone-completion"),
+				},
+				ExecuteQueryResults: map[string]struct {
+					Response *querypb.QueryResult
+					Error    error
+				}{
+					"zone1-0000000100": {
+						Response: &querypb.QueryResult{
+							RowsAffected: 1,
+						},
+					},
+					"zone1-0000000200": {
+						Response: &querypb.QueryResult{
+							RowsAffected: 1,
+						},
+					},
+				},
+				PrimaryPositionResults: map[string]struct {
+					Position string
+					Error    error
+				}{
+					"zone1-0000000100": {},
+					"zone1-0000000200": {},
+				},
+				ReloadSchemaResults: map[string]error{
+					"zone1-0000000100": nil,
+					"zone1-0000000200": nil,
+				},
+			},
+			req: &vtctldatapb.CompleteSchemaMigrationRequest{
+				Keyspace: "ks",
+				Uuid:     uuid,
+			},
+			expected: &vtctldatapb.CompleteSchemaMigrationResponse{
+				RowsAffectedByShard: map[string]uint64{
+					"-80": 1,
+					"80-": 1,
+				},
+			},
+		},
+		{
+			name:    "coordinated cut-over",
+			tablets: primaries,
+			tmc: &testutil.TabletManagerClient{
+				ExecuteFetchAsDbaResults: map[string]struct {
+					Response *querypb.QueryResult
+					Error    error
+				}{
+					"zone1-0000000100": migrationRow("running|1|vitess|--coordinated-cutover"),
+					"zone1-0000000200": migrationRow("running|1|vitess|--coordinated-cutover"),
+				},
+				ExecuteQueryResultsByQuery: map[string]map[string]struct {
+					Response *querypb.QueryResult
+					Error    error
+				}{
+					"zone1-0000000100": {
+						prepareQuery: queryResult(0, nil),
+						commitQuery:  queryResult(1, nil),
+					},
+					"zone1-0000000200": {
+						prepareQuery: queryResult(0, nil),
+						commitQuery:  queryResult(1, nil),
+					},
+				},
+			},
+			req: &vtctldatapb.CompleteSchemaMigrationRequest{
+				Keyspace: "ks",
+				Uuid:     uuid,
+			},
+			expected: &vtctldatapb.CompleteSchemaMigrationResponse{
+				RowsAffectedByShard: map[string]uint64{
+					"-80": 1,
+					"80-": 1,
+				},
+				CutOverResultsByShard: map[string]string{
+					"-80": "committed",
+					"80-": "committed",
+				},
+			},
+		},
+		{
+			name:    "coordinated cut-over not ready on all shards",
+			tablets: primaries,
+			tmc: &testutil.TabletManagerClient{
+				ExecuteFetchAsDbaResults: map[string]struct {
+					Response *querypb.QueryResult
+					Error    error
+				}{
+					"zone1-0000000100": migrationRow("running|1|vitess|--coordinated-cutover"),
+					"zone1-0000000200": migrationRow("running|0|vitess|--coordinated-cutover"),
+				},
+				ExecuteQueryResults: map[string]struct {
+					Response *querypb.QueryResult
+					Error    error
+				}{
+					"zone1-0000000100": {
+						Response: &querypb.QueryResult{},
+					},
+					"zone1-0000000200": {
+						Response: &querypb.QueryResult{},
+					},
+				},
+			},
+			req: &vtctldatapb.CompleteSchemaMigrationRequest{
+				Keyspace: "ks",
+				Uuid:     uuid,
+			},
+			shouldErr: true,
+		},
+		{
+			name:    "coordinated cut-over prepare failure",
+			tablets: primaries,
+			tmc: &testutil.TabletManagerClient{
+				ExecuteFetchAsDbaResults: map[string]struct {
+					Response *querypb.QueryResult
+					Error    error
+				}{
+					"zone1-0000000100": migrationRow("running|1|vitess|--coordinated-cutover"),
+					"zone1-0000000200": migrationRow("running|1|vitess|--coordinated-cutover"),
+				},
+				ExecuteQueryResultsByQuery: map[string]map[string]struct {
+					Response *querypb.QueryResult
+					Error    error
+				}{
+					"zone1-0000000100": {
+						prepareQuery: queryResult(0, nil),
+						abortQuery:   queryResult(0, nil),
+					},
+					"zone1-0000000200": {
+						prepareQuery: queryResult(0, assert.AnError),
+						abortQuery:   queryResult(0, nil),
+					},
+				},
+			},
+			req: &vtctldatapb.CompleteSchemaMigrationRequest{
+				Keyspace: "ks",
+				Uuid:     uuid,
+			},
+			expected: &vtctldatapb.CompleteSchemaMigrationResponse{
+				RowsAffectedByShard: map[string]uint64{},
+				CutOverResultsByShard: map[string]string{
+					"-80": "aborted",
+					"80-": "prepare failed: " + assert.AnError.Error(),
+				},
+			},
+		},
+		{
+			name:    "coordinated cut-over abort failure",
+			tablets: primaries,
+			tmc: &testutil.TabletManagerClient{
+				ExecuteFetchAsDbaResults: map[string]struct {
+					Response *querypb.QueryResult
+					Error    error
+				}{
+					"zone1-0000000100": migrationRow("running|1|vitess|--coordinated-cutover"),
+					"zone1-0000000200": migrationRow("running|1|vitess|--coordinated-cutover"),
+				},
+				ExecuteQueryResultsByQuery: map[string]map[string]struct {
+					Response *querypb.QueryResult
+					Error    error
+				}{
+					"zone1-0000000100": {
+						prepareQuery: queryResult(0, nil),
+						abortQuery:   queryResult(0, assert.AnError),
+					},
+					"zone1-0000000200": {
+						prepareQuery: queryResult(0, assert.AnError),
+						abortQuery:   queryResult(0, nil),
+					},
+				},
+			},
+			req: &vtctldatapb.CompleteSchemaMigrationRequest{
+				Keyspace: "ks",
+				Uuid:     uuid,
+			},
+			expected: &vtctldatapb.CompleteSchemaMigrationResponse{
+				RowsAffectedByShard: map[string]uint64{},
+				CutOverResultsByShard: map[string]string{
+					"-80": "abort failed: " + assert.AnError.Error(),
+					"80-": "prepare failed: " + assert.AnError.Error(),
+				},
+			},
+		},
+		{
+			name:    "coordinated cut-over commit failure",
+			tablets: primaries,
+			tmc: &testutil.TabletManagerClient{
+				ExecuteFetchAsDbaResults: map[string]struct {
+					Response *querypb.QueryResult
+					Error    error
+				}{
+					"zone1-0000000100": migrationRow("running|1|vitess|--coordinated-cutover"),
+					"zone1-0000000200": migrationRow("running|1|vitess|--coordinated-cutover"),
+				},
+				ExecuteQueryResultsByQuery: map[string]map[string]struct {
+					Response *querypb.QueryResult
+					Error    error
+				}{
+					"zone1-0000000100": {
+						prepareQuery: queryResult(0, nil),
+						commitQuery:  queryResult(1, nil),
+					},
+					"zone1-0000000200": {
+						prepareQuery: queryResult(0, nil),
+						commitQuery:  queryResult(0, assert.AnError),
+					},
+				},
+			},
+			req: &vtctldatapb.CompleteSchemaMigrationRequest{
+				Keyspace: "ks",
+				Uuid:     uuid,
+			},
+			expected: &vtctldatapb.CompleteSchemaMigrationResponse{
+				RowsAffectedByShard: map[string]uint64{
+					"-80": 1,
+				},
+				CutOverResultsByShard: map[string]string{
+					"-80": "committed",
+					"80-": "commit failed: " + assert.AnError.Error(),
+				},
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -3287,6 +3795,38 @@ func TestDeleteKeyspace(t *testing.T) {
 	}
 }
 
+func TestDeleteQueryRule(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1")
+	r1 := &topodatapb.QueryRule{Name: "r1", Rule: `{"Name":"r1"}`}
+	_, err := ts.UpdateQueryRules(ctx, func(queryRules *topodatapb.QueryRules) error {
+		queryRules.Rules = []*topodatapb.QueryRule{
+			r1,
+			{Name: "r2", Rule: `{"Name":"r2"}`, ExpireTime: protoutil.TimeToProto(time.Now().Add(time.Hour))},
+			{Name: "expired", Rule: `{"Name":"expired"}`, ExpireTime: protoutil.TimeToProto(time.Now().Add(-time.Minute))},
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(vtenv.NewTestEnv(), ts)
+	})
+	_, err = vtctld.DeleteQueryRule(ctx, &vtctldatapb.DeleteQueryRuleRequest{Name: "r2"})
+	require.NoError(t, err)
+
+	queryRules, err := ts.GetQueryRules(ctx)
+	require.NoError(t, err)
+	utils.MustMatch(t, &topodatapb.QueryRules{Rules: []*topodatapb.QueryRule{r1}}, queryRules)
+
+	_, err = vtctld.DeleteQueryRule(ctx, &vtctldatapb.DeleteQueryRuleRequest{Name: "r2"})
+	assert.Equal(t, vtrpcpb.Code_NOT_FOUND, vterrors.Code(err))
+	assert.ErrorContains(t, err, "query rule r2 not found")
+}
+
 func TestDeleteShards(t *testing.T) {
 	t.Parallel()
 
@@ -5722,6 +6262,34 @@ func TestGetPermissions(t *testing.T) {
 	}
 }
 
+func TestGetQueryRules(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts, factory := memorytopo.NewServerAndFactory(ctx, "zone1")
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(vtenv.NewTestEnv(), ts)
+	})
+
+	resp, err := vtctld.GetQueryRules(ctx, &vtctldatapb.GetQueryRulesRequest{})
+	require.NoError(t, err)
+	utils.MustMatch(t, &topodatapb.QueryRules{}, resp.QueryRules)
+
+	queryRules, err := ts.UpdateQueryRules(ctx, func(queryRules *topodatapb.QueryRules) error {
+		queryRules.Rules = []*topodatapb.QueryRule{{Name: "r1", Rule: `{"Name":"r1"}`, Keyspace: "ks"}}
+		return nil
+	})
+	require.NoError(t, err)
+	resp, err = vtctld.GetQueryRules(ctx, &vtctldatapb.GetQueryRulesRequest{})
+	require.NoError(t, err)
+	utils.MustMatch(t, queryRules, resp.QueryRules)
+
+	factory.SetError(errors.New("topo down for testing"))
+	_, err = vtctld.GetQueryRules(ctx, &vtctldatapb.GetQueryRulesRequest{})
+	assert.Error(t, err)
+}
+
 func TestGetRoutingRules(t *testing.T) {
 	t.Parallel()
 
@@ -5989,6 +6557,167 @@ func TestGetSchema(t *testing.T) {
 	}
 }
 
+func TestGetSchemaHistory(t *testing.T) {
+	t.Parallel()
+
+	historyRows := func(rows ...string) *querypb.QueryResult {
+		return sqltypes.ResultToProto3(sqltypes.MakeTestResult(
+			sqltypes.MakeTestFields(
+				"id|pos|time_updated|table_name|ddl|migration_uuid|create_statement_before|create_statement_after",
+				"int64|varchar|int64|varchar|blob|varchar|text|text",
+			),
+			rows...,
+		))
+	}
+	tablets := []*topodatapb.Tablet{
+		{
+			Keyspace: "ks",
+			Shard:    "80-",
+			Alias: &topodatapb.TabletAlias{
+				Cell: "zone1",
+				Uid:  200,
+			},
+			Type: topodatapb.TabletType_PRIMARY,
+		},
+		{
+			Keyspace: "ks",
+			Shard:    "-80",
+			Alias: &topodatapb.TabletAlias{
+				Cell: "zone1",
+				Uid:  100,
+			},
+			Type: topodatapb.TabletType_PRIMARY,
+		},
+	}
+
+	tests := []struct {
+		name    string
+		results map[string]struct {
+			Response *querypb.QueryResult
+			Error    error
+		}
+		req       *vtctldatapb.GetSchemaHistoryRequest
+		expected  *vtctldatapb.GetSchemaHistoryResponse
+		shouldErr bool
+	}{
+		{
+			name: "entries by shard",
+			results: map[string]struct {
+				Response *querypb.QueryResult
+				Error    error
+			}{
+				"zone1-0000000100": {
+					Response: historyRows(
+						"2|MySQL56/7b04699f-f5e9-11e9-bf88-9cb6d089e1c3:1-12|1715081422|t1|alter table t1 add column j int||CREATE TABLE `t1` (`i` int)|CREATE TABLE `t1` (`i` int, `j` int)",
+						"1|MySQL56/7b04699f-f5e9-11e9-bf88-9cb6d089e1c3:1-11|1715081421|t1|create table t1 (i int)|||CREATE TABLE `t1` (`i` int)",
+					),
+				},
+				"zone1-0000000200": {
+					Response: historyRows(
+						"1|MySQL56/8b04699f-f5e9-11e9-bf88-9cb6d089e1c3:1-20|1715081422|t1|alter table t1 add column j int||null|CREATE TABLE `t1` (`i` int, `j` int)",
+					),
+				},
+			},
+			req: &vtctldatapb.GetSchemaHistoryRequest{
+				Keyspace: "ks",
+				Table:    "t1",
+				Limit:    2,
+			},
+			expected: &vtctldatapb.GetSchemaHistoryResponse{
+				Entries: []*vtctldatapb.SchemaHistoryEntry{
+					{
+						Keyspace:              "ks",
+						Shard:                 "-80",
+						Id:                    2,
+						Position:              "MySQL56/7b04699f-f5e9-11e9-bf88-9cb6d089e1c3:1-12",
+						Time:                  protoutil.TimeToProto(time.Unix(1715081422, 0)),
+						Table:                 "t1",
+						Ddl:                   "alter table t1 add column j int",
+						CreateStatementBefore: "CREATE TABLE `t1` (`i` int)",
+						CreateStatementAfter:  "CREATE TABLE `t1` (`i` int, `j` int)",
+					},
+					{
+						Keyspace:             "ks",
+						Shard:                "-80",
+						Id:                   1,
+						Position:             "MySQL56/7b04699f-f5e9-11e9-bf88-9cb6d089e1c3:1-11",
+						Time:                 protoutil.TimeToProto(time.Unix(1715081421, 0)),
+						Table:                "t1",
+						Ddl:                  "create table t1 (i int)",
+						CreateStatementAfter: "CREATE TABLE `t1` (`i` int)",
+					},
+					{
+						Keyspace:                     "ks",
+						Shard:                        "80-",
+						Id:                           1,
+						Position:                     "MySQL56/8b04699f-f5e9-11e9-bf88-9cb6d089e1c3:1-20",
+						Time:                         protoutil.TimeToProto(time.Unix(1715081422, 0)),
+						Table:                        "t1",
+						Ddl:                          "alter table t1 add column j int",
+						CreateStatementAfter:         "CREATE TABLE `t1` (`i` int, `j` int)",
+						CreateStatementBeforeUnknown: true,
+					},
+				},
+			},
+		},
+		{
+			name: "no history",
+			results: map[string]struct {
+				Response *querypb.QueryResult
+				Error    error
+			}{
+				"zone1-0000000100": {Response: historyRows()},
+				"zone1-0000000200": {Response: historyRows()},
+			},
+			req: &vtctldatapb.GetSchemaHistoryRequest{
+				Keyspace: "ks",
+			},
+			expected: &vtctldatapb.GetSchemaHistoryResponse{},
+		},
+		{
+			name: "execute fetch failure",
+			results: map[string]struct {
+				Response *querypb.QueryResult
+				Error    error
+			}{
+				"zone1-0000000100": {Response: historyRows()},
+				"zone1-0000000200": {Error: assert.AnError},
+			},
+			req: &vtctldatapb.GetSchemaHistoryRequest{
+				Keyspace: "ks",
+			},
+			shouldErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			ts := memorytopo.NewServer(ctx, "zone1")
+			testutil.AddTablets(ctx, t, ts, &testutil.AddTabletOptions{AlsoSetShardPrimary: true}, tablets...)
+
+			tmc := &testutil.TabletManagerClient{
+				ExecuteFetchAsDbaResults: test.results,
+			}
+			vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, tmc, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+				return NewVtctldServer(vtenv.NewTestEnv(), ts)
+			})
+
+			resp, err := vtctld.GetSchemaHistory(ctx, test.req)
+			if test.shouldErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			utils.MustMatch(t, test.expected, resp)
+		})
+	}
+}
+
 func TestGetSchemaMigrations(t *testing.T) {
 	t.Parallel()
 
@@ -7688,6 +8417,71 @@ func TestGetTablets(t *testing.T) {
 	}
 }
 
+func TestGetTenantQuotas(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		topoDown  bool
+		tqIn      *vschemapb.TenantQuotas
+		expected  *vschemapb.TenantQuotas
+		shouldErr bool
+	}{
+		{
+			name: "success",
+			tqIn: &vschemapb.TenantQuotas{
+				DryRun: true,
+				Quotas: []*vschemapb.TenantQuota{{Tenant: "acme", RowsPerMinute: 1000}},
+			},
+			expected: &vschemapb.TenantQuotas{
+				DryRun: true,
+				Quotas: []*vschemapb.TenantQuota{{Tenant: "acme", RowsPerMinute: 1000}},
+			},
+		},
+		{
+			name:     "empty tenant quotas",
+			tqIn:     nil,
+			expected: &vschemapb.TenantQuotas{},
+		},
+		{
+			name:      "topo error",
+			topoDown:  true,
+			shouldErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			ts, factory := memorytopo.NewServerAndFactory(ctx)
+			if tt.tqIn != nil {
+				err := ts.SaveTenantQuotas(ctx, tt.tqIn)
+				require.NoError(t, err, "could not save tenant quotas: %+v", tt.tqIn)
+			}
+
+			if tt.topoDown {
+				factory.SetError(errors.New("topo down for testing"))
+			}
+
+			vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+				return NewVtctldServer(vtenv.NewTestEnv(), ts)
+			})
+			resp, err := vtctld.GetTenantQuotas(ctx, &vtctldatapb.GetTenantQuotasRequest{})
+			if tt.shouldErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			utils.MustMatch(t, tt.expected, resp.TenantQuotas)
+		})
+	}
+}
+
 func TestGetTopologyPath(t *testing.T) {
 	t.Parallel()
 
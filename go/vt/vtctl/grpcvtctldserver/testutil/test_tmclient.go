@@ -228,6 +228,12 @@ type TabletManagerClient struct {
 		Response *querypb.QueryResult
 		Error    error
 	}
+	// keyed by tablet alias, then by query. Takes precedence over
+	// ExecuteQueryResults.
+	ExecuteQueryResultsByQuery map[string]map[string]struct {
+		Response *querypb.QueryResult
+		Error    error
+	}
 	// FullStatus result
 	FullStatusResult *replicationdatapb.FullStatus
 	// keyed by tablet alias.
@@ -608,7 +614,7 @@ func (fake *TabletManagerClient) ExecuteHook(ctx context.Context, tablet *topoda
 
 // ExecuteQuery is part of the tmclient.TabletManagerClient interface.
 func (fake *TabletManagerClient) ExecuteQuery(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.ExecuteQueryRequest) (*querypb.QueryResult, error) {
-	if fake.ExecuteQueryResults == nil {
+	if fake.ExecuteQueryResults == nil && fake.ExecuteQueryResultsByQuery == nil {
 		return nil, fmt.Errorf("%w: no ExecuteQuery results on fake TabletManagerClient", assert.AnError)
 	}
 
@@ -623,6 +629,9 @@ func (fake *TabletManagerClient) ExecuteQuery(ctx context.Context, tablet *topod
 			}
 		}
 	}
+	if result, ok := fake.ExecuteQueryResultsByQuery[key][string(req.Query)]; ok {
+		return result.Response, result.Error
+	}
 	if result, ok := fake.ExecuteQueryResults[key]; ok {
 		return result.Response, result.Error
 	}
@@ -39,6 +39,11 @@ func (client *localVtctldClient) AddCellsAlias(ctx context.Context, in *vtctldat
 	return client.s.AddCellsAlias(ctx, in)
 }
 
+// ApplyQueryRules is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) ApplyQueryRules(ctx context.Context, in *vtctldatapb.ApplyQueryRulesRequest, opts ...grpc.CallOption) (*vtctldatapb.ApplyQueryRulesResponse, error) {
+	return client.s.ApplyQueryRules(ctx, in)
+}
+
 // ApplyRoutingRules is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) ApplyRoutingRules(ctx context.Context, in *vtctldatapb.ApplyRoutingRulesRequest, opts ...grpc.CallOption) (*vtctldatapb.ApplyRoutingRulesResponse, error) {
 	return client.s.ApplyRoutingRules(ctx, in)
@@ -54,6 +59,11 @@ func (client *localVtctldClient) ApplyShardRoutingRules(ctx context.Context, in
 	return client.s.ApplyShardRoutingRules(ctx, in)
 }
 
+// ApplyTenantQuotas is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) ApplyTenantQuotas(ctx context.Context, in *vtctldatapb.ApplyTenantQuotasRequest, opts ...grpc.CallOption) (*vtctldatapb.ApplyTenantQuotasResponse, error) {
+	return client.s.ApplyTenantQuotas(ctx, in)
+}
+
 // ApplyVSchema is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) ApplyVSchema(ctx context.Context, in *vtctldatapb.ApplyVSchemaRequest, opts ...grpc.CallOption) (*vtctldatapb.ApplyVSchemaResponse, error) {
 	return client.s.ApplyVSchema(ctx, in)
@@ -161,6 +171,11 @@ func (client *localVtctldClient) BackupShard(ctx context.Context, in *vtctldatap
 	return stream, nil
 }
 
+// CancelDMLJob is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) CancelDMLJob(ctx context.Context, in *vtctldatapb.CancelDMLJobRequest, opts ...grpc.CallOption) (*vtctldatapb.CancelDMLJobResponse, error) {
+	return client.s.CancelDMLJob(ctx, in)
+}
+
 // CancelSchemaMigration is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) CancelSchemaMigration(ctx context.Context, in *vtctldatapb.CancelSchemaMigrationRequest, opts ...grpc.CallOption) (*vtctldatapb.CancelSchemaMigrationResponse, error) {
 	return client.s.CancelSchemaMigration(ctx, in)
@@ -171,6 +186,11 @@ func (client *localVtctldClient) ChangeTabletType(ctx context.Context, in *vtctl
 	return client.s.ChangeTabletType(ctx, in)
 }
 
+// ChangeVindexCreate is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) ChangeVindexCreate(ctx context.Context, in *vtctldatapb.ChangeVindexCreateRequest, opts ...grpc.CallOption) (*vtctldatapb.ChangeVindexCreateResponse, error) {
+	return client.s.ChangeVindexCreate(ctx, in)
+}
+
 // CleanupSchemaMigration is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) CleanupSchemaMigration(ctx context.Context, in *vtctldatapb.CleanupSchemaMigrationRequest, opts ...grpc.CallOption) (*vtctldatapb.CleanupSchemaMigrationResponse, error) {
 	return client.s.CleanupSchemaMigration(ctx, in)
@@ -206,6 +226,16 @@ func (client *localVtctldClient) DeleteKeyspace(ctx context.Context, in *vtctlda
 	return client.s.DeleteKeyspace(ctx, in)
 }
 
+// DeletePartitionLifecycle is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) DeletePartitionLifecycle(ctx context.Context, in *vtctldatapb.DeletePartitionLifecycleRequest, opts ...grpc.CallOption) (*vtctldatapb.DeletePartitionLifecycleResponse, error) {
+	return client.s.DeletePartitionLifecycle(ctx, in)
+}
+
+// DeleteQueryRule is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) DeleteQueryRule(ctx context.Context, in *vtctldatapb.DeleteQueryRuleRequest, opts ...grpc.CallOption) (*vtctldatapb.DeleteQueryRuleResponse, error) {
+	return client.s.DeleteQueryRule(ctx, in)
+}
+
 // DeleteShards is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) DeleteShards(ctx context.Context, in *vtctldatapb.DeleteShardsRequest, opts ...grpc.CallOption) (*vtctldatapb.DeleteShardsResponse, error) {
 	return client.s.DeleteShards(ctx, in)
@@ -276,6 +306,11 @@ func (client *localVtctldClient) GetCellsAliases(ctx context.Context, in *vtctld
 	return client.s.GetCellsAliases(ctx, in)
 }
 
+// GetDMLJobs is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) GetDMLJobs(ctx context.Context, in *vtctldatapb.GetDMLJobsRequest, opts ...grpc.CallOption) (*vtctldatapb.GetDMLJobsResponse, error) {
+	return client.s.GetDMLJobs(ctx, in)
+}
+
 // GetFullStatus is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) GetFullStatus(ctx context.Context, in *vtctldatapb.GetFullStatusRequest, opts ...grpc.CallOption) (*vtctldatapb.GetFullStatusResponse, error) {
 	return client.s.GetFullStatus(ctx, in)
@@ -291,11 +326,21 @@ func (client *localVtctldClient) GetKeyspaces(ctx context.Context, in *vtctldata
 	return client.s.GetKeyspaces(ctx, in)
 }
 
+// GetPartitionLifecycles is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) GetPartitionLifecycles(ctx context.Context, in *vtctldatapb.GetPartitionLifecyclesRequest, opts ...grpc.CallOption) (*vtctldatapb.GetPartitionLifecyclesResponse, error) {
+	return client.s.GetPartitionLifecycles(ctx, in)
+}
+
 // GetPermissions is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) GetPermissions(ctx context.Context, in *vtctldatapb.GetPermissionsRequest, opts ...grpc.CallOption) (*vtctldatapb.GetPermissionsResponse, error) {
 	return client.s.GetPermissions(ctx, in)
 }
 
+// GetQueryRules is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) GetQueryRules(ctx context.Context, in *vtctldatapb.GetQueryRulesRequest, opts ...grpc.CallOption) (*vtctldatapb.GetQueryRulesResponse, error) {
+	return client.s.GetQueryRules(ctx, in)
+}
+
 // GetRoutingRules is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) GetRoutingRules(ctx context.Context, in *vtctldatapb.GetRoutingRulesRequest, opts ...grpc.CallOption) (*vtctldatapb.GetRoutingRulesResponse, error) {
 	return client.s.GetRoutingRules(ctx, in)
@@ -306,6 +351,11 @@ func (client *localVtctldClient) GetSchema(ctx context.Context, in *vtctldatapb.
 	return client.s.GetSchema(ctx, in)
 }
 
+// GetSchemaHistory is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) GetSchemaHistory(ctx context.Context, in *vtctldatapb.GetSchemaHistoryRequest, opts ...grpc.CallOption) (*vtctldatapb.GetSchemaHistoryResponse, error) {
+	return client.s.GetSchemaHistory(ctx, in)
+}
+
 // GetSchemaMigrations is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) GetSchemaMigrations(ctx context.Context, in *vtctldatapb.GetSchemaMigrationsRequest, opts ...grpc.CallOption) (*vtctldatapb.GetSchemaMigrationsResponse, error) {
 	return client.s.GetSchemaMigrations(ctx, in)
@@ -356,6 +406,11 @@ func (client *localVtctldClient) GetTablets(ctx context.Context, in *vtctldatapb
 	return client.s.GetTablets(ctx, in)
 }
 
+// GetTenantQuotas is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) GetTenantQuotas(ctx context.Context, in *vtctldatapb.GetTenantQuotasRequest, opts ...grpc.CallOption) (*vtctldatapb.GetTenantQuotasResponse, error) {
+	return client.s.GetTenantQuotas(ctx, in)
+}
+
 // GetTopologyPath is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) GetTopologyPath(ctx context.Context, in *vtctldatapb.GetTopologyPathRequest, opts ...grpc.CallOption) (*vtctldatapb.GetTopologyPathResponse, error) {
 	return client.s.GetTopologyPath(ctx, in)
@@ -396,6 +451,16 @@ func (client *localVtctldClient) LookupVindexExternalize(ctx context.Context, in
 	return client.s.LookupVindexExternalize(ctx, in)
 }
 
+// LookupVindexRepair is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) LookupVindexRepair(ctx context.Context, in *vtctldatapb.LookupVindexRepairRequest, opts ...grpc.CallOption) (*vtctldatapb.LookupVindexRepairResponse, error) {
+	return client.s.LookupVindexRepair(ctx, in)
+}
+
+// LookupVindexValidate is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) LookupVindexValidate(ctx context.Context, in *vtctldatapb.LookupVindexValidateRequest, opts ...grpc.CallOption) (*vtctldatapb.LookupVindexValidateResponse, error) {
+	return client.s.LookupVindexValidate(ctx, in)
+}
+
 // MaterializeCreate is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) MaterializeCreate(ctx context.Context, in *vtctldatapb.MaterializeCreateRequest, opts ...grpc.CallOption) (*vtctldatapb.MaterializeCreateResponse, error) {
 	return client.s.MaterializeCreate(ctx, in)
@@ -436,6 +501,11 @@ func (client *localVtctldClient) MoveTablesCreate(ctx context.Context, in *vtctl
 	return client.s.MoveTablesCreate(ctx, in)
 }
 
+// PauseDMLJob is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) PauseDMLJob(ctx context.Context, in *vtctldatapb.PauseDMLJobRequest, opts ...grpc.CallOption) (*vtctldatapb.PauseDMLJobResponse, error) {
+	return client.s.PauseDMLJob(ctx, in)
+}
+
 // PingTablet is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) PingTablet(ctx context.Context, in *vtctldatapb.PingTabletRequest, opts ...grpc.CallOption) (*vtctldatapb.PingTabletResponse, error) {
 	return client.s.PingTablet(ctx, in)
@@ -557,6 +627,62 @@ func (client *localVtctldClient) RestoreFromBackup(ctx context.Context, in *vtct
 	return stream, nil
 }
 
+type restoreTableStreamAdapter struct {
+	*grpcshim.BidiStream
+	ch chan *vtctldatapb.RestoreTableResponse
+}
+
+func (stream *restoreTableStreamAdapter) Recv() (*vtctldatapb.RestoreTableResponse, error) {
+	select {
+	case <-stream.Context().Done():
+		return nil, stream.Context().Err()
+	case <-stream.Closed():
+		// Stream has been closed for future sends. If there are messages that
+		// have already been sent, receive them until there are no more. After
+		// all sent messages have been received, Recv will return the CloseErr.
+		select {
+		case msg := <-stream.ch:
+			return msg, nil
+		default:
+			return nil, stream.CloseErr()
+		}
+	case err := <-stream.ErrCh:
+		return nil, err
+	case msg := <-stream.ch:
+		return msg, nil
+	}
+}
+
+func (stream *restoreTableStreamAdapter) Send(msg *vtctldatapb.RestoreTableResponse) error {
+	select {
+	case <-stream.Context().Done():
+		return stream.Context().Err()
+	case <-stream.Closed():
+		return grpcshim.ErrStreamClosed
+	case stream.ch <- msg:
+		return nil
+	}
+}
+
+// RestoreTable is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) RestoreTable(ctx context.Context, in *vtctldatapb.RestoreTableRequest, opts ...grpc.CallOption) (vtctlservicepb.Vtctld_RestoreTableClient, error) {
+	stream := &restoreTableStreamAdapter{
+		BidiStream: grpcshim.NewBidiStream(ctx),
+		ch:         make(chan *vtctldatapb.RestoreTableResponse, 1),
+	}
+	go func() {
+		err := client.s.RestoreTable(in, stream)
+		stream.CloseWithError(err)
+	}()
+
+	return stream, nil
+}
+
+// ResumeDMLJob is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) ResumeDMLJob(ctx context.Context, in *vtctldatapb.ResumeDMLJobRequest, opts ...grpc.CallOption) (*vtctldatapb.ResumeDMLJobResponse, error) {
+	return client.s.ResumeDMLJob(ctx, in)
+}
+
 // RetrySchemaMigration is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) RetrySchemaMigration(ctx context.Context, in *vtctldatapb.RetrySchemaMigrationRequest, opts ...grpc.CallOption) (*vtctldatapb.RetrySchemaMigrationResponse, error) {
 	return client.s.RetrySchemaMigration(ctx, in)
@@ -572,6 +698,11 @@ func (client *localVtctldClient) SetKeyspaceDurabilityPolicy(ctx context.Context
 	return client.s.SetKeyspaceDurabilityPolicy(ctx, in)
 }
 
+// SetPartitionLifecycle is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) SetPartitionLifecycle(ctx context.Context, in *vtctldatapb.SetPartitionLifecycleRequest, opts ...grpc.CallOption) (*vtctldatapb.SetPartitionLifecycleResponse, error) {
+	return client.s.SetPartitionLifecycle(ctx, in)
+}
+
 // SetShardIsPrimaryServing is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) SetShardIsPrimaryServing(ctx context.Context, in *vtctldatapb.SetShardIsPrimaryServingRequest, opts ...grpc.CallOption) (*vtctldatapb.SetShardIsPrimaryServingResponse, error) {
 	return client.s.SetShardIsPrimaryServing(ctx, in)
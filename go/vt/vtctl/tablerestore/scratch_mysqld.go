@@ -0,0 +1,219 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tablerestore
+
+import (
+	"context"
+	"crypto/rand"
+	"math"
+	"math/big"
+	"net"
+	"os"
+	"time"
+
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/dbconfigs"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstats"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/schema"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/tabletenv"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/vstreamer"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+const (
+	// scratchMysqldInitTimeout is how long to wait for a scratch mysqld to
+	// be initialized and started.
+	scratchMysqldInitTimeout = 5 * time.Minute
+	// scratchMysqldShutdownTimeout is how long to wait for a scratch mysqld to
+	// shut down.
+	scratchMysqldShutdownTimeout = 5 * time.Minute
+	// scratchRestoreConcurrency is the number of files restored in parallel
+	// into a scratch mysqld.
+	scratchRestoreConcurrency = 4
+)
+
+// scratchMysqld is a mysqld which only lives for the duration of a
+// RestoreTable operation, and into which the backups of one shard are
+// restored.
+type scratchMysqld interface {
+	// GetSchema returns the definitions of the given tables.
+	GetSchema(ctx context.Context, tables []string) (*tabletmanagerdatapb.SchemaDefinition, error)
+	// Exec runs the queries, on a single connection, as the dba user.
+	Exec(ctx context.Context, queries []string) error
+	// StreamRows streams the rows of the query, which selects from a single
+	// table, with the VReplication row streamer: in primary key order from a
+	// consistent snapshot, starting after lastPK if it is set.
+	StreamRows(ctx context.Context, query string, lastPK []sqltypes.Value, send func(*binlogdatapb.VStreamRowsResponse) error) error
+	// Close shuts down the mysqld and removes all of its files.
+	Close()
+}
+
+// scratchParams are the parameters of the restore into a scratch mysqld.
+type scratchParams struct {
+	keyspace           string
+	shard              string
+	dbName             string
+	restoreToPos       replication.Position
+	restoreToTimestamp time.Time
+	logger             logutil.Logger
+}
+
+// mysqlctlScratchMysqld is the scratchMysqld run by the vtctld itself through
+// mysqlctl, the same way vtbackup runs its mysqld.
+type mysqlctlScratchMysqld struct {
+	uid    uint32
+	dbName string
+	mysqld *mysqlctl.Mysqld
+	mycnf  *mysqlctl.Mycnf
+	se     *schema.Engine
+	vse    *vstreamer.Engine
+}
+
+// startScratchMysqld starts a new mysqld on the vtctld host, restores the
+// backups of the shard into it, and opens a row streamer on it. The mysqld
+// uses a random uid, so its files are in their own directory of $VTDATAROOT.
+func (tr *TableRestorer) startScratchMysqld(ctx context.Context, params *scratchParams) (_ scratchMysqld, err error) {
+	bigN, err := rand.Int(rand.Reader, big.NewInt(math.MaxUint32))
+	if err != nil {
+		return nil, vterrors.Wrap(err, "can't generate random uid")
+	}
+	port, err := getFreePort()
+	if err != nil {
+		return nil, vterrors.Wrap(err, "can't find a free port")
+	}
+	uid := uint32(bigN.Uint64())
+	sm := &mysqlctlScratchMysqld{
+		uid:    uid,
+		dbName: params.dbName,
+		mycnf:  mysqlctl.NewMycnf(uid, port),
+	}
+	if err := sm.mycnf.RandomizeMysqlServerID(); err != nil {
+		return nil, vterrors.Wrap(err, "can't generate random MySQL server_id")
+	}
+	// The global DBConfigs are shared by the shards that are restored
+	// concurrently, so each scratch mysqld uses its own copy.
+	dbcfgs := dbconfigs.GlobalDBConfigs.Clone()
+	dbcfgs.DBName = params.dbName
+	dbcfgs.InitWithSocket(sm.mycnf.SocketFile, tr.env.CollationEnv())
+	sm.mysqld = mysqlctl.NewMysqld(dbcfgs)
+	defer func() {
+		if err != nil {
+			sm.Close()
+		}
+	}()
+
+	params.logger.Infof("Starting scratch mysqld in %s", mysqlctl.TabletDir(sm.uid))
+	initCtx, initCancel := context.WithTimeout(ctx, scratchMysqldInitTimeout)
+	defer initCancel()
+	if err := sm.mysqld.Init(initCtx, sm.mycnf, ""); err != nil {
+		return nil, vterrors.Wrap(err, "failed to initialize scratch mysqld")
+	}
+	_, err = mysqlctl.Restore(ctx, mysqlctl.RestoreParams{
+		Cnf:                  sm.mycnf,
+		Mysqld:               sm.mysqld,
+		Logger:               params.logger,
+		Concurrency:          scratchRestoreConcurrency,
+		HookExtraEnv:         map[string]string{},
+		DeleteBeforeRestore:  true,
+		DbName:               params.dbName,
+		Keyspace:             params.keyspace,
+		Shard:                params.shard,
+		RestoreToPos:         params.restoreToPos,
+		RestoreToTimestamp:   params.restoreToTimestamp,
+		Stats:                backupstats.RestoreStats(),
+		MysqlShutdownTimeout: scratchMysqldShutdownTimeout,
+	})
+	switch {
+	case err == mysqlctl.ErrNoBackup:
+		return nil, vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "no backup found for shard %s/%s", params.keyspace, params.shard)
+	case err != nil:
+		return nil, vterrors.Wrap(err, "failed to restore backup into scratch mysqld")
+	}
+	// The restored mysqld starts in super read-only mode, like the tablets.
+	// The scratch mysqld never serves any traffic, so the filter of the
+	// request can be applied by deleting rows from it.
+	if _, err := sm.mysqld.SetSuperReadOnly(false); err != nil {
+		return nil, vterrors.Wrap(err, "failed to disable super_read_only on scratch mysqld")
+	}
+
+	config := tabletenv.NewDefaultConfig()
+	config.DB = dbcfgs
+	env := tabletenv.NewEnv(tr.env, config, "RestoreTable")
+	sm.se = schema.NewEngine(env)
+	sm.vse = vstreamer.NewEngine(env, nil, sm.se, nil, "")
+	sm.vse.InitDBConfig(params.keyspace, params.shard)
+	sm.se.InitDBConfig(dbcfgs.AllPrivsWithDB())
+	if err := sm.se.Open(); err != nil {
+		return nil, vterrors.Wrap(err, "failed to load the schema of scratch mysqld")
+	}
+	sm.vse.Open()
+	return sm, nil
+}
+
+// GetSchema is part of the scratchMysqld interface.
+func (sm *mysqlctlScratchMysqld) GetSchema(ctx context.Context, tables []string) (*tabletmanagerdatapb.SchemaDefinition, error) {
+	return sm.mysqld.GetSchema(ctx, sm.dbName, &tabletmanagerdatapb.GetSchemaRequest{Tables: tables})
+}
+
+// Exec is part of the scratchMysqld interface.
+func (sm *mysqlctlScratchMysqld) Exec(ctx context.Context, queries []string) error {
+	return sm.mysqld.ExecuteSuperQueryList(ctx, queries)
+}
+
+// StreamRows is part of the scratchMysqld interface.
+func (sm *mysqlctlScratchMysqld) StreamRows(ctx context.Context, query string, lastPK []sqltypes.Value, send func(*binlogdatapb.VStreamRowsResponse) error) error {
+	return sm.vse.StreamRows(ctx, query, lastPK, send)
+}
+
+// Close is part of the scratchMysqld interface.
+func (sm *mysqlctlScratchMysqld) Close() {
+	if sm.vse != nil {
+		sm.vse.Close()
+	}
+	if sm.se != nil {
+		sm.se.Close()
+	}
+	// Don't use the context of the request, so the mysqld is shut down even
+	// if the request was canceled.
+	ctx, cancel := context.WithTimeout(context.Background(), scratchMysqldShutdownTimeout+10*time.Second)
+	defer cancel()
+	if err := sm.mysqld.Shutdown(ctx, sm.mycnf, false, scratchMysqldShutdownTimeout); err != nil {
+		log.Errorf("Failed to shut down scratch mysqld %d: %v", sm.uid, err)
+	}
+	sm.mysqld.Close()
+	if err := os.RemoveAll(mysqlctl.TabletDir(sm.uid)); err != nil {
+		log.Errorf("Failed to remove scratch mysqld directory %s: %v", mysqlctl.TabletDir(sm.uid), err)
+	}
+}
+
+// getFreePort returns a TCP port that is not in use on the local host.
+func getFreePort() (int, error) {
+	l, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		return 0, err
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
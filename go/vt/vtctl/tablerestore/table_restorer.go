@@ -0,0 +1,490 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tablerestore restores individual tables from backups into a live
+// keyspace.
+//
+// The backups of each shard are first restored, up to a point in time, into a
+// scratch mysqld that the vtctld runs through mysqlctl, so none of the tablets
+// of the keyspace are touched. The rows of the requested tables are then
+// streamed from the scratch mysqld with the VReplication row streamer, in
+// primary key order from a consistent snapshot, and copied into new tables on
+// the shard's primary in batches, checking the primary's throttler before each
+// batch. A copy that was interrupted can be resumed after the last row of the
+// new tables.
+//
+// The vtctld host therefore needs the mysqld binaries, and enough room in
+// $VTDATAROOT for the restored backups of the shards.
+package tablerestore
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/sqlescape"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/concurrency"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vtenv"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/throttle/throttlerapp"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	logutilpb "vitess.io/vitess/go/vt/proto/logutil"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+const (
+	// DefaultTargetTableSuffix is appended to the name of a restored table when
+	// no suffix is provided.
+	DefaultTargetTableSuffix = "_restored"
+	// DefaultBatchSize is the number of rows copied in a single statement when
+	// no batch size is provided.
+	DefaultBatchSize = 1000
+)
+
+// throttleCheckInterval is how long to wait before checking the throttler on
+// a primary again, after it has throttled the copy.
+var throttleCheckInterval = time.Second
+
+// TableRestorer performs RestoreTable operations.
+type TableRestorer struct {
+	ts  *topo.Server
+	tmc tmclient.TabletManagerClient
+	env *vtenv.Environment
+
+	// newScratchMysqld is startScratchMysqld, except in tests.
+	newScratchMysqld func(ctx context.Context, params *scratchParams) (scratchMysqld, error)
+}
+
+// shardRestore holds what is needed to restore the tables of one shard.
+type shardRestore struct {
+	keyspace string
+	shard    string
+	primary  *topodatapb.Tablet
+	logger   logutil.Logger
+}
+
+// NewTableRestorer returns a new TableRestorer object, ready to perform
+// RestoreTable operations using the given topo.Server, TabletManagerClient,
+// and environment.
+func NewTableRestorer(ts *topo.Server, tmc tmclient.TabletManagerClient, env *vtenv.Environment) *TableRestorer {
+	tr := &TableRestorer{
+		ts:  ts,
+		tmc: tmc,
+		env: env,
+	}
+	tr.newScratchMysqld = tr.startScratchMysqld
+	return tr
+}
+
+// RestoreTables restores the tables of the request, calling send for every
+// log event of the operation. The calls to send for different shards may
+// happen concurrently.
+func (tr *TableRestorer) RestoreTables(ctx context.Context, req *vtctldatapb.RestoreTableRequest, send func(*vtctldatapb.RestoreTableResponse)) error {
+	if len(req.Tables) == 0 {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "no tables provided")
+	}
+	restoreToTimestamp := protoutil.TimeFromProto(req.RestoreToTimestamp).UTC()
+	if req.RestoreToPos != "" && !restoreToTimestamp.IsZero() {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "only one of restore_to_pos and restore_to_timestamp can be provided")
+	}
+	var restoreToPos replication.Position
+	if req.RestoreToPos != "" {
+		var err error
+		if restoreToPos, err = replication.DecodePosition(req.RestoreToPos); err != nil {
+			return vterrors.Wrapf(err, "invalid restore_to_pos %q", req.RestoreToPos)
+		}
+	}
+	suffix := req.TargetTableSuffix
+	if suffix == "" {
+		suffix = DefaultTargetTableSuffix
+	}
+	batchSize := req.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+	var filter string
+	if req.Filter != "" {
+		expr, err := tr.env.Parser().ParseExpr(req.Filter)
+		if err != nil {
+			return vterrors.Wrapf(err, "invalid filter %q", req.Filter)
+		}
+		filter = sqlparser.String(expr)
+	}
+	targetTables := make([]string, len(req.Tables))
+	for i, table := range req.Tables {
+		targetTables[i] = table + suffix
+	}
+
+	shards, err := tr.getShardRestores(ctx, req.Keyspace, send)
+	if err != nil {
+		return err
+	}
+	if len(shards) > 1 && req.RestoreToPos != "" {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "restore_to_pos can only be used with a keyspace with a single shard, use restore_to_timestamp instead")
+	}
+	vschema, err := tr.getTargetVSchema(ctx, req.Keyspace, req.Tables, targetTables, req.Resume)
+	if err != nil {
+		return err
+	}
+	// Fail early, before running the restores, if the tables are already
+	// there and are not meant to be resumed.
+	if !req.Resume {
+		for _, shard := range shards {
+			schema, err := tr.tmc.GetSchema(ctx, shard.primary, &tabletmanagerdatapb.GetSchemaRequest{Tables: targetTables})
+			if err != nil {
+				return err
+			}
+			if len(schema.TableDefinitions) > 0 {
+				return vterrors.Errorf(vtrpcpb.Code_ALREADY_EXISTS, "table %s already exists on %s, use resume to continue copying into it",
+					schema.TableDefinitions[0].Name, topoproto.TabletAliasString(shard.primary.Alias))
+			}
+		}
+	}
+
+	wg := sync.WaitGroup{}
+	rec := concurrency.AllErrorRecorder{}
+	for _, shard := range shards {
+		wg.Add(1)
+		go func(shard *shardRestore) {
+			defer wg.Done()
+			params := &scratchParams{
+				keyspace:           shard.keyspace,
+				shard:              shard.shard,
+				dbName:             topoproto.TabletDbName(shard.primary),
+				restoreToPos:       restoreToPos,
+				restoreToTimestamp: restoreToTimestamp,
+				logger:             shard.logger,
+			}
+			if err := tr.restoreShard(ctx, req, shard, params, targetTables, filter, batchSize); err != nil {
+				shard.logger.Errorf("Failed to restore tables on shard %s/%s: %v", shard.keyspace, shard.shard, err)
+				rec.RecordError(vterrors.Wrapf(err, "shard %s/%s", shard.keyspace, shard.shard))
+			}
+		}(shard)
+	}
+	wg.Wait()
+	if rec.HasErrors() {
+		return rec.Error()
+	}
+
+	if vschema == nil {
+		return nil
+	}
+	if err := tr.ts.SaveVSchema(ctx, req.Keyspace, vschema); err != nil {
+		return err
+	}
+	return tr.ts.RebuildSrvVSchema(ctx, nil)
+}
+
+// getShardRestores returns the serving shards of the keyspace, with the
+// primary of each shard.
+func (tr *TableRestorer) getShardRestores(ctx context.Context, keyspace string, send func(*vtctldatapb.RestoreTableResponse)) ([]*shardRestore, error) {
+	servingShards, err := tr.ts.GetServingShards(ctx, keyspace)
+	if err != nil {
+		return nil, err
+	}
+	shards := make([]*shardRestore, 0, len(servingShards))
+	for _, si := range servingShards {
+		if si.PrimaryAlias == nil {
+			return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "shard %s/%s has no primary", keyspace, si.ShardName())
+		}
+		primary, err := tr.ts.GetTablet(ctx, si.PrimaryAlias)
+		if err != nil {
+			return nil, err
+		}
+		shard := si.ShardName()
+		shards = append(shards, &shardRestore{
+			keyspace: keyspace,
+			shard:    shard,
+			primary:  primary.Tablet,
+			logger: logutil.NewCallbackLogger(func(e *logutilpb.Event) {
+				send(&vtctldatapb.RestoreTableResponse{
+					Keyspace: keyspace,
+					Shard:    shard,
+					Event:    e,
+				})
+			}),
+		})
+	}
+	return shards, nil
+}
+
+// getTargetVSchema returns the vschema of the keyspace with the restored tables
+// added to it, copying the vschema of the original tables. When resuming, the
+// restored tables may already be in the vschema. It returns nil if the
+// keyspace is not sharded.
+func (tr *TableRestorer) getTargetVSchema(ctx context.Context, keyspace string, tables, targetTables []string, resume bool) (*vschemapb.Keyspace, error) {
+	vschema, err := tr.ts.GetVSchema(ctx, keyspace)
+	if err != nil {
+		return nil, err
+	}
+	if !vschema.Sharded {
+		return nil, nil
+	}
+	for i, table := range tables {
+		vtable, ok := vschema.Tables[table]
+		if !ok {
+			return nil, vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "table %s not found in the vschema for the %s keyspace", table, keyspace)
+		}
+		if _, ok := vschema.Tables[targetTables[i]]; ok {
+			if resume {
+				continue
+			}
+			return nil, vterrors.Errorf(vtrpcpb.Code_ALREADY_EXISTS, "table %s already exists in the vschema for the %s keyspace", targetTables[i], keyspace)
+		}
+		vtable = vtable.CloneVT()
+		// The restored table is not maintained by the application, so it
+		// must not drive any sequences.
+		vtable.AutoIncrement = nil
+		vschema.Tables[targetTables[i]] = vtable
+	}
+	return vschema, nil
+}
+
+// restoreShard restores the backups of a shard into a scratch mysqld, and
+// copies the tables from it to the shard's primary.
+func (tr *TableRestorer) restoreShard(ctx context.Context, req *vtctldatapb.RestoreTableRequest, shard *shardRestore, params *scratchParams, targetTables []string, filter string, batchSize int64) error {
+	sm, err := tr.newScratchMysqld(ctx, params)
+	if err != nil {
+		return err
+	}
+	defer sm.Close()
+
+	schema, err := sm.GetSchema(ctx, req.Tables)
+	if err != nil {
+		return err
+	}
+	tds := make(map[string]*tabletmanagerdatapb.TableDefinition, len(schema.TableDefinitions))
+	for _, td := range schema.TableDefinitions {
+		tds[td.Name] = td
+	}
+	for i, table := range req.Tables {
+		td, ok := tds[table]
+		if !ok {
+			return vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "table %s not found in the restored backup", table)
+		}
+		if filter != "" {
+			// The row streamer can't apply arbitrary filters, so the rows
+			// that don't match are removed from the scratch copy instead.
+			// Cascading deletes must not remove rows of the other tables.
+			shard.logger.Infof("Deleting the rows of table %s which don't match the filter", table)
+			if err := sm.Exec(ctx, []string{"set foreign_key_checks=0", buildFilterDeleteQuery(params.dbName, table, filter)}); err != nil {
+				return vterrors.Wrapf(err, "failed to apply the filter to table %s", table)
+			}
+		}
+		if err := tr.copyTable(ctx, sm, shard, td, targetTables[i], req.Resume, batchSize); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyTable creates the target table on the primary, if it does not exist yet,
+// and copies the rows of the table from the scratch mysqld into it. When
+// resuming, the copy starts after the last row of the target table.
+func (tr *TableRestorer) copyTable(ctx context.Context, sm scratchMysqld, shard *shardRestore, td *tabletmanagerdatapb.TableDefinition, targetTable string, resume bool, batchSize int64) error {
+	if len(td.PrimaryKeyColumns) == 0 {
+		return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "table %s has no primary key", td.Name)
+	}
+	createDDL, insertColumns, err := tr.getTargetTableDDL(td, targetTable)
+	if err != nil {
+		return err
+	}
+	columns := make([]string, 0, len(insertColumns))
+	for _, col := range td.Columns {
+		if insertColumns[strings.ToLower(col)] {
+			columns = append(columns, col)
+		}
+	}
+
+	schema, err := tr.tmc.GetSchema(ctx, shard.primary, &tabletmanagerdatapb.GetSchemaRequest{Tables: []string{targetTable}})
+	if err != nil {
+		return err
+	}
+	var lastPK []sqltypes.Value
+	switch {
+	case len(schema.TableDefinitions) > 0 && !resume:
+		return vterrors.Errorf(vtrpcpb.Code_ALREADY_EXISTS, "table %s already exists on %s", targetTable, topoproto.TabletAliasString(shard.primary.Alias))
+	case len(schema.TableDefinitions) > 0:
+		if lastPK, err = tr.getLastPK(ctx, shard, targetTable, td.PrimaryKeyColumns); err != nil {
+			return err
+		}
+		shard.logger.Infof("Resuming the copy of table %s into %s after primary key %v", td.Name, targetTable, lastPK)
+	default:
+		shard.logger.Infof("Creating table %s on %s", targetTable, topoproto.TabletAliasString(shard.primary.Alias))
+		if _, err := tr.tmc.ExecuteFetchAsDba(ctx, shard.primary, false, &tabletmanagerdatapb.ExecuteFetchAsDbaRequest{
+			Query:        []byte(createDDL),
+			DbName:       topoproto.TabletDbName(shard.primary),
+			ReloadSchema: true,
+		}); err != nil {
+			return vterrors.Wrapf(err, "failed to create table %s", targetTable)
+		}
+	}
+
+	var (
+		fields []*querypb.Field
+		copied int
+	)
+	err = sm.StreamRows(ctx, buildSelectQuery(td.Name, columns), lastPK, func(resp *binlogdatapb.VStreamRowsResponse) error {
+		if len(resp.Fields) > 0 {
+			fields = resp.Fields
+		}
+		rows := make([][]sqltypes.Value, len(resp.Rows))
+		for i, row := range resp.Rows {
+			rows[i] = sqltypes.MakeRowTrusted(fields, row)
+		}
+		for len(rows) > 0 {
+			batch := rows[:min(int64(len(rows)), batchSize)]
+			rows = rows[len(batch):]
+			if err := tr.waitForThrottler(ctx, shard); err != nil {
+				return err
+			}
+			if _, err := tr.tmc.ExecuteFetchAsDba(ctx, shard.primary, false, &tabletmanagerdatapb.ExecuteFetchAsDbaRequest{
+				Query:                   []byte(buildInsertQuery(targetTable, columns, batch)),
+				DbName:                  topoproto.TabletDbName(shard.primary),
+				DisableForeignKeyChecks: true,
+			}); err != nil {
+				return vterrors.Wrapf(err, "failed to copy rows into table %s", targetTable)
+			}
+			copied += len(batch)
+			shard.logger.Infof("Copied %d rows of table %s into %s", copied, td.Name, targetTable)
+		}
+		return nil
+	})
+	if err != nil {
+		return vterrors.Wrapf(err, "failed to copy table %s", td.Name)
+	}
+	shard.logger.Infof("Finished copying table %s into %s: %d rows", td.Name, targetTable, copied)
+	return nil
+}
+
+// getLastPK returns the primary key of the last row of the target table, or
+// nil if the table is empty. The rows are copied in primary key order, and
+// each batch in a single statement, so all the rows before it were copied.
+func (tr *TableRestorer) getLastPK(ctx context.Context, shard *shardRestore, targetTable string, pkColumns []string) ([]sqltypes.Value, error) {
+	escapedPK := sqlescape.EscapeIDs(pkColumns)
+	orderBy := make([]string, len(escapedPK))
+	for i, col := range escapedPK {
+		orderBy[i] = col + " desc"
+	}
+	query := fmt.Sprintf("select %s from %s order by %s limit 1", strings.Join(escapedPK, ", "), sqlescape.EscapeID(targetTable), strings.Join(orderBy, ", "))
+	qrproto, err := tr.tmc.ExecuteFetchAsDba(ctx, shard.primary, false, &tabletmanagerdatapb.ExecuteFetchAsDbaRequest{
+		Query:   []byte(query),
+		DbName:  topoproto.TabletDbName(shard.primary),
+		MaxRows: 1,
+	})
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "failed to read the last row of table %s", targetTable)
+	}
+	qr := sqltypes.Proto3ToResult(qrproto)
+	if len(qr.Rows) == 0 {
+		return nil, nil
+	}
+	return qr.Rows[0], nil
+}
+
+// getTargetTableDDL returns the CREATE TABLE statement for the target table,
+// along with the set of lower-cased columns which can be inserted into.
+func (tr *TableRestorer) getTargetTableDDL(td *tabletmanagerdatapb.TableDefinition, targetTable string) (string, map[string]bool, error) {
+	stmt, err := tr.env.Parser().ParseStrictDDL(td.Schema)
+	if err != nil {
+		return "", nil, err
+	}
+	createTable, ok := stmt.(*sqlparser.CreateTable)
+	if !ok {
+		return "", nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "expected a CREATE TABLE statement for table %s, got: %s", td.Name, td.Schema)
+	}
+	createTable.SetTable("", targetTable)
+	// Constraint names must be unique within a schema, and the restored table
+	// is only a copy of the data, so its constraints are not kept.
+	createTable.TableSpec.Constraints = nil
+	insertColumns := make(map[string]bool, len(createTable.TableSpec.Columns))
+	for _, col := range createTable.TableSpec.Columns {
+		// Generated columns cannot be inserted into.
+		if col.Type.Options != nil && col.Type.Options.As != nil {
+			continue
+		}
+		insertColumns[col.Name.Lowered()] = true
+	}
+	return sqlparser.String(createTable), insertColumns, nil
+}
+
+// waitForThrottler blocks until the throttler on the primary of the shard
+// allows the next batch of rows to be copied.
+func (tr *TableRestorer) waitForThrottler(ctx context.Context, shard *shardRestore) error {
+	for {
+		resp, err := tr.tmc.CheckThrottler(ctx, shard.primary, &tabletmanagerdatapb.CheckThrottlerRequest{
+			AppName: throttlerapp.RestoreTableName.String(),
+		})
+		if err == nil && resp.StatusCode == http.StatusOK {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(throttleCheckInterval):
+		}
+	}
+}
+
+// buildFilterDeleteQuery returns the query that deletes the rows of the table
+// which don't match the filter.
+func buildFilterDeleteQuery(dbName, table, filter string) string {
+	return fmt.Sprintf("delete from %s.%s where (%s) is not true", sqlescape.EscapeID(dbName), sqlescape.EscapeID(table), filter)
+}
+
+// buildSelectQuery returns the query that the row streamer runs to read the
+// given columns of the table.
+func buildSelectQuery(table string, columns []string) string {
+	return fmt.Sprintf("select %s from %s", strings.Join(sqlescape.EscapeIDs(columns), ", "), sqlescape.EscapeID(table))
+}
+
+// buildInsertQuery returns the query that inserts the given rows, which hold
+// the values of the given columns, into the target table.
+func buildInsertQuery(table string, columns []string, rows [][]sqltypes.Value) string {
+	buf := strings.Builder{}
+	fmt.Fprintf(&buf, "insert into %s (%s) values ", sqlescape.EscapeID(table), strings.Join(sqlescape.EscapeIDs(columns), ", "))
+	for i, row := range rows {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString("(")
+		for j, val := range row {
+			if j > 0 {
+				buf.WriteString(", ")
+			}
+			val.EncodeSQLStringBuilder(&buf)
+		}
+		buf.WriteString(")")
+	}
+	return buf.String()
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tablerestore
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/vtenv"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// fakeScratchMysqld streams the given rows of a table, in a single response.
+type fakeScratchMysqld struct {
+	scratchMysqld
+	fields []*querypb.Field
+	rows   [][]sqltypes.Value
+
+	query  string
+	lastPK []sqltypes.Value
+}
+
+func (sm *fakeScratchMysqld) StreamRows(ctx context.Context, query string, lastPK []sqltypes.Value, send func(*binlogdatapb.VStreamRowsResponse) error) error {
+	sm.query = query
+	sm.lastPK = lastPK
+	resp := &binlogdatapb.VStreamRowsResponse{Fields: sm.fields}
+	for _, row := range sm.rows {
+		resp.Rows = append(resp.Rows, sqltypes.RowToProto3(row))
+	}
+	return send(resp)
+}
+
+// fakeTMClient records the queries run on the primary, and returns the
+// given target table definitions and query results.
+type fakeTMClient struct {
+	tmclient.TabletManagerClient
+	targetTables []*tabletmanagerdatapb.TableDefinition
+	results      map[string]*querypb.QueryResult
+
+	queries []string
+}
+
+func (tmc *fakeTMClient) GetSchema(ctx context.Context, tablet *topodatapb.Tablet, request *tabletmanagerdatapb.GetSchemaRequest) (*tabletmanagerdatapb.SchemaDefinition, error) {
+	return &tabletmanagerdatapb.SchemaDefinition{TableDefinitions: tmc.targetTables}, nil
+}
+
+func (tmc *fakeTMClient) ExecuteFetchAsDba(ctx context.Context, tablet *topodatapb.Tablet, usePool bool, req *tabletmanagerdatapb.ExecuteFetchAsDbaRequest) (*querypb.QueryResult, error) {
+	tmc.queries = append(tmc.queries, string(req.Query))
+	if qr, ok := tmc.results[string(req.Query)]; ok {
+		return qr, nil
+	}
+	return &querypb.QueryResult{}, nil
+}
+
+func (tmc *fakeTMClient) CheckThrottler(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.CheckThrottlerRequest) (*tabletmanagerdatapb.CheckThrottlerResponse, error) {
+	return &tabletmanagerdatapb.CheckThrottlerResponse{StatusCode: http.StatusOK}, nil
+}
+
+func TestCopyTable(t *testing.T) {
+	td := &tabletmanagerdatapb.TableDefinition{
+		Name:              "t1",
+		Schema:            "create table t1 (id bigint not null, c int, c2 int as (c + 1), primary key (id))",
+		Columns:           []string{"id", "c", "c2"},
+		PrimaryKeyColumns: []string{"id"},
+	}
+	fields := sqltypes.MakeTestFields("id|c", "int64|int32")
+	rows := [][]sqltypes.Value{
+		{sqltypes.NewInt64(1), sqltypes.NewInt32(10)},
+		{sqltypes.NewInt64(2), sqltypes.NULL},
+		{sqltypes.NewInt64(3), sqltypes.NewInt32(30)},
+	}
+	shard := &shardRestore{
+		keyspace: "ks",
+		shard:    "0",
+		primary:  &topodatapb.Tablet{Keyspace: "ks", Shard: "0", Alias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 100}},
+		logger:   logutil.NewMemoryLogger(),
+	}
+
+	t.Run("new table", func(t *testing.T) {
+		tmc := &fakeTMClient{}
+		tr := NewTableRestorer(nil, tmc, vtenv.NewTestEnv())
+		sm := &fakeScratchMysqld{fields: fields, rows: rows}
+		err := tr.copyTable(context.Background(), sm, shard, td, "t1_restored", false, 2)
+		require.NoError(t, err)
+		assert.Equal(t, "select `id`, `c` from `t1`", sm.query)
+		assert.Nil(t, sm.lastPK)
+		assert.Equal(t, []string{
+			"create table t1_restored (\n\tid bigint not null,\n\tc int,\n\tc2 int as (c + 1) virtual,\n\tprimary key (id)\n)",
+			"insert into `t1_restored` (`id`, `c`) values (1, 10), (2, null)",
+			"insert into `t1_restored` (`id`, `c`) values (3, 30)",
+		}, tmc.queries)
+	})
+
+	t.Run("existing table", func(t *testing.T) {
+		tmc := &fakeTMClient{targetTables: []*tabletmanagerdatapb.TableDefinition{{Name: "t1_restored"}}}
+		tr := NewTableRestorer(nil, tmc, vtenv.NewTestEnv())
+		err := tr.copyTable(context.Background(), &fakeScratchMysqld{}, shard, td, "t1_restored", false, 2)
+		require.ErrorContains(t, err, "table t1_restored already exists")
+		assert.Empty(t, tmc.queries)
+	})
+
+	t.Run("resume", func(t *testing.T) {
+		lastPKQuery := "select `id` from `t1_restored` order by `id` desc limit 1"
+		tmc := &fakeTMClient{
+			targetTables: []*tabletmanagerdatapb.TableDefinition{{Name: "t1_restored"}},
+			results: map[string]*querypb.QueryResult{
+				lastPKQuery: sqltypes.ResultToProto3(sqltypes.MakeTestResult(sqltypes.MakeTestFields("id", "int64"), "2")),
+			},
+		}
+		tr := NewTableRestorer(nil, tmc, vtenv.NewTestEnv())
+		sm := &fakeScratchMysqld{fields: fields, rows: rows[2:]}
+		err := tr.copyTable(context.Background(), sm, shard, td, "t1_restored", true, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []sqltypes.Value{sqltypes.NewInt64(2)}, sm.lastPK)
+		assert.Equal(t, []string{
+			lastPKQuery,
+			"insert into `t1_restored` (`id`, `c`) values (3, 30)",
+		}, tmc.queries)
+	})
+}
+
+func TestBuildFilterDeleteQuery(t *testing.T) {
+	got := buildFilterDeleteQuery("vt_ks", "t1", "c = 1 or c = 2")
+	assert.Equal(t, "delete from `vt_ks`.`t1` where (c = 1 or c = 2) is not true", got)
+}
+
+func TestBuildInsertQuery(t *testing.T) {
+	rows := [][]sqltypes.Value{
+		{sqltypes.NewInt64(1), sqltypes.NewVarChar("o'brien")},
+		{sqltypes.NewInt64(3), sqltypes.NULL},
+	}
+	got := buildInsertQuery("t1_restored", []string{"id", "name"}, rows)
+	assert.Equal(t, "insert into `t1_restored` (`id`, `name`) values (1, 'o\\'brien'), (3, null)", got)
+}
+
+func TestGetTargetTableDDL(t *testing.T) {
+	tr := NewTableRestorer(nil, nil, vtenv.NewTestEnv())
+	td := &tabletmanagerdatapb.TableDefinition{
+		Name: "t1",
+		Schema: "create table t1 (id bigint not null, c int, c2 int as (c + 1), parent_id bigint, primary key (id), " +
+			"constraint fk_parent foreign key (parent_id) references parent (id))",
+	}
+	ddl, insertColumns, err := tr.getTargetTableDDL(td, "t1_restored")
+	require.NoError(t, err)
+	assert.NotContains(t, ddl, "fk_parent")
+	assert.Contains(t, ddl, "create table t1_restored")
+	assert.Equal(t, map[string]bool{"id": true, "c": true, "parent_id": true}, insertColumns)
+
+	td.Schema = "create view v1 as select 1 from dual"
+	_, _, err = tr.getTargetTableDDL(td, "t1_restored")
+	require.Error(t, err)
+}
@@ -0,0 +1,753 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workflow
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/time/rate"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/trace"
+	"vitess.io/vitess/go/vt/grpcclient"
+	"vitess.io/vitess/go/vt/key"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/engine"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+	"vitess.io/vitess/go/vt/vttablet/tabletconn"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+const (
+	defaultLookupVindexMaxSampleRows    = 10
+	defaultLookupVindexMaxRowsPerSecond = 100
+)
+
+// streamLookupVindexQuery streams the results of a query from a primary
+// tablet. It is a variable so that it can be replaced in tests.
+var streamLookupVindexQuery = func(ctx context.Context, tablet *topodatapb.Tablet, query string, callback func(*sqltypes.Result) error) error {
+	conn, err := tabletconn.GetDialer()(tablet, grpcclient.FailFast(false))
+	if err != nil {
+		return err
+	}
+	defer conn.Close(ctx)
+	target := &querypb.Target{
+		Keyspace:   tablet.Keyspace,
+		Shard:      tablet.Shard,
+		TabletType: tablet.Type,
+	}
+	return conn.StreamExecute(ctx, target, query, nil, 0, 0, nil, callback)
+}
+
+// lookupVindexDiffKind is the kind of difference found between the owner
+// table of a lookup vindex and its lookup table.
+type lookupVindexDiffKind int
+
+const (
+	// lookupVindexMissing is an owner row that has no lookup entry.
+	lookupVindexMissing lookupVindexDiffKind = iota
+	// lookupVindexDangling is a lookup entry that has no owner row.
+	lookupVindexDangling
+)
+
+// lookupVindexDiffer compares the rows of the owner table of a lookup
+// vindex with the entries of its lookup table. Both tables are streamed
+// from the primary tablets of all of their shards, sorted by the from
+// columns, and merge sorted the same way that VDiff does it.
+type lookupVindexDiffer struct {
+	ws *Server
+
+	vindexName string
+
+	ownerKeyspace string
+	ownerTable    string
+	ownerColumns  []string
+	// ownerVindex is the primary vindex of the owner table. It's used to
+	// compute the keyspace ids of the owner rows.
+	ownerVindex        vindexes.Vindex
+	ownerVindexColumns []string
+	ownerShards        []*topo.ShardInfo
+
+	lookupKeyspace string
+	lookupTable    string
+	fromColumns    []string
+	toColumn       string
+	ignoreNulls    bool
+	// lookupVindex is the primary vindex of the lookup table and the
+	// offsets of its columns in fromColumns. It's nil when the lookup
+	// table is in an unsharded keyspace.
+	lookupVindex        vindexes.Vindex
+	lookupVindexColumns []int
+	lookupShards        []*topo.ShardInfo
+
+	primaries map[string]*topodatapb.Tablet
+	orderBy   evalengine.Comparison
+
+	ownerRows  int64
+	lookupRows int64
+}
+
+// LookupVindexValidate compares the owner table of a lookup vindex with
+// its lookup table and reports the owner rows that have no lookup entry
+// (missing) and the lookup entries that have no owner row (dangling).
+func (s *Server) LookupVindexValidate(ctx context.Context, req *vtctldatapb.LookupVindexValidateRequest) (*vtctldatapb.LookupVindexValidateResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "workflow.Server.LookupVindexValidate")
+	defer span.Finish()
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("name", req.Name)
+	span.Annotate("table_keyspace", req.TableKeyspace)
+
+	maxSampleRows := req.MaxSampleRows
+	if maxSampleRows <= 0 {
+		maxSampleRows = defaultLookupVindexMaxSampleRows
+	}
+	d, err := s.newLookupVindexDiffer(ctx, req.Keyspace, req.Name, req.TableKeyspace)
+	if err != nil {
+		return nil, err
+	}
+	resp := &vtctldatapb.LookupVindexValidateResponse{}
+	err = d.diff(ctx, func(kind lookupVindexDiffKind, from []sqltypes.Value, ksid []byte) error {
+		entry := &vtctldatapb.LookupVindexEntry{
+			From:       make([]string, len(from)),
+			KeyspaceId: hex.EncodeToString(ksid),
+		}
+		for i, v := range from {
+			entry.From[i] = v.ToString()
+		}
+		switch kind {
+		case lookupVindexMissing:
+			resp.MissingCount++
+			if int64(len(resp.Missing)) < maxSampleRows {
+				resp.Missing = append(resp.Missing, entry)
+			}
+		case lookupVindexDangling:
+			resp.DanglingCount++
+			if int64(len(resp.Dangling)) < maxSampleRows {
+				resp.Dangling = append(resp.Dangling, entry)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp.OwnerRows = d.ownerRows
+	resp.LookupRows = d.lookupRows
+	return resp, nil
+}
+
+// LookupVindexRepair compares the owner table of a lookup vindex with its
+// lookup table, like LookupVindexValidate, and fixes the differences: the
+// dangling lookup entries are deleted and the missing ones are inserted.
+// Every difference is checked again against the owner table right before
+// it's fixed, so that rows which were changed by the application in the
+// meantime are skipped. The writes are throttled to the requested rate.
+func (s *Server) LookupVindexRepair(ctx context.Context, req *vtctldatapb.LookupVindexRepairRequest) (*vtctldatapb.LookupVindexRepairResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "workflow.Server.LookupVindexRepair")
+	defer span.Finish()
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("name", req.Name)
+	span.Annotate("table_keyspace", req.TableKeyspace)
+	span.Annotate("max_rows_per_second", req.MaxRowsPerSecond)
+
+	maxRowsPerSecond := req.MaxRowsPerSecond
+	if maxRowsPerSecond <= 0 {
+		maxRowsPerSecond = defaultLookupVindexMaxRowsPerSecond
+	}
+	d, err := s.newLookupVindexDiffer(ctx, req.Keyspace, req.Name, req.TableKeyspace)
+	if err != nil {
+		return nil, err
+	}
+	limiter := rate.NewLimiter(rate.Limit(maxRowsPerSecond), 1)
+	resp := &vtctldatapb.LookupVindexRepairResponse{}
+	err = d.diff(ctx, func(kind lookupVindexDiffKind, from []sqltypes.Value, ksid []byte) error {
+		if err := limiter.Wait(ctx); err != nil {
+			return err
+		}
+		fixed, err := d.repair(ctx, kind, from, ksid)
+		if err != nil {
+			return err
+		}
+		switch {
+		case !fixed:
+			resp.Skipped++
+		case kind == lookupVindexMissing:
+			resp.Inserted++
+		default:
+			resp.Deleted++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// newLookupVindexDiffer finds the lookup vindex, or unique constraint,
+// named name in keyspace and builds a differ for it.
+func (s *Server) newLookupVindexDiffer(ctx context.Context, keyspace, name, tableKeyspace string) (*lookupVindexDiffer, error) {
+	if keyspace == "" || name == "" {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "a keyspace and a vindex name must be provided")
+	}
+	vschema, err := s.ts.GetVSchema(ctx, keyspace)
+	if err != nil {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "failed to get vschema for the %s keyspace", keyspace)
+	}
+	vindexName := name
+	vindex := vschema.Vindexes[name]
+	if vindex == nil {
+		// Otherwise look for a unique constraint and its hidden vindex.
+		uc, ucVindex, err := findUniqueConstraint(keyspace, vschema, name)
+		if err != nil {
+			return nil, err
+		}
+		if uc != nil {
+			if vindexName, _, err = vindexes.UniqueConstraintVindex(keyspace, ucVindex.Owner, uc); err != nil {
+				return nil, err
+			}
+			vindex = ucVindex
+		}
+	}
+	if vindex == nil {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "vindex %s not found in the %s keyspace", name, keyspace)
+	}
+	switch vindex.Type {
+	case "lookup", "lookup_unique", "consistent_lookup", "consistent_lookup_unique":
+	default:
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "vindex %s is of type %s, only lookup vindexes that store keyspace ids are supported", name, vindex.Type)
+	}
+	if vindex.Owner == "" {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "vindex %s has no owner table", name)
+	}
+
+	d := &lookupVindexDiffer{
+		ws:            s,
+		vindexName:    vindexName,
+		ownerKeyspace: keyspace,
+		ownerTable:    vindex.Owner,
+		toColumn:      vindex.Params["to"],
+		primaries:     make(map[string]*topodatapb.Tablet),
+	}
+	if ignoreNulls := vindex.Params["ignore_nulls"]; ignoreNulls != "" {
+		if d.ignoreNulls, err = strconv.ParseBool(ignoreNulls); err != nil {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid ignore_nulls value %s for vindex %s", ignoreNulls, name)
+		}
+	}
+	d.lookupKeyspace, d.lookupTable, err = s.env.Parser().ParseTable(vindex.Params["table"])
+	if err != nil {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid lookup table name %s for vindex %s", vindex.Params["table"], name)
+	}
+	if d.lookupKeyspace == "" {
+		d.lookupKeyspace = keyspace
+	}
+	if tableKeyspace != "" && tableKeyspace != d.lookupKeyspace {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "the lookup table of vindex %s is in the %s keyspace, not in %s", name, d.lookupKeyspace, tableKeyspace)
+	}
+	for _, col := range strings.Split(vindex.Params["from"], ",") {
+		d.fromColumns = append(d.fromColumns, strings.TrimSpace(col))
+	}
+	if d.toColumn == "" {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "vindex %s has no to column", name)
+	}
+
+	ks, err := vindexes.BuildKeyspaceSchema(vschema, keyspace, s.env.Parser())
+	if err != nil {
+		return nil, err
+	}
+	owner := ks.Tables[d.ownerTable]
+	if owner == nil || len(owner.ColumnVindexes) == 0 {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "owner table %s of vindex %s has no primary vindex", d.ownerTable, name)
+	}
+	for _, cv := range owner.ColumnVindexes {
+		if cv.Name != vindexName {
+			continue
+		}
+		for _, col := range cv.Columns {
+			d.ownerColumns = append(d.ownerColumns, col.String())
+		}
+	}
+	if len(d.ownerColumns) != len(d.fromColumns) {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "owner table %s does not use vindex %s with %d columns", d.ownerTable, name, len(d.fromColumns))
+	}
+	primary := owner.ColumnVindexes[0]
+	if primary.Vindex.NeedsVCursor() {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "primary vindex %s of table %s needs to query the database, which is not supported", primary.Name, d.ownerTable)
+	}
+	d.ownerVindex = primary.Vindex
+	for _, col := range primary.Columns {
+		d.ownerVindexColumns = append(d.ownerVindexColumns, col.String())
+	}
+
+	lookupKs := ks
+	if d.lookupKeyspace != keyspace {
+		lookupVSchema, err := s.ts.GetVSchema(ctx, d.lookupKeyspace)
+		if err != nil {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "failed to get vschema for the %s keyspace", d.lookupKeyspace)
+		}
+		if lookupKs, err = vindexes.BuildKeyspaceSchema(lookupVSchema, d.lookupKeyspace, s.env.Parser()); err != nil {
+			return nil, err
+		}
+	}
+	if lookupKs.Keyspace.Sharded {
+		lookup := lookupKs.Tables[d.lookupTable]
+		if lookup == nil || len(lookup.ColumnVindexes) == 0 {
+			return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "lookup table %s not found in the vschema for the %s keyspace", d.lookupTable, d.lookupKeyspace)
+		}
+		primary := lookup.ColumnVindexes[0]
+		if primary.Vindex.NeedsVCursor() {
+			return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "primary vindex %s of table %s needs to query the database, which is not supported", primary.Name, d.lookupTable)
+		}
+		d.lookupVindex = primary.Vindex
+		for _, col := range primary.Columns {
+			i := -1
+			for j, from := range d.fromColumns {
+				if col.EqualString(from) {
+					i = j
+					break
+				}
+			}
+			if i == -1 {
+				return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "primary vindex column %s of lookup table %s is not a from column of vindex %s", col.String(), d.lookupTable, name)
+			}
+			d.lookupVindexColumns = append(d.lookupVindexColumns, i)
+		}
+	}
+
+	if d.ownerShards, err = s.ts.GetServingShards(ctx, keyspace); err != nil {
+		return nil, err
+	}
+	if d.lookupShards, err = s.ts.GetServingShards(ctx, d.lookupKeyspace); err != nil {
+		return nil, err
+	}
+	for _, si := range append(d.ownerShards, d.lookupShards...) {
+		if !si.HasPrimary() {
+			return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "shard %s/%s has no primary", si.Keyspace(), si.ShardName())
+		}
+		ti, err := s.ts.GetTablet(ctx, si.PrimaryAlias)
+		if err != nil {
+			return nil, err
+		}
+		d.primaries[si.Keyspace()+"/"+si.ShardName()] = ti.Tablet
+	}
+	if err := d.buildOrderBy(ctx); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// buildOrderBy builds the comparison of the from values, using the
+// collations of the owner columns.
+func (d *lookupVindexDiffer) buildOrderBy(ctx context.Context) error {
+	query := fmt.Sprintf("select %s from %s where 1 != 1", d.columnList(d.ownerColumns), sqlparser.String(sqlparser.NewIdentifierCS(d.ownerTable)))
+	var fields []*querypb.Field
+	err := streamLookupVindexQuery(ctx, d.primary(d.ownerShards[0]), query, func(qr *sqltypes.Result) error {
+		if fields == nil {
+			fields = qr.Fields
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(fields) != len(d.ownerColumns) {
+		return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "unexpected fields for the columns of table %s: %v", d.ownerTable, fields)
+	}
+	collationEnv := d.ws.env.CollationEnv()
+	d.orderBy = make(evalengine.Comparison, len(fields))
+	for i, field := range fields {
+		// If the column has no collation, compare it as bytes.
+		var collation collations.ID = collations.CollationBinaryID
+		if sqltypes.IsText(field.Type) && field.Charset != 0 {
+			collation = collations.ID(field.Charset)
+		}
+		d.orderBy[i] = evalengine.OrderByParams{Col: i, WeightStringCol: -1, Type: evalengine.NewType(sqltypes.Unknown, collation), CollationEnv: collationEnv}
+	}
+	return nil
+}
+
+// diff streams both tables and calls onDiff for every owner row that has
+// no lookup entry and for every lookup entry that has no owner row. For
+// the same from values, the dangling entries are reported before the
+// missing ones.
+func (d *lookupVindexDiffer) diff(ctx context.Context, onDiff func(kind lookupVindexDiffKind, from []sqltypes.Value, ksid []byte) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	n := len(d.fromColumns)
+	owner := d.stream(ctx, d.ownerShards, d.ownerQuery())
+	lookup := d.stream(ctx, d.lookupShards, d.lookupQuery())
+	ownerRow, err := owner.next()
+	if err != nil {
+		return err
+	}
+	lookupRow, err := lookup.next()
+	if err != nil {
+		return err
+	}
+	for ownerRow != nil || lookupRow != nil {
+		var c int
+		switch {
+		case ownerRow == nil:
+			c = 1
+		case lookupRow == nil:
+			c = -1
+		default:
+			c = d.orderBy.Compare(ownerRow, lookupRow)
+		}
+		// Gather all the keyspace ids of the smallest from values on both
+		// sides, so that non-unique vindexes are compared as multisets.
+		var from []sqltypes.Value
+		var ownerKsids, lookupKsids [][]byte
+		if c <= 0 {
+			from = ownerRow[:n]
+			for ownerRow != nil && d.orderBy.Compare(ownerRow, from) == 0 {
+				ksid, err := d.ownerKeyspaceID(ctx, ownerRow[n:])
+				if err != nil {
+					return err
+				}
+				ownerKsids = append(ownerKsids, ksid)
+				d.ownerRows++
+				if ownerRow, err = owner.next(); err != nil {
+					return err
+				}
+			}
+		}
+		if c >= 0 {
+			if from == nil {
+				from = lookupRow[:n]
+			}
+			for lookupRow != nil && d.orderBy.Compare(lookupRow, from) == 0 {
+				raw, err := lookupRow[n].ToBytes()
+				if err != nil {
+					return err
+				}
+				lookupKsids = append(lookupKsids, raw)
+				d.lookupRows++
+				if lookupRow, err = lookup.next(); err != nil {
+					return err
+				}
+			}
+		}
+		var missingKsids [][]byte
+		for _, ksid := range ownerKsids {
+			i := indexOfKeyspaceID(lookupKsids, ksid)
+			if i == -1 {
+				missingKsids = append(missingKsids, ksid)
+				continue
+			}
+			lookupKsids = append(lookupKsids[:i], lookupKsids[i+1:]...)
+		}
+		// The dangling entries come first: the entry of a unique lookup
+		// vindex that points to the wrong keyspace id has to be deleted
+		// before the right one can be inserted.
+		for _, ksid := range lookupKsids {
+			if err := onDiff(lookupVindexDangling, from, ksid); err != nil {
+				return err
+			}
+		}
+		for _, ksid := range missingKsids {
+			if err := onDiff(lookupVindexMissing, from, ksid); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// findDuplicate streams the owner table and returns the first from values
+// that are used by more than one owner row, or nil if all of them are
+// unique. Owner rows on different shards are compared too.
+func (d *lookupVindexDiffer) findDuplicate(ctx context.Context) ([]sqltypes.Value, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	n := len(d.fromColumns)
+	owner := d.stream(ctx, d.ownerShards, d.ownerQuery())
+	var prev []sqltypes.Value
+	for {
+		row, err := owner.next()
+		if err != nil || row == nil {
+			return nil, err
+		}
+		if prev != nil && d.orderBy.Compare(row, prev) == 0 {
+			return row[:n], nil
+		}
+		prev = row[:n]
+	}
+}
+
+// repair fixes one difference found by diff. It returns false if the
+// difference no longer exists in the owner table or if the lookup table
+// was not changed.
+func (d *lookupVindexDiffer) repair(ctx context.Context, kind lookupVindexDiffKind, from []sqltypes.Value, ksid []byte) (bool, error) {
+	exists, err := d.ownerRowExists(ctx, from, ksid)
+	if err != nil {
+		return false, err
+	}
+	buf := sqlparser.NewTrackedBuffer(nil)
+	switch kind {
+	case lookupVindexMissing:
+		if !exists {
+			return false, nil
+		}
+		buf.Myprintf("insert ignore into %v(%s, %v) values (", sqlparser.NewIdentifierCS(d.lookupTable), d.columnList(d.fromColumns), sqlparser.NewIdentifierCI(d.toColumn))
+		for _, v := range from {
+			v.EncodeSQLStringBuilder(buf.Builder)
+			buf.WriteString(", ")
+		}
+		buf.Myprintf("x'%s')", hex.EncodeToString(ksid))
+	case lookupVindexDangling:
+		if exists {
+			return false, nil
+		}
+		buf.Myprintf("delete from %v where ", sqlparser.NewIdentifierCS(d.lookupTable))
+		d.writeFromCondition(buf, d.fromColumns, from)
+		buf.Myprintf(" and %v = x'%s'", sqlparser.NewIdentifierCI(d.toColumn), hex.EncodeToString(ksid))
+	}
+	shard, err := d.lookupShard(ctx, from)
+	if err != nil {
+		return false, err
+	}
+	qr, err := d.ws.tmc.ExecuteFetchAsApp(ctx, d.primary(shard), true, &tabletmanagerdatapb.ExecuteFetchAsAppRequest{
+		Query:   []byte(buf.String()),
+		MaxRows: 1,
+	})
+	if err != nil {
+		return false, err
+	}
+	return qr.RowsAffected > 0, nil
+}
+
+// ownerRowExists returns true if the owner table has a row with the given
+// from values and keyspace id.
+func (d *lookupVindexDiffer) ownerRowExists(ctx context.Context, from []sqltypes.Value, ksid []byte) (bool, error) {
+	var shard *topo.ShardInfo
+	for _, si := range d.ownerShards {
+		if key.KeyRangeContains(si.KeyRange, ksid) {
+			shard = si
+			break
+		}
+	}
+	if shard == nil {
+		return false, nil
+	}
+	buf := sqlparser.NewTrackedBuffer(nil)
+	buf.Myprintf("select %s from %v where ", d.columnList(d.ownerVindexColumns), sqlparser.NewIdentifierCS(d.ownerTable))
+	d.writeFromCondition(buf, d.ownerColumns, from)
+	qr, err := d.ws.tmc.ExecuteFetchAsApp(ctx, d.primary(shard), true, &tabletmanagerdatapb.ExecuteFetchAsAppRequest{
+		Query:   []byte(buf.String()),
+		MaxRows: 10000,
+	})
+	if err != nil {
+		return false, err
+	}
+	for _, row := range sqltypes.Proto3ToResult(qr).Rows {
+		rowKsid, err := d.ownerKeyspaceID(ctx, row)
+		if err != nil {
+			return false, err
+		}
+		if bytes.Equal(rowKsid, ksid) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// lookupShard returns the shard of the lookup table that holds the entries
+// for the given from values.
+func (d *lookupVindexDiffer) lookupShard(ctx context.Context, from []sqltypes.Value) (*topo.ShardInfo, error) {
+	if d.lookupVindex == nil {
+		if len(d.lookupShards) != 1 {
+			return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "unsharded keyspace %s has %d shards", d.lookupKeyspace, len(d.lookupShards))
+		}
+		return d.lookupShards[0], nil
+	}
+	values := make([]sqltypes.Value, len(d.lookupVindexColumns))
+	for i, col := range d.lookupVindexColumns {
+		values[i] = from[col]
+	}
+	ksid, err := mapKeyspaceID(ctx, d.lookupVindex, values)
+	if err != nil {
+		return nil, err
+	}
+	for _, si := range d.lookupShards {
+		if key.KeyRangeContains(si.KeyRange, ksid) {
+			return si, nil
+		}
+	}
+	return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "no shard of the %s keyspace contains keyspace id %x", d.lookupKeyspace, ksid)
+}
+
+// ownerKeyspaceID computes the keyspace id of an owner row from the values
+// of its primary vindex columns.
+func (d *lookupVindexDiffer) ownerKeyspaceID(ctx context.Context, values []sqltypes.Value) ([]byte, error) {
+	return mapKeyspaceID(ctx, d.ownerVindex, values)
+}
+
+func (d *lookupVindexDiffer) ownerQuery() string {
+	buf := sqlparser.NewTrackedBuffer(nil)
+	buf.Myprintf("select %s, %s from %v", d.columnList(d.ownerColumns), d.columnList(d.ownerVindexColumns), sqlparser.NewIdentifierCS(d.ownerTable))
+	d.writeNotNullCondition(buf, d.ownerColumns)
+	buf.Myprintf(" order by %s", d.columnList(d.ownerColumns))
+	return buf.String()
+}
+
+func (d *lookupVindexDiffer) lookupQuery() string {
+	buf := sqlparser.NewTrackedBuffer(nil)
+	buf.Myprintf("select %s, %v from %v", d.columnList(d.fromColumns), sqlparser.NewIdentifierCI(d.toColumn), sqlparser.NewIdentifierCS(d.lookupTable))
+	d.writeNotNullCondition(buf, d.fromColumns)
+	buf.Myprintf(" order by %s", d.columnList(d.fromColumns))
+	return buf.String()
+}
+
+func (d *lookupVindexDiffer) writeNotNullCondition(buf *sqlparser.TrackedBuffer, columns []string) {
+	if !d.ignoreNulls {
+		return
+	}
+	for i, col := range columns {
+		if i == 0 {
+			buf.WriteString(" where ")
+		} else {
+			buf.WriteString(" and ")
+		}
+		buf.Myprintf("%v is not null", sqlparser.NewIdentifierCI(col))
+	}
+}
+
+func (d *lookupVindexDiffer) writeFromCondition(buf *sqlparser.TrackedBuffer, columns []string, from []sqltypes.Value) {
+	for i, col := range columns {
+		if i > 0 {
+			buf.WriteString(" and ")
+		}
+		if from[i].IsNull() {
+			buf.Myprintf("%v is null", sqlparser.NewIdentifierCI(col))
+			continue
+		}
+		buf.Myprintf("%v = ", sqlparser.NewIdentifierCI(col))
+		from[i].EncodeSQLStringBuilder(buf.Builder)
+	}
+}
+
+func (d *lookupVindexDiffer) columnList(columns []string) string {
+	escaped := make([]string, len(columns))
+	for i, col := range columns {
+		escaped[i] = sqlparser.String(sqlparser.NewIdentifierCI(col))
+	}
+	return strings.Join(escaped, ", ")
+}
+
+func (d *lookupVindexDiffer) primary(si *topo.ShardInfo) *topodatapb.Tablet {
+	return d.primaries[si.Keyspace()+"/"+si.ShardName()]
+}
+
+// stream runs query on the primaries of all shards and merge sorts the
+// results by the from columns.
+func (d *lookupVindexDiffer) stream(ctx context.Context, shards []*topo.ShardInfo, query string) *lookupVindexRowIterator {
+	ms := &engine.MergeSort{
+		OrderBy: d.orderBy[:len(d.fromColumns)],
+	}
+	for _, si := range shards {
+		ms.Primitives = append(ms.Primitives, &lookupVindexStreamer{
+			tablet: d.primary(si),
+			query:  query,
+		})
+	}
+	it := &lookupVindexRowIterator{rows: make(chan []sqltypes.Value, 100)}
+	go func() {
+		it.err = ms.TryStreamExecute(ctx, nil, nil, false, func(qr *sqltypes.Result) error {
+			for _, row := range qr.Rows {
+				select {
+				case it.rows <- row:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+			return nil
+		})
+		close(it.rows)
+	}()
+	return it
+}
+
+// lookupVindexStreamer streams the rows of a table from one shard. It
+// implements engine.StreamExecutor so that it can be merge sorted.
+type lookupVindexStreamer struct {
+	tablet *topodatapb.Tablet
+	query  string
+}
+
+// StreamExecute is part of the engine.StreamExecutor interface.
+func (st *lookupVindexStreamer) StreamExecute(ctx context.Context, vcursor engine.VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
+	return streamLookupVindexQuery(ctx, st.tablet, st.query, func(qr *sqltypes.Result) error {
+		// Only the rows are needed, the fields were already used to
+		// build the comparison.
+		return callback(&sqltypes.Result{Rows: qr.Rows})
+	})
+}
+
+// lookupVindexRowIterator returns the merge sorted rows one at a time.
+type lookupVindexRowIterator struct {
+	rows chan []sqltypes.Value
+	err  error
+}
+
+// next returns the next row, or nil once all rows have been returned.
+func (it *lookupVindexRowIterator) next() ([]sqltypes.Value, error) {
+	row, ok := <-it.rows
+	if !ok {
+		return nil, it.err
+	}
+	return row, nil
+}
+
+func mapKeyspaceID(ctx context.Context, vindex vindexes.Vindex, values []sqltypes.Value) ([]byte, error) {
+	dests, err := vindexes.Map(ctx, vindex, nil, [][]sqltypes.Value{values})
+	if err != nil {
+		return nil, err
+	}
+	ksid, ok := dests[0].(key.DestinationKeyspaceID)
+	if !ok {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "values %v do not map to a single keyspace id: %v", values, dests[0])
+	}
+	return ksid, nil
+}
+
+func indexOfKeyspaceID(ksids [][]byte, ksid []byte) int {
+	for i, k := range ksids {
+		if bytes.Equal(k, ksid) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,326 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workflow
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/key"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+// lookupVindexTestEnv has a t1 owner table with a unique lookup vindex on
+// col2, and a t1_col2_lkp lookup table, both sharded on -80 and 80-.
+type lookupVindexTestEnv struct {
+	*testMaterializerEnv
+	xxhash vindexes.Vindex
+	// owner and lookup are the rows of both tables, sorted by col2.
+	owner  [][2]int64
+	lookup []lookupVindexTestEntry
+}
+
+type lookupVindexTestEntry struct {
+	col2 int64
+	id   int64 // the id of the owner row that the keyspace id is computed from
+}
+
+func newLookupVindexTestEnv(t *testing.T, ctx context.Context) *lookupVindexTestEnv {
+	ms := &vtctldatapb.MaterializeSettings{
+		SourceKeyspace: "ks",
+		TargetKeyspace: "ks",
+	}
+	env := &lookupVindexTestEnv{
+		testMaterializerEnv: newTestMaterializerEnv(t, ctx, ms, []string{"-80", "80-"}, []string{"-80", "80-"}),
+	}
+	var err error
+	env.xxhash, err = vindexes.CreateVindex("xxhash", "xxhash", nil)
+	require.NoError(t, err)
+	err = env.topoServ.SaveVSchema(ctx, "ks", &vschemapb.Keyspace{
+		Sharded: true,
+		Vindexes: map[string]*vschemapb.Vindex{
+			"xxhash": {
+				Type: "xxhash",
+			},
+			"t1_col2_lkp": {
+				Type: "consistent_lookup_unique",
+				Params: map[string]string{
+					"table": "ks.t1_col2_lkp",
+					"from":  "col2",
+					"to":    "keyspace_id",
+				},
+				Owner: "t1",
+			},
+			"hash": {
+				Type: "hash",
+			},
+		},
+		Tables: map[string]*vschemapb.Table{
+			"t1": {
+				ColumnVindexes: []*vschemapb.ColumnVindex{{
+					Name:   "xxhash",
+					Column: "id",
+				}, {
+					Name:   "t1_col2_lkp",
+					Column: "col2",
+				}},
+			},
+			"t1_col2_lkp": {
+				ColumnVindexes: []*vschemapb.ColumnVindex{{
+					Name:   "xxhash",
+					Column: "col2",
+				}},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	origStreamLookupVindexQuery := streamLookupVindexQuery
+	streamLookupVindexQuery = env.streamQuery
+	t.Cleanup(func() {
+		streamLookupVindexQuery = origStreamLookupVindexQuery
+	})
+	return env
+}
+
+func (env *lookupVindexTestEnv) ksid(t *testing.T, id int64) []byte {
+	ksid, err := mapKeyspaceID(context.Background(), env.xxhash, []sqltypes.Value{sqltypes.NewInt64(id)})
+	require.NoError(t, err)
+	return ksid
+}
+
+// tabletID returns the id of the primary tablet of the shard that holds
+// the given keyspace id.
+func (env *lookupVindexTestEnv) tabletID(ksid []byte) int {
+	for id, tablet := range env.tablets {
+		kr, err := key.ParseShardingSpec(tablet.Shard)
+		if err != nil {
+			panic(err)
+		}
+		if key.KeyRangeContains(kr[0], ksid) {
+			return id
+		}
+	}
+	panic(fmt.Sprintf("no tablet for keyspace id %x", ksid))
+}
+
+func (env *lookupVindexTestEnv) streamQuery(ctx context.Context, tablet *topodatapb.Tablet, query string, callback func(*sqltypes.Result) error) error {
+	switch query {
+	case "select col2 from t1 where 1 != 1":
+		return callback(&sqltypes.Result{Fields: sqltypes.MakeTestFields("col2", "int64")})
+	case "select col2, id from t1 order by col2", "select col2, id from t1 where col2 is not null order by col2":
+		result := &sqltypes.Result{Fields: sqltypes.MakeTestFields("col2|id", "int64|int64")}
+		for _, row := range env.owner {
+			ksid, _ := mapKeyspaceID(ctx, env.xxhash, []sqltypes.Value{sqltypes.NewInt64(row[1])})
+			if env.tabletID(ksid) == int(tablet.Alias.Uid) {
+				result.Rows = append(result.Rows, []sqltypes.Value{sqltypes.NewInt64(row[0]), sqltypes.NewInt64(row[1])})
+			}
+		}
+		return callback(result)
+	case "select col2, keyspace_id from t1_col2_lkp order by col2":
+		result := &sqltypes.Result{Fields: sqltypes.MakeTestFields("col2|keyspace_id", "int64|varbinary")}
+		for _, entry := range env.lookup {
+			ksid, _ := mapKeyspaceID(ctx, env.xxhash, []sqltypes.Value{sqltypes.NewInt64(entry.col2)})
+			if env.tabletID(ksid) == int(tablet.Alias.Uid) {
+				ownerKsid, _ := mapKeyspaceID(ctx, env.xxhash, []sqltypes.Value{sqltypes.NewInt64(entry.id)})
+				result.Rows = append(result.Rows, []sqltypes.Value{sqltypes.NewInt64(entry.col2), sqltypes.NewVarBinary(string(ownerKsid))})
+			}
+		}
+		return callback(result)
+	}
+	return fmt.Errorf("unexpected query %s", query)
+}
+
+func TestLookupVindexValidate(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	env := newLookupVindexTestEnv(t, ctx)
+	defer env.close()
+
+	env.owner = [][2]int64{{10, 1}, {20, 2}, {30, 3}}
+	env.lookup = []lookupVindexTestEntry{
+		{col2: 10, id: 1},
+		{col2: 20, id: 1}, // wrong keyspace id
+		{col2: 40, id: 4}, // no owner row
+	}
+
+	resp, err := env.ws.LookupVindexValidate(ctx, &vtctldatapb.LookupVindexValidateRequest{
+		Keyspace: "ks",
+		Name:     "t1_col2_lkp",
+	})
+	require.NoError(t, err)
+	want := &vtctldatapb.LookupVindexValidateResponse{
+		OwnerRows:     3,
+		LookupRows:    3,
+		MissingCount:  2,
+		DanglingCount: 2,
+		Missing: []*vtctldatapb.LookupVindexEntry{
+			{From: []string{"20"}, KeyspaceId: hex.EncodeToString(env.ksid(t, 2))},
+			{From: []string{"30"}, KeyspaceId: hex.EncodeToString(env.ksid(t, 3))},
+		},
+		Dangling: []*vtctldatapb.LookupVindexEntry{
+			{From: []string{"20"}, KeyspaceId: hex.EncodeToString(env.ksid(t, 1))},
+			{From: []string{"40"}, KeyspaceId: hex.EncodeToString(env.ksid(t, 4))},
+		},
+	}
+	require.EqualValues(t, want.String(), resp.String())
+
+	// Only the requested number of samples is returned.
+	resp, err = env.ws.LookupVindexValidate(ctx, &vtctldatapb.LookupVindexValidateRequest{
+		Keyspace:      "ks",
+		Name:          "t1_col2_lkp",
+		MaxSampleRows: 1,
+	})
+	require.NoError(t, err)
+	require.EqualValues(t, 2, resp.MissingCount)
+	require.Len(t, resp.Missing, 1)
+	require.EqualValues(t, 2, resp.DanglingCount)
+	require.Len(t, resp.Dangling, 1)
+
+	errs := []struct {
+		req *vtctldatapb.LookupVindexValidateRequest
+		err string
+	}{{
+		req: &vtctldatapb.LookupVindexValidateRequest{Keyspace: "ks", Name: "nonexistent"},
+		err: "vindex nonexistent not found in the ks keyspace",
+	}, {
+		req: &vtctldatapb.LookupVindexValidateRequest{Keyspace: "ks", Name: "hash"},
+		err: "vindex hash is of type hash, only lookup vindexes that store keyspace ids are supported",
+	}, {
+		req: &vtctldatapb.LookupVindexValidateRequest{Keyspace: "ks", Name: "t1_col2_lkp", TableKeyspace: "other"},
+		err: "the lookup table of vindex t1_col2_lkp is in the ks keyspace, not in other",
+	}}
+	for _, tc := range errs {
+		_, err := env.ws.LookupVindexValidate(ctx, tc.req)
+		require.ErrorContains(t, err, tc.err)
+	}
+}
+
+func TestLookupVindexRepair(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	env := newLookupVindexTestEnv(t, ctx)
+	defer env.close()
+
+	env.owner = [][2]int64{{10, 1}, {20, 2}, {30, 3}, {50, 5}}
+	env.lookup = []lookupVindexTestEntry{
+		{col2: 10, id: 1},
+		{col2: 20, id: 1}, // wrong keyspace id
+		{col2: 40, id: 4}, // no owner row
+		{col2: 50, id: 1}, // wrong keyspace id
+	}
+	col2Ksid := func(col2 int64) []byte {
+		ksid, err := mapKeyspaceID(ctx, env.xxhash, []sqltypes.Value{sqltypes.NewInt64(col2)})
+		require.NoError(t, err)
+		return ksid
+	}
+	idResult := func(ids ...string) *sqltypes.Result {
+		return sqltypes.MakeTestResult(sqltypes.MakeTestFields("id", "int64"), ids...)
+	}
+	rowsAffected := &sqltypes.Result{RowsAffected: 1}
+	noRowsAffected := &sqltypes.Result{}
+
+	// 20 is dangling for id 1: the owner row doesn't match, so it's deleted
+	// first, which makes room for the entry of id 2.
+	env.tmc.expectVRQuery(env.tabletID(env.ksid(t, 1)), "select id from t1 where col2 = 20", idResult())
+	env.tmc.expectVRQuery(env.tabletID(col2Ksid(20)), fmt.Sprintf("delete from t1_col2_lkp where col2 = 20 and keyspace_id = x'%x'", env.ksid(t, 1)), rowsAffected)
+	// 20 is missing for id 2: the owner row still exists, so it's inserted.
+	env.tmc.expectVRQuery(env.tabletID(env.ksid(t, 2)), "select id from t1 where col2 = 20", idResult("2"))
+	env.tmc.expectVRQuery(env.tabletID(col2Ksid(20)), fmt.Sprintf("insert ignore into t1_col2_lkp(col2, keyspace_id) values (20, x'%x')", env.ksid(t, 2)), rowsAffected)
+	// 30 is missing, but the owner row was deleted in the meantime.
+	env.tmc.expectVRQuery(env.tabletID(env.ksid(t, 3)), "select id from t1 where col2 = 30", idResult())
+	// 40 is dangling, but the owner row was inserted in the meantime.
+	env.tmc.expectVRQuery(env.tabletID(env.ksid(t, 4)), "select id from t1 where col2 = 40", idResult("4"))
+	// 50 is dangling for id 1, but the owner row was updated in the
+	// meantime, so the entry is kept and the one for id 5 is ignored.
+	env.tmc.expectVRQuery(env.tabletID(env.ksid(t, 1)), "select id from t1 where col2 = 50", idResult("1"))
+	env.tmc.expectVRQuery(env.tabletID(env.ksid(t, 5)), "select id from t1 where col2 = 50", idResult("5"))
+	env.tmc.expectVRQuery(env.tabletID(col2Ksid(50)), fmt.Sprintf("insert ignore into t1_col2_lkp(col2, keyspace_id) values (50, x'%x')", env.ksid(t, 5)), noRowsAffected)
+
+	resp, err := env.ws.LookupVindexRepair(ctx, &vtctldatapb.LookupVindexRepairRequest{
+		Keyspace:         "ks",
+		Name:             "t1_col2_lkp",
+		MaxRowsPerSecond: 1000,
+	})
+	require.NoError(t, err)
+	env.tmc.verifyQueries(t)
+	want := &vtctldatapb.LookupVindexRepairResponse{
+		Inserted: 1,
+		Deleted:  1,
+		Skipped:  4,
+	}
+	require.EqualValues(t, want.String(), resp.String())
+}
+
+func TestLookupVindexExternalizeUniqueConstraint(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	env := newLookupVindexTestEnv(t, ctx)
+	defer env.close()
+
+	vschema, err := env.topoServ.GetVSchema(ctx, "ks")
+	require.NoError(t, err)
+	delete(vschema.Vindexes, "t1_col2_lkp")
+	vschema.Tables["t1"].ColumnVindexes = vschema.Tables["t1"].ColumnVindexes[:1]
+	vschema.Tables["t1"].UniqueConstraints = []*vschemapb.UniqueConstraint{{
+		Name:        "col2",
+		Columns:     []string{"col2"},
+		LookupTable: "ks.t1_col2_lkp",
+		WriteOnly:   true,
+	}}
+	require.NoError(t, env.topoServ.SaveVSchema(ctx, "ks", vschema))
+	env.tmc.workflowState = binlogdatapb.VReplicationWorkflowState_Running
+
+	req := &vtctldatapb.LookupVindexExternalizeRequest{
+		Keyspace:      "ks",
+		Name:          "col2",
+		TableKeyspace: "ks",
+	}
+
+	// The owner rows with ids 2 and 8 are on different shards and share
+	// the value 20, which the backfill silently kept only once.
+	require.NotEqual(t, env.tabletID(env.ksid(t, 2)), env.tabletID(env.ksid(t, 8)))
+	env.owner = [][2]int64{{10, 1}, {20, 2}, {20, 8}}
+	_, err = env.ws.LookupVindexExternalize(ctx, req)
+	require.ErrorContains(t, err, "unique constraint col2 has duplicate values (20) in table t1")
+	vschema, err = env.topoServ.GetVSchema(ctx, "ks")
+	require.NoError(t, err)
+	require.True(t, vschema.Tables["t1"].UniqueConstraints[0].WriteOnly)
+
+	// The streams of the workflow read from sourceks/0, which needs to
+	// exist for the workflow to be deleted.
+	env.addTablet(300, "sourceks", "0", topodatapb.TabletType_PRIMARY)
+	require.NoError(t, env.topoServ.RebuildSrvVSchema(ctx, nil))
+	env.tmc.expectVRQuery(300, "delete from _vt.vreplication where db_name = 'vt_sourceks' and workflow = 'col2_reverse'", &sqltypes.Result{})
+	env.owner = [][2]int64{{10, 1}, {20, 2}, {30, 3}}
+	_, err = env.ws.LookupVindexExternalize(ctx, req)
+	require.NoError(t, err)
+	env.tmc.verifyQueries(t)
+	vschema, err = env.topoServ.GetVSchema(ctx, "ks")
+	require.NoError(t, err)
+	require.False(t, vschema.Tables["t1"].UniqueConstraints[0].WriteOnly)
+}
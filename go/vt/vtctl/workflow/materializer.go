@@ -78,6 +78,8 @@ func (mz *materializer) getWorkflowType() binlogdatapb.VReplicationWorkflowType
 		workflowType = binlogdatapb.VReplicationWorkflowType_MoveTables
 	case vtctldatapb.MaterializationIntent_CREATELOOKUPINDEX:
 		workflowType = binlogdatapb.VReplicationWorkflowType_CreateLookupIndex
+	case vtctldatapb.MaterializationIntent_CHANGEVINDEX:
+		workflowType = binlogdatapb.VReplicationWorkflowType_ChangeVindex
 	}
 	return workflowType
 }
@@ -175,6 +175,8 @@ type testMaterializerTMClient struct {
 
 	// Used to confirm the number of times WorkflowDelete was called.
 	workflowDeleteCalls int
+	// The state of the streams returned by ReadVReplicationWorkflow.
+	workflowState binlogdatapb.VReplicationWorkflowState
 }
 
 func newTestMaterializerTMClient() *testMaterializerTMClient {
@@ -205,7 +207,8 @@ func (tmc *testMaterializerTMClient) ReadVReplicationWorkflow(ctx context.Contex
 		WorkflowType: workflowType,
 		Streams: []*tabletmanagerdatapb.ReadVReplicationWorkflowResponse_Stream{
 			{
-				Id: 1,
+				Id:    1,
+				State: tmc.workflowState,
 				Bls: &binlogdatapb.BinlogSource{
 					Keyspace: "sourceks",
 					Shard:    "0",
@@ -315,6 +318,11 @@ func (tmc *testMaterializerTMClient) ExecuteFetchAsDba(ctx context.Context, tabl
 	return tmc.VReplicationExec(ctx, tablet, string(req.Query))
 }
 
+func (tmc *testMaterializerTMClient) ExecuteFetchAsApp(ctx context.Context, tablet *topodatapb.Tablet, usePool bool, req *tabletmanagerdatapb.ExecuteFetchAsAppRequest) (*querypb.QueryResult, error) {
+	// Reuse VReplicationExec
+	return tmc.VReplicationExec(ctx, tablet, string(req.Query))
+}
+
 func (tmc *testMaterializerTMClient) ExecuteFetchAsAllPrivs(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.ExecuteFetchAsAllPrivsRequest) (*querypb.QueryResult, error) {
 	return nil, nil
 }
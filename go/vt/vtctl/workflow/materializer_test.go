@@ -1768,6 +1768,135 @@ func TestCreateLookupVindexIgnoreNulls(t *testing.T) {
 	require.Equal(t, wantQuery, ms.TableSettings[0].SourceExpression, "unexpected query")
 }
 
+func TestCreateLookupVindexUniqueConstraint(t *testing.T) {
+	ms := &vtctldatapb.MaterializeSettings{
+		SourceKeyspace: "ks",
+		TargetKeyspace: "ks",
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	env := newTestMaterializerEnv(t, ctx, ms, []string{"0"}, []string{"0"})
+	defer env.close()
+
+	specs := &vschemapb.Keyspace{
+		Tables: map[string]*vschemapb.Table{
+			"t1": {
+				UniqueConstraints: []*vschemapb.UniqueConstraint{{
+					Name:        "col2",
+					Columns:     []string{"col2"},
+					LookupTable: "ks.t1_col2_uniq",
+				}},
+			},
+		},
+	}
+	// Dummy sourceSchema
+	sourceSchema := "CREATE TABLE `t1` (\n" +
+		"  `col1` int(11) NOT NULL AUTO_INCREMENT,\n" +
+		"  `col2` int(11) DEFAULT NULL,\n" +
+		"  PRIMARY KEY (`id`)\n" +
+		") ENGINE=InnoDB AUTO_INCREMENT=3 DEFAULT CHARSET=latin1"
+
+	vschema := &vschemapb.Keyspace{
+		Sharded: true,
+		Vindexes: map[string]*vschemapb.Vindex{
+			"xxhash": {
+				Type: "xxhash",
+			},
+		},
+		Tables: map[string]*vschemapb.Table{
+			"t1": {
+				ColumnVindexes: []*vschemapb.ColumnVindex{{
+					Name:   "xxhash",
+					Column: "col1",
+				}},
+			},
+		},
+	}
+
+	// The constraint is added in write only mode, without a visible vindex.
+	wantKs := &vschemapb.Keyspace{
+		Sharded: true,
+		Vindexes: map[string]*vschemapb.Vindex{
+			"xxhash": {
+				Type: "xxhash",
+			},
+		},
+		Tables: map[string]*vschemapb.Table{
+			"t1": {
+				ColumnVindexes: []*vschemapb.ColumnVindex{{
+					Name:   "xxhash",
+					Column: "col1",
+				}},
+				UniqueConstraints: []*vschemapb.UniqueConstraint{{
+					Name:        "col2",
+					Columns:     []string{"col2"},
+					LookupTable: "ks.t1_col2_uniq",
+					WriteOnly:   true,
+				}},
+			},
+			"t1_col2_uniq": {
+				ColumnVindexes: []*vschemapb.ColumnVindex{{
+					Column: "col2",
+					Name:   "xxhash",
+				}},
+			},
+		},
+	}
+	wantQuery := "select col2 as col2, keyspace_id() as keyspace_id from t1 where col2 is not null group by col2, keyspace_id"
+
+	env.tmc.schema[ms.SourceKeyspace+".t1"] = &tabletmanagerdatapb.SchemaDefinition{
+		TableDefinitions: []*tabletmanagerdatapb.TableDefinition{{
+			Fields: []*querypb.Field{{
+				Name: "col1",
+				Type: querypb.Type_INT64,
+			}, {
+				Name: "col2",
+				Type: querypb.Type_INT64,
+			}},
+			Schema: sourceSchema,
+		}},
+	}
+	if err := env.topoServ.SaveVSchema(ctx, ms.TargetKeyspace, vschema); err != nil {
+		t.Fatal(err)
+	}
+
+	// An existing lookup table must reject duplicates through its primary key.
+	lookupTable := &tabletmanagerdatapb.TableDefinition{
+		Name:              "t1_col2_uniq",
+		PrimaryKeyColumns: []string{"col2", "keyspace_id"},
+	}
+	env.tmc.schema[ms.TargetKeyspace+".t1_col2_uniq"] = &tabletmanagerdatapb.SchemaDefinition{
+		TableDefinitions: []*tabletmanagerdatapb.TableDefinition{lookupTable},
+	}
+	_, _, _, err := env.ws.prepareCreateLookup(ctx, "col2", ms.TargetKeyspace, specs, true)
+	require.EqualError(t, err, "the primary key (col2,keyspace_id) of lookup table ks.t1_col2_uniq must be the columns (col2) of unique constraint col2")
+	lookupTable.PrimaryKeyColumns = []string{"col2"}
+
+	ms, ks, _, err := env.ws.prepareCreateLookup(ctx, "col2", ms.TargetKeyspace, specs, true)
+	require.NoError(t, err)
+	if !proto.Equal(wantKs, ks) {
+		t.Errorf("unexpected keyspace value: got:\n%v, want\n%v", ks, wantKs)
+	}
+	require.NotNil(t, ms)
+	require.Len(t, ms.TableSettings, 1)
+	require.Equal(t, "t1_col2_uniq", ms.TableSettings[0].TargetTable)
+	require.Equal(t, wantQuery, ms.TableSettings[0].SourceExpression, "unexpected query")
+	require.False(t, ms.StopAfterCopy)
+
+	// The constraint must be visible through its name when externalizing.
+	uc, vindex, err := findUniqueConstraint("ks", ks, "col2")
+	require.NoError(t, err)
+	require.Equal(t, ks.Tables["t1"].UniqueConstraints[0], uc)
+	require.Equal(t, "consistent_lookup_unique", vindex.Type)
+	require.Equal(t, "t1", vindex.Owner)
+
+	// Creating the same constraint again conflicts with the existing one.
+	require.NoError(t, env.topoServ.SaveVSchema(ctx, ms.TargetKeyspace, ks))
+	_, _, _, err = env.ws.prepareCreateLookup(ctx, "col2", ms.TargetKeyspace, specs, true)
+	require.EqualError(t, err, "a conflicting unique constraint named col2 on table t1 already exists in the ks keyspace")
+}
+
 func TestStopAfterCopyFlag(t *testing.T) {
 	ms := &vtctldatapb.MaterializeSettings{
 		SourceKeyspace: "ks",
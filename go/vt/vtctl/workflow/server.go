@@ -163,6 +163,10 @@ func (s *Server) SQLParser() *sqlparser.Parser {
 	return s.env.Parser()
 }
 
+func (s *Server) Environment() *vtenv.Environment {
+	return s.env
+}
+
 // CheckReshardingJournalExistsOnTablet returns the journal (or an empty
 // journal) and a boolean to indicate if the resharding_journal table exists on
 // the given tablet.
@@ -988,6 +992,10 @@ func (s *Server) getWorkflowState(ctx context.Context, targetKeyspace, workflowN
 					// Not a relevant rule.
 				}
 			}
+		} else if ts.workflowType == binlogdatapb.VReplicationWorkflowType_ChangeVindex {
+			if err := s.setChangeVindexTrafficState(ctx, ts, state, reverse); err != nil {
+				return nil, nil, err
+			}
 		} else {
 			state.RdonlyCellsSwitched, state.RdonlyCellsNotSwitched, err = s.GetCellsWithTableReadsSwitched(ctx, targetKeyspace, table, topodatapb.TabletType_RDONLY)
 			if err != nil {
@@ -1037,8 +1045,11 @@ func (s *Server) getWorkflowState(ctx context.Context, targetKeyspace, workflowN
 			state.WritesSwitched = true
 		}
 	}
-	if ts.workflowType == binlogdatapb.VReplicationWorkflowType_Migrate {
+	switch ts.workflowType {
+	case binlogdatapb.VReplicationWorkflowType_Migrate:
 		state.WorkflowType = TypeMigrate
+	case binlogdatapb.VReplicationWorkflowType_ChangeVindex:
+		state.WorkflowType = TypeChangeVindex
 	}
 
 	return ts, state, nil
@@ -1142,6 +1153,14 @@ func (s *Server) LookupVindexExternalize(ctx context.Context, req *vtctldatapb.L
 		return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "failed to get vschema for the %s keyspace", req.Keyspace)
 	}
 	vindex := sourceVschema.Vindexes[req.Name]
+	var uniqueConstraint *vschemapb.UniqueConstraint
+	if vindex == nil {
+		// Otherwise look for a unique constraint and its hidden vindex.
+		uniqueConstraint, vindex, err = findUniqueConstraint(req.Keyspace, sourceVschema, req.Name)
+		if err != nil {
+			return nil, err
+		}
+	}
 	if vindex == nil {
 		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "vindex %s not found in the %s keyspace", req.Name, req.Keyspace)
 	}
@@ -1206,6 +1225,28 @@ func (s *Server) LookupVindexExternalize(ctx context.Context, req *vtctldatapb.L
 		return nil, err
 	}
 
+	if uniqueConstraint != nil {
+		// The backfill groups the owner rows by the constraint columns and
+		// ignores the rows that conflict with an existing lookup entry, so
+		// duplicate values don't fail the workflow. Look for them before
+		// the constraint is enforced.
+		d, err := s.newLookupVindexDiffer(ctx, req.Keyspace, req.Name, req.TableKeyspace)
+		if err != nil {
+			return nil, err
+		}
+		dup, err := d.findDuplicate(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if dup != nil {
+			values := make([]string, len(dup))
+			for i, v := range dup {
+				values[i] = v.ToString()
+			}
+			return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "unique constraint %s has duplicate values (%s) in table %s", req.Name, strings.Join(values, ", "), vindex.Owner)
+		}
+	}
+
 	resp := &vtctldatapb.LookupVindexExternalizeResponse{}
 
 	if vindex.Owner != "" {
@@ -1224,7 +1265,11 @@ func (s *Server) LookupVindexExternalize(ctx context.Context, req *vtctldatapb.L
 	}
 
 	// Remove the write_only param and save the source vschema.
-	delete(vindex.Params, "write_only")
+	if uniqueConstraint != nil {
+		uniqueConstraint.WriteOnly = false
+	} else {
+		delete(vindex.Params, "write_only")
+	}
 	if err := s.ts.SaveVSchema(ctx, req.Keyspace, sourceVschema); err != nil {
 		return nil, err
 	}
@@ -2525,6 +2570,11 @@ func (s *Server) buildTrafficSwitcher(ctx context.Context, targetKeyspace, workf
 			if ts.tables == nil {
 				for _, rule := range bls.Filter.Rules {
 					ts.tables = append(ts.tables, rule.Match)
+					if ts.workflowType == binlogdatapb.VReplicationWorkflowType_ChangeVindex {
+						if err := ts.addSourceTable(rule, s.env.Parser()); err != nil {
+							return nil, err
+						}
+					}
 				}
 				sort.Strings(ts.tables)
 			} else {
@@ -2666,6 +2716,13 @@ func (s *Server) dropSources(ctx context.Context, ts *trafficSwitcher, removalTy
 	if err := s.dropArtifacts(ctx, keepRoutingRules, sw); err != nil {
 		return nil, err
 	}
+	// The shadow tables of a ChangeVindex workflow are only renamed once the
+	// reverse streams, which write into the source tables, are gone.
+	if !keepData && ts.MigrationType() == binlogdatapb.MigrationType_TABLES {
+		if err := sw.renameShadowTables(ctx); err != nil {
+			return nil, err
+		}
+	}
 	if err := ts.TopoServer().RebuildSrvVSchema(ctx, nil); err != nil {
 		return nil, err
 	}
@@ -3492,6 +3549,15 @@ func (s *Server) prepareCreateLookup(ctx context.Context, workflow, keyspace str
 	if specs == nil {
 		return nil, nil, nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "no vindex provided")
 	}
+	// A unique constraint is backfilled through its hidden vindex, so
+	// rewrite the specs to use that vindex instead.
+	var uniqueConstraint *vschemapb.UniqueConstraint
+	if len(specs.Vindexes) == 0 {
+		uniqueConstraint, specs, err = uniqueConstraintSpecs(keyspace, specs)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
 	if len(specs.Vindexes) != 1 {
 		return nil, nil, nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "only one vindex must be specified")
 	}
@@ -3624,6 +3690,14 @@ func (s *Server) prepareCreateLookup(ctx context.Context, workflow, keyspace str
 	if sourceVSchemaTable == nil && !schema.IsInternalOperationTableName(sourceTableName) {
 		return nil, nil, nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "table %s not found in the %s keyspace", sourceTableName, keyspace)
 	}
+	if uniqueConstraint != nil {
+		for _, uc := range sourceVSchemaTable.UniqueConstraints {
+			if uc.Name == uniqueConstraint.Name {
+				return nil, nil, nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "a conflicting unique constraint named %s on table %s already exists in the %s keyspace",
+					uc.Name, sourceTableName, keyspace)
+			}
+		}
+	}
 	for _, colVindex := range sourceVSchemaTable.ColumnVindexes {
 		// For a conflict, the vindex name and column should match.
 		if colVindex.Name != vindexName {
@@ -3656,6 +3730,11 @@ func (s *Server) prepareCreateLookup(ctx context.Context, workflow, keyspace str
 	if len(tableSchema.TableDefinitions) != 1 {
 		return nil, nil, nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "unexpected number of tables (%d) returned from %s schema", len(tableSchema.TableDefinitions), keyspace)
 	}
+	if uniqueConstraint != nil {
+		if err := s.checkUniqueConstraintLookupTable(ctx, targetKeyspace, targetTableName, uniqueConstraint); err != nil {
+			return nil, nil, nil, err
+		}
+	}
 
 	// Generate "create table" statement.
 	lines := strings.Split(tableSchema.TableDefinitions[0].Schema, "\n")
@@ -3789,12 +3868,110 @@ func (s *Server) prepareCreateLookup(ctx context.Context, workflow, keyspace str
 	}
 
 	// Update sourceVSchema
-	sourceVSchema.Vindexes[vindexName] = vindex
-	sourceVSchemaTable.ColumnVindexes = append(sourceVSchemaTable.ColumnVindexes, sourceTable.ColumnVindexes[0])
+	if uniqueConstraint != nil {
+		// Keep the constraint write only until it is externalized.
+		uniqueConstraint.WriteOnly = true
+		sourceVSchemaTable.UniqueConstraints = append(sourceVSchemaTable.UniqueConstraints, uniqueConstraint)
+	} else {
+		sourceVSchema.Vindexes[vindexName] = vindex
+		sourceVSchemaTable.ColumnVindexes = append(sourceVSchemaTable.ColumnVindexes, sourceTable.ColumnVindexes[0])
+	}
 
 	return ms, sourceVSchema, targetVSchema, nil
 }
 
+// uniqueConstraintSpecs validates specs that declare a single unique
+// constraint on the owner table, and returns that constraint along with
+// specs that declare its hidden lookup vindex instead.
+func uniqueConstraintSpecs(keyspace string, specs *vschemapb.Keyspace) (*vschemapb.UniqueConstraint, *vschemapb.Keyspace, error) {
+	var (
+		uniqueConstraint *vschemapb.UniqueConstraint
+		ownerTableName   string
+	)
+	for tableName, table := range specs.Tables {
+		if len(table.UniqueConstraints) == 0 {
+			continue
+		}
+		if len(table.UniqueConstraints) != 1 || uniqueConstraint != nil {
+			return nil, nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "only one unique constraint must be specified")
+		}
+		uniqueConstraint = table.UniqueConstraints[0]
+		ownerTableName = tableName
+	}
+	if uniqueConstraint == nil {
+		return nil, nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "only one vindex must be specified")
+	}
+	vindexName, vindex, err := vindexes.UniqueConstraintVindex(keyspace, ownerTableName, uniqueConstraint)
+	if err != nil {
+		return nil, nil, err
+	}
+	specs = proto.Clone(specs).(*vschemapb.Keyspace)
+	specs.Vindexes = map[string]*vschemapb.Vindex{vindexName: vindex}
+	specs.Tables[ownerTableName] = &vschemapb.Table{
+		ColumnVindexes: []*vschemapb.ColumnVindex{{
+			Name:    vindexName,
+			Columns: uniqueConstraint.Columns,
+		}},
+	}
+	return proto.Clone(uniqueConstraint).(*vschemapb.UniqueConstraint), specs, nil
+}
+
+// checkUniqueConstraintLookupTable checks that the lookup table of the unique
+// constraint uc, if it already exists, has the columns of the constraint as
+// its primary key. Duplicates are only rejected through that primary key: the
+// hidden vindex of a multi-column constraint is not unique, so that queries
+// on a prefix of its columns can still be routed through it.
+func (s *Server) checkUniqueConstraintLookupTable(ctx context.Context, keyspace, table string, uc *vschemapb.UniqueConstraint) error {
+	shards, err := s.ts.GetServingShards(ctx, keyspace)
+	if err != nil {
+		return err
+	}
+	if len(shards) == 0 || shards[0].PrimaryAlias == nil {
+		return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "no primary found for the %s keyspace", keyspace)
+	}
+	req := &tabletmanagerdatapb.GetSchemaRequest{Tables: []string{table}}
+	tableSchema, err := schematools.GetSchema(ctx, s.ts, s.tmc, shards[0].PrimaryAlias, req)
+	if err != nil {
+		return err
+	}
+	if len(tableSchema.TableDefinitions) == 0 {
+		// The table is created with the right primary key.
+		return nil
+	}
+	pkColumns := tableSchema.TableDefinitions[0].PrimaryKeyColumns
+	if !slices.EqualFunc(pkColumns, uc.Columns, strings.EqualFold) {
+		return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "the primary key (%s) of lookup table %s.%s must be the columns (%s) of unique constraint %s",
+			strings.Join(pkColumns, ","), keyspace, table, strings.Join(uc.Columns, ","), uc.Name)
+	}
+	return nil
+}
+
+// findUniqueConstraint returns the unique constraint named name in the
+// vschema of keyspace, along with the definition of its hidden vindex.
+// It returns nil if there is no such constraint.
+func findUniqueConstraint(keyspace string, vschema *vschemapb.Keyspace, name string) (*vschemapb.UniqueConstraint, *vschemapb.Vindex, error) {
+	var (
+		found  *vschemapb.UniqueConstraint
+		vindex *vschemapb.Vindex
+	)
+	for tableName, table := range vschema.Tables {
+		for _, uc := range table.UniqueConstraints {
+			if uc.Name != name {
+				continue
+			}
+			if found != nil {
+				return nil, nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "more than one unique constraint named %s found in the %s keyspace", name, keyspace)
+			}
+			var err error
+			if _, vindex, err = vindexes.UniqueConstraintVindex(keyspace, tableName, uc); err != nil {
+				return nil, nil, err
+			}
+			found = uc
+		}
+	}
+	return found, vindex, nil
+}
+
 func generateColDef(lines []string, sourceVindexCol, vindexFromCol string) (string, error) {
 	source := sqlescape.EscapeID(sourceVindexCol)
 	target := sqlescape.EscapeID(vindexFromCol)
@@ -39,9 +39,10 @@ type Type string
 
 // Workflow string types.
 const (
-	TypeMoveTables Type = "MoveTables"
-	TypeReshard    Type = "Reshard"
-	TypeMigrate    Type = "Migrate"
+	TypeMoveTables   Type = "MoveTables"
+	TypeReshard      Type = "Reshard"
+	TypeMigrate      Type = "Migrate"
+	TypeChangeVindex Type = "ChangeVindex"
 )
 
 var TypeStrMap = map[VReplicationWorkflowType]Type{
@@ -58,6 +58,10 @@ func (r *switcher) removeSourceTables(ctx context.Context, removalType TableRemo
 	return r.ts.removeSourceTables(ctx, removalType)
 }
 
+func (r *switcher) renameShadowTables(ctx context.Context) error {
+	return r.ts.renameShadowTables(ctx)
+}
+
 func (r *switcher) dropSourceShards(ctx context.Context) error {
 	return r.ts.dropSourceShards(ctx)
 }
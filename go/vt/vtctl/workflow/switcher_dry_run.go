@@ -228,7 +228,7 @@ func (dr *switcherDryRun) lockKeyspace(ctx context.Context, keyspace, _ string)
 func (dr *switcherDryRun) removeSourceTables(ctx context.Context, removalType TableRemovalType) error {
 	logs := make([]string, 0)
 	for _, source := range dr.ts.Sources() {
-		for _, tableName := range dr.ts.Tables() {
+		for _, tableName := range dr.ts.sourceTableNames() {
 			logs = append(logs, fmt.Sprintf("keyspace:%s;shard:%s;dbname:%s;tablet:%d;table:%s",
 				source.GetPrimary().Keyspace, source.GetPrimary().Shard, source.GetPrimary().DbName(), source.GetPrimary().Alias.Uid, tableName))
 		}
@@ -244,6 +244,19 @@ func (dr *switcherDryRun) removeSourceTables(ctx context.Context, removalType Ta
 	return nil
 }
 
+func (dr *switcherDryRun) renameShadowTables(ctx context.Context) error {
+	if dr.ts.workflowType != binlogdatapb.VReplicationWorkflowType_ChangeVindex {
+		return nil
+	}
+	renames := make([]string, 0, len(dr.ts.Tables()))
+	for _, table := range dr.ts.Tables() {
+		renames = append(renames, fmt.Sprintf("%s to %s", table, dr.ts.sourceTableName(table)))
+	}
+	dr.drLog.Logf("Renaming these shadow tables in the database and in the vschema for keyspace %s, and deleting their routing rules: [%s]",
+		dr.ts.TargetKeyspaceName(), strings.Join(renames, ","))
+	return nil
+}
+
 func (dr *switcherDryRun) dropSourceShards(ctx context.Context) error {
 	logs := make([]string, 0)
 	tabletsList := make(map[string][]string)
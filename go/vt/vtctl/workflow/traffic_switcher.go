@@ -20,6 +20,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -234,6 +235,11 @@ type trafficSwitcher struct {
 	targetTimeZone   string
 	workflowType     binlogdatapb.VReplicationWorkflowType
 	workflowSubType  binlogdatapb.VReplicationWorkflowSubType
+	// sourceTables maps each target table to the source table that it is
+	// copied from, when the two differ. This is only the case for
+	// ChangeVindex workflows, where a table is copied into a shadow table
+	// in the same keyspace.
+	sourceTables map[string]string
 }
 
 func (ts *trafficSwitcher) TopoServer() *topo.Server                          { return ts.ws.ts }
@@ -261,6 +267,38 @@ func (ts *trafficSwitcher) WorkflowName() string                           { ret
 func (ts *trafficSwitcher) SourceTimeZone() string                         { return ts.sourceTimeZone }
 func (ts *trafficSwitcher) TargetTimeZone() string                         { return ts.targetTimeZone }
 
+// sourceTableName returns the name of the source table that the given target
+// table is copied from.
+func (ts *trafficSwitcher) sourceTableName(table string) string {
+	if sourceTable, ok := ts.sourceTables[table]; ok {
+		return sourceTable
+	}
+	return table
+}
+
+// sourceTableNames returns the names of the source tables for the workflow.
+func (ts *trafficSwitcher) sourceTableNames() []string {
+	tables := make([]string, 0, len(ts.tables))
+	for _, table := range ts.tables {
+		tables = append(tables, ts.sourceTableName(table))
+	}
+	return tables
+}
+
+// addSourceTable records the source table for the given stream rule of a
+// ChangeVindex workflow, which is the table that the rule's filter selects from.
+func (ts *trafficSwitcher) addSourceTable(rule *binlogdatapb.Rule, parser *sqlparser.Parser) error {
+	sourceTable, err := parser.TableFromStatement(rule.Filter)
+	if err != nil {
+		return vterrors.Wrapf(err, "failed to find the source table for the %s table", rule.Match)
+	}
+	if ts.sourceTables == nil {
+		ts.sourceTables = make(map[string]string)
+	}
+	ts.sourceTables[rule.Match] = sourceTable.Name.String()
+	return nil
+}
+
 func (ts *trafficSwitcher) ForAllSources(f func(source *MigrationSource) error) error {
 	var wg sync.WaitGroup
 	allErrors := &concurrency.AllErrorRecorder{}
@@ -410,6 +448,19 @@ func (ts *trafficSwitcher) deleteRoutingRules(ctx context.Context) error {
 		delete(rules, ts.SourceKeyspaceName()+"."+table)
 		delete(rules, ts.SourceKeyspaceName()+"."+table+"@replica")
 		delete(rules, ts.SourceKeyspaceName()+"."+table+"@rdonly")
+		if sourceTable := ts.sourceTableName(table); sourceTable != table {
+			// Once traffic has been switched, the rules for the source table of
+			// a ChangeVindex workflow are how the application reaches the new
+			// table, so we only delete the ones still routing to the source.
+			toSource := ts.SourceKeyspaceName() + "." + sourceTable
+			for _, fromTable := range []string{sourceTable, ts.SourceKeyspaceName() + "." + sourceTable} {
+				for _, suffix := range []string{"", "@replica", "@rdonly"} {
+					if rr := rules[fromTable+suffix]; len(rr) > 0 && rr[0] == toSource {
+						delete(rules, fromTable+suffix)
+					}
+				}
+			}
+		}
 	}
 	if err := topotools.SaveRoutingRules(ctx, ts.TopoServer(), rules); err != nil {
 		return err
@@ -437,7 +488,7 @@ func (ts *trafficSwitcher) deleteShardRoutingRules(ctx context.Context) error {
 func (ts *trafficSwitcher) dropSourceDeniedTables(ctx context.Context) error {
 	return ts.ForAllSources(func(source *MigrationSource) error {
 		if _, err := ts.TopoServer().UpdateShardFields(ctx, ts.SourceKeyspaceName(), source.GetShard().ShardName(), func(si *topo.ShardInfo) error {
-			return si.UpdateDeniedTables(ctx, topodatapb.TabletType_PRIMARY, nil, true, ts.Tables())
+			return ts.removeDeniedTables(ctx, si, ts.sourceTableNames())
 		}); err != nil {
 			return err
 		}
@@ -451,7 +502,7 @@ func (ts *trafficSwitcher) dropSourceDeniedTables(ctx context.Context) error {
 func (ts *trafficSwitcher) dropTargetDeniedTables(ctx context.Context) error {
 	return ts.ForAllTargets(func(target *MigrationTarget) error {
 		if _, err := ts.TopoServer().UpdateShardFields(ctx, ts.TargetKeyspaceName(), target.GetShard().ShardName(), func(si *topo.ShardInfo) error {
-			return si.UpdateDeniedTables(ctx, topodatapb.TabletType_PRIMARY, nil, true, ts.Tables())
+			return ts.removeDeniedTables(ctx, si, ts.Tables())
 		}); err != nil {
 			return err
 		}
@@ -462,11 +513,36 @@ func (ts *trafficSwitcher) dropTargetDeniedTables(ctx context.Context) error {
 	})
 }
 
+// removeDeniedTables removes the given tables from the denied tables of the
+// shard's primary tablets. The source and target tables of a ChangeVindex
+// workflow live in the same shards, so for those we only remove the tables
+// that are currently denied: the source tables are only denied once writes
+// have been switched and the target tables never are.
+func (ts *trafficSwitcher) removeDeniedTables(ctx context.Context, si *topo.ShardInfo, tables []string) error {
+	if ts.workflowType == binlogdatapb.VReplicationWorkflowType_ChangeVindex {
+		tc := si.GetTabletControl(topodatapb.TabletType_PRIMARY)
+		if tc == nil {
+			return nil
+		}
+		var deniedTables []string
+		for _, table := range tables {
+			if slices.Contains(tc.DeniedTables, table) {
+				deniedTables = append(deniedTables, table)
+			}
+		}
+		if len(deniedTables) == 0 {
+			return nil
+		}
+		tables = deniedTables
+	}
+	return si.UpdateDeniedTables(ctx, topodatapb.TabletType_PRIMARY, nil, true, tables)
+}
+
 func (ts *trafficSwitcher) validateWorkflowHasCompleted(ctx context.Context) error {
 	return doValidateWorkflowHasCompleted(ctx, ts)
 }
 
-func (ts *trafficSwitcher) dropParticipatingTablesFromKeyspace(ctx context.Context, keyspace string) error {
+func (ts *trafficSwitcher) dropParticipatingTablesFromKeyspace(ctx context.Context, keyspace string, tables []string) error {
 	vschema, err := ts.TopoServer().GetVSchema(ctx, keyspace)
 	if err != nil {
 		return err
@@ -476,10 +552,13 @@ func (ts *trafficSwitcher) dropParticipatingTablesFromKeyspace(ctx context.Conte
 	// definitions to use -- and we should not delete them either
 	// (on workflow Cancel) as the user must create them separately
 	// and they contain information about the vindex definitions, etc.
-	if vschema.Sharded && keyspace == ts.TargetKeyspaceName() {
+	// The exception is ChangeVindex, which creates the vschema entry
+	// for the shadow table itself.
+	if vschema.Sharded && keyspace == ts.TargetKeyspaceName() &&
+		ts.workflowType != binlogdatapb.VReplicationWorkflowType_ChangeVindex {
 		return nil
 	}
-	for _, tableName := range ts.Tables() {
+	for _, tableName := range tables {
 		delete(vschema.Tables, tableName)
 	}
 	return ts.TopoServer().SaveVSchema(ctx, keyspace, vschema)
@@ -487,7 +566,7 @@ func (ts *trafficSwitcher) dropParticipatingTablesFromKeyspace(ctx context.Conte
 
 func (ts *trafficSwitcher) removeSourceTables(ctx context.Context, removalType TableRemovalType) error {
 	err := ts.ForAllSources(func(source *MigrationSource) error {
-		for _, tableName := range ts.Tables() {
+		for _, tableName := range ts.sourceTableNames() {
 			primaryDbName, err := sqlescape.EnsureEscaped(source.GetPrimary().DbName())
 			if err != nil {
 				return err
@@ -529,7 +608,70 @@ func (ts *trafficSwitcher) removeSourceTables(ctx context.Context, removalType T
 		return err
 	}
 
-	return ts.dropParticipatingTablesFromKeyspace(ctx, ts.SourceKeyspaceName())
+	return ts.dropParticipatingTablesFromKeyspace(ctx, ts.SourceKeyspaceName(), ts.sourceTableNames())
+}
+
+// renameShadowTables renames the shadow tables of a ChangeVindex workflow to
+// the names of the source tables that they replace, once those have been
+// removed. The vschema definitions of the shadow tables are moved to the
+// source table names and the routing rules between the two names are
+// deleted, so that nothing of the workflow is left behind. Queries for the
+// tables fail from the time that the shadow tables are renamed until the
+// SrvVSchema is rebuilt.
+func (ts *trafficSwitcher) renameShadowTables(ctx context.Context) error {
+	if ts.workflowType != binlogdatapb.VReplicationWorkflowType_ChangeVindex {
+		return nil
+	}
+	err := ts.ForAllTargets(func(target *MigrationTarget) error {
+		primaryDbName, err := sqlescape.EnsureEscaped(target.GetPrimary().DbName())
+		if err != nil {
+			return err
+		}
+		for _, table := range ts.Tables() {
+			sourceTable := ts.sourceTableName(table)
+			ts.Logger().Infof("%s: Renaming table %s.%s to %s.%s\n",
+				target.GetPrimary().String(), target.GetPrimary().DbName(), table, target.GetPrimary().DbName(), sourceTable)
+			query := fmt.Sprintf("rename table %s.%s TO %s.%s", primaryDbName, sqlescape.EscapeID(table), primaryDbName, sqlescape.EscapeID(sourceTable))
+			if _, err := ts.ws.tmc.ExecuteFetchAsDba(ctx, target.GetPrimary().Tablet, false, &tabletmanagerdatapb.ExecuteFetchAsDbaRequest{
+				Query:        []byte(query),
+				MaxRows:      1,
+				ReloadSchema: true,
+			}); err != nil {
+				ts.Logger().Errorf("%s: Error renaming table %s: %v", target.GetPrimary().String(), table, err)
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	vschema, err := ts.TopoServer().GetVSchema(ctx, ts.TargetKeyspaceName())
+	if err != nil {
+		return err
+	}
+	rules, err := topotools.GetRoutingRules(ctx, ts.TopoServer())
+	if err != nil {
+		return err
+	}
+	for _, table := range ts.Tables() {
+		sourceTable := ts.sourceTableName(table)
+		if vtable, ok := vschema.Tables[table]; ok {
+			vschema.Tables[sourceTable] = vtable
+			delete(vschema.Tables, table)
+		}
+		for _, fromTable := range []string{table, sourceTable} {
+			for _, suffix := range []string{"", "@replica", "@rdonly"} {
+				delete(rules, fromTable+suffix)
+				delete(rules, ts.TargetKeyspaceName()+"."+fromTable+suffix)
+			}
+		}
+	}
+	if err := ts.TopoServer().SaveVSchema(ctx, ts.TargetKeyspaceName(), vschema); err != nil {
+		return err
+	}
+	return topotools.SaveRoutingRules(ctx, ts.TopoServer(), rules)
 }
 
 // FIXME: even after dropSourceShards there are still entries in the topo, need to research and fix
@@ -604,9 +746,13 @@ func (ts *trafficSwitcher) switchTableReads(ctx context.Context, cells []string,
 				log.Infof("Route direction backwards")
 			}
 			toTarget := []string{ts.TargetKeyspaceName() + "." + table}
+			sourceTable := ts.sourceTableName(table)
 			rules[table+"@"+tt] = toTarget
 			rules[ts.TargetKeyspaceName()+"."+table+"@"+tt] = toTarget
-			rules[ts.SourceKeyspaceName()+"."+table+"@"+tt] = toTarget
+			rules[ts.SourceKeyspaceName()+"."+sourceTable+"@"+tt] = toTarget
+			if sourceTable != table {
+				rules[sourceTable+"@"+tt] = toTarget
+			}
 		}
 	}
 	if err := topotools.SaveRoutingRules(ctx, ts.TopoServer(), rules); err != nil {
@@ -692,7 +838,7 @@ func (ts *trafficSwitcher) allowTargetWrites(ctx context.Context) error {
 func (ts *trafficSwitcher) allowTableTargetWrites(ctx context.Context) error {
 	return ts.ForAllTargets(func(target *MigrationTarget) error {
 		if _, err := ts.TopoServer().UpdateShardFields(ctx, ts.TargetKeyspaceName(), target.GetShard().ShardName(), func(si *topo.ShardInfo) error {
-			return si.UpdateDeniedTables(ctx, topodatapb.TabletType_PRIMARY, nil, true, ts.Tables())
+			return ts.removeDeniedTables(ctx, si, ts.Tables())
 		}); err != nil {
 			return err
 		}
@@ -731,13 +877,18 @@ func (ts *trafficSwitcher) changeWriteRoute(ctx context.Context) error {
 			return err
 		}
 		for _, table := range ts.Tables() {
+			sourceTable := ts.sourceTableName(table)
 			targetKsTable := fmt.Sprintf("%s.%s", ts.TargetKeyspaceName(), table)
-			sourceKsTable := fmt.Sprintf("%s.%s", ts.SourceKeyspaceName(), table)
+			sourceKsTable := fmt.Sprintf("%s.%s", ts.SourceKeyspaceName(), sourceTable)
 			delete(rules, targetKsTable)
 			ts.Logger().Infof("Deleted routing: %s", targetKsTable)
-			rules[table] = []string{targetKsTable}
+			if sourceTable != table {
+				delete(rules, table)
+				ts.Logger().Infof("Deleted routing: %s", table)
+			}
+			rules[sourceTable] = []string{targetKsTable}
 			rules[sourceKsTable] = []string{targetKsTable}
-			ts.Logger().Infof("Added routing: %v %v", table, sourceKsTable)
+			ts.Logger().Infof("Added routing: %v %v", sourceTable, sourceKsTable)
 		}
 		if err := topotools.SaveRoutingRules(ctx, ts.TopoServer(), rules); err != nil {
 			return err
@@ -856,6 +1007,7 @@ func (ts *trafficSwitcher) createReverseVReplication(ctx context.Context) error
 				continue
 			}
 			var filter string
+			sourceTable := ts.sourceTableName(rule.Match)
 			if strings.HasPrefix(rule.Match, "/") {
 				if ts.SourceKeyspaceSchema().Keyspace.Sharded {
 					filter = key.KeyRangeString(source.GetShard().KeyRange)
@@ -863,9 +1015,9 @@ func (ts *trafficSwitcher) createReverseVReplication(ctx context.Context) error
 			} else {
 				var inKeyrange string
 				if ts.SourceKeyspaceSchema().Keyspace.Sharded {
-					vtable, ok := ts.SourceKeyspaceSchema().Tables[rule.Match]
+					vtable, ok := ts.SourceKeyspaceSchema().Tables[sourceTable]
 					if !ok {
-						return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "table %s not found in vschema", rule.Match)
+						return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "table %s not found in vschema", sourceTable)
 					}
 					// We currently assume the primary vindex is the best way to filter rows
 					// for the table, which may not always be true.
@@ -888,7 +1040,7 @@ func (ts *trafficSwitcher) createReverseVReplication(ctx context.Context) error
 				filter = fmt.Sprintf("select * from %s%s", sqlescape.EscapeID(rule.Match), inKeyrange)
 			}
 			reverseBls.Filter.Rules = append(reverseBls.Filter.Rules, &binlogdatapb.Rule{
-				Match:  rule.Match,
+				Match:  sourceTable,
 				Filter: filter,
 			})
 		}
@@ -974,7 +1126,7 @@ func (ts *trafficSwitcher) stopSourceWrites(ctx context.Context) error {
 func (ts *trafficSwitcher) changeTableSourceWrites(ctx context.Context, access accessType) error {
 	err := ts.ForAllSources(func(source *MigrationSource) error {
 		if _, err := ts.TopoServer().UpdateShardFields(ctx, ts.SourceKeyspaceName(), source.GetShard().ShardName(), func(si *topo.ShardInfo) error {
-			return si.UpdateDeniedTables(ctx, topodatapb.TabletType_PRIMARY, nil, access == allowWrites /* remove */, ts.Tables())
+			return si.UpdateDeniedTables(ctx, topodatapb.TabletType_PRIMARY, nil, access == allowWrites /* remove */, ts.sourceTableNames())
 		}); err != nil {
 			return err
 		}
@@ -1105,7 +1257,7 @@ func (ts *trafficSwitcher) removeTargetTables(ctx context.Context) error {
 		return err
 	}
 
-	return ts.dropParticipatingTablesFromKeyspace(ctx, ts.TargetKeyspaceName())
+	return ts.dropParticipatingTablesFromKeyspace(ctx, ts.TargetKeyspaceName(), ts.Tables())
 }
 
 func (ts *trafficSwitcher) dropTargetShards(ctx context.Context) error {
@@ -1176,14 +1328,15 @@ func (ts *trafficSwitcher) checkJournals(ctx context.Context) (journalsExist boo
 // source shard's primary tablet using a non-pooled connection as the DBA user. The connection
 // is closed when the LOCK TABLES statement returns, so we immediately release the LOCKs.
 func (ts *trafficSwitcher) executeLockTablesOnSource(ctx context.Context) error {
-	ts.Logger().Infof("Locking (and then immediately unlocking) the following tables on source keyspace %v: %v", ts.SourceKeyspaceName(), ts.Tables())
-	if len(ts.Tables()) == 0 {
+	sourceTables := ts.sourceTableNames()
+	ts.Logger().Infof("Locking (and then immediately unlocking) the following tables on source keyspace %v: %v", ts.SourceKeyspaceName(), sourceTables)
+	if len(sourceTables) == 0 {
 		return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "no tables found in the source keyspace %v associated with the %s workflow", ts.SourceKeyspaceName(), ts.WorkflowName())
 	}
 
 	sb := strings.Builder{}
 	sb.WriteString("LOCK TABLES ")
-	for _, tableName := range ts.Tables() {
+	for _, tableName := range sourceTables {
 		sb.WriteString(fmt.Sprintf("%s READ,", sqlescape.EscapeID(tableName)))
 	}
 	// trim extra trailing comma
@@ -17,11 +17,23 @@ limitations under the License.
 package workflow
 
 import (
+	"context"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topotools"
 	"vitess.io/vitess/go/vt/vtgate/vindexes"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
 )
 
 type testTrafficSwitcher struct {
@@ -56,3 +68,232 @@ func TestReverseWorkflowName(t *testing.T) {
 		assert.Equal(t, test.out, got)
 	}
 }
+
+// newTestChangeVindexTrafficSwitcher returns the traffic switcher of a
+// ChangeVindex workflow which copies the t1 table of the ks keyspace into
+// the _t1_vcv shadow table.
+func newTestChangeVindexTrafficSwitcher(t *testing.T, ctx context.Context, env *testMaterializerEnv) *trafficSwitcher {
+	t.Helper()
+	vs := &vschemapb.Keyspace{
+		Sharded: true,
+		Vindexes: map[string]*vschemapb.Vindex{
+			"xxhash": {Type: "xxhash"},
+			"hash":   {Type: "hash"},
+		},
+		Tables: map[string]*vschemapb.Table{
+			"t1": {
+				ColumnVindexes: []*vschemapb.ColumnVindex{{Name: "xxhash", Column: "id"}},
+			},
+			"_t1_vcv": {
+				ColumnVindexes: []*vschemapb.ColumnVindex{{Name: "hash", Column: "c1"}},
+			},
+		},
+	}
+	require.NoError(t, env.topoServ.SaveVSchema(ctx, "ks", vs))
+	ksSchema, err := vindexes.BuildKeyspaceSchema(vs, "ks", env.ws.env.Parser())
+	require.NoError(t, err)
+
+	ts := &trafficSwitcher{
+		ws:              env.ws,
+		logger:          logutil.NewMemoryLogger(),
+		migrationType:   binlogdatapb.MigrationType_TABLES,
+		workflow:        "wf",
+		reverseWorkflow: "wf_reverse",
+		sources:         make(map[string]*MigrationSource),
+		targets:         make(map[string]*MigrationTarget),
+		targetKeyspace:  "ks",
+		tables:          []string{"_t1_vcv"},
+		sourceKSSchema:  ksSchema,
+		workflowType:    binlogdatapb.VReplicationWorkflowType_ChangeVindex,
+	}
+	for _, shard := range env.sources {
+		si, err := env.topoServ.GetShard(ctx, "ks", shard)
+		require.NoError(t, err)
+		primary, err := env.topoServ.GetTablet(ctx, si.PrimaryAlias)
+		require.NoError(t, err)
+		rule := &binlogdatapb.Rule{Match: "_t1_vcv", Filter: "select * from t1"}
+		require.NoError(t, ts.addSourceTable(rule, env.ws.env.Parser()))
+		ts.sources[shard] = NewMigrationSource(si, primary)
+		ts.targets[shard] = &MigrationTarget{
+			si:      si,
+			primary: primary,
+			Sources: map[int32]*binlogdatapb.BinlogSource{
+				1: {
+					Keyspace: "ks",
+					Shard:    shard,
+					Filter:   &binlogdatapb.Filter{Rules: []*binlogdatapb.Rule{rule}},
+				},
+			},
+		}
+	}
+	return ts
+}
+
+func newTestChangeVindexEnv(t *testing.T, ctx context.Context) *testMaterializerEnv {
+	t.Helper()
+	ms := &vtctldatapb.MaterializeSettings{
+		SourceKeyspace: "ks",
+		TargetKeyspace: "ks",
+	}
+	return newTestMaterializerEnv(t, ctx, ms, []string{"-80", "80-"}, []string{"-80", "80-"})
+}
+
+func TestChangeVindexSourceTables(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	env := newTestChangeVindexEnv(t, ctx)
+	defer env.close()
+	ts := newTestChangeVindexTrafficSwitcher(t, ctx, env)
+
+	assert.Equal(t, "t1", ts.sourceTableName("_t1_vcv"))
+	assert.Equal(t, "t2", ts.sourceTableName("t2"))
+	assert.Equal(t, []string{"t1"}, ts.sourceTableNames())
+
+	err := ts.addSourceTable(&binlogdatapb.Rule{Match: "_t2_vcv", Filter: "not a query"}, env.ws.env.Parser())
+	assert.ErrorContains(t, err, "failed to find the source table for the _t2_vcv table")
+}
+
+func TestChangeVindexRemoveDeniedTables(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	env := newTestChangeVindexEnv(t, ctx)
+	defer env.close()
+	ts := newTestChangeVindexTrafficSwitcher(t, ctx, env)
+	ctx, unlock, err := env.topoServ.LockKeyspace(ctx, "ks", "test")
+	require.NoError(t, err)
+	defer unlock(&err)
+
+	// Nothing is denied before writes are switched.
+	_, err = env.topoServ.UpdateShardFields(ctx, "ks", "-80", func(si *topo.ShardInfo) error {
+		return ts.removeDeniedTables(ctx, si, []string{"t1", "_t1_vcv"})
+	})
+	require.NoError(t, err)
+
+	_, err = env.topoServ.UpdateShardFields(ctx, "ks", "-80", func(si *topo.ShardInfo) error {
+		return si.UpdateDeniedTables(ctx, topodatapb.TabletType_PRIMARY, nil, false, []string{"t1", "t2"})
+	})
+	require.NoError(t, err)
+	// Only the tables which are denied are removed, and the other tables
+	// of the shard stay denied.
+	si, err := env.topoServ.UpdateShardFields(ctx, "ks", "-80", func(si *topo.ShardInfo) error {
+		return ts.removeDeniedTables(ctx, si, []string{"t1", "_t1_vcv"})
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"t2"}, si.GetTabletControl(topodatapb.TabletType_PRIMARY).DeniedTables)
+}
+
+func TestChangeVindexChangeWriteRoute(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	env := newTestChangeVindexEnv(t, ctx)
+	defer env.close()
+	ts := newTestChangeVindexTrafficSwitcher(t, ctx, env)
+
+	// The routing rules created along with the workflow.
+	rules := map[string][]string{}
+	for _, suffix := range []string{"", "@replica", "@rdonly"} {
+		rules["_t1_vcv"+suffix] = []string{"ks.t1"}
+		rules["ks._t1_vcv"+suffix] = []string{"ks.t1"}
+	}
+	require.NoError(t, topotools.SaveRoutingRules(ctx, env.topoServ, rules))
+
+	require.NoError(t, ts.changeWriteRoute(ctx))
+
+	got, err := topotools.GetRoutingRules(ctx, env.topoServ)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		"t1":                 {"ks._t1_vcv"},
+		"ks.t1":              {"ks._t1_vcv"},
+		"_t1_vcv@replica":    {"ks.t1"},
+		"_t1_vcv@rdonly":     {"ks.t1"},
+		"ks._t1_vcv@replica": {"ks.t1"},
+		"ks._t1_vcv@rdonly":  {"ks.t1"},
+	}, got)
+}
+
+func TestChangeVindexDeleteRoutingRules(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	env := newTestChangeVindexEnv(t, ctx)
+	defer env.close()
+	ts := newTestChangeVindexTrafficSwitcher(t, ctx, env)
+
+	// Traffic was switched for the primary only: the rules for the shadow
+	// table and the ones routing to the t1 table are deleted, the ones
+	// routing to the shadow table are kept.
+	rules := map[string][]string{
+		"t1":                 {"ks._t1_vcv"},
+		"ks.t1":              {"ks._t1_vcv"},
+		"t1@replica":         {"ks.t1"},
+		"ks.t1@replica":      {"ks.t1"},
+		"_t1_vcv@replica":    {"ks.t1"},
+		"ks._t1_vcv@replica": {"ks.t1"},
+		"t2":                 {"ks.t2"},
+	}
+	require.NoError(t, topotools.SaveRoutingRules(ctx, env.topoServ, rules))
+
+	require.NoError(t, ts.deleteRoutingRules(ctx))
+
+	got, err := topotools.GetRoutingRules(ctx, env.topoServ)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		"t1":    {"ks._t1_vcv"},
+		"ks.t1": {"ks._t1_vcv"},
+		"t2":    {"ks.t2"},
+	}, got)
+}
+
+func TestChangeVindexCreateReverseVReplication(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	env := newTestChangeVindexEnv(t, ctx)
+	defer env.close()
+	ts := newTestChangeVindexTrafficSwitcher(t, ctx, env)
+
+	for uid, shard := range map[int]string{100: "-80", 110: "80-"} {
+		env.tmc.expectVRQuery(uid, fmt.Sprintf(sqlDeleteWorkflow, encodeString("vt_ks"), encodeString("wf_reverse")), &sqltypes.Result{})
+		// The reverse stream copies the shadow table back into the t1 table,
+		// filtered with the primary vindex of the t1 table.
+		env.tmc.expectVRQuery(uid, fmt.Sprintf(`/insert into _vt.vreplication.*'wf_reverse'.*match:\\"t1\\" filter:\\"select \* from ._t1_vcv. where in_keyrange\(id, \\'ks.xxhash\\', \\'%s\\'\)`, shard), &sqltypes.Result{})
+	}
+
+	require.NoError(t, ts.createReverseVReplication(ctx))
+	env.tmc.verifyQueries(t)
+}
+
+func TestChangeVindexRenameShadowTables(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	env := newTestChangeVindexEnv(t, ctx)
+	defer env.close()
+	ts := newTestChangeVindexTrafficSwitcher(t, ctx, env)
+
+	// The t1 table was removed when the workflow was completed.
+	vs, err := env.topoServ.GetVSchema(ctx, "ks")
+	require.NoError(t, err)
+	delete(vs.Tables, "t1")
+	require.NoError(t, env.topoServ.SaveVSchema(ctx, "ks", vs))
+	rules := map[string][]string{
+		"t1":    {"ks._t1_vcv"},
+		"ks.t1": {"ks._t1_vcv"},
+		"t2":    {"ks.t2"},
+	}
+	require.NoError(t, topotools.SaveRoutingRules(ctx, env.topoServ, rules))
+	for _, uid := range []int{100, 110} {
+		env.tmc.expectVRQuery(uid, "rename table `vt_ks`.`_t1_vcv` TO `vt_ks`.`t1`", &sqltypes.Result{})
+	}
+
+	require.NoError(t, ts.renameShadowTables(ctx))
+	env.tmc.verifyQueries(t)
+
+	vs, err = env.topoServ.GetVSchema(ctx, "ks")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]*vschemapb.Table{
+		"t1": {
+			ColumnVindexes: []*vschemapb.ColumnVindex{{Name: "hash", Column: "c1"}},
+		},
+	}, vs.Tables)
+	got, err := topotools.GetRoutingRules(ctx, env.topoServ)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{"t2": {"ks.t2"}}, got)
+}
@@ -553,7 +553,7 @@ func doValidateWorkflowHasCompleted(ctx context.Context, ts *trafficSwitcher) er
 			}
 			for fromTable, toTables := range rules {
 				for _, toTable := range toTables {
-					for _, table := range ts.Tables() {
+					for _, table := range ts.sourceTableNames() {
 						if toTable == fmt.Sprintf("%s.%s", ts.SourceKeyspaceName(), table) {
 							rec.RecordError(fmt.Errorf("routing still exists from keyspace %s table %s to %s", ts.SourceKeyspaceName(), table, fromTable))
 						}
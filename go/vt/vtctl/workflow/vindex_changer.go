@@ -0,0 +1,358 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+
+	"vitess.io/vitess/go/trace"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/topotools"
+	"vitess.io/vitess/go/vt/vtctl/schematools"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// shadowTableTemplate is the default name used for the table that a
+// ChangeVindex workflow copies the rows into.
+const shadowTableTemplate = "_%.59s_vcv" // limit table name to 64 characters
+
+// ChangeVindexCreate is part of the vtctlservicepb.VtctldServer interface.
+// It changes the primary vindex of a table in a sharded keyspace without
+// moving it to another keyspace. The table's rows are copied with a
+// VReplication workflow into a shadow table in the same keyspace which
+// uses the new primary vindex. The workflow is then managed like a
+// MoveTables workflow: it can be diffed with VDiff, traffic is cut over
+// to the shadow table with SwitchTraffic (which uses routing rules so that
+// the application continues to use the original table name), and rolled
+// back with ReverseTraffic until it is completed. Completing the workflow
+// removes the original table and renames the shadow table to the original
+// name, along with its vschema definition, and deletes the routing rules.
+func (s *Server) ChangeVindexCreate(ctx context.Context, req *vtctldatapb.ChangeVindexCreateRequest) (resp *vtctldatapb.ChangeVindexCreateResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "workflow.Server.ChangeVindexCreate")
+	defer span.Finish()
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("workflow", req.Workflow)
+	span.Annotate("table", req.Table)
+	span.Annotate("shadow_table", req.ShadowTable)
+	span.Annotate("cells", req.Cells)
+	span.Annotate("tablet_types", req.TabletTypes)
+
+	shadowTable := req.ShadowTable
+	if shadowTable == "" {
+		shadowTable = fmt.Sprintf(shadowTableTemplate, req.Table)
+	}
+
+	// Hold the keyspace lock while the vschema and the routing rules are
+	// changed, and until the workflow is created or they are rolled back.
+	lockCtx, unlock, lockErr := s.ts.LockKeyspace(ctx, req.Keyspace, "ChangeVindexCreate")
+	if lockErr != nil {
+		return nil, vterrors.Wrapf(lockErr, "failed to lock the %s keyspace", req.Keyspace)
+	}
+	defer unlock(&err)
+	ctx = lockCtx
+
+	vschema, err := s.prepareChangeVindex(ctx, req, shadowTable)
+	if err != nil {
+		return nil, err
+	}
+	createDDL, err := s.getChangeVindexCreateDDL(ctx, req.Keyspace, req.Table, shadowTable)
+	if err != nil {
+		return nil, err
+	}
+
+	origVSchema, err := s.ts.GetVSchema(ctx, req.Keyspace)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := topotools.GetRoutingRules(ctx, s.ts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Save the routing rules before the vschema, so that the shadow table is
+	// never routable on its own.
+	toTable := []string{req.Keyspace + "." + req.Table}
+	var addedRules []string
+	for _, suffix := range []string{"", "@replica", "@rdonly"} {
+		for _, from := range []string{shadowTable + suffix, req.Keyspace + "." + shadowTable + suffix} {
+			if _, ok := rules[from]; !ok {
+				addedRules = append(addedRules, from)
+			}
+			rules[from] = toTable
+		}
+	}
+
+	// restore puts the original vschema back and removes the routing rules
+	// added above when the workflow cannot be created, so that the request
+	// can be retried. The routing rules are global, so the ones of other
+	// keyspaces, which are not protected by the keyspace lock, are left as
+	// they are now.
+	restore := func(err error) error {
+		if rerr := s.ts.SaveVSchema(ctx, req.Keyspace, origVSchema); rerr != nil {
+			err = vterrors.Wrapf(err, "failed to restore the original vschema: %v", rerr)
+		}
+		if rerr := removeRoutingRules(ctx, s.ts, addedRules); rerr != nil {
+			err = vterrors.Wrapf(err, "failed to remove the routing rules for the %s shadow table: %v", shadowTable, rerr)
+		}
+		if rerr := s.ts.RebuildSrvVSchema(ctx, nil); rerr != nil {
+			err = vterrors.Wrapf(err, "failed to rebuild the SrvVSchema: %v", rerr)
+		}
+		return err
+	}
+
+	if err := topotools.SaveRoutingRules(ctx, s.ts, rules); err != nil {
+		return nil, restore(err)
+	}
+	if err := s.ts.SaveVSchema(ctx, req.Keyspace, vschema); err != nil {
+		return nil, restore(err)
+	}
+	if err := s.ts.RebuildSrvVSchema(ctx, nil); err != nil {
+		return nil, restore(err)
+	}
+
+	buf := sqlparser.NewTrackedBuffer(nil)
+	buf.Myprintf("select * from %v", sqlparser.NewIdentifierCS(req.Table))
+	ms := &vtctldatapb.MaterializeSettings{
+		Workflow:                  req.Workflow,
+		MaterializationIntent:     vtctldatapb.MaterializationIntent_CHANGEVINDEX,
+		SourceKeyspace:            req.Keyspace,
+		TargetKeyspace:            req.Keyspace,
+		Cell:                      strings.Join(req.Cells, ","),
+		TabletTypes:               topoproto.MakeStringTypeCSV(req.TabletTypes),
+		TabletSelectionPreference: req.TabletSelectionPreference,
+		DeferSecondaryKeys:        req.DeferSecondaryKeys,
+		TableSettings: []*vtctldatapb.TableMaterializeSettings{{
+			TargetTable:      shadowTable,
+			SourceExpression: buf.String(),
+			CreateDdl:        createDDL,
+		}},
+	}
+	if err := s.Materialize(ctx, ms); err != nil {
+		return nil, restore(err)
+	}
+
+	return &vtctldatapb.ChangeVindexCreateResponse{
+		ShadowTable: shadowTable,
+	}, nil
+}
+
+// removeRoutingRules deletes the given routing rules, keeping any other rule
+// as it currently is in the topo.
+func removeRoutingRules(ctx context.Context, ts *topo.Server, from []string) error {
+	if len(from) == 0 {
+		return nil
+	}
+	rules, err := topotools.GetRoutingRules(ctx, ts)
+	if err != nil {
+		return err
+	}
+	for _, rule := range from {
+		delete(rules, rule)
+	}
+	return topotools.SaveRoutingRules(ctx, ts, rules)
+}
+
+// prepareChangeVindex validates the request and returns the keyspace's vschema
+// with the shadow table, and the new vindex if needed, added to it.
+func (s *Server) prepareChangeVindex(ctx context.Context, req *vtctldatapb.ChangeVindexCreateRequest, shadowTable string) (*vschemapb.Keyspace, error) {
+	if req.Workflow == "" {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "no workflow name provided")
+	}
+	if req.Table == "" {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "no table provided")
+	}
+	if shadowTable == req.Table {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "the shadow table must have a different name than the %s table", req.Table)
+	}
+	cv := req.ColumnVindex
+	if cv == nil || cv.Name == "" || (cv.Column == "" && len(cv.Columns) == 0) {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "a column vindex with a name and columns must be provided")
+	}
+
+	vschema, err := s.ts.GetVSchema(ctx, req.Keyspace)
+	if err != nil {
+		return nil, err
+	}
+	if !vschema.Sharded {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "the %s keyspace is not sharded", req.Keyspace)
+	}
+	table, ok := vschema.Tables[req.Table]
+	if !ok {
+		return nil, vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "table %s not found in the vschema for the %s keyspace", req.Table, req.Keyspace)
+	}
+	if table.Type != "" {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "the %s table is a %s table, which has no primary vindex to change", req.Table, table.Type)
+	}
+	if _, ok := vschema.Tables[shadowTable]; ok {
+		return nil, vterrors.Errorf(vtrpcpb.Code_ALREADY_EXISTS, "the %s shadow table already exists in the vschema for the %s keyspace", shadowTable, req.Keyspace)
+	}
+	// The shadow table would become a second owner of any lookup vindexes
+	// that are owned by the table, which is not supported.
+	for name, vindex := range vschema.Vindexes {
+		if vindex.Owner == req.Table {
+			return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "the %s table owns the %s vindex, which is not supported", req.Table, name)
+		}
+	}
+
+	vindex, ok := vschema.Vindexes[cv.Name]
+	switch {
+	case !ok && req.Vindex == nil:
+		return nil, vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "vindex %s not found in the %s keyspace and no definition was provided", cv.Name, req.Keyspace)
+	case !ok:
+		if vschema.Vindexes == nil {
+			vschema.Vindexes = make(map[string]*vschemapb.Vindex)
+		}
+		vschema.Vindexes[cv.Name] = req.Vindex
+	case req.Vindex != nil && !proto.Equal(vindex, req.Vindex):
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "a different definition of the %s vindex already exists in the %s keyspace", cv.Name, req.Keyspace)
+	}
+
+	// The shadow table is the same as the original, other than its primary
+	// vindex.
+	shadow := table.CloneVT()
+	if len(shadow.ColumnVindexes) == 0 {
+		shadow.ColumnVindexes = []*vschemapb.ColumnVindex{cv}
+	} else {
+		shadow.ColumnVindexes[0] = cv
+	}
+	vschema.Tables[shadowTable] = shadow
+
+	// Ensure that the resulting vschema is valid, e.g. that the new primary
+	// vindex is unique.
+	if _, err := vindexes.BuildKeyspaceSchema(vschema, req.Keyspace, s.env.Parser()); err != nil {
+		return nil, vterrors.Wrapf(err, "invalid vschema for the %s shadow table", shadowTable)
+	}
+	return vschema, nil
+}
+
+// getChangeVindexCreateDDL returns the CREATE TABLE statement for the shadow
+// table, based on the schema of the given table.
+func (s *Server) getChangeVindexCreateDDL(ctx context.Context, keyspace, table, shadowTable string) (string, error) {
+	shards, err := s.ts.GetServingShards(ctx, keyspace)
+	if err != nil {
+		return "", err
+	}
+	if len(shards) == 0 || shards[0].PrimaryAlias == nil {
+		return "", vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "no primary tablet found to get the schema of the %s table from", table)
+	}
+	schema, err := schematools.GetSchema(ctx, s.ts, s.tmc, shards[0].PrimaryAlias, &tabletmanagerdatapb.GetSchemaRequest{
+		Tables: []string{table},
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(schema.TableDefinitions) != 1 || schema.TableDefinitions[0].Name != table {
+		return "", vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "table %s not found in the %s keyspace", table, keyspace)
+	}
+	stmt, err := s.env.Parser().ParseStrictDDL(schema.TableDefinitions[0].Schema)
+	if err != nil {
+		return "", err
+	}
+	createTable, ok := stmt.(*sqlparser.CreateTable)
+	if !ok {
+		return "", vterrors.Errorf(vtrpcpb.Code_INTERNAL, "expected a CREATE TABLE statement for the %s table, got: %s",
+			table, schema.TableDefinitions[0].Schema)
+	}
+	createTable.SetTable("", shadowTable)
+	return sqlparser.String(createTable), nil
+}
+
+// setChangeVindexTrafficState sets the traffic state for a ChangeVindex
+// workflow. The source and target tables are in the same keyspace, so traffic
+// has been switched when the routing rules for the original table point to the
+// shadow table.
+func (s *Server) setChangeVindexTrafficState(ctx context.Context, ts *trafficSwitcher, state *State, reverse bool) error {
+	// The shadow table is the target of the workflow, or the source of the
+	// reverse workflow.
+	table, shadowTable := ts.sourceTableName(ts.Tables()[0]), ts.Tables()[0]
+	if reverse {
+		table, shadowTable = shadowTable, table
+	}
+	toShadowTable := fmt.Sprintf("%s.%s", ts.TargetKeyspaceName(), shadowTable)
+
+	var err error
+	state.RdonlyCellsSwitched, state.RdonlyCellsNotSwitched, err = s.getCellsWithTableRoutedTo(ctx, ts.TargetKeyspaceName(), table,
+		toShadowTable, topodatapb.TabletType_RDONLY)
+	if err != nil {
+		return err
+	}
+	state.ReplicaCellsSwitched, state.ReplicaCellsNotSwitched, err = s.getCellsWithTableRoutedTo(ctx, ts.TargetKeyspaceName(), table,
+		toShadowTable, topodatapb.TabletType_REPLICA)
+	if err != nil {
+		return err
+	}
+	rules, err := topotools.GetRoutingRules(ctx, s.ts)
+	if err != nil {
+		return err
+	}
+	if rr := rules[table]; len(rr) > 0 && rr[0] == toShadowTable {
+		state.WritesSwitched = true
+	}
+	return nil
+}
+
+// getCellsWithTableRoutedTo returns the cells where the reads for the given
+// tablet type and table are routed to the toTable, and the cells where they
+// are not.
+func (s *Server) getCellsWithTableRoutedTo(ctx context.Context, keyspace, table, toTable string, tabletType topodatapb.TabletType) (cellsSwitched []string, cellsNotSwitched []string, err error) {
+	cells, err := s.ts.GetCellInfoNames(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	fromTable := fmt.Sprintf("%s.%s@%s", keyspace, table, strings.ToLower(tabletType.String()))
+	for _, cell := range cells {
+		srvVSchema, err := s.ts.GetSrvVSchema(ctx, cell)
+		if err != nil {
+			return nil, nil, err
+		}
+		switched := false
+		for _, rule := range srvVSchema.GetRoutingRules().GetRules() {
+			if rule.FromTable != fromTable {
+				continue
+			}
+			for _, to := range rule.ToTables {
+				if to == toTable {
+					switched = true
+					break
+				}
+			}
+			break
+		}
+		if switched {
+			cellsSwitched = append(cellsSwitched, cell)
+		} else {
+			cellsNotSwitched = append(cellsNotSwitched, cell)
+		}
+	}
+	log.Infof("getCellsWithTableRoutedTo: %s -> %s: switched cells %v, not switched cells %v", fromTable, toTable, cellsSwitched, cellsNotSwitched)
+	return cellsSwitched, cellsNotSwitched, nil
+}
@@ -0,0 +1,339 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workflow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/mysqlctl/tmutils"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topotools"
+	"vitess.io/vitess/go/vt/vtenv"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+func TestPrepareChangeVindex(t *testing.T) {
+	ms := &vtctldatapb.MaterializeSettings{
+		SourceKeyspace: "ks",
+		TargetKeyspace: "ks",
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	env := newTestMaterializerEnv(t, ctx, ms, []string{"-80", "80-"}, []string{"-80", "80-"})
+	defer env.close()
+
+	vs := &vschemapb.Keyspace{
+		Sharded: true,
+		Vindexes: map[string]*vschemapb.Vindex{
+			"xxhash": {
+				Type: "xxhash",
+			},
+			"lkp": {
+				Type: "lookup_unique",
+				Params: map[string]string{
+					"table": "ks.lkp",
+					"from":  "c1",
+					"to":    "keyspace_id",
+				},
+				Owner: "t2",
+			},
+			"lkp_multi": {
+				Type: "lookup",
+				Params: map[string]string{
+					"table": "ks.lkp_multi",
+					"from":  "c1",
+					"to":    "keyspace_id",
+				},
+			},
+		},
+		Tables: map[string]*vschemapb.Table{
+			"t1": {
+				ColumnVindexes: []*vschemapb.ColumnVindex{{
+					Name:   "xxhash",
+					Column: "id",
+				}},
+			},
+			"t2": {
+				ColumnVindexes: []*vschemapb.ColumnVindex{{
+					Name:   "xxhash",
+					Column: "id",
+				}, {
+					Name:   "lkp",
+					Column: "c1",
+				}},
+			},
+			"ref": {
+				Type: "reference",
+			},
+		},
+	}
+	err := env.topoServ.SaveVSchema(ctx, ms.TargetKeyspace, vs)
+	require.NoError(t, err)
+
+	testcases := []struct {
+		name        string
+		req         *vtctldatapb.ChangeVindexCreateRequest
+		shadowTable string
+		want        *vschemapb.Table
+		wantVindex  *vschemapb.Vindex
+		err         string
+	}{
+		{
+			name: "new vindex",
+			req: &vtctldatapb.ChangeVindexCreateRequest{
+				Workflow:     "wf",
+				Table:        "t1",
+				ColumnVindex: &vschemapb.ColumnVindex{Name: "hash", Column: "c1"},
+				Vindex:       &vschemapb.Vindex{Type: "hash"},
+			},
+			shadowTable: "_t1_vcv",
+			want: &vschemapb.Table{
+				ColumnVindexes: []*vschemapb.ColumnVindex{{Name: "hash", Column: "c1"}},
+			},
+			wantVindex: &vschemapb.Vindex{Type: "hash"},
+		},
+		{
+			name: "existing vindex",
+			req: &vtctldatapb.ChangeVindexCreateRequest{
+				Workflow:     "wf",
+				Table:        "t1",
+				ColumnVindex: &vschemapb.ColumnVindex{Name: "xxhash", Column: "c1"},
+			},
+			shadowTable: "t1_new",
+			want: &vschemapb.Table{
+				ColumnVindexes: []*vschemapb.ColumnVindex{{Name: "xxhash", Column: "c1"}},
+			},
+		},
+		{
+			name: "no workflow",
+			req: &vtctldatapb.ChangeVindexCreateRequest{
+				Table:        "t1",
+				ColumnVindex: &vschemapb.ColumnVindex{Name: "xxhash", Column: "c1"},
+			},
+			shadowTable: "_t1_vcv",
+			err:         "no workflow name provided",
+		},
+		{
+			name: "same shadow table name",
+			req: &vtctldatapb.ChangeVindexCreateRequest{
+				Workflow:     "wf",
+				Table:        "t1",
+				ColumnVindex: &vschemapb.ColumnVindex{Name: "xxhash", Column: "c1"},
+			},
+			shadowTable: "t1",
+			err:         "the shadow table must have a different name than the t1 table",
+		},
+		{
+			name: "no column vindex",
+			req: &vtctldatapb.ChangeVindexCreateRequest{
+				Workflow: "wf",
+				Table:    "t1",
+			},
+			shadowTable: "_t1_vcv",
+			err:         "a column vindex with a name and columns must be provided",
+		},
+		{
+			name: "table not found",
+			req: &vtctldatapb.ChangeVindexCreateRequest{
+				Workflow:     "wf",
+				Table:        "t3",
+				ColumnVindex: &vschemapb.ColumnVindex{Name: "xxhash", Column: "c1"},
+			},
+			shadowTable: "_t3_vcv",
+			err:         "table t3 not found in the vschema for the ks keyspace",
+		},
+		{
+			name: "reference table",
+			req: &vtctldatapb.ChangeVindexCreateRequest{
+				Workflow:     "wf",
+				Table:        "ref",
+				ColumnVindex: &vschemapb.ColumnVindex{Name: "xxhash", Column: "c1"},
+			},
+			shadowTable: "_ref_vcv",
+			err:         "the ref table is a reference table, which has no primary vindex to change",
+		},
+		{
+			name: "shadow table exists",
+			req: &vtctldatapb.ChangeVindexCreateRequest{
+				Workflow:     "wf",
+				Table:        "t1",
+				ColumnVindex: &vschemapb.ColumnVindex{Name: "xxhash", Column: "c1"},
+			},
+			shadowTable: "t2",
+			err:         "the t2 shadow table already exists in the vschema for the ks keyspace",
+		},
+		{
+			name: "owner table",
+			req: &vtctldatapb.ChangeVindexCreateRequest{
+				Workflow:     "wf",
+				Table:        "t2",
+				ColumnVindex: &vschemapb.ColumnVindex{Name: "xxhash", Column: "c1"},
+			},
+			shadowTable: "_t2_vcv",
+			err:         "the t2 table owns the lkp vindex, which is not supported",
+		},
+		{
+			name: "vindex not found",
+			req: &vtctldatapb.ChangeVindexCreateRequest{
+				Workflow:     "wf",
+				Table:        "t1",
+				ColumnVindex: &vschemapb.ColumnVindex{Name: "hash", Column: "c1"},
+			},
+			shadowTable: "_t1_vcv",
+			err:         "vindex hash not found in the ks keyspace and no definition was provided",
+		},
+		{
+			name: "different vindex definition",
+			req: &vtctldatapb.ChangeVindexCreateRequest{
+				Workflow:     "wf",
+				Table:        "t1",
+				ColumnVindex: &vschemapb.ColumnVindex{Name: "xxhash", Column: "c1"},
+				Vindex:       &vschemapb.Vindex{Type: "hash"},
+			},
+			shadowTable: "_t1_vcv",
+			err:         "a different definition of the xxhash vindex already exists in the ks keyspace",
+		},
+		{
+			name: "non-unique primary vindex",
+			req: &vtctldatapb.ChangeVindexCreateRequest{
+				Workflow:     "wf",
+				Table:        "t1",
+				ColumnVindex: &vschemapb.ColumnVindex{Name: "lkp_multi", Column: "c1"},
+			},
+			shadowTable: "_t1_vcv",
+			err:         "invalid vschema for the _t1_vcv shadow table",
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.req.Keyspace = ms.TargetKeyspace
+			got, err := env.ws.prepareChangeVindex(ctx, tc.req, tc.shadowTable)
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.EqualValues(t, tc.want.String(), got.Tables[tc.shadowTable].String())
+			if tc.wantVindex != nil {
+				require.EqualValues(t, tc.wantVindex.String(), got.Vindexes[tc.req.ColumnVindex.Name].String())
+			}
+			// The original table must be left as is.
+			require.EqualValues(t, vs.Tables[tc.req.Table].String(), got.Tables[tc.req.Table].String())
+		})
+	}
+}
+
+func TestChangeVindexCreateRestoresOnFailure(t *testing.T) {
+	ms := &vtctldatapb.MaterializeSettings{
+		SourceKeyspace: "ks",
+		TargetKeyspace: "ks",
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	env := newTestMaterializerEnv(t, ctx, ms, []string{"-80", "80-"}, []string{"-80", "80-"})
+	defer env.close()
+
+	vs := &vschemapb.Keyspace{
+		Sharded: true,
+		Vindexes: map[string]*vschemapb.Vindex{
+			"xxhash": {
+				Type: "xxhash",
+			},
+		},
+		Tables: map[string]*vschemapb.Table{
+			"t1": {
+				ColumnVindexes: []*vschemapb.ColumnVindex{{
+					Name:   "xxhash",
+					Column: "id",
+				}},
+			},
+		},
+	}
+	require.NoError(t, env.topoServ.SaveVSchema(ctx, ms.TargetKeyspace, vs))
+	rules := map[string][]string{"t2": {"ks2.t2"}}
+	require.NoError(t, topotools.SaveRoutingRules(ctx, env.topoServ, rules))
+	env.tmc.schema["ks.t1"] = &tabletmanagerdatapb.SchemaDefinition{
+		TableDefinitions: []*tabletmanagerdatapb.TableDefinition{{
+			Name:   "t1",
+			Schema: "create table t1 (id int, c1 int, primary key (id))",
+		}},
+	}
+	// Creating the shadow table fails, as the tablets expect no queries. While
+	// it is being created, the keyspace is locked and another routing rule is
+	// added.
+	tmc := &changeVindexTMClient{
+		testMaterializerTMClient: env.tmc,
+		onApplySchema: func(ctx context.Context) error {
+			if err := topo.CheckKeyspaceLocked(ctx, ms.TargetKeyspace); err != nil {
+				return err
+			}
+			rules, err := topotools.GetRoutingRules(ctx, env.topoServ)
+			if err != nil {
+				return err
+			}
+			rules["t3"] = []string{"ks3.t3"}
+			return topotools.SaveRoutingRules(ctx, env.topoServ, rules)
+		},
+	}
+	ws := NewServer(vtenv.NewTestEnv(), env.topoServ, tmc)
+
+	_, err := ws.ChangeVindexCreate(ctx, &vtctldatapb.ChangeVindexCreateRequest{
+		Workflow:     "wf",
+		Keyspace:     ms.TargetKeyspace,
+		Table:        "t1",
+		ColumnVindex: &vschemapb.ColumnVindex{Name: "xxhash", Column: "c1"},
+	})
+	require.ErrorContains(t, err, "does not expect any more queries: create table _t1_vcv")
+
+	// The vschema is the one from before the request, and only the routing
+	// rules for the shadow table were removed.
+	got, err := env.topoServ.GetVSchema(ctx, ms.TargetKeyspace)
+	require.NoError(t, err)
+	require.EqualValues(t, vs.String(), got.String())
+	gotRules, err := topotools.GetRoutingRules(ctx, env.topoServ)
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{"t2": {"ks2.t2"}, "t3": {"ks3.t3"}}, gotRules)
+
+	// The keyspace was unlocked.
+	_, unlock, err := env.topoServ.LockKeyspace(ctx, ms.TargetKeyspace, "test")
+	require.NoError(t, err)
+	unlock(&err)
+	require.NoError(t, err)
+}
+
+// changeVindexTMClient runs onApplySchema before the schema changes of a
+// ChangeVindex workflow are applied.
+type changeVindexTMClient struct {
+	*testMaterializerTMClient
+	onApplySchema func(ctx context.Context) error
+}
+
+func (tmc *changeVindexTMClient) ApplySchema(ctx context.Context, tablet *topodatapb.Tablet, change *tmutils.SchemaChange) (*tabletmanagerdatapb.SchemaChangeResult, error) {
+	if err := tmc.onApplySchema(ctx); err != nil {
+		return nil, err
+	}
+	return tmc.testMaterializerTMClient.ApplySchema(ctx, tablet, change)
+}
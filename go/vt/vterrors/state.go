@@ -76,6 +76,7 @@ const (
 
 	// resource exhausted
 	NetPacketTooLarge
+	UserLimitReached
 
 	// cancelled
 	QueryInterrupted
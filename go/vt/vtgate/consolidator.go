@@ -0,0 +1,179 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/sync2"
+	"vitess.io/vitess/go/vt/callerid"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/sqlparser"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// Consolidator modes, as in vttablet.
+const (
+	consolidatorEnable       = "enable"
+	consolidatorDisable      = "disable"
+	consolidatorNotOnPrimary = "notOnPrimary"
+)
+
+var (
+	queriesConsolidated = stats.NewCountersWithSingleLabel("QueriesConsolidated", "Queries at vtgate that shared the result of an identical in-flight query, by plan type", "Plan")
+	consolidatorWaits   = stats.NewTimings("ConsolidatorWaits", "Time spent by queries at vtgate waiting on an identical in-flight query, by plan type", "Plan")
+	// consolidatorSkipped counts the queries that found an identical in-flight query, but executed on
+	// their own because it already had too many waiters, or because its result was too large to share.
+	consolidatorSkipped = stats.NewCountersWithMultiLabels("ConsolidatorSkipped", "Queries at vtgate that did not share the result of an identical in-flight query, by plan type and reason", []string{"Plan", "Reason"})
+)
+
+// queryConsolidator consolidates identical concurrent reads that vtgate sends to the same
+// keyspace, shard and tablet type outside of a transaction: only the first one is executed, and
+// the others wait for it and share its result. It complements the consolidator of vttablet, which
+// only sees the queries of a single tablet, so that the load of a thundering herd of reads is not
+// multiplied by the number of vtgates.
+type queryConsolidator struct {
+	consolidator sync2.Consolidator
+	mode         string
+	// waiterCap is the maximum number of queries that wait on a single in-flight query. 0 means
+	// no limit.
+	waiterCap int64
+	// maxResultSize is the maximum size, in bytes, of a result that is shared with the waiting
+	// queries. 0 means no limit.
+	maxResultSize int64
+}
+
+// newQueryConsolidator returns a queryConsolidator for the given mode, or nil if the mode
+// disables consolidation.
+func newQueryConsolidator(mode string, waiterCap, maxResultSize int64) *queryConsolidator {
+	switch mode {
+	case consolidatorEnable, consolidatorNotOnPrimary:
+	case consolidatorDisable, "":
+		return nil
+	default:
+		log.Warningf("Invalid vtgate consolidator mode %q, consolidation is disabled", mode)
+		return nil
+	}
+	return &queryConsolidator{
+		consolidator:  sync2.NewConsolidator(),
+		mode:          mode,
+		waiterCap:     waiterCap,
+		maxResultSize: maxResultSize,
+	}
+}
+
+// shouldConsolidate returns whether a query sent to the given tablet type should be consolidated.
+// The CONSOLIDATOR comment directive of the query, if any, takes precedence over the mode.
+func (qc *queryConsolidator) shouldConsolidate(tabletType topodatapb.TabletType, options *querypb.ExecuteOptions) bool {
+	switch options.GetConsolidator() {
+	case querypb.ExecuteOptions_CONSOLIDATOR_DISABLED:
+		return false
+	case querypb.ExecuteOptions_CONSOLIDATOR_ENABLED:
+		return true
+	case querypb.ExecuteOptions_CONSOLIDATOR_ENABLED_REPLICAS:
+		return tabletType != topodatapb.TabletType_PRIMARY
+	default:
+		return qc.mode == consolidatorEnable || (qc.mode == consolidatorNotOnPrimary && tabletType != topodatapb.TabletType_PRIMARY)
+	}
+}
+
+// isLockingRead returns whether the SELECT statement locks the rows it reads. Route queries are
+// generated by the parser, which writes the lock clause in lowercase; a string literal that looks
+// like one only makes the query execute on its own.
+func isLockingRead(sql string) bool {
+	return strings.Contains(sql, sqlparser.ForUpdateStr) ||
+		strings.Contains(sql, sqlparser.ForShareStr) ||
+		strings.Contains(sql, sqlparser.ShareModeStr)
+}
+
+// consolidationKey identifies the query: queries are identical when they are routed to the same
+// keyspace, shard and tablet type, and have the same SQL, ignoring margin comments, the same bind
+// variables and the same execute options. Queries of different callers are never identical, since
+// vttablet checks the table ACLs of each caller.
+func consolidationKey(ctx context.Context, target *querypb.Target, query *querypb.BoundQuery, options *querypb.ExecuteOptions) (string, error) {
+	sql, _ := sqlparser.SplitMarginComments(query.Sql)
+	marshal := proto.MarshalOptions{Deterministic: true}
+	bindVars, err := marshal.Marshal(&querypb.BoundQuery{BindVariables: query.BindVariables})
+	if err != nil {
+		return "", err
+	}
+	opts, err := marshal.Marshal(options)
+	if err != nil {
+		return "", err
+	}
+	effectiveCaller, err := marshal.Marshal(callerid.EffectiveCallerIDFromContext(ctx))
+	if err != nil {
+		return "", err
+	}
+	immediateCaller, err := marshal.Marshal(callerid.ImmediateCallerIDFromContext(ctx))
+	if err != nil {
+		return "", err
+	}
+	return target.Keyspace + "/" + target.Shard + "@" + target.TabletType.String() + ":" + sql + "\x00" + string(bindVars) + "\x00" + string(opts) +
+		"\x00" + string(effectiveCaller) + "\x00" + string(immediateCaller), nil
+}
+
+// execute runs exec, unless an identical query is already in flight, in which case it waits for
+// that query and returns a copy of its result.
+func (qc *queryConsolidator) execute(ctx context.Context, planType string, target *querypb.Target, query *querypb.BoundQuery, options *querypb.ExecuteOptions, exec func() (*sqltypes.Result, error)) (*sqltypes.Result, error) {
+	key, err := consolidationKey(ctx, target, query, options)
+	if err != nil {
+		return exec()
+	}
+
+	q, original := qc.consolidator.Create(key)
+	if original {
+		defer q.Broadcast()
+		res, err := exec()
+		q.SetErr(err)
+		// The waiters get their own copies of a snapshot of the result, since the result returned
+		// to the caller may be modified as it is merged into the results of other shards.
+		if err == nil && (qc.maxResultSize == 0 || res.CachedSize(true) <= qc.maxResultSize) {
+			q.SetResult(res.Copy())
+		}
+		return res, err
+	}
+
+	if waiters := q.AddWaiterCounter(1); qc.waiterCap > 0 && waiters > qc.waiterCap {
+		q.AddWaiterCounter(-1)
+		consolidatorSkipped.Add([]string{planType, "WaiterCap"}, 1)
+		return exec()
+	}
+	startTime := time.Now()
+	q.Wait()
+	q.AddWaiterCounter(-1)
+	consolidatorWaits.Record(planType, startTime)
+
+	if err := q.Err(); err != nil {
+		return nil, err
+	}
+	res := q.Result()
+	if res == nil {
+		consolidatorSkipped.Add([]string{planType, "ResultSize"}, 1)
+		return exec()
+	}
+	queriesConsolidated.Add(planType, 1)
+	return res.Copy(), nil
+}
@@ -0,0 +1,245 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/callerid"
+	"vitess.io/vitess/go/vt/vtgate/engine"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func TestNewQueryConsolidator(t *testing.T) {
+	assert.Nil(t, newQueryConsolidator(consolidatorDisable, 0, 0))
+	assert.Nil(t, newQueryConsolidator("", 0, 0))
+	assert.Nil(t, newQueryConsolidator("sometimes", 0, 0))
+	assert.NotNil(t, newQueryConsolidator(consolidatorEnable, 0, 0))
+	assert.NotNil(t, newQueryConsolidator(consolidatorNotOnPrimary, 0, 0))
+}
+
+func TestQueryConsolidatorShouldConsolidate(t *testing.T) {
+	primary, replica := topodatapb.TabletType_PRIMARY, topodatapb.TabletType_REPLICA
+	enabled := &querypb.ExecuteOptions{Consolidator: querypb.ExecuteOptions_CONSOLIDATOR_ENABLED}
+	disabled := &querypb.ExecuteOptions{Consolidator: querypb.ExecuteOptions_CONSOLIDATOR_DISABLED}
+	enabledReplicas := &querypb.ExecuteOptions{Consolidator: querypb.ExecuteOptions_CONSOLIDATOR_ENABLED_REPLICAS}
+
+	tcases := []struct {
+		mode       string
+		tabletType topodatapb.TabletType
+		options    *querypb.ExecuteOptions
+		want       bool
+	}{
+		{consolidatorEnable, primary, nil, true},
+		{consolidatorEnable, replica, nil, true},
+		{consolidatorNotOnPrimary, primary, nil, false},
+		{consolidatorNotOnPrimary, replica, &querypb.ExecuteOptions{}, true},
+		{consolidatorEnable, replica, disabled, false},
+		{consolidatorNotOnPrimary, primary, enabled, true},
+		{consolidatorEnable, primary, enabledReplicas, false},
+		{consolidatorEnable, replica, enabledReplicas, true},
+	}
+	for _, tcase := range tcases {
+		qc := newQueryConsolidator(tcase.mode, 0, 0)
+		assert.Equal(t, tcase.want, qc.shouldConsolidate(tcase.tabletType, tcase.options), "%s %s %v", tcase.mode, tcase.tabletType, tcase.options)
+	}
+}
+
+func TestConsolidationKey(t *testing.T) {
+	target := &querypb.Target{Keyspace: "ks", Shard: "-80", TabletType: topodatapb.TabletType_REPLICA}
+	query := &querypb.BoundQuery{
+		Sql:           "select * from t where id = :id",
+		BindVariables: map[string]*querypb.BindVariable{"id": sqltypes.Int64BindVariable(1), "name": sqltypes.StringBindVariable("a")},
+	}
+	keyAs := func(ctx context.Context, target *querypb.Target, query *querypb.BoundQuery, options *querypb.ExecuteOptions) string {
+		k, err := consolidationKey(ctx, target, query, options)
+		require.NoError(t, err)
+		return k
+	}
+	key := func(target *querypb.Target, query *querypb.BoundQuery, options *querypb.ExecuteOptions) string {
+		return keyAs(context.Background(), target, query, options)
+	}
+	base := key(target, query, nil)
+
+	// Margin comments are ignored, and bind variables compare by value.
+	assert.Equal(t, base, key(target, &querypb.BoundQuery{
+		Sql:           "/* trace */ select * from t where id = :id /* app */",
+		BindVariables: map[string]*querypb.BindVariable{"name": sqltypes.StringBindVariable("a"), "id": sqltypes.Int64BindVariable(1)},
+	}, nil))
+
+	assert.NotEqual(t, base, key(&querypb.Target{Keyspace: "ks", Shard: "80-", TabletType: topodatapb.TabletType_REPLICA}, query, nil))
+	assert.NotEqual(t, base, key(&querypb.Target{Keyspace: "ks", Shard: "-80", TabletType: topodatapb.TabletType_RDONLY}, query, nil))
+	assert.NotEqual(t, base, key(target, &querypb.BoundQuery{
+		Sql:           query.Sql,
+		BindVariables: map[string]*querypb.BindVariable{"id": sqltypes.Int64BindVariable(2), "name": sqltypes.StringBindVariable("a")},
+	}, nil))
+	assert.NotEqual(t, base, key(target, query, &querypb.ExecuteOptions{IncludedFields: querypb.ExecuteOptions_TYPE_ONLY}))
+
+	// Queries of different callers are not identical.
+	callerCtx := func(principal, user string) context.Context {
+		return callerid.NewContext(context.Background(), callerid.NewEffectiveCallerID(principal, "", ""), callerid.NewImmediateCallerID(user))
+	}
+	caller := keyAs(callerCtx("app", "app_user"), target, query, nil)
+	assert.NotEqual(t, base, caller)
+	assert.Equal(t, caller, keyAs(callerCtx("app", "app_user"), target, query, nil))
+	assert.NotEqual(t, caller, keyAs(callerCtx("admin", "app_user"), target, query, nil))
+	assert.NotEqual(t, caller, keyAs(callerCtx("app", "admin_user"), target, query, nil))
+}
+
+// runConsolidated executes the query once, blocked until release is closed, and then the given
+// number of identical queries, which it waits for to find the first one in flight.
+func runConsolidated(t *testing.T, qc *queryConsolidator, waiters int, release chan struct{}, exec func() (*sqltypes.Result, error)) ([]*sqltypes.Result, []error) {
+	target := &querypb.Target{Keyspace: "ks", Shard: "-80", TabletType: topodatapb.TabletType_REPLICA}
+	query := &querypb.BoundQuery{Sql: "select * from t"}
+
+	results := make([]*sqltypes.Result, waiters+1)
+	errs := make([]error, waiters+1)
+	var wg sync.WaitGroup
+	run := func(i int, exec func() (*sqltypes.Result, error)) {
+		defer wg.Done()
+		results[i], errs[i] = qc.execute(context.Background(), "Scatter", target, query, nil, exec)
+	}
+
+	started := make(chan struct{})
+	wg.Add(1)
+	go run(0, func() (*sqltypes.Result, error) {
+		close(started)
+		<-release
+		return exec()
+	})
+	<-started
+
+	var arrived atomic.Int64
+	for i := 1; i <= waiters; i++ {
+		wg.Add(1)
+		go run(i, func() (*sqltypes.Result, error) {
+			arrived.Add(1)
+			return exec()
+		})
+	}
+	// The waiters either wait on the first query, which records them, or execute on their own.
+	require.Eventually(t, func() bool {
+		var waiting int64
+		for _, item := range qc.consolidator.Items() {
+			waiting += item.Count
+		}
+		return waiting+arrived.Load() >= int64(waiters)
+	}, 5*time.Second, time.Millisecond)
+
+	close(release)
+	wg.Wait()
+	return results, errs
+}
+
+func TestQueryConsolidatorExecute(t *testing.T) {
+	result := sqltypes.MakeTestResult(sqltypes.MakeTestFields("id", "int64"), "1", "2")
+
+	t.Run("shared result", func(t *testing.T) {
+		consolidated := queriesConsolidated.Counts()["Scatter"]
+		var executions atomic.Int64
+		qc := newQueryConsolidator(consolidatorEnable, 0, 0)
+		results, errs := runConsolidated(t, qc, 3, make(chan struct{}), func() (*sqltypes.Result, error) {
+			executions.Add(1)
+			return result, nil
+		})
+		assert.EqualValues(t, 1, executions.Load())
+		assert.EqualValues(t, 3, queriesConsolidated.Counts()["Scatter"]-consolidated)
+		assert.Same(t, result, results[0])
+		for i := 1; i < len(results); i++ {
+			require.NoError(t, errs[i])
+			assert.Equal(t, result, results[i])
+			assert.NotSame(t, result, results[i])
+		}
+	})
+	t.Run("shared error", func(t *testing.T) {
+		var executions atomic.Int64
+		qc := newQueryConsolidator(consolidatorEnable, 0, 0)
+		_, errs := runConsolidated(t, qc, 2, make(chan struct{}), func() (*sqltypes.Result, error) {
+			executions.Add(1)
+			return nil, errors.New("connection refused")
+		})
+		assert.EqualValues(t, 1, executions.Load())
+		for _, err := range errs {
+			assert.EqualError(t, err, "connection refused")
+		}
+	})
+	t.Run("waiter cap", func(t *testing.T) {
+		skipped := consolidatorSkipped.Counts()["Scatter.WaiterCap"]
+		var executions atomic.Int64
+		qc := newQueryConsolidator(consolidatorEnable, 1, 0)
+		results, errs := runConsolidated(t, qc, 3, make(chan struct{}), func() (*sqltypes.Result, error) {
+			executions.Add(1)
+			return result, nil
+		})
+		assert.EqualValues(t, 3, executions.Load())
+		assert.EqualValues(t, 2, consolidatorSkipped.Counts()["Scatter.WaiterCap"]-skipped)
+		for i := range results {
+			require.NoError(t, errs[i])
+			assert.Equal(t, result, results[i])
+		}
+	})
+	t.Run("result size", func(t *testing.T) {
+		skipped := consolidatorSkipped.Counts()["Scatter.ResultSize"]
+		var executions atomic.Int64
+		qc := newQueryConsolidator(consolidatorEnable, 0, 1)
+		results, errs := runConsolidated(t, qc, 2, make(chan struct{}), func() (*sqltypes.Result, error) {
+			executions.Add(1)
+			return result, nil
+		})
+		assert.EqualValues(t, 3, executions.Load())
+		assert.EqualValues(t, 2, consolidatorSkipped.Counts()["Scatter.ResultSize"]-skipped)
+		for i := range results {
+			require.NoError(t, errs[i])
+			assert.Equal(t, result, results[i])
+		}
+	})
+}
+
+func TestScatterConnShouldConsolidate(t *testing.T) {
+	target := &querypb.Target{Keyspace: "ks", Shard: "-80", TabletType: topodatapb.TabletType_REPLICA}
+	route := func(opcode engine.Opcode) *engine.Route {
+		return &engine.Route{RoutingParameters: &engine.RoutingParameters{Opcode: opcode}}
+	}
+	sql := "select id from t where col = :col"
+	stc := &ScatterConn{consolidator: newQueryConsolidator(consolidatorEnable, 0, 0)}
+
+	assert.True(t, stc.shouldConsolidate(route(engine.Scatter), target, sql, &shardActionInfo{}, nil))
+	assert.True(t, stc.shouldConsolidate(route(engine.EqualUnique), target, "/* trace */ "+sql+" /* app */", &shardActionInfo{}, nil))
+	assert.False(t, stc.shouldConsolidate(route(engine.Scatter), target, sql, &shardActionInfo{transactionID: 1}, nil))
+	assert.False(t, stc.shouldConsolidate(route(engine.Scatter), target, sql, &shardActionInfo{reservedID: 1}, nil))
+	assert.False(t, stc.shouldConsolidate(route(engine.Next), target, "select next 1 values from seq", &shardActionInfo{}, nil))
+	assert.False(t, stc.shouldConsolidate(route(engine.DBA), target, "select * from information_schema.tables", &shardActionInfo{}, nil))
+	assert.False(t, stc.shouldConsolidate(route(engine.Scatter), target, sql+" for update", &shardActionInfo{}, nil))
+	assert.False(t, stc.shouldConsolidate(route(engine.Scatter), target, sql+" for share skip locked", &shardActionInfo{}, nil))
+	assert.False(t, stc.shouldConsolidate(route(engine.Scatter), target, sql+" lock in share mode", &shardActionInfo{}, nil))
+	assert.False(t, stc.shouldConsolidate(route(engine.Scatter), target, "show tables", &shardActionInfo{}, nil))
+	assert.False(t, stc.shouldConsolidate(&engine.Route{}, target, sql, &shardActionInfo{}, nil))
+	assert.False(t, stc.shouldConsolidate(&engine.Update{}, target, sql, &shardActionInfo{}, nil))
+	assert.False(t, stc.shouldConsolidate(nil, target, sql, &shardActionInfo{}, nil))
+	assert.False(t, (&ScatterConn{}).shouldConsolidate(route(engine.Scatter), target, sql, &shardActionInfo{}, nil))
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/key"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/schema"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/srvtopo"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+)
+
+var _ Primitive = (*BatchDML)(nil)
+
+// BatchDML represents the instructions to submit an UPDATE or DELETE statement as a batch DML job.
+// The job runs asynchronously on each target shard's primary, in small, throttled batches.
+type BatchDML struct {
+	noTxNeeded
+	noInputs
+
+	Keyspace *vindexes.Keyspace
+	// TargetDestination specifies an explicit target destination to send the query to.
+	TargetDestination key.Destination
+	// DML is the statement to run, with its keyspace qualifier removed.
+	DML       sqlparser.Statement
+	TableName string
+	BatchSize int64
+}
+
+func (v *BatchDML) description() PrimitiveDescription {
+	return PrimitiveDescription{
+		OperatorType:      "BatchDML",
+		Keyspace:          v.Keyspace,
+		TargetDestination: v.TargetDestination,
+		Other: map[string]any{
+			"Query":     sqlparser.String(v.DML),
+			"Table":     v.TableName,
+			"BatchSize": v.BatchSize,
+		},
+	}
+}
+
+// RouteType implements the Primitive interface
+func (v *BatchDML) RouteType() string {
+	return "BatchDML"
+}
+
+// GetKeyspaceName implements the Primitive interface
+func (v *BatchDML) GetKeyspaceName() string {
+	return v.Keyspace.Name
+}
+
+// GetTableName implements the Primitive interface
+func (v *BatchDML) GetTableName() string {
+	return v.TableName
+}
+
+// TryExecute implements the Primitive interface
+func (v *BatchDML) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
+	if vcursor.Session().InTransaction() {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "batch DML is not supported within a transaction")
+	}
+	// The job outlives this query, so the statement's bind variables are resolved here.
+	query, err := sqlparser.NewParsedQuery(v.DML).GenerateQuery(bindVars, nil)
+	if err != nil {
+		return nil, err
+	}
+	stmt, err := vcursor.Environment().Parser().Parse(query)
+	if err != nil {
+		return nil, err
+	}
+	jobContext := vcursor.Session().GetMigrationContext()
+	if jobContext == "" {
+		// default to @@session_uuid
+		jobContext = fmt.Sprintf("vtgate:%s", vcursor.Session().GetSessionUUID())
+	}
+	onlineDML, err := schema.NewOnlineDML(v.GetKeyspaceName(), stmt, v.BatchSize, jobContext)
+	if err != nil {
+		return nil, err
+	}
+	if err := v.submit(ctx, vcursor, onlineDML); err != nil {
+		return nil, err
+	}
+	return &sqltypes.Result{
+		Fields: []*querypb.Field{
+			{
+				Name:    "uuid",
+				Type:    sqltypes.VarChar,
+				Charset: uint32(vcursor.ConnCollation()),
+			},
+		},
+		Rows: [][]sqltypes.Value{
+			{sqltypes.NewVarChar(onlineDML.UUID)},
+		},
+	}, nil
+}
+
+// submit goes directly to the tablets, much like the Send primitive does, but submits the job to one
+// shard at a time. If a shard fails, the job is not submitted to the remaining shards, and the error
+// names the shards that already queued it, so that it can be cancelled there.
+func (v *BatchDML) submit(ctx context.Context, vcursor VCursor, onlineDML *schema.OnlineDML) error {
+	ctx, cancelFunc := addQueryTimeout(ctx, vcursor, 0)
+	defer cancelFunc()
+
+	rss, _, err := vcursor.ResolveDestinations(ctx, v.Keyspace.Name, nil, []key.Destination{v.TargetDestination})
+	if err != nil {
+		return err
+	}
+	if !v.Keyspace.Sharded && len(rss) != 1 {
+		return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "Keyspace does not have exactly one shard: %v", rss)
+	}
+	queries := []*querypb.BoundQuery{{Sql: onlineDML.SQL}}
+	var submitted []string
+	for _, rs := range rss {
+		_, errs := vcursor.ExecuteMultiShard(ctx, v, []*srvtopo.ResolvedShard{rs}, queries, false /* rollbackOnError */, false /* canAutocommit */)
+		if err := vterrors.Aggregate(errs); err != nil {
+			if len(submitted) == 0 {
+				return err
+			}
+			return vterrors.Wrapf(err, "batch DML job %s failed on shard %s, but was already submitted to shards %s, where it can be cancelled with DMLJob cancel",
+				onlineDML.UUID, rs.Target.Shard, strings.Join(submitted, ","))
+		}
+		submitted = append(submitted, rs.Target.Shard)
+	}
+	return nil
+}
+
+// TryStreamExecute implements the Primitive interface
+func (v *BatchDML) TryStreamExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
+	result, err := v.TryExecute(ctx, vcursor, bindVars, wantfields)
+	if err != nil {
+		return err
+	}
+	return callback(result)
+}
+
+// GetFields implements the Primitive interface
+func (v *BatchDML) GetFields(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "[BUG] GetFields is not reachable")
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/key"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+)
+
+// batchDMLVCursor provides the session's migration context to the job.
+type batchDMLVCursor struct {
+	*loggingVCursor
+}
+
+func (vc *batchDMLVCursor) Session() SessionActions {
+	return vc
+}
+
+func (vc *batchDMLVCursor) GetMigrationContext() string {
+	return "test"
+}
+
+func TestBatchDMLExecute(t *testing.T) {
+	stmt, err := sqlparser.NewTestParser().Parse("delete from t where col = 5")
+	require.NoError(t, err)
+	batchDML := &BatchDML{
+		Keyspace: &vindexes.Keyspace{
+			Name:    "ks",
+			Sharded: true,
+		},
+		TargetDestination: key.DestinationAllShards{},
+		DML:               stmt,
+		TableName:         "t",
+		BatchSize:         100,
+	}
+
+	vc := &batchDMLVCursor{&loggingVCursor{shards: []string{"-20", "20-40", "40-"}}}
+	result, err := batchDML.TryExecute(context.Background(), vc, nil, true)
+	require.NoError(t, err)
+	require.Len(t, result.Rows, 1)
+	// The job is submitted to one shard at a time.
+	require.Len(t, vc.log, 4)
+	for i, shard := range []string{"-20", "20-40", "40-"} {
+		require.Contains(t, vc.log[i+1], "ExecuteMultiShard ks."+shard+": delete /*vt+ BATCH_DML=100 uuid=")
+	}
+
+	// The second shard fails: the job is not submitted to the third one, and
+	// the error names the shard that already has it.
+	vc = &batchDMLVCursor{&loggingVCursor{
+		shards:    []string{"-20", "20-40", "40-"},
+		results:   []*sqltypes.Result{{}},
+		resultErr: errors.New("shard is not serving"),
+	}}
+	_, err = batchDML.TryExecute(context.Background(), vc, nil, true)
+	require.ErrorContains(t, err, "failed on shard 20-40, but was already submitted to shards -20, where it can be cancelled with DMLJob cancel: shard is not serving")
+	require.Len(t, vc.log, 3)
+
+	// The first shard fails: nothing was submitted.
+	vc = &batchDMLVCursor{&loggingVCursor{
+		shards:    []string{"-20", "20-40", "40-"},
+		resultErr: errors.New("shard is not serving"),
+	}}
+	_, err = batchDML.TryExecute(context.Background(), vc, nil, true)
+	require.EqualError(t, err, "shard is not serving")
+	require.Len(t, vc.log, 2)
+}
@@ -63,6 +63,28 @@ func (cached *AlterVSchema) CachedSize(alloc bool) int64 {
 	size += cached.AlterVschemaDDL.CachedSize(true)
 	return size
 }
+func (cached *BatchDML) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(64)
+	}
+	// field Keyspace *vitess.io/vitess/go/vt/vtgate/vindexes.Keyspace
+	size += cached.Keyspace.CachedSize(true)
+	// field TargetDestination vitess.io/vitess/go/vt/key.Destination
+	if cc, ok := cached.TargetDestination.(cachedObject); ok {
+		size += cc.CachedSize(true)
+	}
+	// field DML vitess.io/vitess/go/vt/sqlparser.Statement
+	if cc, ok := cached.DML.(cachedObject); ok {
+		size += cc.CachedSize(true)
+	}
+	// field TableName string
+	size += hack.RuntimeAllocSize(int64(len(cached.TableName)))
+	return size
+}
 func (cached *CheckCol) CachedSize(alloc bool) int64 {
 	if cached == nil {
 		return int64(0)
@@ -364,7 +386,7 @@ func (cached *Generate) CachedSize(alloc bool) int64 {
 	}
 	size := int64(0)
 	if alloc {
-		size += int64(48)
+		size += int64(56)
 	}
 	// field Keyspace *vitess.io/vitess/go/vt/vtgate/vindexes.Keyspace
 	size += cached.Keyspace.CachedSize(true)
@@ -374,6 +396,8 @@ func (cached *Generate) CachedSize(alloc bool) int64 {
 	if cc, ok := cached.Values.(cachedObject); ok {
 		size += cc.CachedSize(true)
 	}
+	// field Allocator *vitess.io/vitess/go/vt/vtgate/vindexes.SequenceAllocator
+	size += cached.Allocator.CachedSize(true)
 	return size
 }
 func (cached *GroupByParams) CachedSize(alloc bool) int64 {
@@ -914,7 +938,7 @@ func (cached *RoutingParameters) CachedSize(alloc bool) int64 {
 	}
 	size := int64(0)
 	if alloc {
-		size += int64(112)
+		size += int64(120)
 	}
 	// field Keyspace *vitess.io/vitess/go/vt/vtgate/vindexes.Keyspace
 	size += cached.Keyspace.CachedSize(true)
@@ -961,6 +985,8 @@ func (cached *RoutingParameters) CachedSize(alloc bool) int64 {
 			}
 		}
 	}
+	// field SequenceAllocator *vitess.io/vitess/go/vt/vtgate/vindexes.SequenceAllocator
+	size += cached.SequenceAllocator.CachedSize(true)
 	return size
 }
 func (cached *Rows) CachedSize(alloc bool) int64 {
@@ -108,6 +108,10 @@ func (t *noopVCursor) GetWarmingReadsChannel() chan bool {
 	panic("implement me")
 }
 
+func (t *noopVCursor) LocalCell() string {
+	return ""
+}
+
 func (t *noopVCursor) CloneForReplicaWarming(ctx context.Context) VCursor {
 	panic("implement me")
 }
@@ -199,6 +203,9 @@ func (t *noopVCursor) GetMigrationContext() string {
 	panic("implement me")
 }
 
+func (t *noopVCursor) SetConsistentSnapshot(bool) {
+}
+
 func (t *noopVCursor) GetSessionUUID() string {
 	panic("implement me")
 }
@@ -421,6 +428,12 @@ type loggingVCursor struct {
 	shardSession []*srvtopo.ResolvedShard
 
 	parser *sqlparser.Parser
+
+	cell string
+}
+
+func (f *loggingVCursor) LocalCell() string {
+	return f.cell
 }
 
 func (f *loggingVCursor) HasCreatedTempTable() {
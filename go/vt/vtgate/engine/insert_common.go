@@ -20,6 +20,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
 	"strconv"
 	"strings"
 
@@ -96,6 +97,9 @@ type (
 		Values evalengine.Expr
 		// Insert using Select, offset for auto increment column
 		Offset int
+		// Allocator is set if the sequence is spread over the shards
+		// of the keyspace.
+		Allocator *vindexes.SequenceAllocator
 	}
 
 	// InsertOpcode is a number representing the opcode
@@ -371,21 +375,19 @@ func (ic *InsertCommon) processGenerateFromSelect(
 		return 0, nil
 	}
 
-	insertID, err = ic.execGenerate(ctx, vcursor, loggingPrimitive, count)
+	runs, err := ic.execGenerate(ctx, vcursor, loggingPrimitive, count)
 	if err != nil {
 		return 0, err
 	}
+	insertID = runs.first()
 
-	used := insertID
 	for idx, val := range rows {
 		if genColPresent {
 			if shouldGenerate(val[offset], evalengine.ParseSQLMode(vcursor.SQLMode())) {
-				val[offset] = sqltypes.NewInt64(used)
-				used++
+				val[offset] = sqltypes.NewInt64(runs.next())
 			}
 		} else {
-			rows[idx] = append(val, sqltypes.NewInt64(used))
-			used++
+			rows[idx] = append(val, sqltypes.NewInt64(runs.next()))
 		}
 	}
 
@@ -420,20 +422,20 @@ func (ic *InsertCommon) processGenerateFromValues(
 		}
 	}
 
-	// If generation is needed, generate the requested number of values (as one call).
+	// If generation is needed, generate the requested number of values.
+	var runs sequenceRuns
 	if count != 0 {
-		insertID, err = ic.execGenerate(ctx, vcursor, loggingPrimitive, count)
+		runs, err = ic.execGenerate(ctx, vcursor, loggingPrimitive, count)
 		if err != nil {
 			return 0, err
 		}
+		insertID = runs.first()
 	}
 
 	// Fill the holes where no value was supplied.
-	cur := insertID
 	for i, v := range values {
 		if shouldGenerate(v, evalengine.ParseSQLMode(vcursor.SQLMode())) {
-			bindVars[SeqVarName+strconv.Itoa(i)] = sqltypes.Int64BindVariable(cur)
-			cur++
+			bindVars[SeqVarName+strconv.Itoa(i)] = sqltypes.Int64BindVariable(runs.next())
 		} else {
 			bindVars[SeqVarName+strconv.Itoa(i)] = sqltypes.ValueBindVariable(v)
 		}
@@ -441,23 +443,100 @@ func (ic *InsertCommon) processGenerateFromValues(
 	return insertID, nil
 }
 
-func (ic *InsertCommon) execGenerate(ctx context.Context, vcursor VCursor, loggingPrimitive Primitive, count int64) (int64, error) {
-	// If generation is needed, generate the requested number of values (as one call).
+// execGenerate fetches count values from the sequence. A sequence that is
+// spread over the shards of its keyspace hands out a limited number of
+// consecutive values at once, so the values may come in several runs.
+func (ic *InsertCommon) execGenerate(ctx context.Context, vcursor VCursor, loggingPrimitive Primitive, count int64) (sequenceRuns, error) {
+	if ic.Generate.Allocator != nil {
+		return ic.execGenerateAllocated(ctx, vcursor, loggingPrimitive, count)
+	}
+
+	// The values of an unsharded sequence are generated as one call.
+	bindVars := map[string]*querypb.BindVariable{nextValBV: sqltypes.Int64BindVariable(count)}
 	rss, _, err := vcursor.ResolveDestinations(ctx, ic.Generate.Keyspace.Name, nil, []key.Destination{key.DestinationAnyShard{}})
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 	if len(rss) != 1 {
-		return 0, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "auto sequence generation can happen through single shard only, it is getting routed to %d shards", len(rss))
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "auto sequence generation can happen through single shard only, it is getting routed to %d shards", len(rss))
 	}
-	bindVars := map[string]*querypb.BindVariable{nextValBV: sqltypes.Int64BindVariable(count)}
 	qr, err := vcursor.ExecuteStandalone(ctx, loggingPrimitive, ic.Generate.Query, bindVars, rss[0])
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 	// If no rows are returned, it's an internal error, and the code
 	// must panic, which will be caught and reported.
-	return qr.Rows[0][0].ToCastInt64()
+	start, err := qr.Rows[0][0].ToCastInt64()
+	if err != nil {
+		return nil, err
+	}
+	return sequenceRuns{{start: start, count: count}}, nil
+}
+
+// execGenerateAllocated fetches count values from a sequence with an
+// allocator. The tablet is allowed to return fewer values than asked for,
+// along with how many it returned, and is asked again for the rest.
+func (ic *InsertCommon) execGenerateAllocated(ctx context.Context, vcursor VCursor, loggingPrimitive Primitive, count int64) (sequenceRuns, error) {
+	rs, bindVars, err := resolveSequenceShard(ctx, vcursor, ic.Generate.Keyspace, ic.Generate.Allocator, nil)
+	if err != nil {
+		return nil, err
+	}
+	bindVars[sqltypes.BvSequencePartial] = sqltypes.Int64BindVariable(1)
+
+	var runs sequenceRuns
+	for remaining := count; remaining > 0; {
+		bv := maps.Clone(bindVars)
+		bv[nextValBV] = sqltypes.Int64BindVariable(remaining)
+		qr, err := vcursor.ExecuteStandalone(ctx, loggingPrimitive, ic.Generate.Query, bv, rs)
+		if err != nil {
+			return nil, err
+		}
+		row := qr.Rows[0]
+		start, err := row[0].ToCastInt64()
+		if err != nil {
+			return nil, err
+		}
+		// A tablet that does not know about partial runs returns all the
+		// values at once, or fails.
+		n := remaining
+		if len(row) > 1 {
+			if n, err = row[1].ToCastInt64(); err != nil {
+				return nil, err
+			}
+			if n < 1 || n > remaining {
+				return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "sequence returned %d values when %d were requested", n, remaining)
+			}
+		}
+		runs = append(runs, sequenceRun{start: start, count: n})
+		remaining -= n
+	}
+	return runs, nil
+}
+
+// sequenceRun is a run of consecutive values fetched from a sequence.
+type sequenceRun struct {
+	start, count int64
+}
+
+// sequenceRuns are the values fetched from a sequence, in the order
+// they are to be used.
+type sequenceRuns []sequenceRun
+
+// first returns the first value, which is reported as the insert id.
+func (r sequenceRuns) first() int64 {
+	return r[0].start
+}
+
+// next consumes and returns the next value.
+func (r *sequenceRuns) next() int64 {
+	run := &(*r)[0]
+	val := run.start
+	run.start++
+	run.count--
+	if run.count == 0 {
+		*r = (*r)[1:]
+	}
+	return val
 }
 
 // shouldGenerate determines if a sequence value should be generated for a given value
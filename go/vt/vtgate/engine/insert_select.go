@@ -358,6 +358,9 @@ func (ic *InsertCommon) commonDesc() map[string]any {
 		} else {
 			other["AutoIncrement"] = fmt.Sprintf("%s:Values::%s", ic.Generate.Query, sqlparser.String(ic.Generate.Values))
 		}
+		if ic.Generate.Allocator != nil {
+			other["SequenceAllocator"] = ic.Generate.Allocator.Type
+		}
 	}
 	return other
 }
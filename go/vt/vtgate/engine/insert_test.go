@@ -120,6 +120,68 @@ func TestInsertUnshardedGenerate(t *testing.T) {
 	expectResult(t, result, &sqltypes.Result{InsertID: 4})
 }
 
+func TestInsertUnshardedGenerateSequenceAllocator(t *testing.T) {
+	ins := newQueryInsert(
+		InsertUnsharded,
+		&vindexes.Keyspace{
+			Name:    "ks",
+			Sharded: false,
+		},
+		"dummy_insert",
+	)
+	ins.Generate = &Generate{
+		Keyspace: &vindexes.Keyspace{
+			Name:    "ks2",
+			Sharded: true,
+		},
+		Query: "dummy_generate",
+		Values: evalengine.NewTupleExpr(
+			evalengine.NullExpr,
+			evalengine.NewLiteralInt(1),
+			evalengine.NullExpr,
+			evalengine.NullExpr,
+		),
+		Allocator: &vindexes.SequenceAllocator{
+			Type:       vindexes.SequenceInterleaved,
+			CellShards: map[string]string{"zone2": "80-"},
+		},
+	}
+
+	vc := newDMLTestVCursor("0")
+	vc.ksShardMap = map[string][]string{"ks2": {"-80", "80-"}}
+	vc.cell = "zone2"
+	vc.results = []*sqltypes.Result{
+		sqltypes.MakeTestResult(
+			sqltypes.MakeTestFields(
+				"nextval|count",
+				"int64|int64",
+			),
+			"20|2",
+		),
+		sqltypes.MakeTestResult(
+			sqltypes.MakeTestFields(
+				"nextval|count",
+				"int64|int64",
+			),
+			"50|1",
+		),
+		{InsertID: 1},
+	}
+
+	result, err := ins.TryExecute(context.Background(), vc, map[string]*querypb.BindVariable{}, false)
+	require.NoError(t, err)
+	vc.ExpectLog(t, []string{
+		// The sequence values are fetched from the shard of the local cell,
+		// which may hand them out in several runs.
+		`ResolveDestinations ks2 [] Destinations:DestinationAllShards()`,
+		`ExecuteStandalone dummy_generate __vtseqallocator: type:VARCHAR value:"interleaved" __vtseqepoch: type:INT64 value:"0" __vtseqpartial: type:INT64 value:"1" n: type:INT64 value:"3" ks2 80-`,
+		`ExecuteStandalone dummy_generate __vtseqallocator: type:VARCHAR value:"interleaved" __vtseqepoch: type:INT64 value:"0" __vtseqpartial: type:INT64 value:"1" n: type:INT64 value:"1" ks2 80-`,
+		`ResolveDestinations ks [] Destinations:DestinationAllShards()`,
+		`ExecuteMultiShard ks.0: dummy_insert {__seq0: type:INT64 value:"20" __seq1: type:INT64 value:"1" __seq2: type:INT64 value:"21" __seq3: type:INT64 value:"50"} true true`,
+	})
+	expectResult(t, result, &sqltypes.Result{InsertID: 20})
+}
+
 func TestInsertUnshardedGenerate_Zeros(t *testing.T) {
 	ins := newQueryInsert(
 		InsertUnsharded,
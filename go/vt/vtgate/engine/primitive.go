@@ -131,6 +131,9 @@ type (
 
 		// CloneForReplicaWarming clones the VCursor for re-use in warming queries to replicas
 		CloneForReplicaWarming(ctx context.Context) VCursor
+
+		// LocalCell returns the cell of this vtgate
+		LocalCell() string
 	}
 
 	// SessionActions gives primitives ability to interact with the session state
@@ -170,6 +173,7 @@ type (
 		GetDDLStrategy() string
 		SetMigrationContext(string)
 		GetMigrationContext() string
+		SetConsistentSnapshot(bool)
 
 		GetSessionUUID() string
 
@@ -261,6 +261,49 @@ func TestSelectEqualUnique(t *testing.T) {
 	expectResult(t, result, defaultSelectResult)
 }
 
+func TestSelectRange(t *testing.T) {
+	vindex, _ := vindexes.CreateVindex("numeric", "", nil)
+	sel := NewRoute(
+		Range,
+		&vindexes.Keyspace{
+			Name:    "ks",
+			Sharded: true,
+		},
+		"dummy_select",
+		"dummy_select_field",
+	)
+	sel.Vindex = vindex.(vindexes.SingleColumn)
+
+	sel.Values = []evalengine.Expr{
+		evalengine.NewLiteralUint(0x1000000000000000),
+		evalengine.NewLiteralUint(0x3000000000000000),
+	}
+	vc := &loggingVCursor{
+		shards:       []string{"-20", "20-40", "40-"},
+		shardForKsid: []string{"-20", "20-40"},
+		results:      []*sqltypes.Result{defaultSelectResult},
+	}
+	result, err := sel.TryExecute(context.Background(), vc, map[string]*querypb.BindVariable{}, false)
+	require.NoError(t, err)
+	vc.ExpectLog(t, []string{
+		`ResolveDestinations ks [] Destinations:DestinationKeyRange(1000000000000000-300000000000000000)`,
+		`ExecuteMultiShard ks.-20: dummy_select {} ks.20-40: dummy_select {} false false`,
+	})
+	expectResult(t, result, defaultSelectResult)
+
+	// A NULL bound leaves the range open on that side, which the numeric
+	// vindex maps to all shards, since the negative ids come last.
+	sel.Values[1] = evalengine.NullExpr
+	vc.Rewind()
+	result, err = wrapStreamExecute(sel, vc, map[string]*querypb.BindVariable{}, false)
+	require.NoError(t, err)
+	vc.ExpectLog(t, []string{
+		`ResolveDestinations ks [] Destinations:DestinationAllShards()`,
+		`StreamExecuteMulti dummy_select ks.-20: {} ks.20-40: {} ks.40-: {} `,
+	})
+	expectResult(t, result, defaultSelectResult)
+}
+
 func TestSelectNone(t *testing.T) {
 	vindex, _ := vindexes.CreateVindex("hash", "", nil)
 	sel := NewRoute(
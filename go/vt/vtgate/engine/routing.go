@@ -19,6 +19,8 @@ package engine
 import (
 	"context"
 	"encoding/json"
+	"maps"
+	"slices"
 	"strconv"
 
 	"vitess.io/vitess/go/sqltypes"
@@ -71,6 +73,9 @@ const (
 	// Is used when the query explicitly sets a target destination:
 	// in the clause e.g: UPDATE `keyspace[-]`.x1 SET foo=1
 	ByDestination
+	// Range is for routing a query to the shards covering a range of values.
+	// Requires: A Sequential Vindex, and the start and end Values of the range.
+	Range
 )
 
 var opName = map[Opcode]string{
@@ -86,6 +91,7 @@ var opName = map[Opcode]string{
 	None:          "None",
 	ByDestination: "ByDestination",
 	SubShard:      "SubShard",
+	Range:         "Range",
 }
 
 // MarshalJSON serializes the Opcode as a JSON string.
@@ -119,6 +125,10 @@ type RoutingParameters struct {
 
 	// Values specifies the vindex values to use for routing.
 	Values []evalengine.Expr
+
+	// SequenceAllocator is set when fetching from a sequence that is spread
+	// over the shards of the keyspace.
+	SequenceAllocator *vindexes.SequenceAllocator
 }
 
 func (code Opcode) IsSingleShard() bool {
@@ -135,7 +145,16 @@ func (rp *RoutingParameters) findRoute(ctx context.Context, vcursor VCursor, bin
 		return nil, nil, nil
 	case DBA:
 		return rp.systemQuery(ctx, vcursor, bindVars)
-	case Unsharded, Next:
+	case Next:
+		if rp.SequenceAllocator != nil {
+			rs, bv, err := resolveSequenceShard(ctx, vcursor, rp.Keyspace, rp.SequenceAllocator, bindVars)
+			if err != nil {
+				return nil, nil, err
+			}
+			return []*srvtopo.ResolvedShard{rs}, []map[string]*querypb.BindVariable{bv}, nil
+		}
+		return rp.unsharded(ctx, vcursor, bindVars)
+	case Unsharded:
 		return rp.unsharded(ctx, vcursor, bindVars)
 	case Reference:
 		return rp.anyShard(ctx, vcursor, bindVars)
@@ -164,6 +183,8 @@ func (rp *RoutingParameters) findRoute(ctx context.Context, vcursor VCursor, bin
 		default:
 			return rp.multiEqual(ctx, vcursor, bindVars)
 		}
+	case Range:
+		return rp.valueRange(ctx, vcursor, bindVars)
 	default:
 		// Unreachable.
 		return nil, nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "unsupported opcode: %v", rp.Opcode)
@@ -320,6 +341,40 @@ func (rp *RoutingParameters) unsharded(ctx context.Context, vcursor VCursor, bin
 	return rss, multiBindVars, nil
 }
 
+// resolveSequenceShard picks the shard to fetch values from for a sequence that
+// is spread over the shards of a keyspace, and returns it with the bind
+// variables that tell the shard how to allocate the values. It prefers the
+// shard configured for the cell of this vtgate, and otherwise picks a random
+// shard. The position of each shard among the shards of the sequence is stored
+// in its backing row, so it does not depend on the shards of the keyspace.
+func resolveSequenceShard(ctx context.Context, vcursor VCursor, keyspace *vindexes.Keyspace, allocator *vindexes.SequenceAllocator, bindVars map[string]*querypb.BindVariable) (*srvtopo.ResolvedShard, map[string]*querypb.BindVariable, error) {
+	rss, _, err := vcursor.ResolveDestinations(ctx, keyspace.Name, nil, []key.Destination{key.DestinationAllShards{}})
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(rss) == 0 {
+		return nil, nil, vterrors.Errorf(vtrpcpb.Code_UNAVAILABLE, "no shard in keyspace %s", keyspace.Name)
+	}
+
+	idx := -1
+	if shard, ok := allocator.CellShards[vcursor.LocalCell()]; ok {
+		idx = slices.IndexFunc(rss, func(rs *srvtopo.ResolvedShard) bool {
+			return rs.Target.Shard == shard
+		})
+	}
+	if idx < 0 {
+		idx = key.AnyShardPicker.PickShard(len(rss))
+	}
+
+	bv := maps.Clone(bindVars)
+	if bv == nil {
+		bv = make(map[string]*querypb.BindVariable, 2)
+	}
+	bv[sqltypes.BvSequenceAllocator] = sqltypes.StringBindVariable(allocator.Type)
+	bv[sqltypes.BvSequenceEpoch] = sqltypes.Int64BindVariable(allocator.Epoch)
+	return rss[idx], bv, nil
+}
+
 func (rp *RoutingParameters) byDestination(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, destination key.Destination) ([]*srvtopo.ResolvedShard, []map[string]*querypb.BindVariable, error) {
 	rss, _, err := vcursor.ResolveDestinations(ctx, rp.Keyspace.Name, nil, []key.Destination{destination})
 	if err != nil {
@@ -397,6 +452,29 @@ func (rp *RoutingParameters) inMultiCol(ctx context.Context, vcursor VCursor, bi
 	return rss, shardVarsMultiCol(bindVars, mapVals, isSingleVal), nil
 }
 
+func (rp *RoutingParameters) valueRange(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable) ([]*srvtopo.ResolvedShard, []map[string]*querypb.BindVariable, error) {
+	vindex, ok := rp.Vindex.(vindexes.Sequential)
+	if !ok {
+		return nil, nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "vindex %s does not support ranges", rp.Vindex.String())
+	}
+
+	env := evalengine.NewExpressionEnv(ctx, bindVars, vcursor)
+	var bounds [2]sqltypes.Value
+	for i, value := range rp.Values {
+		v, err := env.Evaluate(value)
+		if err != nil {
+			return nil, nil, err
+		}
+		bounds[i] = v.Value(vcursor.ConnCollation())
+	}
+
+	destination, err := vindex.RangeMap(ctx, vcursor, bounds[0], bounds[1])
+	if err != nil {
+		return nil, nil, err
+	}
+	return rp.byDestination(ctx, vcursor, bindVars, destination)
+}
+
 func (rp *RoutingParameters) multiEqual(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable) ([]*srvtopo.ResolvedShard, []map[string]*querypb.BindVariable, error) {
 	env := evalengine.NewExpressionEnv(ctx, bindVars, vcursor)
 	value, err := env.Evaluate(rp.Values[0])
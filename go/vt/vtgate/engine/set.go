@@ -239,6 +239,10 @@ func (svci *SysVarCheckAndIgnore) VariableName() string {
 
 // Execute implements the SetOp interface method
 func (svci *SysVarCheckAndIgnore) Execute(ctx context.Context, vcursor VCursor, env *evalengine.ExpressionEnv) error {
+	if svci.Name == sysvars.TransactionIsolation.Name || svci.Name == sysvars.TxIsolation.Name {
+		// Any MySQL isolation level takes the session out of the consistent snapshot mode.
+		vcursor.Session().SetConsistentSnapshot(false)
+	}
 	rss, _, err := vcursor.ResolveDestinations(ctx, svci.Keyspace.Name, nil, []key.Destination{svci.TargetDestination})
 	if err != nil {
 		return err
@@ -279,6 +283,10 @@ func (svs *SysVarReservedConn) VariableName() string {
 
 // Execute implements the SetOp interface method
 func (svs *SysVarReservedConn) Execute(ctx context.Context, vcursor VCursor, env *evalengine.ExpressionEnv) error {
+	if svs.Name == sysvars.TransactionIsolation.Name || svs.Name == sysvars.TxIsolation.Name {
+		// Any MySQL isolation level takes the session out of the consistent snapshot mode.
+		vcursor.Session().SetConsistentSnapshot(false)
+	}
 	// For those running on advanced vitess settings.
 	if svs.TargetDestination != nil {
 		rss, _, err := vcursor.ResolveDestinations(ctx, svs.Keyspace.Name, nil, []key.Destination{svs.TargetDestination})
@@ -539,6 +547,15 @@ func (svss *SysVarSetAware) Execute(ctx context.Context, vcursor VCursor, env *e
 		default:
 			return vterrors.NewErrorf(vtrpcpb.Code_INVALID_ARGUMENT, vterrors.WrongValueForVar, "variable 'session_track_gtids' can't be set to the value of '%s'", str)
 		}
+	case sysvars.TransactionIsolation.Name, sysvars.TxIsolation.Name:
+		str, err := svss.evalAsString(env, vcursor)
+		if err != nil {
+			return err
+		}
+		if !strings.EqualFold(str, sysvars.ConsistentSnapshot) {
+			return vterrors.NewErrorf(vtrpcpb.Code_INVALID_ARGUMENT, vterrors.WrongValueForVar, "variable '%s' can't be set to the value of '%s'", svss.Name, str)
+		}
+		vcursor.Session().SetConsistentSnapshot(true)
 	default:
 		return vterrors.NewErrorf(vtrpcpb.Code_NOT_FOUND, vterrors.UnknownSystemVariable, "unknown system variable '%s'", svss.Name)
 	}
@@ -23,6 +23,9 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -113,6 +116,12 @@ type Executor struct {
 	plans *PlanCache
 	epoch atomic.Uint32
 
+	// queryDigests holds the execution statistics of the most expensive normalized queries. It is
+	// nil when query digests are disabled.
+	queryDigests *queryDigests
+	// tenantQuotas enforces the per-tenant resource quotas of the SrvVSchema.
+	tenantQuotas *tenantQuotas
+
 	normalize       bool
 	warnShardedOnly bool
 
@@ -133,6 +142,7 @@ var executorOnce sync.Once
 
 const pathQueryPlans = "/debug/query_plans"
 const pathScatterStats = "/debug/scatter_stats"
+const pathQueryDigests = "/debug/query_digests"
 const pathVSchema = "/debug/vschema"
 
 type PlanCacheKey = theine.HashKey256
@@ -175,16 +185,19 @@ func NewExecutor(
 		plans:               plans,
 		warmingReadsPercent: warmingReadsPercent,
 		warmingReadsChannel: make(chan bool, warmingReadsConcurrency),
+		queryDigests:        newQueryDigests(queryDigestsSize),
+		tenantQuotas:        newTenantQuotas(),
 	}
 
 	vschemaacl.Init()
 	// we subscribe to update from the VSchemaManager
 	e.vm = &VSchemaManager{
-		subscriber: e.SaveVSchema,
-		serv:       serv,
-		cell:       cell,
-		schema:     e.schemaTracker,
-		parser:     env.Parser(),
+		subscriber:   e.SaveVSchema,
+		serv:         serv,
+		cell:         cell,
+		schema:       e.schemaTracker,
+		parser:       env.Parser(),
+		tenantQuotas: e.tenantQuotas,
 	}
 	serv.WatchSrvVSchema(ctx, cell, e.vm.VSchemaUpdate)
 
@@ -210,6 +223,10 @@ func NewExecutor(
 		servenv.HTTPHandle(pathQueryPlans, e)
 		servenv.HTTPHandle(pathScatterStats, e)
 		servenv.HTTPHandle(pathVSchema, e)
+		servenv.HTTPHandle(pathQueryDigests, e)
+		if queryDigestsExportTopK > 0 {
+			publishQueryDigests(e.queryDigests, queryDigestsExportTopK)
+		}
 	})
 	return e
 }
@@ -333,6 +350,9 @@ func (e *Executor) StreamExecute(
 		err := vc.StreamExecutePrimitive(ctx, plan.Instructions, bindVars, true, func(qr *sqltypes.Result) error {
 			return srr.storeResultStats(plan.Type, qr)
 		})
+		srr.mu.Lock()
+		e.queryDigests.record(plan, time.Since(logStats.StartTime), logStats.ShardQueries, logStats.RowsExamined, srr.rowsAffected, uint64(srr.rowsReturned), err)
+		srr.mu.Unlock()
 
 		// Check if there was partial DML execution. If so, rollback the effect of the partially executed query.
 		if err != nil {
@@ -519,6 +539,16 @@ func (e *Executor) addNeededBindVars(vcursor *vcursorImpl, bindVarNeeds *sqlpars
 			bindVars[key] = sqltypes.StringBindVariable(servenv.AppVersion.String())
 		case sysvars.Socket.Name:
 			bindVars[key] = sqltypes.StringBindVariable(mysqlSocketPath())
+		case sysvars.ConsistentSnapshotGTIDs.Name:
+			var v string
+			if gtids := session.GetConsistentSnapshotGTIDs(); len(gtids) > 0 {
+				b, err := json.Marshal(gtids)
+				if err != nil {
+					return err
+				}
+				v = string(b)
+			}
+			bindVars[key] = sqltypes.StringBindVariable(v)
 		default:
 			if value, hasSysVar := session.SystemVariables[sysVar]; hasSysVar {
 				expr, err := e.env.Parser().ParseExpr(value)
@@ -578,6 +608,12 @@ func (e *Executor) handleBegin(ctx context.Context, safeSession *SafeSession, lo
 
 	begin := stmt.(*sqlparser.Begin)
 	err := e.txConn.Begin(ctx, safeSession, begin.TxAccessModes)
+	if err == nil && e.wantsConsistentSnapshot(safeSession, begin) {
+		if err = e.beginConsistentSnapshot(ctx, safeSession); err != nil {
+			// Don't leave the session in a transaction without the snapshot.
+			_ = e.txConn.Rollback(ctx, safeSession)
+		}
+	}
 	logStats.ExecuteTime = time.Since(execStart)
 
 	e.updateQueryCounts("Begin", "", "", 0)
@@ -585,6 +621,44 @@ func (e *Executor) handleBegin(ctx context.Context, safeSession *SafeSession, lo
 	return &sqltypes.Result{}, err
 }
 
+// wantsConsistentSnapshot returns true if the transaction has to be started from a
+// cross-shard consistent snapshot. This is the case when the session is in the
+// CONSISTENT_SNAPSHOT isolation mode, or when a keyspace is targeted by a
+// START TRANSACTION WITH CONSISTENT SNAPSHOT statement.
+func (e *Executor) wantsConsistentSnapshot(safeSession *SafeSession, begin *sqlparser.Begin) bool {
+	if safeSession.GetConsistentSnapshot() {
+		return true
+	}
+	if !slices.Contains(begin.TxAccessModes, sqlparser.WithConsistentSnapshot) {
+		return false
+	}
+	keyspace, _, _, err := e.ParseDestinationTarget(safeSession.TargetString)
+	return err == nil && keyspace != ""
+}
+
+// beginConsistentSnapshot starts the transaction on all the shards of the targeted keyspace
+// from a cross-shard consistent snapshot.
+func (e *Executor) beginConsistentSnapshot(ctx context.Context, safeSession *SafeSession) error {
+	keyspace, tabletType, dest, err := e.ParseDestinationTarget(safeSession.TargetString)
+	if err != nil {
+		return err
+	}
+	if keyspace == "" {
+		return vterrors.VT09005()
+	}
+	if tabletType != topodatapb.TabletType_PRIMARY {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "consistent snapshot transactions are only supported on primary tablets, got %v", tabletType)
+	}
+	if dest == nil {
+		dest = key.DestinationAllShards{}
+	}
+	rss, _, err := e.resolver.resolver.ResolveDestinations(ctx, keyspace, tabletType, nil, []key.Destination{dest})
+	if err != nil {
+		return err
+	}
+	return e.txConn.BeginConsistentSnapshot(ctx, safeSession, rss)
+}
+
 func (e *Executor) handleCommit(ctx context.Context, safeSession *SafeSession, logStats *logstats.LogStats) (*sqltypes.Result, error) {
 	execStart := time.Now()
 	logStats.PlanTime = execStart.Sub(logStats.StartTime)
@@ -917,6 +991,49 @@ func (e *Executor) showTablets(filter *sqlparser.ShowFilter) (*sqltypes.Result,
 	}, nil
 }
 
+// showQueryDigests returns the execution statistics of the query digests, the digests with the
+// highest total execution time first. Times are in seconds.
+func (e *Executor) showQueryDigests(filter *sqlparser.ShowFilter) (*sqltypes.Result, error) {
+	var queryRegexp *regexp.Regexp
+	if filter != nil && filter.Like != "" {
+		queryRegexp = sqlparser.LikeToRegexp(filter.Like)
+	}
+	seconds := func(d time.Duration) string {
+		return fmt.Sprintf("%.6f", d.Seconds())
+	}
+
+	var rows [][]sqltypes.Value
+	for _, qds := range e.queryDigests.top(0) {
+		if queryRegexp != nil && !queryRegexp.MatchString(qds.Query) {
+			continue
+		}
+		rows = append(rows, buildVarCharRow(
+			qds.Digest,
+			qds.Keyspace,
+			e.env.Parser().TruncateForUI(qds.Query),
+			qds.StmtType,
+			strconv.FormatUint(qds.Count, 10),
+			strconv.FormatUint(qds.Errors, 10),
+			seconds(qds.TotalTime),
+			seconds(qds.P50),
+			seconds(qds.P95),
+			seconds(qds.P99),
+			seconds(qds.MaxTime),
+			strconv.FormatUint(qds.ShardQueries, 10),
+			strconv.FormatUint(qds.RowsExamined, 10),
+			strconv.FormatUint(qds.RowsAffected, 10),
+			strconv.FormatUint(qds.RowsReturned, 10),
+			qds.FirstSeen.UTC().Format(time.RFC3339),
+			qds.LastSeen.UTC().Format(time.RFC3339),
+		))
+	}
+	return &sqltypes.Result{
+		Fields: buildVarCharFields("Digest", "Keyspace", "Query", "StmtType", "Count", "Errors", "TotalTime", "P50", "P95", "P99", "MaxTime",
+			"ShardQueries", "RowsExamined", "RowsAffected", "RowsReturned", "FirstSeen", "LastSeen"),
+		Rows: rows,
+	}, nil
+}
+
 func (e *Executor) showVitessReplicationStatus(ctx context.Context, filter *sqlparser.ShowFilter) (*sqltypes.Result, error) {
 	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
 	defer cancel()
@@ -1235,6 +1352,8 @@ func (e *Executor) ServeHTTP(response http.ResponseWriter, request *http.Request
 		returnAsJSON(response, e.VSchema())
 	case pathScatterStats:
 		e.WriteScatterStats(response)
+	case pathQueryDigests:
+		returnAsJSON(response, e.queryDigests.top(0))
 	default:
 		response.WriteHeader(http.StatusNotFound)
 	}
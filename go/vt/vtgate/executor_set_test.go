@@ -158,6 +158,12 @@ func TestExecutorSet(t *testing.T) {
 	}, {
 		in:  "set transaction_isolation = 'read-committed'",
 		out: &vtgatepb.Session{Autocommit: true},
+	}, {
+		in:  "set transaction_isolation = 'consistent_snapshot'",
+		out: &vtgatepb.Session{Autocommit: true, ConsistentSnapshot: true},
+	}, {
+		in:  "set transaction_isolation = 'consistent_snapshot', tx_isolation = 'read-committed'",
+		out: &vtgatepb.Session{Autocommit: true},
 	}, {
 		in:  "set transaction_mode = 'twopc', autocommit=1",
 		out: &vtgatepb.Session{Autocommit: true, TransactionMode: vtgatepb.TransactionMode_TWOPC},
@@ -257,6 +263,9 @@ func TestExecutorSet(t *testing.T) {
 	}, {
 		in:  "set @@socket = '/tmp/change.sock'",
 		err: "VT03010: variable 'socket' is a read only variable",
+	}, {
+		in:  "set @@consistent_snapshot_gtids = 'uuid:1-5'",
+		err: "VT03010: variable 'consistent_snapshot_gtids' is a read only variable",
 	}, {
 		in:  "set @@query_timeout = 50",
 		out: &vtgatepb.Session{Autocommit: true, QueryTimeout: 50},
@@ -194,6 +194,34 @@ func TestExecutorTransactionsNoAutoCommit(t *testing.T) {
 	require.EqualError(t, err, `can't execute the given command because you have an active transaction`)
 }
 
+func TestExecutorReadOnlyTransactionOnReplica(t *testing.T) {
+	ctx := utils.LeakCheckContext(t)
+	executor, primary, replica := createExecutorEnvWithPrimaryReplicaConn(t, ctx, 0)
+
+	session := NewSafeSession(&vtgatepb.Session{
+		TargetString: "TestUnsharded@replica",
+		Options:      &querypb.ExecuteOptions{TransactionIsolation: querypb.ExecuteOptions_REPEATABLE_READ},
+	})
+
+	_, err := executor.Execute(ctx, nil, "TestExecute", session, "start transaction read only", nil)
+	require.NoError(t, err)
+	_, err = executor.Execute(ctx, nil, "TestExecute", session, "select id from user", nil)
+	require.NoError(t, err)
+
+	assert.EqualValues(t, 0, primary.BeginCount.Load(), "primary begin count")
+	assert.EqualValues(t, 1, replica.BeginCount.Load(), "replica begin count")
+	require.NotEmpty(t, replica.Options)
+	utils.MustMatch(t, &querypb.ExecuteOptions{
+		TransactionIsolation:  querypb.ExecuteOptions_REPEATABLE_READ,
+		TransactionAccessMode: []querypb.ExecuteOptions_TransactionAccessMode{querypb.ExecuteOptions_READ_ONLY},
+	}, replica.Options[len(replica.Options)-1], "options")
+
+	_, err = executor.Execute(ctx, nil, "TestExecute", session, "commit", nil)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, replica.CommitCount.Load(), "replica commit count")
+	assert.Empty(t, session.Options.TransactionAccessMode, "access modes should only apply to one transaction")
+}
+
 func TestDirectTargetRewrites(t *testing.T) {
 	executor, _, _, sbclookup, ctx := createExecutorEnv(t)
 
@@ -2730,6 +2758,44 @@ func TestExecutorStartTxnStmt(t *testing.T) {
 	}
 }
 
+func TestExecutorBeginConsistentSnapshotFailure(t *testing.T) {
+	executor, sbc1, _, _, ctx := createExecutorEnv(t)
+
+	// Taking the global read lock fails on one of the shards.
+	sbc1.MustFailExecute[sqlparser.StmtFlush] = 1
+	session := NewAutocommitSession(&vtgatepb.Session{TargetString: "TestExecutor"})
+	_, err := executor.Execute(ctx, nil, "TestExecutorBeginConsistentSnapshotFailure", session, "start transaction with consistent snapshot", nil)
+	require.ErrorContains(t, err, "unable to lock shards for a consistent snapshot")
+
+	// The lock that was taken is released.
+	assert.EqualValues(t, 1, sbc1.ReleaseCount.Load())
+	// The session is not left in a transaction without the snapshot.
+	assert.False(t, session.InTransaction())
+	assert.Empty(t, session.ShardSessions)
+}
+
+func TestExecutorBeginConsistentSnapshotGTIDs(t *testing.T) {
+	executor, _, _, sbclookup, ctx := createExecutorEnv(t)
+
+	sbclookup.SetResults([]*sqltypes.Result{{}, {}, sqltypes.MakeTestResult(sqltypes.MakeTestFields("@@global.gtid_executed", "varchar"), "uuid:1-5")})
+	session := NewAutocommitSession(&vtgatepb.Session{TargetString: KsTestUnsharded})
+	_, err := executor.Execute(ctx, nil, "TestExecutorBeginConsistentSnapshotGTIDs", session, "start transaction with consistent snapshot", nil)
+	require.NoError(t, err)
+	require.Len(t, session.ShardSessions, 1)
+	assert.Equal(t, "uuid:1-5", session.ShardSessions[0].GtidExecuted)
+
+	// The GTIDs are available to the client until the transaction ends.
+	qr, err := executor.Execute(ctx, nil, "TestExecutorBeginConsistentSnapshotGTIDs", session, "select @@consistent_snapshot_gtids", nil)
+	require.NoError(t, err)
+	assert.Equal(t, `{"TestUnsharded/0":"uuid:1-5"}`, qr.Rows[0][0].ToString())
+
+	_, err = executor.Execute(ctx, nil, "TestExecutorBeginConsistentSnapshotGTIDs", session, "rollback", nil)
+	require.NoError(t, err)
+	qr, err = executor.Execute(ctx, nil, "TestExecutorBeginConsistentSnapshotGTIDs", session, "select @@consistent_snapshot_gtids", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "", qr.Rows[0][0].ToString())
+}
+
 func TestExecutorPrepareExecute(t *testing.T) {
 	executor, _, _, _, _ := createExecutorEnv(t)
 
@@ -2907,6 +2973,31 @@ func TestExecutorKillStmt(t *testing.T) {
 	}
 }
 
+func TestExecutorTenantQuotas(t *testing.T) {
+	executor, sbc1, _, _, ctx := createExecutorEnv(t)
+	executor.tenantQuotas.update(&vschemapb.TenantQuotas{
+		TenantKey: "tag",
+		Quotas:    []*vschemapb.TenantQuota{{Tenant: "acme", MaxShards: 1, MaxConcurrentQueries: 10}},
+	})
+	session := &vtgatepb.Session{TargetString: "@primary"}
+
+	// A scatter query exceeds the quota of the tenant, before it is sent to any shard.
+	_, err := executorExec(ctx, executor, session, "select /*vt+ TENANT=acme */ id from user", nil)
+	require.ErrorContains(t, err, "tenant quota exceeded for 'acme': Shards limit of 1")
+	assert.Equal(t, sqlerror.ERUserLimitReached, sqlerror.NewSQLErrorFromError(err).(*sqlerror.SQLError).Number())
+	assert.Empty(t, sbc1.Queries)
+
+	_, err = executorExec(ctx, executor, session, "select /*vt+ TENANT=acme */ id from user where id = 1", nil)
+	require.NoError(t, err)
+	assert.EqualValues(t, 0, executor.tenantQuotas.tenants["acme"].concurrent)
+
+	// Queries of other tenants are not limited.
+	_, err = executorExec(ctx, executor, session, "select /*vt+ TENANT=globex */ id from user", nil)
+	require.NoError(t, err)
+	_, err = executorExec(ctx, executor, session, "select id from user", nil)
+	require.NoError(t, err)
+}
+
 type fakeMysqlConnection struct {
 	ErrMsg string
 	Log    []string
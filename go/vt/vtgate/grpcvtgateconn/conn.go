@@ -35,6 +35,7 @@ import (
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
 	vtgateservicepb "vitess.io/vitess/go/vt/proto/vtgateservice"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 )
 
 var (
@@ -118,7 +119,7 @@ func (conn *vtgateConn) Execute(ctx context.Context, session *vtgatepb.Session,
 	}
 	response, err := conn.c.Execute(ctx, request)
 	if err != nil {
-		return session, nil, vterrors.FromGRPC(err)
+		return session, nil, fromGRPCCall(err)
 	}
 	if response.Error != nil {
 		return response.Session, nil, vterrors.FromVTRPC(response.Error)
@@ -142,7 +143,7 @@ func (conn *vtgateConn) ExecuteBatch(ctx context.Context, session *vtgatepb.Sess
 	}
 	response, err := conn.c.ExecuteBatch(ctx, request)
 	if err != nil {
-		return session, nil, vterrors.FromGRPC(err)
+		return session, nil, fromGRPCCall(err)
 	}
 	if response.Error != nil {
 		return response.Session, nil, vterrors.FromVTRPC(response.Error)
@@ -186,7 +187,7 @@ func (conn *vtgateConn) StreamExecute(ctx context.Context, session *vtgatepb.Ses
 	}
 	stream, err := conn.c.StreamExecute(ctx, req)
 	if err != nil {
-		return nil, vterrors.FromGRPC(err)
+		return nil, fromGRPCCall(err)
 	}
 	return &streamExecuteAdapter{
 		recv: func() (*querypb.QueryResult, error) {
@@ -211,7 +212,7 @@ func (conn *vtgateConn) Prepare(ctx context.Context, session *vtgatepb.Session,
 	}
 	response, err := conn.c.Prepare(ctx, request)
 	if err != nil {
-		return session, nil, vterrors.FromGRPC(err)
+		return session, nil, fromGRPCCall(err)
 	}
 	if response.Error != nil {
 		return response.Session, nil, vterrors.FromVTRPC(response.Error)
@@ -226,7 +227,7 @@ func (conn *vtgateConn) CloseSession(ctx context.Context, session *vtgatepb.Sess
 	}
 	response, err := conn.c.CloseSession(ctx, request)
 	if err != nil {
-		return vterrors.FromGRPC(err)
+		return fromGRPCCall(err)
 	}
 	if response.Error != nil {
 		return vterrors.FromVTRPC(response.Error)
@@ -267,7 +268,7 @@ func (conn *vtgateConn) VStream(ctx context.Context, tabletType topodatapb.Table
 	}
 	stream, err := conn.c.VStream(ctx, req)
 	if err != nil {
-		return nil, vterrors.FromGRPC(err)
+		return nil, fromGRPCCall(err)
 	}
 	return &vstreamAdapter{
 		stream: stream,
@@ -280,3 +281,15 @@ func (conn *vtgateConn) Close() {
 
 // Make sure vtgateConn implements vtgateconn.Impl
 var _ vtgateconn.Impl = (*vtgateConn)(nil)
+
+// fromGRPCCall converts the error of a gRPC call whose vtgate errors are
+// returned in its response, or of the start of a stream. An UNAVAILABLE error
+// then means that the vtgate could not be reached at all, rather than one that
+// it relays from a tablet.
+func fromGRPCCall(err error) error {
+	err = vterrors.FromGRPC(err)
+	if vterrors.Code(err) == vtrpcpb.Code_UNAVAILABLE {
+		return vtgateconn.NewUnreachableError(err)
+	}
+	return err
+}
@@ -44,6 +44,7 @@ type LogStats struct {
 	ShardQueries   uint64
 	RowsAffected   uint64
 	RowsReturned   uint64
+	RowsExamined   uint64 // RowsExamined is the number of rows that the shards returned to vtgate
 	PlanTime       time.Duration
 	ExecuteTime    time.Duration
 	CommitTime     time.Duration
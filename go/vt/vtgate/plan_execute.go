@@ -85,6 +85,15 @@ func (e *Executor) newExecute(
 		return err
 	}
 
+	// Enforce the resource quota of the tenant of the query.
+	tenantQuota, err := e.tenantQuotas.admit(ctx, stmt)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		tenantQuota.done(logStats.RowsExamined)
+	}()
+
 	var lastVSchemaCreated time.Time
 	vs := e.VSchema()
 	lastVSchemaCreated = vs.GetCreated()
@@ -101,6 +110,7 @@ func (e *Executor) newExecute(
 		if err != nil {
 			return err
 		}
+		vcursor.tenantQuota = tenantQuota
 
 		// 3: Create a plan for the query
 		// If we are retrying, it is likely that the routing rules have changed and hence we need to
@@ -344,6 +354,7 @@ func (e *Executor) setLogStats(logStats *logstats.LogStats, plan *engine.Plan, v
 	logStats.TabletType = vcursor.TabletType().String()
 	errCount := e.logExecutionEnd(logStats, execStart, plan, err, qr)
 	plan.AddStats(1, time.Since(logStats.StartTime), logStats.ShardQueries, logStats.RowsAffected, logStats.RowsReturned, errCount)
+	e.queryDigests.record(plan, time.Since(logStats.StartTime), logStats.ShardQueries, logStats.RowsExamined, logStats.RowsAffected, logStats.RowsReturned, err)
 }
 
 func (e *Executor) logExecutionEnd(logStats *logstats.LogStats, execStart time.Time, plan *engine.Plan, err error, qr *sqltypes.Result) uint64 {
@@ -0,0 +1,116 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package planbuilder
+
+import (
+	"vitess.io/vitess/go/vt/key"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/schema"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/engine"
+	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+)
+
+// buildBatchDMLPlan plans an UPDATE or DELETE statement that carries the BATCH_DML directive. Such a
+// statement is not executed by vtgate; it is submitted as a job to the primary tablet of every target
+// shard, which then runs it in batches. Since the job runs on each shard independently, the
+// statement may not change anything that vtgate would otherwise need to maintain across shards.
+func buildBatchDMLPlan(stmt sqlparser.Statement, batchSize int64, vschema plancontext.VSchema) (*planResult, error) {
+	tableName, err := schema.ValidateOnlineDMLStatement(stmt)
+	if err != nil {
+		return nil, err
+	}
+	vschemaTable, _, tabletType, dest, err := vschema.FindTable(tableName)
+	if err != nil {
+		return nil, err
+	}
+	if tabletType != topodatapb.TabletType_PRIMARY {
+		return nil, vterrors.VT09002("batch DML")
+	}
+	if err := validateBatchDMLTable(stmt, vschemaTable, vschema); err != nil {
+		return nil, err
+	}
+	if dest == nil {
+		dest = key.DestinationAllShards{}
+	}
+	if batchSize == 0 {
+		batchSize = schema.DefaultOnlineDMLBatchSize
+	}
+
+	// The statement runs on the tablets, where the keyspace name is meaningless.
+	stmt = sqlparser.CloneStatement(stmt)
+	sqlparser.RemoveKeyspace(stmt)
+
+	return newPlanResult(&engine.BatchDML{
+		Keyspace:          vschemaTable.Keyspace,
+		TargetDestination: dest,
+		DML:               stmt,
+		TableName:         vschemaTable.Name.String(),
+		BatchSize:         batchSize,
+	}, singleTable(vschemaTable.Keyspace.Name, vschemaTable.Name.String())), nil
+}
+
+func validateBatchDMLTable(stmt sqlparser.Statement, table *vindexes.Table, vschema plancontext.VSchema) error {
+	switch table.Type {
+	case vindexes.TypeReference:
+		return vterrors.VT12001("batch DML on reference table")
+	case vindexes.TypeSequence:
+		return vterrors.VT12001("batch DML on sequence table")
+	}
+	if len(table.Owned) > 0 {
+		return vterrors.VT12001("batch DML on a table with owned vindexes")
+	}
+	fkMode, err := vschema.ForeignKeyMode(table.Keyspace.Name)
+	if err != nil {
+		return err
+	}
+	if fkMode == vschemapb.Keyspace_managed && (len(table.ChildForeignKeys) > 0 || len(table.ParentForeignKeys) > 0) {
+		return vterrors.VT12001("batch DML on a table with foreign keys managed by Vitess")
+	}
+	if table.Keyspace.Sharded {
+		// The job runs on each shard independently, where a subquery would only see that shard's rows.
+		hasSubquery := false
+		_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+			if _, ok := node.(*sqlparser.Subquery); ok {
+				hasSubquery = true
+				return false, nil
+			}
+			return true, nil
+		}, stmt)
+		if hasSubquery {
+			return vterrors.VT12001("batch DML with a subquery on a sharded table")
+		}
+	}
+	upd, ok := stmt.(*sqlparser.Update)
+	if !ok {
+		return nil
+	}
+	for _, updateExpr := range upd.Exprs {
+		for _, colVindex := range table.ColumnVindexes {
+			for _, column := range colVindex.Columns {
+				if updateExpr.Name.Name.Equal(column) {
+					return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "batch DML cannot change vindex column: %s", column.String())
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -157,7 +157,20 @@ func buildRoutePlan(stmt sqlparser.Statement, reservedVars *sqlparser.ReservedVa
 
 func createInstructionFor(ctx context.Context, query string, stmt sqlparser.Statement, reservedVars *sqlparser.ReservedVars, vschema plancontext.VSchema, enableOnlineDDL, enableDirectDDL bool) (*planResult, error) {
 	switch stmt := stmt.(type) {
-	case *sqlparser.Select, *sqlparser.Insert, *sqlparser.Update, *sqlparser.Delete:
+	case *sqlparser.Update, *sqlparser.Delete:
+		batchSize, isBatchDML, err := sqlparser.GetBatchDMLFromStatement(stmt)
+		if err != nil {
+			return nil, err
+		}
+		if isBatchDML {
+			return buildBatchDMLPlan(stmt, batchSize, vschema)
+		}
+		configuredPlanner, err := getConfiguredPlanner(vschema, stmt, query)
+		if err != nil {
+			return nil, err
+		}
+		return buildRoutePlan(stmt, reservedVars, vschema, configuredPlanner)
+	case *sqlparser.Select, *sqlparser.Insert:
 		configuredPlanner, err := getConfiguredPlanner(vschema, stmt, query)
 		if err != nil {
 			return nil, err
@@ -641,10 +641,11 @@ func autoIncGenerate(gen *operators.Generate) *engine.Generate {
 		SelectExprs: sqlparser.SelectExprs{&sqlparser.Nextval{Expr: &sqlparser.Argument{Name: "n", Type: sqltypes.Int64}}},
 	}
 	return &engine.Generate{
-		Keyspace: gen.Keyspace,
-		Query:    sqlparser.String(selNext),
-		Values:   gen.Values,
-		Offset:   gen.Offset,
+		Keyspace:  gen.Keyspace,
+		Query:     sqlparser.String(selNext),
+		Values:    gen.Values,
+		Offset:    gen.Offset,
+		Allocator: gen.Allocator,
 	}
 }
 
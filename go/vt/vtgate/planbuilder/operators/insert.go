@@ -69,6 +69,8 @@ type Generate struct {
 	Values evalengine.Expr
 	// Insert using Select, offset for auto increment column
 	Offset int
+	// Allocator is set if the sequence is spread over the shards of the keyspace.
+	Allocator *vindexes.SequenceAllocator
 
 	// added indicates whether the auto-increment column was already present in the insert column list or added.
 	added bool
@@ -616,6 +618,7 @@ func modifyForAutoinc(ctx *plancontext.PlanningContext, ins *sqlparser.Insert, v
 	gen := &Generate{
 		Keyspace:  vTable.AutoIncrement.Sequence.Keyspace,
 		TableName: sqlparser.TableName{Name: vTable.AutoIncrement.Sequence.Name},
+		Allocator: vTable.AutoIncrement.Sequence.SequenceAllocator,
 	}
 	colNum, newColAdded := findOrAddColumn(ins, vTable.AutoIncrement.Column)
 	switch rows := ins.Rows.(type) {
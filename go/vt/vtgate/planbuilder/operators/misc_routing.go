@@ -52,7 +52,8 @@ type (
 	DualRouting struct{}
 
 	SequenceRouting struct {
-		keyspace *vindexes.Keyspace
+		keyspace  *vindexes.Keyspace
+		allocator *vindexes.SequenceAllocator
 	}
 )
 
@@ -181,10 +182,11 @@ func (dr *DualRouting) Keyspace() *vindexes.Keyspace {
 func (sr *SequenceRouting) UpdateRoutingParams(_ *plancontext.PlanningContext, rp *engine.RoutingParameters) {
 	rp.Opcode = engine.Next
 	rp.Keyspace = sr.keyspace
+	rp.SequenceAllocator = sr.allocator
 }
 
 func (sr *SequenceRouting) Clone() Routing {
-	return &SequenceRouting{keyspace: sr.keyspace}
+	return &SequenceRouting{keyspace: sr.keyspace, allocator: sr.allocator}
 }
 
 func (sr *SequenceRouting) updateRoutingLogic(*plancontext.PlanningContext, sqlparser.Expr) Routing {
@@ -497,7 +497,7 @@ func createRouteFromVSchemaTable(
 func createRoutingForVTable(ctx *plancontext.PlanningContext, vschemaTable *vindexes.Table, id semantics.TableSet) Routing {
 	switch {
 	case vschemaTable.Type == vindexes.TypeSequence:
-		return &SequenceRouting{keyspace: vschemaTable.Keyspace}
+		return &SequenceRouting{keyspace: vschemaTable.Keyspace, allocator: vschemaTable.SequenceAllocator}
 	case vschemaTable.Type == vindexes.TypeReference && vschemaTable.Name.String() == "dual":
 		return &DualRouting{}
 	case vschemaTable.Type == vindexes.TypeReference || !vschemaTable.Keyspace.Sharded:
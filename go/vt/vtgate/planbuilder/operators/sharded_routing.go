@@ -22,6 +22,7 @@ import (
 
 	"vitess.io/vitess/go/mysql/collations"
 	"vitess.io/vitess/go/slice"
+	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vtgate/engine"
@@ -230,6 +231,10 @@ func (tr *ShardedRouting) searchForNewVindexes(ctx *plancontext.PlanningContext,
 	case *sqlparser.IsExpr:
 		found := tr.planIsExpr(ctx, node)
 		newVindexFound = newVindexFound || found
+
+	case *sqlparser.BetweenExpr:
+		found := tr.planBetween(ctx, node)
+		newVindexFound = newVindexFound || found
 	}
 
 	return nil, newVindexFound
@@ -246,7 +251,9 @@ func (tr *ShardedRouting) planComparison(ctx *plancontext.PlanningContext, cmp *
 	case sqlparser.LikeOp:
 		found := tr.planLikeOp(ctx, cmp)
 		return nil, found
-
+	case sqlparser.LessThanOp, sqlparser.LessEqualOp, sqlparser.GreaterThanOp, sqlparser.GreaterEqualOp:
+		found := tr.planRangeOp(ctx, cmp)
+		return nil, found
 	}
 	return nil, false
 }
@@ -325,6 +332,135 @@ func (tr *ShardedRouting) planLikeOp(ctx *plancontext.PlanningContext, node *sql
 	return tr.haveMatchingVindex(ctx, node, vdValue, column, val, selectEqual, vdx)
 }
 
+// planRangeOp plans a comparison such as `col > 5` as a range with one open side.
+func (tr *ShardedRouting) planRangeOp(ctx *plancontext.PlanningContext, cmp *sqlparser.ComparisonExpr) bool {
+	column, ok := cmp.Left.(*sqlparser.ColName)
+	bound := cmp.Right
+	lowerBound := cmp.Operator == sqlparser.GreaterThanOp || cmp.Operator == sqlparser.GreaterEqualOp
+	if !ok {
+		column, ok = cmp.Right.(*sqlparser.ColName)
+		if !ok {
+			return false
+		}
+		// `5 < col` is the same as `col > 5`
+		bound = cmp.Left
+		lowerBound = !lowerBound
+	}
+
+	if lowerBound {
+		return tr.haveMatchingRange(ctx, cmp, column, bound, &sqlparser.NullVal{})
+	}
+	return tr.haveMatchingRange(ctx, cmp, column, &sqlparser.NullVal{}, bound)
+}
+
+func (tr *ShardedRouting) planBetween(ctx *plancontext.PlanningContext, node *sqlparser.BetweenExpr) bool {
+	if !node.IsBetween {
+		return false
+	}
+	column, ok := node.Left.(*sqlparser.ColName)
+	if !ok {
+		return false
+	}
+	return tr.haveMatchingRange(ctx, node, column, node.From, node.To)
+}
+
+// haveMatchingRange adds Range options for the Sequential vindexes on column,
+// for the inclusive range [from, to]. A NULL from or to leaves the range open on
+// that side. Since the routing only has to cover the rows that match, an
+// exclusive bound can be planned as an inclusive one.
+func (tr *ShardedRouting) haveMatchingRange(
+	ctx *plancontext.PlanningContext,
+	node sqlparser.Expr,
+	column *sqlparser.ColName,
+	from, to sqlparser.Expr,
+) bool {
+	fromValue := makeEvalEngineExpr(ctx, from)
+	toValue := makeEvalEngineExpr(ctx, to)
+	if fromValue == nil || toValue == nil {
+		return false
+	}
+
+	newVindexFound := false
+	for _, v := range tr.VindexPreds {
+		if !ctx.SemTable.DirectDeps(column).IsSolvedBy(v.TableID) {
+			continue
+		}
+		if _, ok := v.ColVindex.Vindex.(vindexes.Sequential); !ok || !column.Name.Equal(v.ColVindex.Columns[0]) {
+			continue
+		}
+		switch v.ColVindex.Vindex.(type) {
+		case *vindexes.Binary, *vindexes.CFC:
+			if !comparesBytes(ctx, column) {
+				// these vindexes order values by their bytes, which may not
+				// be how the column compares them
+				continue
+			}
+		}
+
+		option := &VindexOption{
+			Values:      []evalengine.Expr{fromValue, toValue},
+			ValueExprs:  []sqlparser.Expr{from, to},
+			Predicates:  []sqlparser.Expr{node},
+			OpCode:      engine.Range,
+			FoundVindex: v.ColVindex.Vindex,
+			Cost:        costFor(v.ColVindex, engine.Range),
+			Ready:       true,
+		}
+		v.Options = append(v.Options, option)
+		if isOpenBound(from) || isOpenBound(to) {
+			// the combined options come last, so they win the tie with the
+			// option for this predicate alone
+			v.Options = append(v.Options, combineRanges(option, v.Options)...)
+		}
+		newVindexFound = true
+	}
+	return newVindexFound
+}
+
+// combineRanges closes the open side of the range in option with the bounds of
+// the other Range options.
+func combineRanges(option *VindexOption, others []*VindexOption) []*VindexOption {
+	var combined []*VindexOption
+	for _, other := range others {
+		if other == option || other.OpCode != engine.Range {
+			continue
+		}
+		vo := copyOption(option)
+		vo.Ready = true
+		vo.Predicates = append(vo.Predicates, other.Predicates...)
+		for i, expr := range vo.ValueExprs {
+			if isOpenBound(expr) {
+				vo.Values[i] = other.Values[i]
+				vo.ValueExprs[i] = other.ValueExprs[i]
+			}
+		}
+		if !isOpenBound(vo.ValueExprs[0]) && !isOpenBound(vo.ValueExprs[1]) {
+			combined = append(combined, vo)
+		}
+	}
+	return combined
+}
+
+func isOpenBound(expr sqlparser.Expr) bool {
+	_, ok := expr.(*sqlparser.NullVal)
+	return ok
+}
+
+// comparesBytes returns true if the column is known to be compared byte by
+// byte, i.e. it is a binary string or a text with the binary collation.
+func comparesBytes(ctx *plancontext.PlanningContext, column *sqlparser.ColName) bool {
+	typ, found := ctx.SemTable.TypeForExpr(column)
+	switch {
+	case !found:
+		return false
+	case sqltypes.IsBinary(typ.Type()):
+		return true
+	case sqltypes.IsText(typ.Type()):
+		return typ.Collation() == collations.CollationBinaryID
+	}
+	return false
+}
+
 func (tr *ShardedRouting) Cost() int {
 	switch tr.RouteOpCode {
 	case engine.EqualUnique:
@@ -335,6 +471,8 @@ func (tr *ShardedRouting) Cost() int {
 		return 10
 	case engine.MultiEqual:
 		return 10
+	case engine.Range:
+		return 15
 	case engine.Scatter:
 		return 20
 	default:
@@ -661,7 +799,7 @@ func tryMergeJoinShardedRouting(
 		// can merge via join predicates instead.
 		fallthrough
 
-	case engine.Scatter, engine.IN, engine.None:
+	case engine.Scatter, engine.IN, engine.Range, engine.None:
 		if len(joinPredicates) == 0 {
 			// If we are doing two Scatters, we have to make sure that the
 			// joins are on the correct vindex to allow them to be merged
@@ -21,6 +21,7 @@ import (
 	"strconv"
 	"strings"
 
+	"vitess.io/vitess/go/mysql/collations"
 	"vitess.io/vitess/go/vt/sysvars"
 	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
 
@@ -177,6 +178,12 @@ func planSysVarCheckIgnore(expr *sqlparser.SetExpr, schema plancontext.VSchema,
 
 func buildSetOpReservedConn(s setting) planFunc {
 	return func(expr *sqlparser.SetExpr, vschema plancontext.VSchema, _ *expressionConverter) (engine.SetOp, error) {
+		if isConsistentSnapshotIsolation(expr) {
+			return &engine.SysVarSetAware{
+				Name: expr.Var.Name.Lowered(),
+				Expr: evalengine.NewLiteralString([]byte(sysvars.ConsistentSnapshot), collations.SystemCollation),
+			}, nil
+		}
 		if !vschema.SysVarSetEnabled() {
 			return planSysVarCheckIgnore(expr, vschema, s.boolean)
 		}
@@ -201,6 +208,22 @@ func buildSetOpReservedConn(s setting) planFunc {
 	}
 }
 
+// isConsistentSnapshotIsolation returns true when the expression sets the transaction isolation
+// to the vitess specific CONSISTENT_SNAPSHOT value, which is handled by vtgate and never sent to MySQL.
+func isConsistentSnapshotIsolation(expr *sqlparser.SetExpr) bool {
+	switch expr.Var.Name.Lowered() {
+	case sysvars.TransactionIsolation.Name, sysvars.TxIsolation.Name:
+	default:
+		return false
+	}
+	value, err := getValueFor(expr)
+	if err != nil {
+		return false
+	}
+	str, ok := value.(string)
+	return ok && strings.EqualFold(str, sysvars.ConsistentSnapshot)
+}
+
 func provideAppliedCase(value string, storageCase sysvars.StorageCase) string {
 	switch storageCase {
 	case sysvars.SCUpper:
@@ -98,6 +98,10 @@ func buildShowBasicPlan(show *sqlparser.ShowBasic, vschema plancontext.VSchema)
 		return buildSendAnywherePlan(show, vschema)
 	case sqlparser.VitessMigrations:
 		return buildShowVitessMigrationsPlan(show, vschema)
+	case sqlparser.VitessDMLJobs:
+		return buildShowVitessDMLJobsPlan(show, vschema)
+	case sqlparser.VitessSchemaHistory:
+		return buildShowVitessSchemaHistoryPlan(show, vschema)
 	case sqlparser.VGtidExecGlobal:
 		return buildShowVGtidPlan(show, vschema)
 	case sqlparser.GtidExecGlobal:
@@ -108,7 +112,7 @@ func buildShowBasicPlan(show *sqlparser.ShowBasic, vschema plancontext.VSchema)
 		return buildPluginsPlan()
 	case sqlparser.Engines:
 		return buildEnginesPlan()
-	case sqlparser.VitessReplicationStatus, sqlparser.VitessShards, sqlparser.VitessTablets, sqlparser.VitessVariables:
+	case sqlparser.VitessQueryDigests, sqlparser.VitessReplicationStatus, sqlparser.VitessShards, sqlparser.VitessTablets, sqlparser.VitessVariables:
 		return &engine.ShowExec{
 			Command:    show.Command,
 			ShowFilter: show.Filter,
@@ -271,6 +275,56 @@ func buildShowVitessMigrationsPlan(show *sqlparser.ShowBasic, vschema plancontex
 	}, nil
 }
 
+// buildShowVitessSchemaHistoryPlan serves `SHOW VITESS_SCHEMA_HISTORY ...` queries.
+// It sends down the SHOW command to the target tablets (on all shards)
+func buildShowVitessSchemaHistoryPlan(show *sqlparser.ShowBasic, vschema plancontext.VSchema) (engine.Primitive, error) {
+	dest, ks, _, err := vschema.TargetDestination(show.DbName.String())
+	if err != nil {
+		return nil, err
+	}
+	if ks == nil {
+		return nil, vterrors.VT09005()
+	}
+
+	if dest == nil {
+		dest = key.DestinationAllShards{}
+	}
+
+	return &engine.Send{
+		Keyspace:          ks,
+		TargetDestination: dest,
+		Query:             sqlparser.String(show),
+		IsDML:             false,
+	}, nil
+}
+
+// buildShowVitessDMLJobsPlan serves `SHOW VITESS_DML_JOBS ...` queries.
+// It sends down the SHOW command to the PRIMARY shard tablets (on all shards)
+func buildShowVitessDMLJobsPlan(show *sqlparser.ShowBasic, vschema plancontext.VSchema) (engine.Primitive, error) {
+	dest, ks, tabletType, err := vschema.TargetDestination(show.DbName.String())
+	if err != nil {
+		return nil, err
+	}
+	if ks == nil {
+		return nil, vterrors.VT09005()
+	}
+
+	if tabletType != topodatapb.TabletType_PRIMARY {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "SHOW VITESS_DML_JOBS works only on primary tablet")
+	}
+
+	if dest == nil {
+		dest = key.DestinationAllShards{}
+	}
+
+	return &engine.Send{
+		Keyspace:          ks,
+		TargetDestination: dest,
+		Query:             sqlparser.String(show),
+		IsDML:             false,
+	}, nil
+}
+
 func buildPlanWithDB(show *sqlparser.ShowBasic, vschema plancontext.VSchema) (engine.Primitive, error) {
 	dbName := show.DbName
 	dbDestination := show.DbName.String()
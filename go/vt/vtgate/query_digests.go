@@ -0,0 +1,323 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/vt/vtgate/engine"
+)
+
+// queryDigestLatencyCutoffs are the upper bounds of the latency histogram buckets of a query
+// digest. They grow by 25% from 100µs up to a couple of minutes, which bounds the error of the
+// interpolated percentiles.
+var queryDigestLatencyCutoffs = func() []time.Duration {
+	var cutoffs []time.Duration
+	for cutoff := 100 * time.Microsecond; cutoff < 3*time.Minute; cutoff = cutoff * 5 / 4 {
+		cutoffs = append(cutoffs, cutoff)
+	}
+	return cutoffs
+}()
+
+// QueryDigestStats are the execution statistics of a query digest, i.e. of all the executions of
+// a normalized query in a keyspace.
+type QueryDigestStats struct {
+	// Digest is the fingerprint of the normalized query
+	Digest   string
+	Keyspace string
+	Query    string
+	StmtType string
+
+	Count     uint64
+	Errors    uint64
+	TotalTime time.Duration
+	MaxTime   time.Duration
+	P50       time.Duration
+	P95       time.Duration
+	P99       time.Duration
+
+	// ShardQueries is the total number of queries sent to shards
+	ShardQueries uint64
+	// RowsExamined is the total number of rows that the shards returned to vtgate, before vtgate
+	// filtered, aggregated or joined them
+	RowsExamined uint64
+	RowsAffected uint64
+	RowsReturned uint64
+
+	FirstSeen time.Time
+	LastSeen  time.Time
+}
+
+type queryDigestKey struct {
+	keyspace string
+	query    string
+}
+
+// queryDigest accumulates the statistics of a query digest. It is updated without locking.
+type queryDigest struct {
+	digest    string
+	keyspace  string
+	query     string
+	stmtType  string
+	firstSeen time.Time
+
+	count        atomic.Uint64
+	errors       atomic.Uint64
+	totalTime    atomic.Uint64
+	maxTime      atomic.Uint64
+	shardQueries atomic.Uint64
+	rowsExamined atomic.Uint64
+	rowsAffected atomic.Uint64
+	rowsReturned atomic.Uint64
+	lastSeen     atomic.Int64
+	// buckets counts the executions by latency, the last bucket holding the ones that took longer
+	// than the last cutoff
+	buckets []atomic.Uint64
+}
+
+// queryDigests is a bounded table of query digests, keyed by normalized query and keyspace. It
+// keeps the digests with the highest total execution time: once the table holds twice its size,
+// the digests beyond its size are evicted.
+type queryDigests struct {
+	size int
+
+	mu      sync.RWMutex
+	digests map[queryDigestKey]*queryDigest
+}
+
+// newQueryDigests creates a digest table of the given size. A size of zero disables it.
+func newQueryDigests(size int) *queryDigests {
+	if size <= 0 {
+		return nil
+	}
+	return &queryDigests{
+		size:    size,
+		digests: make(map[queryDigestKey]*queryDigest),
+	}
+}
+
+// record adds an execution of the plan to its digest. The digest is keyed by the query of the plan,
+// which is normalized unless query normalization is disabled.
+func (qd *queryDigests) record(plan *engine.Plan, elapsed time.Duration, shardQueries, rowsExamined, rowsAffected, rowsReturned uint64, err error) {
+	if qd == nil || plan == nil || plan.Instructions == nil {
+		return
+	}
+	key := queryDigestKey{keyspace: plan.Instructions.GetKeyspaceName(), query: plan.Original}
+
+	qd.mu.RLock()
+	d, ok := qd.digests[key]
+	qd.mu.RUnlock()
+	if !ok {
+		d = qd.add(key, plan)
+	}
+
+	d.count.Add(1)
+	if err != nil {
+		d.errors.Add(1)
+	}
+	d.totalTime.Add(uint64(elapsed))
+	for {
+		maxTime := d.maxTime.Load()
+		if uint64(elapsed) <= maxTime || d.maxTime.CompareAndSwap(maxTime, uint64(elapsed)) {
+			break
+		}
+	}
+	d.shardQueries.Add(shardQueries)
+	d.rowsExamined.Add(rowsExamined)
+	d.rowsAffected.Add(rowsAffected)
+	d.rowsReturned.Add(rowsReturned)
+	d.lastSeen.Store(time.Now().UnixNano())
+	bucket := sort.Search(len(queryDigestLatencyCutoffs), func(i int) bool {
+		return elapsed <= queryDigestLatencyCutoffs[i]
+	})
+	d.buckets[bucket].Add(1)
+}
+
+func (qd *queryDigests) add(key queryDigestKey, plan *engine.Plan) *queryDigest {
+	qd.mu.Lock()
+	defer qd.mu.Unlock()
+
+	if d, ok := qd.digests[key]; ok {
+		return d
+	}
+	hash := sha256.Sum256([]byte(key.query))
+	d := &queryDigest{
+		digest:    hex.EncodeToString(hash[:]),
+		keyspace:  key.keyspace,
+		query:     key.query,
+		stmtType:  plan.Type.String(),
+		firstSeen: time.Now(),
+		buckets:   make([]atomic.Uint64, len(queryDigestLatencyCutoffs)+1),
+	}
+	qd.digests[key] = d
+	if len(qd.digests) > 2*qd.size {
+		qd.evictLocked()
+	}
+	return d
+}
+
+// evictLocked evicts the digests with the lowest total execution time, down to the size of the
+// table. Digests that are evicted lose their statistics.
+func (qd *queryDigests) evictLocked() {
+	digests := make([]*queryDigest, 0, len(qd.digests))
+	for _, d := range qd.digests {
+		digests = append(digests, d)
+	}
+	sort.Slice(digests, func(i, j int) bool {
+		return digests[i].totalTime.Load() > digests[j].totalTime.Load()
+	})
+	for _, d := range digests[qd.size:] {
+		delete(qd.digests, queryDigestKey{keyspace: d.keyspace, query: d.query})
+	}
+}
+
+// top returns the statistics of the digests with the highest total execution time, at most limit
+// of them. A limit of zero returns at most as many digests as the size of the table.
+func (qd *queryDigests) top(limit int) []*QueryDigestStats {
+	if qd == nil {
+		return nil
+	}
+	qd.mu.RLock()
+	result := make([]*QueryDigestStats, 0, len(qd.digests))
+	for _, d := range qd.digests {
+		result = append(result, d.stats())
+	}
+	qd.mu.RUnlock()
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].TotalTime != result[j].TotalTime {
+			return result[i].TotalTime > result[j].TotalTime
+		}
+		return result[i].Digest < result[j].Digest
+	})
+	if limit <= 0 {
+		limit = qd.size
+	}
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
+
+func (d *queryDigest) stats() *QueryDigestStats {
+	buckets := make([]uint64, len(d.buckets))
+	for i := range d.buckets {
+		buckets[i] = d.buckets[i].Load()
+	}
+	maxTime := time.Duration(d.maxTime.Load())
+	return &QueryDigestStats{
+		Digest:       d.digest,
+		Keyspace:     d.keyspace,
+		Query:        d.query,
+		StmtType:     d.stmtType,
+		Count:        d.count.Load(),
+		Errors:       d.errors.Load(),
+		TotalTime:    time.Duration(d.totalTime.Load()),
+		MaxTime:      maxTime,
+		P50:          latencyPercentile(buckets, maxTime, 0.50),
+		P95:          latencyPercentile(buckets, maxTime, 0.95),
+		P99:          latencyPercentile(buckets, maxTime, 0.99),
+		ShardQueries: d.shardQueries.Load(),
+		RowsExamined: d.rowsExamined.Load(),
+		RowsAffected: d.rowsAffected.Load(),
+		RowsReturned: d.rowsReturned.Load(),
+		FirstSeen:    d.firstSeen,
+		LastSeen:     time.Unix(0, d.lastSeen.Load()),
+	}
+}
+
+// latencyPercentile estimates a latency percentile from histogram buckets, interpolating linearly
+// within the bucket that holds the percentile. The estimate never exceeds the maximum latency.
+func latencyPercentile(buckets []uint64, maxTime time.Duration, percentile float64) time.Duration {
+	var total uint64
+	for _, count := range buckets {
+		total += count
+	}
+	if total == 0 {
+		return 0
+	}
+	rank := percentile * float64(total)
+	var cumulative uint64
+	for i, count := range buckets {
+		if count == 0 || float64(cumulative+count) < rank {
+			cumulative += count
+			continue
+		}
+		var lower time.Duration
+		if i > 0 {
+			lower = queryDigestLatencyCutoffs[i-1]
+		}
+		upper := maxTime
+		if i < len(queryDigestLatencyCutoffs) && queryDigestLatencyCutoffs[i] < upper {
+			upper = queryDigestLatencyCutoffs[i]
+		}
+		if upper <= lower {
+			return upper
+		}
+		fraction := (rank - float64(cumulative)) / float64(count)
+		return lower + time.Duration(fraction*float64(upper-lower))
+	}
+	return maxTime
+}
+
+// publishQueryDigests exports the statistics of the top k query digests as metrics, labeled by
+// digest and keyspace. The normalized queries are not exported: they can be looked up by digest
+// with SHOW VITESS_QUERY_DIGESTS.
+func publishQueryDigests(qd *queryDigests, k int) {
+	labels := []string{"Digest", "Keyspace"}
+	values := func(value func(qds *QueryDigestStats) int64) func() map[string]int64 {
+		return func() map[string]int64 {
+			result := make(map[string]int64)
+			for _, qds := range qd.top(k) {
+				result[qds.Digest+"."+strings.ReplaceAll(qds.Keyspace, ".", "_")] = value(qds)
+			}
+			return result
+		}
+	}
+	stats.NewCountersFuncWithMultiLabels("QueryDigestCount", "Query digest execution count", labels, values(func(qds *QueryDigestStats) int64 {
+		return int64(qds.Count)
+	}))
+	stats.NewCountersFuncWithMultiLabels("QueryDigestErrors", "Query digest execution errors", labels, values(func(qds *QueryDigestStats) int64 {
+		return int64(qds.Errors)
+	}))
+	stats.NewCountersFuncWithMultiLabels("QueryDigestTotalTimeNs", "Query digest total execution time in nanoseconds", labels, values(func(qds *QueryDigestStats) int64 {
+		return int64(qds.TotalTime)
+	}))
+	stats.NewCountersFuncWithMultiLabels("QueryDigestRowsExamined", "Query digest rows returned by the shards", labels, values(func(qds *QueryDigestStats) int64 {
+		return int64(qds.RowsExamined)
+	}))
+	stats.NewCountersFuncWithMultiLabels("QueryDigestRowsReturned", "Query digest rows returned", labels, values(func(qds *QueryDigestStats) int64 {
+		return int64(qds.RowsReturned)
+	}))
+	stats.NewGaugesFuncWithMultiLabels("QueryDigestLatencyNs", "Query digest execution time percentiles in nanoseconds", []string{"Digest", "Keyspace", "Percentile"}, func() map[string]int64 {
+		result := make(map[string]int64)
+		for _, qds := range qd.top(k) {
+			key := qds.Digest + "." + strings.ReplaceAll(qds.Keyspace, ".", "_")
+			result[key+".p50"] = int64(qds.P50)
+			result[key+".p95"] = int64(qds.P95)
+			result[key+".p99"] = int64(qds.P99)
+		}
+		return result
+	})
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtgate/engine"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+)
+
+func testDigestPlan(keyspace, query string) *engine.Plan {
+	return &engine.Plan{
+		Type:         sqlparser.StmtSelect,
+		Original:     query,
+		Instructions: &engine.Send{Keyspace: &vindexes.Keyspace{Name: keyspace}},
+	}
+}
+
+func TestQueryDigestsRecord(t *testing.T) {
+	qd := newQueryDigests(10)
+	plan := testDigestPlan("ks", "select * from t where id = :id")
+	for i := 1; i <= 100; i++ {
+		var err error
+		if i%10 == 0 {
+			err = errors.New("failed")
+		}
+		qd.record(plan, time.Duration(i)*time.Millisecond, 2, 10, 0, 1, err)
+	}
+	// The same query in another keyspace is another digest
+	qd.record(testDigestPlan("other", "select * from t where id = :id"), time.Millisecond, 1, 1, 0, 1, nil)
+
+	top := qd.top(0)
+	require.Len(t, top, 2)
+	qds := top[0]
+	assert.Equal(t, "ks", qds.Keyspace)
+	assert.Equal(t, "select * from t where id = :id", qds.Query)
+	assert.Equal(t, "SELECT", qds.StmtType)
+	assert.Len(t, qds.Digest, 64)
+	assert.Equal(t, top[1].Digest, qds.Digest)
+	assert.EqualValues(t, 100, qds.Count)
+	assert.EqualValues(t, 10, qds.Errors)
+	assert.Equal(t, 5050*time.Millisecond, qds.TotalTime)
+	assert.Equal(t, 100*time.Millisecond, qds.MaxTime)
+	assert.EqualValues(t, 200, qds.ShardQueries)
+	assert.EqualValues(t, 1000, qds.RowsExamined)
+	assert.EqualValues(t, 100, qds.RowsReturned)
+	// Percentiles are estimates, within the 25% width of a histogram bucket
+	assert.InEpsilon(t, float64(50*time.Millisecond), float64(qds.P50), 0.25)
+	assert.InEpsilon(t, float64(95*time.Millisecond), float64(qds.P95), 0.25)
+	assert.InEpsilon(t, float64(99*time.Millisecond), float64(qds.P99), 0.25)
+	assert.LessOrEqual(t, qds.P99, qds.MaxTime)
+	assert.False(t, qds.LastSeen.Before(qds.FirstSeen))
+
+	assert.Len(t, qd.top(1), 1)
+}
+
+func TestQueryDigestsEviction(t *testing.T) {
+	qd := newQueryDigests(2)
+	for i := 1; i <= 5; i++ {
+		qd.record(testDigestPlan("ks", fmt.Sprintf("select %d", i)), time.Duration(i)*time.Second, 1, 0, 0, 0, nil)
+	}
+	// The table was trimmed down to its size when the fifth digest was added
+	var queries []string
+	for _, qds := range qd.top(0) {
+		queries = append(queries, qds.Query)
+	}
+	assert.Equal(t, []string{"select 4", "select 3"}, queries)
+}
+
+func TestQueryDigestsDisabled(t *testing.T) {
+	qd := newQueryDigests(0)
+	assert.Nil(t, qd)
+	qd.record(testDigestPlan("ks", "select 1"), time.Second, 1, 0, 0, 0, nil)
+	assert.Empty(t, qd.top(0))
+}
+
+func TestLatencyPercentile(t *testing.T) {
+	buckets := make([]uint64, len(queryDigestLatencyCutoffs)+1)
+	assert.Zero(t, latencyPercentile(buckets, 0, 0.5))
+
+	// All executions in the first bucket, which is interpolated up to the max time
+	buckets[0] = 10
+	assert.Equal(t, 25*time.Microsecond, latencyPercentile(buckets, 50*time.Microsecond, 0.5))
+
+	// Executions that took longer than the last cutoff are bounded by the max time
+	buckets[len(buckets)-1] = 90
+	p99 := latencyPercentile(buckets, time.Hour, 0.99)
+	assert.Greater(t, p99, queryDigestLatencyCutoffs[len(queryDigestLatencyCutoffs)-1])
+	assert.LessOrEqual(t, p99, time.Hour)
+}
+
+func TestExecutorQueryDigests(t *testing.T) {
+	executor, _, _, _, ctx := createExecutorEnv(t)
+	executor.normalize = true
+	session := &vtgatepb.Session{TargetString: "@primary"}
+
+	for _, sql := range []string{
+		"select id from user where id = 1",
+		"select id from user where id = 2",
+		"select id from user",
+		"select id from user where id = 3",
+	} {
+		_, err := executorExec(ctx, executor, session, sql, nil)
+		require.NoError(t, err)
+	}
+
+	qr, err := executorExec(ctx, executor, session, "show vitess_query_digests", nil)
+	require.NoError(t, err)
+	require.Len(t, qr.Fields, 17)
+	byQuery := map[string][]string{}
+	for _, row := range qr.Rows {
+		var values []string
+		for _, value := range row {
+			values = append(values, value.ToString())
+		}
+		byQuery[values[2]] = values
+	}
+
+	// Queries are normalized, so executions with different values share a digest
+	digest := byQuery["select id from `user` where id = :id /* INT64 */"]
+	require.NotNil(t, digest, "%v", byQuery)
+	assert.Equal(t, KsTestSharded, digest[1])
+	assert.Equal(t, "SELECT", digest[3])
+	assert.Equal(t, "3", digest[4])  // Count
+	assert.Equal(t, "0", digest[5])  // Errors
+	assert.Equal(t, "3", digest[11]) // ShardQueries
+	assert.Equal(t, "3", digest[12]) // RowsExamined
+	assert.Equal(t, "3", digest[14]) // RowsReturned
+
+	scatter := byQuery["select id from `user`"]
+	require.NotNil(t, scatter, "%v", byQuery)
+	assert.Equal(t, "1", scatter[4])
+	assert.Equal(t, "8", scatter[11])
+	assert.Equal(t, "8", scatter[12])
+
+	qr, err = executorExec(ctx, executor, session, "show vitess_query_digests like '%where%'", nil)
+	require.NoError(t, err)
+	require.Len(t, qr.Rows, 1)
+	assert.Equal(t, digest[0], qr.Rows[0][0].ToString())
+
+	resp := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", pathQueryDigests, nil)
+	executor.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusOK, resp.Code)
+	var stats []*QueryDigestStats
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &stats))
+	assert.NotEmpty(t, stats)
+}
@@ -390,7 +390,11 @@ func addOrUpdate(shardSession *vtgatepb.Session_ShardSession, sessions []*vtgate
 					sess.Target.Keyspace, sess.Target.TabletType, sess.Target.Shard)
 				return nil, vterrors.New(vtrpcpb.Code_FAILED_PRECONDITION, errorDetails)
 			}
-			// replace the old info with the new one
+			// replace the old info with the new one, keeping the position of
+			// the consistent snapshot the transaction was started from.
+			if shardSession.GtidExecuted == "" && shardSession.TransactionId == sess.TransactionId {
+				shardSession.GtidExecuted = sess.GtidExecuted
+			}
 			sessions[i] = shardSession
 			appendSession = false
 			break
@@ -751,6 +755,39 @@ func (session *SafeSession) GetMigrationContext() string {
 	return session.MigrationContext
 }
 
+// SetConsistentSnapshot sets the consistent_snapshot setting.
+func (session *SafeSession) SetConsistentSnapshot(consistentSnapshot bool) {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	session.ConsistentSnapshot = consistentSnapshot
+}
+
+// GetConsistentSnapshot returns the consistent_snapshot value.
+func (session *SafeSession) GetConsistentSnapshot() bool {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	return session.ConsistentSnapshot
+}
+
+// GetConsistentSnapshotGTIDs returns the GTID set of each shard, keyed by
+// keyspace/shard, at which the current transaction was started from a
+// cross-shard consistent snapshot.
+func (session *SafeSession) GetConsistentSnapshotGTIDs() map[string]string {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	var gtids map[string]string
+	for _, shardSession := range session.ShardSessions {
+		if shardSession.GtidExecuted == "" {
+			continue
+		}
+		if gtids == nil {
+			gtids = make(map[string]string)
+		}
+		gtids[shardSession.Target.Keyspace+"/"+shardSession.Target.Shard] = shardSession.GtidExecuted
+	}
+	return gtids
+}
+
 // GetSessionUUID returns the SessionUUID value.
 func (session *SafeSession) GetSessionUUID() string {
 	session.mu.Lock()
@@ -51,6 +51,39 @@ func TestFailToMultiShardWhenSetToSingleDb(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestAppendOrUpdateKeepsConsistentSnapshotGTID(t *testing.T) {
+	session := NewSafeSession(&vtgatepb.Session{InTransaction: true})
+
+	target := &querypb.Target{Keyspace: "keyspace", Shard: "0"}
+	alias := &topodatapb.TabletAlias{Cell: "cell", Uid: 0}
+	err := session.AppendOrUpdate(&vtgatepb.Session_ShardSession{
+		Target:        target,
+		TabletAlias:   alias,
+		TransactionId: 1,
+		GtidExecuted:  "uuid:1-5",
+	}, vtgatepb.TransactionMode_MULTI)
+	require.NoError(t, err)
+
+	// Reserving a connection for the transaction keeps the GTID.
+	err = session.AppendOrUpdate(&vtgatepb.Session_ShardSession{
+		Target:        target,
+		TabletAlias:   alias,
+		TransactionId: 1,
+		ReservedId:    1,
+	}, vtgatepb.TransactionMode_MULTI)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"keyspace/0": "uuid:1-5"}, session.GetConsistentSnapshotGTIDs())
+
+	// Another transaction does not.
+	err = session.AppendOrUpdate(&vtgatepb.Session_ShardSession{
+		Target:        target,
+		TabletAlias:   alias,
+		TransactionId: 2,
+	}, vtgatepb.TransactionMode_MULTI)
+	require.NoError(t, err)
+	assert.Empty(t, session.GetConsistentSnapshotGTIDs())
+}
+
 func TestPrequeries(t *testing.T) {
 	session := NewSafeSession(&vtgatepb.Session{
 		SystemVariables: map[string]string{
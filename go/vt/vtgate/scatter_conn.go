@@ -51,6 +51,8 @@ type ScatterConn struct {
 	tabletCallErrorCount *stats.CountersWithMultiLabels
 	txConn               *TxConn
 	gateway              *TabletGateway
+	// consolidator is nil when consolidation is disabled
+	consolidator *queryConsolidator
 }
 
 // shardActionFunc defines the contract for a shard action
@@ -86,8 +88,9 @@ func NewScatterConn(statsName string, txConn *TxConn, gw *TabletGateway) *Scatte
 			tabletCallErrorCountStatsName,
 			"Error count from tablet calls in scatter conns",
 			[]string{"Operation", "Keyspace", "ShardName", "DbType"}),
-		txConn:  txConn,
-		gateway: gw,
+		txConn:       txConn,
+		gateway:      gw,
+		consolidator: newQueryConsolidator(consolidatorMode, consolidatorQueryWaiterCap, consolidatorQuerySize),
 	}
 }
 
@@ -207,7 +210,13 @@ func (stc *ScatterConn) ExecuteMultiShard(
 
 			switch info.actionNeeded {
 			case nothing:
-				innerqr, err = qs.Execute(ctx, rs.Target, queries[i].Sql, queries[i].BindVariables, info.transactionID, info.reservedID, opts)
+				if stc.shouldConsolidate(primitive, rs.Target, queries[i].Sql, info, opts) {
+					innerqr, err = stc.consolidator.execute(ctx, primitive.RouteType(), rs.Target, queries[i], opts, func() (*sqltypes.Result, error) {
+						return qs.Execute(ctx, rs.Target, queries[i].Sql, queries[i].BindVariables, 0, 0, opts)
+					})
+				} else {
+					innerqr, err = qs.Execute(ctx, rs.Target, queries[i].Sql, queries[i].BindVariables, info.transactionID, info.reservedID, opts)
+				}
 				if err != nil {
 					retryRequest(func() {
 						// we seem to have lost our connection. it was a reserved connection, let's try to recreate it
@@ -273,6 +282,28 @@ func (stc *ScatterConn) ExecuteMultiShard(
 	return qr, allErrors.GetErrors()
 }
 
+// shouldConsolidate returns whether the query of a route primitive should be consolidated with
+// identical concurrent queries. Only read-only selects outside of transactions and reserved
+// connections are: sequence and DBA routes, and locking reads, always execute on their own.
+func (stc *ScatterConn) shouldConsolidate(primitive engine.Primitive, target *querypb.Target, sql string, info *shardActionInfo, opts *querypb.ExecuteOptions) bool {
+	if stc.consolidator == nil || info.transactionID != 0 || info.reservedID != 0 {
+		return false
+	}
+	route, isRoute := primitive.(*engine.Route)
+	if !isRoute || route.RoutingParameters == nil {
+		return false
+	}
+	switch route.Opcode {
+	case engine.Next, engine.DBA:
+		return false
+	}
+	sql, _ = sqlparser.SplitMarginComments(sql)
+	if sqlparser.Preview(sql) != sqlparser.StmtSelect || isLockingRead(sql) {
+		return false
+	}
+	return stc.consolidator.shouldConsolidate(target.TabletType, opts)
+}
+
 func (stc *ScatterConn) runLockQuery(ctx context.Context, session *SafeSession) {
 	rs := &srvtopo.ResolvedShard{Target: session.LockSession.Target, Gateway: stc.gateway}
 	query := &querypb.BoundQuery{Sql: "select 1", BindVariables: nil}
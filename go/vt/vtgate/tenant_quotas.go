@@ -0,0 +1,351 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"google.golang.org/protobuf/proto"
+
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/vt/callerid"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// The limits of a tenant quota, as reported in the stats and errors.
+const (
+	tenantLimitQPS               = "QPS"
+	tenantLimitConcurrentQueries = "ConcurrentQueries"
+	tenantLimitShards            = "Shards"
+	tenantLimitRowsPerMinute     = "RowsPerMinute"
+)
+
+var (
+	tenantQueries           = stats.NewCountersWithSingleLabel("TenantQueries", "Queries executed at vtgate by the tenants that have a resource quota", "Tenant")
+	tenantRowsExamined      = stats.NewCountersWithSingleLabel("TenantRowsExamined", "Rows read from the shards by the tenants that have a resource quota", "Tenant")
+	tenantConcurrentQueries = stats.NewGaugesWithSingleLabel("TenantConcurrentQueries", "Queries currently executing at vtgate for the tenants that have a resource quota", "Tenant")
+	// tenantQuotaExceeded also counts the queries that exceed a quota in dry-run mode, which
+	// tenantQuotaRejected does not.
+	tenantQuotaExceeded = stats.NewCountersWithMultiLabels("TenantQuotaExceeded", "Queries at vtgate that exceeded a limit of the resource quota of their tenant", []string{"Tenant", "Limit"})
+	tenantQuotaRejected = stats.NewCountersWithMultiLabels("TenantQuotaRejected", "Queries at vtgate rejected because they exceeded a limit of the resource quota of their tenant", []string{"Tenant", "Limit"})
+)
+
+// tenantEvictionInterval is how often the state of the idle tenants is evicted.
+const tenantEvictionInterval = time.Minute
+
+// tenantQuotas enforces the per-tenant resource quotas of the SrvVSchema, so that a single tenant
+// cannot saturate the cluster. The query rate and the rows read from the shards are limited with
+// token buckets: rows are charged once a query completes, and the queries of a tenant are rejected
+// while it is in deficit. The tenants without a quota of their own each get a copy of the default
+// quota.
+type tenantQuotas struct {
+	// mu protects the policy, the tenants map and lastEviction. The state of each tenant is
+	// protected by its own mutex, which may be locked while holding mu, but not the other way round.
+	mu        sync.RWMutex
+	tenantKey string
+	dryRun    bool
+	quotas    map[string]*vschemapb.TenantQuota
+	// tenants holds the state of the tenants that sent queries recently, by tenant.
+	tenants      map[string]*tenantState
+	lastEviction time.Time
+	now          func() time.Time
+}
+
+type tenantState struct {
+	mu    sync.Mutex
+	name  string
+	quota *vschemapb.TenantQuota
+	// queries and rows are the token buckets of the query rate and of the rows read per minute,
+	// or nil if the quota does not limit them.
+	queries    *rate.Limiter
+	rows       *rate.Limiter
+	concurrent int64
+	// evicted is set once the state is removed from the tenants. The queries that are admitted
+	// after that use a new state.
+	evicted bool
+}
+
+func newTenantQuotas() *tenantQuotas {
+	return &tenantQuotas{
+		tenants: make(map[string]*tenantState),
+		now:     time.Now,
+	}
+}
+
+// update applies the tenant quotas of a new SrvVSchema. The tenants keep their count of executing
+// queries, and the state of their token buckets as long as their quota does not change.
+func (tq *tenantQuotas) update(policy *vschemapb.TenantQuotas) {
+	if tq == nil {
+		return
+	}
+	if err := vindexes.ValidateTenantQuotas(policy); err != nil {
+		log.Errorf("Ignoring invalid tenant quotas: %v", err)
+		return
+	}
+
+	quotas := make(map[string]*vschemapb.TenantQuota, len(policy.GetQuotas()))
+	for _, quota := range policy.GetQuotas() {
+		quotas[quota.Tenant] = quota
+	}
+
+	tq.mu.Lock()
+	defer tq.mu.Unlock()
+
+	tq.tenantKey = policy.GetTenantKey()
+	tq.dryRun = policy.GetDryRun()
+	tq.quotas = quotas
+	for name, state := range tq.tenants {
+		state.mu.Lock()
+		switch quota := tq.quotaOf(name); {
+		case quota == nil:
+			state.evicted = true
+			delete(tq.tenants, name)
+		case !proto.Equal(quota, state.quota):
+			state.setQuota(quota)
+		}
+		state.mu.Unlock()
+	}
+}
+
+func newTenantState(name string, quota *vschemapb.TenantQuota) *tenantState {
+	state := &tenantState{name: name}
+	state.setQuota(quota)
+	return state
+}
+
+// setQuota applies the quota to the state, which keeps its count of executing queries. Its token
+// buckets start full.
+func (state *tenantState) setQuota(quota *vschemapb.TenantQuota) {
+	state.quota = quota
+	state.queries = nil
+	if quota.Qps > 0 {
+		burst := quota.Burst
+		if burst == 0 {
+			burst = int64(math.Ceil(quota.Qps))
+		}
+		state.queries = rate.NewLimiter(rate.Limit(quota.Qps), int(burst))
+	}
+	state.rows = nil
+	if quota.RowsPerMinute > 0 {
+		state.rows = rate.NewLimiter(rate.Limit(float64(quota.RowsPerMinute)/60), int(quota.RowsPerMinute))
+	}
+}
+
+// idle returns whether the tenant has no executing queries and full token buckets, in which case
+// its state can be evicted, since a new one would be identical.
+func (state *tenantState) idle(now time.Time) bool {
+	full := func(bucket *rate.Limiter) bool {
+		return bucket == nil || bucket.TokensAt(now) >= float64(bucket.Burst())
+	}
+	return state.concurrent == 0 && full(state.queries) && full(state.rows)
+}
+
+// tenantOf returns the tenant of the query, as identified by the tenant key.
+func tenantOf(ctx context.Context, stmt sqlparser.Statement, tenantKey string) string {
+	switch tenantKey {
+	case vindexes.TenantKeyPrincipal:
+		return callerid.GetPrincipal(callerid.EffectiveCallerIDFromContext(ctx))
+	case vindexes.TenantKeyComponent:
+		return callerid.GetComponent(callerid.EffectiveCallerIDFromContext(ctx))
+	case vindexes.TenantKeySubcomponent:
+		return callerid.GetSubcomponent(callerid.EffectiveCallerIDFromContext(ctx))
+	case vindexes.TenantKeyTag:
+		return sqlparser.GetTenantFromStatement(stmt)
+	default:
+		return callerid.GetUsername(callerid.ImmediateCallerIDFromContext(ctx))
+	}
+}
+
+// stateOf returns the state of the tenant of the query, creating it if needed, along with the
+// dry-run mode of the policy. The state is nil if the tenant has no quota.
+func (tq *tenantQuotas) stateOf(ctx context.Context, stmt sqlparser.Statement) (state *tenantState, dryRun bool) {
+	tq.mu.RLock()
+	tenant := tenantOf(ctx, stmt, tq.tenantKey)
+	quota := tq.quotaOf(tenant)
+	state, dryRun = tq.tenants[tenant], tq.dryRun
+	tq.mu.RUnlock()
+	if quota == nil || state != nil {
+		return state, dryRun
+	}
+
+	tq.mu.Lock()
+	defer tq.mu.Unlock()
+
+	// The policy may have changed in the meantime.
+	tenant = tenantOf(ctx, stmt, tq.tenantKey)
+	quota = tq.quotaOf(tenant)
+	if quota == nil {
+		return nil, tq.dryRun
+	}
+	state, ok := tq.tenants[tenant]
+	if !ok {
+		state = newTenantState(tenant, quota)
+		tq.tenants[tenant] = state
+	}
+	return state, tq.dryRun
+}
+
+// quotaOf returns the quota of the tenant: its own quota, or else the default quota. It is nil if
+// the tenant has no quota. The caller must hold tq.mu.
+func (tq *tenantQuotas) quotaOf(tenant string) *vschemapb.TenantQuota {
+	if quota, ok := tq.quotas[tenant]; ok {
+		return quota
+	}
+	return tq.quotas[vindexes.DefaultTenantQuota]
+}
+
+// evictIdle removes the state of the idle tenants, at most once per tenantEvictionInterval, so
+// that the tenants that stopped sending queries are not kept forever.
+func (tq *tenantQuotas) evictIdle(now time.Time) {
+	tq.mu.RLock()
+	due := now.Sub(tq.lastEviction) >= tenantEvictionInterval
+	tq.mu.RUnlock()
+	if !due {
+		return
+	}
+
+	tq.mu.Lock()
+	defer tq.mu.Unlock()
+
+	if now.Sub(tq.lastEviction) < tenantEvictionInterval {
+		return
+	}
+	tq.lastEviction = now
+	for name, state := range tq.tenants {
+		state.mu.Lock()
+		if state.idle(now) {
+			state.evicted = true
+			delete(tq.tenants, name)
+		}
+		state.mu.Unlock()
+	}
+}
+
+// admit admits the query for execution, or returns an error if its tenant exceeded its quota. The
+// returned tenantAdmission, which is nil if the tenant has no quota, must be released with done
+// once the query completes.
+func (tq *tenantQuotas) admit(ctx context.Context, stmt sqlparser.Statement) (*tenantAdmission, error) {
+	if tq == nil {
+		return nil, nil
+	}
+	now := tq.now()
+	tq.evictIdle(now)
+
+	var (
+		state  *tenantState
+		dryRun bool
+	)
+	for {
+		state, dryRun = tq.stateOf(ctx, stmt)
+		if state == nil {
+			return nil, nil
+		}
+		state.mu.Lock()
+		if !state.evicted {
+			break
+		}
+		// The state was evicted after it was looked up.
+		state.mu.Unlock()
+	}
+	defer state.mu.Unlock()
+
+	ta := &tenantAdmission{state: state, dryRun: dryRun, now: tq.now}
+	tenantQueries.Add(state.name, 1)
+	switch {
+	case state.quota.MaxConcurrentQueries > 0 && state.concurrent >= state.quota.MaxConcurrentQueries:
+		if err := ta.exceeded(tenantLimitConcurrentQueries, state.quota.MaxConcurrentQueries); err != nil {
+			return nil, err
+		}
+	case state.rows != nil && state.rows.TokensAt(now) <= 0:
+		if err := ta.exceeded(tenantLimitRowsPerMinute, state.quota.RowsPerMinute); err != nil {
+			return nil, err
+		}
+	case state.queries != nil && !state.queries.AllowN(now, 1):
+		if err := ta.exceeded(tenantLimitQPS, state.quota.Qps); err != nil {
+			return nil, err
+		}
+	}
+
+	state.concurrent++
+	tenantConcurrentQueries.Set(state.name, state.concurrent)
+	return ta, nil
+}
+
+// tenantAdmission is a query admitted by the tenant quotas. Its methods are no-ops on a nil
+// tenantAdmission, which is the admission of a query whose tenant has no quota.
+type tenantAdmission struct {
+	state  *tenantState
+	dryRun bool
+	now    func() time.Time
+}
+
+// exceeded records that the query exceeded the given limit of the quota of its tenant, and
+// returns the error to reject it with, or nil in dry-run mode. The caller must hold the lock of
+// the tenant's state.
+func (ta *tenantAdmission) exceeded(limit string, value any) error {
+	tenantQuotaExceeded.Add([]string{ta.state.name, limit}, 1)
+	if ta.dryRun {
+		return nil
+	}
+	tenantQuotaRejected.Add([]string{ta.state.name, limit}, 1)
+	return vterrors.NewErrorf(vtrpcpb.Code_RESOURCE_EXHAUSTED, vterrors.UserLimitReached, "tenant quota exceeded for '%s': %s limit of %v", ta.state.name, limit, value)
+}
+
+// checkShards returns an error if the query is about to be sent to more shards at once than the
+// quota of its tenant allows.
+func (ta *tenantAdmission) checkShards(shards int) error {
+	if ta == nil {
+		return nil
+	}
+	ta.state.mu.Lock()
+	defer ta.state.mu.Unlock()
+
+	if maxShards := ta.state.quota.MaxShards; maxShards > 0 && int64(shards) > maxShards {
+		return ta.exceeded(tenantLimitShards, maxShards)
+	}
+	return nil
+}
+
+// done releases the query, and charges the rows it read from the shards to its tenant.
+func (ta *tenantAdmission) done(rowsExamined uint64) {
+	if ta == nil {
+		return
+	}
+	state := ta.state
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	if state.concurrent > 0 {
+		state.concurrent--
+	}
+	tenantConcurrentQueries.Set(state.name, state.concurrent)
+	tenantRowsExamined.Add(state.name, int64(rowsExamined))
+	if state.rows != nil && rowsExamined > 0 {
+		// A reservation cannot exceed the burst, which is enough to put the tenant in deficit.
+		state.rows.ReserveN(ta.now(), int(min(rowsExamined, uint64(state.quota.RowsPerMinute))))
+	}
+}
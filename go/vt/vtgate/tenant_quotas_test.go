@@ -0,0 +1,365 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/sqlerror"
+	"vitess.io/vitess/go/vt/callerid"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// newTestTenantQuotas returns tenantQuotas with the given policy and a clock that the returned
+// function advances.
+func newTestTenantQuotas(policy *vschemapb.TenantQuotas) (*tenantQuotas, func(time.Duration)) {
+	now := time.Now()
+	tq := newTenantQuotas()
+	tq.now = func() time.Time { return now }
+	tq.update(policy)
+	return tq, func(d time.Duration) { now = now.Add(d) }
+}
+
+func userContext(username string) context.Context {
+	return callerid.NewContext(context.Background(), nil, callerid.NewImmediateCallerID(username))
+}
+
+func assertQuotaExceeded(t *testing.T, err error, msg string) {
+	t.Helper()
+	require.Error(t, err)
+	assert.Equal(t, vtrpcpb.Code_RESOURCE_EXHAUSTED, vterrors.Code(err))
+	assert.Equal(t, vterrors.UserLimitReached, vterrors.ErrState(err))
+	assert.EqualValues(t, sqlerror.ERUserLimitReached, sqlerror.NewSQLErrorFromError(err).(*sqlerror.SQLError).Number())
+	assert.EqualError(t, err, msg)
+}
+
+func TestTenantQuotasNoQuota(t *testing.T) {
+	var nilQuotas *tenantQuotas
+	ta, err := nilQuotas.admit(userContext("acme"), nil)
+	require.NoError(t, err)
+	assert.Nil(t, ta)
+	assert.NoError(t, ta.checkShards(100))
+	ta.done(100)
+
+	tq, _ := newTestTenantQuotas(&vschemapb.TenantQuotas{
+		Quotas: []*vschemapb.TenantQuota{{Tenant: "acme", Qps: 1}},
+	})
+	ta, err = tq.admit(userContext("globex"), nil)
+	require.NoError(t, err)
+	assert.Nil(t, ta)
+}
+
+func TestTenantQuotasQPS(t *testing.T) {
+	tq, advance := newTestTenantQuotas(&vschemapb.TenantQuotas{
+		Quotas: []*vschemapb.TenantQuota{{Tenant: "acme", Qps: 1, Burst: 2}},
+	})
+	ctx := userContext("acme")
+	rejected := tenantQuotaRejected.Counts()["acme.QPS"]
+
+	for i := 0; i < 2; i++ {
+		ta, err := tq.admit(ctx, nil)
+		require.NoError(t, err)
+		ta.done(0)
+	}
+	_, err := tq.admit(ctx, nil)
+	assertQuotaExceeded(t, err, "tenant quota exceeded for 'acme': QPS limit of 1")
+	assert.EqualValues(t, 1, tenantQuotaRejected.Counts()["acme.QPS"]-rejected)
+
+	advance(time.Second)
+	ta, err := tq.admit(ctx, nil)
+	require.NoError(t, err)
+	ta.done(0)
+}
+
+func TestTenantQuotasConcurrentQueries(t *testing.T) {
+	tq, _ := newTestTenantQuotas(&vschemapb.TenantQuotas{
+		Quotas: []*vschemapb.TenantQuota{{Tenant: "acme", MaxConcurrentQueries: 2}},
+	})
+	ctx := userContext("acme")
+
+	ta1, err := tq.admit(ctx, nil)
+	require.NoError(t, err)
+	ta2, err := tq.admit(ctx, nil)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, tenantConcurrentQueries.Counts()["acme"])
+
+	_, err = tq.admit(ctx, nil)
+	assertQuotaExceeded(t, err, "tenant quota exceeded for 'acme': ConcurrentQueries limit of 2")
+
+	ta1.done(0)
+	ta3, err := tq.admit(ctx, nil)
+	require.NoError(t, err)
+	ta2.done(0)
+	ta3.done(0)
+	assert.EqualValues(t, 0, tenantConcurrentQueries.Counts()["acme"])
+}
+
+func TestTenantQuotasRowsPerMinute(t *testing.T) {
+	tq, advance := newTestTenantQuotas(&vschemapb.TenantQuotas{
+		Quotas: []*vschemapb.TenantQuota{{Tenant: "acme", RowsPerMinute: 60}},
+	})
+	ctx := userContext("acme")
+
+	// The rows are charged once the query completes, which can put the tenant in deficit.
+	ta, err := tq.admit(ctx, nil)
+	require.NoError(t, err)
+	ta.done(100)
+
+	_, err = tq.admit(ctx, nil)
+	assertQuotaExceeded(t, err, "tenant quota exceeded for 'acme': RowsPerMinute limit of 60")
+
+	// The bucket refills at one row per second.
+	advance(2 * time.Second)
+	ta, err = tq.admit(ctx, nil)
+	require.NoError(t, err)
+	ta.done(1)
+}
+
+func TestTenantQuotasMaxShards(t *testing.T) {
+	tq, _ := newTestTenantQuotas(&vschemapb.TenantQuotas{
+		Quotas: []*vschemapb.TenantQuota{{Tenant: "acme", MaxShards: 2}},
+	})
+
+	ta, err := tq.admit(userContext("acme"), nil)
+	require.NoError(t, err)
+	defer ta.done(0)
+	assert.NoError(t, ta.checkShards(2))
+	assertQuotaExceeded(t, ta.checkShards(3), "tenant quota exceeded for 'acme': Shards limit of 2")
+}
+
+func TestTenantQuotasDryRun(t *testing.T) {
+	tq, _ := newTestTenantQuotas(&vschemapb.TenantQuotas{
+		DryRun: true,
+		Quotas: []*vschemapb.TenantQuota{{Tenant: "acme", MaxConcurrentQueries: 1, MaxShards: 1}},
+	})
+	ctx := userContext("acme")
+	exceeded := tenantQuotaExceeded.Counts()
+	rejected := tenantQuotaRejected.Counts()
+
+	ta1, err := tq.admit(ctx, nil)
+	require.NoError(t, err)
+	ta2, err := tq.admit(ctx, nil)
+	require.NoError(t, err)
+	assert.NoError(t, ta2.checkShards(2))
+	ta1.done(0)
+	ta2.done(0)
+
+	assert.EqualValues(t, 1, tenantQuotaExceeded.Counts()["acme.ConcurrentQueries"]-exceeded["acme.ConcurrentQueries"])
+	assert.EqualValues(t, 1, tenantQuotaExceeded.Counts()["acme.Shards"]-exceeded["acme.Shards"])
+	assert.Equal(t, rejected, tenantQuotaRejected.Counts())
+}
+
+func TestTenantQuotasTenantKey(t *testing.T) {
+	parser := sqlparser.NewTestParser()
+	stmt, err := parser.Parse("select /*vt+ TENANT=acme */ 1 from dual")
+	require.NoError(t, err)
+	ctx := callerid.NewContext(context.Background(), callerid.NewEffectiveCallerID("principal", "component", "subcomponent"), callerid.NewImmediateCallerID("username"))
+
+	tcases := []struct {
+		tenantKey string
+		want      string
+	}{
+		{"", "username"},
+		{vindexes.TenantKeyUsername, "username"},
+		{vindexes.TenantKeyPrincipal, "principal"},
+		{vindexes.TenantKeyComponent, "component"},
+		{vindexes.TenantKeySubcomponent, "subcomponent"},
+		{vindexes.TenantKeyTag, "acme"},
+	}
+	for _, tcase := range tcases {
+		assert.Equal(t, tcase.want, tenantOf(ctx, stmt, tcase.tenantKey), tcase.tenantKey)
+	}
+}
+
+func TestTenantQuotasDefaultQuota(t *testing.T) {
+	tq, _ := newTestTenantQuotas(&vschemapb.TenantQuotas{
+		Quotas: []*vschemapb.TenantQuota{
+			{Tenant: vindexes.DefaultTenantQuota, MaxShards: 1, MaxConcurrentQueries: 2},
+			{Tenant: "acme", MaxShards: 4},
+		},
+	})
+	queries := tenantQueries.Counts()
+
+	// The tenants without a quota of their own each get a copy of the default one.
+	for i := 0; i < 2; i++ {
+		globex, err := tq.admit(userContext("globex"), nil)
+		require.NoError(t, err)
+		defer globex.done(0)
+		assert.Error(t, globex.checkShards(2))
+	}
+	_, err := tq.admit(userContext("globex"), nil)
+	assertQuotaExceeded(t, err, "tenant quota exceeded for 'globex': ConcurrentQueries limit of 2")
+	initech, err := tq.admit(userContext("initech"), nil)
+	require.NoError(t, err)
+	defer initech.done(0)
+	assert.EqualValues(t, 3, tenantQueries.Counts()["globex"]-queries["globex"])
+	assert.EqualValues(t, 1, tenantQueries.Counts()["initech"]-queries["initech"])
+	assert.NotContains(t, tenantQueries.Counts(), vindexes.DefaultTenantQuota)
+	assert.Len(t, tq.tenants, 2)
+
+	acme, err := tq.admit(userContext("acme"), nil)
+	require.NoError(t, err)
+	defer acme.done(0)
+	assert.NoError(t, acme.checkShards(4))
+	assert.Len(t, tq.tenants, 3)
+}
+
+func TestTenantQuotasEvictIdle(t *testing.T) {
+	tq, advance := newTestTenantQuotas(&vschemapb.TenantQuotas{
+		Quotas: []*vschemapb.TenantQuota{
+			{Tenant: "acme", Qps: 1},
+			{Tenant: "globex", MaxConcurrentQueries: 1},
+		},
+	})
+
+	acme, err := tq.admit(userContext("acme"), nil)
+	require.NoError(t, err)
+	acme.done(0)
+	globex, err := tq.admit(userContext("globex"), nil)
+	require.NoError(t, err)
+	require.Len(t, tq.tenants, 2)
+
+	// The tenants are evicted once they have no executing query and full token buckets.
+	advance(tenantEvictionInterval)
+	_, err = tq.admit(userContext("initech"), nil)
+	require.NoError(t, err)
+	assert.NotContains(t, tq.tenants, "acme")
+	assert.Contains(t, tq.tenants, "globex")
+
+	// Idle tenants are only looked for once per interval.
+	globex.done(0)
+	_, err = tq.admit(userContext("initech"), nil)
+	require.NoError(t, err)
+	assert.Contains(t, tq.tenants, "globex")
+	advance(tenantEvictionInterval)
+	_, err = tq.admit(userContext("initech"), nil)
+	require.NoError(t, err)
+	assert.Empty(t, tq.tenants)
+}
+
+func TestTenantStateIdle(t *testing.T) {
+	now := time.Now()
+	state := newTenantState("acme", &vschemapb.TenantQuota{Tenant: "acme", Qps: 1, Burst: 2, RowsPerMinute: 60})
+	assert.True(t, state.idle(now))
+
+	require.True(t, state.queries.AllowN(now, 1))
+	assert.False(t, state.idle(now))
+	assert.True(t, state.idle(now.Add(time.Second)))
+
+	state.rows.ReserveN(now, 30)
+	assert.False(t, state.idle(now.Add(time.Second)))
+	assert.True(t, state.idle(now.Add(30*time.Second)))
+
+	state.concurrent = 1
+	assert.False(t, state.idle(now.Add(time.Minute)))
+}
+
+func TestTenantQuotasConcurrentAdmissions(t *testing.T) {
+	tq := newTenantQuotas()
+	tq.update(&vschemapb.TenantQuotas{
+		Quotas: []*vschemapb.TenantQuota{
+			{Tenant: vindexes.DefaultTenantQuota, MaxConcurrentQueries: 4},
+			{Tenant: "acme", MaxConcurrentQueries: 2},
+		},
+	})
+
+	// The queries admitted for each state, between their admission and their release, never
+	// exceed its limit.
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		inFlight = map[string]int64{}
+		maxSeen  = map[string]int64{}
+	)
+	for _, tenant := range []string{"acme", "globex", "initech"} {
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func(tenant string) {
+				defer wg.Done()
+				ctx := userContext(tenant)
+				for j := 0; j < 50; j++ {
+					ta, err := tq.admit(ctx, nil)
+					if err != nil {
+						continue
+					}
+					mu.Lock()
+					inFlight[ta.state.name]++
+					maxSeen[ta.state.name] = max(maxSeen[ta.state.name], inFlight[ta.state.name])
+					mu.Unlock()
+					runtime.Gosched()
+					mu.Lock()
+					inFlight[ta.state.name]--
+					mu.Unlock()
+					ta.done(0)
+				}
+			}(tenant)
+		}
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, maxSeen["acme"], int64(2))
+	assert.LessOrEqual(t, maxSeen["globex"], int64(4))
+	assert.LessOrEqual(t, maxSeen["initech"], int64(4))
+	for name, state := range tq.tenants {
+		assert.Zero(t, state.concurrent, name)
+	}
+}
+
+func TestTenantQuotasUpdate(t *testing.T) {
+	tq, _ := newTestTenantQuotas(&vschemapb.TenantQuotas{
+		Quotas: []*vschemapb.TenantQuota{{Tenant: "acme", MaxConcurrentQueries: 1}},
+	})
+	ctx := userContext("acme")
+
+	ta1, err := tq.admit(ctx, nil)
+	require.NoError(t, err)
+
+	// A new quota keeps the count of executing queries.
+	tq.update(&vschemapb.TenantQuotas{
+		Quotas: []*vschemapb.TenantQuota{{Tenant: "acme", MaxConcurrentQueries: 2}},
+	})
+	ta2, err := tq.admit(ctx, nil)
+	require.NoError(t, err)
+	_, err = tq.admit(ctx, nil)
+	assertQuotaExceeded(t, err, "tenant quota exceeded for 'acme': ConcurrentQueries limit of 2")
+	ta1.done(0)
+	ta2.done(0)
+	assert.EqualValues(t, 0, tq.tenants["acme"].concurrent)
+
+	// An invalid policy is ignored.
+	tq.update(&vschemapb.TenantQuotas{TenantKey: "hostname"})
+	assert.Contains(t, tq.tenants, "acme")
+
+	// Removing the quota of the tenant stops enforcing it.
+	tq.update(&vschemapb.TenantQuotas{})
+	ta, err := tq.admit(ctx, nil)
+	require.NoError(t, err)
+	assert.Nil(t, ta)
+	assert.Empty(t, tq.tenants)
+}
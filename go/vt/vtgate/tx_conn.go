@@ -23,10 +23,12 @@ import (
 	"sync"
 
 	"vitess.io/vitess/go/mysql/sqlerror"
+	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/vt/concurrency"
 	"vitess.io/vitess/go/vt/dtids"
 	"vitess.io/vitess/go/vt/log"
 	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/srvtopo"
 	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vttablet/queryservice"
 
@@ -82,6 +84,116 @@ func (txc *TxConn) Begin(ctx context.Context, session *SafeSession, txAccessMode
 	return nil
 }
 
+// snapshotLock is a reserved connection holding a global read lock on a shard
+// while the consistent snapshot transactions are being started.
+type snapshotLock struct {
+	reservedID int64
+	alias      *topodatapb.TabletAlias
+}
+
+// BeginConsistentSnapshot starts a read only transaction on every one of the given shards
+// such that all of them read from the same cross-shard consistent point. Writes are
+// briefly blocked on all the shards with a global read lock, the consistent snapshot
+// transactions are started while the locks are held and the locks are then released.
+// The session must already be in a transaction which has not yet touched any shard, and
+// the caller must roll it back if this fails.
+//
+// The global read lock is taken with the app user of the tablets, which therefore needs
+// the RELOAD privilege.
+func (txc *TxConn) BeginConsistentSnapshot(ctx context.Context, session *SafeSession, rss []*srvtopo.ResolvedShard) error {
+	if len(session.ShardSessions) > 0 || len(session.PreSessions) > 0 || len(session.PostSessions) > 0 {
+		return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "consistent snapshot cannot be taken on a session with open shard connections")
+	}
+	for _, accessMode := range session.GetOrCreateOptions().TransactionAccessMode {
+		if accessMode == querypb.ExecuteOptions_READ_WRITE {
+			return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "cross-shard consistent snapshot transactions are read only")
+		}
+	}
+
+	targets := make([]*querypb.Target, 0, len(rss))
+	for _, rs := range rss {
+		targets = append(targets, rs.Target)
+	}
+
+	var mu sync.Mutex
+	locks := make(map[string]*snapshotLock, len(targets))
+	defer func() {
+		// Always release the locks, even if taking some of them failed or ctx
+		// is done, so writes are not blocked on the shards any longer.
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), consistentSnapshotUnlockTimeout)
+		defer cancel()
+		_ = txc.runTargets(targets, func(target *querypb.Target) error {
+			lock, ok := locks[target.Shard]
+			if !ok {
+				return nil
+			}
+			qs, err := txc.queryService(lock.alias)
+			if err != nil {
+				return err
+			}
+			if _, err := qs.Execute(ctx, target, "unlock tables", nil, 0, lock.reservedID, nil); err != nil {
+				log.Warningf("Unlock tables on %s/%s failed: %v", target.Keyspace, target.Shard, err)
+			}
+			return qs.Release(ctx, target, 0, lock.reservedID)
+		})
+	}()
+
+	lockWaitTimeout := fmt.Sprintf("set @@session.lock_wait_timeout = %d", int64(consistentSnapshotLockWaitTimeout.Seconds()))
+	err := txc.runTargets(targets, func(target *querypb.Target) error {
+		state, _, err := txc.tabletGateway.ReserveExecute(ctx, target, []string{lockWaitTimeout}, "flush tables with read lock", nil, 0, nil)
+		if state.ReservedID != 0 {
+			mu.Lock()
+			locks[target.Shard] = &snapshotLock{reservedID: state.ReservedID, alias: state.TabletAlias}
+			mu.Unlock()
+		}
+		return err
+	})
+	if err != nil {
+		return vterrors.Wrapf(err, "unable to lock shards for a consistent snapshot")
+	}
+
+	options := session.GetOrCreateOptions().CloneVT()
+	options.TransactionIsolation = querypb.ExecuteOptions_CONSISTENT_SNAPSHOT_READ_ONLY
+	options.TransactionAccessMode = nil
+	err = txc.runTargets(targets, func(target *querypb.Target) error {
+		lock := locks[target.Shard]
+		// The transaction has to be started on the tablet holding the lock.
+		qs, err := txc.queryService(lock.alias)
+		if err != nil {
+			return err
+		}
+		var shardSession *vtgatepb.Session_ShardSession
+		var qr *sqltypes.Result
+		if session.InReservedConn() {
+			var state queryservice.ReservedTransactionState
+			state, qr, err = qs.ReserveBeginExecute(ctx, target, session.SetPreQueries(), nil, "select @@global.gtid_executed", nil, options)
+			shardSession = &vtgatepb.Session_ShardSession{Target: target, TransactionId: state.TransactionID, ReservedId: state.ReservedID, TabletAlias: state.TabletAlias}
+		} else {
+			var state queryservice.TransactionState
+			state, qr, err = qs.BeginExecute(ctx, target, nil, "select @@global.gtid_executed", nil, 0, options)
+			shardSession = &vtgatepb.Session_ShardSession{Target: target, TransactionId: state.TransactionID, TabletAlias: state.TabletAlias}
+		}
+		if err == nil && len(qr.Rows) == 1 {
+			// Record the position of the snapshot on the shard, so that it is
+			// returned to the client with the session.
+			shardSession.GtidExecuted = qr.Rows[0][0].ToString()
+		}
+		if shardSession.TransactionId != 0 || shardSession.ReservedId != 0 {
+			// Record the shard session even on error, so that it gets rolled back.
+			if appendErr := session.AppendOrUpdate(shardSession, txc.mode); appendErr != nil && err == nil {
+				err = appendErr
+			}
+		}
+		if err != nil {
+			return err
+		}
+		log.Infof("Consistent snapshot on %s/%s taken at %s", target.Keyspace, target.Shard, shardSession.GtidExecuted)
+		session.logging.log(nil, target, nil, "begin consistent snapshot", true, nil)
+		return nil
+	})
+	return err
+}
+
 // Commit commits the current transaction. The type of commit can be
 // best effort or 2pc depending on the session setting.
 func (txc *TxConn) Commit(ctx context.Context, session *SafeSession) error {
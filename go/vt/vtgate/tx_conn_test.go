@@ -29,8 +29,10 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"vitess.io/vitess/go/mysql/sqlerror"
+	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/vt/discovery"
 	"vitess.io/vitess/go/vt/key"
+	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/srvtopo"
 	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vttablet/sandboxconn"
@@ -67,6 +69,56 @@ func TestTxConnBegin(t *testing.T) {
 	assert.EqualValues(t, 1, sbc0.CommitCount.Load(), "sbc0.CommitCount")
 }
 
+func TestTxConnBeginConsistentSnapshot(t *testing.T) {
+	ctx := utils.LeakCheckContext(t)
+
+	sc, sbc0, sbc1, _, _, rss01 := newTestTxConnEnv(t, ctx, "TestTxConn")
+	session := &vtgatepb.Session{}
+	safeSession := NewSafeSession(session)
+	require.NoError(t, sc.txConn.Begin(ctx, safeSession, nil))
+	gtidFields := sqltypes.MakeTestFields("@@global.gtid_executed", "varchar")
+	sbc0.SetResults([]*sqltypes.Result{{}, {}, sqltypes.MakeTestResult(gtidFields, "uuid0:1-10")})
+	sbc1.SetResults([]*sqltypes.Result{{}, {}, sqltypes.MakeTestResult(gtidFields, "uuid1:1-20")})
+
+	err := sc.txConn.BeginConsistentSnapshot(ctx, safeSession, rss01)
+	require.NoError(t, err)
+	require.Len(t, session.ShardSessions, 2)
+	for _, ss := range session.ShardSessions {
+		assert.NotZero(t, ss.TransactionId)
+		assert.Zero(t, ss.ReservedId)
+	}
+	// The GTIDs of the snapshot are recorded on every shard.
+	assert.Equal(t, map[string]string{
+		"TestTxConn/0": "uuid0:1-10",
+		"TestTxConn/1": "uuid1:1-20",
+	}, safeSession.GetConsistentSnapshotGTIDs())
+
+	for _, sbc := range []*sandboxconn.SandboxConn{sbc0, sbc1} {
+		var sqls []string
+		for _, q := range sbc.Queries {
+			sqls = append(sqls, q.Sql)
+		}
+		assert.Equal(t, []string{
+			"set @@session.lock_wait_timeout = 10",
+			"flush tables with read lock",
+			"select @@global.gtid_executed",
+			"unlock tables",
+		}, sqls)
+		assert.EqualValues(t, 1, sbc.ReleaseCount.Load())
+		assert.Equal(t, querypb.ExecuteOptions_CONSISTENT_SNAPSHOT_READ_ONLY, sbc.Options[2].TransactionIsolation)
+	}
+
+	// A consistent snapshot can only be taken before any shard is touched.
+	err = sc.txConn.BeginConsistentSnapshot(ctx, safeSession, rss01)
+	require.ErrorContains(t, err, "consistent snapshot cannot be taken on a session with open shard connections")
+
+	// The snapshot transactions are read only.
+	safeSession = NewSafeSession(&vtgatepb.Session{})
+	require.NoError(t, sc.txConn.Begin(ctx, safeSession, []sqlparser.TxAccessMode{sqlparser.ReadWrite}))
+	err = sc.txConn.BeginConsistentSnapshot(ctx, safeSession, rss01)
+	require.ErrorContains(t, err, "cross-shard consistent snapshot transactions are read only")
+}
+
 func TestTxConnCommitFailure(t *testing.T) {
 	ctx := utils.LeakCheckContext(t)
 
@@ -78,6 +78,7 @@ type iExecute interface {
 	showShards(ctx context.Context, filter *sqlparser.ShowFilter, destTabletType topodatapb.TabletType) (*sqltypes.Result, error)
 	showTablets(filter *sqlparser.ShowFilter) (*sqltypes.Result, error)
 	showVitessMetadata(ctx context.Context, filter *sqlparser.ShowFilter) (*sqltypes.Result, error)
+	showQueryDigests(filter *sqlparser.ShowFilter) (*sqltypes.Result, error)
 	setVitessMetadata(ctx context.Context, name, value string) error
 
 	// TODO: remove when resolver is gone
@@ -107,6 +108,7 @@ type vcursorImpl struct {
 	topoServer     *topo.Server
 	logStats       *logstats.LogStats
 	collation      collations.ID
+	cell           string
 
 	// fkChecksState stores the state of foreign key checks variable.
 	// This state is meant to be the final fk checks state after consulting the
@@ -124,6 +126,10 @@ type vcursorImpl struct {
 
 	warmingReadsPercent int
 	warmingReadsChannel chan bool
+
+	// tenantQuota is the admission of the query by the tenant quotas, which limits the number of
+	// shards it can be sent to at once.
+	tenantQuota *tenantAdmission
 }
 
 // newVcursorImpl creates a vcursorImpl. Before creating this object, you have to separate out any marginComments that came with
@@ -170,9 +176,11 @@ func newVCursorImpl(
 
 	warmingReadsPct := 0
 	var warmingReadsChan chan bool
+	var cell string
 	if executor != nil {
 		warmingReadsPct = executor.warmingReadsPercent
 		warmingReadsChan = executor.warmingReadsChannel
+		cell = executor.cell
 	}
 	return &vcursorImpl{
 		safeSession:         safeSession,
@@ -183,6 +191,7 @@ func newVCursorImpl(
 		executor:            executor,
 		logStats:            logStats,
 		collation:           connCollation,
+		cell:                cell,
 		resolver:            resolver,
 		vschema:             vschema,
 		vm:                  vm,
@@ -571,6 +580,9 @@ const txRollback = "Rollback Transaction"
 // ExecuteMultiShard is part of the engine.VCursor interface.
 func (vc *vcursorImpl) ExecuteMultiShard(ctx context.Context, primitive engine.Primitive, rss []*srvtopo.ResolvedShard, queries []*querypb.BoundQuery, rollbackOnError, canAutocommit bool) (*sqltypes.Result, []error) {
 	noOfShards := len(rss)
+	if err := vc.tenantQuota.checkShards(noOfShards); err != nil {
+		return nil, []error{err}
+	}
 	atomic.AddUint64(&vc.logStats.ShardQueries, uint64(noOfShards))
 	err := vc.markSavepoint(ctx, rollbackOnError && (noOfShards > 1), map[string]*querypb.BindVariable{})
 	if err != nil {
@@ -579,6 +591,9 @@ func (vc *vcursorImpl) ExecuteMultiShard(ctx context.Context, primitive engine.P
 
 	qr, errs := vc.executor.ExecuteMultiShard(ctx, primitive, rss, commentedShardQueries(queries, vc.marginComments), vc.safeSession, canAutocommit, vc.ignoreMaxMemoryRows)
 	vc.setRollbackOnPartialExecIfRequired(len(errs) != len(rss), rollbackOnError)
+	if qr != nil {
+		atomic.AddUint64(&vc.logStats.RowsExamined, uint64(len(qr.Rows)))
+	}
 
 	return qr, errs
 }
@@ -586,13 +601,19 @@ func (vc *vcursorImpl) ExecuteMultiShard(ctx context.Context, primitive engine.P
 // StreamExecuteMulti is the streaming version of ExecuteMultiShard.
 func (vc *vcursorImpl) StreamExecuteMulti(ctx context.Context, primitive engine.Primitive, query string, rss []*srvtopo.ResolvedShard, bindVars []map[string]*querypb.BindVariable, rollbackOnError bool, autocommit bool, callback func(reply *sqltypes.Result) error) []error {
 	noOfShards := len(rss)
+	if err := vc.tenantQuota.checkShards(noOfShards); err != nil {
+		return []error{err}
+	}
 	atomic.AddUint64(&vc.logStats.ShardQueries, uint64(noOfShards))
 	err := vc.markSavepoint(ctx, rollbackOnError && (noOfShards > 1), map[string]*querypb.BindVariable{})
 	if err != nil {
 		return []error{err}
 	}
 
-	errs := vc.executor.StreamExecuteMulti(ctx, primitive, vc.marginComments.Leading+query+vc.marginComments.Trailing, rss, bindVars, vc.safeSession, autocommit, callback)
+	errs := vc.executor.StreamExecuteMulti(ctx, primitive, vc.marginComments.Leading+query+vc.marginComments.Trailing, rss, bindVars, vc.safeSession, autocommit, func(reply *sqltypes.Result) error {
+		atomic.AddUint64(&vc.logStats.RowsExamined, uint64(len(reply.Rows)))
+		return callback(reply)
+	})
 	vc.setRollbackOnPartialExecIfRequired(len(errs) != len(rss), rollbackOnError)
 
 	return errs
@@ -944,6 +965,11 @@ func (vc *vcursorImpl) GetMigrationContext() string {
 	return vc.safeSession.GetMigrationContext()
 }
 
+// SetConsistentSnapshot implements the SessionActions interface
+func (vc *vcursorImpl) SetConsistentSnapshot(consistentSnapshot bool) {
+	vc.safeSession.SetConsistentSnapshot(consistentSnapshot)
+}
+
 // GetSessionUUID implements the SessionActions interface
 func (vc *vcursorImpl) GetSessionUUID() string {
 	return vc.safeSession.GetSessionUUID()
@@ -1182,6 +1208,8 @@ func (vc *vcursorImpl) ShowExec(ctx context.Context, command sqlparser.ShowComma
 		return vc.executor.showTablets(filter)
 	case sqlparser.VitessVariables:
 		return vc.executor.showVitessMetadata(ctx, filter)
+	case sqlparser.VitessQueryDigests:
+		return vc.executor.showQueryDigests(filter)
 	default:
 		return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "bug: unexpected show command: %v", command)
 	}
@@ -1320,6 +1348,11 @@ func (vc *vcursorImpl) GetWarmingReadsChannel() chan bool {
 	return vc.warmingReadsChannel
 }
 
+// LocalCell returns the cell of this vtgate.
+func (vc *vcursorImpl) LocalCell() string {
+	return vc.cell
+}
+
 func (vc *vcursorImpl) CloneForReplicaWarming(ctx context.Context) engine.VCursor {
 	callerId := callerid.EffectiveCallerIDFromContext(ctx)
 	immediateCallerId := callerid.ImmediateCallerIDFromContext(ctx)
@@ -1338,6 +1371,7 @@ func (vc *vcursorImpl) CloneForReplicaWarming(ctx context.Context) engine.VCurso
 		topoServer:          vc.topoServer,
 		logStats:            &logstats.LogStats{Ctx: clonedCtx},
 		collation:           vc.collation,
+		cell:                vc.cell,
 		ignoreMaxMemoryRows: vc.ignoreMaxMemoryRows,
 		vschema:             vc.vschema,
 		vm:                  vc.vm,
@@ -29,6 +29,7 @@ var (
 	_ SingleColumn    = (*Binary)(nil)
 	_ Reversible      = (*Binary)(nil)
 	_ Hashing         = (*Binary)(nil)
+	_ Sequential      = (*Binary)(nil)
 	_ ParamValidating = (*Binary)(nil)
 )
 
@@ -108,6 +109,26 @@ func (*Binary) ReverseMap(_ VCursor, ksids [][]byte) ([]sqltypes.Value, error) {
 	return reverseIds, nil
 }
 
+// RangeMap implements the Sequential interface. Keyspace ids are ordered like
+// the bytes of the ids, which is only how MySQL compares them if both sides
+// are strings, so a range with a bound that isn't a string maps to all shards.
+func (vind *Binary) RangeMap(ctx context.Context, vcursor VCursor, start, end sqltypes.Value) (key.Destination, error) {
+	var startKsid, endKsid []byte
+	if !start.IsNull() {
+		if !sqltypes.IsTextOrBinary(start.Type()) {
+			return key.DestinationAllShards{}, nil
+		}
+		startKsid = start.Raw()
+	}
+	if !end.IsNull() {
+		if !sqltypes.IsTextOrBinary(end.Type()) {
+			return key.DestinationAllShards{}, nil
+		}
+		endKsid = end.Raw()
+	}
+	return keyRangeBetween(startKsid, endKsid), nil
+}
+
 // UnknownParams implements the ParamValidating interface.
 func (vind *Binary) UnknownParams() []string {
 	return vind.unknownParams
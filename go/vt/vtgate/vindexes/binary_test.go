@@ -28,6 +28,8 @@ import (
 
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/vt/key"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 )
 
 var binOnlyVindex SingleColumn
@@ -113,6 +115,39 @@ func TestBinaryMap(t *testing.T) {
 	}
 }
 
+func TestBinaryRangeMap(t *testing.T) {
+	tcases := []struct {
+		start, end sqltypes.Value
+		want       key.Destination
+	}{{
+		start: sqltypes.NewVarBinary("a"),
+		end:   sqltypes.NewVarChar("b"),
+		want: key.DestinationKeyRange{KeyRange: &topodatapb.KeyRange{
+			Start: []byte("a"),
+			End:   []byte("b\x00"),
+		}},
+	}, {
+		start: sqltypes.NULL,
+		end:   sqltypes.NewVarBinary("b"),
+		want: key.DestinationKeyRange{KeyRange: &topodatapb.KeyRange{
+			End: []byte("b\x00"),
+		}},
+	}, {
+		start: sqltypes.NewVarBinary("b"),
+		end:   sqltypes.NewVarBinary("a"),
+		want:  key.DestinationNone{},
+	}, {
+		start: sqltypes.NewInt64(1),
+		end:   sqltypes.NewVarBinary("b"),
+		want:  key.DestinationAllShards{},
+	}}
+	for _, tcase := range tcases {
+		got, err := binOnlyVindex.(Sequential).RangeMap(context.Background(), nil, tcase.start, tcase.end)
+		require.NoError(t, err)
+		require.Equal(t, tcase.want, got, "RangeMap(%v, %v)", tcase.start, tcase.end)
+	}
+}
+
 func TestBinaryVerify(t *testing.T) {
 	hexValStr := "8a1e"
 	hexValStrSQL := fmt.Sprintf("x'%s'", hexValStr)
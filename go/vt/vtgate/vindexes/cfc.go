@@ -35,6 +35,7 @@ const (
 
 var (
 	_ ParamValidating = (*CFC)(nil)
+	_ Sequential      = (*CFC)(nil)
 
 	cfcParams = []string{
 		cfcParamHash,
@@ -274,6 +275,55 @@ func (vind *CFC) Map(_ context.Context, _ VCursor, ids []sqltypes.Value) ([]key.
 	return out, nil
 }
 
+// RangeMap implements the Sequential interface. Without a hash, the keyspace
+// ids are the ids, so they are ordered like the bytes of the ids. With a hash,
+// only the components that all the ids in the range share are known, so the
+// range maps to the key range of their prefix, like a LIKE on that prefix.
+// Either way a range with a bound that isn't a string maps to all shards, as
+// MySQL doesn't compare it with the ids byte by byte.
+func (vind *CFC) RangeMap(_ context.Context, _ VCursor, start, end sqltypes.Value) (key.Destination, error) {
+	var startID, endID []byte
+	if !start.IsNull() {
+		if !sqltypes.IsTextOrBinary(start.Type()) {
+			return key.DestinationAllShards{}, nil
+		}
+		startID = start.Raw()
+	}
+	if !end.IsNull() {
+		if !sqltypes.IsTextOrBinary(end.Type()) {
+			return key.DestinationAllShards{}, nil
+		}
+		endID = end.Raw()
+	}
+	if vind.hash == nil {
+		return keyRangeBetween(startID, endID), nil
+	}
+	if start.IsNull() || end.IsNull() {
+		return key.DestinationAllShards{}, nil
+	}
+	if bytes.Compare(startID, endID) > 0 {
+		return key.DestinationNone{}, nil
+	}
+	// The ids in the range share the common prefix of its bounds, of which
+	// only the complete fixed-width components can be hashed.
+	common := 0
+	for common < len(startID) && common < len(endID) && startID[common] == endID[common] {
+		common++
+	}
+	prefix := 0
+	for _, offset := range vind.offsets {
+		if offset > common {
+			break
+		}
+		prefix = offset
+	}
+	begin, err := vind.computeKsid(startID[:prefix], true)
+	if err != nil {
+		return nil, err
+	}
+	return NewKeyRangeFromPrefix(begin), nil
+}
+
 // PrefixVindex switches the vindex to prefix mode
 func (vind *CFC) PrefixVindex() SingleColumn {
 	return vind.prefixCFC
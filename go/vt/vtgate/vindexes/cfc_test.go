@@ -458,6 +458,78 @@ func TestCFCPrefixQueryMapNoHash(t *testing.T) {
 	}
 }
 
+func TestCFCRangeMap(t *testing.T) {
+	cfc := makeCFC(t, map[string]string{"hash": "md5", "offsets": "[3,5]"})
+	cfcNoHash := makeCFC(t, nil)
+
+	cases := []struct {
+		testName   string
+		vindex     *CFC
+		start, end sqltypes.Value
+		dest       key.Destination
+	}{
+		{
+			testName: "common first component",
+			vindex:   cfc,
+			start:    sqltypes.NewVarBinary("abcaa"),
+			end:      sqltypes.NewVarBinary("abczz"),
+			dest:     NewKeyRangeFromPrefix(expectedHash([][]byte{{'a', 'b', 'c'}})),
+		},
+		{
+			testName: "common fixed-width components",
+			vindex:   cfc,
+			start:    sqltypes.NewVarBinary("abcdea"),
+			end:      sqltypes.NewVarChar("abcdez"),
+			dest:     NewKeyRangeFromPrefix(expectedHash([][]byte{{'a', 'b', 'c'}, {'d', 'e'}})),
+		},
+		{
+			testName: "no common component",
+			vindex:   cfc,
+			start:    sqltypes.NewVarBinary("abaaa"),
+			end:      sqltypes.NewVarBinary("abzzz"),
+			dest:     key.DestinationAllShards{},
+		},
+		{
+			testName: "open range",
+			vindex:   cfc,
+			start:    sqltypes.NewVarBinary("abcaa"),
+			end:      sqltypes.NULL,
+			dest:     key.DestinationAllShards{},
+		},
+		{
+			testName: "empty range",
+			vindex:   cfc,
+			start:    sqltypes.NewVarBinary("abczz"),
+			end:      sqltypes.NewVarBinary("abcaa"),
+			dest:     key.DestinationNone{},
+		},
+		{
+			testName: "bound that isn't a string",
+			vindex:   cfc,
+			start:    sqltypes.NewInt64(1),
+			end:      sqltypes.NewInt64(2),
+			dest:     key.DestinationAllShards{},
+		},
+		{
+			testName: "no hash",
+			vindex:   cfcNoHash,
+			start:    sqltypes.NewVarBinary("a"),
+			end:      sqltypes.NewVarBinary("b"),
+			dest: key.DestinationKeyRange{KeyRange: &topodatapb.KeyRange{
+				Start: []byte("a"),
+				End:   []byte("b\x00"),
+			}},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.testName, func(t *testing.T) {
+			dest, err := tc.vindex.RangeMap(context.Background(), nil, tc.start, tc.end)
+			require.NoError(t, err)
+			assert.EqualValues(t, tc.dest, dest)
+		})
+	}
+}
+
 func TestCFCFindPrefixEscape(t *testing.T) {
 	cases := []struct {
 		str, prefix string
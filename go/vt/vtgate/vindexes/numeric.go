@@ -30,6 +30,7 @@ var (
 	_ SingleColumn    = (*Numeric)(nil)
 	_ Reversible      = (*Numeric)(nil)
 	_ Hashing         = (*Numeric)(nil)
+	_ Sequential      = (*Numeric)(nil)
 	_ ParamValidating = (*Numeric)(nil)
 )
 
@@ -108,6 +109,38 @@ func (*Numeric) ReverseMap(_ VCursor, ksids [][]byte) ([]sqltypes.Value, error)
 	return reverseIds, nil
 }
 
+// RangeMap implements the Sequential interface. Keyspace ids are ordered like
+// non-negative ids only: negative ids map to the keyspace ids after those of
+// all the non-negative ones. So unless both bounds of the range are
+// non-negative integers, the range maps to all shards.
+func (vind *Numeric) RangeMap(ctx context.Context, vcursor VCursor, start, end sqltypes.Value) (key.Destination, error) {
+	if start.IsNull() || end.IsNull() || !isNonNegativeInteger(start) || !isNonNegativeInteger(end) {
+		return key.DestinationAllShards{}, nil
+	}
+	startKsid, err := vind.Hash(start)
+	if err != nil {
+		return nil, err
+	}
+	endKsid, err := vind.Hash(end)
+	if err != nil {
+		return nil, err
+	}
+	return keyRangeBetween(startKsid, endKsid), nil
+}
+
+// isNonNegativeInteger returns true if v is an integer that is at least 0.
+func isNonNegativeInteger(v sqltypes.Value) bool {
+	switch {
+	case v.IsUnsigned():
+		return true
+	case v.IsSigned():
+		n, err := v.ToInt64()
+		return err == nil && n >= 0
+	default:
+		return false
+	}
+}
+
 // UnknownParams implements the ParamValidating interface.
 func (vind *Numeric) UnknownParams() []string {
 	return vind.unknownParams
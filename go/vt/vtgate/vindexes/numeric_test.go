@@ -25,6 +25,8 @@ import (
 
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/vt/key"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 )
 
 var numeric SingleColumn
@@ -117,6 +119,58 @@ func TestNumericMap(t *testing.T) {
 	}
 }
 
+func TestNumericRangeMap(t *testing.T) {
+	tcases := []struct {
+		start, end sqltypes.Value
+		want       key.Destination
+	}{{
+		start: sqltypes.NewInt64(1),
+		end:   sqltypes.NewInt64(10),
+		want: key.DestinationKeyRange{KeyRange: &topodatapb.KeyRange{
+			Start: []byte("\x00\x00\x00\x00\x00\x00\x00\x01"),
+			End:   []byte("\x00\x00\x00\x00\x00\x00\x00\x0a\x00"),
+		}},
+	}, {
+		start: sqltypes.NewUint64(1 << 63),
+		end:   sqltypes.NewUint64(1<<63 + 1),
+		want: key.DestinationKeyRange{KeyRange: &topodatapb.KeyRange{
+			Start: []byte("\x80\x00\x00\x00\x00\x00\x00\x00"),
+			End:   []byte("\x80\x00\x00\x00\x00\x00\x00\x01\x00"),
+		}},
+	}, {
+		// id >= 1 << 63 also matches negative ids.
+		start: sqltypes.NewUint64(1 << 63),
+		end:   sqltypes.NULL,
+		want:  key.DestinationAllShards{},
+	}, {
+		// id <= 5 also matches negative ids.
+		start: sqltypes.NULL,
+		end:   sqltypes.NewInt64(5),
+		want:  key.DestinationAllShards{},
+	}, {
+		start: sqltypes.NewInt64(10),
+		end:   sqltypes.NewInt64(1),
+		want:  key.DestinationNone{},
+	}, {
+		start: sqltypes.NewInt64(-1),
+		end:   sqltypes.NewInt64(10),
+		want:  key.DestinationAllShards{},
+	}, {
+		start: sqltypes.NewInt64(1),
+		end:   sqltypes.NewInt64(-1),
+		want:  key.DestinationAllShards{},
+	}, {
+		start: sqltypes.NewFloat64(1.5),
+		end:   sqltypes.NewInt64(10),
+		want:  key.DestinationAllShards{},
+	}}
+	for _, tcase := range tcases {
+		got, err := numeric.(Sequential).RangeMap(context.Background(), nil, tcase.start, tcase.end)
+		require.NoError(t, err)
+		require.Equal(t, tcase.want, got, "RangeMap(%v, %v)", tcase.start, tcase.end)
+	}
+}
+
 func TestNumericVerify(t *testing.T) {
 	got, err := numeric.Verify(context.Background(), nil, []sqltypes.Value{sqltypes.NewInt64(1), sqltypes.NewInt64(2)}, [][]byte{[]byte("\x00\x00\x00\x00\x00\x00\x00\x01"), []byte("\x00\x00\x00\x00\x00\x00\x00\x01")})
 	require.NoError(t, err)
@@ -0,0 +1,64 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vindexes
+
+import (
+	"math"
+
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+// The tenant keys of the tenant quotas, which select what identifies the tenant of a query.
+const (
+	TenantKeyUsername     = "username"
+	TenantKeyPrincipal    = "principal"
+	TenantKeyComponent    = "component"
+	TenantKeySubcomponent = "subcomponent"
+	TenantKeyTag          = "tag"
+)
+
+// DefaultTenantQuota is the tenant of the quota that applies to each tenant without a quota of its own.
+const DefaultTenantQuota = "*"
+
+// ValidateTenantQuotas returns an error if the tenant quotas have an unknown tenant key, a negative
+// limit or more than one quota for the same tenant.
+func ValidateTenantQuotas(tq *vschemapb.TenantQuotas) error {
+	switch tq.GetTenantKey() {
+	case "", TenantKeyUsername, TenantKeyPrincipal, TenantKeyComponent, TenantKeySubcomponent, TenantKeyTag:
+	default:
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid tenant key %q, expected one of %s, %s, %s, %s or %s",
+			tq.GetTenantKey(), TenantKeyUsername, TenantKeyPrincipal, TenantKeyComponent, TenantKeySubcomponent, TenantKeyTag)
+	}
+
+	tenants := make(map[string]bool, len(tq.GetQuotas()))
+	for _, quota := range tq.GetQuotas() {
+		if tenants[quota.Tenant] {
+			return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "duplicate quota for tenant %q", quota.Tenant)
+		}
+		tenants[quota.Tenant] = true
+
+		if quota.Qps < 0 || math.IsNaN(quota.Qps) || math.IsInf(quota.Qps, 0) || quota.Burst < 0 || quota.MaxConcurrentQueries < 0 || quota.MaxShards < 0 || quota.RowsPerMinute < 0 {
+			return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid quota for tenant %q: limits must be finite and non-negative", quota.Tenant)
+		}
+		if quota.Burst > 0 && quota.Qps == 0 {
+			return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid quota for tenant %q: burst requires qps", quota.Tenant)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vindexes
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+)
+
+func TestValidateTenantQuotas(t *testing.T) {
+	tcases := []struct {
+		name   string
+		tq     *vschemapb.TenantQuotas
+		errMsg string
+	}{{
+		name: "nil",
+	}, {
+		name: "valid",
+		tq: &vschemapb.TenantQuotas{
+			TenantKey: TenantKeyTag,
+			Quotas: []*vschemapb.TenantQuota{
+				{Tenant: DefaultTenantQuota, Qps: 100},
+				{Tenant: "acme", Qps: 0.5, Burst: 10, MaxConcurrentQueries: 5, MaxShards: 2, RowsPerMinute: 1000},
+			},
+		},
+	}, {
+		name:   "unknown tenant key",
+		tq:     &vschemapb.TenantQuotas{TenantKey: "hostname"},
+		errMsg: `invalid tenant key "hostname"`,
+	}, {
+		name: "duplicate tenant",
+		tq: &vschemapb.TenantQuotas{
+			Quotas: []*vschemapb.TenantQuota{{Tenant: "acme", Qps: 1}, {Tenant: "acme", MaxShards: 1}},
+		},
+		errMsg: `duplicate quota for tenant "acme"`,
+	}, {
+		name: "negative limit",
+		tq: &vschemapb.TenantQuotas{
+			Quotas: []*vschemapb.TenantQuota{{Tenant: "acme", RowsPerMinute: -1}},
+		},
+		errMsg: `invalid quota for tenant "acme": limits must be finite and non-negative`,
+	}, {
+		name: "infinite qps",
+		tq: &vschemapb.TenantQuotas{
+			Quotas: []*vschemapb.TenantQuota{{Tenant: "acme", Qps: math.Inf(1)}},
+		},
+		errMsg: `invalid quota for tenant "acme": limits must be finite and non-negative`,
+	}, {
+		name: "burst without qps",
+		tq: &vschemapb.TenantQuotas{
+			Quotas: []*vschemapb.TenantQuota{{Tenant: "acme", Burst: 10}},
+		},
+		errMsg: `invalid quota for tenant "acme": burst requires qps`,
+	}}
+	for _, tcase := range tcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			err := ValidateTenantQuotas(tcase.tq)
+			if tcase.errMsg == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tcase.errMsg)
+		})
+	}
+}
@@ -0,0 +1,384 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vindexes
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"vitess.io/vitess/go/mysql/datetime"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/key"
+	"vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+const (
+	timeRangeParamJSON     = "json"
+	timeRangeParamJSONPath = "json_path"
+	timeRangeParamIDType   = "id_type"
+
+	timeRangeIDTypeDatetime = "datetime"
+	timeRangeIDTypeUUIDv7   = "uuidv7"
+	timeRangeIDTypeULID     = "ulid"
+)
+
+var (
+	_ SingleColumn    = (*TimeRange)(nil)
+	_ Hashing         = (*TimeRange)(nil)
+	_ Sequential      = (*TimeRange)(nil)
+	_ ParamValidating = (*TimeRange)(nil)
+
+	timeRangeParams = []string{
+		timeRangeParamJSON,
+		timeRangeParamJSONPath,
+		timeRangeParamIDType,
+	}
+)
+
+// timeRangeBucket maps the times from start (in unix milliseconds) up to the
+// start of the next bucket to a keyspace id prefix.
+type timeRangeBucket struct {
+	start  int64
+	prefix []byte
+}
+
+// TimeRange is a vindex for time-ordered ids: DATETIME values, or UUIDv7 or
+// ULID values, which start with a millisecond timestamp. Ids are assigned to
+// time ranges by a mapping table, given as a JSON object from the start of
+// each range to its keyspace id prefix, in hex, e.g.
+//
+//	{"2024-01-01": "40", "2024-02-01": "80", "2024-03-01": "c0"}
+//
+// The keyspace id of an id is the prefix of its range followed by its time, so
+// ids that are ordered by time have ordered keyspace ids, and a time range can
+// be mapped to a key range (see Sequential). For example, rotating to a new
+// shard every month only requires appending a range to the mapping.
+//
+// Prefixes must all have the same length and increase with time. Ids earlier
+// than the first range can't be mapped.
+type TimeRange struct {
+	name          string
+	idType        string
+	buckets       []timeRangeBucket
+	unknownParams []string
+}
+
+func init() {
+	Register("time_range", newTimeRange)
+}
+
+// newTimeRange creates a TimeRange vindex.
+func newTimeRange(name string, params map[string]string) (Vindex, error) {
+	jsonStr, jsok := params[timeRangeParamJSON]
+	jsonPath, jpok := params[timeRangeParamJSONPath]
+
+	if !jsok && !jpok {
+		return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "TimeRange: Could not find either `json_path` or `json` params in vschema")
+	}
+
+	if jsok && jpok {
+		return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "TimeRange: Found both `json` and `json_path` params in vschema")
+	}
+
+	data := []byte(jsonStr)
+	if jpok {
+		var err error
+		data, err = os.ReadFile(jsonPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	buckets, err := parseTimeRangeBuckets(data)
+	if err != nil {
+		return nil, err
+	}
+
+	idType := timeRangeIDTypeDatetime
+	if t, ok := params[timeRangeParamIDType]; ok {
+		idType = strings.ToLower(t)
+	}
+
+	switch idType {
+	case timeRangeIDTypeDatetime, timeRangeIDTypeUUIDv7, timeRangeIDTypeULID:
+	default:
+		return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "TimeRange: invalid id_type %q, must be one of datetime, uuidv7 or ulid", idType)
+	}
+
+	return &TimeRange{
+		name:          name,
+		idType:        idType,
+		buckets:       buckets,
+		unknownParams: FindUnknownParams(params, timeRangeParams),
+	}, nil
+}
+
+func parseTimeRangeBuckets(data []byte) ([]timeRangeBucket, error) {
+	var m map[string]string
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, vterrors.Wrapf(err, "TimeRange: invalid mapping")
+	}
+
+	if len(m) == 0 {
+		return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "TimeRange: the mapping must have at least one range")
+	}
+
+	buckets := make([]timeRangeBucket, 0, len(m))
+	for start, prefix := range m {
+		t, ok := parseDatetime(start)
+		if !ok {
+			return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "TimeRange: invalid range start %q", start)
+		}
+
+		p, err := hex.DecodeString(prefix)
+		if err != nil || len(p) == 0 {
+			return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "TimeRange: invalid keyspace id prefix %q for %s", prefix, start)
+		}
+
+		buckets = append(buckets, timeRangeBucket{start: t.UnixMilli(), prefix: p})
+	}
+
+	slices.SortFunc(buckets, func(a, b timeRangeBucket) int {
+		switch {
+		case a.start < b.start:
+			return -1
+		case a.start > b.start:
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	for i := 1; i < len(buckets); i++ {
+		prev, cur := buckets[i-1], buckets[i]
+		if len(cur.prefix) != len(prev.prefix) {
+			return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "TimeRange: keyspace id prefixes must all have the same length")
+		}
+
+		if cur.start == prev.start || bytes.Compare(cur.prefix, prev.prefix) <= 0 {
+			return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "TimeRange: keyspace id prefixes must increase with the range start, got %x after %x", cur.prefix, prev.prefix)
+		}
+	}
+
+	return buckets, nil
+}
+
+// String returns the name of the vindex.
+func (vind *TimeRange) String() string {
+	return vind.name
+}
+
+// Cost returns the cost of this vindex as 1.
+func (*TimeRange) Cost() int {
+	return 1
+}
+
+// IsUnique returns true since the Vindex is unique.
+func (*TimeRange) IsUnique() bool {
+	return true
+}
+
+// NeedsVCursor satisfies the Vindex interface.
+func (*TimeRange) NeedsVCursor() bool {
+	return false
+}
+
+// Verify returns true if ids and ksids match.
+func (vind *TimeRange) Verify(ctx context.Context, vcursor VCursor, ids []sqltypes.Value, ksids [][]byte) ([]bool, error) {
+	out := make([]bool, 0, len(ids))
+	for i, id := range ids {
+		ksid, err := vind.Hash(id)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, bytes.Equal(ksid, ksids[i]))
+	}
+	return out, nil
+}
+
+// Map can map ids to key.Destination objects.
+func (vind *TimeRange) Map(ctx context.Context, vcursor VCursor, ids []sqltypes.Value) ([]key.Destination, error) {
+	out := make([]key.Destination, 0, len(ids))
+	for _, id := range ids {
+		ksid, err := vind.Hash(id)
+		if err != nil {
+			out = append(out, key.DestinationNone{})
+			continue
+		}
+		out = append(out, key.DestinationKeyspaceID(ksid))
+	}
+	return out, nil
+}
+
+// Hash returns the keyspace id of the id.
+func (vind *TimeRange) Hash(id sqltypes.Value) ([]byte, error) {
+	ms, err := vind.timestamp(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return vind.keyspaceID(ms)
+}
+
+// RangeMap implements the Sequential interface.
+func (vind *TimeRange) RangeMap(ctx context.Context, vcursor VCursor, start, end sqltypes.Value) (key.Destination, error) {
+	var startKsid, endKsid []byte
+	if !start.IsNull() {
+		ms, err := vind.timestamp(start)
+		if err != nil {
+			return nil, err
+		}
+
+		// Times before the first range can't be mapped, so the key range
+		// starts at the first range.
+		ms = max(ms, vind.buckets[0].start)
+		if startKsid, err = vind.keyspaceID(ms); err != nil {
+			return nil, err
+		}
+	}
+
+	if !end.IsNull() {
+		ms, err := vind.timestamp(end)
+		if err != nil {
+			return nil, err
+		}
+
+		if ms < vind.buckets[0].start {
+			return key.DestinationNone{}, nil
+		}
+
+		if endKsid, err = vind.keyspaceID(ms); err != nil {
+			return nil, err
+		}
+	}
+
+	return keyRangeBetween(startKsid, endKsid), nil
+}
+
+// UnknownParams implements the ParamValidating interface.
+func (vind *TimeRange) UnknownParams() []string {
+	return vind.unknownParams
+}
+
+// keyspaceID returns the keyspace id for a time in unix milliseconds: the
+// prefix of its range, followed by the time, with its sign bit flipped so that
+// the bytes sort like the times.
+func (vind *TimeRange) keyspaceID(ms int64) ([]byte, error) {
+	i := sort.Search(len(vind.buckets), func(i int) bool {
+		return vind.buckets[i].start > ms
+	}) - 1
+	if i < 0 {
+		return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "TimeRange: %s is earlier than the first range", time.UnixMilli(ms).UTC().Format(time.RFC3339Nano))
+	}
+
+	prefix := vind.buckets[i].prefix
+	ksid := make([]byte, len(prefix)+8)
+	copy(ksid, prefix)
+	binary.BigEndian.PutUint64(ksid[len(prefix):], uint64(ms)^(1<<63))
+	return ksid, nil
+}
+
+// timestamp returns the time of an id, in unix milliseconds.
+func (vind *TimeRange) timestamp(id sqltypes.Value) (int64, error) {
+	switch vind.idType {
+	case timeRangeIDTypeUUIDv7:
+		return uuidv7Timestamp(id)
+	case timeRangeIDTypeULID:
+		return ulidTimestamp(id)
+	}
+
+	if id.IsDateTime() || id.IsQuoted() {
+		if t, ok := parseDatetime(id.ToString()); ok {
+			return t.UnixMilli(), nil
+		}
+	}
+
+	return 0, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "TimeRange: cannot map %s to a time", id.String())
+}
+
+// parseDatetime parses a MySQL DATE or DATETIME literal, in UTC.
+func parseDatetime(s string) (time.Time, bool) {
+	if dt, _, ok := datetime.ParseDateTime(s, -1); ok {
+		return dt.ToStdTime(time.Unix(0, 0).UTC()), true
+	}
+
+	if d, ok := datetime.ParseDate(s); ok {
+		return d.ToStdTime(time.UTC), true
+	}
+
+	return time.Time{}, false
+}
+
+// uuidv7Timestamp returns the timestamp of a UUIDv7, given as text (with or
+// without dashes) or as 16 bytes.
+func uuidv7Timestamp(id sqltypes.Value) (int64, error) {
+	raw := id.Raw()
+	if len(raw) != 16 {
+		var err error
+		raw, err = hex.DecodeString(strings.ReplaceAll(id.ToString(), "-", ""))
+		if err != nil || len(raw) != 16 {
+			return 0, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "TimeRange: invalid UUID %s", id.String())
+		}
+	}
+
+	if raw[6]>>4 != 7 {
+		return 0, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "TimeRange: %s is not a version 7 UUID", id.String())
+	}
+
+	return int64(binary.BigEndian.Uint64(append([]byte{0, 0}, raw[:6]...))), nil
+}
+
+// crockfordBase32 is the alphabet of ULIDs.
+const crockfordBase32 = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ulidTimestamp returns the timestamp of a ULID, given as its 26 character
+// text encoding or as 16 bytes.
+func ulidTimestamp(id sqltypes.Value) (int64, error) {
+	raw := id.Raw()
+	if len(raw) == 16 {
+		return int64(binary.BigEndian.Uint64(append([]byte{0, 0}, raw[:6]...))), nil
+	}
+
+	if len(raw) != 26 {
+		return 0, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "TimeRange: invalid ULID %s", id.String())
+	}
+
+	// The first 10 characters encode the 48 bit timestamp.
+	var ms int64
+	for _, c := range strings.ToUpper(string(raw[:10])) {
+		v := strings.IndexRune(crockfordBase32, c)
+		if v < 0 {
+			return 0, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "TimeRange: invalid ULID %s", id.String())
+		}
+		ms = ms<<5 | int64(v)
+	}
+
+	if ms >= 1<<48 {
+		return 0, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "TimeRange: invalid ULID %s", id.String())
+	}
+
+	return ms, nil
+}
@@ -0,0 +1,344 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vindexes
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/key"
+	"vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+const timeRangeTestJSON = `{"2024-01-01": "40", "2024-02-01": "80", "2024-03-01 12:00:00": "c0"}`
+
+func timeRangeCreateVindexTestCase(
+	testName string,
+	vindexParams map[string]string,
+	expectErr error,
+	expectUnknownParams []string,
+) createVindexTestCase {
+	return createVindexTestCase{
+		testName: testName,
+
+		vindexType:   "time_range",
+		vindexName:   "time_range",
+		vindexParams: vindexParams,
+
+		expectCost:          1,
+		expectErr:           expectErr,
+		expectIsUnique:      true,
+		expectNeedsVCursor:  false,
+		expectString:        "time_range",
+		expectUnknownParams: expectUnknownParams,
+	}
+}
+
+func TestTimeRangeCreateVindex(t *testing.T) {
+	cases := []createVindexTestCase{
+		timeRangeCreateVindexTestCase(
+			"no params invalid, require either json_path or json",
+			nil,
+			vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "TimeRange: Could not find either `json_path` or `json` params in vschema"),
+			nil,
+		),
+		timeRangeCreateVindexTestCase(
+			"json_path and json mutually exclusive",
+			map[string]string{
+				"json":      timeRangeTestJSON,
+				"json_path": "/path/to/map.json",
+			},
+			vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "TimeRange: Found both `json` and `json_path` params in vschema"),
+			nil,
+		),
+		timeRangeCreateVindexTestCase(
+			"json_path must exist",
+			map[string]string{
+				"json_path": "/path/to/map.json",
+			},
+			errors.New("open /path/to/map.json: no such file or directory"),
+			nil,
+		),
+		timeRangeCreateVindexTestCase(
+			"json ok",
+			map[string]string{
+				"json": timeRangeTestJSON,
+			},
+			nil,
+			nil,
+		),
+		timeRangeCreateVindexTestCase(
+			"mapping must not be empty",
+			map[string]string{
+				"json": "{}",
+			},
+			vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "TimeRange: the mapping must have at least one range"),
+			nil,
+		),
+		timeRangeCreateVindexTestCase(
+			"range start must be a date",
+			map[string]string{
+				"json": `{"soon": "40"}`,
+			},
+			vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "TimeRange: invalid range start \"soon\""),
+			nil,
+		),
+		timeRangeCreateVindexTestCase(
+			"prefix must be hex",
+			map[string]string{
+				"json": `{"2024-01-01": "zz"}`,
+			},
+			vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "TimeRange: invalid keyspace id prefix \"zz\" for 2024-01-01"),
+			nil,
+		),
+		timeRangeCreateVindexTestCase(
+			"prefixes must have the same length",
+			map[string]string{
+				"json": `{"2024-01-01": "40", "2024-02-01": "8000"}`,
+			},
+			vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "TimeRange: keyspace id prefixes must all have the same length"),
+			nil,
+		),
+		timeRangeCreateVindexTestCase(
+			"prefixes must increase",
+			map[string]string{
+				"json": `{"2024-01-01": "80", "2024-02-01": "40"}`,
+			},
+			vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "TimeRange: keyspace id prefixes must increase with the range start, got 40 after 80"),
+			nil,
+		),
+		timeRangeCreateVindexTestCase(
+			"id_type ok",
+			map[string]string{
+				"json":    timeRangeTestJSON,
+				"id_type": "uuidv7",
+			},
+			nil,
+			nil,
+		),
+		timeRangeCreateVindexTestCase(
+			"id_type must be valid",
+			map[string]string{
+				"json":    timeRangeTestJSON,
+				"id_type": "uuidv4",
+			},
+			vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "TimeRange: invalid id_type \"uuidv4\", must be one of datetime, uuidv7 or ulid"),
+			nil,
+		),
+		timeRangeCreateVindexTestCase(
+			"unknown params",
+			map[string]string{
+				"json":  timeRangeTestJSON,
+				"hello": "world",
+			},
+			nil,
+			[]string{"hello"},
+		),
+	}
+
+	testCreateVindexes(t, cases)
+}
+
+func createTimeRange(t *testing.T, idType string) *TimeRange {
+	vindex, err := CreateVindex("time_range", "time_range", map[string]string{
+		"json":    timeRangeTestJSON,
+		"id_type": idType,
+	})
+	require.NoError(t, err)
+	return vindex.(*TimeRange)
+}
+
+// timeRangeKsid returns the expected keyspace id for the time t in the range
+// with the given prefix.
+func timeRangeKsid(prefix byte, t string) []byte {
+	ts, err := time.Parse(time.DateTime, t)
+	if err != nil {
+		panic(err)
+	}
+	ksid := make([]byte, 9)
+	ksid[0] = prefix
+	binary.BigEndian.PutUint64(ksid[1:], uint64(ts.UnixMilli())^(1<<63))
+	return ksid
+}
+
+func TestTimeRangeMap(t *testing.T) {
+	vindex := createTimeRange(t, "datetime")
+
+	got, err := vindex.Map(context.Background(), nil, []sqltypes.Value{
+		sqltypes.MakeTrusted(sqltypes.Datetime, []byte("2024-01-15 10:00:00")),
+		sqltypes.NewVarChar("2024-02-01"),
+		sqltypes.MakeTrusted(sqltypes.Date, []byte("2024-03-01")),
+		sqltypes.NewVarChar("2024-03-01 12:00:00.000"),
+		sqltypes.NewVarChar("2023-12-31 23:59:59"),
+		sqltypes.NewInt64(1),
+		sqltypes.NULL,
+	})
+	require.NoError(t, err)
+
+	want := []key.Destination{
+		key.DestinationKeyspaceID(timeRangeKsid(0x40, "2024-01-15 10:00:00")),
+		key.DestinationKeyspaceID(timeRangeKsid(0x80, "2024-02-01 00:00:00")),
+		key.DestinationKeyspaceID(timeRangeKsid(0x80, "2024-03-01 00:00:00")),
+		key.DestinationKeyspaceID(timeRangeKsid(0xc0, "2024-03-01 12:00:00")),
+		key.DestinationNone{},
+		key.DestinationNone{},
+		key.DestinationNone{},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestTimeRangeVerify(t *testing.T) {
+	vindex := createTimeRange(t, "datetime")
+
+	got, err := vindex.Verify(context.Background(), nil,
+		[]sqltypes.Value{sqltypes.NewVarChar("2024-01-15 10:00:00"), sqltypes.NewVarChar("2024-02-15 10:00:00")},
+		[][]byte{timeRangeKsid(0x40, "2024-01-15 10:00:00"), timeRangeKsid(0x40, "2024-02-15 10:00:00")})
+	require.NoError(t, err)
+	assert.Equal(t, []bool{true, false}, got)
+
+	_, err = vindex.Verify(context.Background(), nil, []sqltypes.Value{sqltypes.NewVarChar("2023-01-01")}, [][]byte{nil})
+	require.EqualError(t, err, "TimeRange: 2023-01-01T00:00:00Z is earlier than the first range")
+}
+
+func TestTimeRangeUUIDv7(t *testing.T) {
+	vindex := createTimeRange(t, "uuidv7")
+
+	ts, err := time.Parse(time.DateTime, "2024-02-10 08:30:00")
+	require.NoError(t, err)
+	ms := fmt.Sprintf("%012x", ts.UnixMilli())
+	text := ms[:8] + "-" + ms[8:] + "-7abc-8def-0123456789ab"
+	raw := make([]byte, 16)
+	binary.BigEndian.PutUint64(raw[:8], uint64(ts.UnixMilli())<<16|0x7abc)
+
+	want := timeRangeKsid(0x80, "2024-02-10 08:30:00")
+	for _, id := range []sqltypes.Value{
+		sqltypes.NewVarChar(text),
+		sqltypes.NewVarChar(ms + "7abc8def0123456789ab"),
+		sqltypes.NewVarBinary(string(raw)),
+	} {
+		ksid, err := vindex.Hash(id)
+		require.NoError(t, err, id.String())
+		assert.Equal(t, want, ksid, id.String())
+	}
+
+	_, err = vindex.Hash(sqltypes.NewVarChar(ms[:8] + "-" + ms[8:] + "-4abc-8def-0123456789ab"))
+	require.ErrorContains(t, err, "is not a version 7 UUID")
+
+	_, err = vindex.Hash(sqltypes.NewVarChar("not-a-uuid"))
+	require.ErrorContains(t, err, "invalid UUID")
+}
+
+func TestTimeRangeULID(t *testing.T) {
+	vindex := createTimeRange(t, "ulid")
+
+	ts, err := time.Parse(time.DateTime, "2024-03-05 01:02:03")
+	require.NoError(t, err)
+	ms := uint64(ts.UnixMilli())
+
+	var text []byte
+	for i := 9; i >= 0; i-- {
+		text = append(text, crockfordBase32[(ms>>(5*i))&31])
+	}
+	text = append(text, "ABCDEFGHJKMNPQRS"...)
+	raw := make([]byte, 16)
+	binary.BigEndian.PutUint64(raw[:8], ms<<16)
+
+	want := timeRangeKsid(0xc0, "2024-03-05 01:02:03")
+	for _, id := range []sqltypes.Value{
+		sqltypes.NewVarChar(string(text)),
+		sqltypes.NewVarBinary(string(raw)),
+	} {
+		ksid, err := vindex.Hash(id)
+		require.NoError(t, err, id.String())
+		assert.Equal(t, want, ksid, id.String())
+	}
+
+	_, err = vindex.Hash(sqltypes.NewVarChar("UUUUUUUUUUUUUUUUUUUUUUUUUU"))
+	require.ErrorContains(t, err, "invalid ULID")
+}
+
+func TestTimeRangeRangeMap(t *testing.T) {
+	vindex := createTimeRange(t, "datetime")
+
+	tcases := []struct {
+		name       string
+		start, end sqltypes.Value
+		want       key.Destination
+	}{{
+		name:  "bounded",
+		start: sqltypes.NewVarChar("2024-01-15 00:00:00"),
+		end:   sqltypes.NewVarChar("2024-02-15 00:00:00"),
+		want: key.DestinationKeyRange{KeyRange: &topodatapb.KeyRange{
+			Start: timeRangeKsid(0x40, "2024-01-15 00:00:00"),
+			End:   append(timeRangeKsid(0x80, "2024-02-15 00:00:00"), 0),
+		}},
+	}, {
+		name:  "unbounded start",
+		start: sqltypes.NULL,
+		end:   sqltypes.NewVarChar("2024-01-15 00:00:00"),
+		want: key.DestinationKeyRange{KeyRange: &topodatapb.KeyRange{
+			End: append(timeRangeKsid(0x40, "2024-01-15 00:00:00"), 0),
+		}},
+	}, {
+		name:  "unbounded end",
+		start: sqltypes.NewVarChar("2024-03-02"),
+		end:   sqltypes.NULL,
+		want: key.DestinationKeyRange{KeyRange: &topodatapb.KeyRange{
+			Start: timeRangeKsid(0xc0, "2024-03-02 00:00:00"),
+		}},
+	}, {
+		name:  "start before the first range",
+		start: sqltypes.NewVarChar("2020-01-01"),
+		end:   sqltypes.NewVarChar("2024-01-15 00:00:00"),
+		want: key.DestinationKeyRange{KeyRange: &topodatapb.KeyRange{
+			Start: timeRangeKsid(0x40, "2024-01-01 00:00:00"),
+			End:   append(timeRangeKsid(0x40, "2024-01-15 00:00:00"), 0),
+		}},
+	}, {
+		name:  "end before the first range",
+		start: sqltypes.NewVarChar("2020-01-01"),
+		end:   sqltypes.NewVarChar("2021-01-01"),
+		want:  key.DestinationNone{},
+	}, {
+		name:  "empty range",
+		start: sqltypes.NewVarChar("2024-02-15"),
+		end:   sqltypes.NewVarChar("2024-01-15"),
+		want:  key.DestinationNone{},
+	}}
+
+	for _, tcase := range tcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			got, err := vindex.RangeMap(context.Background(), nil, tcase.start, tcase.end)
+			require.NoError(t, err)
+			assert.Equal(t, tcase.want, got)
+		})
+	}
+
+	_, err := vindex.RangeMap(context.Background(), nil, sqltypes.NewInt64(1), sqltypes.NULL)
+	require.EqualError(t, err, "TimeRange: cannot map INT64(1) to a time")
+}
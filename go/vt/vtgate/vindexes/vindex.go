@@ -17,6 +17,7 @@ limitations under the License.
 package vindexes
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"sort"
@@ -29,6 +30,7 @@ import (
 	"vitess.io/vitess/go/vt/vterrors"
 
 	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
 	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 )
@@ -120,6 +122,19 @@ type (
 	Hashing interface {
 		Hash(id sqltypes.Value) ([]byte, error)
 	}
+	// A Sequential vindex is one whose keyspace ids are ordered like its ids,
+	// or, like cfc, share a prefix for ids that share one, so that a range of
+	// ids can be mapped to a key range. This allows range
+	// predicates (e.g. BETWEEN) on the vindex column to be routed to the
+	// shards covering the range, instead of scattering.
+	Sequential interface {
+		SingleColumn
+		// RangeMap returns the destination of the ids in the inclusive range
+		// [start, end]. A NULL start or end means that the range is unbounded
+		// on that side.
+		RangeMap(ctx context.Context, vcursor VCursor, start, end sqltypes.Value) (key.Destination, error)
+	}
+
 	// A Reversible vindex is one that can perform a
 	// reverse lookup from a keyspace id to an id. This
 	// is optional. If present, VTGate can use it to
@@ -253,3 +268,17 @@ func FindUnknownParams(params map[string]string, knownParams []string) []string
 	sort.Strings(unknownParams)
 	return unknownParams
 }
+
+// keyRangeBetween returns the destination of the keyspace ids in the inclusive
+// range [start, end]. A nil start or end means that the range is unbounded on
+// that side.
+func keyRangeBetween(start, end []byte) key.Destination {
+	if end != nil {
+		// The end of a key range is exclusive, so it ends right after end.
+		end = append(end[:len(end):len(end)], 0)
+		if start != nil && bytes.Compare(start, end) >= 0 {
+			return key.DestinationNone{}
+		}
+	}
+	return key.DestinationKeyRange{KeyRange: &topodatapb.KeyRange{Start: start, End: end}}
+}
@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -58,6 +59,12 @@ const (
 	TypeReference = "reference"
 )
 
+// The following constants represent the types of sequence allocators.
+const (
+	SequenceInterleaved = "interleaved"
+	SequenceSnowflake   = "snowflake"
+)
+
 // VSchema represents the denormalized version of SrvVSchema,
 // used for building routing plans.
 type VSchema struct {
@@ -116,6 +123,9 @@ type Table struct {
 	// Source is a keyspace-qualified table name that points to the source of a
 	// reference table. Only applicable for tables with Type set to "reference".
 	Source *Source `json:"source,omitempty"`
+	// SequenceAllocator is set for sequence tables that are spread over the
+	// shards of a keyspace. Only applicable for tables with Type set to "sequence".
+	SequenceAllocator *SequenceAllocator `json:"sequence_allocator,omitempty"`
 
 	ChildForeignKeys  []ChildFKInfo  `json:"child_foreign_keys,omitempty"`
 	ParentForeignKeys []ParentFKInfo `json:"parent_foreign_keys,omitempty"`
@@ -296,6 +306,14 @@ type AutoIncrement struct {
 	Sequence *Table                 `json:"sequence"`
 }
 
+// SequenceAllocator contains the configuration of a sequence table that is
+// spread over the shards of a keyspace.
+type SequenceAllocator struct {
+	Type       string            `json:"type"`
+	Epoch      int64             `json:"epoch,omitempty"`
+	CellShards map[string]string `json:"cell_shards,omitempty"`
+}
+
 type Source struct {
 	sqlparser.TableName
 }
@@ -597,7 +615,13 @@ func buildTables(ks *vschemapb.Keyspace, vschema *VSchema, ksvschema *KeyspaceSc
 			}
 			t.Type = table.Type
 		case TypeSequence:
-			if keyspace.Sharded && table.Pinned == "" {
+			if table.SequenceAllocator != nil {
+				allocator, err := buildSequenceAllocator(tname, table)
+				if err != nil {
+					return err
+				}
+				t.SequenceAllocator = allocator
+			} else if keyspace.Sharded && table.Pinned == "" {
 				return vterrors.Errorf(
 					vtrpcpb.Code_FAILED_PRECONDITION,
 					"sequence table has to be in an unsharded keyspace or must be pinned: %s",
@@ -612,6 +636,13 @@ func buildTables(ks *vschemapb.Keyspace, vschema *VSchema, ksvschema *KeyspaceSc
 				table.Type,
 			)
 		}
+		if table.SequenceAllocator != nil && t.Type != TypeSequence {
+			return vterrors.Errorf(
+				vtrpcpb.Code_INVALID_ARGUMENT,
+				"sequence allocator is only allowed for sequence tables: %s",
+				tname,
+			)
+		}
 		if table.Pinned != "" {
 			decoded, err := hex.DecodeString(table.Pinned)
 			if err != nil {
@@ -624,8 +655,9 @@ func buildTables(ks *vschemapb.Keyspace, vschema *VSchema, ksvschema *KeyspaceSc
 			t.Pinned = decoded
 		}
 
-		// If keyspace is sharded, then any table that's not a reference or pinned must have vindexes.
-		if keyspace.Sharded && t.Type != TypeReference && table.Pinned == "" && len(table.ColumnVindexes) == 0 {
+		// If keyspace is sharded, then any table that's not a reference, pinned or
+		// a sharded sequence must have vindexes.
+		if keyspace.Sharded && t.Type != TypeReference && table.Pinned == "" && t.SequenceAllocator == nil && len(table.ColumnVindexes) == 0 {
 			return vterrors.Errorf(
 				vtrpcpb.Code_NOT_FOUND,
 				"missing primary col vindex for table: %s",
@@ -789,6 +821,34 @@ func buildTables(ks *vschemapb.Keyspace, vschema *VSchema, ksvschema *KeyspaceSc
 				t.ColumnVindexes = append(t.ColumnVindexes, columnVindex)
 			}
 		}
+		// Initialize the hidden vindexes of the unique constraints.
+		if len(table.UniqueConstraints) > 0 && !keyspace.Sharded {
+			return vterrors.Errorf(
+				vtrpcpb.Code_INVALID_ARGUMENT,
+				"unique constraints are only supported in sharded keyspaces, table %s is in %s",
+				tname,
+				keyspace.Name,
+			)
+		}
+		for _, uc := range table.UniqueConstraints {
+			columnVindex, err := buildUniqueConstraint(keyspace.Name, tname, uc, ks)
+			if err != nil {
+				return err
+			}
+			if slices.ContainsFunc(t.ColumnVindexes, func(cv *ColumnVindex) bool { return cv.Name == columnVindex.Name }) {
+				return vterrors.Errorf(
+					vtrpcpb.Code_INVALID_ARGUMENT,
+					"duplicate unique constraint %s for table %s",
+					uc.Name,
+					tname,
+				)
+			}
+			if err := columnVindex.Vindex.(WantOwnerInfo).SetOwnerInfo(keyspace.Name, tname, columnVindex.Columns); err != nil {
+				return err
+			}
+			t.ColumnVindexes = append(t.ColumnVindexes, columnVindex)
+			t.Owned = append(t.Owned, columnVindex)
+		}
 		t.Ordered = colVindexSorted(t.ColumnVindexes)
 
 		// Add the table to the map entries.
@@ -807,6 +867,124 @@ func (vschema *VSchema) addTableName(t *Table) {
 	}
 }
 
+func buildSequenceAllocator(tname string, table *vschemapb.Table) (*SequenceAllocator, error) {
+	if table.Pinned != "" {
+		return nil, vterrors.Errorf(
+			vtrpcpb.Code_INVALID_ARGUMENT,
+			"sequence table cannot both be pinned and have a sequence allocator: %s",
+			tname,
+		)
+	}
+	switch table.SequenceAllocator.Type {
+	case SequenceInterleaved, SequenceSnowflake:
+	default:
+		return nil, vterrors.Errorf(
+			vtrpcpb.Code_INVALID_ARGUMENT,
+			"invalid sequence allocator type %q for table %s, must be %s or %s",
+			table.SequenceAllocator.Type,
+			tname,
+			SequenceInterleaved,
+			SequenceSnowflake,
+		)
+	}
+	if table.SequenceAllocator.Epoch < 0 {
+		return nil, vterrors.Errorf(
+			vtrpcpb.Code_INVALID_ARGUMENT,
+			"invalid sequence allocator epoch %d for table %s",
+			table.SequenceAllocator.Epoch,
+			tname,
+		)
+	}
+	return &SequenceAllocator{
+		Type:       table.SequenceAllocator.Type,
+		Epoch:      table.SequenceAllocator.Epoch,
+		CellShards: table.SequenceAllocator.CellShards,
+	}, nil
+}
+
+// UniqueConstraintVindex returns the name and the definition of the hidden
+// consistent lookup vindex that enforces the unique constraint uc of the
+// table tname in keyspace. A single column constraint is backed by a
+// consistent_lookup_unique vindex, and a multi-column one by a
+// consistent_lookup vindex whose lookup table is keyed by all the columns.
+// The latter is not unique, because queries on the first column alone are
+// routed through it: it relies on the primary key of the lookup table to
+// reject duplicates, which the vtctld checks when creating the constraint.
+func UniqueConstraintVindex(keyspace, tname string, uc *vschemapb.UniqueConstraint) (string, *vschemapb.Vindex, error) {
+	if uc.Name == "" {
+		return "", nil, vterrors.Errorf(
+			vtrpcpb.Code_INVALID_ARGUMENT,
+			"missing name for unique constraint of table %s",
+			tname,
+		)
+	}
+	if len(uc.Columns) == 0 {
+		return "", nil, vterrors.Errorf(
+			vtrpcpb.Code_INVALID_ARGUMENT,
+			"must specify at least one column for unique constraint %s of table %s",
+			uc.Name,
+			tname,
+		)
+	}
+	name := fmt.Sprintf("%s_%s_uniq", tname, uc.Name)
+	lookupTable := uc.LookupTable
+	if lookupTable == "" {
+		lookupTable = keyspace + "." + name
+	}
+	vindexType := "consistent_lookup_unique"
+	if len(uc.Columns) > 1 {
+		vindexType = "consistent_lookup"
+	}
+	params := map[string]string{
+		"table":        lookupTable,
+		"from":         strings.Join(uc.Columns, ","),
+		"to":           "keyspace_id",
+		"ignore_nulls": "true",
+	}
+	if uc.WriteOnly {
+		params["write_only"] = "true"
+	}
+	return name, &vschemapb.Vindex{
+		Type:   vindexType,
+		Params: params,
+		Owner:  tname,
+	}, nil
+}
+
+func buildUniqueConstraint(keyspace, tname string, uc *vschemapb.UniqueConstraint, ks *vschemapb.Keyspace) (*ColumnVindex, error) {
+	name, vindexInfo, err := UniqueConstraintVindex(keyspace, tname, uc)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := ks.Vindexes[name]; ok {
+		return nil, vterrors.Errorf(
+			vtrpcpb.Code_INVALID_ARGUMENT,
+			"vindex %s of unique constraint %s conflicts with an existing vindex for table %s",
+			name,
+			uc.Name,
+			tname,
+		)
+	}
+	vindex, err := CreateVindex(vindexInfo.Type, name, vindexInfo.Params)
+	if err != nil {
+		return nil, err
+	}
+	columns := make([]sqlparser.IdentifierCI, 0, len(uc.Columns))
+	for _, col := range uc.Columns {
+		columns = append(columns, sqlparser.NewIdentifierCI(col))
+	}
+	return &ColumnVindex{
+		Columns:  columns,
+		Type:     vindexInfo.Type,
+		Name:     name,
+		Owned:    true,
+		Vindex:   vindex,
+		isUnique: vindex.IsUnique(),
+		cost:     vindex.Cost(),
+		backfill: vindex.(LookupBackfill).IsBackfilling(),
+	}, nil
+}
+
 func resolveAutoIncrement(source *vschemapb.SrvVSchema, vschema *VSchema, parser *sqlparser.Parser) {
 	for ksname, ks := range source.Keyspaces {
 		ksvschema := vschema.Keyspaces[ksname]
@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
 	"reflect"
 	"strings"
 	"testing"
@@ -2273,6 +2274,180 @@ func TestBadShardedSequence(t *testing.T) {
 	}
 }
 
+func TestShardedSequenceAllocator(t *testing.T) {
+	input := vschemapb.SrvVSchema{
+		Keyspaces: map[string]*vschemapb.Keyspace{
+			"sharded": {
+				Sharded: true,
+				Tables: map[string]*vschemapb.Table{
+					"seq": {
+						Type: "sequence",
+						SequenceAllocator: &vschemapb.SequenceAllocator{
+							Type:       "snowflake",
+							CellShards: map[string]string{"zone1": "-80"},
+						},
+					},
+				},
+			},
+		},
+	}
+	got := BuildVSchema(&input, sqlparser.NewTestParser())
+	require.NoError(t, got.Keyspaces["sharded"].Error)
+	seq := got.Keyspaces["sharded"].Tables["seq"]
+	require.NotNil(t, seq)
+	assert.Equal(t, &SequenceAllocator{Type: "snowflake", CellShards: map[string]string{"zone1": "-80"}}, seq.SequenceAllocator)
+
+	tcases := []struct {
+		name  string
+		table *vschemapb.Table
+		want  string
+	}{{
+		name: "invalid type",
+		table: &vschemapb.Table{
+			Type:              "sequence",
+			SequenceAllocator: &vschemapb.SequenceAllocator{Type: "random"},
+		},
+		want: `invalid sequence allocator type "random" for table t1, must be interleaved or snowflake`,
+	}, {
+		name: "pinned",
+		table: &vschemapb.Table{
+			Type:              "sequence",
+			Pinned:            "80",
+			SequenceAllocator: &vschemapb.SequenceAllocator{Type: "interleaved"},
+		},
+		want: "sequence table cannot both be pinned and have a sequence allocator: t1",
+	}, {
+		name: "not a sequence",
+		table: &vschemapb.Table{
+			Type:              "reference",
+			SequenceAllocator: &vschemapb.SequenceAllocator{Type: "interleaved"},
+		},
+		want: "sequence allocator is only allowed for sequence tables: t1",
+	}}
+	for _, tcase := range tcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			bad := vschemapb.SrvVSchema{
+				Keyspaces: map[string]*vschemapb.Keyspace{
+					"sharded": {
+						Sharded: true,
+						Tables:  map[string]*vschemapb.Table{"t1": tcase.table},
+					},
+				},
+			}
+			got := BuildVSchema(&bad, sqlparser.NewTestParser())
+			require.EqualError(t, got.Keyspaces["sharded"].Error, tcase.want)
+		})
+	}
+}
+
+func TestUniqueConstraints(t *testing.T) {
+	primary := []*vschemapb.ColumnVindex{{Column: "id", Name: "hash"}}
+	input := vschemapb.SrvVSchema{
+		Keyspaces: map[string]*vschemapb.Keyspace{
+			"sharded": {
+				Sharded:  true,
+				Vindexes: map[string]*vschemapb.Vindex{"hash": {Type: "hash"}},
+				Tables: map[string]*vschemapb.Table{
+					"user": {
+						ColumnVindexes: primary,
+						UniqueConstraints: []*vschemapb.UniqueConstraint{{
+							Name:    "email",
+							Columns: []string{"email"},
+						}, {
+							Name:        "name",
+							Columns:     []string{"first_name", "last_name"},
+							LookupTable: "lookup.user_name",
+							WriteOnly:   true,
+						}},
+					},
+				},
+			},
+		},
+	}
+	got := BuildVSchema(&input, sqlparser.NewTestParser())
+	require.NoError(t, got.Keyspaces["sharded"].Error)
+	user := got.Keyspaces["sharded"].Tables["user"]
+	require.Len(t, user.ColumnVindexes, 3)
+	require.Len(t, user.Owned, 2)
+	// The vindexes of the constraints are not visible in the keyspace.
+	assert.NotContains(t, got.Keyspaces["sharded"].Vindexes, "user_email_uniq")
+
+	email := user.Owned[0]
+	assert.Equal(t, "user_email_uniq", email.Name)
+	assert.Equal(t, "consistent_lookup_unique", email.Type)
+	assert.True(t, email.IsUnique())
+	assert.False(t, email.IsBackfilling())
+	assert.Equal(t, []sqlparser.IdentifierCI{sqlparser.NewIdentifierCI("email")}, email.Columns)
+	assert.Equal(t, "sharded.user_email_uniq", email.Vindex.(*ConsistentLookupUnique).lkp.Table)
+	assert.True(t, email.Vindex.(*ConsistentLookupUnique).lkp.IgnoreNulls)
+
+	name := user.Owned[1]
+	assert.Equal(t, "user_name_uniq", name.Name)
+	assert.Equal(t, "consistent_lookup", name.Type)
+	assert.True(t, name.IsBackfilling())
+	assert.Equal(t, "lookup.user_name", name.Vindex.(*ConsistentLookup).lkp.Table)
+	assert.Equal(t, []string{"first_name", "last_name"}, name.Vindex.(*ConsistentLookup).lkp.FromColumns)
+
+	tcases := []struct {
+		name     string
+		sharded  bool
+		vindexes map[string]*vschemapb.Vindex
+		ucs      []*vschemapb.UniqueConstraint
+		want     string
+	}{{
+		name:    "missing name",
+		sharded: true,
+		ucs:     []*vschemapb.UniqueConstraint{{Columns: []string{"email"}}},
+		want:    "missing name for unique constraint of table t1",
+	}, {
+		name:    "missing columns",
+		sharded: true,
+		ucs:     []*vschemapb.UniqueConstraint{{Name: "email"}},
+		want:    "must specify at least one column for unique constraint email of table t1",
+	}, {
+		name:    "duplicate",
+		sharded: true,
+		ucs: []*vschemapb.UniqueConstraint{
+			{Name: "email", Columns: []string{"email"}},
+			{Name: "email", Columns: []string{"email2"}},
+		},
+		want: "duplicate unique constraint email for table t1",
+	}, {
+		name:    "conflicting vindex",
+		sharded: true,
+		vindexes: map[string]*vschemapb.Vindex{
+			"t1_email_uniq": {Type: "hash"},
+		},
+		ucs:  []*vschemapb.UniqueConstraint{{Name: "email", Columns: []string{"email"}}},
+		want: "vindex t1_email_uniq of unique constraint email conflicts with an existing vindex for table t1",
+	}, {
+		name: "unsharded",
+		ucs:  []*vschemapb.UniqueConstraint{{Name: "email", Columns: []string{"email"}}},
+		want: "unique constraints are only supported in sharded keyspaces, table t1 is in ks",
+	}}
+	for _, tcase := range tcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			vindexes := map[string]*vschemapb.Vindex{"hash": {Type: "hash"}}
+			maps.Copy(vindexes, tcase.vindexes)
+			table := &vschemapb.Table{UniqueConstraints: tcase.ucs}
+			if tcase.sharded {
+				table.ColumnVindexes = primary
+			}
+			bad := vschemapb.SrvVSchema{
+				Keyspaces: map[string]*vschemapb.Keyspace{
+					"ks": {
+						Sharded:  tcase.sharded,
+						Vindexes: vindexes,
+						Tables:   map[string]*vschemapb.Table{"t1": table},
+					},
+				},
+			}
+			got := BuildVSchema(&bad, sqlparser.NewTestParser())
+			require.EqualError(t, got.Keyspaces["ks"].Error, tcase.want)
+		})
+	}
+}
+
 func TestFindTable(t *testing.T) {
 	input := vschemapb.SrvVSchema{
 		Keyspaces: map[string]*vschemapb.Keyspace{
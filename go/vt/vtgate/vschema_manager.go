@@ -46,6 +46,7 @@ type VSchemaManager struct {
 	subscriber        func(vschema *vindexes.VSchema, stats *VSchemaStats)
 	schema            SchemaInfo
 	parser            *sqlparser.Parser
+	tenantQuotas      *tenantQuotas
 }
 
 // SchemaInfo is an interface to schema tracker.
@@ -139,6 +140,7 @@ func (vm *VSchemaManager) VSchemaUpdate(v *vschemapb.SrvVSchema, err error) bool
 	} else {
 		vschema = vm.buildAndEnhanceVSchema(v)
 		vm.currentVschema = vschema
+		vm.tenantQuotas.update(v.TenantQuotas)
 	}
 
 	if vm.subscriber != nil {
@@ -825,3 +825,21 @@ func (f *fakeSchema) Views(string) map[string]sqlparser.SelectStatement {
 }
 
 var _ SchemaInfo = (*fakeSchema)(nil)
+
+func TestVSchemaUpdateTenantQuotas(t *testing.T) {
+	vm := &VSchemaManager{tenantQuotas: newTenantQuotas()}
+	vm.VSchemaUpdate(&vschemapb.SrvVSchema{
+		TenantQuotas: &vschemapb.TenantQuotas{
+			TenantKey: "tag",
+			DryRun:    true,
+			Quotas:    []*vschemapb.TenantQuota{{Tenant: "acme", Qps: 10}},
+		},
+	}, nil)
+	require.Equal(t, "tag", vm.tenantQuotas.tenantKey)
+	require.True(t, vm.tenantQuotas.dryRun)
+	require.Contains(t, vm.tenantQuotas.quotas, "acme")
+
+	// The quotas are cleared along with the tenant quotas of the SrvVSchema.
+	vm.VSchemaUpdate(&vschemapb.SrvVSchema{}, nil)
+	require.Empty(t, vm.tenantQuotas.quotas)
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vstreamclient
+
+import (
+	"time"
+
+	"vitess.io/vitess/go/sqltypes"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+// ChangeType is the type of a row change.
+type ChangeType int
+
+// ChangeType values.
+const (
+	ChangeInsert ChangeType = iota
+	ChangeUpdate
+	ChangeDelete
+)
+
+// String is part of the fmt.Stringer interface.
+func (t ChangeType) String() string {
+	switch t {
+	case ChangeInsert:
+		return "insert"
+	case ChangeUpdate:
+		return "update"
+	case ChangeDelete:
+		return "delete"
+	default:
+		return "unknown"
+	}
+}
+
+// Change is a change to a single row, as delivered to a ChangeHandler.
+type Change struct {
+	Keyspace string
+	Shard    string
+	Table    string
+	Type     ChangeType
+	// Before is the row before the change. It is nil for inserts.
+	Before *Row
+	// After is the row after the change. It is nil for deletes.
+	After *Row
+	// Timestamp is the time of the binlog event, with a precision of one
+	// second. It is zero for rows delivered by the copy phase.
+	Timestamp time.Time
+	// Copy is true if the row was delivered by the copy phase, which streams
+	// the rows that existed before the consumer first started, as inserts.
+	Copy bool
+}
+
+// Row is a row image, decoded according to the fields of its table.
+type Row struct {
+	Fields []*querypb.Field
+	Values []sqltypes.Value
+}
+
+func newRow(fields []*querypb.Field, row *querypb.Row) *Row {
+	if row == nil {
+		return nil
+	}
+
+	return &Row{
+		Fields: fields,
+		Values: sqltypes.MakeRowTrusted(fields, row),
+	}
+}
+
+// Value returns the value of the given column, and whether the row has such a
+// column.
+func (r *Row) Value(column string) (sqltypes.Value, bool) {
+	for i, field := range r.Fields {
+		if field.Name == column && i < len(r.Values) {
+			return r.Values[i], true
+		}
+	}
+
+	return sqltypes.Value{}, false
+}
+
+// Map returns the row as a map of column names to values.
+func (r *Row) Map() map[string]sqltypes.Value {
+	m := make(map[string]sqltypes.Value, len(r.Fields))
+	for i, field := range r.Fields {
+		if i < len(r.Values) {
+			m[field.Name] = r.Values[i]
+		}
+	}
+
+	return m
+}
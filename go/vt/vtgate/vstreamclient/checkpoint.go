@@ -0,0 +1,247 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vstreamclient
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"vitess.io/vitess/go/sqlescape"
+	"vitess.io/vitess/go/vt/topo"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+)
+
+// CheckpointStore persists the position of a consumer, so that it can resume
+// where it left off after a restart.
+type CheckpointStore interface {
+	// Load returns the last position saved for the named consumer, or nil if
+	// there is none.
+	Load(ctx context.Context, name string) (*binlogdatapb.VGtid, error)
+	// Save saves the position of the named consumer.
+	Save(ctx context.Context, name string, vgtid *binlogdatapb.VGtid) error
+}
+
+func validateCheckpointName(name string) error {
+	if name == "" || strings.ContainsAny(name, "/\\") || name == "." || name == ".." {
+		return fmt.Errorf("invalid checkpoint name %q", name)
+	}
+
+	return nil
+}
+
+// FileCheckpointStore is a CheckpointStore that saves each position as a JSON
+// file in a directory. Files are replaced atomically.
+type FileCheckpointStore struct {
+	dir string
+}
+
+// NewFileCheckpointStore returns a FileCheckpointStore that saves positions in
+// the given directory, creating it if needed.
+func NewFileCheckpointStore(dir string) (*FileCheckpointStore, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, err
+	}
+
+	return &FileCheckpointStore{dir: dir}, nil
+}
+
+func (s *FileCheckpointStore) path(name string) string {
+	return filepath.Join(s.dir, name+".json")
+}
+
+// Load is part of the CheckpointStore interface.
+func (s *FileCheckpointStore) Load(ctx context.Context, name string) (*binlogdatapb.VGtid, error) {
+	if err := validateCheckpointName(name); err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(s.path(name))
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+
+	return unmarshalVGtid(data)
+}
+
+// Save is part of the CheckpointStore interface.
+func (s *FileCheckpointStore) Save(ctx context.Context, name string, vgtid *binlogdatapb.VGtid) error {
+	if err := validateCheckpointName(name); err != nil {
+		return err
+	}
+
+	data, err := protojson.Marshal(vgtid)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(s.dir, name+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), s.path(name))
+}
+
+// SQLCheckpointStore is a CheckpointStore that saves positions in a MySQL
+// table, one row per consumer. The table is created if it does not exist.
+//
+// Storing the position in the same database as the data written by the
+// handlers allows both to be updated in the same transaction.
+type SQLCheckpointStore struct {
+	db    *sql.DB
+	table string
+}
+
+// NewSQLCheckpointStore returns a SQLCheckpointStore that saves positions in
+// the given table, creating it if it does not exist.
+func NewSQLCheckpointStore(ctx context.Context, db *sql.DB, table string) (*SQLCheckpointStore, error) {
+	s := &SQLCheckpointStore{
+		db:    db,
+		table: sqlescape.EscapeID(table),
+	}
+
+	query := fmt.Sprintf(`create table if not exists %s (
+	name varbinary(255) not null,
+	vgtid json not null,
+	updated_at timestamp not null default current_timestamp on update current_timestamp,
+	primary key (name)
+)`, s.table)
+	if _, err := db.ExecContext(ctx, query); err != nil {
+		return nil, fmt.Errorf("failed to create checkpoint table %s: %w", s.table, err)
+	}
+
+	return s, nil
+}
+
+// Load is part of the CheckpointStore interface.
+func (s *SQLCheckpointStore) Load(ctx context.Context, name string) (*binlogdatapb.VGtid, error) {
+	var data []byte
+	err := s.db.QueryRowContext(ctx, fmt.Sprintf("select vgtid from %s where name = ?", s.table), name).Scan(&data)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+
+	return unmarshalVGtid(data)
+}
+
+// Save is part of the CheckpointStore interface.
+func (s *SQLCheckpointStore) Save(ctx context.Context, name string, vgtid *binlogdatapb.VGtid) error {
+	data, err := protojson.Marshal(vgtid)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf("insert into %s (name, vgtid) values (?, ?) on duplicate key update vgtid = values(vgtid)", s.table)
+	_, err = s.db.ExecContext(ctx, query, name, string(data))
+	return err
+}
+
+// TopoCheckpointStore is a CheckpointStore that saves positions in the global
+// topo server, under vstreamclient/<name>.
+type TopoCheckpointStore struct {
+	ts *topo.Server
+}
+
+// NewTopoCheckpointStore returns a TopoCheckpointStore backed by the given topo
+// server.
+func NewTopoCheckpointStore(ts *topo.Server) *TopoCheckpointStore {
+	return &TopoCheckpointStore{ts: ts}
+}
+
+const topoCheckpointDir = "vstreamclient"
+
+// Load is part of the CheckpointStore interface.
+func (s *TopoCheckpointStore) Load(ctx context.Context, name string) (*binlogdatapb.VGtid, error) {
+	if err := validateCheckpointName(name); err != nil {
+		return nil, err
+	}
+
+	conn, err := s.ts.ConnForCell(ctx, topo.GlobalCell)
+	if err != nil {
+		return nil, err
+	}
+
+	data, _, err := conn.Get(ctx, path.Join(topoCheckpointDir, name))
+	switch {
+	case topo.IsErrType(err, topo.NoNode):
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+
+	return unmarshalVGtid(data)
+}
+
+// Save is part of the CheckpointStore interface.
+func (s *TopoCheckpointStore) Save(ctx context.Context, name string, vgtid *binlogdatapb.VGtid) error {
+	if err := validateCheckpointName(name); err != nil {
+		return err
+	}
+
+	data, err := protojson.Marshal(vgtid)
+	if err != nil {
+		return err
+	}
+
+	conn, err := s.ts.ConnForCell(ctx, topo.GlobalCell)
+	if err != nil {
+		return err
+	}
+
+	// A nil version unconditionally creates or replaces the file.
+	_, err = conn.Update(ctx, path.Join(topoCheckpointDir, name), data, nil)
+	return err
+}
+
+func unmarshalVGtid(data []byte) (*binlogdatapb.VGtid, error) {
+	vgtid := &binlogdatapb.VGtid{}
+	if err := protojson.Unmarshal(data, vgtid); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal checkpoint: %w", err)
+	}
+
+	return vgtid, nil
+}
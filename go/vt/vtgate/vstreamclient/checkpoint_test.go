@@ -0,0 +1,84 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vstreamclient
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/test/utils"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+func testCheckpointStore(t *testing.T, store CheckpointStore) {
+	t.Helper()
+	ctx := context.Background()
+
+	vgtid, err := store.Load(ctx, "consumer")
+	require.NoError(t, err)
+	assert.Nil(t, vgtid)
+
+	want := &binlogdatapb.VGtid{ShardGtids: []*binlogdatapb.ShardGtid{{
+		Keyspace: "ks",
+		Shard:    "-80",
+		Gtid:     "MySQL56/00000000-0000-0000-0000-000000000000:1-10",
+		TablePKs: []*binlogdatapb.TableLastPK{{
+			TableName: "t",
+			Lastpk:    &querypb.QueryResult{Fields: []*querypb.Field{{Name: "id", Type: querypb.Type_INT64}}},
+		}},
+	}}}
+
+	for i := 0; i < 2; i++ {
+		require.NoError(t, store.Save(ctx, "consumer", want))
+
+		vgtid, err = store.Load(ctx, "consumer")
+		require.NoError(t, err)
+		utils.MustMatch(t, want, vgtid)
+
+		want.ShardGtids[0].Gtid = "MySQL56/00000000-0000-0000-0000-000000000000:1-20"
+	}
+
+	vgtid, err = store.Load(ctx, "other")
+	require.NoError(t, err)
+	assert.Nil(t, vgtid)
+
+	_, err = store.Load(ctx, "../consumer")
+	assert.Error(t, err)
+}
+
+func TestFileCheckpointStore(t *testing.T) {
+	store, err := NewFileCheckpointStore(t.TempDir())
+	require.NoError(t, err)
+
+	testCheckpointStore(t, store)
+}
+
+func TestTopoCheckpointStore(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ts := memorytopo.NewServer(ctx, "zone1")
+	defer ts.Close()
+
+	testCheckpointStore(t, NewTopoCheckpointStore(ts))
+}
@@ -0,0 +1,417 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package vstreamclient is a high-level consumer of vtgate VStreams, for
+// change data capture.
+//
+// A Consumer streams the changes to a set of tables of a keyspace, and calls a
+// handler for every changed row, with its before and after images decoded into
+// sqltypes.Values. It takes care of the parts of consuming a VStream that are
+// otherwise rewritten by every application:
+//
+//   - Bootstrapping: on its first run, a consumer streams the existing rows of
+//     its tables (the copy phase) before streaming changes, unless
+//     Config.SkipCopy is set.
+//   - Checkpointing: the position (VGtid) of the consumer is periodically saved
+//     to a CheckpointStore, and a consumer resumes from its last checkpoint,
+//     including in the middle of the copy phase.
+//   - Reshards: streams are not stopped on reshards; vtgate follows the
+//     journal to the new shards, and the consumer checkpoints the new shard
+//     positions.
+//   - Reconnects: the stream is restarted from the last position if it fails
+//     with a transient error.
+//
+// Delivery is at-least-once: changes after the last checkpoint are delivered
+// again after a restart, so handlers must be idempotent.
+package vstreamclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"vitess.io/vitess/go/sqlescape"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/vtgateconn"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+const (
+	defaultCheckpointInterval = time.Second
+	defaultRetryInterval      = time.Second
+	finalCheckpointTimeout    = 10 * time.Second
+)
+
+// ChangeHandler handles a row change. If it returns an error, the consumer
+// stops, and the change is delivered again when it is restarted.
+type ChangeHandler func(ctx context.Context, change *Change) error
+
+// TableConfig configures the streaming of one table.
+type TableConfig struct {
+	// Name is the name of the table.
+	Name string
+	// Query optionally restricts the columns or rows streamed, e.g.
+	// "select id, name from t where in_keyrange('-80')".
+	//
+	// Default: "select * from <Name>"
+	Query string
+	// Handler is called for every change to a row of the table.
+	Handler ChangeHandler
+}
+
+// Config configures a Consumer. Fields with documented default values do not
+// have to be set explicitly.
+type Config struct {
+	// Name identifies the consumer in the CheckpointStore.
+	Name string
+	// Keyspace is the keyspace to stream from.
+	Keyspace string
+	// Tables are the tables to stream.
+	Tables []*TableConfig
+	// Checkpoints stores the position of the consumer.
+	Checkpoints CheckpointStore
+	// CheckpointInterval is the minimum time between checkpoints. Changes are
+	// redelivered after a restart for at most this long.
+	//
+	// Default: 1s
+	CheckpointInterval time.Duration
+	// TabletType is the type of tablet to stream from.
+	//
+	// Default: REPLICA
+	TabletType topodatapb.TabletType
+	// SkipCopy, if set, makes a consumer without a checkpoint start from the
+	// current position, instead of first streaming all the existing rows.
+	SkipCopy bool
+	// Flags are passed to VStream. StopOnReshard is always false, since the
+	// consumer handles reshards.
+	Flags *vtgatepb.VStreamFlags
+	// RetryInterval is the time to wait before restarting a stream that failed
+	// with a transient error.
+	//
+	// Default: 1s
+	RetryInterval time.Duration
+}
+
+// Consumer consumes the changes to the tables of a keyspace. See the package
+// documentation for details.
+type Consumer struct {
+	conn   *vtgateconn.VTGateConn
+	cfg    Config
+	tables map[string]*TableConfig
+	filter *binlogdatapb.Filter
+	flags  *vtgatepb.VStreamFlags
+
+	// The fields below are only used by Run.
+
+	position *binlogdatapb.VGtid
+	// dirty is true if position has not been checkpointed.
+	dirty          bool
+	lastCheckpoint time.Time
+	// fields of each table, keyed by shard and qualified table name.
+	fields map[string][]*querypb.Field
+	// copying is true while in the copy phase, and copied holds the shards
+	// that have completed it.
+	copying bool
+	copied  map[string]bool
+}
+
+// handlerError wraps an error returned by a ChangeHandler, which must not be
+// retried.
+type handlerError struct {
+	err error
+}
+
+func (e *handlerError) Error() string { return e.err.Error() }
+func (e *handlerError) Unwrap() error { return e.err }
+
+// NewConsumer returns a Consumer that streams from the given vtgate.
+func NewConsumer(conn *vtgateconn.VTGateConn, cfg Config) (*Consumer, error) {
+	switch {
+	case cfg.Name == "":
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "consumer name is required")
+	case cfg.Keyspace == "":
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "keyspace is required")
+	case len(cfg.Tables) == 0:
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "at least one table is required")
+	case cfg.Checkpoints == nil:
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "a checkpoint store is required")
+	}
+
+	if cfg.CheckpointInterval <= 0 {
+		cfg.CheckpointInterval = defaultCheckpointInterval
+	}
+
+	if cfg.RetryInterval <= 0 {
+		cfg.RetryInterval = defaultRetryInterval
+	}
+
+	if cfg.TabletType == topodatapb.TabletType_UNKNOWN {
+		cfg.TabletType = topodatapb.TabletType_REPLICA
+	}
+
+	flags := &vtgatepb.VStreamFlags{}
+	if cfg.Flags != nil {
+		flags = proto.Clone(cfg.Flags).(*vtgatepb.VStreamFlags)
+	}
+	flags.StopOnReshard = false
+
+	c := &Consumer{
+		conn:   conn,
+		cfg:    cfg,
+		tables: make(map[string]*TableConfig, len(cfg.Tables)),
+		filter: &binlogdatapb.Filter{},
+		flags:  flags,
+	}
+
+	for _, table := range cfg.Tables {
+		switch {
+		case table.Name == "":
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "table name is required")
+		case table.Handler == nil:
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "table %s has no handler", table.Name)
+		case c.tables[table.Name] != nil:
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "table %s is configured more than once", table.Name)
+		}
+
+		c.tables[table.Name] = table
+
+		query := table.Query
+		if query == "" {
+			query = "select * from " + sqlescape.EscapeID(table.Name)
+		}
+
+		c.filter.Rules = append(c.filter.Rules, &binlogdatapb.Rule{
+			Match:  table.Name,
+			Filter: query,
+		})
+	}
+
+	return c, nil
+}
+
+// Run streams changes until the context is cancelled, a handler fails, or the
+// stream fails with an error that is not transient. It saves a final
+// checkpoint before returning, and always returns a non-nil error.
+//
+// Run must not be called concurrently.
+func (c *Consumer) Run(ctx context.Context) (err error) {
+	position, err := c.cfg.Checkpoints.Load(ctx, c.cfg.Name)
+	if err != nil {
+		return vterrors.Wrapf(err, "failed to load checkpoint for %s", c.cfg.Name)
+	}
+
+	if position == nil {
+		position = c.startPosition()
+		c.copying = !c.cfg.SkipCopy
+	} else {
+		for _, sgtid := range position.ShardGtids {
+			if len(sgtid.TablePKs) > 0 {
+				c.copying = true
+			}
+		}
+	}
+
+	c.position = position
+	c.copied = map[string]bool{}
+	c.lastCheckpoint = time.Now()
+
+	defer func() {
+		// Use a new context, since ctx is typically cancelled by now.
+		ctx, cancel := context.WithTimeout(context.Background(), finalCheckpointTimeout)
+		defer cancel()
+
+		if cerr := c.checkpoint(ctx, true); cerr != nil {
+			err = errors.Join(err, cerr)
+		}
+	}()
+
+	for {
+		err := c.stream(ctx)
+		switch {
+		case ctx.Err() != nil:
+			return ctx.Err()
+		case isRetryable(err):
+			log.Warningf("vstream for %s failed, restarting in %v: %v", c.cfg.Name, c.cfg.RetryInterval, err)
+		default:
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(c.cfg.RetryInterval):
+		}
+	}
+}
+
+// Position returns the position of the last change delivered. It must not be
+// called concurrently with Run.
+func (c *Consumer) Position() *binlogdatapb.VGtid {
+	return proto.Clone(c.position).(*binlogdatapb.VGtid)
+}
+
+func (c *Consumer) startPosition() *binlogdatapb.VGtid {
+	gtid := ""
+	if c.cfg.SkipCopy {
+		gtid = "current"
+	}
+
+	return &binlogdatapb.VGtid{
+		ShardGtids: []*binlogdatapb.ShardGtid{{
+			Keyspace: c.cfg.Keyspace,
+			Gtid:     gtid,
+		}},
+	}
+}
+
+func isRetryable(err error) bool {
+	var herr *handlerError
+	switch {
+	case errors.As(err, &herr):
+		return false
+	case errors.Is(err, io.EOF):
+		return true
+	default:
+		return vterrors.Code(err) == vtrpcpb.Code_UNAVAILABLE
+	}
+}
+
+// stream runs a single VStream from the current position.
+func (c *Consumer) stream(ctx context.Context) error {
+	// Every (re)started stream sends the fields again.
+	c.fields = map[string][]*querypb.Field{}
+
+	reader, err := c.conn.VStream(ctx, c.cfg.TabletType, c.position, c.filter, c.flags)
+	if err != nil {
+		return err
+	}
+
+	for {
+		events, err := reader.Recv()
+		if err != nil {
+			return err
+		}
+
+		for _, event := range events {
+			if err := c.handleEvent(ctx, event); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func fieldsKey(shard, table string) string {
+	return shard + "/" + table
+}
+
+func (c *Consumer) handleEvent(ctx context.Context, event *binlogdatapb.VEvent) error {
+	switch event.Type {
+	case binlogdatapb.VEventType_FIELD:
+		c.fields[fieldsKey(event.FieldEvent.Shard, event.FieldEvent.TableName)] = event.FieldEvent.Fields
+	case binlogdatapb.VEventType_ROW:
+		return c.handleRows(ctx, event)
+	case binlogdatapb.VEventType_VGTID:
+		// The rows of a transaction are sent before its VGTID event, so they
+		// have all been handled by now.
+		c.position = event.Vgtid
+		c.dirty = true
+		return c.checkpoint(ctx, false)
+	case binlogdatapb.VEventType_COPY_COMPLETED:
+		if event.Keyspace == "" && event.Shard == "" {
+			// The copy phase is complete on every shard.
+			c.copying = false
+			return c.checkpoint(ctx, true)
+		}
+
+		c.copied[event.Keyspace+"/"+event.Shard] = true
+	}
+
+	return nil
+}
+
+func (c *Consumer) handleRows(ctx context.Context, event *binlogdatapb.VEvent) error {
+	rowEvent := event.RowEvent
+	table := strings.TrimPrefix(rowEvent.TableName, rowEvent.Keyspace+".")
+	tc, ok := c.tables[table]
+	if !ok {
+		return nil
+	}
+
+	fields, ok := c.fields[fieldsKey(rowEvent.Shard, rowEvent.TableName)]
+	if !ok {
+		return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "received rows for %s/%s before its fields", rowEvent.Shard, rowEvent.TableName)
+	}
+
+	var timestamp time.Time
+	if event.Timestamp != 0 {
+		timestamp = time.Unix(event.Timestamp, 0)
+	}
+
+	isCopy := c.copying && !c.copied[rowEvent.Keyspace+"/"+rowEvent.Shard]
+	for _, rc := range rowEvent.RowChanges {
+		change := &Change{
+			Keyspace:  rowEvent.Keyspace,
+			Shard:     rowEvent.Shard,
+			Table:     table,
+			Before:    newRow(fields, rc.Before),
+			After:     newRow(fields, rc.After),
+			Timestamp: timestamp,
+			Copy:      isCopy,
+		}
+
+		switch {
+		case change.Before == nil:
+			change.Type = ChangeInsert
+		case change.After == nil:
+			change.Type = ChangeDelete
+		default:
+			change.Type = ChangeUpdate
+		}
+
+		if err := tc.Handler(ctx, change); err != nil {
+			return &handlerError{err: fmt.Errorf("handler for %s failed: %w", table, err)}
+		}
+	}
+
+	return nil
+}
+
+// checkpoint saves the current position if it has changed, and either force
+// is set or the checkpoint interval has elapsed.
+func (c *Consumer) checkpoint(ctx context.Context, force bool) error {
+	if !c.dirty || (!force && time.Since(c.lastCheckpoint) < c.cfg.CheckpointInterval) {
+		return nil
+	}
+
+	if err := c.cfg.Checkpoints.Save(ctx, c.cfg.Name, c.position); err != nil {
+		return vterrors.Wrapf(err, "failed to save checkpoint for %s", c.cfg.Name)
+	}
+
+	c.dirty = false
+	c.lastCheckpoint = time.Now()
+	return nil
+}
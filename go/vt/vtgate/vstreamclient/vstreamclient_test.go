@@ -0,0 +1,407 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vstreamclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/test/utils"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/vtgateconn"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// fakeVStream is a vtgateconn.Impl whose VStream calls each return the next
+// scripted stream.
+type fakeVStream struct {
+	vtgateconn.Impl
+
+	m       sync.Mutex
+	streams []*fakeReader
+	// positions are the positions that VStream was called with.
+	positions []*binlogdatapb.VGtid
+	flags     []*vtgatepb.VStreamFlags
+}
+
+// fakeReader returns batches of events, then err (or blocks until the stream
+// is cancelled if err is nil).
+type fakeReader struct {
+	ctx     context.Context
+	batches [][]*binlogdatapb.VEvent
+	err     error
+}
+
+func (r *fakeReader) Recv() ([]*binlogdatapb.VEvent, error) {
+	if len(r.batches) > 0 {
+		batch := r.batches[0]
+		r.batches = r.batches[1:]
+		return batch, nil
+	}
+
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	<-r.ctx.Done()
+	return nil, r.ctx.Err()
+}
+
+func (f *fakeVStream) VStream(ctx context.Context, tabletType topodatapb.TabletType, vgtid *binlogdatapb.VGtid, filter *binlogdatapb.Filter, flags *vtgatepb.VStreamFlags) (vtgateconn.VStreamReader, error) {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	f.positions = append(f.positions, proto.Clone(vgtid).(*binlogdatapb.VGtid))
+	f.flags = append(f.flags, flags)
+	if len(f.streams) == 0 {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "no more streams")
+	}
+
+	stream := f.streams[0]
+	f.streams = f.streams[1:]
+	stream.ctx = ctx
+	return stream, nil
+}
+
+func (f *fakeVStream) Close() {}
+
+func newFakeConn(t *testing.T, impl *fakeVStream) *vtgateconn.VTGateConn {
+	t.Helper()
+
+	protocol := "vstreamclient-" + t.Name()
+	vtgateconn.RegisterDialer(protocol, func(ctx context.Context, address string) (vtgateconn.Impl, error) {
+		return impl, nil
+	})
+	t.Cleanup(func() { vtgateconn.DeregisterDialer(protocol) })
+
+	conn, err := vtgateconn.DialProtocol(context.Background(), protocol, "")
+	require.NoError(t, err)
+	return conn
+}
+
+type memoryCheckpointStore struct {
+	m           sync.Mutex
+	checkpoints map[string]*binlogdatapb.VGtid
+	saves       int
+}
+
+func (s *memoryCheckpointStore) Load(ctx context.Context, name string) (*binlogdatapb.VGtid, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return s.checkpoints[name], nil
+}
+
+func (s *memoryCheckpointStore) Save(ctx context.Context, name string, vgtid *binlogdatapb.VGtid) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+	if s.checkpoints == nil {
+		s.checkpoints = map[string]*binlogdatapb.VGtid{}
+	}
+	s.checkpoints[name] = proto.Clone(vgtid).(*binlogdatapb.VGtid)
+	s.saves++
+	return nil
+}
+
+var testFields = []*querypb.Field{
+	{Name: "id", Type: sqltypes.Int64},
+	{Name: "name", Type: sqltypes.VarChar},
+}
+
+func fieldEvent(shard string) *binlogdatapb.VEvent {
+	return &binlogdatapb.VEvent{
+		Type: binlogdatapb.VEventType_FIELD,
+		FieldEvent: &binlogdatapb.FieldEvent{
+			TableName: "ks.t",
+			Fields:    testFields,
+			Keyspace:  "ks",
+			Shard:     shard,
+		},
+	}
+}
+
+func rowEvent(shard string, changes ...*binlogdatapb.RowChange) *binlogdatapb.VEvent {
+	return &binlogdatapb.VEvent{
+		Type:      binlogdatapb.VEventType_ROW,
+		Timestamp: 1700000000,
+		RowEvent: &binlogdatapb.RowEvent{
+			TableName:  "ks.t",
+			RowChanges: changes,
+			Keyspace:   "ks",
+			Shard:      shard,
+		},
+	}
+}
+
+func row(id int64, name string) *querypb.Row {
+	return sqltypes.RowToProto3([]sqltypes.Value{sqltypes.NewInt64(id), sqltypes.NewVarChar(name)})
+}
+
+func vgtidEvent(shardGtids ...*binlogdatapb.ShardGtid) *binlogdatapb.VEvent {
+	return &binlogdatapb.VEvent{
+		Type:  binlogdatapb.VEventType_VGTID,
+		Vgtid: &binlogdatapb.VGtid{ShardGtids: shardGtids},
+	}
+}
+
+func TestConsumer(t *testing.T) {
+	copyPos := &binlogdatapb.ShardGtid{
+		Keyspace: "ks",
+		Shard:    "0",
+		Gtid:     "pos1",
+		TablePKs: []*binlogdatapb.TableLastPK{{TableName: "t"}},
+	}
+	pos2 := &binlogdatapb.ShardGtid{Keyspace: "ks", Shard: "0", Gtid: "pos2"}
+	pos3 := &binlogdatapb.ShardGtid{Keyspace: "ks", Shard: "0", Gtid: "pos3"}
+
+	impl := &fakeVStream{
+		streams: []*fakeReader{{
+			batches: [][]*binlogdatapb.VEvent{
+				// Copy phase.
+				{fieldEvent("0"), rowEvent("0", &binlogdatapb.RowChange{After: row(1, "a")}), vgtidEvent(copyPos)},
+				{{Type: binlogdatapb.VEventType_COPY_COMPLETED, Keyspace: "ks", Shard: "0"}, {Type: binlogdatapb.VEventType_COPY_COMPLETED}},
+				// Replication.
+				{
+					{Type: binlogdatapb.VEventType_BEGIN},
+					rowEvent("0", &binlogdatapb.RowChange{Before: row(1, "a"), After: row(1, "b")}),
+					vgtidEvent(pos2),
+					{Type: binlogdatapb.VEventType_COMMIT},
+				},
+			},
+			// The stream breaks, and is restarted from pos2.
+			err: vterrors.Errorf(vtrpcpb.Code_UNAVAILABLE, "tablet went away"),
+		}, {
+			batches: [][]*binlogdatapb.VEvent{{
+				fieldEvent("0"),
+				{Type: binlogdatapb.VEventType_BEGIN},
+				rowEvent("0", &binlogdatapb.RowChange{Before: row(1, "b")}),
+				vgtidEvent(pos3),
+				{Type: binlogdatapb.VEventType_COMMIT},
+			}},
+		}},
+	}
+
+	store := &memoryCheckpointStore{}
+	changes := make(chan *Change, 10)
+	consumer, err := NewConsumer(newFakeConn(t, impl), Config{
+		Name:     "test",
+		Keyspace: "ks",
+		Tables: []*TableConfig{{
+			Name: "t",
+			Handler: func(ctx context.Context, change *Change) error {
+				changes <- change
+				return nil
+			},
+		}},
+		Checkpoints:        store,
+		CheckpointInterval: time.Hour,
+		RetryInterval:      time.Millisecond,
+		Flags:              &vtgatepb.VStreamFlags{StopOnReshard: true},
+	})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- consumer.Run(ctx) }()
+
+	var got []*Change
+	for len(got) < 3 {
+		select {
+		case change := <-changes:
+			got = append(got, change)
+		case err := <-done:
+			t.Fatalf("consumer stopped: %v", err)
+		case <-time.After(10 * time.Second):
+			t.Fatalf("timed out, got %d changes", len(got))
+		}
+	}
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+
+	require.Len(t, got, 3)
+
+	assert.Equal(t, ChangeInsert, got[0].Type)
+	assert.True(t, got[0].Copy)
+	assert.Nil(t, got[0].Before)
+	name, ok := got[0].After.Value("name")
+	require.True(t, ok)
+	assert.Equal(t, "a", name.ToString())
+
+	assert.Equal(t, ChangeUpdate, got[1].Type)
+	assert.False(t, got[1].Copy)
+	assert.Equal(t, "t", got[1].Table)
+	assert.Equal(t, "0", got[1].Shard)
+	assert.Equal(t, time.Unix(1700000000, 0), got[1].Timestamp)
+	id, err := got[1].After.Map()["id"].ToInt64()
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, id)
+	assert.Equal(t, "b", got[1].After.Map()["name"].ToString())
+
+	assert.Equal(t, ChangeDelete, got[2].Type)
+	assert.Nil(t, got[2].After)
+
+	// The first stream starts with the copy phase, and the second one resumes
+	// from the last position.
+	require.Len(t, impl.positions, 2)
+	utils.MustMatch(t, &binlogdatapb.VGtid{ShardGtids: []*binlogdatapb.ShardGtid{{Keyspace: "ks"}}}, impl.positions[0])
+	utils.MustMatch(t, &binlogdatapb.VGtid{ShardGtids: []*binlogdatapb.ShardGtid{pos2}}, impl.positions[1])
+	assert.False(t, impl.flags[0].StopOnReshard, "the consumer must handle reshards")
+
+	// The copy phase completion and the final position were checkpointed.
+	assert.Equal(t, 2, store.saves)
+	utils.MustMatch(t, &binlogdatapb.VGtid{ShardGtids: []*binlogdatapb.ShardGtid{pos3}}, store.checkpoints["test"])
+	utils.MustMatch(t, store.checkpoints["test"], consumer.Position())
+}
+
+func TestConsumerResume(t *testing.T) {
+	pos := &binlogdatapb.ShardGtid{Keyspace: "ks", Shard: "0", Gtid: "pos"}
+	store := &memoryCheckpointStore{
+		checkpoints: map[string]*binlogdatapb.VGtid{"test": {ShardGtids: []*binlogdatapb.ShardGtid{pos}}},
+	}
+
+	handlerErr := errors.New("handler failed")
+	impl := &fakeVStream{
+		streams: []*fakeReader{{
+			batches: [][]*binlogdatapb.VEvent{
+				{fieldEvent("0"), rowEvent("0", &binlogdatapb.RowChange{After: row(2, "x")})},
+			},
+		}},
+	}
+
+	consumer, err := NewConsumer(newFakeConn(t, impl), Config{
+		Name:     "test",
+		Keyspace: "ks",
+		Tables: []*TableConfig{{
+			Name:  "t",
+			Query: "select id from t",
+			Handler: func(ctx context.Context, change *Change) error {
+				assert.False(t, change.Copy)
+				return handlerErr
+			},
+		}},
+		Checkpoints: store,
+	})
+	require.NoError(t, err)
+
+	// Handler errors are returned, and not retried.
+	err = consumer.Run(context.Background())
+	assert.ErrorIs(t, err, handlerErr)
+
+	require.Len(t, impl.positions, 1)
+	utils.MustMatch(t, store.checkpoints["test"], impl.positions[0])
+	assert.Equal(t, 0, store.saves)
+}
+
+func TestConsumerStreamErrors(t *testing.T) {
+	impl := &fakeVStream{
+		streams: []*fakeReader{
+			{err: io.EOF},
+			{err: vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "bad filter")},
+		},
+	}
+
+	consumer, err := NewConsumer(newFakeConn(t, impl), Config{
+		Name:          "test",
+		Keyspace:      "ks",
+		Tables:        []*TableConfig{{Name: "t", Handler: func(context.Context, *Change) error { return nil }}},
+		Checkpoints:   &memoryCheckpointStore{},
+		SkipCopy:      true,
+		RetryInterval: time.Millisecond,
+	})
+	require.NoError(t, err)
+
+	err = consumer.Run(context.Background())
+	assert.ErrorContains(t, err, "bad filter")
+	require.Len(t, impl.positions, 2)
+	assert.Equal(t, "current", impl.positions[0].ShardGtids[0].Gtid)
+}
+
+func TestNewConsumer(t *testing.T) {
+	handler := func(context.Context, *Change) error { return nil }
+	store := &memoryCheckpointStore{}
+
+	tests := []struct {
+		name string
+		cfg  Config
+		err  string
+	}{
+		{
+			name: "no name",
+			cfg:  Config{Keyspace: "ks", Tables: []*TableConfig{{Name: "t", Handler: handler}}, Checkpoints: store},
+			err:  "consumer name is required",
+		},
+		{
+			name: "no keyspace",
+			cfg:  Config{Name: "c", Tables: []*TableConfig{{Name: "t", Handler: handler}}, Checkpoints: store},
+			err:  "keyspace is required",
+		},
+		{
+			name: "no tables",
+			cfg:  Config{Name: "c", Keyspace: "ks", Checkpoints: store},
+			err:  "at least one table is required",
+		},
+		{
+			name: "no checkpoint store",
+			cfg:  Config{Name: "c", Keyspace: "ks", Tables: []*TableConfig{{Name: "t", Handler: handler}}},
+			err:  "a checkpoint store is required",
+		},
+		{
+			name: "no handler",
+			cfg:  Config{Name: "c", Keyspace: "ks", Tables: []*TableConfig{{Name: "t"}}, Checkpoints: store},
+			err:  "table t has no handler",
+		},
+		{
+			name: "duplicate table",
+			cfg:  Config{Name: "c", Keyspace: "ks", Tables: []*TableConfig{{Name: "t", Handler: handler}, {Name: "t", Handler: handler}}, Checkpoints: store},
+			err:  "table t is configured more than once",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewConsumer(nil, tt.cfg)
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+
+	c, err := NewConsumer(nil, Config{
+		Name:        "c",
+		Keyspace:    "ks",
+		Tables:      []*TableConfig{{Name: "t", Handler: handler}, {Name: "u", Query: "select id from u", Handler: handler}},
+		Checkpoints: store,
+	})
+	require.NoError(t, err)
+	utils.MustMatch(t, &binlogdatapb.Filter{Rules: []*binlogdatapb.Rule{
+		{Match: "t", Filter: "select * from `t`"},
+		{Match: "u", Filter: "select id from u"},
+	}}, c.filter)
+	assert.Equal(t, topodatapb.TabletType_REPLICA, c.cfg.TabletType)
+}
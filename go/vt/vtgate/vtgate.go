@@ -87,7 +87,15 @@ var (
 
 	// lockHeartbeatTime is used to set the next heartbeat time.
 	lockHeartbeatTime = 5 * time.Second
-	warnShardedOnly   bool
+
+	// consistentSnapshotLockWaitTimeout is the maximum time to wait for the global read lock
+	// on each shard when starting a cross-shard consistent snapshot transaction.
+	consistentSnapshotLockWaitTimeout = 10 * time.Second
+	// consistentSnapshotUnlockTimeout is the maximum time to wait for the global read lock
+	// to be released on each shard, once the consistent snapshot transactions are started.
+	consistentSnapshotUnlockTimeout = 5 * time.Second
+
+	warnShardedOnly bool
 
 	// ddl related flags
 	foreignKeyMode     = "allow"
@@ -117,6 +125,16 @@ var (
 	warmingReadsPercent      = 0
 	warmingReadsQueryTimeout = 5 * time.Second
 	warmingReadsConcurrency  = 500
+
+	// queryDigestsSize is the number of query digests that vtgate keeps statistics for
+	queryDigestsSize = 1000
+	// queryDigestsExportTopK is the number of query digests whose statistics are exported as metrics
+	queryDigestsExportTopK = 0
+
+	// consolidatorMode controls the consolidation of identical concurrent reads in vtgate
+	consolidatorMode           = consolidatorDisable
+	consolidatorQueryWaiterCap int64
+	consolidatorQuerySize      int64 = 2 * 1024 * 1024
 )
 
 func registerFlags(fs *pflag.FlagSet) {
@@ -139,6 +157,7 @@ func registerFlags(fs *pflag.FlagSet) {
 	fs.BoolVar(&sysVarSetEnabled, "enable_system_settings", sysVarSetEnabled, "This will enable the system settings to be changed per session at the database connection level")
 	fs.BoolVar(&setVarEnabled, "enable_set_var", setVarEnabled, "This will enable the use of MySQL's SET_VAR query hint for certain system variables instead of using reserved connections")
 	fs.DurationVar(&lockHeartbeatTime, "lock_heartbeat_time", lockHeartbeatTime, "If there is lock function used. This will keep the lock connection active by using this heartbeat")
+	fs.DurationVar(&consistentSnapshotLockWaitTimeout, "consistent-snapshot-lock-wait-timeout", consistentSnapshotLockWaitTimeout, "Maximum time to wait for the global read lock on each shard when starting a cross-shard consistent snapshot transaction. Taking the lock requires the RELOAD privilege for the app user of the tablets.")
 	fs.BoolVar(&warnShardedOnly, "warn_sharded_only", warnShardedOnly, "If any features that are only available in unsharded mode are used, query execution warnings will be added to the session")
 	fs.StringVar(&foreignKeyMode, "foreign_key_mode", foreignKeyMode, "This is to provide how to handle foreign key constraint in create/alter table. Valid values are: allow, disallow")
 	fs.BoolVar(&enableOnlineDDL, "enable_online_ddl", enableOnlineDDL, "Allow users to submit, review and control Online DDL")
@@ -153,6 +172,11 @@ func registerFlags(fs *pflag.FlagSet) {
 	fs.IntVar(&warmingReadsPercent, "warming-reads-percent", 0, "Percentage of reads on the primary to forward to replicas. Useful for keeping buffer pools warm")
 	fs.IntVar(&warmingReadsConcurrency, "warming-reads-concurrency", 500, "Number of concurrent warming reads allowed")
 	fs.DurationVar(&warmingReadsQueryTimeout, "warming-reads-query-timeout", 5*time.Second, "Timeout of warming read queries")
+	fs.IntVar(&queryDigestsSize, "query-digests-size", queryDigestsSize, "Number of query digests, keyed by normalized query and keyspace, to keep execution statistics for. The digests with the highest total execution time are kept. 0 disables query digests")
+	fs.IntVar(&queryDigestsExportTopK, "query-digests-export-top-k", queryDigestsExportTopK, "Number of query digests, with the highest total execution time, whose statistics are exported as metrics. 0 disables the export")
+	fs.StringVar(&consolidatorMode, "vtgate-consolidator", consolidatorMode, "Consolidate identical concurrent reads that are routed to the same keyspace, shard and tablet type outside of a transaction, so that they share a single execution. Valid values are: disable, enable, notOnPrimary. Can be overridden per query by the CONSOLIDATOR comment directive")
+	fs.Int64Var(&consolidatorQueryWaiterCap, "vtgate-consolidator-query-waiter-cap", consolidatorQueryWaiterCap, "Maximum number of queries that wait on a single in-flight identical query in the vtgate consolidator. Further identical queries are executed on their own. 0 means no limit")
+	fs.Int64Var(&consolidatorQuerySize, "vtgate-consolidator-query-size", consolidatorQuerySize, "Maximum size in bytes of a result that the vtgate consolidator shares with waiting queries. The waiters of a query with a larger result execute it on their own. 0 means no limit")
 }
 
 func init() {
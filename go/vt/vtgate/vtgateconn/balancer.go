@@ -0,0 +1,584 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgateconn
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+const (
+	defaultHealthCheckInterval = 5 * time.Second
+	defaultHealthCheckTimeout  = 2 * time.Second
+	defaultMaxRetries          = 2
+
+	healthCheckQuery = "select 1"
+)
+
+// Resolver returns the addresses of the vtgates that a balanced VTGateConn
+// connects to. It is called when dialing, and then periodically to pick up
+// vtgates being added or removed.
+//
+// Any service discovery mechanism can be adapted to a Resolver; for example,
+// one backed by VTAdmin's discovery.Discovery.DiscoverVTGateAddrs.
+type Resolver interface {
+	Resolve(ctx context.Context) ([]string, error)
+}
+
+// StaticResolver is a Resolver that always returns the same addresses.
+type StaticResolver []string
+
+// Resolve is part of the Resolver interface.
+func (r StaticResolver) Resolve(ctx context.Context) ([]string, error) {
+	return r, nil
+}
+
+// BalancerOptions configures a VTGateConn created with DialBalanced. The zero
+// value uses the documented defaults.
+type BalancerOptions struct {
+	// HealthCheckInterval is how often the vtgate addresses are re-resolved
+	// and each vtgate is health checked.
+	//
+	// Default: 5s
+	HealthCheckInterval time.Duration
+	// HealthCheckTimeout bounds each health check query.
+	//
+	// Default: 2s
+	HealthCheckTimeout time.Duration
+	// MaxRetries is the maximum number of other vtgates on which a failed
+	// read-only query is retried.
+	//
+	// Default: 2
+	MaxRetries int
+}
+
+func (opts *BalancerOptions) setDefaults() {
+	if opts.HealthCheckInterval <= 0 {
+		opts.HealthCheckInterval = defaultHealthCheckInterval
+	}
+
+	if opts.HealthCheckTimeout <= 0 {
+		opts.HealthCheckTimeout = defaultHealthCheckTimeout
+	}
+
+	if opts.MaxRetries < 0 {
+		opts.MaxRetries = 0
+	} else if opts.MaxRetries == 0 {
+		opts.MaxRetries = defaultMaxRetries
+	}
+}
+
+// DialBalanced dials every vtgate returned by the resolver using the given
+// protocol, and returns a *VTGateConn that balances sessions across them.
+//
+// Each session is pinned to one healthy vtgate, chosen round-robin when the
+// session is created. If a call fails because its vtgate is unavailable, the
+// vtgate is marked unhealthy until it passes a health check, and the session
+// moves to another vtgate, carrying its vtgatepb.Session (and therefore any
+// open transaction) with it. Read-only statements that fail this way are
+// retried on the next vtgate; other statements return the error, since they
+// may have been executed.
+//
+// Each vtgate is health checked before DialBalanced returns, and then every
+// HealthCheckInterval. Since calls may wait for a vtgate's connection to be
+// ready (as grpcvtgateconn does), a vtgate that goes away is usually detected by
+// a health check rather than by a failed call, so callers should still set
+// deadlines on their contexts.
+func DialBalanced(ctx context.Context, protocol string, resolver Resolver, opts BalancerOptions) (*VTGateConn, error) {
+	dialer, err := getDialer(protocol)
+	if err != nil {
+		return nil, err
+	}
+
+	opts.setDefaults()
+
+	b := &balancer{
+		dialer:   dialer,
+		resolver: resolver,
+		opts:     opts,
+		done:     make(chan struct{}),
+	}
+
+	if err := b.update(ctx); err != nil {
+		b.closeEndpoints()
+		return nil, err
+	}
+
+	b.healthCheck(ctx)
+
+	loopCtx, cancel := context.WithCancel(context.Background())
+	b.cancel = cancel
+	go b.loop(loopCtx)
+
+	return &VTGateConn{
+		impl: b,
+	}, nil
+}
+
+type endpoint struct {
+	addr    string
+	impl    Impl
+	healthy atomic.Bool
+}
+
+// balancer is an Impl that spreads calls across several vtgates. Calls made
+// directly on the balancer are not pinned to a vtgate; sessions get a
+// balancedSession from newSession instead.
+type balancer struct {
+	dialer   DialerFunc
+	resolver Resolver
+	opts     BalancerOptions
+
+	m         sync.Mutex
+	endpoints []*endpoint // sorted by address
+	next      int         // index in endpoints of the next round-robin pick
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// newSession returns an Impl for a new session.
+func (b *balancer) newSession() Impl {
+	return &balancedSession{b: b}
+}
+
+// pick returns the next healthy endpoint not in exclude. If every endpoint not
+// in exclude is unhealthy, one of those is returned anyway, since the health
+// information may be stale.
+func (b *balancer) pick(exclude []*endpoint) (*endpoint, error) {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	var fallback *endpoint
+	for i := 0; i < len(b.endpoints); i++ {
+		idx := (b.next + i) % len(b.endpoints)
+		ep := b.endpoints[idx]
+		if slices.Contains(exclude, ep) {
+			continue
+		}
+
+		if !ep.healthy.Load() {
+			if fallback == nil {
+				fallback = ep
+			}
+
+			continue
+		}
+
+		b.next = (idx + 1) % len(b.endpoints)
+		return ep, nil
+	}
+
+	if fallback != nil {
+		return fallback, nil
+	}
+
+	return nil, vterrors.New(vtrpcpb.Code_UNAVAILABLE, "no vtgate available")
+}
+
+func (b *balancer) markUnhealthy(ep *endpoint, err error) {
+	if ep.healthy.CompareAndSwap(true, false) {
+		log.Warningf("vtgate %s is unavailable, failing over: %v", ep.addr, err)
+	}
+}
+
+// update resolves the current set of vtgate addresses, dialing new vtgates and
+// closing removed ones. New vtgates are assumed healthy until their first
+// health check.
+func (b *balancer) update(ctx context.Context) error {
+	addrs, err := b.resolver.Resolve(ctx)
+	if err != nil {
+		return vterrors.Wrap(err, "failed to resolve vtgate addresses")
+	}
+
+	if len(addrs) == 0 {
+		return vterrors.New(vtrpcpb.Code_UNAVAILABLE, "resolver returned no vtgate addresses")
+	}
+
+	addrs = slices.Clone(addrs)
+	slices.Sort(addrs)
+	addrs = slices.Compact(addrs)
+
+	b.m.Lock()
+	current := make(map[string]*endpoint, len(b.endpoints))
+	for _, ep := range b.endpoints {
+		current[ep.addr] = ep
+	}
+	b.m.Unlock()
+
+	endpoints := make([]*endpoint, 0, len(addrs))
+	for _, addr := range addrs {
+		if ep, ok := current[addr]; ok {
+			endpoints = append(endpoints, ep)
+			delete(current, addr)
+			continue
+		}
+
+		impl, err := b.dialer(ctx, addr)
+		if err != nil {
+			log.Warningf("failed to dial vtgate %s: %v", addr, err)
+			continue
+		}
+
+		ep := &endpoint{addr: addr, impl: impl}
+		ep.healthy.Store(true)
+		endpoints = append(endpoints, ep)
+	}
+
+	if len(endpoints) == 0 {
+		return vterrors.Errorf(vtrpcpb.Code_UNAVAILABLE, "failed to dial any vtgate in %v", addrs)
+	}
+
+	b.m.Lock()
+	b.endpoints = endpoints
+	b.next = 0
+	b.m.Unlock()
+
+	// Anything left in current has been removed. Sessions still pinned to it
+	// will see that it is unhealthy and move elsewhere.
+	for _, ep := range current {
+		ep.healthy.Store(false)
+		ep.impl.Close()
+	}
+
+	return nil
+}
+
+// healthCheck probes every vtgate concurrently, updating its health.
+func (b *balancer) healthCheck(ctx context.Context) {
+	b.m.Lock()
+	endpoints := slices.Clone(b.endpoints)
+	b.m.Unlock()
+
+	var wg sync.WaitGroup
+	for _, ep := range endpoints {
+		wg.Add(1)
+		go func(ep *endpoint) {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(ctx, b.opts.HealthCheckTimeout)
+			defer cancel()
+
+			// Any response, even an error, means that the vtgate is reachable.
+			_, _, err := ep.impl.Execute(ctx, &vtgatepb.Session{Autocommit: true}, healthCheckQuery, nil)
+			switch {
+			case ctx.Err() != nil && ctx.Err() != context.DeadlineExceeded:
+				// The balancer is closing.
+			case isUnavailable(err), vterrors.Code(err) == vtrpcpb.Code_DEADLINE_EXCEEDED:
+				b.markUnhealthy(ep, err)
+			default:
+				if ep.healthy.CompareAndSwap(false, true) {
+					log.Infof("vtgate %s is healthy", ep.addr)
+				}
+			}
+		}(ep)
+	}
+
+	wg.Wait()
+}
+
+func (b *balancer) loop(ctx context.Context) {
+	defer close(b.done)
+
+	ticker := time.NewTicker(b.opts.HealthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := b.update(ctx); err != nil {
+			log.Warningf("failed to update vtgate addresses, keeping the current set: %v", err)
+		}
+
+		b.healthCheck(ctx)
+	}
+}
+
+func (b *balancer) closeEndpoints() {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	for _, ep := range b.endpoints {
+		ep.healthy.Store(false)
+		ep.impl.Close()
+	}
+
+	b.endpoints = nil
+}
+
+// Execute is part of the Impl interface.
+func (b *balancer) Execute(ctx context.Context, session *vtgatepb.Session, query string, bindVars map[string]*querypb.BindVariable) (*vtgatepb.Session, *sqltypes.Result, error) {
+	return b.newSession().Execute(ctx, session, query, bindVars)
+}
+
+// ExecuteBatch is part of the Impl interface.
+func (b *balancer) ExecuteBatch(ctx context.Context, session *vtgatepb.Session, queryList []string, bindVarsList []map[string]*querypb.BindVariable) (*vtgatepb.Session, []sqltypes.QueryResponse, error) {
+	return b.newSession().ExecuteBatch(ctx, session, queryList, bindVarsList)
+}
+
+// StreamExecute is part of the Impl interface.
+func (b *balancer) StreamExecute(ctx context.Context, session *vtgatepb.Session, query string, bindVars map[string]*querypb.BindVariable, processResponse func(*vtgatepb.StreamExecuteResponse)) (sqltypes.ResultStream, error) {
+	return b.newSession().StreamExecute(ctx, session, query, bindVars, processResponse)
+}
+
+// Prepare is part of the Impl interface.
+func (b *balancer) Prepare(ctx context.Context, session *vtgatepb.Session, query string, bindVars map[string]*querypb.BindVariable) (*vtgatepb.Session, []*querypb.Field, error) {
+	return b.newSession().Prepare(ctx, session, query, bindVars)
+}
+
+// CloseSession is part of the Impl interface.
+func (b *balancer) CloseSession(ctx context.Context, session *vtgatepb.Session) error {
+	return b.newSession().CloseSession(ctx, session)
+}
+
+// ResolveTransaction is part of the Impl interface.
+func (b *balancer) ResolveTransaction(ctx context.Context, dtid string) error {
+	return b.newSession().ResolveTransaction(ctx, dtid)
+}
+
+// VStream is part of the Impl interface.
+func (b *balancer) VStream(ctx context.Context, tabletType topodatapb.TabletType, vgtid *binlogdatapb.VGtid, filter *binlogdatapb.Filter, flags *vtgatepb.VStreamFlags) (VStreamReader, error) {
+	return b.newSession().VStream(ctx, tabletType, vgtid, filter, flags)
+}
+
+// Close is part of the Impl interface.
+func (b *balancer) Close() {
+	b.cancel()
+	<-b.done
+	b.closeEndpoints()
+}
+
+// balancedSession is the Impl for a single session of a balanced VTGateConn.
+// It sends calls to the same vtgate until that vtgate becomes unavailable. Like
+// VTGateSession, it must not be used concurrently.
+type balancedSession struct {
+	b  *balancer
+	ep *endpoint
+}
+
+// do calls f with the Impl of the session's vtgate. If f fails because the
+// vtgate is unavailable, the session moves to another vtgate, and if retryable
+// is true, f is called again there.
+func (s *balancedSession) do(ctx context.Context, retryable bool, f func(impl Impl) error) error {
+	var tried []*endpoint
+	for {
+		if s.ep == nil || !s.ep.healthy.Load() || slices.Contains(tried, s.ep) {
+			ep, err := s.b.pick(tried)
+			if err != nil {
+				return err
+			}
+
+			s.ep = ep
+		}
+
+		ep := s.ep
+		err := f(ep.impl)
+		if !isUnavailable(err) || ctx.Err() != nil {
+			return err
+		}
+
+		s.b.markUnhealthy(ep, err)
+		s.ep = nil
+		tried = append(tried, ep)
+
+		if !retryable || len(tried) > s.b.opts.MaxRetries {
+			return err
+		}
+	}
+}
+
+// Execute is part of the Impl interface.
+func (s *balancedSession) Execute(ctx context.Context, session *vtgatepb.Session, query string, bindVars map[string]*querypb.BindVariable) (*vtgatepb.Session, *sqltypes.Result, error) {
+	var (
+		newSession = session
+		result     *sqltypes.Result
+	)
+	err := s.do(ctx, isReadOnlyQuery(query), func(impl Impl) (err error) {
+		newSession, result, err = impl.Execute(ctx, session, query, bindVars)
+		return err
+	})
+
+	return newSession, result, err
+}
+
+// ExecuteBatch is part of the Impl interface.
+func (s *balancedSession) ExecuteBatch(ctx context.Context, session *vtgatepb.Session, queryList []string, bindVarsList []map[string]*querypb.BindVariable) (*vtgatepb.Session, []sqltypes.QueryResponse, error) {
+	retryable := true
+	for _, query := range queryList {
+		retryable = retryable && isReadOnlyQuery(query)
+	}
+
+	var (
+		newSession = session
+		results    []sqltypes.QueryResponse
+	)
+	err := s.do(ctx, retryable, func(impl Impl) (err error) {
+		newSession, results, err = impl.ExecuteBatch(ctx, session, queryList, bindVarsList)
+		return err
+	})
+
+	return newSession, results, err
+}
+
+// StreamExecute is part of the Impl interface. Only errors returned when
+// starting the stream are retried; errors from the stream itself are returned
+// to the caller.
+func (s *balancedSession) StreamExecute(ctx context.Context, session *vtgatepb.Session, query string, bindVars map[string]*querypb.BindVariable, processResponse func(*vtgatepb.StreamExecuteResponse)) (sqltypes.ResultStream, error) {
+	var stream sqltypes.ResultStream
+	err := s.do(ctx, isReadOnlyQuery(query), func(impl Impl) (err error) {
+		stream, err = impl.StreamExecute(ctx, session, query, bindVars, processResponse)
+		return err
+	})
+
+	return stream, err
+}
+
+// Prepare is part of the Impl interface.
+func (s *balancedSession) Prepare(ctx context.Context, session *vtgatepb.Session, query string, bindVars map[string]*querypb.BindVariable) (*vtgatepb.Session, []*querypb.Field, error) {
+	var (
+		newSession = session
+		fields     []*querypb.Field
+	)
+	err := s.do(ctx, true, func(impl Impl) (err error) {
+		newSession, fields, err = impl.Prepare(ctx, session, query, bindVars)
+		return err
+	})
+
+	return newSession, fields, err
+}
+
+// CloseSession is part of the Impl interface.
+func (s *balancedSession) CloseSession(ctx context.Context, session *vtgatepb.Session) error {
+	return s.do(ctx, true, func(impl Impl) error {
+		return impl.CloseSession(ctx, session)
+	})
+}
+
+// ResolveTransaction is part of the Impl interface.
+func (s *balancedSession) ResolveTransaction(ctx context.Context, dtid string) error {
+	return s.do(ctx, true, func(impl Impl) error {
+		return impl.ResolveTransaction(ctx, dtid)
+	})
+}
+
+// VStream is part of the Impl interface.
+func (s *balancedSession) VStream(ctx context.Context, tabletType topodatapb.TabletType, vgtid *binlogdatapb.VGtid, filter *binlogdatapb.Filter, flags *vtgatepb.VStreamFlags) (VStreamReader, error) {
+	var reader VStreamReader
+	err := s.do(ctx, true, func(impl Impl) (err error) {
+		reader, err = impl.VStream(ctx, tabletType, vgtid, filter, flags)
+		return err
+	})
+
+	return reader, err
+}
+
+// Close is part of the Impl interface. The vtgate connections are owned by the
+// balancer, so this is a no-op.
+func (s *balancedSession) Close() {}
+
+// unreachableError is an error returned by an Impl that could not reach its
+// vtgate at all.
+type unreachableError struct {
+	err error
+}
+
+// NewUnreachableError marks err, returned by an Impl, as meaning that the
+// vtgate could not be reached, so that a balanced VTGateConn moves to another
+// vtgate. Impls must only mark transport-level failures: an UNAVAILABLE error
+// that the vtgate relays from a tablet says nothing about the vtgate itself.
+func NewUnreachableError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &unreachableError{err: err}
+}
+
+func (e *unreachableError) Error() string { return e.err.Error() }
+
+func (e *unreachableError) Unwrap() error { return e.err }
+
+// ErrorCode is part of the vterrors.ErrorWithCode interface.
+func (e *unreachableError) ErrorCode() vtrpcpb.Code { return vterrors.Code(e.err) }
+
+// isUnavailable returns whether err means that the vtgate could not be reached.
+func isUnavailable(err error) bool {
+	var unreachable *unreachableError
+	return errors.As(err, &unreachable)
+}
+
+// readOnlyStatements are the (lowercased) leading keywords of statements that
+// are safe to send again after a failure.
+var readOnlyStatements = []string{"select", "show", "describe", "desc", "explain"}
+
+// notReadOnlyRegexp matches the parts of an otherwise read-only statement that
+// take locks or have side effects: locking reads, named locks, sequence values
+// and select ... into.
+var notReadOnlyRegexp = regexp.MustCompile(`(?i)\bfor\s+(update|share)\b|\block\s+in\s+share\s+mode\b|\b(get_lock|release_lock|release_all_locks)\s*\(|\bnext\s+(\S+\s+)?values?\b|\binto\b`)
+
+// isReadOnlyQuery returns whether the query is a read-only statement, ignoring
+// any leading whitespace and comments. It deliberately does not parse the
+// query, so anything it does not recognize is considered not read-only, and
+// so is anything that looks like a locking read, even in a string literal.
+func isReadOnlyQuery(query string) bool {
+	for {
+		query = strings.TrimLeft(query, " \t\r\n(")
+		switch {
+		case strings.HasPrefix(query, "/*"):
+			end := strings.Index(query, "*/")
+			if end == -1 {
+				return false
+			}
+
+			query = query[end+2:]
+		case strings.HasPrefix(query, "-- "), strings.HasPrefix(query, "#"):
+			end := strings.IndexByte(query, '\n')
+			if end == -1 {
+				return false
+			}
+
+			query = query[end+1:]
+		default:
+			end := strings.IndexAny(query, " \t\r\n(/")
+			if end == -1 {
+				end = len(query)
+			}
+
+			return slices.Contains(readOnlyStatements, strings.ToLower(query[:end])) && !notReadOnlyRegexp.MatchString(query)
+		}
+	}
+}
@@ -0,0 +1,288 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgateconn
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// fakeImpl is an Impl whose Execute records the queries it receives, and fails
+// as if the vtgate could not be reached while down is set. Queries listed in
+// relayUnavailable fail with an UNAVAILABLE error relayed by the vtgate.
+type fakeImpl struct {
+	Impl
+
+	addr string
+
+	m       sync.Mutex
+	down    bool
+	queries []string
+
+	relayUnavailable []string
+	closed           bool
+}
+
+func (f *fakeImpl) setDown(down bool) {
+	f.m.Lock()
+	defer f.m.Unlock()
+	f.down = down
+}
+
+func (f *fakeImpl) executed() []string {
+	f.m.Lock()
+	defer f.m.Unlock()
+	return f.queries
+}
+
+func (f *fakeImpl) Execute(ctx context.Context, session *vtgatepb.Session, query string, bindVars map[string]*querypb.BindVariable) (*vtgatepb.Session, *sqltypes.Result, error) {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	if f.down {
+		return session, nil, NewUnreachableError(vterrors.Errorf(vtrpcpb.Code_UNAVAILABLE, "%s is down", f.addr))
+	}
+
+	if query != healthCheckQuery {
+		f.queries = append(f.queries, query)
+	}
+
+	if slices.Contains(f.relayUnavailable, query) {
+		return session, nil, vterrors.Errorf(vtrpcpb.Code_UNAVAILABLE, "target: ks.0.primary: no healthy tablet available")
+	}
+
+	return &vtgatepb.Session{InTransaction: session.InTransaction, TargetString: f.addr}, &sqltypes.Result{}, nil
+}
+
+func (f *fakeImpl) Close() {
+	f.m.Lock()
+	defer f.m.Unlock()
+	f.closed = true
+}
+
+type fakeResolver struct {
+	m     sync.Mutex
+	addrs []string
+}
+
+func (r *fakeResolver) set(addrs ...string) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.addrs = addrs
+}
+
+func (r *fakeResolver) Resolve(ctx context.Context) ([]string, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	return r.addrs, nil
+}
+
+func registerFakeBalancerDialer(t *testing.T, protocol string) map[string]*fakeImpl {
+	t.Helper()
+
+	var m sync.Mutex
+	impls := map[string]*fakeImpl{}
+	for _, addr := range []string{"a", "b", "c"} {
+		impls[addr] = &fakeImpl{addr: addr}
+	}
+
+	RegisterDialer(protocol, func(ctx context.Context, addr string) (Impl, error) {
+		m.Lock()
+		defer m.Unlock()
+		return impls[addr], nil
+	})
+	t.Cleanup(func() { DeregisterDialer(protocol) })
+
+	return impls
+}
+
+func TestDialBalanced(t *testing.T) {
+	ctx := context.Background()
+	impls := registerFakeBalancerDialer(t, "balanced-test-rr")
+
+	conn, err := DialBalanced(ctx, "balanced-test-rr", StaticResolver{"c", "b", "a", "b"}, BalancerOptions{HealthCheckInterval: time.Hour})
+	require.NoError(t, err)
+
+	// Sessions are spread round-robin, and stick to their vtgate.
+	for i := 0; i < 3; i++ {
+		session := conn.Session("", nil)
+		for j := 0; j < 2; j++ {
+			_, err := session.Execute(ctx, "select 1 from t", nil)
+			require.NoError(t, err)
+		}
+	}
+
+	for _, addr := range []string{"a", "b", "c"} {
+		assert.Equal(t, []string{"select 1 from t", "select 1 from t"}, impls[addr].executed(), "vtgate %s", addr)
+	}
+
+	conn.Close()
+	for _, addr := range []string{"a", "b", "c"} {
+		assert.True(t, impls[addr].closed, "vtgate %s should be closed", addr)
+	}
+}
+
+func TestDialBalancedFailover(t *testing.T) {
+	ctx := context.Background()
+	impls := registerFakeBalancerDialer(t, "balanced-test-failover")
+
+	conn, err := DialBalanced(ctx, "balanced-test-failover", StaticResolver{"a", "b"}, BalancerOptions{HealthCheckInterval: time.Hour})
+	require.NoError(t, err)
+	defer conn.Close()
+
+	session := conn.Session("", nil)
+	_, err = session.Execute(ctx, "begin", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "a", session.SessionPb().TargetString)
+
+	impls["a"].setDown(true)
+
+	// A write fails, and is not retried ...
+	_, err = session.Execute(ctx, "update t set x = 1", nil)
+	require.Error(t, err)
+	assert.Equal(t, vtrpcpb.Code_UNAVAILABLE, vterrors.Code(err))
+	assert.Empty(t, impls["b"].executed())
+
+	// ... but the session has moved to the other vtgate.
+	_, err = session.Execute(ctx, "update t set x = 1", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "b", session.SessionPb().TargetString)
+
+	// Reads are retried on another vtgate.
+	session = conn.Session("", nil)
+	impls["b"].setDown(true)
+	impls["a"].setDown(false)
+
+	_, err = session.Execute(ctx, "/* comment */ select * from t", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "a", session.SessionPb().TargetString)
+
+	// When every vtgate is down, the error is returned.
+	impls["a"].setDown(true)
+	_, err = session.Execute(ctx, "select * from t", nil)
+	require.Error(t, err)
+	assert.Equal(t, vtrpcpb.Code_UNAVAILABLE, vterrors.Code(err))
+}
+
+func TestDialBalancedRelayedUnavailable(t *testing.T) {
+	ctx := context.Background()
+	impls := registerFakeBalancerDialer(t, "balanced-test-relayed-unavailable")
+
+	conn, err := DialBalanced(ctx, "balanced-test-relayed-unavailable", StaticResolver{"a", "b"}, BalancerOptions{HealthCheckInterval: time.Hour})
+	require.NoError(t, err)
+	defer conn.Close()
+
+	session := conn.Session("", nil)
+	_, err = session.Execute(ctx, "select 2", nil)
+	require.NoError(t, err)
+	target := session.SessionPb().TargetString
+	other := "a"
+	if target == "a" {
+		other = "b"
+	}
+
+	// An UNAVAILABLE error relayed by the vtgate is returned as is: the read is
+	// not retried on another vtgate, and the session stays where it is.
+	impls[target].m.Lock()
+	impls[target].relayUnavailable = []string{"select * from t"}
+	impls[target].m.Unlock()
+
+	_, err = session.Execute(ctx, "select * from t", nil)
+	require.Error(t, err)
+	assert.Equal(t, vtrpcpb.Code_UNAVAILABLE, vterrors.Code(err))
+	assert.Empty(t, impls[other].executed())
+
+	_, err = session.Execute(ctx, "select 3", nil)
+	require.NoError(t, err)
+	assert.Equal(t, target, session.SessionPb().TargetString)
+}
+
+func TestDialBalancedResolve(t *testing.T) {
+	ctx := context.Background()
+	impls := registerFakeBalancerDialer(t, "balanced-test-resolve")
+
+	resolver := &fakeResolver{}
+	_, err := DialBalanced(ctx, "balanced-test-resolve", resolver, BalancerOptions{})
+	assert.Error(t, err, "dialing with no addresses should fail")
+
+	resolver.set("a")
+	conn, err := DialBalanced(ctx, "balanced-test-resolve", resolver, BalancerOptions{HealthCheckInterval: 10 * time.Millisecond})
+	require.NoError(t, err)
+	defer conn.Close()
+
+	resolver.set("b")
+	assert.Eventually(t, func() bool {
+		impls["a"].m.Lock()
+		defer impls["a"].m.Unlock()
+		return impls["a"].closed
+	}, 5*time.Second, 10*time.Millisecond, "removed vtgate should be closed")
+
+	_, err = conn.Session("", nil).Execute(ctx, "select 1 from t", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"select 1 from t"}, impls["b"].executed())
+}
+
+func TestIsReadOnlyQuery(t *testing.T) {
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{query: "select 1", want: true},
+		{query: "  SELECT * from t", want: true},
+		{query: "(select 1) union (select 2)", want: true},
+		{query: "/* vt+ foo=bar */ select 1", want: true},
+		{query: "-- comment\nshow tables", want: true},
+		{query: "# comment\ndesc t", want: true},
+		{query: "explain select 1", want: true},
+		{query: "describe t", want: true},
+		{query: "select * from t for update", want: false},
+		{query: "select * from t FOR\nSHARE", want: false},
+		{query: "select * from t lock in share mode", want: false},
+		{query: "select next value from seq", want: false},
+		{query: "select next 5 values from seq", want: false},
+		{query: "select next :n values from seq", want: false},
+		{query: "select get_lock('l', 10)", want: false},
+		{query: "select release_lock('l')", want: false},
+		{query: "select * from t into outfile '/tmp/t'", want: false},
+		{query: "explain analyze select * from t for update", want: false},
+		{query: "select update_time, next_id from t", want: true},
+		{query: "selectx", want: false},
+		{query: "insert into t values (1)", want: false},
+		{query: "update t set x = 1", want: false},
+		{query: "/* select */ delete from t", want: false},
+		{query: "/* unterminated select 1", want: false},
+		{query: "begin", want: false},
+		{query: "", want: false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, isReadOnlyQuery(tt.query), "isReadOnlyQuery(%q)", tt.query)
+	}
+}
@@ -70,7 +70,7 @@ func (conn *VTGateConn) Session(targetString string, options *querypb.ExecuteOpt
 			Options:      options,
 			Autocommit:   true,
 		},
-		impl: conn.impl,
+		impl: conn.sessionImpl(),
 	}
 }
 
@@ -83,10 +83,20 @@ func (sn *VTGateSession) SessionPb() *vtgatepb.Session {
 func (conn *VTGateConn) SessionFromPb(sn *vtgatepb.Session) *VTGateSession {
 	return &VTGateSession{
 		session: sn,
-		impl:    conn.impl,
+		impl:    conn.sessionImpl(),
 	}
 }
 
+// sessionImpl returns the Impl to be used by a new session. For a balanced
+// conn (see DialBalanced), this pins the session to one of the vtgates.
+func (conn *VTGateConn) sessionImpl() Impl {
+	if b, ok := conn.impl.(*balancer); ok {
+		return b.newSession()
+	}
+
+	return conn.impl
+}
+
 // ResolveTransaction resolves the 2pc transaction.
 func (conn *VTGateConn) ResolveTransaction(ctx context.Context, dtid string) error {
 	return conn.impl.ResolveTransaction(ctx, dtid)
@@ -220,8 +230,7 @@ func DeregisterDialer(name string) {
 	delete(dialers, name)
 }
 
-// DialProtocol dials a specific protocol, and returns the *VTGateConn
-func DialProtocol(ctx context.Context, protocol string, address string) (*VTGateConn, error) {
+func getDialer(protocol string) (DialerFunc, error) {
 	dialersM.Lock()
 	dialer, ok := dialers[protocol]
 	dialersM.Unlock()
@@ -229,6 +238,15 @@ func DialProtocol(ctx context.Context, protocol string, address string) (*VTGate
 	if !ok {
 		return nil, fmt.Errorf("no dialer registered for VTGate protocol %s", protocol)
 	}
+	return dialer, nil
+}
+
+// DialProtocol dials a specific protocol, and returns the *VTGateConn
+func DialProtocol(ctx context.Context, protocol string, address string) (*VTGateConn, error) {
+	dialer, err := getDialer(protocol)
+	if err != nil {
+		return nil, err
+	}
 	impl, err := dialer(ctx, address)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,239 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topocustomrule
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/rules"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// Commandline flag to enable the query rules of the global topo.
+var enableQueryRules = false
+
+func registerQueryRulesFlags(fs *pflag.FlagSet) {
+	fs.BoolVar(&enableQueryRules, "topocustomrule_query_rules", enableQueryRules, "apply the query rules of the global topo, managed with the ApplyQueryRules and DeleteQueryRule vtctld RPCs. Each tablet watches the global topo when enabled.")
+}
+
+func init() {
+	servenv.OnParseFor("vttablet", registerQueryRulesFlags)
+}
+
+// topoQueryRulesSource is the rule source name of the query rules of the global topo.
+const topoQueryRulesSource string = "TOPO_QUERY_RULES"
+
+// The following are vars and not consts so the test can change them.
+var (
+	// queryRulesRefreshInterval is how often the query rules are matched
+	// again against the target of the tablet, and their expire time.
+	queryRulesRefreshInterval = time.Second
+	// sleepWithoutQueryRules is how long to sleep before watching again
+	// when the global topo has no query rules yet.
+	sleepWithoutQueryRules = 5 * time.Second
+)
+
+// topoQueryRules applies the query rules of the global topo that are in
+// scope for the tablet, and not expired.
+type topoQueryRules struct {
+	// qsc is set at construction time.
+	qsc tabletserver.Controller
+
+	// now is time.Now, except in tests.
+	now func() time.Time
+
+	// mu protects the following variables.
+	mu sync.Mutex
+
+	// queryRules are the query rules last read from the topo, along with
+	// their parsed definitions.
+	queryRules []*topoQueryRule
+
+	// qrs is the current rule set applied to the tablet.
+	qrs *rules.Rules
+
+	// cancel stops the watch and the refresh of the query rules.
+	cancel func()
+}
+
+type topoQueryRule struct {
+	*topodatapb.QueryRule
+	rule *rules.Rule
+}
+
+func newTopoQueryRules(qsc tabletserver.Controller) *topoQueryRules {
+	return &topoQueryRules{
+		qsc: qsc,
+		now: time.Now,
+		qrs: rules.New(),
+	}
+}
+
+func (tqr *topoQueryRules) start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	tqr.mu.Lock()
+	tqr.cancel = cancel
+	tqr.mu.Unlock()
+
+	go tqr.watch(ctx)
+	go func() {
+		ticker := time.NewTicker(queryRulesRefreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				tqr.mu.Lock()
+				tqr.applyLocked()
+				tqr.mu.Unlock()
+			}
+		}
+	}()
+}
+
+func (tqr *topoQueryRules) stop() {
+	tqr.mu.Lock()
+	defer tqr.mu.Unlock()
+	if tqr.cancel != nil {
+		tqr.cancel()
+	}
+}
+
+// watch keeps the query rules up to date with the global topo until ctx is
+// done. The query rules are kept as they are while the topo is unreachable.
+func (tqr *topoQueryRules) watch(ctx context.Context) {
+	for {
+		current, changes, err := tqr.qsc.TopoServer().WatchQueryRules(ctx)
+		if err == nil {
+			tqr.update(current.Value)
+			for change := range changes {
+				if change.Err != nil {
+					err = change.Err
+					break
+				}
+				tqr.update(change.Value)
+			}
+		}
+
+		sleep := sleepDuringTopoFailure
+		switch {
+		case ctx.Err() != nil:
+			return
+		case topo.IsErrType(err, topo.NoNode):
+			tqr.update(nil)
+			sleep = sleepWithoutQueryRules
+		default:
+			log.Warningf("Background watch of topo query rules failed: %v, sleeping for %v before trying again", err, sleep)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(sleep):
+		}
+	}
+}
+
+// update applies new query rules of the topo. The rules with an invalid
+// definition are skipped.
+func (tqr *topoQueryRules) update(queryRules *topodatapb.QueryRules) {
+	parsed := make([]*topoQueryRule, 0, len(queryRules.GetRules()))
+	for _, qr := range queryRules.GetRules() {
+		rule, err := rules.UnmarshalRule([]byte(qr.Rule))
+		if err != nil {
+			log.Errorf("Skipping invalid topo query rule %v: %v", qr.Name, err)
+			continue
+		}
+		parsed = append(parsed, &topoQueryRule{QueryRule: qr, rule: rule})
+	}
+
+	tqr.mu.Lock()
+	defer tqr.mu.Unlock()
+	tqr.queryRules = parsed
+	tqr.applyLocked()
+}
+
+// applyLocked sets the query rules that apply to the tablet at present, if
+// they changed.
+func (tqr *topoQueryRules) applyLocked() {
+	target := tqr.qsc.CurrentTarget()
+	now := tqr.now()
+
+	qrs := rules.New()
+	for _, qr := range tqr.queryRules {
+		if qr.appliesTo(target, now) {
+			qrs.Add(qr.rule.Copy())
+		}
+	}
+	if qrs.Equal(tqr.qrs) {
+		return
+	}
+	if err := tqr.qsc.SetQueryRules(topoQueryRulesSource, qrs); err != nil {
+		log.Errorf("Failed to apply topo query rules: %v", err)
+		return
+	}
+	tqr.qrs = qrs
+	log.Infof("%d topo query rules applied to vttablet", len(qrs.CopyUnderlying()))
+}
+
+// appliesTo returns true if the tablet with the target is in scope of the
+// query rule, and the query rule is not expired.
+func (qr *topoQueryRule) appliesTo(target *querypb.Target, now time.Time) bool {
+	if qr.Keyspace != "" && qr.Keyspace != target.GetKeyspace() {
+		return false
+	}
+	if len(qr.Shards) > 0 && !slices.Contains(qr.Shards, target.GetShard()) {
+		return false
+	}
+	if len(qr.TabletTypes) > 0 && !slices.Contains(qr.TabletTypes, target.GetTabletType()) {
+		return false
+	}
+	if qr.ExpireTime != nil && !now.Before(protoutil.TimeFromProto(qr.ExpireTime)) {
+		return false
+	}
+	return true
+}
+
+// activateTopoQueryRules activates the query rules of the global topo.
+func activateTopoQueryRules(qsc tabletserver.Controller) {
+	if !enableQueryRules || qsc.TopoServer() == nil {
+		return
+	}
+	qsc.RegisterQueryRuleSource(topoQueryRulesSource)
+
+	tqr := newTopoQueryRules(qsc)
+	tqr.start()
+
+	servenv.OnTerm(tqr.stop)
+}
+
+func init() {
+	tabletserver.RegisterFunctions = append(tabletserver.RegisterFunctions, activateTopoQueryRules)
+}
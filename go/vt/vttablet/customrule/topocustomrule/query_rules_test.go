@@ -0,0 +1,143 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topocustomrule
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/rules"
+	"vitess.io/vitess/go/vt/vttablet/tabletservermock"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// ruleNames returns the names of the rules of the topo query rules source.
+func ruleNames(qsc *tabletservermock.Controller) []string {
+	var names []string
+	for _, qr := range qsc.GetQueryRules(topoQueryRulesSource).CopyUnderlying() {
+		names = append(names, qr.Name)
+	}
+	return names
+}
+
+func TestTopoQueryRulesScope(t *testing.T) {
+	now := time.Now()
+	queryRules := &topodatapb.QueryRules{
+		Rules: []*topodatapb.QueryRule{
+			{Name: "all", Rule: `{"Name": "all", "Query": "select.*"}`},
+			{Name: "ks1", Rule: `{"Name": "ks1"}`, Keyspace: "ks1"},
+			{Name: "ks2", Rule: `{"Name": "ks2"}`, Keyspace: "ks2"},
+			{Name: "shard", Rule: `{"Name": "shard"}`, Keyspace: "ks1", Shards: []string{"-80"}},
+			{Name: "replica", Rule: `{"Name": "replica"}`, TabletTypes: []topodatapb.TabletType{topodatapb.TabletType_REPLICA}},
+			{Name: "expiring", Rule: `{"Name": "expiring"}`, ExpireTime: protoutil.TimeToProto(now.Add(time.Minute))},
+			{Name: "expired", Rule: `{"Name": "expired"}`, ExpireTime: protoutil.TimeToProto(now)},
+			{Name: "invalid", Rule: `{"Name": "invalid", "Action": "IGNORE"}`},
+		},
+	}
+
+	qsc := tabletservermock.NewController()
+	err := qsc.InitDBConfig(&querypb.Target{Keyspace: "ks1", Shard: "-80", TabletType: topodatapb.TabletType_PRIMARY}, nil, nil)
+	require.NoError(t, err)
+
+	tqr := newTopoQueryRules(qsc)
+	tqr.now = func() time.Time { return now }
+	tqr.update(queryRules)
+	assert.Equal(t, []string{"all", "ks1", "shard", "expiring"}, ruleNames(qsc))
+
+	// The rules follow the tablet type, and expire.
+	qsc.SetServingType(topodatapb.TabletType_REPLICA, time.Time{}, true, "")
+	now = now.Add(time.Minute)
+	tqr.mu.Lock()
+	tqr.applyLocked()
+	tqr.mu.Unlock()
+	assert.Equal(t, []string{"all", "ks1", "shard", "replica"}, ruleNames(qsc))
+
+	// Removing the query rules from the topo clears them.
+	tqr.update(nil)
+	assert.Empty(t, ruleNames(qsc))
+}
+
+func TestTopoQueryRulesWatch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ts := memorytopo.NewServer(ctx, "cell1")
+	defer ts.Close()
+	qsc := tabletservermock.NewController()
+	qsc.TS = ts
+	err := qsc.InitDBConfig(&querypb.Target{Keyspace: "ks1", Shard: "0", TabletType: topodatapb.TabletType_PRIMARY}, nil, nil)
+	require.NoError(t, err)
+
+	sleepWithoutQueryRules = time.Millisecond
+	queryRulesRefreshInterval = time.Millisecond
+
+	tqr := newTopoQueryRules(qsc)
+	tqr.start()
+	defer tqr.stop()
+
+	// The first query rules are picked up once they are created.
+	dryRun, err := rules.UnmarshalRule([]byte(`{"Name": "r1", "Query": "select.*", "DryRun": true}`))
+	require.NoError(t, err)
+	_, err = ts.UpdateQueryRules(ctx, func(queryRules *topodatapb.QueryRules) error {
+		queryRules.Rules = []*topodatapb.QueryRule{{Name: "r1", Rule: `{"Name": "r1", "Query": "select.*", "DryRun": true}`}}
+		return nil
+	})
+	require.NoError(t, err)
+	want := rules.New()
+	want.Add(dryRun)
+	waitForValueFromSource(t, qsc, topoQueryRulesSource, want)
+
+	// A rule expires while the query rules of the topo do not change.
+	_, err = ts.UpdateQueryRules(ctx, func(queryRules *topodatapb.QueryRules) error {
+		queryRules.Rules[0].ExpireTime = protoutil.TimeToProto(time.Now().Add(100 * time.Millisecond))
+		return nil
+	})
+	require.NoError(t, err)
+	waitForValueFromSource(t, qsc, topoQueryRulesSource, rules.New())
+}
+
+func TestTopoQueryRulesDisabledByDefault(t *testing.T) {
+	fs := pflag.NewFlagSet("vttablet", pflag.ContinueOnError)
+	registerQueryRulesFlags(fs)
+	assert.Equal(t, "false", fs.Lookup("topocustomrule_query_rules").DefValue)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ts := memorytopo.NewServer(ctx, "cell1")
+	defer ts.Close()
+	_, err := ts.UpdateQueryRules(ctx, func(queryRules *topodatapb.QueryRules) error {
+		queryRules.Rules = []*topodatapb.QueryRule{{Name: "r1", Rule: `{"Name": "r1", "Query": "select.*"}`}}
+		return nil
+	})
+	require.NoError(t, err)
+	qsc := tabletservermock.NewController()
+	qsc.TS = ts
+
+	// Without the flag, the tablet does not watch the query rules of the topo.
+	activateTopoQueryRules(qsc)
+	assert.Nil(t, qsc.GetQueryRules(topoQueryRulesSource))
+}
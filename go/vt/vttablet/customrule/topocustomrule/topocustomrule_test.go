@@ -50,9 +50,13 @@ var customRule2 = `
 ]`
 
 func waitForValue(t *testing.T, qsc *tabletservermock.Controller, expected *rules.Rules) {
+	waitForValueFromSource(t, qsc, topoCustomRuleSource, expected)
+}
+
+func waitForValueFromSource(t *testing.T, qsc *tabletservermock.Controller, ruleSource string, expected *rules.Rules) {
 	start := time.Now()
 	for {
-		val := qsc.GetQueryRules(topoCustomRuleSource)
+		val := qsc.GetQueryRules(ruleSource)
 		if val != nil {
 			if val.Equal(expected) {
 				return
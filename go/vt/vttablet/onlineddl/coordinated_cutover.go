@@ -0,0 +1,244 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package onlineddl
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/schema"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// coordinatedCutOver is a cut-over of a --coordinated-cutover migration, driven by vtctld across all
+// shards of the keyspace. The cut-over runs up to the point where the tables are locked, the RENAME
+// is blocked and vreplication is caught up and stopped. It then reports that it is prepared, and
+// holds everything in place until it is told to either commit the RENAME or abort.
+type coordinatedCutOver struct {
+	uuid string
+	// decisionTimeout is how long the prepared cut-over waits for the coordinator's decision. Zero
+	// stands for the migration's cut-over threshold.
+	decisionTimeout time.Duration
+
+	preparedOnce sync.Once
+	// prepared receives the outcome of the prepare phase
+	prepared chan error
+	decided  atomic.Bool
+	// decision receives true to commit the cut-over, false to abort it
+	decision chan bool
+	// done receives the outcome of the cut-over
+	done chan error
+}
+
+func newCoordinatedCutOver(uuid string, decisionTimeout time.Duration) *coordinatedCutOver {
+	return &coordinatedCutOver{
+		uuid:            uuid,
+		decisionTimeout: decisionTimeout,
+		prepared:        make(chan error, 1),
+		decision:        make(chan bool, 1),
+		done:            make(chan error, 1),
+	}
+}
+
+// timeout returns how long the prepared cut-over waits for the coordinator's decision, given the
+// migration's cut-over threshold.
+func (c *coordinatedCutOver) timeout(cutOverThreshold time.Duration) time.Duration {
+	if c.decisionTimeout != 0 {
+		return c.decisionTimeout
+	}
+	return cutOverThreshold
+}
+
+// markPrepared reports the outcome of the prepare phase. Only the first report counts.
+func (c *coordinatedCutOver) markPrepared(err error) {
+	c.preparedOnce.Do(func() {
+		c.prepared <- err
+	})
+}
+
+// decide sends the coordinator's decision, and returns false if a decision was already made.
+func (c *coordinatedCutOver) decide(commit bool) bool {
+	if !c.decided.CompareAndSwap(false, true) {
+		return false
+	}
+	c.decision <- commit
+	return true
+}
+
+// awaitDecision is called by the cut-over once it is prepared. It reports readiness, then blocks
+// until the coordinator commits or aborts. A cut-over that is not decided within the given timeout
+// is aborted, so that a lost coordinator does not leave the tables locked.
+func (c *coordinatedCutOver) awaitDecision(timeout time.Duration) error {
+	c.markPrepared(nil)
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case commit := <-c.decision:
+		if commit {
+			return nil
+		}
+		return vterrors.Errorf(vtrpcpb.Code_ABORTED, "coordinated cut-over of migration %s aborted", c.uuid)
+	case <-timer.C:
+		c.decided.Store(true)
+		return vterrors.Errorf(vtrpcpb.Code_DEADLINE_EXCEEDED, "coordinated cut-over of migration %s was neither committed nor aborted within %v", c.uuid, timeout)
+	}
+}
+
+// finish reports the outcome of the cut-over. A cut-over that failed before it was prepared also
+// reports its error as the outcome of the prepare phase.
+func (c *coordinatedCutOver) finish(err error) {
+	c.markPrepared(err)
+	c.done <- err
+}
+
+// runCoordinatedCutOver validates that the migration can be cut over, and runs its cut-over. It
+// holds the migration mutex throughout, so that the scheduler does not interfere.
+func (e *Executor) runCoordinatedCutOver(ctx context.Context, c *coordinatedCutOver) error {
+	e.migrationMutex.Lock()
+	defer e.migrationMutex.Unlock()
+
+	onlineDDL, row, err := e.readMigration(ctx, c.uuid)
+	if err != nil {
+		return err
+	}
+	if !onlineDDL.StrategySetting().IsCoordinatedCutOverFlag() {
+		return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "migration %s does not use --coordinated-cutover", c.uuid)
+	}
+	if onlineDDL.Status != schema.OnlineDDLStatusRunning {
+		return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "migration %s is not running: %s", c.uuid, onlineDDL.Status)
+	}
+	if onlineDDL.ReadyToComplete == 0 {
+		return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "migration %s is not ready to complete", c.uuid)
+	}
+	s, err := e.readVReplStream(ctx, c.uuid, true)
+	if err != nil {
+		return err
+	}
+	if s == nil || !s.isRunning() {
+		return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "vreplication stream of migration %s is not running", c.uuid)
+	}
+	return e.cutOverVReplMigration(ctx, s, row.AsBool("force_cutover", false), c)
+}
+
+// PrepareCutOver runs the first phase of a coordinated cut-over: it locks the migrated table, blocks
+// the RENAME, and waits for vreplication to catch up. It returns once the cut-over is prepared,
+// leaving it in place until CommitCutOver or AbortCutOver is called, or until the given expiry,
+// which defaults to the migration's cut-over threshold, passes.
+func (e *Executor) PrepareCutOver(ctx context.Context, uuid string, expireString string) (result *sqltypes.Result, err error) {
+	if atomic.LoadInt64(&e.isOpen) == 0 {
+		return nil, vterrors.New(vtrpcpb.Code_FAILED_PRECONDITION, schema.ErrOnlineDDLDisabled.Error())
+	}
+	if !schema.IsOnlineDDLUUID(uuid) {
+		return nil, vterrors.Errorf(vtrpcpb.Code_UNKNOWN, "Not a valid migration ID in PREPARE_CUTOVER: %s", uuid)
+	}
+	var decisionTimeout time.Duration
+	if expireString != "" {
+		decisionTimeout, err = time.ParseDuration(expireString)
+		if err != nil || decisionTimeout <= 0 {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid EXPIRE value: %s. Try '120s', '30m', '1h', etc. Allowed units are (s)ec, (m)in, (h)hour", expireString)
+		}
+	}
+	log.Infof("PrepareCutOver: request to prepare cut-over of migration %s", uuid)
+
+	c := newCoordinatedCutOver(uuid, decisionTimeout)
+	if _, loaded := e.coordinatedCutOvers.LoadOrStore(uuid, c); loaded {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "cut-over of migration %s is already prepared", uuid)
+	}
+	go func() {
+		// The cut-over outlives this request: it holds its locks until it is committed or aborted.
+		defer e.coordinatedCutOvers.Delete(uuid)
+		c.finish(e.runCoordinatedCutOver(context.Background(), c))
+	}()
+
+	select {
+	case err := <-c.prepared:
+		if err != nil {
+			return nil, err
+		}
+	case <-ctx.Done():
+		c.decide(false)
+		return nil, ctx.Err()
+	}
+	log.Infof("PrepareCutOver: cut-over of migration %s prepared", uuid)
+	return &sqltypes.Result{RowsAffected: 1}, nil
+}
+
+// CommitCutOver runs the second phase of a prepared coordinated cut-over: it releases the locks,
+// letting the RENAME complete, and waits for the migration to complete.
+func (e *Executor) CommitCutOver(ctx context.Context, uuid string) (result *sqltypes.Result, err error) {
+	if atomic.LoadInt64(&e.isOpen) == 0 {
+		return nil, vterrors.New(vtrpcpb.Code_FAILED_PRECONDITION, schema.ErrOnlineDDLDisabled.Error())
+	}
+	if !schema.IsOnlineDDLUUID(uuid) {
+		return nil, vterrors.Errorf(vtrpcpb.Code_UNKNOWN, "Not a valid migration ID in COMMIT_CUTOVER: %s", uuid)
+	}
+	log.Infof("CommitCutOver: request to commit cut-over of migration %s", uuid)
+
+	v, ok := e.coordinatedCutOvers.Load(uuid)
+	if !ok {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "cut-over of migration %s is not prepared", uuid)
+	}
+	c := v.(*coordinatedCutOver)
+	if !c.decide(true) {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "cut-over of migration %s is already committed or aborted", uuid)
+	}
+	select {
+	case err := <-c.done:
+		if err != nil {
+			return nil, err
+		}
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	log.Infof("CommitCutOver: cut-over of migration %s committed", uuid)
+	return &sqltypes.Result{RowsAffected: 1}, nil
+}
+
+// AbortCutOver aborts a prepared coordinated cut-over: the RENAME is killed, the locks released and
+// vreplication restarted. The migration keeps running. Aborting a cut-over that is not prepared is
+// a no-op, so that the coordinator can abort on all shards regardless of which ones prepared.
+func (e *Executor) AbortCutOver(ctx context.Context, uuid string) (result *sqltypes.Result, err error) {
+	if atomic.LoadInt64(&e.isOpen) == 0 {
+		return nil, vterrors.New(vtrpcpb.Code_FAILED_PRECONDITION, schema.ErrOnlineDDLDisabled.Error())
+	}
+	if !schema.IsOnlineDDLUUID(uuid) {
+		return nil, vterrors.Errorf(vtrpcpb.Code_UNKNOWN, "Not a valid migration ID in ABORT_CUTOVER: %s", uuid)
+	}
+	log.Infof("AbortCutOver: request to abort cut-over of migration %s", uuid)
+
+	v, ok := e.coordinatedCutOvers.Load(uuid)
+	if !ok {
+		return &sqltypes.Result{}, nil
+	}
+	c := v.(*coordinatedCutOver)
+	if !c.decide(false) {
+		return &sqltypes.Result{}, nil
+	}
+	select {
+	case <-c.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	log.Infof("AbortCutOver: cut-over of migration %s aborted", uuid)
+	return &sqltypes.Result{RowsAffected: 1}, nil
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package onlineddl
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoordinatedCutOver(t *testing.T) {
+	uuid := "6842c23d_0ad8_11ef_8b5c_0a43f95f28a3"
+
+	t.Run("commit", func(t *testing.T) {
+		c := newCoordinatedCutOver(uuid, 0)
+		go func() {
+			c.finish(c.awaitDecision(time.Minute))
+		}()
+		require.NoError(t, <-c.prepared)
+		assert.True(t, c.decide(true))
+		assert.False(t, c.decide(false))
+		assert.NoError(t, <-c.done)
+	})
+	t.Run("abort", func(t *testing.T) {
+		c := newCoordinatedCutOver(uuid, 0)
+		go func() {
+			c.finish(c.awaitDecision(time.Minute))
+		}()
+		require.NoError(t, <-c.prepared)
+		assert.True(t, c.decide(false))
+		assert.ErrorContains(t, <-c.done, "aborted")
+	})
+	t.Run("timeout", func(t *testing.T) {
+		c := newCoordinatedCutOver(uuid, 0)
+		go func() {
+			c.finish(c.awaitDecision(10 * time.Millisecond))
+		}()
+		require.NoError(t, <-c.prepared)
+		assert.ErrorContains(t, <-c.done, "neither committed nor aborted")
+		assert.False(t, c.decide(true))
+	})
+	t.Run("prepare failure", func(t *testing.T) {
+		c := newCoordinatedCutOver(uuid, 0)
+		c.finish(errors.New("lock wait timeout"))
+		assert.EqualError(t, <-c.prepared, "lock wait timeout")
+		assert.EqualError(t, <-c.done, "lock wait timeout")
+	})
+	t.Run("decision timeout", func(t *testing.T) {
+		assert.Equal(t, 10*time.Second, newCoordinatedCutOver(uuid, 0).timeout(10*time.Second))
+		assert.Equal(t, time.Minute, newCoordinatedCutOver(uuid, time.Minute).timeout(10*time.Second))
+	})
+}
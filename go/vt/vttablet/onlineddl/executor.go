@@ -198,6 +198,8 @@ type Executor struct {
 	vreplicationLastError         map[string]*vterrors.LastError
 	tickReentranceFlag            int64
 	reviewedRunningMigrationsFlag bool
+	// coordinatedCutOvers lists the coordinated cut-overs in progress (consider this a map[string]*coordinatedCutOver)
+	coordinatedCutOvers sync.Map
 
 	ticks  *timer.Timer
 	isOpen int64
@@ -877,8 +879,9 @@ func (e *Executor) killTableLockHoldersAndAccessors(ctx context.Context, tableNa
 	return nil
 }
 
-// cutOverVReplMigration stops vreplication, then removes the _vt.vreplication entry for the given migration
-func (e *Executor) cutOverVReplMigration(ctx context.Context, s *VReplStream, shouldForceCutOver bool) error {
+// cutOverVReplMigration stops vreplication, then removes the _vt.vreplication entry for the given migration.
+// A coordinated cut-over is held in place once prepared, until the coordinator commits or aborts it.
+func (e *Executor) cutOverVReplMigration(ctx context.Context, s *VReplStream, shouldForceCutOver bool, coordinated *coordinatedCutOver) error {
 	if err := e.incrementCutoverAttempts(ctx, s.workflow); err != nil {
 		return err
 	}
@@ -1045,6 +1048,10 @@ func (e *Executor) cutOverVReplMigration(ctx context.Context, s *VReplStream, sh
 	toggleBuffering := func(bufferQueries bool) error {
 		log.Infof("toggling buffering: %t in migration %v", bufferQueries, onlineDDL.UUID)
 		timeout := migrationCutOverThreshold + qrBufferExtraTimeout
+		if coordinated != nil {
+			// Queries stay buffered while the prepared cut-over waits for the coordinator's decision.
+			timeout += coordinated.timeout(migrationCutOverThreshold)
+		}
 
 		e.toggleBufferTableFunc(bufferingCtx, onlineDDL.Table, timeout, bufferQueries)
 		if !bufferQueries {
@@ -1157,6 +1164,22 @@ func (e *Executor) cutOverVReplMigration(ctx context.Context, s *VReplStream, sh
 	}
 	go log.Infof("cutOverVReplMigration %v: stopped vreplication", s.workflow)
 
+	if coordinated != nil {
+		// Tables are locked, the RENAME is blocked and vreplication is caught up and stopped: other
+		// shards may now prepare their own cut-over, while we wait for all of them to be prepared.
+		e.updateMigrationStage(ctx, onlineDDL.UUID, "cut-over prepared, waiting for coordinated commit")
+		if err := coordinated.awaitDecision(coordinated.timeout(migrationCutOverThreshold)); err != nil {
+			e.updateMigrationStage(ctx, onlineDDL.UUID, "coordinated cut-over aborted: %v", err)
+			// The deferred functions kill the RENAME, unlock the tables and re-enable writes. The
+			// migration keeps running, for a future cut-over attempt.
+			if _, err := e.vreplicationExec(ctx, tablet.Tablet, binlogplayer.StartVReplication(s.id)); err != nil {
+				log.Errorf("cutOverVReplMigration %v: failed restarting vreplication: %v", s.workflow, err)
+			}
+			return err
+		}
+		e.updateMigrationStage(ctx, onlineDDL.UUID, "coordinated cut-over committed")
+	}
+
 	// rename tables atomically (remember, writes on source tables are stopped)
 	{
 		if isVreplicationTestSuite {
@@ -3739,6 +3762,17 @@ func shouldCutOverAccordingToBackoff(
 	return false, false
 }
 
+// isWithinCutOverWindow is called when a vitess migration (ALTER TABLE) is generally ready to cut-over.
+// It returns true when the migration has no `--cut-over-window`, or when the current time is within it.
+// A user-forced cut-over (the `force_cutover` column) overrides the window. `--force-cut-over-after`
+// does not: such a migration is forced once the window opens.
+func isWithinCutOverWindow(cutOverWindow *schema.CutOverWindow, shouldForceCutOverIndicator bool, now time.Time) bool {
+	if cutOverWindow == nil || shouldForceCutOverIndicator {
+		return true
+	}
+	return cutOverWindow.Contains(now)
+}
+
 // reviewRunningMigrations iterates migrations in 'running' state. Normally there's only one running, which was
 // spawned by this tablet; but vreplication migrations could also resume from failure.
 func (e *Executor) reviewRunningMigrations(ctx context.Context) (countRunnning int, cancellable []*cancellableMigration, err error) {
@@ -3788,6 +3822,12 @@ func (e *Executor) reviewRunningMigrations(ctx context.Context) (countRunnning i
 		if errForceCutOverAfter != nil {
 			forceCutOverAfter = 0
 		}
+		// Likewise, the cut-over window is validated on submission. An invalid window is
+		// ignored, so that the migration is not blocked forever.
+		var cutOverWindow *schema.CutOverWindow
+		if spec := row["cutover_window"].ToString(); spec != "" {
+			cutOverWindow, _ = schema.ParseCutOverWindow(spec)
+		}
 
 		uuidsFoundRunning[uuid] = true
 
@@ -3866,19 +3906,28 @@ func (e *Executor) reviewRunningMigrations(ctx context.Context) (countRunnning i
 					// override. Even if migration is ready, we do not complete it.
 					return nil
 				}
+				if strategySetting.IsCoordinatedCutOverFlag() {
+					// The cut-over is coordinated by vtctld across all shards, via PREPARE_CUTOVER and COMMIT_CUTOVER.
+					return nil
+				}
 				if strategySetting.IsInOrderCompletion() {
 					if len(pendingMigrationsUUIDs) > 0 && pendingMigrationsUUIDs[0] != onlineDDL.UUID {
 						// wait for earlier pending migrations to complete
 						return nil
 					}
 				}
+				if !isWithinCutOverWindow(cutOverWindow, shouldForceCutOver, time.Now()) {
+					// Keep tailing the binary logs until the window opens.
+					e.updateMigrationStage(ctx, onlineDDL.UUID, "waiting for cut-over window: %s", cutOverWindow)
+					return nil
+				}
 				shouldCutOver, shouldForceCutOver := shouldCutOverAccordingToBackoff(
 					shouldForceCutOver, forceCutOverAfter, sinceReadyToComplete, sinceLastCutoverAttempt, cutoverAttempts,
 				)
 				if !shouldCutOver {
 					return nil
 				}
-				if err := e.cutOverVReplMigration(ctx, s, shouldForceCutOver); err != nil {
+				if err := e.cutOverVReplMigration(ctx, s, shouldForceCutOver, nil); err != nil {
 					_ = e.updateMigrationMessage(ctx, uuid, err.Error())
 					log.Errorf("cutOverVReplMigration failed: err=%v", err)
 					if merr, ok := err.(*sqlerror.SQLError); ok {
@@ -5052,6 +5101,11 @@ func (e *Executor) SubmitMigration(
 		retainArtifactsSeconds = int64((retainArtifacts).Seconds())
 	}
 
+	cutOverWindow := ""
+	if w, _ := onlineDDL.StrategySetting().CutOverWindow(); w != nil {
+		cutOverWindow = w.String()
+	}
+
 	_, allowConcurrentMigration := e.allowConcurrentMigration(onlineDDL)
 	submitQuery, err := sqlparser.ParseAndBind(sqlInsertMigration,
 		sqltypes.StringBindVariable(onlineDDL.UUID),
@@ -5072,6 +5126,7 @@ func (e *Executor) SubmitMigration(
 		sqltypes.BoolBindVariable(allowConcurrentMigration),
 		sqltypes.StringBindVariable(revertedUUID),
 		sqltypes.BoolBindVariable(onlineDDL.IsView(e.env.Environment().Parser())),
+		sqltypes.StringBindVariable(cutOverWindow),
 	)
 	if err != nil {
 		return nil, err
@@ -491,3 +491,49 @@ func TestShouldCutOverAccordingToBackoff(t *testing.T) {
 		})
 	}
 }
+
+func TestIsWithinCutOverWindow(t *testing.T) {
+	window, err := schema.ParseCutOverWindow("Mon-Fri 02:00-04:00 UTC")
+	require.NoError(t, err)
+	// 2024-01-01 is a Monday
+	inWindow := time.Date(2024, 1, 1, 3, 0, 0, 0, time.UTC)
+	outOfWindow := time.Date(2024, 1, 1, 5, 0, 0, 0, time.UTC)
+
+	tcases := []struct {
+		name                        string
+		cutOverWindow               *schema.CutOverWindow
+		shouldForceCutOverIndicator bool
+		now                         time.Time
+		expect                      bool
+	}{
+		{
+			name:   "no window",
+			now:    outOfWindow,
+			expect: true,
+		},
+		{
+			name:          "within window",
+			cutOverWindow: window,
+			now:           inWindow,
+			expect:        true,
+		},
+		{
+			name:          "outside window",
+			cutOverWindow: window,
+			now:           outOfWindow,
+			expect:        false,
+		},
+		{
+			name:                        "forced cut-over overrides window",
+			cutOverWindow:               window,
+			shouldForceCutOverIndicator: true,
+			now:                         outOfWindow,
+			expect:                      true,
+		},
+	}
+	for _, tcase := range tcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			assert.Equal(t, tcase.expect, isWithinCutOverWindow(tcase.cutOverWindow, tcase.shouldForceCutOverIndicator, tcase.now))
+		})
+	}
+}
@@ -36,9 +36,10 @@ const (
 		postpone_completion,
 		allow_concurrent,
 		reverted_uuid,
-		is_view
+		is_view,
+		cutover_window
 	) VALUES (
-		%a, %a, %a, %a, %a, %a, %a, %a, %a, NOW(6), %a, %a, %a, %a, %a, %a, %a, %a, %a
+		%a, %a, %a, %a, %a, %a, %a, %a, %a, NOW(6), %a, %a, %a, %a, %a, %a, %a, %a, %a, %a
 	)`
 
 	sqlSelectQueuedMigrations = `SELECT
@@ -296,6 +297,7 @@ const (
 			migration_uuid,
 			postpone_completion,
 			force_cutover,
+			cutover_window,
 			cutover_attempts,
 			ifnull(timestampdiff(second, ready_to_complete_timestamp, now()), 0) as seconds_since_ready_to_complete,
 			ifnull(timestampdiff(second, last_cutover_attempt_timestamp, now()), 0) as seconds_since_last_cutover_attempt,
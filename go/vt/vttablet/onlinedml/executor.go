@@ -0,0 +1,638 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package onlinedml runs batch DML jobs: UPDATE and DELETE statements submitted with the BATCH_DML
+query directive. Rather than running the statement in a single, potentially huge, transaction, the
+Executor walks the table's primary key and applies the statement to one range of rows at a time,
+each in its own small transaction. Batches are gated by the tablet throttler, and the progress of a
+job is checkpointed in _vt.dml_jobs along with each batch, so that a job can be paused, resumed,
+or survive a failover without applying the statement twice to the same rows.
+*/
+
+package onlinedml
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"vitess.io/vitess/go/constants/sidecar"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/timer"
+	"vitess.io/vitess/go/vt/log"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/schema"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/connpool"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/tabletenv"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/throttle"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/throttle/throttlerapp"
+)
+
+var (
+	// ErrJobNotFound is returned by readJob when the given UUID cannot be found
+	ErrJobNotFound = vterrors.New(vtrpcpb.Code_NOT_FOUND, "batch DML job not found")
+	// ErrExecutorClosed is returned when the executor is asked to submit or show jobs while it is not open,
+	// which is the case on any non-primary tablet
+	ErrExecutorClosed = vterrors.New(vtrpcpb.Code_FAILED_PRECONDITION, "batch DML jobs can only run on an open primary tablet")
+)
+
+var (
+	jobCheckInterval      = 10 * time.Second
+	jobNextCheckIntervals = []time.Duration{time.Second, 5 * time.Second}
+)
+
+const databasePoolSize = 3
+
+// Executor runs batch DML jobs on a primary tablet. At most one job runs at any given time; jobs
+// run in the order in which they were submitted.
+type Executor struct {
+	env             tabletenv.Env
+	pool            *connpool.Pool
+	throttlerClient *throttle.Client
+	tabletAlias     *topodatapb.TabletAlias
+
+	keyspace string
+	shard    string
+	dbName   string
+
+	initMutex          sync.Mutex
+	tickReentranceFlag int64
+	cancelJobs         context.CancelFunc
+	jobsWaitGroup      sync.WaitGroup
+
+	ticks  *timer.Timer
+	isOpen int64
+}
+
+// NewExecutor creates a new batch DML executor.
+func NewExecutor(env tabletenv.Env, tabletAlias *topodatapb.TabletAlias, lagThrottler *throttle.Throttler) *Executor {
+	return &Executor{
+		env:             env,
+		tabletAlias:     tabletAlias.CloneVT(),
+		throttlerClient: throttle.NewBackgroundClient(lagThrottler, throttlerapp.OnlineDMLName, throttle.ThrottleCheckPrimaryWrite),
+		pool: connpool.NewPool(env, "OnlineDMLExecutorPool", tabletenv.ConnPoolConfig{
+			Size:        databasePoolSize,
+			IdleTimeout: env.Config().OltpReadPool.IdleTimeout,
+		}),
+		ticks: timer.NewTimer(jobCheckInterval),
+	}
+}
+
+// InitDBConfig initializes keyspace
+func (e *Executor) InitDBConfig(keyspace, shard, dbName string) {
+	e.keyspace = keyspace
+	e.shard = shard
+	e.dbName = dbName
+}
+
+// Open opens database pool and starts running jobs
+func (e *Executor) Open() error {
+	e.initMutex.Lock()
+	defer e.initMutex.Unlock()
+	if atomic.LoadInt64(&e.isOpen) > 0 {
+		return nil
+	}
+	log.Infof("onlineDML Executor Open()")
+
+	var ctx context.Context
+	ctx, e.cancelJobs = context.WithCancel(context.Background())
+	e.pool.Open(e.env.Config().DB.AppWithDB(), e.env.Config().DB.DbaWithDB(), e.env.Config().DB.AppDebugWithDB())
+	e.ticks.Start(func() { e.onJobCheckTick(ctx) })
+	e.triggerNextCheckInterval()
+
+	atomic.StoreInt64(&e.isOpen, 1)
+	return nil
+}
+
+// Close stops the running job, if any, and frees resources. The job resumes from its last
+// checkpoint once the executor opens again, here or on a new primary.
+func (e *Executor) Close() {
+	e.initMutex.Lock()
+	defer e.initMutex.Unlock()
+	if atomic.LoadInt64(&e.isOpen) == 0 {
+		return
+	}
+	log.Infof("onlineDML Executor Close()")
+
+	e.ticks.Stop()
+	e.cancelJobs()
+	e.jobsWaitGroup.Wait()
+	e.pool.Close()
+	atomic.StoreInt64(&e.isOpen, 0)
+}
+
+// triggerNextCheckInterval the next tick sooner than normal
+func (e *Executor) triggerNextCheckInterval() {
+	for _, interval := range jobNextCheckIntervals {
+		e.ticks.TriggerAfter(interval)
+	}
+}
+
+func (e *Executor) tabletAliasString() string {
+	return topoproto.TabletAliasString(e.tabletAlias)
+}
+
+// execQuery runs a query on a pooled connection. Queries on the sidecar database are written
+// against the default sidecar database name, and are rewritten if another name is in use.
+func (e *Executor) execQuery(ctx context.Context, query string) (result *sqltypes.Result, err error) {
+	defer e.env.LogError()
+
+	conn, err := e.pool.Get(ctx, nil)
+	if err != nil {
+		return result, err
+	}
+	defer conn.Recycle()
+
+	if query, err = e.withSidecarDBReplacement(query); err != nil {
+		return nil, err
+	}
+	return conn.Conn.Exec(ctx, query, -1, true)
+}
+
+func (e *Executor) withSidecarDBReplacement(query string) (string, error) {
+	if sidecar.GetName() == sidecar.DefaultName {
+		return query, nil
+	}
+	return e.env.Environment().Parser().ReplaceTableQualifiers(query, sidecar.DefaultName, sidecar.GetName())
+}
+
+// SubmitJob queues a new batch DML job. The statement is expected to carry the BATCH_DML directive
+// along with the job's UUID, as generated by schema.NewOnlineDML.
+func (e *Executor) SubmitJob(ctx context.Context, stmt sqlparser.Statement) (*sqltypes.Result, error) {
+	if atomic.LoadInt64(&e.isOpen) == 0 {
+		return nil, ErrExecutorClosed
+	}
+	onlineDML, err := schema.OnlineDMLFromCommentedStatement(stmt)
+	if err != nil {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "error submitting batch DML job %s: %v", sqlparser.String(stmt), err)
+	}
+	log.Infof("SubmitJob: request to submit batch DML job %s on table %s", onlineDML.UUID, onlineDML.Table)
+
+	query, err := sqlparser.ParseAndBind(sqlInsertJob,
+		sqltypes.StringBindVariable(onlineDML.UUID),
+		sqltypes.StringBindVariable(e.keyspace),
+		sqltypes.StringBindVariable(e.shard),
+		sqltypes.StringBindVariable(e.dbName),
+		sqltypes.StringBindVariable(onlineDML.Table),
+		sqltypes.StringBindVariable(onlineDML.SQL),
+		sqltypes.StringBindVariable(onlineDML.JobContext),
+		sqltypes.StringBindVariable(string(schema.OnlineDMLStatusQueued)),
+		sqltypes.Int64BindVariable(onlineDML.BatchSize),
+		sqltypes.StringBindVariable(e.tabletAliasString()),
+	)
+	if err != nil {
+		return nil, err
+	}
+	// The insert is idempotent: resubmitting a job with the same UUID is a no-op.
+	result, err := e.execQuery(ctx, query)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "submitting batch DML job %v", onlineDML.UUID)
+	}
+	defer e.triggerNextCheckInterval()
+	return result, nil
+}
+
+// ShowJobs shows batch DML jobs, optionally filtered by a condition
+func (e *Executor) ShowJobs(ctx context.Context, show *sqlparser.Show) (result *sqltypes.Result, err error) {
+	if atomic.LoadInt64(&e.isOpen) == 0 {
+		return nil, ErrExecutorClosed
+	}
+	showBasic, ok := show.Internal.(*sqlparser.ShowBasic)
+	if !ok || showBasic.Command != sqlparser.VitessDMLJobs {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "[BUG] ShowJobs expects a VitessDMLJobs command. Statement: %s", sqlparser.String(show))
+	}
+	whereExpr := ""
+	if showBasic.Filter != nil {
+		if showBasic.Filter.Filter != nil {
+			whereExpr = fmt.Sprintf("where %s", sqlparser.String(showBasic.Filter.Filter))
+		} else if showBasic.Filter.Like != "" {
+			lit := sqlparser.String(sqlparser.NewStrLiteral(showBasic.Filter.Like))
+			whereExpr = fmt.Sprintf("where job_uuid LIKE %s OR job_context LIKE %s OR job_status LIKE %s", lit, lit, lit)
+		}
+	}
+	return e.execQuery(ctx, fmt.Sprintf(sqlShowJobsWhere, whereExpr))
+}
+
+// onJobCheckTick runs the runnable jobs, one at a time, in the background. It is a no-op when a
+// previous tick is still running jobs.
+func (e *Executor) onJobCheckTick(ctx context.Context) {
+	if !atomic.CompareAndSwapInt64(&e.tickReentranceFlag, 0, 1) {
+		return
+	}
+	e.jobsWaitGroup.Add(1)
+	go func() {
+		defer e.jobsWaitGroup.Done()
+		defer atomic.StoreInt64(&e.tickReentranceFlag, 0)
+
+		if err := e.runJobs(ctx); err != nil {
+			log.Errorf("onlineDML: error running jobs: %v", err)
+		}
+	}()
+}
+
+func (e *Executor) runJobs(ctx context.Context) error {
+	r, err := e.execQuery(ctx, sqlSelectRunnableJobs)
+	if err != nil {
+		return err
+	}
+	for _, row := range r.Named().Rows {
+		uuid := row.AsString("job_uuid", "")
+		err := e.runJob(ctx, uuid)
+		if ctx.Err() != nil {
+			// The executor is closing. The job resumes from its last checkpoint when the executor reopens.
+			return nil
+		}
+		if err != nil {
+			log.Errorf("onlineDML: job %s failed: %v", uuid, err)
+			if err := e.updateJobFailed(ctx, uuid, err.Error()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// dmlJob is the state of a running batch DML job
+type dmlJob struct {
+	uuid      string
+	table     string
+	stmt      sqlparser.Statement
+	batchSize int64
+	pkColumns []string
+	// lastPK is the comma separated list of the primary key values of the last row covered by the
+	// job, as SQL literals. It is empty before the first batch.
+	lastPK string
+}
+
+func (e *Executor) readJob(ctx context.Context, uuid string) (job *dmlJob, row sqltypes.RowNamedValues, err error) {
+	query, err := sqlparser.ParseAndBind(sqlSelectJob, sqltypes.StringBindVariable(uuid))
+	if err != nil {
+		return nil, nil, err
+	}
+	r, err := e.execQuery(ctx, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	row = r.Named().Row()
+	if row == nil {
+		return nil, nil, ErrJobNotFound
+	}
+	stmt, err := e.env.Environment().Parser().Parse(row.AsString("job_statement", ""))
+	if err != nil {
+		return nil, nil, err
+	}
+	job = &dmlJob{
+		uuid:      uuid,
+		table:     row.AsString("mysql_table", ""),
+		stmt:      stmt,
+		batchSize: row.AsInt64("batch_size", schema.DefaultOnlineDMLBatchSize),
+		lastPK:    row.AsString("last_pk", ""),
+	}
+	if pkColumns := row.AsString("pk_columns", ""); pkColumns != "" {
+		job.pkColumns = strings.Split(pkColumns, ",")
+	}
+	return job, row, nil
+}
+
+func (e *Executor) readJobStatus(ctx context.Context, uuid string) (schema.OnlineDMLStatus, error) {
+	query, err := sqlparser.ParseAndBind(sqlSelectJobStatus, sqltypes.StringBindVariable(uuid))
+	if err != nil {
+		return "", err
+	}
+	r, err := e.execQuery(ctx, query)
+	if err != nil {
+		return "", err
+	}
+	row := r.Named().Row()
+	if row == nil {
+		return "", ErrJobNotFound
+	}
+	return schema.OnlineDMLStatus(row.AsString("job_status", "")), nil
+}
+
+// startJob moves a queued job to running state. It reads the table's primary key columns the first
+// time the job starts, and rejects an update of any of them. It returns false if the job is no longer
+// queued.
+func (e *Executor) startJob(ctx context.Context, job *dmlJob) (bool, error) {
+	if len(job.pkColumns) == 0 {
+		query, err := sqlparser.ParseAndBind(sqlSelectPKColumns,
+			sqltypes.StringBindVariable(e.dbName),
+			sqltypes.StringBindVariable(job.table),
+		)
+		if err != nil {
+			return false, err
+		}
+		r, err := e.execQuery(ctx, query)
+		if err != nil {
+			return false, err
+		}
+		for _, row := range r.Rows {
+			job.pkColumns = append(job.pkColumns, row[0].ToString())
+		}
+		if len(job.pkColumns) == 0 {
+			return false, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "table %s has no PRIMARY KEY", job.table)
+		}
+	}
+	if err := job.checkUpdatedColumns(); err != nil {
+		return false, err
+	}
+	query, err := sqlparser.ParseAndBind(sqlSelectTableRows,
+		sqltypes.StringBindVariable(e.dbName),
+		sqltypes.StringBindVariable(job.table),
+	)
+	if err != nil {
+		return false, err
+	}
+	r, err := e.execQuery(ctx, query)
+	if err != nil {
+		return false, err
+	}
+	var tableRows int64
+	if row := r.Named().Row(); row != nil {
+		tableRows = row.AsInt64("TABLE_ROWS", 0)
+	}
+	query, err = sqlparser.ParseAndBind(sqlUpdateJobStarted,
+		sqltypes.StringBindVariable(e.tabletAliasString()),
+		sqltypes.StringBindVariable(strings.Join(job.pkColumns, ",")),
+		sqltypes.Int64BindVariable(tableRows),
+		sqltypes.StringBindVariable(job.uuid),
+	)
+	if err != nil {
+		return false, err
+	}
+	r, err = e.execQuery(ctx, query)
+	if err != nil {
+		return false, err
+	}
+	return r.RowsAffected > 0, nil
+}
+
+// runJob runs the given job batch by batch, until it completes, fails, or is paused or cancelled.
+func (e *Executor) runJob(ctx context.Context, uuid string) error {
+	job, row, err := e.readJob(ctx, uuid)
+	if err != nil {
+		return err
+	}
+	if schema.OnlineDMLStatus(row.AsString("job_status", "")) == schema.OnlineDMLStatusQueued {
+		started, err := e.startJob(ctx, job)
+		if err != nil {
+			return err
+		}
+		if !started {
+			return nil
+		}
+		log.Infof("onlineDML: started job %s on table %s", job.uuid, job.table)
+	}
+	throttlerAppName := throttlerapp.Name(throttlerapp.OnlineDMLName.ConcatenateString(job.uuid))
+	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		// The job may have been paused or cancelled since the last batch.
+		status, err := e.readJobStatus(ctx, job.uuid)
+		if err != nil {
+			return err
+		}
+		if status != schema.OnlineDMLStatusRunning {
+			log.Infof("onlineDML: job %s is %s", job.uuid, status)
+			return nil
+		}
+		if !e.throttlerClient.ThrottleCheckOKOrWaitAppName(ctx, throttlerAppName) {
+			if err := e.updateJobLiveness(ctx, job.uuid); err != nil {
+				return err
+			}
+			continue
+		}
+		done, err := e.runBatch(ctx, job)
+		if err != nil {
+			return err
+		}
+		if done {
+			log.Infof("onlineDML: job %s is complete", job.uuid)
+			return e.updateJobComplete(ctx, job.uuid)
+		}
+	}
+}
+
+// runBatch applies the job's statement to the next batch of rows, and checkpoints the job's progress
+// in the same transaction. It returns true when there are no more rows to process.
+func (e *Executor) runBatch(ctx context.Context, job *dmlJob) (done bool, err error) {
+	conn, err := e.pool.Get(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Recycle()
+
+	selectQuery, err := job.selectBatchQuery(e.env.Environment().Parser())
+	if err != nil {
+		return false, err
+	}
+	rows, err := conn.Conn.Exec(ctx, selectQuery, int(job.batchSize), false)
+	if err != nil {
+		return false, err
+	}
+	if len(rows.Rows) == 0 {
+		return true, nil
+	}
+	upperPK := encodePK(rows.Rows[len(rows.Rows)-1])
+	dmlQuery, err := job.batchDMLQuery(e.env.Environment().Parser(), upperPK)
+	if err != nil {
+		return false, err
+	}
+
+	if _, err := conn.Conn.Exec(ctx, "begin", 1, false); err != nil {
+		return false, err
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			_, _ = conn.Conn.Exec(ctx, "rollback", 1, false)
+		}
+	}()
+	result, err := conn.Conn.Exec(ctx, dmlQuery, 1, false)
+	if err != nil {
+		return false, err
+	}
+	progressQuery, err := sqlparser.ParseAndBind(sqlUpdateJobProgress,
+		sqltypes.StringBindVariable(upperPK),
+		sqltypes.Int64BindVariable(int64(len(rows.Rows))),
+		sqltypes.Uint64BindVariable(result.RowsAffected),
+		sqltypes.StringBindVariable(job.uuid),
+	)
+	if err != nil {
+		return false, err
+	}
+	if progressQuery, err = e.withSidecarDBReplacement(progressQuery); err != nil {
+		return false, err
+	}
+	if _, err := conn.Conn.Exec(ctx, progressQuery, 1, false); err != nil {
+		return false, err
+	}
+	if _, err := conn.Conn.Exec(ctx, "commit", 1, false); err != nil {
+		return false, err
+	}
+	committed = true
+	job.lastPK = upperPK
+
+	return int64(len(rows.Rows)) < job.batchSize, nil
+}
+
+func (e *Executor) updateJob(ctx context.Context, sql string, args ...*querypb.BindVariable) error {
+	query, err := sqlparser.ParseAndBind(sql, args...)
+	if err != nil {
+		return err
+	}
+	_, err = e.execQuery(ctx, query)
+	return err
+}
+
+func (e *Executor) updateJobLiveness(ctx context.Context, uuid string) error {
+	return e.updateJob(ctx, sqlUpdateJobLiveness, sqltypes.StringBindVariable(uuid))
+}
+
+func (e *Executor) updateJobComplete(ctx context.Context, uuid string) error {
+	return e.updateJob(ctx, sqlUpdateJobComplete, sqltypes.StringBindVariable(uuid))
+}
+
+func (e *Executor) updateJobFailed(ctx context.Context, uuid string, message string) error {
+	return e.updateJob(ctx, sqlUpdateJobFailed, sqltypes.StringBindVariable(message), sqltypes.StringBindVariable(uuid))
+}
+
+// encodePK returns the given primary key values as a comma separated list of SQL literals
+func encodePK(values []sqltypes.Value) string {
+	var b strings.Builder
+	for i, value := range values {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		value.EncodeSQLStringBuilder(&b)
+	}
+	return b.String()
+}
+
+// checkUpdatedColumns fails if the job updates a primary key column. Batches walk the table in
+// primary key order, so a row whose key changes could be updated again, or skipped.
+func (job *dmlJob) checkUpdatedColumns() error {
+	upd, ok := job.stmt.(*sqlparser.Update)
+	if !ok {
+		return nil
+	}
+	for _, updateExpr := range upd.Exprs {
+		for _, column := range job.pkColumns {
+			if updateExpr.Name.Name.EqualString(column) {
+				return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "batch DML cannot change primary key column: %s", column)
+			}
+		}
+	}
+	return nil
+}
+
+// pkColumnNames returns the job's primary key columns
+func (job *dmlJob) pkColumnNames() []*sqlparser.ColName {
+	columns := make([]*sqlparser.ColName, 0, len(job.pkColumns))
+	for _, column := range job.pkColumns {
+		columns = append(columns, sqlparser.NewColName(column))
+	}
+	return columns
+}
+
+// pkRangeExpr compares the job's primary key with the given encoded primary key values. For a composite
+// key (c1, c2) and values (1, 2) it returns `c1 = 1 and c2 <lastOp> 2 or c1 <op> 1`. Unlike a row
+// constructor comparison, this form lets MySQL use a range scan on the primary key.
+func (job *dmlJob) pkRangeExpr(parser *sqlparser.Parser, pk string, op, lastOp sqlparser.ComparisonExprOperator) (sqlparser.Expr, error) {
+	expr, err := parser.ParseExpr(fmt.Sprintf("(%s)", pk))
+	if err != nil {
+		return nil, err
+	}
+	values, ok := expr.(sqlparser.ValTuple)
+	if !ok {
+		values = sqlparser.ValTuple{expr}
+	}
+	columns := job.pkColumnNames()
+	if len(values) != len(columns) {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "primary key values don't match the primary key columns: (%s) vs %v", pk, job.pkColumns)
+	}
+	var ranges []sqlparser.Expr
+	for last := len(columns) - 1; last >= 0; last-- {
+		var conditions []sqlparser.Expr
+		for i := 0; i < last; i++ {
+			conditions = append(conditions, &sqlparser.ComparisonExpr{Operator: sqlparser.EqualOp, Left: columns[i], Right: values[i]})
+		}
+		lastColumnOp := op
+		if last == len(columns)-1 {
+			lastColumnOp = lastOp
+		}
+		conditions = append(conditions, &sqlparser.ComparisonExpr{Operator: lastColumnOp, Left: columns[last], Right: values[last]})
+		ranges = append(ranges, sqlparser.AndExpressions(conditions...))
+	}
+	rangeExpr := ranges[0]
+	for _, expr := range ranges[1:] {
+		rangeExpr = &sqlparser.OrExpr{Left: rangeExpr, Right: expr}
+	}
+	return rangeExpr, nil
+}
+
+// selectBatchQuery returns the query that reads the primary key values of the job's next batch of rows
+func (job *dmlJob) selectBatchQuery(parser *sqlparser.Parser) (string, error) {
+	sel := &sqlparser.Select{
+		From:  []sqlparser.TableExpr{sqlparser.NewAliasedTableExpr(sqlparser.NewTableName(job.table), "")},
+		Limit: sqlparser.NewLimitWithoutOffset(int(job.batchSize)),
+	}
+	for _, column := range job.pkColumnNames() {
+		sel.SelectExprs = append(sel.SelectExprs, &sqlparser.AliasedExpr{Expr: column})
+		sel.AddOrder(sqlparser.NewOrder(column, sqlparser.AscOrder))
+	}
+	if job.lastPK != "" {
+		lowerBound, err := job.pkRangeExpr(parser, job.lastPK, sqlparser.GreaterThanOp, sqlparser.GreaterThanOp)
+		if err != nil {
+			return "", err
+		}
+		sel.AddWhere(lowerBound)
+	}
+	return sqlparser.String(sel), nil
+}
+
+// batchDMLQuery returns the job's statement, limited to the rows that follow the last processed row,
+// up to and including the row with the given primary key values.
+func (job *dmlJob) batchDMLQuery(parser *sqlparser.Parser, upperPK string) (string, error) {
+	stmt := sqlparser.CloneStatement(job.stmt)
+	dml, ok := stmt.(interface{ AddWhere(sqlparser.Expr) })
+	if !ok {
+		return "", vterrors.Errorf(vtrpcpb.Code_INTERNAL, "unsupported statement for batch DML: %s", sqlparser.String(stmt))
+	}
+	if job.lastPK != "" {
+		lowerBound, err := job.pkRangeExpr(parser, job.lastPK, sqlparser.GreaterThanOp, sqlparser.GreaterThanOp)
+		if err != nil {
+			return "", err
+		}
+		dml.AddWhere(lowerBound)
+	}
+	upperBound, err := job.pkRangeExpr(parser, upperPK, sqlparser.LessThanOp, sqlparser.LessEqualOp)
+	if err != nil {
+		return "", err
+	}
+	dml.AddWhere(upperBound)
+	return sqlparser.String(stmt), nil
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package onlinedml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/sqlparser"
+)
+
+func TestEncodePK(t *testing.T) {
+	values := []sqltypes.Value{
+		sqltypes.NewInt64(7),
+		sqltypes.NewVarChar("it's"),
+	}
+	assert.Equal(t, `7, 'it\'s'`, encodePK(values))
+}
+
+func TestBatchQueries(t *testing.T) {
+	tcases := []struct {
+		name      string
+		query     string
+		pkColumns []string
+		lastPK    string
+		upperPK   string
+		selectSQL string
+		dmlSQL    string
+	}{
+		{
+			name:      "first batch",
+			query:     "delete from t where val is null",
+			pkColumns: []string{"id"},
+			upperPK:   "100",
+			selectSQL: "select id from t order by id asc limit 50",
+			dmlSQL:    "delete from t where val is null and id <= 100",
+		},
+		{
+			name:      "next batch",
+			query:     "update t set val = 'x' where val is null or val = 'y'",
+			pkColumns: []string{"id"},
+			lastPK:    "100",
+			upperPK:   "150",
+			selectSQL: "select id from t where id > 100 order by id asc limit 50",
+			dmlSQL:    "update t set val = 'x' where (val is null or val = 'y') and id > 100 and id <= 150",
+		},
+		{
+			name:      "composite key",
+			query:     "delete from t",
+			pkColumns: []string{"a", "b"},
+			lastPK:    "1, 'x'",
+			upperPK:   "3, 'z'",
+			selectSQL: "select a, b from t where a = 1 and b > 'x' or a > 1 order by a asc, b asc limit 50",
+			dmlSQL:    "delete from t where (a = 1 and b > 'x' or a > 1) and (a = 3 and b <= 'z' or a < 3)",
+		},
+	}
+	parser := sqlparser.NewTestParser()
+	for _, tcase := range tcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			stmt, err := parser.Parse(tcase.query)
+			require.NoError(t, err)
+			job := &dmlJob{
+				uuid:      "1876a9c2_2c46_11ef_b6e8_0a43f95f28a3",
+				table:     "t",
+				stmt:      stmt,
+				batchSize: 50,
+				pkColumns: tcase.pkColumns,
+				lastPK:    tcase.lastPK,
+			}
+			selectSQL, err := job.selectBatchQuery(parser)
+			require.NoError(t, err)
+			assert.Equal(t, tcase.selectSQL, selectSQL)
+
+			dmlSQL, err := job.batchDMLQuery(parser, tcase.upperPK)
+			require.NoError(t, err)
+			assert.Equal(t, tcase.dmlSQL, dmlSQL)
+			// The job's statement is left untouched
+			assert.Equal(t, tcase.query, sqlparser.String(job.stmt))
+		})
+	}
+
+	job := &dmlJob{table: "t", batchSize: 50, pkColumns: []string{"a", "b"}, lastPK: "1"}
+	_, err := job.selectBatchQuery(parser)
+	assert.ErrorContains(t, err, "primary key values don't match the primary key columns")
+}
+
+func TestCheckUpdatedColumns(t *testing.T) {
+	tcases := []struct {
+		query string
+		err   string
+	}{
+		{
+			query: "delete from t where val is null",
+		},
+		{
+			query: "update t set val = 'x' where val is null",
+		},
+		{
+			query: "update t set val = 'x', B = b + 1",
+			err:   "batch DML cannot change primary key column: b",
+		},
+	}
+	parser := sqlparser.NewTestParser()
+	for _, tcase := range tcases {
+		t.Run(tcase.query, func(t *testing.T) {
+			stmt, err := parser.Parse(tcase.query)
+			require.NoError(t, err)
+			job := &dmlJob{table: "t", stmt: stmt, pkColumns: []string{"a", "b"}}
+			err = job.checkUpdatedColumns()
+			if tcase.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tcase.err)
+			}
+		})
+	}
+}
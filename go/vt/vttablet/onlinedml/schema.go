@@ -0,0 +1,128 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package onlinedml
+
+const (
+	sqlInsertJob = `INSERT IGNORE INTO _vt.dml_jobs (
+		job_uuid,
+		keyspace,
+		shard,
+		mysql_schema,
+		mysql_table,
+		job_statement,
+		job_context,
+		job_status,
+		batch_size,
+		tablet,
+		pk_columns,
+		last_pk,
+		message
+	) VALUES (
+		%a, %a, %a, %a, %a, %a, %a, %a, %a, %a, '', '', ''
+	)`
+	sqlSelectJob = `SELECT
+			job_uuid,
+			keyspace,
+			mysql_table,
+			job_statement,
+			job_context,
+			job_status,
+			batch_size,
+			pk_columns,
+			last_pk
+		FROM _vt.dml_jobs
+		WHERE
+			job_uuid=%a
+	`
+	sqlSelectRunnableJobs = `SELECT
+			job_uuid
+		FROM _vt.dml_jobs
+		WHERE
+			job_status IN ('queued', 'running')
+		ORDER BY id
+	`
+	sqlSelectJobStatus = `SELECT
+			job_status
+		FROM _vt.dml_jobs
+		WHERE
+			job_uuid=%a
+	`
+	sqlUpdateJobStarted = `UPDATE _vt.dml_jobs
+			SET job_status='running',
+			tablet=%a,
+			pk_columns=IF(pk_columns='', %a, pk_columns),
+			table_rows=%a,
+			started_timestamp=IFNULL(started_timestamp, NOW()),
+			liveness_timestamp=NOW(),
+			message=''
+		WHERE
+			job_uuid=%a
+			AND job_status='queued'
+	`
+	sqlUpdateJobProgress = `UPDATE _vt.dml_jobs
+			SET last_pk=%a,
+			rows_scanned=rows_scanned+%a,
+			rows_affected=rows_affected+%a,
+			progress=LEAST(100, IF(table_rows > 0, 100 * rows_scanned / table_rows, 0)),
+			liveness_timestamp=NOW()
+		WHERE
+			job_uuid=%a
+	`
+	sqlUpdateJobLiveness = `UPDATE _vt.dml_jobs
+			SET liveness_timestamp=NOW()
+		WHERE
+			job_uuid=%a
+	`
+	sqlUpdateJobComplete = `UPDATE _vt.dml_jobs
+			SET job_status='complete',
+			progress=100,
+			completed_timestamp=NOW(6)
+		WHERE
+			job_uuid=%a
+			AND job_status='running'
+	`
+	sqlUpdateJobFailed = `UPDATE _vt.dml_jobs
+			SET job_status='failed',
+			message=%a,
+			completed_timestamp=NOW(6)
+		WHERE
+			job_uuid=%a
+			AND job_status='running'
+	`
+	sqlShowJobsWhere = `SELECT
+			*
+		FROM _vt.dml_jobs
+		%s
+		ORDER BY id
+	`
+	sqlSelectPKColumns = `SELECT
+			COLUMN_NAME
+		FROM information_schema.KEY_COLUMN_USAGE
+		WHERE
+			TABLE_SCHEMA=%a
+			AND TABLE_NAME=%a
+			AND CONSTRAINT_NAME='PRIMARY'
+		ORDER BY ORDINAL_POSITION
+	`
+	sqlSelectTableRows = `SELECT
+			TABLE_ROWS
+		FROM information_schema.TABLES
+		WHERE
+			TABLE_SCHEMA=%a
+			AND TABLE_NAME=%a
+	`
+)
@@ -0,0 +1,379 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package partitionmgr manages the partitions of time-series tables. Tables are registered in
+_vt.partition_lifecycle with a partition interval, a number of future partitions to pre-create and
+a retention period. On a primary tablet, the Manager periodically checks each registered table,
+and submits Online DDL migrations with the --fast-range-rotation flag: one migration for each
+missing future partition, and one for each partition whose rows are all past retention. Dropped
+partitions are exchanged into a table GC table, and purged via the table lifecycle. The outcome
+of each check is recorded in _vt.partition_lifecycle.
+
+Partition intervals are aligned in UTC. A managed table must be partitioned by RANGE COLUMNS over a
+single date or datetime column, or by RANGE over an expression of a single column, e.g.
+TO_DAYS(created_at) or UNIX_TIMESTAMP(created_at).
+*/
+
+package partitionmgr
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"vitess.io/vitess/go/constants/sidecar"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/timer"
+	"vitess.io/vitess/go/vt/dbconnpool"
+	"vitess.io/vitess/go/vt/log"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/schema"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/tabletenv"
+)
+
+var (
+	checkInterval      = time.Hour
+	nextCheckIntervals = []time.Duration{time.Minute}
+)
+
+const (
+	// MigrationContext is the migration context of the migrations submitted by the Manager
+	MigrationContext = "partition-lifecycle"
+
+	lifecycleStatusOK    = "ok"
+	lifecycleStatusError = "error"
+)
+
+// SubmitMigrationFunc submits an Online DDL migration, given its commented statement.
+type SubmitMigrationFunc func(ctx context.Context, stmt sqlparser.Statement) (*sqltypes.Result, error)
+
+// lifecycle is the partition lifecycle configuration of a table
+type lifecycle struct {
+	table            string
+	interval         schema.PartitionInterval
+	futurePartitions int
+	retention        time.Duration
+}
+
+// lifecycleStatus is the outcome of checking a table
+type lifecycleStatus struct {
+	partitions     int
+	firstPartition string
+	lastPartition  string
+	submitted      []string
+}
+
+// Manager checks the tables registered in _vt.partition_lifecycle and submits partition rotation
+// migrations. It only runs on a primary tablet.
+type Manager struct {
+	env             tabletenv.Env
+	submitMigration SubmitMigrationFunc
+
+	keyspace string
+	shard    string
+
+	initMutex          sync.Mutex
+	tickReentranceFlag int64
+	cancelChecks       context.CancelFunc
+	checksWaitGroup    sync.WaitGroup
+
+	ticks  *timer.Timer
+	isOpen int64
+}
+
+// NewManager creates a new partition lifecycle manager. Migrations are submitted via the given
+// function, which is normally the Online DDL executor's SubmitMigration.
+func NewManager(env tabletenv.Env, submitMigration SubmitMigrationFunc) *Manager {
+	return &Manager{
+		env:             env,
+		submitMigration: submitMigration,
+		ticks:           timer.NewTimer(checkInterval),
+	}
+}
+
+// InitDBConfig initializes keyspace and shard
+func (m *Manager) InitDBConfig(keyspace, shard string) {
+	m.keyspace = keyspace
+	m.shard = shard
+}
+
+// Open starts checking tables periodically
+func (m *Manager) Open() error {
+	m.initMutex.Lock()
+	defer m.initMutex.Unlock()
+	if atomic.LoadInt64(&m.isOpen) > 0 {
+		return nil
+	}
+	log.Infof("partition lifecycle Manager Open()")
+
+	var ctx context.Context
+	ctx, m.cancelChecks = context.WithCancel(context.Background())
+	m.ticks.Start(func() { m.onCheckTick(ctx) })
+	for _, interval := range nextCheckIntervals {
+		m.ticks.TriggerAfter(interval)
+	}
+
+	atomic.StoreInt64(&m.isOpen, 1)
+	return nil
+}
+
+// Close stops checking tables. Migrations already submitted are unaffected.
+func (m *Manager) Close() {
+	m.initMutex.Lock()
+	defer m.initMutex.Unlock()
+	if atomic.LoadInt64(&m.isOpen) == 0 {
+		return
+	}
+	log.Infof("partition lifecycle Manager Close()")
+
+	m.ticks.Stop()
+	m.cancelChecks()
+	m.checksWaitGroup.Wait()
+	atomic.StoreInt64(&m.isOpen, 0)
+}
+
+// onCheckTick checks all registered tables in the background. It is a no-op when a previous tick
+// is still running.
+func (m *Manager) onCheckTick(ctx context.Context) {
+	if !atomic.CompareAndSwapInt64(&m.tickReentranceFlag, 0, 1) {
+		return
+	}
+	m.checksWaitGroup.Add(1)
+	go func() {
+		defer m.checksWaitGroup.Done()
+		defer atomic.StoreInt64(&m.tickReentranceFlag, 0)
+
+		if err := m.checkTables(ctx, time.Now()); err != nil {
+			log.Errorf("partition lifecycle: error checking tables in %s/%s: %v", m.keyspace, m.shard, err)
+		}
+	}()
+}
+
+// withSidecarDBReplacement rewrites queries on the sidecar database when a non default sidecar
+// database name is in use.
+func (m *Manager) withSidecarDBReplacement(query string) (string, error) {
+	if sidecar.GetName() == sidecar.DefaultName {
+		return query, nil
+	}
+	return m.env.Environment().Parser().ReplaceTableQualifiers(query, sidecar.DefaultName, sidecar.GetName())
+}
+
+func (m *Manager) checkTables(ctx context.Context, now time.Time) error {
+	conn, err := dbconnpool.NewDBConnection(ctx, m.env.Config().DB.DbaWithDB())
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// Partitioning expressions such as UNIX_TIMESTAMP() depend on the session time zone, while
+	// partition intervals are aligned in UTC.
+	if _, err := conn.ExecuteFetch(sqlSetUTCTimeZone, 0, false); err != nil {
+		return err
+	}
+	query, err := m.withSidecarDBReplacement(sqlSelectLifecycles)
+	if err != nil {
+		return err
+	}
+	r, err := conn.ExecuteFetch(query, -1, true)
+	if err != nil {
+		return err
+	}
+	for _, row := range r.Named().Rows {
+		if ctx.Err() != nil {
+			return nil
+		}
+		lc := &lifecycle{
+			table:            row.AsString("mysql_table", ""),
+			futurePartitions: int(row.AsInt64("future_partitions", 0)),
+			retention:        time.Duration(row.AsInt64("retention_seconds", 0)) * time.Second,
+		}
+		status := &lifecycleStatus{}
+		lc.interval, err = schema.ParsePartitionInterval(row.AsString("partition_interval", ""))
+		if err == nil {
+			status, err = m.checkTable(ctx, conn, lc, now)
+		}
+		statusValue, message := lifecycleStatusOK, ""
+		if err != nil {
+			log.Errorf("partition lifecycle: error checking table %s: %v", lc.table, err)
+			statusValue, message = lifecycleStatusError, err.Error()
+		}
+		if err := m.updateStatus(conn, lc.table, statusValue, message, status); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Manager) updateStatus(conn *dbconnpool.DBConnection, table string, statusValue string, message string, status *lifecycleStatus) error {
+	query, err := sqlparser.ParseAndBind(sqlUpdateLifecycleStatus,
+		sqltypes.StringBindVariable(statusValue),
+		sqltypes.StringBindVariable(message),
+		sqltypes.Int64BindVariable(int64(status.partitions)),
+		sqltypes.StringBindVariable(status.firstPartition),
+		sqltypes.StringBindVariable(status.lastPartition),
+		sqltypes.StringBindVariable(strings.Join(status.submitted, ",")),
+		sqltypes.StringBindVariable(table),
+	)
+	if err != nil {
+		return err
+	}
+	if query, err = m.withSidecarDBReplacement(query); err != nil {
+		return err
+	}
+	_, err = conn.ExecuteFetch(query, 0, false)
+	return err
+}
+
+// checkTable submits the migrations that bring the table's partitions up to date with its
+// lifecycle configuration.
+func (m *Manager) checkTable(ctx context.Context, conn *dbconnpool.DBConnection, lc *lifecycle, now time.Time) (*lifecycleStatus, error) {
+	status := &lifecycleStatus{}
+	parsed := sqlparser.BuildParsedQuery(sqlShowCreateTable, lc.table)
+	r, err := conn.ExecuteFetch(parsed.Query, 1, false)
+	if err != nil {
+		return status, err
+	}
+	if len(r.Rows) == 0 {
+		return status, vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "table %s not found", lc.table)
+	}
+	stmt, err := m.env.Environment().Parser().ParseStrictDDL(r.Rows[0][1].ToString())
+	if err != nil {
+		return status, err
+	}
+	createTable, ok := stmt.(*sqlparser.CreateTable)
+	if !ok {
+		return status, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "%s is not a table", lc.table)
+	}
+	rp, err := newRangePartitioning(createTable)
+	if err != nil {
+		return status, err
+	}
+	status.partitions = len(rp.partitions)
+	status.firstPartition = rp.partitions[0].name
+	status.lastPartition = rp.partitions[len(rp.partitions)-1].name
+
+	wanted := boundaries(lc.interval, now, lc.futurePartitions)
+	var retentionValue *int64
+	if lc.retention > 0 {
+		retentionValue = new(int64)
+	}
+	if err := m.evaluateValues(conn, rp, wanted, now.Add(-lc.retention), retentionValue); err != nil {
+		return status, err
+	}
+	plan, err := planRotation(rp.partitions, wanted, retentionValue)
+	if err != nil {
+		return status, err
+	}
+
+	tableName := sqlparser.String(sqlparser.NewIdentifierCS(lc.table))
+	var statements []string
+	for _, b := range plan.add {
+		statements = append(statements, fmt.Sprintf("alter table %s add partition (partition %s values less than (%s))",
+			tableName, sqlparser.String(sqlparser.NewIdentifierCI(b.name)), b.valueSQL))
+	}
+	for _, name := range plan.drop {
+		statements = append(statements, fmt.Sprintf("alter table %s drop partition %s",
+			tableName, sqlparser.String(sqlparser.NewIdentifierCI(name))))
+	}
+	for _, sql := range statements {
+		uuid, err := m.submit(ctx, lc.table, sql)
+		if err != nil {
+			return status, err
+		}
+		status.submitted = append(status.submitted, uuid)
+	}
+	return status, nil
+}
+
+// evaluateValues computes the partitioning values of the wanted boundaries, and of the retention
+// time when a retention value is given. Values of RANGE COLUMNS tables are computed here, other
+// values are evaluated by MySQL.
+func (m *Manager) evaluateValues(conn *dbconnpool.DBConnection, rp *rangePartitioning, wanted []*boundary, retentionTime time.Time, retentionValue *int64) error {
+	if rp.expr == nil {
+		for _, b := range wanted {
+			b.value = b.end.Unix()
+			b.valueSQL = sqlparser.String(rp.valueExpr(b.end))
+		}
+		if retentionValue != nil {
+			*retentionValue = retentionTime.Unix()
+		}
+		return nil
+	}
+	var exprs []string
+	for _, b := range wanted {
+		exprs = append(exprs, sqlparser.String(rp.valueExpr(b.end)))
+	}
+	if retentionValue != nil {
+		exprs = append(exprs, sqlparser.String(rp.valueExpr(retentionTime)))
+	}
+	r, err := conn.ExecuteFetch("select "+strings.Join(exprs, ", "), 1, false)
+	if err != nil {
+		return err
+	}
+	if len(r.Rows) != 1 || len(r.Rows[0]) != len(exprs) {
+		return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "unexpected result evaluating partitioning expression %s", sqlparser.String(rp.expr))
+	}
+	values := make([]int64, len(exprs))
+	for i, v := range r.Rows[0] {
+		if values[i], err = v.ToInt64(); err != nil {
+			return vterrors.Wrapf(err, "evaluating %s", exprs[i])
+		}
+	}
+	for i, b := range wanted {
+		b.value = values[i]
+		b.valueSQL = strconv.FormatInt(values[i], 10)
+	}
+	if retentionValue != nil {
+		*retentionValue = values[len(values)-1]
+	}
+	return nil
+}
+
+// migrationUUID returns a UUID derived from the table and the rotation statement, so that a
+// statement that is submitted again in a later check maps onto the same migration. Online DDL
+// treats such a resubmission as a no-op, or as a retry if the migration had failed.
+func migrationUUID(table string, sql string) string {
+	sum := sha256.Sum256([]byte(MigrationContext + ":" + table + ":" + sql))
+	h := hex.EncodeToString(sum[:16])
+	return strings.Join([]string{h[0:8], h[8:12], h[12:16], h[16:20], h[20:32]}, "_")
+}
+
+// submit submits a rotation statement as an Online DDL migration and returns its UUID.
+func (m *Manager) submit(ctx context.Context, table string, sql string) (string, error) {
+	strategySetting := schema.NewDDLStrategySetting(schema.DDLStrategyVitess, "--fast-range-rotation")
+	onlineDDL, err := schema.NewOnlineDDL(m.keyspace, table, sql, strategySetting, MigrationContext, migrationUUID(table, sql), m.env.Environment().Parser())
+	if err != nil {
+		return "", err
+	}
+	stmt, err := m.env.Environment().Parser().Parse(onlineDDL.SQL)
+	if err != nil {
+		return "", err
+	}
+	log.Infof("partition lifecycle: submitting migration %s: %s", onlineDDL.UUID, sql)
+	if _, err := m.submitMigration(ctx, stmt); err != nil {
+		return "", vterrors.Wrapf(err, "submitting migration %s", onlineDDL.UUID)
+	}
+	return onlineDDL.UUID, nil
+}
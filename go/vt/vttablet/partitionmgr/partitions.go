@@ -0,0 +1,243 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package partitionmgr
+
+import (
+	"strconv"
+	"time"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/schema"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+// partitionBound is an existing partition of a table, along with its VALUES LESS THAN bound.
+type partitionBound struct {
+	name     string
+	value    int64
+	maxValue bool
+}
+
+// rangePartitioning describes a table partitioned by RANGE over a single temporal column. Bounds
+// of RANGE COLUMNS partitions are dates or datetimes, which are converted to unix timestamps so
+// that all bounds compare as integers.
+type rangePartitioning struct {
+	column sqlparser.IdentifierCI
+	// expr is the partitioning expression, e.g. TO_DAYS(created_at), or nil when the table is
+	// partitioned by RANGE COLUMNS.
+	expr       sqlparser.Expr
+	partitions []*partitionBound
+}
+
+// boundary is a partition the table should have: the partition covering the interval [start, end).
+type boundary struct {
+	name  string
+	start time.Time
+	end   time.Time
+	// value is the bound of the partition, comparable with partitionBound.value
+	value int64
+	// valueSQL is the partition's VALUES LESS THAN bound, as SQL
+	valueSQL string
+}
+
+// rotationPlan lists the partitions to add and drop, in order.
+type rotationPlan struct {
+	add  []*boundary
+	drop []string
+}
+
+var temporalLayouts = []string{time.DateTime, time.DateOnly}
+
+// parseTemporal parses a date or datetime literal as found in a RANGE COLUMNS partition bound.
+func parseTemporal(s string) (time.Time, error) {
+	for _, layout := range temporalLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.UTC); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "unsupported partition bound: '%s'. Expected a date or a datetime", s)
+}
+
+// newRangePartitioning analyzes the partitioning of a table, and returns an error if the partition
+// lifecycle manager cannot manage it.
+func newRangePartitioning(createTable *sqlparser.CreateTable) (*rangePartitioning, error) {
+	tableName := createTable.Table.Name.String()
+	part := createTable.TableSpec.PartitionOption
+	if part == nil {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "table %s is not partitioned", tableName)
+	}
+	if part.Type != sqlparser.RangeType {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "table %s is not partitioned by RANGE", tableName)
+	}
+	if part.SubPartition != nil {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "table %s has subpartitions, which are not supported", tableName)
+	}
+	if len(part.Definitions) == 0 {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "table %s has no partitions", tableName)
+	}
+	rp := &rangePartitioning{expr: part.Expr}
+	if part.Expr == nil {
+		if len(part.ColList) != 1 {
+			return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "table %s must be partitioned by RANGE COLUMNS over a single column", tableName)
+		}
+		rp.column = part.ColList[0]
+	} else {
+		var columns []sqlparser.IdentifierCI
+		_ = sqlparser.Walk(func(node sqlparser.SQLNode) (kontinue bool, err error) {
+			if col, ok := node.(*sqlparser.ColName); ok {
+				columns = append(columns, col.Name)
+			}
+			return true, nil
+		}, part.Expr)
+		for _, col := range columns {
+			if !col.Equal(columns[0]) {
+				columns = nil
+				break
+			}
+		}
+		if len(columns) == 0 {
+			return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "partitioning expression of table %s must reference a single column: %s", tableName, sqlparser.String(part.Expr))
+		}
+		rp.column = columns[0]
+	}
+	for _, def := range part.Definitions {
+		bound := &partitionBound{name: def.Name.String()}
+		if def.Options == nil || def.Options.ValueRange == nil || def.Options.ValueRange.Type != sqlparser.LessThanType {
+			return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "partition %s of table %s has no VALUES LESS THAN clause", bound.name, tableName)
+		}
+		valueRange := def.Options.ValueRange
+		switch {
+		case valueRange.Maxvalue:
+			bound.maxValue = true
+		case len(valueRange.Range) != 1:
+			return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "partition %s of table %s must have a single bound value", bound.name, tableName)
+		default:
+			value, err := rp.boundValue(valueRange.Range[0])
+			if err != nil {
+				return nil, vterrors.Wrapf(err, "partition %s of table %s", bound.name, tableName)
+			}
+			bound.value = value
+		}
+		rp.partitions = append(rp.partitions, bound)
+	}
+	return rp, nil
+}
+
+// boundValue converts the bound of an existing partition to an integer.
+func (rp *rangePartitioning) boundValue(expr sqlparser.Expr) (int64, error) {
+	if introducer, ok := expr.(*sqlparser.IntroducerExpr); ok {
+		expr = introducer.Expr
+	}
+	if minus, ok := expr.(*sqlparser.UnaryExpr); ok && minus.Operator == sqlparser.UMinusOp {
+		value, err := rp.boundValue(minus.Expr)
+		return -value, err
+	}
+	lit, ok := expr.(*sqlparser.Literal)
+	if !ok {
+		return 0, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "unsupported partition bound: %s", sqlparser.String(expr))
+	}
+	if rp.expr == nil {
+		if lit.Type != sqlparser.StrVal {
+			return 0, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "unsupported partition bound: %s. Expected a date or a datetime", sqlparser.String(expr))
+		}
+		t, err := parseTemporal(lit.Val)
+		if err != nil {
+			return 0, err
+		}
+		return t.Unix(), nil
+	}
+	if lit.Type != sqlparser.IntVal {
+		return 0, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "unsupported partition bound: %s. Expected an integer", sqlparser.String(expr))
+	}
+	return strconv.ParseInt(lit.Val, 10, 64)
+}
+
+// temporalLiteral returns the given time as a date literal, or as a datetime literal if it has a
+// time of day.
+func temporalLiteral(t time.Time) *sqlparser.Literal {
+	t = t.UTC()
+	if t.Equal(schema.PartitionIntervalDay.Truncate(t)) {
+		return sqlparser.NewStrLiteral(t.Format(time.DateOnly))
+	}
+	return sqlparser.NewStrLiteral(t.Format(time.DateTime))
+}
+
+// valueExpr returns an expression that evaluates to the partitioning value of the given time. For
+// RANGE COLUMNS tables this is the time itself. Otherwise, it is the partitioning expression with
+// the time in place of the partitioning column, to be evaluated by MySQL.
+func (rp *rangePartitioning) valueExpr(t time.Time) sqlparser.Expr {
+	lit := temporalLiteral(t)
+	if rp.expr == nil {
+		return lit
+	}
+	return sqlparser.CopyOnRewrite(rp.expr, nil, func(cursor *sqlparser.CopyOnWriteCursor) {
+		if _, ok := cursor.Node().(*sqlparser.ColName); ok {
+			cursor.Replace(lit)
+		}
+	}, nil).(sqlparser.Expr)
+}
+
+// boundaries returns the partitions the table should have as of the given time: the partition
+// covering the current interval, followed by the given number of future partitions.
+func boundaries(interval schema.PartitionInterval, now time.Time, futurePartitions int) []*boundary {
+	var result []*boundary
+	start := interval.Truncate(now)
+	for i := 0; i <= futurePartitions; i++ {
+		end := interval.Add(start, 1)
+		result = append(result, &boundary{
+			name:  interval.PartitionName(start),
+			start: start,
+			end:   end,
+		})
+		start = end
+	}
+	return result
+}
+
+// planRotation decides which partitions to add and which to drop. Boundaries beyond the last
+// partition are added. Partitions whose bound is at or below the retention value only hold rows
+// past retention, and are dropped, except for the last partition, which is never dropped. A nil
+// retention value means partitions are kept forever.
+func planRotation(partitions []*partitionBound, wanted []*boundary, retentionValue *int64) (*rotationPlan, error) {
+	plan := &rotationPlan{}
+	last := partitions[len(partitions)-1]
+	names := make(map[string]bool, len(partitions))
+	for _, p := range partitions {
+		names[p.name] = true
+	}
+	for _, b := range wanted {
+		if last.maxValue {
+			return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "partition %s is a MAXVALUE partition, cannot add partitions after it", last.name)
+		}
+		if b.value <= last.value {
+			continue
+		}
+		if names[b.name] {
+			return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "cannot add partition %s: a partition by that name already exists", b.name)
+		}
+		plan.add = append(plan.add, b)
+	}
+	if retentionValue != nil {
+		for _, p := range partitions[:len(partitions)-1] {
+			if p.value <= *retentionValue {
+				plan.drop = append(plan.drop, p.name)
+			}
+		}
+	}
+	return plan, nil
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package partitionmgr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/schema"
+	"vitess.io/vitess/go/vt/sqlparser"
+)
+
+func TestNewRangePartitioning(t *testing.T) {
+	tcases := []struct {
+		name       string
+		create     string
+		column     string
+		partitions []*partitionBound
+		expectErr  string
+	}{
+		{
+			name:   "range columns",
+			create: "create table t (id int, created_at datetime, primary key (id, created_at)) partition by range columns (created_at) (partition p20240101 values less than ('2024-01-02'), partition p20240102 values less than ('2024-01-03 00:00:00'))",
+			column: "created_at",
+			partitions: []*partitionBound{
+				{name: "p20240101", value: 1704153600},
+				{name: "p20240102", value: 1704240000},
+			},
+		},
+		{
+			name:   "range expression",
+			create: "create table t (id int, created_at datetime, primary key (id, created_at)) partition by range (to_days(created_at)) (partition p0 values less than (739252), partition pmax values less than maxvalue)",
+			column: "created_at",
+			partitions: []*partitionBound{
+				{name: "p0", value: 739252},
+				{name: "pmax", maxValue: true},
+			},
+		},
+		{
+			name:      "not partitioned",
+			create:    "create table t (id int primary key)",
+			expectErr: "table t is not partitioned",
+		},
+		{
+			name:      "hash partitioned",
+			create:    "create table t (id int primary key) partition by hash (id) partitions 4",
+			expectErr: "table t is not partitioned by RANGE",
+		},
+		{
+			name:      "multiple columns",
+			create:    "create table t (a date, b date) partition by range columns (a, b) (partition p0 values less than ('2024-01-01', '2024-01-01'))",
+			expectErr: "must be partitioned by RANGE COLUMNS over a single column",
+		},
+		{
+			name:      "non temporal bound",
+			create:    "create table t (id int, name varchar(10)) partition by range columns (name) (partition p0 values less than ('m'))",
+			expectErr: "Expected a date or a datetime",
+		},
+	}
+	parser := sqlparser.NewTestParser()
+	for _, tcase := range tcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			stmt, err := parser.ParseStrictDDL(tcase.create)
+			require.NoError(t, err)
+			rp, err := newRangePartitioning(stmt.(*sqlparser.CreateTable))
+			if tcase.expectErr != "" {
+				assert.ErrorContains(t, err, tcase.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tcase.column, rp.column.String())
+			assert.Equal(t, tcase.partitions, rp.partitions)
+		})
+	}
+}
+
+func TestValueExpr(t *testing.T) {
+	parser := sqlparser.NewTestParser()
+	stmt, err := parser.ParseStrictDDL("create table t (id int, created_at datetime) partition by range (unix_timestamp(created_at)) (partition p0 values less than (1704067200))")
+	require.NoError(t, err)
+	rp, err := newRangePartitioning(stmt.(*sqlparser.CreateTable))
+	require.NoError(t, err)
+
+	assert.Equal(t, "unix_timestamp('2024-01-02')", sqlparser.String(rp.valueExpr(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC))))
+	assert.Equal(t, "unix_timestamp('2024-01-02 05:00:00')", sqlparser.String(rp.valueExpr(time.Date(2024, 1, 2, 5, 0, 0, 0, time.UTC))))
+	// The partitioning expression is left untouched
+	assert.Equal(t, "unix_timestamp(created_at)", sqlparser.String(rp.expr))
+
+	rp = &rangePartitioning{column: sqlparser.NewIdentifierCI("created_at")}
+	assert.Equal(t, "'2024-01-02'", sqlparser.String(rp.valueExpr(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC))))
+}
+
+func TestBoundaries(t *testing.T) {
+	now := time.Date(2024, 1, 30, 13, 0, 0, 0, time.UTC)
+	result := boundaries(schema.PartitionIntervalDay, now, 2)
+	require.Len(t, result, 3)
+	for i, expect := range []struct {
+		name string
+		end  string
+	}{
+		{name: "p20240130", end: "2024-01-31"},
+		{name: "p20240131", end: "2024-02-01"},
+		{name: "p20240201", end: "2024-02-02"},
+	} {
+		assert.Equal(t, expect.name, result[i].name)
+		assert.Equal(t, expect.end, result[i].end.Format(time.DateOnly))
+	}
+}
+
+func TestPlanRotation(t *testing.T) {
+	partitions := []*partitionBound{
+		{name: "p1", value: 10},
+		{name: "p2", value: 20},
+		{name: "p3", value: 30},
+	}
+	wanted := []*boundary{
+		{name: "p3", value: 30},
+		{name: "p4", value: 40},
+		{name: "p5", value: 50},
+	}
+	value := func(v int64) *int64 { return &v }
+
+	t.Run("add only", func(t *testing.T) {
+		plan, err := planRotation(partitions, wanted, nil)
+		require.NoError(t, err)
+		assert.Equal(t, wanted[1:], plan.add)
+		assert.Empty(t, plan.drop)
+	})
+	t.Run("add and drop", func(t *testing.T) {
+		plan, err := planRotation(partitions, wanted, value(25))
+		require.NoError(t, err)
+		assert.Equal(t, wanted[1:], plan.add)
+		assert.Equal(t, []string{"p1", "p2"}, plan.drop)
+	})
+	t.Run("never drop last partition", func(t *testing.T) {
+		plan, err := planRotation(partitions, nil, value(100))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"p1", "p2"}, plan.drop)
+	})
+	t.Run("up to date", func(t *testing.T) {
+		plan, err := planRotation(partitions, wanted[:1], value(5))
+		require.NoError(t, err)
+		assert.Empty(t, plan.add)
+		assert.Empty(t, plan.drop)
+	})
+	t.Run("maxvalue", func(t *testing.T) {
+		_, err := planRotation([]*partitionBound{{name: "p1", value: 10}, {name: "pmax", maxValue: true}}, wanted, nil)
+		assert.ErrorContains(t, err, "MAXVALUE partition")
+	})
+	t.Run("name conflict", func(t *testing.T) {
+		_, err := planRotation(partitions, []*boundary{{name: "p2", value: 40}}, nil)
+		assert.ErrorContains(t, err, "a partition by that name already exists")
+	})
+}
+
+func TestMigrationUUID(t *testing.T) {
+	uuid := migrationUUID("t", "alter table t drop partition p1")
+	assert.True(t, schema.IsOnlineDDLUUID(uuid))
+	assert.Equal(t, uuid, migrationUUID("t", "alter table t drop partition p1"))
+	assert.NotEqual(t, uuid, migrationUUID("t", "alter table t drop partition p2"))
+}
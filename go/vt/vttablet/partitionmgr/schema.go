@@ -0,0 +1,41 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package partitionmgr
+
+const (
+	sqlSelectLifecycles = `SELECT
+			mysql_table,
+			partition_interval,
+			future_partitions,
+			retention_seconds
+		FROM _vt.partition_lifecycle
+		ORDER BY id
+	`
+	sqlUpdateLifecycleStatus = `UPDATE _vt.partition_lifecycle
+			SET lifecycle_status=%a,
+			message=%a,
+			partitions=%a,
+			first_partition=%a,
+			last_partition=%a,
+			submitted_migrations=%a,
+			last_check_timestamp=NOW()
+		WHERE
+			mysql_table=%a
+	`
+	sqlSetUTCTimeZone  = `SET @@session.time_zone='+00:00'`
+	sqlShowCreateTable = "SHOW CREATE TABLE `%a`"
+)
@@ -206,7 +206,8 @@ func (vr *vreplicator) validateBinlogRowImage() error {
 		switch binlogdatapb.VReplicationWorkflowType(vr.WorkflowType) {
 		case binlogdatapb.VReplicationWorkflowType_MoveTables,
 			binlogdatapb.VReplicationWorkflowType_Reshard,
-			binlogdatapb.VReplicationWorkflowType_OnlineDDL:
+			binlogdatapb.VReplicationWorkflowType_OnlineDDL,
+			binlogdatapb.VReplicationWorkflowType_ChangeVindex:
 		case 0:
 		// used in unit tests only
 		default:
@@ -1037,11 +1038,12 @@ func (vr *vreplicator) execPostCopyActions(ctx context.Context, tableName string
 
 // supportsDeferredSecondaryKeys tells you if related work should be done
 // for the workflow. Deferring secondary index generation is only supported
-// with MoveTables, Migrate, and Reshard.
+// with MoveTables, Migrate, Reshard, and ChangeVindex.
 func (vr *vreplicator) supportsDeferredSecondaryKeys() bool {
 	return vr.WorkflowType == int32(binlogdatapb.VReplicationWorkflowType_MoveTables) ||
 		vr.WorkflowType == int32(binlogdatapb.VReplicationWorkflowType_Migrate) ||
-		vr.WorkflowType == int32(binlogdatapb.VReplicationWorkflowType_Reshard)
+		vr.WorkflowType == int32(binlogdatapb.VReplicationWorkflowType_Reshard) ||
+		vr.WorkflowType == int32(binlogdatapb.VReplicationWorkflowType_ChangeVindex)
 }
 
 func (vr *vreplicator) newClientConnection(ctx context.Context) (*vdbClient, error) {
@@ -60,6 +60,9 @@ type Controller interface {
 	// IsServing returns true if the query service is running
 	IsServing() bool
 
+	// CurrentTarget returns the current target of the query service
+	CurrentTarget() *querypb.Target
+
 	// IsHealthy returns the health status of the QueryService
 	IsHealthy() error
 
@@ -121,6 +121,21 @@ func analyzeDelete(del *sqlparser.Delete, tables map[string]*schema.Table) (plan
 	return plan, nil
 }
 
+// analyzeBatchDML returns a PlanBatchDML plan for an UPDATE or DELETE statement that carries the
+// BATCH_DML directive, and a nil plan for any other statement.
+func analyzeBatchDML(stmt sqlparser.Statement, tableExprs []sqlparser.TableExpr, tables map[string]*schema.Table) (plan *Plan, err error) {
+	_, isBatchDML, err := sqlparser.GetBatchDMLFromStatement(stmt)
+	if err != nil || !isBatchDML {
+		return nil, err
+	}
+	plan = &Plan{
+		PlanID:   PlanBatchDML,
+		FullStmt: stmt,
+	}
+	plan.Table, plan.AllTables = lookupTables(tableExprs, tables)
+	return plan, nil
+}
+
 func analyzeInsert(ins *sqlparser.Insert, tables map[string]*schema.Table) (plan *Plan, err error) {
 	plan = &Plan{
 		PlanID:    PlanInsert,
@@ -141,6 +156,10 @@ func analyzeShow(show *sqlparser.Show, dbName string) (plan *Plan, err error) {
 		switch showInternal.Command {
 		case sqlparser.VitessMigrations:
 			return &Plan{PlanID: PlanShowMigrations, FullStmt: show}, nil
+		case sqlparser.VitessDMLJobs:
+			return &Plan{PlanID: PlanShowDMLJobs, FullStmt: show}, nil
+		case sqlparser.VitessSchemaHistory:
+			return &Plan{PlanID: PlanShowSchemaHistory, FullStmt: show}, nil
 		case sqlparser.Table:
 			// rewrite WHERE clause if it exists
 			// `where Tables_in_Keyspace` => `where Tables_in_DbName`
@@ -80,6 +80,9 @@ const (
 	PlanShowMigrationLogs
 	PlanShowThrottledApps
 	PlanShowThrottlerStatus
+	PlanBatchDML
+	PlanShowDMLJobs
+	PlanShowSchemaHistory
 	NumPlans
 )
 
@@ -116,6 +119,9 @@ var planName = []string{
 	"ShowMigrationLogs",
 	"ShowThrottledApps",
 	"ShowThrottlerStatus",
+	"BatchDML",
+	"ShowDMLJobs",
+	"ShowSchemaHistory",
 }
 
 func (pt PlanType) String() string {
@@ -214,9 +220,13 @@ func Build(env *vtenv.Environment, statement sqlparser.Statement, tables map[str
 	case *sqlparser.Insert:
 		plan, err = analyzeInsert(stmt, tables)
 	case *sqlparser.Update:
-		plan, err = analyzeUpdate(stmt, tables)
+		if plan, err = analyzeBatchDML(stmt, stmt.TableExprs, tables); plan == nil && err == nil {
+			plan, err = analyzeUpdate(stmt, tables)
+		}
 	case *sqlparser.Delete:
-		plan, err = analyzeDelete(stmt, tables)
+		if plan, err = analyzeBatchDML(stmt, stmt.TableExprs, tables); plan == nil && err == nil {
+			plan, err = analyzeDelete(stmt, tables)
+		}
 	case *sqlparser.Set:
 		plan, err = analyzeSet(stmt), nil
 	case sqlparser.DDLStatement:
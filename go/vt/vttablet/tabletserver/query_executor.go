@@ -85,6 +85,16 @@ var (
 			Type: sqltypes.Int64,
 		},
 	}
+	partialSequenceFields = []*querypb.Field{
+		{
+			Name: "nextval",
+			Type: sqltypes.Int64,
+		},
+		{
+			Name: "count",
+			Type: sqltypes.Int64,
+		},
+	}
 	errTxThrottled = vterrors.Errorf(vtrpcpb.Code_RESOURCE_EXHAUSTED, "Transaction throttled")
 )
 
@@ -210,6 +220,12 @@ func (qre *QueryExecutor) Execute() (reply *sqltypes.Result, err error) {
 		return qre.execShowThrottledApps()
 	case p.PlanShowThrottlerStatus:
 		return qre.execShowThrottlerStatus()
+	case p.PlanBatchDML:
+		return qre.execBatchDML()
+	case p.PlanShowDMLJobs:
+		return qre.execShowDMLJobs()
+	case p.PlanShowSchemaHistory:
+		return qre.execShowSchemaHistory()
 	case p.PlanUnlockTables:
 		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "unlock tables should be executed with an existing connection")
 	case p.PlanSet:
@@ -308,6 +324,8 @@ func (qre *QueryExecutor) txConnExec(conn *StatefulConnection) (*sqltypes.Result
 		return qre.execLoad(conn)
 	case p.PlanCallProc:
 		return qre.execProc(conn)
+	case p.PlanBatchDML:
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "batch DML is not supported within a transaction or on a reserved connection: %s", qre.query)
 	}
 	return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "[BUG] %s unexpected plan type", qre.plan.PlanID.String())
 }
@@ -448,7 +466,15 @@ func (qre *QueryExecutor) checkPermissions() error {
 		username = ci.Username()
 	}
 
-	action, ruleCancelCtx, timeout, desc := qre.plan.Rules.GetAction(remoteAddr, username, qre.bindVars, qre.marginComments)
+	for _, name := range qre.plan.Rules.GetDryRunMatches(remoteAddr, username, qre.bindVars, qre.marginComments) {
+		qre.tsv.stats.QueryRuleDryRunHits.Add(name, 1)
+		qre.logStats.DryRunQueryRules = append(qre.logStats.DryRunQueryRules, name)
+	}
+
+	action, ruleCancelCtx, timeout, desc, name := qre.plan.Rules.GetAction(remoteAddr, username, qre.bindVars, qre.marginComments)
+	if action != rules.QRContinue {
+		qre.tsv.stats.QueryRuleHits.Add(name, 1)
+	}
 
 	bufferingTimeoutCtx, cancel := context.WithTimeout(qre.ctx, timeout) // aborts buffering at given timeout
 	defer cancel()
@@ -631,63 +657,256 @@ func (qre *QueryExecutor) execNextval() (*sqltypes.Result, error) {
 	t := qre.plan.Table
 	t.SequenceInfo.Lock()
 	defer t.SequenceInfo.Unlock()
-	if t.SequenceInfo.NextVal == 0 || t.SequenceInfo.NextVal+inc > t.SequenceInfo.LastVal {
-		_, err := qre.execAsTransaction(func(conn *StatefulConnection) (*sqltypes.Result, error) {
-			query := fmt.Sprintf("select next_id, cache from %s where id = 0 for update", sqlparser.String(tableName))
-			qr, err := qre.execStatefulConn(conn, query, false)
-			if err != nil {
-				return nil, err
-			}
-			if len(qr.Rows) != 1 {
-				return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "unexpected rows from reading sequence %s (possible mis-route): %d", tableName, len(qr.Rows))
-			}
-			nextID, err := qr.Rows[0][0].ToCastInt64()
-			if err != nil {
-				return nil, vterrors.Wrapf(err, "error loading sequence %s", tableName)
-			}
+
+	var ret int64
+	allocator, err := qre.sequenceBindVar(sqltypes.BvSequenceAllocator)
+	if err != nil {
+		return nil, err
+	}
+	partialVal, err := qre.sequenceBindVar(sqltypes.BvSequencePartial)
+	if err != nil {
+		return nil, err
+	}
+	partial, _ := partialVal.ToBool()
+	switch allocator.ToString() {
+	case "":
+		ret, err = qre.nextvalSingle(t.SequenceInfo, inc)
+	case "interleaved":
+		ret, inc, err = qre.nextvalInterleaved(t.SequenceInfo, inc, partial)
+	case "snowflake":
+		ret, inc, err = qre.nextvalSnowflake(t.SequenceInfo, inc, partial)
+	default:
+		err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "unknown sequence allocator for sequence %s: %s", tableName, allocator.ToString())
+	}
+	if err != nil {
+		return nil, err
+	}
+	if partial {
+		return &sqltypes.Result{
+			Fields: partialSequenceFields,
+			Rows: [][]sqltypes.Value{{
+				sqltypes.NewInt64(ret),
+				sqltypes.NewInt64(inc),
+			}},
+		}, nil
+	}
+	return &sqltypes.Result{
+		Fields: sequenceFields,
+		Rows: [][]sqltypes.Value{{
+			sqltypes.NewInt64(ret),
+		}},
+	}, nil
+}
+
+// nextvalSingle hands out values from a sequence backed by a single row
+// that owns the whole id space. The sequence lock must be held.
+func (qre *QueryExecutor) nextvalSingle(seq *eschema.SequenceInfo, inc int64) (int64, error) {
+	if seq.NextVal == 0 || seq.NextVal+inc > seq.LastVal {
+		err := qre.reserveSequence(seq, 0, func(nextID, cache int64) (int64, error) {
 			// If LastVal does not match next ID, then either:
 			// VTTablet just started, and we're initializing the cache, or
 			// Someone reset the id underneath us.
-			if t.SequenceInfo.LastVal != nextID {
-				if nextID < t.SequenceInfo.LastVal {
-					log.Warningf("Sequence next ID value %v is below the currently cached max %v, updating it to max", nextID, t.SequenceInfo.LastVal)
-					nextID = t.SequenceInfo.LastVal
+			if seq.LastVal != nextID {
+				if nextID < seq.LastVal {
+					log.Warningf("Sequence next ID value %v is below the currently cached max %v, updating it to max", nextID, seq.LastVal)
+					nextID = seq.LastVal
 				}
-				t.SequenceInfo.NextVal = nextID
-				t.SequenceInfo.LastVal = nextID
-			}
-			cache, err := qr.Rows[0][1].ToCastInt64()
-			if err != nil {
-				return nil, vterrors.Wrapf(err, "error loading sequence %s", tableName)
-			}
-			if cache < 1 {
-				return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid cache value for sequence %s: %d", tableName, cache)
+				seq.NextVal = nextID
+				seq.LastVal = nextID
 			}
 			newLast := nextID + cache
-			for newLast < t.SequenceInfo.NextVal+inc {
+			for newLast < seq.NextVal+inc {
 				newLast += cache
 			}
-			query = fmt.Sprintf("update %s set next_id = %d where id = 0", sqlparser.String(tableName), newLast)
-			conn.TxProperties().RecordQuery(query)
-			_, err = qre.execStatefulConn(conn, query, false)
-			if err != nil {
-				return nil, err
+			seq.LastVal = newLast
+			return newLast, nil
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+	ret := seq.NextVal
+	seq.NextVal += inc
+	return ret, nil
+}
+
+// nextvalInterleaved hands out values from a sequence whose id space is split
+// into blocks of cache values, with block k owned by shard k modulo the number
+// of shards. Every shard therefore allocates from its own row without ever
+// coordinating with the others. At most cache consecutive values can be handed
+// out at once: if partial is set, a larger increment is reduced to cache, and
+// otherwise it fails. It returns the first value and their count. The sequence
+// lock must be held.
+func (qre *QueryExecutor) nextvalInterleaved(seq *eschema.SequenceInfo, inc int64, partial bool) (int64, int64, error) {
+	if seq.NextVal == 0 || seq.NextVal+inc > seq.LastVal {
+		err := qre.reserveSequence(seq, 1<<62, func(nextID, cache int64) (int64, error) {
+			shard, shards := seq.ShardIndex, seq.ShardCount
+			if inc > cache {
+				if !partial {
+					return 0, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "cannot fetch %d consecutive values from interleaved sequence %s, which hands out at most its cache value of %d at once", inc, qre.plan.TableName(), cache)
+				}
+				inc = cache
+			}
+			if nextID < seq.LastVal {
+				log.Warningf("Sequence next ID value %v is below the currently cached max %v, updating it to max", nextID, seq.LastVal)
+				nextID = seq.LastVal
+			}
+			block := nextID / cache
+			start := nextID
+			if owner := block % shards; owner != shard {
+				block += (shard - owner + shards) % shards
+				start = block * cache
+			}
+			// Zero is never a valid sequence value.
+			start = max(start, 1)
+			if start+inc > (block+1)*cache {
+				block += shards
+				start = block * cache
+			}
+			seq.NextVal = start
+			seq.LastVal = (block + 1) * cache
+			return seq.LastVal, nil
+		})
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+	ret := seq.NextVal
+	seq.NextVal += inc
+	return ret, inc, nil
+}
+
+const (
+	snowflakeSequenceBits = 12
+	snowflakeShardBits    = 10
+	snowflakeTimeBits     = 41
+
+	// defaultSnowflakeEpoch is 2020-01-01T00:00:00Z in unix milliseconds.
+	defaultSnowflakeEpoch = 1577836800000
+)
+
+// snowflakeNow returns the current time in unix milliseconds.
+// It is a variable so that tests can control the clock.
+var snowflakeNow = func() int64 {
+	return time.Now().UnixMilli()
+}
+
+// nextvalSnowflake hands out snowflake ids laid out as
+// (milliseconds since epoch) << 22 | shard << 12 | counter.
+// The backing row stores the highest millisecond this tablet may use, and
+// every refill reserves cache more milliseconds, so that a new primary never
+// reuses a millisecond even if its clock is behind. At most 4096 consecutive
+// values, those of one millisecond, can be handed out at once: if partial is
+// set, a larger increment is reduced to that, and otherwise it fails. It
+// returns the first value and their count. The sequence lock must be held.
+func (qre *QueryExecutor) nextvalSnowflake(seq *eschema.SequenceInfo, inc int64, partial bool) (int64, int64, error) {
+	tableName := qre.plan.TableName()
+	if inc > 1<<snowflakeSequenceBits {
+		if !partial {
+			return 0, 0, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "cannot fetch %d consecutive values from snowflake sequence %s, which hands out at most %d at once", inc, tableName, 1<<snowflakeSequenceBits)
+		}
+		inc = 1 << snowflakeSequenceBits
+	}
+	epochVal, err := qre.sequenceBindVar(sqltypes.BvSequenceEpoch)
+	if err != nil {
+		return 0, 0, err
+	}
+	epoch, _ := epochVal.ToInt64()
+	if epoch == 0 {
+		epoch = defaultSnowflakeEpoch
+	}
+
+	ms, counter := seq.SnowflakeMs, seq.SnowflakeCounter
+	if now := snowflakeNow(); now > ms {
+		ms, counter = now, 0
+	}
+	if counter+inc > 1<<snowflakeSequenceBits {
+		ms, counter = ms+1, 0
+	}
+	// The first call after the cache was reset always reserves, which also
+	// loads the shard index.
+	if ms >= seq.LastVal {
+		err := qre.reserveSequence(seq, 1<<snowflakeShardBits, func(nextID, cache int64) (int64, error) {
+			if ms < nextID {
+				ms, counter = nextID, 0
 			}
-			t.SequenceInfo.LastVal = newLast
-			return nil, nil
+			seq.LastVal = ms + cache
+			return seq.LastVal, nil
 		})
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+	elapsed := ms - epoch
+	if elapsed < 0 || elapsed >= 1<<snowflakeTimeBits {
+		return 0, 0, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "time %d is out of range for snowflake sequence %s with epoch %d", ms, tableName, epoch)
+	}
+	seq.SnowflakeMs, seq.SnowflakeCounter = ms, counter+inc
+	return elapsed<<(snowflakeShardBits+snowflakeSequenceBits) | seq.ShardIndex<<snowflakeSequenceBits | counter, inc, nil
+}
+
+// reserveSequence reads the backing row of the sequence in a transaction
+// and stores the next_id returned by reserve. If maxShards is set, the
+// sequence is spread over the shards of its keyspace, and the row also holds
+// the position of this shard among them and their count, which are loaded
+// into seq before calling reserve. They are stored with the sequence rather
+// than derived from the shards of the keyspace, so that resharding the
+// keyspace cannot make two shards allocate the same values.
+func (qre *QueryExecutor) reserveSequence(seq *eschema.SequenceInfo, maxShards int64, reserve func(nextID, cache int64) (int64, error)) error {
+	tableName := qre.plan.TableName()
+	_, err := qre.execAsTransaction(func(conn *StatefulConnection) (*sqltypes.Result, error) {
+		columns := "next_id, cache"
+		if maxShards > 0 {
+			columns += ", shard_index, shard_count"
+		}
+		query := fmt.Sprintf("select %s from %s where id = 0 for update", columns, sqlparser.String(tableName))
+		qr, err := qre.execStatefulConn(conn, query, false)
 		if err != nil {
 			return nil, err
 		}
+		if len(qr.Rows) != 1 {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "unexpected rows from reading sequence %s (possible mis-route): %d", tableName, len(qr.Rows))
+		}
+		nextID, err := qr.Rows[0][0].ToCastInt64()
+		if err != nil {
+			return nil, vterrors.Wrapf(err, "error loading sequence %s", tableName)
+		}
+		cache, err := qr.Rows[0][1].ToCastInt64()
+		if err != nil {
+			return nil, vterrors.Wrapf(err, "error loading sequence %s", tableName)
+		}
+		if cache < 1 {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid cache value for sequence %s: %d", tableName, cache)
+		}
+		if maxShards > 0 {
+			shard, err1 := qr.Rows[0][2].ToCastInt64()
+			shards, err2 := qr.Rows[0][3].ToCastInt64()
+			if err1 != nil || err2 != nil || shards < 1 || shards > maxShards || shard < 0 || shard >= shards {
+				return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid shard_index %s and shard_count %s for sequence %s", qr.Rows[0][2].ToString(), qr.Rows[0][3].ToString(), tableName)
+			}
+			seq.ShardIndex, seq.ShardCount = shard, shards
+		}
+		newNextID, err := reserve(nextID, cache)
+		if err != nil {
+			return nil, err
+		}
+		query = fmt.Sprintf("update %s set next_id = %d where id = 0", sqlparser.String(tableName), newNextID)
+		conn.TxProperties().RecordQuery(query)
+		_, err = qre.execStatefulConn(conn, query, false)
+		if err != nil {
+			return nil, err
+		}
+		return nil, nil
+	})
+	return err
+}
+
+func (qre *QueryExecutor) sequenceBindVar(name string) (sqltypes.Value, error) {
+	bv, ok := qre.bindVars[name]
+	if !ok {
+		return sqltypes.NULL, nil
 	}
-	ret := t.SequenceInfo.NextVal
-	t.SequenceInfo.NextVal += inc
-	return &sqltypes.Result{
-		Fields: sequenceFields,
-		Rows: [][]sqltypes.Value{{
-			sqltypes.NewInt64(ret),
-		}},
-	}, nil
+	return sqltypes.BindVariableToValue(bv)
 }
 
 // execSelect sends a query to mysql only if another identical query is not running. Otherwise, it waits and
@@ -963,6 +1182,12 @@ func (qre *QueryExecutor) execAlterMigration() (*sqltypes.Result, error) {
 		return qre.tsv.onlineDDLExecutor.ForceCutOverMigration(qre.ctx, alterMigration.UUID)
 	case sqlparser.ForceCutOverAllMigrationType:
 		return qre.tsv.onlineDDLExecutor.ForceCutOverPendingMigrations(qre.ctx)
+	case sqlparser.PrepareCutOverMigrationType:
+		return qre.tsv.onlineDDLExecutor.PrepareCutOver(qre.ctx, alterMigration.UUID, alterMigration.Expire)
+	case sqlparser.CommitCutOverMigrationType:
+		return qre.tsv.onlineDDLExecutor.CommitCutOver(qre.ctx, alterMigration.UUID)
+	case sqlparser.AbortCutOverMigrationType:
+		return qre.tsv.onlineDDLExecutor.AbortCutOver(qre.ctx, alterMigration.UUID)
 	}
 	return nil, vterrors.New(vtrpcpb.Code_UNIMPLEMENTED, "ALTER VITESS_MIGRATION not implemented")
 }
@@ -981,6 +1206,28 @@ func (qre *QueryExecutor) execShowMigrations() (*sqltypes.Result, error) {
 	return nil, vterrors.New(vtrpcpb.Code_INTERNAL, "Expecting SHOW VITESS_MIGRATIONS plan")
 }
 
+func (qre *QueryExecutor) execBatchDML() (*sqltypes.Result, error) {
+	switch qre.plan.FullStmt.(type) {
+	case *sqlparser.Update, *sqlparser.Delete:
+		return qre.tsv.onlineDMLExecutor.SubmitJob(qre.ctx, qre.plan.FullStmt)
+	}
+	return nil, vterrors.New(vtrpcpb.Code_INTERNAL, "Expecting batch UPDATE or DELETE plan")
+}
+
+func (qre *QueryExecutor) execShowDMLJobs() (*sqltypes.Result, error) {
+	if showStmt, ok := qre.plan.FullStmt.(*sqlparser.Show); ok {
+		return qre.tsv.onlineDMLExecutor.ShowJobs(qre.ctx, showStmt)
+	}
+	return nil, vterrors.New(vtrpcpb.Code_INTERNAL, "Expecting SHOW VITESS_DML_JOBS plan")
+}
+
+func (qre *QueryExecutor) execShowSchemaHistory() (*sqltypes.Result, error) {
+	if showStmt, ok := qre.plan.FullStmt.(*sqlparser.Show); ok {
+		return qre.tsv.tracker.ShowSchemaHistory(qre.ctx, showStmt, qre.tsv.sm.Target().Shard)
+	}
+	return nil, vterrors.New(vtrpcpb.Code_INTERNAL, "Expecting SHOW VITESS_SCHEMA_HISTORY plan")
+}
+
 func (qre *QueryExecutor) execShowMigrationLogs() (*sqltypes.Result, error) {
 	if showMigrationLogsStmt, ok := qre.plan.FullStmt.(*sqlparser.ShowMigrationLogs); ok {
 		return qre.tsv.onlineDDLExecutor.ShowMigrationLogs(qre.ctx, showMigrationLogsStmt)
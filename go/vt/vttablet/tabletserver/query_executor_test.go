@@ -792,6 +792,191 @@ func TestQueryExecutorPlanNextval(t *testing.T) {
 	}
 }
 
+func TestQueryExecutorPlanNextvalInterleaved(t *testing.T) {
+	db := setUpQueryExecutorTest(t)
+	defer db.Close()
+	selQuery := "select next_id, cache, shard_index, shard_count from seq where id = 0 for update"
+	setNextID := func(nextID int64) {
+		db.AddQuery(selQuery, &sqltypes.Result{
+			Fields: []*querypb.Field{
+				{Type: sqltypes.Int64},
+				{Type: sqltypes.Int64},
+				{Type: sqltypes.Int64},
+				{Type: sqltypes.Int64},
+			},
+			Rows: [][]sqltypes.Value{{
+				sqltypes.NewInt64(nextID),
+				sqltypes.NewInt64(10),
+				sqltypes.NewInt64(1),
+				sqltypes.NewInt64(3),
+			}},
+		})
+	}
+	ctx := context.Background()
+	tsv := newTestTabletServer(ctx, noFlags, db)
+	defer tsv.StopService()
+	nextval := func(query string) (int64, error) {
+		qre := newTestQueryExecutor(ctx, tsv, query, 0)
+		qre.bindVars[sqltypes.BvSequenceAllocator] = sqltypes.StringBindVariable("interleaved")
+		qr, err := qre.Execute()
+		if err != nil {
+			return 0, err
+		}
+		return qr.Rows[0][0].ToInt64()
+	}
+	nextvalPartial := func(query string) (int64, int64) {
+		qre := newTestQueryExecutor(ctx, tsv, query, 0)
+		qre.bindVars[sqltypes.BvSequenceAllocator] = sqltypes.StringBindVariable("interleaved")
+		qre.bindVars[sqltypes.BvSequencePartial] = sqltypes.Int64BindVariable(1)
+		qr, err := qre.Execute()
+		require.NoError(t, err)
+		require.Len(t, qr.Rows[0], 2)
+		start, err := qr.Rows[0][0].ToInt64()
+		require.NoError(t, err)
+		count, err := qr.Rows[0][1].ToInt64()
+		require.NoError(t, err)
+		return start, count
+	}
+
+	// Shard 1 of 3, as stored in the backing row, owns the blocks [10, 20),
+	// [40, 50), [70, 80)...
+	setNextID(1)
+	db.AddQuery("update seq set next_id = 20 where id = 0", &sqltypes.Result{})
+	got, err := nextval("select next value from seq")
+	require.NoError(t, err)
+	assert.EqualValues(t, 10, got)
+
+	// The rest of the block is served from the cache.
+	db.DeleteQuery(selQuery)
+	got, err = nextval("select next 5 values from seq")
+	require.NoError(t, err)
+	assert.EqualValues(t, 11, got)
+
+	// 16..19 cannot hold 5 values, so the next owned block is reserved.
+	setNextID(20)
+	db.AddQuery("update seq set next_id = 50 where id = 0", &sqltypes.Result{})
+	got, err = nextval("select next 5 values from seq")
+	require.NoError(t, err)
+	assert.EqualValues(t, 40, got)
+
+	_, err = nextval("select next 11 values from seq")
+	require.ErrorContains(t, err, "cannot fetch 11 consecutive values from interleaved sequence seq, which hands out at most its cache value of 10 at once")
+
+	// When fewer values may be handed out, a whole block is.
+	db.AddQuery("update seq set next_id = 80 where id = 0", &sqltypes.Result{})
+	got, count := nextvalPartial("select next 11 values from seq")
+	assert.EqualValues(t, 70, got)
+	assert.EqualValues(t, 10, count)
+
+	// The shard metadata of the backing row is validated.
+	db.AddQuery(selQuery, &sqltypes.Result{
+		Fields: []*querypb.Field{
+			{Type: sqltypes.Int64},
+			{Type: sqltypes.Int64},
+			{Type: sqltypes.Int64},
+			{Type: sqltypes.Int64},
+		},
+		Rows: [][]sqltypes.Value{{
+			sqltypes.NewInt64(50),
+			sqltypes.NewInt64(10),
+			sqltypes.NewInt64(3),
+			sqltypes.NewInt64(3),
+		}},
+	})
+	_, err = nextval("select next 10 values from seq")
+	require.ErrorContains(t, err, "invalid shard_index 3 and shard_count 3 for sequence seq")
+}
+
+func TestQueryExecutorPlanNextvalSnowflake(t *testing.T) {
+	now := int64(defaultSnowflakeEpoch + 1000)
+	defer func(orig func() int64) { snowflakeNow = orig }(snowflakeNow)
+	snowflakeNow = func() int64 { return now }
+
+	db := setUpQueryExecutorTest(t)
+	defer db.Close()
+	selQuery := "select next_id, cache, shard_index, shard_count from seq where id = 0 for update"
+	setNextID := func(nextID int64) {
+		db.AddQuery(selQuery, &sqltypes.Result{
+			Fields: []*querypb.Field{
+				{Type: sqltypes.Int64},
+				{Type: sqltypes.Int64},
+				{Type: sqltypes.Int64},
+				{Type: sqltypes.Int64},
+			},
+			Rows: [][]sqltypes.Value{{
+				sqltypes.NewInt64(nextID),
+				sqltypes.NewInt64(100),
+				sqltypes.NewInt64(5),
+				sqltypes.NewInt64(8),
+			}},
+		})
+	}
+	ctx := context.Background()
+	tsv := newTestTabletServer(ctx, noFlags, db)
+	defer tsv.StopService()
+	nextval := func(query string) (int64, error) {
+		qre := newTestQueryExecutor(ctx, tsv, query, 0)
+		qre.bindVars[sqltypes.BvSequenceAllocator] = sqltypes.StringBindVariable("snowflake")
+		qre.bindVars[sqltypes.BvSequenceEpoch] = sqltypes.Int64BindVariable(0)
+		qr, err := qre.Execute()
+		if err != nil {
+			return 0, err
+		}
+		return qr.Rows[0][0].ToInt64()
+	}
+	snowflake := func(elapsed, counter int64) int64 {
+		return elapsed<<22 | 5<<12 | counter
+	}
+
+	// The first call reserves 100ms starting from the current time.
+	setNextID(0)
+	db.AddQuery(fmt.Sprintf("update seq set next_id = %d where id = 0", now+100), &sqltypes.Result{})
+	got, err := nextval("select next value from seq")
+	require.NoError(t, err)
+	assert.Equal(t, snowflake(1000, 0), got)
+
+	// Values within the same millisecond increment the counter.
+	db.DeleteQuery(selQuery)
+	got, err = nextval("select next 4000 values from seq")
+	require.NoError(t, err)
+	assert.Equal(t, snowflake(1000, 1), got)
+
+	// A block that does not fit the counter moves to the next millisecond.
+	got, err = nextval("select next 100 values from seq")
+	require.NoError(t, err)
+	assert.Equal(t, snowflake(1001, 0), got)
+
+	// A clock that goes backwards does not reuse a millisecond.
+	now -= 10
+	got, err = nextval("select next value from seq")
+	require.NoError(t, err)
+	assert.Equal(t, snowflake(1001, 100), got)
+
+	// Once the reserved window is used up, the persisted high-water mark
+	// wins over a clock that is behind.
+	now += 200
+	setNextID(now + 50)
+	db.AddQuery(fmt.Sprintf("update seq set next_id = %d where id = 0", now+150), &sqltypes.Result{})
+	got, err = nextval("select next value from seq")
+	require.NoError(t, err)
+	assert.Equal(t, snowflake(1240, 0), got)
+
+	_, err = nextval("select next 4097 values from seq")
+	require.ErrorContains(t, err, "cannot fetch 4097 consecutive values from snowflake sequence seq, which hands out at most 4096 at once")
+
+	// When fewer values may be handed out, a whole millisecond is.
+	qre := newTestQueryExecutor(ctx, tsv, "select next 5000 values from seq", 0)
+	qre.bindVars[sqltypes.BvSequenceAllocator] = sqltypes.StringBindVariable("snowflake")
+	qre.bindVars[sqltypes.BvSequenceEpoch] = sqltypes.Int64BindVariable(0)
+	qre.bindVars[sqltypes.BvSequencePartial] = sqltypes.Int64BindVariable(1)
+	qr, err := qre.Execute()
+	require.NoError(t, err)
+	assert.Equal(t, [][]sqltypes.Value{{
+		sqltypes.NewInt64(snowflake(1241, 0)),
+		sqltypes.NewInt64(4096),
+	}}, qr.Rows)
+}
+
 func TestQueryExecutorMessageStreamACL(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -1187,6 +1372,7 @@ func TestQueryExecutorDenyListQRFail(t *testing.T) {
 	if code := vterrors.Code(err); code != vtrpcpb.Code_INVALID_ARGUMENT {
 		t.Fatalf("qre.Execute: %v, want %v", code, vtrpcpb.Code_INVALID_ARGUMENT)
 	}
+	assert.EqualValues(t, 1, tsv.stats.QueryRuleHits.Counts()["disable update"])
 }
 
 func TestQueryExecutorDenyListQRRetry(t *testing.T) {
@@ -1242,6 +1428,52 @@ func TestQueryExecutorDenyListQRRetry(t *testing.T) {
 	}
 }
 
+func TestQueryExecutorDenyListDryRun(t *testing.T) {
+	db := setUpQueryExecutorTest(t)
+	defer db.Close()
+	query := "select * from test_table where name = 1 limit 1000"
+	expected := &sqltypes.Result{
+		Fields: getTestTableFields(),
+	}
+	db.AddQuery("select * from test_table where `name` = 1 limit 1000", expected)
+
+	db.AddQuery("select * from test_table where 1 != 1", &sqltypes.Result{
+		Fields: getTestTableFields(),
+	})
+
+	alterRule := rules.NewQueryRule("disable select", "disable select", rules.QRFail)
+	alterRule.SetQueryCond("select.*")
+	alterRule.AddTableCond("test_table")
+	alterRule.DryRun = true
+
+	rulesName := "denyListRulesDryRun"
+	rules := rules.New()
+	rules.Add(alterRule)
+
+	callInfo := &fakecallinfo.FakeCallInfo{
+		Remote: "127.0.0.1",
+		User:   "u2",
+	}
+	ctx := callinfo.NewContext(context.Background(), callInfo)
+	tsv := newTestTabletServer(ctx, noFlags, db)
+	tsv.qe.queryRuleSources.UnRegisterSource(rulesName)
+	tsv.qe.queryRuleSources.RegisterSource(rulesName)
+	defer tsv.qe.queryRuleSources.UnRegisterSource(rulesName)
+
+	err := tsv.qe.queryRuleSources.SetRules(rulesName, rules)
+	require.NoError(t, err)
+
+	qre := newTestQueryExecutor(ctx, tsv, query, 0)
+	defer tsv.StopService()
+
+	// A rule in dry-run mode does not fail the query, but reports it.
+	_, err = qre.Execute()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"disable select"}, qre.logStats.DryRunQueryRules)
+	assert.EqualValues(t, 1, tsv.stats.QueryRuleDryRunHits.Counts()["disable select"])
+	assert.Zero(t, tsv.stats.QueryRuleHits.Counts()["disable select"])
+}
+
 func TestReplaceSchemaName(t *testing.T) {
 	db := setUpQueryExecutorTest(t)
 	defer db.Close()
@@ -52,6 +52,7 @@ var (
 				<th>Transaction ID</th>
 				<th>Reserved ID</th>
 				<th>Error</th>
+				<th>Dry-run Rules</th>
 			</tr>
 		</thead>
 	`)
@@ -80,6 +81,7 @@ var (
 			<td>{{.TransactionID}}</td>
 			<td>{{.ReservedID}}</td>
 			<td>{{.ErrorStr}}</td>
+			<td>{{.FmtDryRunQueryRules}}</td>
 		</tr>
 	`))
 )
@@ -45,6 +45,7 @@ func TestQuerylogzHandler(t *testing.T) {
 	logStats.WaitingForConnection = 10 * time.Nanosecond
 	logStats.TransactionID = 131
 	logStats.ReservedID = 313
+	logStats.DryRunQueryRules = []string{"r1", "r2"}
 	logStats.Ctx = callerid.NewContext(
 		context.Background(),
 		callerid.NewEffectiveCallerID("effective-caller", "component", "subcomponent"),
@@ -72,6 +73,7 @@ func TestQuerylogzHandler(t *testing.T) {
 		`<td>131</td>`,
 		`<td>313</td>`,
 		`<td></td>`,
+		`<td>r1,r2</td>`,
 	}
 	logStats.EndTime = logStats.StartTime.Add(1 * time.Millisecond)
 	response := httptest.NewRecorder()
@@ -103,6 +105,7 @@ func TestQuerylogzHandler(t *testing.T) {
 		`<td>131</td>`,
 		`<td>313</td>`,
 		`<td></td>`,
+		`<td>r1,r2</td>`,
 	}
 	logStats.EndTime = logStats.StartTime.Add(20 * time.Millisecond)
 	response = httptest.NewRecorder()
@@ -134,6 +137,7 @@ func TestQuerylogzHandler(t *testing.T) {
 		`<td>131</td>`,
 		`<td>313</td>`,
 		`<td></td>`,
+		`<td>r1,r2</td>`,
 	}
 	logStats.EndTime = logStats.StartTime.Add(500 * time.Millisecond)
 	ch = make(chan *tabletenv.LogStats, 1)
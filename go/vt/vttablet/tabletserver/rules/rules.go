@@ -142,6 +142,17 @@ func (qrs *Rules) UnmarshalJSON(data []byte) (err error) {
 	return nil
 }
 
+// UnmarshalRule unmarshals the JSON definition of a single Rule.
+func UnmarshalRule(data []byte) (*Rule, error) {
+	var ruleInfo map[string]any
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&ruleInfo); err != nil {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "%v", err)
+	}
+	return BuildQueryRule(ruleInfo)
+}
+
 // MarshalJSON marshals to JSON.
 func (qrs *Rules) MarshalJSON() ([]byte, error) {
 	b := bytes.NewBuffer(nil)
@@ -169,7 +180,8 @@ func (qrs *Rules) FilterByPlan(query string, planid planbuilder.PlanType, tableN
 	return &Rules{newrules}
 }
 
-// GetAction runs the input against the rules engine and returns the action to be performed.
+// GetAction runs the input against the rules engine and returns the action to be performed,
+// along with the name of the rule that triggered it. Rules in dry-run mode never trigger.
 func (qrs *Rules) GetAction(
 	ip,
 	user string,
@@ -179,13 +191,36 @@ func (qrs *Rules) GetAction(
 	action Action,
 	cancelCtx context.Context,
 	timeout time.Duration,
-	desc string) {
+	desc string,
+	name string) {
 	for _, qr := range qrs.rules {
+		if qr.DryRun {
+			continue
+		}
 		if act := qr.GetAction(ip, user, bindVars, marginComments); act != QRContinue {
-			return act, qr.cancelCtx, qr.timeout, qr.Description
+			return act, qr.cancelCtx, qr.timeout, qr.Description, qr.Name
 		}
 	}
-	return QRContinue, nil, 0, ""
+	return QRContinue, nil, 0, "", ""
+}
+
+// GetDryRunMatches runs the input against the rules in dry-run mode, and returns the names of
+// those that would have triggered.
+func (qrs *Rules) GetDryRunMatches(
+	ip,
+	user string,
+	bindVars map[string]*querypb.BindVariable,
+	marginComments sqlparser.MarginComments,
+) (names []string) {
+	for _, qr := range qrs.rules {
+		if !qr.DryRun {
+			continue
+		}
+		if act := qr.GetAction(ip, user, bindVars, marginComments); act != QRContinue {
+			names = append(names, qr.Name)
+		}
+	}
+	return names
 }
 
 // -----------------------------------------------
@@ -202,6 +237,10 @@ type Rule struct {
 	Description string
 	Name        string
 
+	// DryRun rules never trigger their action: the queries they match
+	// are only reported.
+	DryRun bool
+
 	// All defined conditions must match for the rule to fire (AND).
 
 	// Regexp conditions. nil conditions are ignored (TRUE).
@@ -263,6 +302,7 @@ func (qr *Rule) Equal(other *Rule) bool {
 	}
 	return (qr.Description == other.Description &&
 		qr.Name == other.Name &&
+		qr.DryRun == other.DryRun &&
 		qr.requestIP.Equal(other.requestIP) &&
 		qr.user.Equal(other.user) &&
 		qr.query.Equal(other.query) &&
@@ -280,6 +320,7 @@ func (qr *Rule) Copy() (newqr *Rule) {
 	newqr = &Rule{
 		Description:     qr.Description,
 		Name:            qr.Name,
+		DryRun:          qr.DryRun,
 		requestIP:       qr.requestIP,
 		user:            qr.user,
 		query:           qr.query,
@@ -339,6 +380,9 @@ func (qr *Rule) MarshalJSON() ([]byte, error) {
 	if qr.timeout != 0 {
 		safeEncode(b, `,"Timeout":`, qr.timeout)
 	}
+	if qr.DryRun {
+		safeEncode(b, `,"DryRun":`, qr.DryRun)
+	}
 	_, _ = b.WriteString("}")
 	return b.Bytes(), nil
 }
@@ -874,7 +918,7 @@ func BuildQueryRule(ruleInfo map[string]any) (qr *Rule, err error) {
 	for k, v := range ruleInfo {
 		var sv string
 		var lv []any
-		var ok bool
+		var bv, ok bool
 		switch k {
 		case "Name", "Description", "RequestIP", "User", "Query", "Action", "LeadingComment", "TrailingComment":
 			sv, ok = v.(string)
@@ -886,6 +930,11 @@ func BuildQueryRule(ruleInfo map[string]any) (qr *Rule, err error) {
 			if !ok {
 				return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "want list for %s", k)
 			}
+		case "DryRun":
+			bv, ok = v.(bool)
+			if !ok {
+				return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "want bool for %s", k)
+			}
 		default:
 			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "unrecognized tag %s", k)
 		}
@@ -894,6 +943,8 @@ func BuildQueryRule(ruleInfo map[string]any) (qr *Rule, err error) {
 			qr.Name = sv
 		case "Description":
 			qr.Description = sv
+		case "DryRun":
+			qr.DryRun = bv
 		case "RequestIP":
 			err = qr.SetIPCond(sv)
 			if err != nil {
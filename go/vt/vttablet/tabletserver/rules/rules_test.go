@@ -26,6 +26,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/vt/sqlparser"
@@ -543,23 +544,24 @@ func TestAction(t *testing.T) {
 		Trailing: "other trailing comments",
 	}
 
-	action, cancelCtx, timeout, desc := qrs.GetAction("123", "user1", bv, mc)
+	action, cancelCtx, timeout, desc, name := qrs.GetAction("123", "user1", bv, mc)
 	assert.Equalf(t, action, QRFail, "expected fail, got %v", action)
 	assert.Equalf(t, timeout, time.Duration(0), "expected zero timeout")
 	assert.Equalf(t, desc, "rule 1", "want rule 1, got %s", desc)
+	assert.Equal(t, "r1", name)
 	assert.Nil(t, cancelCtx)
 
-	action, cancelCtx, timeout, desc = qrs.GetAction("1234", "user", bv, mc)
+	action, cancelCtx, timeout, desc, _ = qrs.GetAction("1234", "user", bv, mc)
 	assert.Equalf(t, action, QRFailRetry, "want fail_retry, got: %s", action)
 	assert.Equalf(t, timeout, time.Duration(0), "expected zero timeout")
 	assert.Equalf(t, desc, "rule 2", "want rule 2, got %s", desc)
 	assert.Nil(t, cancelCtx)
 
-	action, _, _, _ = qrs.GetAction("1234", "user1", bv, mc)
+	action, _, _, _, _ = qrs.GetAction("1234", "user1", bv, mc)
 	assert.Equalf(t, action, QRContinue, "want continue, got %s", action)
 
 	bv["a"] = sqltypes.Uint64BindVariable(1)
-	action, _, _, desc = qrs.GetAction("1234", "user1", bv, mc)
+	action, _, _, desc, _ = qrs.GetAction("1234", "user1", bv, mc)
 	assert.Equalf(t, action, QRFail, "want fail, got %s", action)
 	assert.Equalf(t, desc, "rule 3", "want rule 3, got %s", desc)
 
@@ -572,7 +574,7 @@ func TestAction(t *testing.T) {
 	newQrs := qrs.Copy()
 	newQrs.Add(qr4)
 
-	action, _, _, desc = newQrs.GetAction("1234", "user1", bv, mc)
+	action, _, _, desc, _ = newQrs.GetAction("1234", "user1", bv, mc)
 	assert.Equalf(t, action, QRFail, "want fail, got %s", action)
 	assert.Equalf(t, desc, "rule 4", "want rule 4, got %s", desc)
 
@@ -581,11 +583,40 @@ func TestAction(t *testing.T) {
 
 	newQrs = qrs.Copy()
 	newQrs.Add(qr5)
-	action, _, _, desc = newQrs.GetAction("1234", "user1", bv, mc)
+	action, _, _, desc, _ = newQrs.GetAction("1234", "user1", bv, mc)
 	assert.Equalf(t, action, QRFail, "want fail, got %s", action)
 	assert.Equalf(t, desc, "rule 5", "want rule 5, got %s", desc)
 }
 
+func TestDryRunAction(t *testing.T) {
+	qrs := New()
+
+	qr1 := NewQueryRule("rule 1", "r1", QRFail)
+	qr1.SetUserCond("user1")
+	qr1.DryRun = true
+
+	qr2 := NewQueryRule("rule 2", "r2", QRFailRetry)
+	qr2.SetUserCond("user.*")
+
+	qr3 := NewQueryRule("rule 3", "r3", QRFail)
+	qr3.DryRun = true
+
+	qrs.Add(qr1)
+	qrs.Add(qr2)
+	qrs.Add(qr3)
+
+	// The rules in dry-run mode are skipped.
+	action, _, _, _, name := qrs.Copy().GetAction("1234", "user1", nil, sqlparser.MarginComments{})
+	assert.Equal(t, QRFailRetry, action)
+	assert.Equal(t, "r2", name)
+
+	assert.Equal(t, []string{"r1", "r3"}, qrs.GetDryRunMatches("1234", "user1", nil, sqlparser.MarginComments{}))
+	assert.Equal(t, []string{"r3"}, qrs.GetDryRunMatches("1234", "admin", nil, sqlparser.MarginComments{}))
+
+	action, _, _, _, _ = qrs.GetAction("1234", "admin", nil, sqlparser.MarginComments{})
+	assert.Equal(t, QRContinue, action)
+}
+
 func TestImport(t *testing.T) {
 	var qrs = New()
 	jsondata := `[{
@@ -611,7 +642,8 @@ func TestImport(t *testing.T) {
 	},{
 		"Description": "desc2",
 		"Name": "name2",
-		"Action": "FAIL"
+		"Action": "FAIL",
+		"DryRun": true
 	}]`
 	err := qrs.UnmarshalJSON([]byte(jsondata))
 	if err != nil {
@@ -625,6 +657,17 @@ func TestImport(t *testing.T) {
 	}
 }
 
+func TestUnmarshalRule(t *testing.T) {
+	qr, err := UnmarshalRule([]byte(`{"Name": "r1", "Query": "select.*", "Action": "FAIL_RETRY", "DryRun": true}`))
+	require.NoError(t, err)
+	assert.Equal(t, `{"Description":"","Name":"r1","Query":"select.*","Action":"FAIL_RETRY","DryRun":true}`, marshalled(qr))
+
+	_, err = UnmarshalRule([]byte(`[{"Name": "r1"}]`))
+	assert.Equal(t, vtrpcpb.Code_INVALID_ARGUMENT, vterrors.Code(err))
+	_, err = UnmarshalRule([]byte(`{"Name": "r1", "Plans": ["Unknown"]}`))
+	assert.EqualError(t, err, "invalid plan name: Unknown")
+}
+
 type ValidJSONCase struct {
 	input string
 	op    Operator
@@ -709,6 +752,7 @@ var invalidjsons = []InvalidJSONCase{
 	{`[{"Plans": 1 }]`, "want list for Plans"},
 	{`[{"TableNames": 1 }]`, "want list for TableNames"},
 	{`[{"BindVarConds": 1 }]`, "want list for BindVarConds"},
+	{`[{"DryRun": "true" }]`, "want bool for DryRun"},
 	{`[{"RequestIP": "[" }]`, "could not set IP condition: ["},
 	{`[{"User": "[" }]`, "could not set User condition: ["},
 	{`[{"Query": "[" }]`, "could not set Query condition: ["},
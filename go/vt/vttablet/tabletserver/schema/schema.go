@@ -71,6 +71,17 @@ type SequenceInfo struct {
 	sync.Mutex
 	NextVal int64
 	LastVal int64
+
+	// SnowflakeMs and SnowflakeCounter track the last millisecond and
+	// the next counter value handed out by a snowflake sequence.
+	SnowflakeMs      int64
+	SnowflakeCounter int64
+
+	// ShardIndex and ShardCount are the position of this shard among the
+	// shards of a sequence that is spread over a sharded keyspace, and their
+	// count, as loaded from the backing row of the sequence.
+	ShardIndex int64
+	ShardCount int64
 }
 
 // Reset clears the cache for the sequence. This is called to ensure that we always start with a fresh cache,
@@ -82,6 +93,10 @@ func (seq *SequenceInfo) Reset() {
 	defer seq.Unlock()
 	seq.NextVal = 0
 	seq.LastVal = 0
+	seq.SnowflakeMs = 0
+	seq.SnowflakeCounter = 0
+	seq.ShardIndex = 0
+	seq.ShardCount = 0
 }
 
 func (seq *SequenceInfo) String() {
@@ -25,10 +25,12 @@ import (
 
 	"vitess.io/vitess/go/constants/sidecar"
 	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/mysql/sqlerror"
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/vt/log"
 	"vitess.io/vitess/go/vt/schema"
 	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/tabletenv"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/throttle/throttlerapp"
 
@@ -215,7 +217,15 @@ func (tr *Tracker) schemaUpdated(gtid string, ddl string, timestamp int64) error
 	}
 	ctx := context.Background()
 	// Engine will have reloaded the schema because vstream will reload it on a DDL
-	return tr.saveCurrentSchemaToDb(ctx, gtid, ddl, timestamp)
+	if err := tr.saveCurrentSchemaToDb(ctx, gtid, ddl, timestamp); err != nil {
+		return err
+	}
+	// The history is informational: failing to record it does not fail the schema version.
+	if err := tr.saveSchemaHistoryToDb(ctx, gtid, ddl, timestamp); err != nil {
+		log.Errorf("Error saving schema history: %s for ddl %s, gtid %s",
+			tr.env.Environment().Parser().TruncateForLog(err.Error()), ddl, gtid)
+	}
+	return nil
 }
 
 func (tr *Tracker) saveCurrentSchemaToDb(ctx context.Context, gtid, ddl string, timestamp int64) error {
@@ -241,6 +251,152 @@ func (tr *Tracker) saveCurrentSchemaToDb(ctx context.Context, gtid, ddl string,
 	return nil
 }
 
+// saveSchemaHistoryToDb records a schema_history row for each table changed by the DDL, holding the
+// table's CREATE statement before and after the change. The statement before the change is taken from
+// the table's previous history row. If there is none, and the DDL did not create the table, it is not
+// known and stored as NULL: by the time the tracker sees the DDL, the table has already changed.
+func (tr *Tracker) saveSchemaHistoryToDb(ctx context.Context, gtid, ddl string, timestamp int64) error {
+	dbName := tr.engine.cp.DBName()
+	tables, created, migrationUUID := schemaHistoryTables(ddl, dbName, tr.env.Environment().Parser())
+	if len(tables) == 0 {
+		return nil
+	}
+
+	conn, err := tr.engine.GetConnection(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Recycle()
+
+	for _, table := range tables {
+		query := sqlparser.BuildParsedQuery("select create_statement_after from %s.schema_history "+
+			"where table_name=%s order by id desc limit 1", sidecar.GetIdentifier(), encodeString(table)).Query
+		qr, err := conn.Conn.Exec(ctx, query, 1, false)
+		if err != nil {
+			return err
+		}
+		before := "null"
+		if len(qr.Rows) > 0 {
+			before = encodeString(qr.Rows[0][0].ToString())
+		} else if created[table] {
+			before = encodeString("")
+		}
+
+		after := ""
+		query = sqlparser.BuildParsedQuery("show create table %s.%s",
+			sqlparser.String(sqlparser.NewIdentifierCS(dbName)), sqlparser.String(sqlparser.NewIdentifierCS(table))).Query
+		qr, err = conn.Conn.Exec(ctx, query, 1, false)
+		if err != nil {
+			// The table no longer exists after a DROP or RENAME.
+			if sqlErr, isSQLErr := sqlerror.NewSQLErrorFromError(err).(*sqlerror.SQLError); !isSQLErr || sqlErr.Num != sqlerror.ERNoSuchTable {
+				return err
+			}
+		} else if len(qr.Rows) > 0 && len(qr.Rows[0]) > 1 {
+			after = qr.Rows[0][1].ToString()
+		}
+
+		query = sqlparser.BuildParsedQuery("insert into %s.schema_history "+
+			"(pos, time_updated, table_name, ddl, migration_uuid, create_statement_before, create_statement_after) "+
+			"values (%s, %d, %s, %s, %s, %s, %s)", sidecar.GetIdentifier(), encodeString(gtid), timestamp,
+			encodeString(table), encodeString(ddl), encodeString(migrationUUID),
+			before, encodeString(after)).Query
+		if _, err := conn.Conn.Exec(ctx, query, 1, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ShowSchemaHistory returns the schema history rows matching the `SHOW VITESS_SCHEMA_HISTORY` filter. A LIKE
+// filter applies to the table name. Rows are tagged with the given shard, so that results merged across
+// shards remain meaningful.
+func (tr *Tracker) ShowSchemaHistory(ctx context.Context, show *sqlparser.Show, shard string) (*sqltypes.Result, error) {
+	showBasic, ok := show.Internal.(*sqlparser.ShowBasic)
+	if !ok || showBasic.Command != sqlparser.VitessSchemaHistory {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "[BUG] ShowSchemaHistory expects a VitessSchemaHistory command. Statement: %s", sqlparser.String(show))
+	}
+	whereExpr := ""
+	if showBasic.Filter != nil {
+		if showBasic.Filter.Filter != nil {
+			whereExpr = fmt.Sprintf("where %s", sqlparser.String(showBasic.Filter.Filter))
+		} else if showBasic.Filter.Like != "" {
+			whereExpr = fmt.Sprintf("where table_name LIKE %s", sqlparser.String(sqlparser.NewStrLiteral(showBasic.Filter.Like)))
+		}
+	}
+
+	conn, err := tr.engine.GetConnection(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Recycle()
+
+	query := sqlparser.BuildParsedQuery("select * from (select %s as shard, id, pos, time_updated, table_name, ddl, "+
+		"migration_uuid, create_statement_before, create_statement_after from %s.schema_history) as history %s order by id",
+		encodeString(shard), sidecar.GetIdentifier(), whereExpr).Query
+	return conn.Conn.Exec(ctx, query, -1, true)
+}
+
+// schemaHistoryTables returns the tables of the given database that are changed by the DDL, those of them
+// that did not exist before the DDL, and the UUID of the Online DDL migration that issued it, if any. Online
+// DDL artifact tables are not part of the history, but the vreplication table swapped in by a migration's
+// cut-over identifies that migration.
+func schemaHistoryTables(ddl string, dbname string, parser *sqlparser.Parser) (tables []string, created map[string]bool, migrationUUID string) {
+	stmt, err := parser.Parse(ddl)
+	if err != nil {
+		return nil, nil, ""
+	}
+	ddlStmt, ok := stmt.(sqlparser.DDLStatement)
+	if !ok {
+		return nil, nil, ""
+	}
+	if onlineDDL, err := schema.OnlineDDLFromCommentedStatement(ddlStmt); err == nil {
+		migrationUUID = onlineDDL.UUID
+	}
+	isNew := make(map[string]bool)
+	switch ddlStmt := ddlStmt.(type) {
+	case *sqlparser.CreateTable:
+		isNew[ddlStmt.Table.Name.String()] = !ddlStmt.IfNotExists
+	case *sqlparser.CreateView:
+		isNew[ddlStmt.ViewName.Name.String()] = !ddlStmt.IsReplace
+	case *sqlparser.AlterTable:
+		for _, table := range ddlStmt.GetToTables() {
+			isNew[table.Name.String()] = true
+		}
+	case *sqlparser.RenameTable:
+		// A table renamed to is new, unless the statement also renamed it away, as when swapping tables.
+		for _, pair := range ddlStmt.TablePairs {
+			isNew[pair.ToTable.Name.String()] = true
+		}
+		for _, pair := range ddlStmt.TablePairs {
+			delete(isNew, pair.FromTable.Name.String())
+		}
+	}
+	created = make(map[string]bool)
+	seen := make(map[string]bool)
+	for _, table := range ddlStmt.AffectedTables() {
+		if table.IsEmpty() {
+			continue
+		}
+		if table.Qualifier.NotEmpty() && table.Qualifier.String() != dbname {
+			continue
+		}
+		tableName := table.Name.String()
+		isInternal, hint, uuid, _, _ := schema.AnalyzeInternalTableName(tableName)
+		if isInternal && hint == schema.InternalTableVreplicationHint.String() && migrationUUID == "" && len(uuid) == 32 {
+			migrationUUID = fmt.Sprintf("%s_%s_%s_%s_%s", uuid[0:8], uuid[8:12], uuid[12:16], uuid[16:20], uuid[20:32])
+		}
+		if isInternal || schema.IsOnlineDDLTableName(tableName) || seen[tableName] {
+			continue
+		}
+		seen[tableName] = true
+		tables = append(tables, tableName)
+		if isNew[tableName] {
+			created[tableName] = true
+		}
+	}
+	return tables, created, migrationUUID
+}
+
 func encodeString(in string) string {
 	buf := bytes.NewBuffer(nil)
 	sqltypes.NewVarChar(in).EncodeSQL(buf)
@@ -18,12 +18,14 @@ package schema
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
 	"vitess.io/vitess/go/sqltypes"
 	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	"vitess.io/vitess/go/vt/schema"
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vtenv"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/tabletenv"
@@ -40,6 +42,16 @@ func TestTracker(t *testing.T) {
 	db.AddQueryPattern(query, &sqltypes.Result{})
 
 	db.AddQueryPattern("insert into _vt.schema_version.*1-10.*", &sqltypes.Result{})
+	historyInserted := false
+	db.AddQuery("select create_statement_after from _vt.schema_history where table_name='tracker_test' order by id desc limit 1", &sqltypes.Result{})
+	db.AddQuery("show create table fakesqldb.tracker_test", sqltypes.MakeTestResult(sqltypes.MakeTestFields(
+		"Table|Create Table",
+		"varchar|varchar"),
+		"tracker_test|CREATE TABLE `tracker_test` (`id` int)",
+	))
+	db.AddQueryPatternWithCallback("insert into _vt.schema_history.*1-10.*tracker_test.*", &sqltypes.Result{}, func(query string) {
+		historyInserted = true
+	})
 	db.AddQueryPatternWithCallback("insert into _vt.schema_version.*1-3.*", &sqltypes.Result{}, func(query string) {
 		initialSchemaInserted = true
 	})
@@ -89,6 +101,7 @@ func TestTracker(t *testing.T) {
 	final := env.Stats().ErrorCounters.Counts()["INTERNAL"]
 	require.Equal(t, initial+1, final)
 	require.True(t, initialSchemaInserted)
+	require.True(t, historyInserted)
 }
 
 func TestTrackerShouldNotInsertInitialSchema(t *testing.T) {
@@ -175,3 +188,124 @@ func TestMustReloadSchemaOnDDL(t *testing.T) {
 		})
 	}
 }
+
+func TestSchemaHistoryTables(t *testing.T) {
+	onlineDDL, err := schema.NewOnlineDDL("ks", "x", "alter table x add column j int", schema.NewDDLStrategySetting(schema.DDLStrategyDirect, ""),
+		"", "6842c23d_0ad8_11ef_8b5c_0a43f95f28a3", sqlparser.NewTestParser())
+	require.NoError(t, err)
+
+	testcases := []struct {
+		query         string
+		tables        []string
+		created       map[string]bool
+		migrationUUID string
+	}{
+		{"create table x(i int)", []string{"x"}, map[string]bool{"x": true}, ""},
+		{"create table if not exists x(i int)", []string{"x"}, map[string]bool{}, ""},
+		{"alter table x add column j int", []string{"x"}, map[string]bool{}, ""},
+		{"bad", nil, nil, ""},
+		{"create database db1", nil, nil, ""},
+		{"create table db2.x(i int)", nil, map[string]bool{}, ""},
+		{"create table db1.x(i int)", []string{"x"}, map[string]bool{"x": true}, ""},
+		{"rename table x to y, y to z", []string{"x", "y", "z"}, map[string]bool{"z": true}, ""},
+		{"rename table x to tmp, y to x, tmp to y", []string{"x", "tmp", "y"}, map[string]bool{}, ""},
+		{"create table db1._4e5dcf80_354b_11eb_82cd_f875a4d24e90_20201203114014_gho(i int)", nil, map[string]bool{}, ""},
+		{onlineDDL.SQL, []string{"x"}, map[string]bool{}, "6842c23d_0ad8_11ef_8b5c_0a43f95f28a3"},
+		{"rename table x to _vt_hld_6842c23d0ad811ef8b5c0a43f95f28a3_20240507113022_, _vt_vrp_6842c23d0ad811ef8b5c0a43f95f28a3_20240507113022_ to x", []string{"x"}, map[string]bool{}, "6842c23d_0ad8_11ef_8b5c_0a43f95f28a3"},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.query, func(t *testing.T) {
+			tables, created, migrationUUID := schemaHistoryTables(tc.query, "db1", sqlparser.NewTestParser())
+			require.Equal(t, tc.tables, tables)
+			require.Equal(t, tc.created, created)
+			require.Equal(t, tc.migrationUUID, migrationUUID)
+		})
+	}
+}
+
+func TestSaveSchemaHistoryToDb(t *testing.T) {
+	se, db, cancel := getTestSchemaEngine(t, 0)
+	defer cancel()
+	tracker := NewTracker(se.env, nil, se)
+	gtid := "MySQL56/7b04699f-f5e9-11e9-bf88-9cb6d089e1c3:1-10"
+
+	db.AddQuery("show create table fakesqldb.t1", sqltypes.MakeTestResult(sqltypes.MakeTestFields(
+		"Table|Create Table",
+		"varchar|varchar"),
+		"t1|CREATE TABLE `t1` (`i` int, `j` int)",
+	))
+	var inserted string
+	db.AddQueryPatternWithCallback("insert into _vt.schema_history.*", &sqltypes.Result{}, func(query string) {
+		inserted = query
+	})
+
+	testcases := []struct {
+		name     string
+		ddl      string
+		previous *sqltypes.Result
+		before   string
+	}{
+		{
+			name:     "created table",
+			ddl:      "create table t1 (i int, j int)",
+			previous: &sqltypes.Result{},
+			before:   "''",
+		},
+		{
+			name:     "first change of an existing table",
+			ddl:      "alter table t1 add column j int",
+			previous: &sqltypes.Result{},
+			before:   "null",
+		},
+		{
+			name: "later change",
+			ddl:  "alter table t1 add column j int",
+			previous: sqltypes.MakeTestResult(sqltypes.MakeTestFields(
+				"create_statement_after",
+				"varchar"),
+				"CREATE TABLE `t1` (`i` int)",
+			),
+			before: "'CREATE TABLE `t1` (`i` int)'",
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db.AddQuery("select create_statement_after from _vt.schema_history where table_name='t1' order by id desc limit 1", tc.previous)
+			inserted = ""
+			require.NoError(t, tracker.saveSchemaHistoryToDb(context.Background(), gtid, tc.ddl, 1427325876))
+			require.Contains(t, inserted, fmt.Sprintf("'', %s, 'CREATE TABLE `t1` (`i` int, `j` int)')", tc.before))
+		})
+	}
+}
+
+func TestShowSchemaHistory(t *testing.T) {
+	se, db, cancel := getTestSchemaEngine(t, 0)
+	defer cancel()
+	tracker := NewTracker(se.env, nil, se)
+	parser := sqlparser.NewTestParser()
+
+	result := sqltypes.MakeTestResult(sqltypes.MakeTestFields(
+		"shard|id|pos|time_updated|table_name|ddl|migration_uuid|create_statement_before|create_statement_after",
+		"varchar|int64|varchar|int64|varchar|varchar|varchar|varchar|varchar"),
+		"-80|1|MySQL56/7b04699f-f5e9-11e9-bf88-9cb6d089e1c3:1-10|1427325876|customer|create table customer (id int)|||CREATE TABLE `customer` (`id` int)",
+	)
+	db.AddQuery("select * from (select '-80' as shard, id, pos, time_updated, table_name, ddl, migration_uuid, create_statement_before, create_statement_after from _vt.schema_history) as history where table_name LIKE 'customer' order by id", result)
+	db.AddQuery("select * from (select '-80' as shard, id, pos, time_updated, table_name, ddl, migration_uuid, create_statement_before, create_statement_after from _vt.schema_history) as history where migration_uuid = '9748c3b7_7fdb_11eb_ac2c_f875a4d24e90' order by id", &sqltypes.Result{})
+
+	stmt, err := parser.Parse("show vitess_schema_history from ks like 'customer'")
+	require.NoError(t, err)
+	qr, err := tracker.ShowSchemaHistory(context.Background(), stmt.(*sqlparser.Show), "-80")
+	require.NoError(t, err)
+	require.Equal(t, result.Rows, qr.Rows)
+
+	stmt, err = parser.Parse("show vitess_schema_history where migration_uuid = '9748c3b7_7fdb_11eb_ac2c_f875a4d24e90'")
+	require.NoError(t, err)
+	qr, err = tracker.ShowSchemaHistory(context.Background(), stmt.(*sqlparser.Show), "-80")
+	require.NoError(t, err)
+	require.Empty(t, qr.Rows)
+
+	stmt, err = parser.Parse("show vitess_migrations")
+	require.NoError(t, err)
+	_, err = tracker.ShowSchemaHistory(context.Background(), stmt.(*sqlparser.Show), "-80")
+	require.ErrorContains(t, err, "ShowSchemaHistory expects a VitessSchemaHistory command")
+}
@@ -121,6 +121,8 @@ type stateManager struct {
 	te          txEngine
 	messager    subComponent
 	ddle        onlineDDLExecutor
+	dmle        onlineDMLExecutor
+	pm          partitionManager
 	throttler   lagThrottler
 	tableGC     tableGarbageCollector
 
@@ -181,6 +183,16 @@ type (
 		Close()
 	}
 
+	onlineDMLExecutor interface {
+		Open() error
+		Close()
+	}
+
+	partitionManager interface {
+		Open() error
+		Close()
+	}
+
 	lagThrottler interface {
 		Open() error
 		Close()
@@ -466,6 +478,8 @@ func (sm *stateManager) servePrimary() error {
 	sm.throttler.Open()
 	sm.tableGC.Open()
 	sm.ddle.Open()
+	sm.dmle.Open()
+	sm.pm.Open()
 	sm.setState(topodatapb.TabletType_PRIMARY, StateServing)
 	return nil
 }
@@ -492,6 +506,8 @@ func (sm *stateManager) serveNonPrimary(wantTabletType topodatapb.TabletType) er
 	cancel := sm.terminateAllQueries(nil)
 	defer cancel()
 
+	sm.pm.Close()
+	sm.dmle.Close()
 	sm.ddle.Close()
 	sm.tableGC.Close()
 	sm.messager.Close()
@@ -550,7 +566,11 @@ func (sm *stateManager) unserveCommon() {
 	log.Infof("Finished execution of terminateAllQueries")
 	defer cancel()
 
-	log.Infof("Started online ddl executor close")
+	log.Infof("Started partition manager close")
+	sm.pm.Close()
+	log.Infof("Finished partition manager close. Started online dml executor close")
+	sm.dmle.Close()
+	log.Infof("Finished online dml executor close. Started online ddl executor close")
 	sm.ddle.Close()
 	log.Infof("Finished online ddl executor close. Started table garbage collector close")
 	sm.tableGC.Close()
@@ -91,6 +91,8 @@ func TestStateManagerServePrimary(t *testing.T) {
 	verifySubcomponent(t, 10, sm.throttler, testStateOpen)
 	verifySubcomponent(t, 11, sm.tableGC, testStateOpen)
 	verifySubcomponent(t, 12, sm.ddle, testStateOpen)
+	verifySubcomponent(t, 13, sm.dmle, testStateOpen)
+	verifySubcomponent(t, 14, sm.pm, testStateOpen)
 
 	assert.False(t, sm.se.(*testSchemaEngine).nonPrimary)
 	assert.True(t, sm.se.(*testSchemaEngine).ensureCalled)
@@ -105,20 +107,22 @@ func TestStateManagerServeNonPrimary(t *testing.T) {
 	err := sm.SetServingType(topodatapb.TabletType_REPLICA, testNow, StateServing, "")
 	require.NoError(t, err)
 
-	verifySubcomponent(t, 1, sm.ddle, testStateClosed)
-	verifySubcomponent(t, 2, sm.tableGC, testStateClosed)
-	verifySubcomponent(t, 3, sm.messager, testStateClosed)
-	verifySubcomponent(t, 4, sm.tracker, testStateClosed)
+	verifySubcomponent(t, 1, sm.pm, testStateClosed)
+	verifySubcomponent(t, 2, sm.dmle, testStateClosed)
+	verifySubcomponent(t, 3, sm.ddle, testStateClosed)
+	verifySubcomponent(t, 4, sm.tableGC, testStateClosed)
+	verifySubcomponent(t, 5, sm.messager, testStateClosed)
+	verifySubcomponent(t, 6, sm.tracker, testStateClosed)
 	assert.True(t, sm.se.(*testSchemaEngine).nonPrimary)
 
-	verifySubcomponent(t, 5, sm.se, testStateOpen)
-	verifySubcomponent(t, 6, sm.vstreamer, testStateOpen)
-	verifySubcomponent(t, 7, sm.qe, testStateOpen)
-	verifySubcomponent(t, 8, sm.txThrottler, testStateOpen)
-	verifySubcomponent(t, 9, sm.te, testStateNonPrimary)
-	verifySubcomponent(t, 10, sm.rt, testStateNonPrimary)
-	verifySubcomponent(t, 11, sm.watcher, testStateOpen)
-	verifySubcomponent(t, 12, sm.throttler, testStateOpen)
+	verifySubcomponent(t, 7, sm.se, testStateOpen)
+	verifySubcomponent(t, 8, sm.vstreamer, testStateOpen)
+	verifySubcomponent(t, 9, sm.qe, testStateOpen)
+	verifySubcomponent(t, 10, sm.txThrottler, testStateOpen)
+	verifySubcomponent(t, 11, sm.te, testStateNonPrimary)
+	verifySubcomponent(t, 12, sm.rt, testStateNonPrimary)
+	verifySubcomponent(t, 13, sm.watcher, testStateOpen)
+	verifySubcomponent(t, 14, sm.throttler, testStateOpen)
 
 	assert.Equal(t, topodatapb.TabletType_REPLICA, sm.target.TabletType)
 	assert.Equal(t, StateServing, sm.state)
@@ -130,20 +134,22 @@ func TestStateManagerUnservePrimary(t *testing.T) {
 	err := sm.SetServingType(topodatapb.TabletType_PRIMARY, testNow, StateNotServing, "")
 	require.NoError(t, err)
 
-	verifySubcomponent(t, 1, sm.ddle, testStateClosed)
-	verifySubcomponent(t, 2, sm.tableGC, testStateClosed)
-	verifySubcomponent(t, 3, sm.throttler, testStateClosed)
-	verifySubcomponent(t, 4, sm.messager, testStateClosed)
-	verifySubcomponent(t, 5, sm.te, testStateClosed)
+	verifySubcomponent(t, 1, sm.pm, testStateClosed)
+	verifySubcomponent(t, 2, sm.dmle, testStateClosed)
+	verifySubcomponent(t, 3, sm.ddle, testStateClosed)
+	verifySubcomponent(t, 4, sm.tableGC, testStateClosed)
+	verifySubcomponent(t, 5, sm.throttler, testStateClosed)
+	verifySubcomponent(t, 6, sm.messager, testStateClosed)
+	verifySubcomponent(t, 7, sm.te, testStateClosed)
 
-	verifySubcomponent(t, 6, sm.tracker, testStateClosed)
-	verifySubcomponent(t, 7, sm.watcher, testStateClosed)
-	verifySubcomponent(t, 8, sm.se, testStateOpen)
-	verifySubcomponent(t, 9, sm.vstreamer, testStateOpen)
-	verifySubcomponent(t, 10, sm.qe, testStateOpen)
-	verifySubcomponent(t, 11, sm.txThrottler, testStateOpen)
+	verifySubcomponent(t, 8, sm.tracker, testStateClosed)
+	verifySubcomponent(t, 9, sm.watcher, testStateClosed)
+	verifySubcomponent(t, 10, sm.se, testStateOpen)
+	verifySubcomponent(t, 11, sm.vstreamer, testStateOpen)
+	verifySubcomponent(t, 12, sm.qe, testStateOpen)
+	verifySubcomponent(t, 13, sm.txThrottler, testStateOpen)
 
-	verifySubcomponent(t, 12, sm.rt, testStatePrimary)
+	verifySubcomponent(t, 14, sm.rt, testStatePrimary)
 
 	assert.Equal(t, topodatapb.TabletType_PRIMARY, sm.target.TabletType)
 	assert.Equal(t, StateNotServing, sm.state)
@@ -155,22 +161,24 @@ func TestStateManagerUnserveNonPrimary(t *testing.T) {
 	err := sm.SetServingType(topodatapb.TabletType_RDONLY, testNow, StateNotServing, "")
 	require.NoError(t, err)
 
-	verifySubcomponent(t, 1, sm.ddle, testStateClosed)
-	verifySubcomponent(t, 2, sm.tableGC, testStateClosed)
-	verifySubcomponent(t, 3, sm.throttler, testStateClosed)
-	verifySubcomponent(t, 4, sm.messager, testStateClosed)
-	verifySubcomponent(t, 5, sm.te, testStateClosed)
+	verifySubcomponent(t, 1, sm.pm, testStateClosed)
+	verifySubcomponent(t, 2, sm.dmle, testStateClosed)
+	verifySubcomponent(t, 3, sm.ddle, testStateClosed)
+	verifySubcomponent(t, 4, sm.tableGC, testStateClosed)
+	verifySubcomponent(t, 5, sm.throttler, testStateClosed)
+	verifySubcomponent(t, 6, sm.messager, testStateClosed)
+	verifySubcomponent(t, 7, sm.te, testStateClosed)
 
-	verifySubcomponent(t, 6, sm.tracker, testStateClosed)
+	verifySubcomponent(t, 8, sm.tracker, testStateClosed)
 	assert.True(t, sm.se.(*testSchemaEngine).nonPrimary)
 
-	verifySubcomponent(t, 7, sm.se, testStateOpen)
-	verifySubcomponent(t, 8, sm.vstreamer, testStateOpen)
-	verifySubcomponent(t, 9, sm.qe, testStateOpen)
-	verifySubcomponent(t, 10, sm.txThrottler, testStateOpen)
+	verifySubcomponent(t, 9, sm.se, testStateOpen)
+	verifySubcomponent(t, 10, sm.vstreamer, testStateOpen)
+	verifySubcomponent(t, 11, sm.qe, testStateOpen)
+	verifySubcomponent(t, 12, sm.txThrottler, testStateOpen)
 
-	verifySubcomponent(t, 11, sm.rt, testStateNonPrimary)
-	verifySubcomponent(t, 12, sm.watcher, testStateOpen)
+	verifySubcomponent(t, 13, sm.rt, testStateNonPrimary)
+	verifySubcomponent(t, 14, sm.watcher, testStateOpen)
 
 	assert.Equal(t, topodatapb.TabletType_RDONLY, sm.target.TabletType)
 	assert.Equal(t, StateNotServing, sm.state)
@@ -182,19 +190,21 @@ func TestStateManagerClose(t *testing.T) {
 	err := sm.SetServingType(topodatapb.TabletType_RDONLY, testNow, StateNotConnected, "")
 	require.NoError(t, err)
 
-	verifySubcomponent(t, 1, sm.ddle, testStateClosed)
-	verifySubcomponent(t, 2, sm.tableGC, testStateClosed)
-	verifySubcomponent(t, 3, sm.throttler, testStateClosed)
-	verifySubcomponent(t, 4, sm.messager, testStateClosed)
-	verifySubcomponent(t, 5, sm.te, testStateClosed)
-	verifySubcomponent(t, 6, sm.tracker, testStateClosed)
-
-	verifySubcomponent(t, 7, sm.txThrottler, testStateClosed)
-	verifySubcomponent(t, 8, sm.qe, testStateClosed)
-	verifySubcomponent(t, 9, sm.watcher, testStateClosed)
-	verifySubcomponent(t, 10, sm.vstreamer, testStateClosed)
-	verifySubcomponent(t, 11, sm.rt, testStateClosed)
-	verifySubcomponent(t, 12, sm.se, testStateClosed)
+	verifySubcomponent(t, 1, sm.pm, testStateClosed)
+	verifySubcomponent(t, 2, sm.dmle, testStateClosed)
+	verifySubcomponent(t, 3, sm.ddle, testStateClosed)
+	verifySubcomponent(t, 4, sm.tableGC, testStateClosed)
+	verifySubcomponent(t, 5, sm.throttler, testStateClosed)
+	verifySubcomponent(t, 6, sm.messager, testStateClosed)
+	verifySubcomponent(t, 7, sm.te, testStateClosed)
+	verifySubcomponent(t, 8, sm.tracker, testStateClosed)
+
+	verifySubcomponent(t, 9, sm.txThrottler, testStateClosed)
+	verifySubcomponent(t, 10, sm.qe, testStateClosed)
+	verifySubcomponent(t, 11, sm.watcher, testStateClosed)
+	verifySubcomponent(t, 12, sm.vstreamer, testStateClosed)
+	verifySubcomponent(t, 13, sm.rt, testStateClosed)
+	verifySubcomponent(t, 14, sm.se, testStateClosed)
 
 	assert.Equal(t, topodatapb.TabletType_RDONLY, sm.target.TabletType)
 	assert.Equal(t, StateNotConnected, sm.state)
@@ -296,20 +306,22 @@ func TestStateManagerSetServingTypeNoChange(t *testing.T) {
 	err = sm.SetServingType(topodatapb.TabletType_REPLICA, testNow, StateServing, "")
 	require.NoError(t, err)
 
-	verifySubcomponent(t, 1, sm.ddle, testStateClosed)
-	verifySubcomponent(t, 2, sm.tableGC, testStateClosed)
-	verifySubcomponent(t, 3, sm.messager, testStateClosed)
-	verifySubcomponent(t, 4, sm.tracker, testStateClosed)
+	verifySubcomponent(t, 1, sm.pm, testStateClosed)
+	verifySubcomponent(t, 2, sm.dmle, testStateClosed)
+	verifySubcomponent(t, 3, sm.ddle, testStateClosed)
+	verifySubcomponent(t, 4, sm.tableGC, testStateClosed)
+	verifySubcomponent(t, 5, sm.messager, testStateClosed)
+	verifySubcomponent(t, 6, sm.tracker, testStateClosed)
 	assert.True(t, sm.se.(*testSchemaEngine).nonPrimary)
 
-	verifySubcomponent(t, 5, sm.se, testStateOpen)
-	verifySubcomponent(t, 6, sm.vstreamer, testStateOpen)
-	verifySubcomponent(t, 7, sm.qe, testStateOpen)
-	verifySubcomponent(t, 8, sm.txThrottler, testStateOpen)
-	verifySubcomponent(t, 9, sm.te, testStateNonPrimary)
-	verifySubcomponent(t, 10, sm.rt, testStateNonPrimary)
-	verifySubcomponent(t, 11, sm.watcher, testStateOpen)
-	verifySubcomponent(t, 12, sm.throttler, testStateOpen)
+	verifySubcomponent(t, 7, sm.se, testStateOpen)
+	verifySubcomponent(t, 8, sm.vstreamer, testStateOpen)
+	verifySubcomponent(t, 9, sm.qe, testStateOpen)
+	verifySubcomponent(t, 10, sm.txThrottler, testStateOpen)
+	verifySubcomponent(t, 11, sm.te, testStateNonPrimary)
+	verifySubcomponent(t, 12, sm.rt, testStateNonPrimary)
+	verifySubcomponent(t, 13, sm.watcher, testStateOpen)
+	verifySubcomponent(t, 14, sm.throttler, testStateOpen)
 
 	assert.Equal(t, topodatapb.TabletType_REPLICA, sm.target.TabletType)
 	assert.Equal(t, StateServing, sm.state)
@@ -750,6 +762,8 @@ func newTestStateManager(t *testing.T) *stateManager {
 		te:          &testTxEngine{},
 		messager:    &testSubcomponent{},
 		ddle:        &testOnlineDDLExecutor{},
+		dmle:        &testOnlineDMLExecutor{},
+		pm:          &testPartitionManager{},
 		throttler:   &testLagThrottler{},
 		tableGC:     &testTableGC{},
 		rw:          newRequestsWaiter(),
@@ -947,6 +961,36 @@ func (te *testOnlineDDLExecutor) Close() {
 	te.state = testStateClosed
 }
 
+type testOnlineDMLExecutor struct {
+	testOrderState
+}
+
+func (te *testOnlineDMLExecutor) Open() error {
+	te.order = order.Add(1)
+	te.state = testStateOpen
+	return nil
+}
+
+func (te *testOnlineDMLExecutor) Close() {
+	te.order = order.Add(1)
+	te.state = testStateClosed
+}
+
+type testPartitionManager struct {
+	testOrderState
+}
+
+func (te *testPartitionManager) Open() error {
+	te.order = order.Add(1)
+	te.state = testStateOpen
+	return nil
+}
+
+func (te *testPartitionManager) Close() {
+	te.order = order.Add(1)
+	te.state = testStateClosed
+}
+
 type testLagThrottler struct {
 	testOrderState
 }
@@ -209,6 +209,9 @@ func registerTabletEnvFlags(fs *pflag.FlagSet) {
 	fs.BoolVar(&currentConfig.EnableOnlineDDL, "queryserver_enable_online_ddl", true, "Enable online DDL.")
 	fs.BoolVar(&currentConfig.SanitizeLogMessages, "sanitize_log_messages", false, "Remove potentially sensitive information in tablet INFO, WARNING, and ERROR log messages such as query parameters.")
 	fs.BoolVar(&currentConfig.EnableSettingsPool, "queryserver-enable-settings-pool", true, "Enable pooling of connections with modified system settings")
+	fs.BoolVar(&currentConfig.EnablePoolPriorityScheduling, "queryserver-enable-pool-priority-scheduling", defaultConfig.EnablePoolPriorityScheduling, "If true, the queries waiting for a connection in the query, stream and transaction pools are served by priority instead of first-come first-served")
+	fs.IntVar(&currentConfig.PoolDefaultPriority, "queryserver-pool-default-priority", defaultConfig.PoolDefaultPriority, "Priority assigned to the queries waiting for a pool connection that lack priority information, between 0 (served first) and 100")
+	fs.StringSliceVar(&currentConfig.PoolLowPriorityWorkloads, "queryserver-pool-low-priority-workloads", defaultConfig.PoolLowPriorityWorkloads, "Comma-separated list of workload names whose queries are served after all the others waiting for a pool connection, whatever their priority")
 
 	fs.Int64Var(&currentConfig.RowStreamer.MaxInnoDBTrxHistLen, "vreplication_copy_phase_max_innodb_history_list_length", 1000000, "The maximum InnoDB transaction history that can exist on a vstreamer (source) before starting another round of copying rows. This helps to limit the impact on the source tablet.")
 	fs.Int64Var(&currentConfig.RowStreamer.MaxMySQLReplLagSecs, "vreplication_copy_phase_max_mysql_replication_lag", 43200, "The maximum MySQL replication lag (in seconds) that can exist on a vstreamer (source) before starting another round of copying rows. This helps to limit the impact on the source tablet.")
@@ -364,6 +367,10 @@ type TabletConfig struct {
 	EnableOnlineDDL          bool `json:"-"`
 	EnableSettingsPool       bool `json:"-"`
 
+	EnablePoolPriorityScheduling bool     `json:"-"`
+	PoolDefaultPriority          int      `json:"-"`
+	PoolLowPriorityWorkloads     []string `json:"-"`
+
 	RowStreamer RowStreamerConfig `json:"rowStreamer,omitempty"`
 
 	EnableViews bool `json:"-"`
@@ -877,6 +884,9 @@ func (c *TabletConfig) Verify() error {
 	if err := c.verifyTxThrottlerConfig(); err != nil {
 		return err
 	}
+	if v := c.PoolDefaultPriority; v > sqlparser.MaxPriorityValue || v < 0 {
+		return fmt.Errorf("--queryserver-pool-default-priority must be >= 0 and <= %v (specified value: %v)", sqlparser.MaxPriorityValue, v)
+	}
 	if v := c.HotRowProtection.MaxQueueSize; v <= 0 {
 		return fmt.Errorf("--hot_row_protection_max_queue_size must be > 0 (specified value: %v)", v)
 	}
@@ -1078,6 +1088,9 @@ var defaultConfig = TabletConfig{
 	EnableOnlineDDL:          true,
 	EnableTableGC:            true,
 
+	PoolDefaultPriority:      sqlparser.MaxPriorityValue / 2,
+	PoolLowPriorityWorkloads: []string{},
+
 	RowStreamer: RowStreamerConfig{
 		MaxInnoDBTrxHistLen: 1000000,
 		MaxMySQLReplLagSecs: 43200,
@@ -62,6 +62,9 @@ type LogStats struct {
 	ReservedID           int64
 	Error                error
 	CachedPlan           bool
+	// DryRunQueryRules are the names of the query rules in dry-run mode
+	// that matched the query.
+	DryRunQueryRules []string
 }
 
 // NewLogStats constructs a new LogStats with supplied Method and ctx
@@ -150,6 +153,12 @@ func (stats *LogStats) FmtQuerySources() string {
 	return strings.Join(sources[:n], ",")
 }
 
+// FmtDryRunQueryRules returns a comma separated list of the query rules
+// in dry-run mode that matched the query.
+func (stats *LogStats) FmtDryRunQueryRules() string {
+	return strings.Join(stats.DryRunQueryRules, ",")
+}
+
 // ContextHTML returns the HTML version of the context that was used, or "".
 // This is a method on LogStats instead of a field so that it doesn't need
 // to be passed by value everywhere.
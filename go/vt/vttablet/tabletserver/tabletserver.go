@@ -25,6 +25,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -53,6 +54,8 @@ import (
 	"vitess.io/vitess/go/vt/vtenv"
 	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vttablet/onlineddl"
+	"vitess.io/vitess/go/vt/vttablet/onlinedml"
+	"vitess.io/vitess/go/vt/vttablet/partitionmgr"
 	"vitess.io/vitess/go/vt/vttablet/queryservice"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/gc"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/messager"
@@ -121,6 +124,8 @@ type TabletServer struct {
 	// sm manages state transitions.
 	sm                *stateManager
 	onlineDDLExecutor *onlineddl.Executor
+	onlineDMLExecutor *onlinedml.Executor
+	partitionManager  *partitionmgr.Manager
 
 	// alias is used for identifying this tabletserver in healthcheck responses.
 	alias *topodatapb.TabletAlias
@@ -186,6 +191,8 @@ func NewTabletServer(ctx context.Context, env *vtenv.Environment, name string, c
 
 	tsv.tableGC = gc.NewTableGC(tsv, topoServer, tsv.lagThrottler)
 	tsv.onlineDDLExecutor = onlineddl.NewExecutor(tsv, alias, topoServer, tsv.lagThrottler, tabletTypeFunc, tsv.onlineDDLExecutorToggleTableBuffer, tsv.tableGC.RequestChecks)
+	tsv.onlineDMLExecutor = onlinedml.NewExecutor(tsv, alias, tsv.lagThrottler)
+	tsv.partitionManager = partitionmgr.NewManager(tsv, tsv.onlineDDLExecutor.SubmitMigration)
 
 	tsv.sm = &stateManager{
 		statelessql: tsv.statelessql,
@@ -202,6 +209,8 @@ func NewTabletServer(ctx context.Context, env *vtenv.Environment, name string, c
 		te:          tsv.te,
 		messager:    tsv.messager,
 		ddle:        tsv.onlineDDLExecutor,
+		dmle:        tsv.onlineDMLExecutor,
+		pm:          tsv.partitionManager,
 		throttler:   tsv.lagThrottler,
 		tableGC:     tsv.tableGC,
 		rw:          newRequestsWaiter(),
@@ -273,6 +282,8 @@ func (tsv *TabletServer) InitDBConfig(target *querypb.Target, dbcfgs *dbconfigs.
 	tsv.vstreamer.InitDBConfig(target.Keyspace, target.Shard)
 	tsv.hs.InitDBConfig(target, tsv.config.DB.DbaWithDB())
 	tsv.onlineDDLExecutor.InitDBConfig(target.Keyspace, target.Shard, dbcfgs.DBName)
+	tsv.onlineDMLExecutor.InitDBConfig(target.Keyspace, target.Shard, dbcfgs.DBName)
+	tsv.partitionManager.InitDBConfig(target.Keyspace, target.Shard)
 	tsv.lagThrottler.InitDBConfig(target.Keyspace, target.Shard)
 	tsv.tableGC.InitDBConfig(target.Keyspace, target.Shard, dbcfgs.DBName)
 	return nil
@@ -559,6 +570,23 @@ func (tsv *TabletServer) getPriorityFromOptions(options *querypb.ExecuteOptions)
 	return optionsPriority
 }
 
+// getPoolPriorityFromOptions returns the priority of the request when it has to
+// wait for a connection in the pools: the value of its PRIORITY directive, or
+// the default pool priority. The requests of the low priority workloads are
+// ranked after all the others.
+func (tsv *TabletServer) getPoolPriorityFromOptions(options *querypb.ExecuteOptions) int {
+	priority := tsv.config.PoolDefaultPriority
+	if options.GetPriority() != "" {
+		if optionsPriority, err := strconv.Atoi(options.GetPriority()); err == nil {
+			priority = optionsPriority
+		}
+	}
+	if workload := options.GetWorkloadName(); workload != "" && slices.Contains(tsv.config.PoolLowPriorityWorkloads, workload) {
+		priority += sqlparser.MaxPriorityValue + 1
+	}
+	return priority
+}
+
 // resolveTargetType returns the appropriate target tablet type for a
 // TabletServer request. If the caller has a local context then it's
 // an internal request and the target is the local tablet's current
@@ -1554,6 +1582,10 @@ func (tsv *TabletServer) execRequest(
 		tsv.sm.EndRequest()
 	}()
 
+	if tsv.config.EnablePoolPriorityScheduling {
+		ctx = smartconnpool.NewContextWithPriority(ctx, tsv.getPoolPriorityFromOptions(options))
+	}
+
 	err = exec(ctx, logStats)
 	if err != nil {
 		return tsv.convertAndLogError(ctx, sql, bindVariables, err, logStats)
@@ -1757,6 +1789,11 @@ func (tsv *TabletServer) ExitLameduck() {
 	tsv.sm.ExitLameduck()
 }
 
+// CurrentTarget returns the current target of the TabletServer.
+func (tsv *TabletServer) CurrentTarget() *querypb.Target {
+	return tsv.sm.Target()
+}
+
 // IsServing returns true if TabletServer is in SERVING state.
 func (tsv *TabletServer) IsServing() bool {
 	return tsv.sm.IsServing()
@@ -2787,3 +2787,32 @@ func addTabletServerSupportedQueries(db *fakesqldb.DB) {
 		}},
 	})
 }
+
+func TestGetPoolPriorityFromOptions(t *testing.T) {
+	cfg := tabletenv.NewDefaultConfig()
+	cfg.PoolLowPriorityWorkloads = []string{"batch"}
+	tsv := &TabletServer{config: cfg}
+
+	tcases := []struct {
+		options *querypb.ExecuteOptions
+		want    int
+	}{{
+		options: nil,
+		want:    50,
+	}, {
+		options: &querypb.ExecuteOptions{Priority: "10"},
+		want:    10,
+	}, {
+		options: &querypb.ExecuteOptions{WorkloadName: "batch"},
+		want:    151,
+	}, {
+		options: &querypb.ExecuteOptions{Priority: "0", WorkloadName: "batch"},
+		want:    101,
+	}, {
+		options: &querypb.ExecuteOptions{Priority: "100", WorkloadName: "app"},
+		want:    100,
+	}}
+	for _, tcase := range tcases {
+		assert.Equal(t, tcase.want, tsv.getPoolPriorityFromOptions(tcase.options), "%v", tcase.options)
+	}
+}
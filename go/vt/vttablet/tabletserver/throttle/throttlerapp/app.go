@@ -48,9 +48,12 @@ const (
 
 	TableGCName   Name = "tablegc"
 	OnlineDDLName Name = "online-ddl"
+	OnlineDMLName Name = "online-dml"
 	GhostName     Name = "gh-ost"
 	PTOSCName     Name = "pt-osc"
 
+	RestoreTableName Name = "restore-table"
+
 	VReplicationName      Name = "vreplication"
 	VStreamerName         Name = "vstreamer"
 	VPlayerName           Name = "vplayer"
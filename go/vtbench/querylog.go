@@ -0,0 +1,176 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtbench
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"vitess.io/vitess/go/sqltypes"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+// queryLogTimeFormat is the format of the Start and End fields of a vtgate query log
+const queryLogTimeFormat = "2006-01-02 15:04:05.000000"
+
+// QueryLogEntry is a query captured in a vtgate query log written in JSON format, as configured
+// with --log_queries_to_file and --querylog-format=json.
+type QueryLogEntry struct {
+	Method      string
+	RemoteAddr  string
+	Username    string
+	SessionUUID string
+	SQL         string
+	Start       time.Time
+	TotalTime   time.Duration
+	Error       string
+
+	// BindVars are the bind variables of the query. A bind variable whose value is not available
+	// in the log, such as a tuple, is missing from the map.
+	BindVars map[string]*querypb.BindVariable
+	// BindVarsMissing is set when the log does not hold the values of all the bind variables of
+	// the query, e.g. when it was written with --redact-debug-ui-queries.
+	BindVarsMissing bool
+}
+
+// sessionKey returns the key used to group queries into client sessions. Queries without a
+// session UUID are grouped by client address and user.
+func (e *QueryLogEntry) sessionKey() string {
+	if e.SessionUUID != "" {
+		return e.SessionUUID
+	}
+	return e.RemoteAddr + "/" + e.Username
+}
+
+// replayable reports whether the query is one that a client executed. Prepare calls are not.
+func (e *QueryLogEntry) replayable() bool {
+	switch e.Method {
+	case "Execute", "StreamExecute":
+		return e.SQL != ""
+	}
+	return false
+}
+
+type queryLogLine struct {
+	Method      string
+	RemoteAddr  string
+	Username    string
+	SessionUUID string
+	SQL         string
+	Start       string
+	TotalTime   float64
+	Error       string
+	BindVars    json.RawMessage
+}
+
+type queryLogBindVar struct {
+	Type  string
+	Value json.RawMessage
+}
+
+// ReadQueryLog reads a vtgate JSON query log, one query per line, and returns the queries that
+// clients executed, ordered by start time.
+func ReadQueryLog(r io.Reader) ([]*QueryLogEntry, error) {
+	var entries []*QueryLogEntry
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		entry, err := parseQueryLogLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("query log line %d: %w", lineNumber, err)
+		}
+		if entry.replayable() {
+			entries = append(entries, entry)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	// Queries are logged as they complete. Replay them in the order in which they started.
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Start.Before(entries[j].Start)
+	})
+	return entries, nil
+}
+
+func parseQueryLogLine(line []byte) (*QueryLogEntry, error) {
+	var l queryLogLine
+	if err := json.Unmarshal(line, &l); err != nil {
+		return nil, err
+	}
+	start, err := time.Parse(queryLogTimeFormat, l.Start)
+	if err != nil {
+		return nil, err
+	}
+	entry := &QueryLogEntry{
+		Method:      l.Method,
+		RemoteAddr:  l.RemoteAddr,
+		Username:    l.Username,
+		SessionUUID: l.SessionUUID,
+		SQL:         l.SQL,
+		Start:       start,
+		TotalTime:   time.Duration(l.TotalTime * float64(time.Second)),
+		Error:       l.Error,
+		BindVars:    map[string]*querypb.BindVariable{},
+	}
+	if len(l.BindVars) == 0 || l.BindVars[0] != '{' {
+		// The bind variables are a "[REDACTED]" string
+		entry.BindVarsMissing = len(l.BindVars) > 0
+		return entry, nil
+	}
+	var bindVars map[string]queryLogBindVar
+	if err := json.Unmarshal(l.BindVars, &bindVars); err != nil {
+		return nil, err
+	}
+	for name, bv := range bindVars {
+		bindVar := decodeBindVar(bv)
+		if bindVar == nil {
+			entry.BindVarsMissing = true
+			continue
+		}
+		entry.BindVars[name] = bindVar
+	}
+	return entry, nil
+}
+
+// decodeBindVar decodes a bind variable as written in a query log. It returns nil when the log
+// does not hold the value of the bind variable: tuples are logged as their number of items.
+func decodeBindVar(bv queryLogBindVar) *querypb.BindVariable {
+	typ, ok := querypb.Type_value[bv.Type]
+	if !ok || querypb.Type(typ) == sqltypes.Tuple {
+		return nil
+	}
+	value := string(bv.Value)
+	if len(bv.Value) > 0 && bv.Value[0] == '"' {
+		var err error
+		if value, err = strconv.Unquote(value); err != nil {
+			return nil
+		}
+	}
+	return &querypb.BindVariable{Type: querypb.Type(typ), Value: []byte(value)}
+}
@@ -0,0 +1,323 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtbench
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/sqlparser"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+var errnoRegexp = regexp.MustCompile(`\(errno (\d+)\)`)
+
+// ReplayQueryStats are the replay statistics of a normalized query.
+type ReplayQueryStats struct {
+	Query string
+	// Count is the number of times the query was replayed
+	Count int
+	// Skipped is the number of times the query could not be replayed, because the query log
+	// does not hold the values of its bind variables
+	Skipped int
+	// CapturedTime is the total execution time of the query in the query log
+	CapturedTime time.Duration
+	// ReplayTime is the total execution time of the query in the replay
+	ReplayTime time.Duration
+
+	CapturedErrors int
+	ReplayErrors   int
+	// NewErrors counts replays that failed where the captured query succeeded
+	NewErrors int
+	// FixedErrors counts replays that succeeded where the captured query failed
+	FixedErrors int
+	// ChangedErrors counts replays that failed with a different error than the captured query
+	ChangedErrors int
+	// SampleError is a replay error that differs from the captured outcome
+	SampleError string
+}
+
+// Replay replays the queries of a vtgate query log. Each client session of the log is replayed
+// on its own connection, in order. Queries are started at the same relative time as in the log,
+// scaled by the speed factor, unless the session is running behind.
+type Replay struct {
+	ConnParams ConnParams
+	// Speed is the replay speed relative to the captured traffic. Zero replays as fast as possible.
+	Speed float64
+	// BindVars override the bind variables of the captured queries. They provide values that are
+	// missing from the log, or rewrite captured values.
+	BindVars map[string]*querypb.BindVariable
+
+	Timings   *stats.Timings
+	TotalTime time.Duration
+
+	parser  *sqlparser.Parser
+	entries []*QueryLogEntry
+	connect func(ctx context.Context, cp ConnParams) (clientConn, error)
+
+	mu         sync.Mutex
+	queryStats map[string]*ReplayQueryStats
+}
+
+// NewReplay creates a new replay of the given query log entries.
+func NewReplay(cp ConnParams, entries []*QueryLogEntry, speed float64, bindVars map[string]*querypb.BindVariable, parser *sqlparser.Parser) *Replay {
+	return &Replay{
+		ConnParams: cp,
+		Speed:      speed,
+		BindVars:   bindVars,
+		Timings:    stats.NewTimings("", "", ""),
+		parser:     parser,
+		entries:    entries,
+		connect:    newClientConn,
+		queryStats: map[string]*ReplayQueryStats{},
+	}
+}
+
+// ParseBindVarOverride parses a name=value bind variable override. Integer values are bound as
+// integers, other values as strings.
+func ParseBindVarOverride(s string) (string, *querypb.BindVariable, error) {
+	name, value, ok := strings.Cut(s, "=")
+	if !ok || name == "" {
+		return "", nil, fmt.Errorf("invalid bind variable override %q, expected name=value", s)
+	}
+	if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return name, sqltypes.Int64BindVariable(i), nil
+	}
+	return name, sqltypes.StringBindVariable(value), nil
+}
+
+// Run replays the queries. It returns once all sessions are replayed, or when the context is done.
+func (r *Replay) Run(ctx context.Context) error {
+	if len(r.entries) == 0 {
+		return fmt.Errorf("no queries to replay")
+	}
+	var sessionKeys []string
+	sessions := map[string][]*QueryLogEntry{}
+	for _, entry := range r.entries {
+		key := entry.sessionKey()
+		if _, ok := sessions[key]; !ok {
+			sessionKeys = append(sessionKeys, key)
+		}
+		sessions[key] = append(sessions[key], entry)
+	}
+	fmt.Printf("Replaying %d queries in %d sessions\n", len(r.entries), len(sessions))
+
+	captureStart := r.entries[0].Start
+	start := time.Now()
+	errs := make(chan error, len(sessions))
+	var wg sync.WaitGroup
+	for i, key := range sessionKeys {
+		cp := r.ConnParams
+		cp.Hosts = []string{cp.Hosts[i%len(cp.Hosts)]}
+		wg.Add(1)
+		go func(entries []*QueryLogEntry) {
+			defer wg.Done()
+			if err := r.replaySession(ctx, cp, entries, start, captureStart); err != nil {
+				errs <- err
+			}
+		}(sessions[key])
+	}
+	wg.Wait()
+	r.TotalTime = time.Since(start)
+	close(errs)
+	return <-errs
+}
+
+func (r *Replay) replaySession(ctx context.Context, cp ConnParams, entries []*QueryLogEntry, start time.Time, captureStart time.Time) error {
+	conn, err := r.connect(ctx, cp)
+	if err != nil {
+		return fmt.Errorf("error connecting to %s using %v protocol: %v", cp.Hosts[0], cp.Protocol.String(), err)
+	}
+	for _, entry := range entries {
+		if r.Speed > 0 {
+			offset := time.Duration(float64(entry.Start.Sub(captureStart)) / r.Speed)
+			if wait := time.Until(start.Add(offset)); wait > 0 {
+				select {
+				case <-ctx.Done():
+					return nil
+				case <-time.After(wait):
+				}
+			}
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+		r.replayQuery(ctx, conn, entry)
+	}
+	return nil
+}
+
+// replayQuery runs a single query and records its outcome.
+func (r *Replay) replayQuery(ctx context.Context, conn clientConn, entry *QueryLogEntry) {
+	normalized := r.normalizeQuery(entry.SQL)
+	query, err := r.resolveBindVars(entry)
+	if err != nil {
+		log.V(5).Infof("skipping query %q: %v", entry.SQL, err)
+		r.record(normalized, entry, 0, nil, true)
+		return
+	}
+	queryStart := time.Now()
+	_, err = conn.execute(ctx, query, nil)
+	r.Timings.Record(normalized, queryStart)
+	r.record(normalized, entry, time.Since(queryStart), err, false)
+}
+
+// resolveBindVars returns the query of the entry, with its bind variables replaced by their
+// values. Bind variables are inlined so that queries can be replayed with any client protocol.
+func (r *Replay) resolveBindVars(entry *QueryLogEntry) (string, error) {
+	if len(entry.BindVars) == 0 && len(r.BindVars) == 0 && !entry.BindVarsMissing {
+		return entry.SQL, nil
+	}
+	stmt, _, err := r.parser.Parse2(entry.SQL)
+	if err != nil {
+		// Not all queries that vtgate accepts can be parsed, e.g. some SET statements are
+		// passed through. Those have no bind variables.
+		return entry.SQL, nil
+	}
+	bindVars := make(map[string]*querypb.BindVariable, len(entry.BindVars)+len(r.BindVars))
+	for name, bv := range entry.BindVars {
+		bindVars[name] = bv
+	}
+	for name, bv := range r.BindVars {
+		bindVars[name] = bv
+	}
+	return sqlparser.NewParsedQuery(stmt).GenerateQuery(bindVars, nil)
+}
+
+func (r *Replay) record(normalized string, entry *QueryLogEntry, elapsed time.Duration, err error, skipped bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	qs, ok := r.queryStats[normalized]
+	if !ok {
+		qs = &ReplayQueryStats{Query: normalized}
+		r.queryStats[normalized] = qs
+	}
+	if skipped {
+		qs.Skipped++
+		return
+	}
+	qs.Count++
+	qs.CapturedTime += entry.TotalTime
+	qs.ReplayTime += elapsed
+
+	if entry.Error != "" {
+		qs.CapturedErrors++
+	}
+	if err != nil {
+		qs.ReplayErrors++
+	}
+	switch {
+	case entry.Error == "" && err != nil:
+		qs.NewErrors++
+	case entry.Error != "" && err == nil:
+		qs.FixedErrors++
+	case entry.Error != "" && err != nil && !sameError(entry.Error, err.Error()):
+		qs.ChangedErrors++
+	default:
+		return
+	}
+	if qs.SampleError == "" && err != nil {
+		qs.SampleError = err.Error()
+	}
+}
+
+// sameError compares a captured error with a replay error. Error messages hold details that
+// vary between executions, so errors are compared by their MySQL error number when available.
+func sameError(captured string, replayed string) bool {
+	capturedErrno := errnoRegexp.FindStringSubmatch(captured)
+	replayedErrno := errnoRegexp.FindStringSubmatch(replayed)
+	if capturedErrno != nil && replayedErrno != nil {
+		return capturedErrno[1] == replayedErrno[1]
+	}
+	return captured == replayed
+}
+
+// normalizeQuery returns the query with its literals replaced by bind variables and its margin
+// comments removed, so that queries that only differ in their values are reported together.
+func (r *Replay) normalizeQuery(sql string) string {
+	stripped, _ := sqlparser.SplitMarginComments(sql)
+	normalized, err := r.parser.RedactSQLQuery(stripped)
+	if err != nil {
+		return stripped
+	}
+	return normalized
+}
+
+// QueryStats returns the replay statistics of each normalized query, most frequent first.
+func (r *Replay) QueryStats() []*ReplayQueryStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := make([]*ReplayQueryStats, 0, len(r.queryStats))
+	for _, qs := range r.queryStats {
+		result = append(result, qs)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count+result[i].Skipped != result[j].Count+result[j].Skipped {
+			return result[i].Count+result[i].Skipped > result[j].Count+result[j].Skipped
+		}
+		return result[i].Query < result[j].Query
+	})
+	return result
+}
+
+// PrintReport writes the replay statistics of each normalized query, along with its replay
+// latency histogram.
+func (r *Replay) PrintReport(w io.Writer) {
+	histograms := r.Timings.Histograms()
+	for _, qs := range r.QueryStats() {
+		fmt.Fprintf(w, "Query: %s\n", qs.Query)
+		fmt.Fprintf(w, "  Count: %d (skipped: %d)\n", qs.Count, qs.Skipped)
+		if qs.Count > 0 {
+			fmt.Fprintf(w, "  Average Captured Time: %v\n", qs.CapturedTime/time.Duration(qs.Count))
+			fmt.Fprintf(w, "  Average Replay Time: %v\n", qs.ReplayTime/time.Duration(qs.Count))
+		}
+		fmt.Fprintf(w, "  Errors: captured %d, replay %d, new %d, fixed %d, changed %d\n",
+			qs.CapturedErrors, qs.ReplayErrors, qs.NewErrors, qs.FixedErrors, qs.ChangedErrors)
+		if qs.SampleError != "" {
+			fmt.Fprintf(w, "  Sample Error: %s\n", qs.SampleError)
+		}
+		h, ok := histograms[qs.Query]
+		if !ok {
+			continue
+		}
+		fmt.Fprintf(w, "  Replay Timings:\n")
+		last := int64(0)
+		buckets := h.Buckets()
+		for i, bucket := range h.Cutoffs() {
+			if count := buckets[i]; count != 0 {
+				fmt.Fprintf(w, "    %v-%v: %v\n", time.Duration(last), time.Duration(bucket), count)
+			}
+			last = bucket
+		}
+		if count := buckets[len(buckets)-1]; count != 0 {
+			fmt.Fprintf(w, "    %v-: %v\n", time.Duration(last), count)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtbench
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/sqlparser"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+const testQueryLog = `{"ActiveKeyspace":"ks","BindVars":{"v1":{"type":"INT64","value":7}},"Method":"Execute","RemoteAddr":"10.0.0.1:5000","SQL":"select * from t where id = ?","SessionUUID":"s1","Start":"2024-05-01 10:00:00.000000","TotalTime":0.002,"Username":"app","Error":""}
+{"ActiveKeyspace":"ks","BindVars":{},"Method":"Prepare","RemoteAddr":"10.0.0.1:5000","SQL":"select * from t where id = ?","SessionUUID":"s1","Start":"2024-05-01 09:59:59.000000","TotalTime":0.001,"Username":"app","Error":""}
+{"ActiveKeyspace":"ks","BindVars":{},"Method":"Execute","RemoteAddr":"10.0.0.2:5000","SQL":"insert into t(id) values (1)","SessionUUID":"s2","Start":"2024-05-01 10:00:00.100000","TotalTime":0.003,"Username":"app","Error":"target: ks.-80.primary: vttablet: Duplicate entry '1' for key 't.PRIMARY' (errno 1062) (sqlstate 23000)"}
+{"ActiveKeyspace":"ks","BindVars":{"ids":{"type":"TUPLE","value":"3 items"}},"Method":"StreamExecute","RemoteAddr":"10.0.0.1:5000","SQL":"select * from t where id in ::ids","SessionUUID":"s1","Start":"2024-05-01 10:00:00.050000","TotalTime":0.001,"Username":"app","Error":""}
+{"ActiveKeyspace":"ks","BindVars":{"name":{"type":"VARCHAR","value":"o'neil"}},"Method":"Execute","RemoteAddr":"10.0.0.1:5000","SQL":"update t set name = :name where id = 5","SessionUUID":"s1","Start":"2024-05-01 10:00:00.200000","TotalTime":0.004,"Username":"app","Error":""}
+`
+
+func TestReadQueryLog(t *testing.T) {
+	entries, err := ReadQueryLog(strings.NewReader(testQueryLog))
+	require.NoError(t, err)
+	// The Prepare call is not replayed, and queries are ordered by start time
+	require.Len(t, entries, 4)
+	var queries []string
+	for _, entry := range entries {
+		queries = append(queries, entry.SQL)
+	}
+	assert.Equal(t, []string{
+		"select * from t where id = ?",
+		"select * from t where id in ::ids",
+		"insert into t(id) values (1)",
+		"update t set name = :name where id = 5",
+	}, queries)
+
+	assert.Equal(t, "s1", entries[0].sessionKey())
+	assert.Equal(t, 2*time.Millisecond, entries[0].TotalTime)
+	assert.Equal(t, sqltypes.Int64BindVariable(7), entries[0].BindVars["v1"])
+	// Tuples are logged as their number of items
+	assert.Empty(t, entries[1].BindVars)
+	assert.Equal(t, sqltypes.StringBindVariable("o'neil"), entries[3].BindVars["name"])
+
+	_, err = ReadQueryLog(strings.NewReader("not json\n"))
+	assert.ErrorContains(t, err, "query log line 1")
+}
+
+func TestParseBindVarOverride(t *testing.T) {
+	name, bv, err := ParseBindVarOverride("id=42")
+	require.NoError(t, err)
+	assert.Equal(t, "id", name)
+	assert.Equal(t, sqltypes.Int64BindVariable(42), bv)
+
+	name, bv, err = ParseBindVarOverride("name=a=b")
+	require.NoError(t, err)
+	assert.Equal(t, "name", name)
+	assert.Equal(t, sqltypes.StringBindVariable("a=b"), bv)
+
+	_, _, err = ParseBindVarOverride("=1")
+	assert.Error(t, err)
+}
+
+type fakeReplayConn struct {
+	r *fakeReplayConns
+}
+
+func (c *fakeReplayConn) connect(ctx context.Context, cp ConnParams) error {
+	return nil
+}
+
+func (c *fakeReplayConn) execute(ctx context.Context, query string, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	c.r.mu.Lock()
+	defer c.r.mu.Unlock()
+	c.r.queries = append(c.r.queries, query)
+	if strings.HasPrefix(query, "update") {
+		return nil, errors.New("table t is read only (errno 1036) (sqlstate HY000)")
+	}
+	return &sqltypes.Result{}, nil
+}
+
+type fakeReplayConns struct {
+	mu          sync.Mutex
+	connections int
+	queries     []string
+}
+
+func TestReplay(t *testing.T) {
+	entries, err := ReadQueryLog(strings.NewReader(testQueryLog))
+	require.NoError(t, err)
+
+	conns := &fakeReplayConns{}
+	cp := ConnParams{Hosts: []string{"localhost"}}
+	r := NewReplay(cp, entries, 0, nil, sqlparser.NewTestParser())
+	r.connect = func(ctx context.Context, cp ConnParams) (clientConn, error) {
+		conns.mu.Lock()
+		defer conns.mu.Unlock()
+		conns.connections++
+		return &fakeReplayConn{r: conns}, nil
+	}
+	require.NoError(t, r.Run(context.Background()))
+
+	// One connection per session
+	assert.Equal(t, 2, conns.connections)
+	// Bind variables are inlined, and the query with a tuple bind variable is skipped
+	assert.ElementsMatch(t, []string{
+		"select * from t where id = 7",
+		"insert into t(id) values (1)",
+		"update t set `name` = 'o\\'neil' where id = 5",
+	}, conns.queries)
+
+	byQuery := map[string]*ReplayQueryStats{}
+	for _, qs := range r.QueryStats() {
+		byQuery[qs.Query] = qs
+	}
+	require.Len(t, byQuery, 4)
+
+	qs := byQuery["select * from t where id in ::ids"]
+	assert.Equal(t, 0, qs.Count)
+	assert.Equal(t, 1, qs.Skipped)
+
+	qs = byQuery["insert into t(id) values (:redacted1 /* INT64 */)"]
+	require.NotNil(t, qs)
+	assert.Equal(t, 1, qs.Count)
+	assert.Equal(t, 1, qs.CapturedErrors)
+	assert.Equal(t, 1, qs.FixedErrors)
+
+	qs = byQuery["update t set `name` = :name where id = :id /* INT64 */"]
+	require.NotNil(t, qs)
+	assert.Equal(t, 1, qs.NewErrors)
+	assert.Contains(t, qs.SampleError, "errno 1036")
+
+	var report strings.Builder
+	r.PrintReport(&report)
+	assert.Contains(t, report.String(), "Errors: captured 0, replay 1, new 1, fixed 0, changed 0")
+	assert.Contains(t, report.String(), "Replay Timings:")
+}
+
+func TestReplayBindVarOverrides(t *testing.T) {
+	entries, err := ReadQueryLog(strings.NewReader(testQueryLog))
+	require.NoError(t, err)
+
+	r := NewReplay(ConnParams{}, entries, 0, map[string]*querypb.BindVariable{
+		"ids": sqltypes.TestBindVariable([]any{1, 2}),
+	}, sqlparser.NewTestParser())
+	query, err := r.resolveBindVars(entries[1])
+	require.NoError(t, err)
+	assert.Equal(t, "select * from t where id in (1, 2)", query)
+}
+
+func TestSameError(t *testing.T) {
+	assert.True(t, sameError("vttablet: Duplicate entry '1' for key 'PRIMARY' (errno 1062) (sqlstate 23000)", "Duplicate entry '2' for key 'PRIMARY' (errno 1062) (sqlstate 23000)"))
+	assert.False(t, sameError("(errno 1062)", "(errno 1064)"))
+	assert.True(t, sameError("connection refused", "connection refused"))
+}
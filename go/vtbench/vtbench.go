@@ -135,26 +135,7 @@ func (b *Bench) createConns(ctx context.Context) error {
 		cp := b.ConnParams
 		cp.Hosts = []string{host}
 
-		var conn clientConn
-		var err error
-
-		switch b.ConnParams.Protocol {
-		case MySQL:
-			log.V(5).Infof("connecting to %s using mysql protocol...", host)
-			conn = &mysqlClientConn{}
-			err = conn.connect(ctx, cp)
-		case GRPCVtgate:
-			log.V(5).Infof("connecting to %s using grpc vtgate protocol...", host)
-			conn = &grpcVtgateConn{}
-			err = conn.connect(ctx, cp)
-		case GRPCVttablet:
-			log.V(5).Infof("connecting to %s using grpc vttablet protocol...", host)
-			conn = &grpcVttabletConn{}
-			err = conn.connect(ctx, cp)
-		default:
-			return fmt.Errorf("unimplemented connection protocol %s", b.ConnParams.Protocol.String())
-		}
-
+		conn, err := newClientConn(ctx, cp)
 		if err != nil {
 			return fmt.Errorf("error connecting to %s using %v protocol: %v", host, cp.Protocol.String(), err)
 		}
@@ -178,6 +159,28 @@ func (b *Bench) createConns(ctx context.Context) error {
 	return nil
 }
 
+// newClientConn connects to the first host of the connection parameters
+func newClientConn(ctx context.Context, cp ConnParams) (clientConn, error) {
+	var conn clientConn
+	switch cp.Protocol {
+	case MySQL:
+		log.V(5).Infof("connecting to %s using mysql protocol...", cp.Hosts[0])
+		conn = &mysqlClientConn{}
+	case GRPCVtgate:
+		log.V(5).Infof("connecting to %s using grpc vtgate protocol...", cp.Hosts[0])
+		conn = &grpcVtgateConn{}
+	case GRPCVttablet:
+		log.V(5).Infof("connecting to %s using grpc vttablet protocol...", cp.Hosts[0])
+		conn = &grpcVttabletConn{}
+	default:
+		return nil, fmt.Errorf("unimplemented connection protocol %s", cp.Protocol.String())
+	}
+	if err := conn.connect(ctx, cp); err != nil {
+		return nil, err
+	}
+	return conn, nil
+}
+
 func (b *Bench) getQuery(i int) (string, map[string]*querypb.BindVariable) {
 	query := strings.Replace(b.Query, ":thread", fmt.Sprintf("%d", i), -1)
 	bindVars := make(map[string]*querypb.BindVariable)